@@ -83,13 +83,21 @@ func main() {
 		downloadClientRepo = database.NewDownloadClientRepo(log, db)
 		actionRepo         = database.NewActionRepo(log, db, downloadClientRepo)
 		filterRepo         = database.NewFilterRepo(log, db)
+		filterGroupRepo    = database.NewFilterGroupRepo(log, db)
+		filterRevisionRepo = database.NewFilterRevisionRepo(log, db)
+		filterTemplateRepo = database.NewFilterTemplateRepo(log, db)
+		filterStatsRepo    = database.NewFilterStatsRepo(log, db)
 		feedRepo           = database.NewFeedRepo(log, db)
 		feedCacheRepo      = database.NewFeedCacheRepo(log, db)
 		indexerRepo        = database.NewIndexerRepo(log, db)
 		ircRepo            = database.NewIrcRepo(log, db)
+		ircLogRepo         = database.NewIrcLogRepo(log, db)
+		ircTriggerRepo     = database.NewIrcTriggerRepo(log, db)
 		notificationRepo   = database.NewNotificationRepo(log, db)
 		releaseRepo        = database.NewReleaseRepo(log, db)
+		releaseHoldRepo    = database.NewReleaseHoldRepo(log, db)
 		userRepo           = database.NewUserRepo(log, db)
+		authRecoveryRepo   = database.NewAuthRecoveryRepo(log, db)
 	)
 
 	// setup services
@@ -100,14 +108,14 @@ func main() {
 		schedulingService     = scheduler.NewService(log, cfg.Config, notificationService, updateService)
 		indexerAPIService     = indexer.NewAPIService(log)
 		userService           = user.NewService(userRepo)
-		authService           = auth.NewService(log, userService)
+		authService           = auth.NewService(log, userService, authRecoveryRepo)
 		downloadClientService = download_client.NewService(log, downloadClientRepo)
 		actionService         = action.NewService(log, actionRepo, downloadClientService, bus)
 		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, indexerAPIService, schedulingService)
-		filterService         = filter.NewService(log, filterRepo, actionRepo, releaseRepo, indexerAPIService, indexerService)
-		releaseService        = release.NewService(log, releaseRepo, actionService, filterService)
-		ircService            = irc.NewService(log, serverEvents, ircRepo, releaseService, indexerService, notificationService)
-		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, schedulingService)
+		filterService         = filter.NewService(log, filterRepo, filterGroupRepo, filterRevisionRepo, filterStatsRepo, filterTemplateRepo, actionRepo, releaseRepo, indexerAPIService, indexerService, schedulingService)
+		releaseService        = release.NewService(log, cfg.Config, releaseRepo, releaseHoldRepo, actionService, filterService, schedulingService)
+		ircService            = irc.NewService(log, cfg.Config, serverEvents, ircRepo, ircLogRepo, ircTriggerRepo, releaseService, filterService, indexerService, notificationService, bus)
+		feedService           = feed.NewService(log, cfg.Config, feedRepo, feedCacheRepo, releaseService, schedulingService, notificationService)
 	)
 
 	// register event subscribers
@@ -142,7 +150,7 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGTERM)
 
-	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, schedulingService, updateService)
+	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, filterService, releaseService, schedulingService, updateService)
 	if err := srv.Start(); err != nil {
 		log.Fatal().Stack().Err(err).Msg("could not start server")
 		return
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/api"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// parseAPIKeyArgs returns the key to create from the label and --admin flag
+func parseAPIKeyArgs(args []string) (*domain.APIKey, error) {
+	var admin bool
+
+	fs := flag.NewFlagSet("apikey:create", flag.ContinueOnError)
+	fs.BoolVar(&admin, "admin", false, "allow the key to manage users, api keys and run db:vacuum")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		Name:   fs.Arg(0),
+		Scopes: []string{},
+	}
+
+	if admin {
+		key.Scopes = append(key.Scopes, domain.APIKeyScopeAdmin)
+	}
+
+	return key, nil
+}
+
+func createAPIKey(ctx context.Context, l logger.Logger, db *database.DB, key *domain.APIKey) (*domain.APIKey, error) {
+	apiService := api.NewService(l, database.NewAPIRepo(l, db))
+
+	if err := apiService.Store(ctx, key); err != nil {
+		return nil, errors.Wrap(err, "could not store api key")
+	}
+
+	return key, nil
+}
+
+func listAPIKeys(ctx context.Context, l logger.Logger, db *database.DB) error {
+	apiService := api.NewService(l, database.NewAPIRepo(l, db))
+
+	keys, err := apiService.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list api keys")
+	}
+
+	return printAPIKeys(keys)
+}
+
+func printAPIKeys(keys []domain.APIKey) error {
+	return render(keys, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tKEY\tSCOPES\tCREATED")
+
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.Name, key.Key, strings.Join(key.Scopes, ","), key.CreatedAt.Format(time.RFC3339))
+		}
+
+		return w.Flush()
+	})
+}
+
+func revokeAPIKey(ctx context.Context, l logger.Logger, db *database.DB, key string) error {
+	apiService := api.NewService(l, database.NewAPIRepo(l, db))
+
+	keys, err := apiService.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list api keys")
+	}
+
+	for _, k := range keys {
+		if k.Key != key {
+			continue
+		}
+
+		if err := apiService.Delete(ctx, key); err != nil {
+			return errors.Wrap(err, "could not delete api key")
+		}
+
+		return nil
+	}
+
+	return errors.New("api key not found")
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/auth"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/user"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// maxRecoveryDuration limits how long a recovery token stays valid
+const maxRecoveryDuration = 24 * time.Hour
+
+type recoveryToken struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// recoveryOptions are the flags of auth:recovery-enable
+type recoveryOptions struct {
+	Duration time.Duration
+
+	// URL is the address autobrr is reached at in the browser, like https://autobrr.example.com/ behind a reverse proxy
+	URL *url.URL
+}
+
+func parseRecoveryArgs(args []string) (*recoveryOptions, error) {
+	var duration, publicURL string
+
+	fs := flag.NewFlagSet("auth:recovery-enable", flag.ContinueOnError)
+	fs.StringVar(&duration, "duration", "15m", "how long the recovery token is valid, at most 24h")
+	fs.StringVar(&publicURL, "url", "", "address autobrr is reached at, eg. https://autobrr.example.com/. Defaults to host, port and baseUrl of the config")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	d, err := parseDuration(duration)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid --duration: %s", duration)
+	}
+
+	if d > maxRecoveryDuration {
+		return nil, errors.New("--duration must be at most %s", maxRecoveryDuration)
+	}
+
+	opts := &recoveryOptions{Duration: d}
+
+	if publicURL != "" {
+		u, err := url.Parse(publicURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid --url: %s", publicURL)
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.New("--url must be an http or https address, eg. https://autobrr.example.com/")
+		}
+
+		opts.URL = u
+	}
+
+	return opts, nil
+}
+
+func newAuthService(l logger.Logger, db *database.DB) auth.Service {
+	return auth.NewService(l, user.NewService(database.NewUserRepo(l, db)), database.NewAuthRecoveryRepo(l, db))
+}
+
+// enableRecovery creates a single use login token. It needs direct access to the database
+// so only someone with access to the host can recover an instance.
+func enableRecovery(ctx context.Context, l logger.Logger, db *database.DB, cfg *domain.Config, opts *recoveryOptions) (*recoveryToken, error) {
+	token, err := newAuthService(l, db).CreateRecoveryToken(ctx, opts.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recoveryToken{
+		Token:     token,
+		URL:       recoveryURL(cfg, opts.URL, token),
+		ExpiresAt: time.Now().Add(opts.Duration).Truncate(time.Second),
+	}, nil
+}
+
+// recoveryURL points to publicURL when set, otherwise to the local instance from the host, port and baseUrl of the config.
+// autobrr itself only serves http, https is set up with a reverse proxy and needs publicURL.
+func recoveryURL(cfg *domain.Config, publicURL *url.URL, token string) string {
+	var u url.URL
+
+	if publicURL != nil {
+		u = *publicURL
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/api/auth/recover"
+	} else {
+		host := cfg.Host
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "127.0.0.1"
+		}
+
+		u = url.URL{
+			Scheme: "http",
+			Host:   net.JoinHostPort(host, strconv.Itoa(cfg.Port)),
+			Path:   cfg.BaseURL + "api/auth/recover",
+		}
+	}
+
+	u.RawQuery = url.Values{"token": {token}}.Encode()
+
+	return u.String()
+}
+
+func printRecoveryToken(t *recoveryToken) error {
+	return render(t, func() error {
+		fmt.Printf("Recovery login enabled until %s, the link works once:\n\n", t.ExpiresAt.Format(time.RFC3339))
+		fmt.Printf("  %s\n\n", t.URL)
+		fmt.Println("Change the password under Settings > Account after logging in, or with change-password.")
+		fmt.Println("Run auth:recovery-disable to revoke the link before it expires.")
+
+		return nil
+	})
+}
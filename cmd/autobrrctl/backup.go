@@ -0,0 +1,526 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const backupFormatVersion = 1
+
+// backupManifest describes the contents of a backup archive
+type backupManifest struct {
+	FormatVersion int       `json:"format_version"`
+	Version       string    `json:"version"`
+	Driver        string    `json:"driver"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// backup holds the complete configuration of an instance.
+// Every entity is stored in its own json file inside a tar.gz archive.
+type backup struct {
+	Manifest        backupManifest
+	Users           []*domain.User
+	Indexers        []domain.Indexer
+	IrcNetworks     []domain.IrcNetwork
+	IrcTriggers     []domain.IrcTrigger
+	Clients         []domain.DownloadClient
+	FilterGroups    []filterGroupExport
+	Filters         []filterExport
+	FilterTemplates []domain.FilterTemplate
+	Feeds           []domain.Feed
+	Notifications   []domain.Notification
+	APIKeys         []domain.APIKey
+}
+
+type backupEntry struct {
+	name  string
+	value any
+}
+
+// entries maps archive file names to the backup fields
+func (b *backup) entries() []backupEntry {
+	return []backupEntry{
+		{name: "manifest.json", value: &b.Manifest},
+		{name: "users.json", value: &b.Users},
+		{name: "indexers.json", value: &b.Indexers},
+		{name: "irc_networks.json", value: &b.IrcNetworks},
+		{name: "irc_triggers.json", value: &b.IrcTriggers},
+		{name: "clients.json", value: &b.Clients},
+		{name: "filter_groups.json", value: &b.FilterGroups},
+		{name: "filters.json", value: &b.Filters},
+		{name: "filter_templates.json", value: &b.FilterTemplates},
+		{name: "feeds.json", value: &b.Feeds},
+		{name: "notifications.json", value: &b.Notifications},
+		{name: "api_keys.json", value: &b.APIKeys},
+	}
+}
+
+func createBackup(ctx context.Context, l logger.Logger, db *database.DB, path string) error {
+	var (
+		userRepo         = database.NewUserRepo(l, db)
+		indexerRepo      = database.NewIndexerRepo(l, db)
+		ircRepo          = database.NewIrcRepo(l, db)
+		triggerRepo      = database.NewIrcTriggerRepo(l, db)
+		clientRepo       = database.NewDownloadClientRepo(l, db)
+		templateRepo     = database.NewFilterTemplateRepo(l, db)
+		feedRepo         = database.NewFeedRepo(l, db)
+		notificationRepo = database.NewNotificationRepo(l, db)
+		apiRepo          = database.NewAPIRepo(l, db)
+	)
+
+	b := backup{
+		Manifest: backupManifest{
+			FormatVersion: backupFormatVersion,
+			Version:       version,
+			Driver:        db.Driver,
+			CreatedAt:     time.Now(),
+		},
+	}
+
+	var err error
+
+	if b.Users, err = userRepo.FindAll(ctx); err != nil {
+		return errors.Wrap(err, "could not list users")
+	}
+
+	if b.Indexers, err = indexerRepo.List(ctx); err != nil {
+		return errors.Wrap(err, "could not list indexers")
+	}
+
+	if b.IrcNetworks, err = ircRepo.ListNetworks(ctx); err != nil {
+		return errors.Wrap(err, "could not list irc networks")
+	}
+
+	for i, network := range b.IrcNetworks {
+		channels, err := ircRepo.ListChannels(network.ID)
+		if err != nil {
+			return errors.Wrap(err, "could not list channels for network: %s", network.Name)
+		}
+
+		b.IrcNetworks[i].Channels = channels
+
+		triggers, err := triggerRepo.List(ctx, network.ID)
+		if err != nil {
+			return errors.Wrap(err, "could not list triggers for network: %s", network.Name)
+		}
+
+		b.IrcTriggers = append(b.IrcTriggers, triggers...)
+	}
+
+	if b.Clients, err = clientRepo.List(ctx); err != nil {
+		return errors.Wrap(err, "could not list download clients")
+	}
+
+	if b.FilterGroups, err = collectFilterGroups(ctx, l, db); err != nil {
+		return err
+	}
+
+	if b.Filters, err = collectFilters(ctx, l, db); err != nil {
+		return err
+	}
+
+	if b.FilterTemplates, err = templateRepo.List(ctx); err != nil {
+		return errors.Wrap(err, "could not list filter templates")
+	}
+
+	if b.Feeds, err = feedRepo.Find(ctx); err != nil {
+		return errors.Wrap(err, "could not list feeds")
+	}
+
+	if b.Notifications, err = notificationRepo.List(ctx); err != nil {
+		return errors.Wrap(err, "could not list notifications")
+	}
+
+	if b.APIKeys, err = apiRepo.GetKeys(ctx); err != nil {
+		return errors.Wrap(err, "could not list api keys")
+	}
+
+	return writeBackup(path, &b)
+}
+
+func writeBackup(path string, b *backup) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create file: %s", path)
+	}
+
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	for _, entry := range b.entries() {
+		name := entry.name
+
+		data, err := json.MarshalIndent(entry.value, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "could not marshal %s", name)
+		}
+
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: b.Manifest.CreatedAt,
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return errors.Wrap(err, "could not write header for %s", name)
+		}
+
+		if _, err := tw.Write(data); err != nil {
+			return errors.Wrap(err, "could not write %s", name)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "could not close tar writer")
+	}
+
+	if err := gw.Close(); err != nil {
+		return errors.Wrap(err, "could not close gzip writer")
+	}
+
+	return nil
+}
+
+func readBackup(path string) (*backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open file: %s", path)
+	}
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read gzip archive")
+	}
+
+	defer gr.Close()
+
+	var b backup
+
+	entries := make(map[string]any)
+	for _, entry := range b.entries() {
+		entries[entry.name] = entry.value
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read tar archive")
+		}
+
+		v, ok := entries[header.Name]
+		if !ok {
+			continue
+		}
+
+		if err := json.NewDecoder(tr).Decode(v); err != nil {
+			return nil, errors.Wrap(err, "could not decode %s", header.Name)
+		}
+	}
+
+	if b.Manifest.FormatVersion == 0 {
+		return nil, errors.New("invalid backup: missing manifest")
+	}
+
+	if b.Manifest.FormatVersion > backupFormatVersion {
+		return nil, errors.New("backup format version %d is newer than supported version %d", b.Manifest.FormatVersion, backupFormatVersion)
+	}
+
+	return &b, nil
+}
+
+// restoreBackup restores a backup into an empty database.
+// Relations are rebuilt by name and identifier, so the target may use a different database driver.
+// If the restore fails the database is emptied again, so it can be retried.
+func restoreBackup(ctx context.Context, l logger.Logger, db *database.DB, path string) error {
+	b, err := readBackup(path)
+	if err != nil {
+		return err
+	}
+
+	if err := db.CheckEmpty(ctx); err != nil {
+		return errors.Wrap(err, "restore requires an empty database")
+	}
+
+	// the repos can't share a transaction, so clean up instead of rolling back
+	if err := restoreEntities(ctx, l, db, b); err != nil {
+		if truncateErr := db.Truncate(ctx); truncateErr != nil {
+			return errors.Wrap(err, "could not remove partially restored data: %v", truncateErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func restoreEntities(ctx context.Context, l logger.Logger, db *database.DB, b *backup) error {
+	var (
+		userRepo         = database.NewUserRepo(l, db)
+		indexerRepo      = database.NewIndexerRepo(l, db)
+		ircRepo          = database.NewIrcRepo(l, db)
+		triggerRepo      = database.NewIrcTriggerRepo(l, db)
+		clientRepo       = database.NewDownloadClientRepo(l, db)
+		templateRepo     = database.NewFilterTemplateRepo(l, db)
+		feedRepo         = database.NewFeedRepo(l, db)
+		notificationRepo = database.NewNotificationRepo(l, db)
+		apiRepo          = database.NewAPIRepo(l, db)
+	)
+
+	for _, user := range b.Users {
+		if err := userRepo.Store(ctx, domain.CreateUserRequest{Username: user.Username, Password: user.Password}); err != nil {
+			return errors.Wrap(err, "could not store user: %s", user.Username)
+		}
+	}
+
+	indexerIDs := make(map[string]int64, len(b.Indexers))
+	for _, indexer := range b.Indexers {
+		stored, err := indexerRepo.Store(ctx, indexer)
+		if err != nil {
+			return errors.Wrap(err, "could not store indexer: %s", indexer.Identifier)
+		}
+
+		indexerIDs[indexer.Identifier] = stored.ID
+	}
+
+	// triggers reference their network by id
+	networkIDs := make(map[int64]int64, len(b.IrcNetworks))
+	for _, network := range b.IrcNetworks {
+		network := network
+		oldID := network.ID
+
+		if err := ircRepo.StoreNetwork(ctx, &network); err != nil {
+			return errors.Wrap(err, "could not store irc network: %s", network.Name)
+		}
+
+		if len(network.Channels) > 0 {
+			if err := ircRepo.StoreNetworkChannels(ctx, network.ID, network.Channels); err != nil {
+				return errors.Wrap(err, "could not store channels for irc network: %s", network.Name)
+			}
+		}
+
+		networkIDs[oldID] = network.ID
+	}
+
+	for _, trigger := range b.IrcTriggers {
+		trigger := trigger
+
+		networkID, ok := networkIDs[trigger.NetworkID]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "irc trigger %q: network %d not found, skipping\n", trigger.Name, trigger.NetworkID)
+			continue
+		}
+
+		trigger.NetworkID = networkID
+
+		if err := triggerRepo.Store(ctx, &trigger); err != nil {
+			return errors.Wrap(err, "could not store irc trigger: %s", trigger.Name)
+		}
+	}
+
+	// clients can reference other clients, so keep track of the new ids
+	clientIDs := make(map[int]int, len(b.Clients))
+	for _, client := range b.Clients {
+		stored, err := clientRepo.Store(ctx, client)
+		if err != nil {
+			return errors.Wrap(err, "could not store download client: %s", client.Name)
+		}
+
+		clientIDs[client.ID] = stored.ID
+	}
+
+	for _, client := range b.Clients {
+		if client.Settings.ExternalDownloadClientId == 0 {
+			continue
+		}
+
+		client.ID = clientIDs[client.ID]
+		client.Settings.ExternalDownloadClientId = clientIDs[client.Settings.ExternalDownloadClientId]
+
+		if _, err := clientRepo.Update(ctx, client); err != nil {
+			return errors.Wrap(err, "could not update download client: %s", client.Name)
+		}
+	}
+
+	// filters reference their group by name, so groups go first
+	if err := storeFilterGroups(ctx, l, db, b.FilterGroups); err != nil {
+		return err
+	}
+
+	if err := storeFilters(ctx, l, db, b.Filters); err != nil {
+		return err
+	}
+
+	for _, template := range b.FilterTemplates {
+		template := template
+
+		remapTemplate(&template, indexerIDs, clientIDs)
+
+		if err := templateRepo.Store(ctx, &template); err != nil {
+			return errors.Wrap(err, "could not store filter template: %s", template.Name)
+		}
+	}
+
+	for _, feed := range b.Feeds {
+		feed := feed
+
+		indexerID, ok := indexerIDs[feed.Indexer]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "feed %q: indexer %q not found, skipping\n", feed.Name, feed.Indexer)
+			continue
+		}
+
+		feed.IndexerID = int(indexerID)
+
+		if err := feedRepo.Store(ctx, &feed); err != nil {
+			return errors.Wrap(err, "could not store feed: %s", feed.Name)
+		}
+
+		// store does not handle every field
+		if err := feedRepo.Update(ctx, &feed); err != nil {
+			return errors.Wrap(err, "could not update feed: %s", feed.Name)
+		}
+	}
+
+	for _, notification := range b.Notifications {
+		if _, err := notificationRepo.Store(ctx, notification); err != nil {
+			return errors.Wrap(err, "could not store notification: %s", notification.Name)
+		}
+	}
+
+	for _, key := range b.APIKeys {
+		key := key
+
+		if err := apiRepo.Store(ctx, &key); err != nil {
+			return errors.Wrap(err, "could not store api key: %s", key.Name)
+		}
+	}
+
+	return nil
+}
+
+// remapTemplate points the indexers and actions of a template filter to the ids of the restored indexers and clients.
+// Indexers that were not restored are dropped.
+func remapTemplate(template *domain.FilterTemplate, indexerIDs map[string]int64, clientIDs map[int]int) {
+	indexers := make([]domain.Indexer, 0, len(template.Filter.Indexers))
+	for _, indexer := range template.Filter.Indexers {
+		id, ok := indexerIDs[indexer.Identifier]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "filter template %q: indexer %q not found, skipping\n", template.Name, indexer.Identifier)
+			continue
+		}
+
+		indexer.ID = id
+		indexers = append(indexers, indexer)
+	}
+	template.Filter.Indexers = indexers
+
+	for _, action := range template.Filter.Actions {
+		if action.ClientID != 0 {
+			action.ClientID = int32(clientIDs[int(action.ClientID)])
+		}
+		if action.ExternalDownloadClientID != 0 {
+			action.ExternalDownloadClientID = int32(clientIDs[int(action.ExternalDownloadClientID)])
+		}
+	}
+}
+
+// filterGroupExport references the indexers and clients of a group by identifier and name like filterExport
+type filterGroupExport struct {
+	Group    domain.FilterGroup   `json:"group"`
+	Indexers []string             `json:"indexers"`
+	Actions  []filterExportAction `json:"actions"`
+}
+
+func collectFilterGroups(ctx context.Context, l logger.Logger, db *database.DB) ([]filterGroupExport, error) {
+	var (
+		groupRepo  = database.NewFilterGroupRepo(l, db)
+		actionRepo = database.NewActionRepo(l, db, database.NewDownloadClientRepo(l, db))
+	)
+
+	groups, err := groupRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filter groups")
+	}
+
+	exports := make([]filterGroupExport, 0, len(groups))
+
+	for _, group := range groups {
+		indexers, err := groupRepo.FindIndexers(ctx, group.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find indexers for filter group: %s", group.Name)
+		}
+
+		actions, err := actionRepo.FindByFilterGroupID(ctx, group.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find actions for filter group: %s", group.Name)
+		}
+
+		group.ID = 0
+		group.FiltersCount = 0
+
+		exports = append(exports, filterGroupExport{
+			Group:    group,
+			Indexers: indexerIdentifiers(indexers),
+			Actions:  newExportActions(actions),
+		})
+	}
+
+	return exports, nil
+}
+
+func storeFilterGroups(ctx context.Context, l logger.Logger, db *database.DB, groups []filterGroupExport) error {
+	var (
+		groupRepo  = database.NewFilterGroupRepo(l, db)
+		clientRepo = database.NewDownloadClientRepo(l, db)
+		actionRepo = database.NewActionRepo(l, db, clientRepo)
+	)
+
+	indexerMap, clientMap, err := loadResolveMaps(ctx, database.NewIndexerRepo(l, db), clientRepo)
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range groups {
+		group := imp.Group
+		owner := fmt.Sprintf("filter group %q", group.Name)
+
+		if err := groupRepo.Store(ctx, &group); err != nil {
+			return errors.Wrap(err, "could not store filter group: %s", group.Name)
+		}
+
+		if err := groupRepo.StoreIndexerConnections(ctx, group.ID, resolveIndexers(owner, imp.Indexers, indexerMap)); err != nil {
+			return errors.Wrap(err, "could not store indexers for filter group: %s", group.Name)
+		}
+
+		if actions := resolveActions(owner, imp.Actions, clientMap); len(actions) > 0 {
+			if _, err := actionRepo.StoreFilterGroupActions(ctx, int64(group.ID), actions); err != nil {
+				return errors.Wrap(err, "could not store actions for filter group: %s", group.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type completionCommand struct {
+	name        string
+	description string
+}
+
+// usageCommandRegex matches the action lines of the usage text
+var usageCommandRegex = regexp.MustCompile(`^  ([a-z0-9:-]+)\t+(.*)$`)
+
+// completionCommands reads the actions from the usage text so completion never drifts from the help
+func completionCommands() []completionCommand {
+	var commands []completionCommand
+
+	for _, line := range strings.Split(usage, "\n") {
+		m := usageCommandRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		// the description is the last tab separated column
+		columns := strings.Split(m[2], "\t")
+
+		commands = append(commands, completionCommand{
+			name:        m[1],
+			description: columns[len(columns)-1],
+		})
+	}
+
+	return commands
+}
+
+func writeCompletion(w io.Writer, shell string) error {
+	commands := completionCommands()
+
+	switch shell {
+	case "bash":
+		names := make([]string, 0, len(commands))
+		for _, c := range commands {
+			names = append(names, c.name)
+		}
+
+		_, err := fmt.Fprintf(w, bashCompletion, strings.Join(names, " "))
+		return err
+
+	case "zsh":
+		var b strings.Builder
+		for _, c := range commands {
+			fmt.Fprintf(&b, "    '%s:%s'\n", zshEscape(c.name), zshEscape(c.description))
+		}
+
+		_, err := fmt.Fprintf(w, zshCompletion, b.String())
+		return err
+
+	case "fish":
+		var b strings.Builder
+		for _, c := range commands {
+			fmt.Fprintf(&b, "complete -c autobrrctl -n '__fish_use_subcommand' -a '%s' -d '%s'\n", fishEscape(c.name), fishEscape(c.description))
+		}
+
+		_, err := fmt.Fprintf(w, fishCompletion, b.String())
+		return err
+	}
+
+	return errors.New("unsupported shell %q, must be bash, zsh or fish", shell)
+}
+
+func zshEscape(s string) string {
+	s = strings.ReplaceAll(s, "'", `'\''`)
+	return strings.ReplaceAll(s, ":", `\:`)
+}
+
+func fishEscape(s string) string {
+	return strings.ReplaceAll(s, "'", `\'`)
+}
+
+const bashCompletion = `# bash completion for autobrrctl
+# source it or save it to /etc/bash_completion.d/autobrrctl
+
+_autobrrctl() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        --config|-config)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            return
+            ;;
+        --output|-output)
+            COMPREPLY=($(compgen -W "table json" -- "$cur"))
+            return
+            ;;
+        --remote|-remote|--api-key|-api-key)
+            return
+            ;;
+        completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            return
+            ;;
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "--config --remote --api-key --output" -- "$cur"))
+        return
+    fi
+
+    # complete the action if none is given yet, otherwise complete files
+    local i word
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        case "$word" in
+            -*) ;;
+            *)
+                case "${COMP_WORDS[i-1]}" in
+                    --config|-config|--output|-output|--remote|-remote|--api-key|-api-key) ;;
+                    *)
+                        COMPREPLY=($(compgen -f -- "$cur"))
+                        return
+                        ;;
+                esac
+                ;;
+        esac
+    done
+
+    COMPREPLY=($(compgen -W "%s" -- "$cur"))
+}
+
+complete -F _autobrrctl autobrrctl
+`
+
+const zshCompletion = `#compdef autobrrctl
+# zsh completion for autobrrctl
+# save it as _autobrrctl in a directory in your $fpath
+
+_autobrrctl() {
+  local -a commands
+  commands=(
+%s  )
+
+  _arguments \
+    '--config[path to configuration directory]:config:_files -/' \
+    '--remote[base url of a running instance]:url:' \
+    '--api-key[api key for remote mode]:key:' \
+    '--output[output format]:format:(table json)' \
+    '1:action:->action' \
+    '*::arg:_files'
+
+  case $state in
+    action)
+      _describe 'action' commands
+      ;;
+  esac
+}
+
+_autobrrctl "$@"
+`
+
+const fishCompletion = `# fish completion for autobrrctl
+# save it to ~/.config/fish/completions/autobrrctl.fish
+
+complete -c autobrrctl -l config -r -F -d 'path to configuration directory'
+complete -c autobrrctl -l remote -x -d 'base url of a running instance'
+complete -c autobrrctl -l api-key -x -d 'api key for remote mode'
+complete -c autobrrctl -l output -x -a 'table json' -d 'output format'
+%scomplete -c autobrrctl -n '__fish_seen_subcommand_from completion' -x -a 'bash zsh fish'
+`
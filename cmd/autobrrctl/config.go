@@ -0,0 +1,84 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/config"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/logger"
+)
+
+// validateConfig validates the config file and checks that the database is reachable.
+// It returns false if any errors were found.
+func validateConfig(configPath string) (bool, error) {
+	report, err := config.Validate(configPath)
+	if err != nil {
+		return false, err
+	}
+
+	if !report.HasErrors() {
+		checkDatabase(report)
+	}
+
+	if report.Issues == nil {
+		report.Issues = []config.ValidationIssue{}
+	}
+
+	err = render(report, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Config: %s\n\n", report.File)
+
+		if len(report.Issues) == 0 {
+			fmt.Fprintln(w, "No issues found")
+			return w.Flush()
+		}
+
+		fmt.Fprintln(w, "LEVEL\tKEY\tMESSAGE")
+		for _, issue := range report.Issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", issue.Level, issue.Key, issue.Message)
+		}
+
+		return w.Flush()
+	})
+
+	return !report.HasErrors(), err
+}
+
+// checkDatabase pings the configured database without running migrations
+func checkDatabase(report *config.ValidationReport) {
+	db, err := database.NewDB(report.Config, logger.Mock())
+	if err != nil {
+		report.Issues = append(report.Issues, config.ValidationIssue{Level: config.ValidationLevelError, Key: "databaseType", Message: err.Error()})
+		return
+	}
+
+	if db.Driver == "sqlite" {
+		if _, err := os.Stat(db.DSN); err != nil {
+			report.Issues = append(report.Issues, config.ValidationIssue{Level: config.ValidationLevelWarning, Key: "databaseType", Message: fmt.Sprintf("sqlite database %s does not exist and will be created", db.DSN)})
+			return
+		}
+	}
+
+	handler, err := sql.Open(db.Driver, db.DSN)
+	if err != nil {
+		report.Issues = append(report.Issues, config.ValidationIssue{Level: config.ValidationLevelError, Key: "databaseType", Message: fmt.Sprintf("could not open database: %v", err)})
+		return
+	}
+
+	defer handler.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := handler.PingContext(ctx); err != nil {
+		report.Issues = append(report.Issues, config.ValidationIssue{Level: config.ValidationLevelError, Key: "databaseType", Message: fmt.Sprintf("could not connect to %s database: %v", db.Driver, err)})
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/config"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type doctorReport struct {
+	// Database is nil in remote mode
+	Database        *doctorDatabase        `json:"database,omitempty"`
+	Instance        doctorInstance         `json:"instance"`
+	IrcNetworks     []doctorIrcNetwork     `json:"irc_networks"`
+	DownloadClients []doctorDownloadClient `json:"download_clients"`
+	Feeds           []doctorFeed           `json:"feeds"`
+	Problems        []string               `json:"problems"`
+}
+
+type doctorDatabase struct {
+	Driver        string `json:"driver"`
+	SchemaVersion int    `json:"schema_version"`
+	LatestVersion int    `json:"latest_version"`
+}
+
+type doctorInstance struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+	// LiveState is true when irc state was fetched from the running instance
+	LiveState bool `json:"live_state"`
+}
+
+type doctorIrcNetwork struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Server  string `json:"server"`
+	// Connected and Healthy are nil when the instance is not reachable
+	Connected *bool    `json:"connected,omitempty"`
+	Healthy   *bool    `json:"healthy,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+type doctorDownloadClient struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	Error   string `json:"error,omitempty"`
+}
+
+type doctorFeed struct {
+	Name    string     `json:"name"`
+	Type    string     `json:"type"`
+	Enabled bool       `json:"enabled"`
+	LastRun *time.Time `json:"last_run"`
+	Status  string     `json:"status"`
+}
+
+// doctor collects the state of an instance from its database and, if it is running, its api.
+// In remote mode there is no database and everything comes from the api.
+type doctor struct {
+	ctx context.Context
+	log logger.Logger
+	db  *database.DB
+
+	// api is set when the instance is reachable
+	api *apiClient
+
+	report doctorReport
+}
+
+// runDoctor collects a health report from the database and the running instance
+func runDoctor(ctx context.Context, configPath string) (*doctorReport, error) {
+	cfg := config.New(configPath, version)
+	l := logger.Mock()
+
+	db, err := database.NewDB(cfg.Config, l)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create database")
+	}
+
+	// do not use Open since it runs the migrations we want to report on
+	if err := db.Connect(); err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	d := newDoctor(ctx, l)
+	d.db = db
+
+	d.checkDatabase()
+	d.checkInstance(cfg.Config)
+	d.checkIrc()
+	d.checkDownloadClients()
+	d.checkFeeds()
+
+	return &d.report, nil
+}
+
+// runRemoteDoctor collects a health report using only the api
+func runRemoteDoctor(ctx context.Context, api *apiClient) (*doctorReport, error) {
+	d := newDoctor(ctx, logger.Mock())
+
+	d.report.Instance.URL = api.baseURL
+
+	if !d.checkHealth(api) {
+		return &d.report, nil
+	}
+
+	d.api = api
+	d.report.Instance.LiveState = true
+
+	d.checkIrc()
+	d.checkDownloadClients()
+	d.checkFeeds()
+
+	return &d.report, nil
+}
+
+func newDoctor(ctx context.Context, l logger.Logger) *doctor {
+	return &doctor{
+		ctx: ctx,
+		log: l,
+		report: doctorReport{
+			IrcNetworks:     []doctorIrcNetwork{},
+			DownloadClients: []doctorDownloadClient{},
+			Feeds:           []doctorFeed{},
+			Problems:        []string{},
+		},
+	}
+}
+
+func (d *doctor) problem(format string, args ...any) {
+	d.report.Problems = append(d.report.Problems, fmt.Sprintf(format, args...))
+}
+
+func (d *doctor) checkDatabase() {
+	current, latest, err := d.db.SchemaVersion(d.ctx)
+	if err != nil {
+		d.problem("%v", err)
+		return
+	}
+
+	d.report.Database = &doctorDatabase{
+		Driver:        d.db.Driver,
+		SchemaVersion: current,
+		LatestVersion: latest,
+	}
+
+	switch {
+	case current < latest:
+		d.problem("%d pending migrations, they run on the next start", latest-current)
+	case current > latest:
+		d.problem("schema version %d is newer than this build supports (%d)", current, latest)
+	}
+}
+
+func (d *doctor) checkInstance(cfg *domain.Config) {
+	host := cfg.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	api := newAPIClient(fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(cfg.Port)), cfg.BaseURL), "")
+	d.report.Instance.URL = api.baseURL
+
+	if !d.checkHealth(api) {
+		return
+	}
+
+	// use any api key to read the live state, the instance is ours
+	keys, err := database.NewAPIRepo(d.log, d.db).GetKeys(d.ctx)
+	if err != nil || len(keys) == 0 {
+		return
+	}
+
+	api.apiKey = keys[0].Key
+	d.api = api
+	d.report.Instance.LiveState = true
+}
+
+// checkHealth returns true if the instance is reachable and healthy.
+// An unreachable instance is only a problem in remote mode, locally it may just not be running.
+func (d *doctor) checkHealth(api *apiClient) bool {
+	for _, check := range []string{"liveness", "readiness"} {
+		if err := api.healthy(d.ctx, check); err != nil {
+			if check == "liveness" && d.db != nil {
+				return false
+			}
+
+			d.problem("instance %s check failed: %v", check, err)
+			return false
+		}
+	}
+
+	d.report.Instance.Healthy = true
+
+	return true
+}
+
+func (d *doctor) checkIrc() {
+	if d.api != nil {
+		var networks []domain.IrcNetworkWithHealth
+		err := d.api.do(d.ctx, http.MethodGet, "api/irc", nil, nil, &networks)
+		if err == nil {
+			for _, n := range networks {
+				n := n
+
+				d.report.IrcNetworks = append(d.report.IrcNetworks, doctorIrcNetwork{
+					Name:      n.Name,
+					Enabled:   n.Enabled,
+					Server:    net.JoinHostPort(n.Server, strconv.Itoa(n.Port)),
+					Connected: &n.Connected,
+					Healthy:   &n.Healthy,
+					Errors:    n.ConnectionErrors,
+				})
+
+				if n.Enabled && !n.Healthy {
+					d.problem("irc network %s is not healthy", n.Name)
+				}
+			}
+			return
+		}
+
+		if d.db == nil {
+			d.problem("could not fetch irc state: %v", err)
+			return
+		}
+
+		d.report.Instance.LiveState = false
+	}
+
+	networks, err := database.NewIrcRepo(d.log, d.db).ListNetworks(d.ctx)
+	if err != nil {
+		d.problem("could not list irc networks: %v", err)
+		return
+	}
+
+	for _, n := range networks {
+		d.report.IrcNetworks = append(d.report.IrcNetworks, doctorIrcNetwork{
+			Name:    n.Name,
+			Enabled: n.Enabled,
+			Server:  net.JoinHostPort(n.Server, strconv.Itoa(n.Port)),
+		})
+	}
+}
+
+func (d *doctor) checkDownloadClients() {
+	var (
+		clients []domain.DownloadClient
+		test    func(ctx context.Context, client domain.DownloadClient) error
+		err     error
+	)
+
+	if d.db != nil {
+		repo := database.NewDownloadClientRepo(d.log, d.db)
+		clients, err = repo.List(d.ctx)
+		test = download_client.NewService(d.log, repo).Test
+	} else {
+		err = d.api.do(d.ctx, http.MethodGet, "api/download_clients", nil, nil, &clients)
+		test = func(ctx context.Context, client domain.DownloadClient) error {
+			return d.api.do(ctx, http.MethodPost, "api/download_clients/test", nil, client, nil)
+		}
+	}
+
+	if err != nil {
+		d.problem("could not list download clients: %v", err)
+		return
+	}
+
+	for _, client := range clients {
+		c := doctorDownloadClient{
+			Name:    client.Name,
+			Type:    string(client.Type),
+			Enabled: client.Enabled,
+		}
+
+		if client.Enabled {
+			ctx, cancel := context.WithTimeout(d.ctx, 15*time.Second)
+			err := test(ctx, client)
+			cancel()
+
+			if err != nil {
+				c.Error = err.Error()
+				d.problem("download client %s is unreachable", client.Name)
+			}
+		}
+
+		d.report.DownloadClients = append(d.report.DownloadClients, c)
+	}
+}
+
+func (d *doctor) checkFeeds() {
+	var (
+		feeds []domain.Feed
+		err   error
+	)
+
+	if d.db != nil {
+		feeds, err = database.NewFeedRepo(d.log, d.db).Find(d.ctx)
+	} else {
+		err = d.api.do(d.ctx, http.MethodGet, "api/feeds", nil, nil, &feeds)
+	}
+
+	if err != nil {
+		d.problem("could not list feeds: %v", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		f := doctorFeed{
+			Name:    feed.Name,
+			Type:    feed.Type,
+			Enabled: feed.Enabled,
+			Status:  "ok",
+		}
+
+		if !feed.LastRun.IsZero() {
+			lastRun := feed.LastRun
+			f.LastRun = &lastRun
+		}
+
+		switch {
+		case !feed.Enabled:
+			f.Status = "disabled"
+		case feed.LastRun.IsZero():
+			f.Status = "never run"
+		case time.Since(feed.LastRun) > 2*time.Duration(feed.Interval)*time.Minute:
+			// feeds that keep failing stop updating last_run
+			f.Status = fmt.Sprintf("stale, expected a run every %d minutes", feed.Interval)
+			d.problem("feed %s is stale", feed.Name)
+		}
+
+		d.report.Feeds = append(d.report.Feeds, f)
+	}
+}
+
+func printDoctor(report *doctorReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "== Database ==")
+	if db := report.Database; db != nil {
+		fmt.Fprintf(w, "  driver: %s\n", db.Driver)
+		fmt.Fprintf(w, "  schema version: %d (latest %d)\n", db.SchemaVersion, db.LatestVersion)
+	} else {
+		fmt.Fprintln(w, "  schema version is not available")
+	}
+
+	fmt.Fprintln(w, "\n== Instance ==")
+	fmt.Fprintf(w, "  url: %s\n", report.Instance.URL)
+	fmt.Fprintf(w, "  healthy: %t\n", report.Instance.Healthy)
+	if !report.Instance.LiveState {
+		fmt.Fprintln(w, "  live irc state is unavailable")
+	}
+
+	fmt.Fprintln(w, "\n== IRC ==")
+	fmt.Fprintln(w, "  NETWORK\tENABLED\tSERVER\tCONNECTED\tHEALTHY\tERRORS")
+	for _, n := range report.IrcNetworks {
+		fmt.Fprintf(w, "  %s\t%t\t%s\t%s\t%s\t%s\n", n.Name, n.Enabled, n.Server, formatOptionalBool(n.Connected), formatOptionalBool(n.Healthy), strings.Join(n.Errors, "; "))
+	}
+
+	fmt.Fprintln(w, "\n== Download clients ==")
+	fmt.Fprintln(w, "  CLIENT\tTYPE\tSTATUS")
+	for _, c := range report.DownloadClients {
+		status := "ok"
+		switch {
+		case !c.Enabled:
+			status = "disabled"
+		case c.Error != "":
+			status = "unreachable: " + c.Error
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", c.Name, c.Type, status)
+	}
+
+	fmt.Fprintln(w, "\n== Feeds ==")
+	fmt.Fprintln(w, "  FEED\tTYPE\tLAST RUN\tSTATUS")
+	for _, f := range report.Feeds {
+		lastRun := "never"
+		if f.LastRun != nil {
+			lastRun = f.LastRun.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", f.Name, f.Type, lastRun, f.Status)
+	}
+
+	fmt.Fprintf(w, "\n%d problems found\n", len(report.Problems))
+	for _, problem := range report.Problems {
+		fmt.Fprintf(w, "  - %s\n", problem)
+	}
+
+	return w.Flush()
+}
+
+func formatOptionalBool(b *bool) string {
+	if b == nil {
+		return "-"
+	}
+
+	return strconv.FormatBool(*b)
+}
@@ -0,0 +1,418 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// filterExport is the portable representation of a filter.
+// Indexers and download clients are referenced by identifier and name
+// since database ids differ between instances.
+type filterExport struct {
+	Filter   domain.Filter        `json:"filter"`
+	Group    string               `json:"group,omitempty"`
+	Indexers []string             `json:"indexers"`
+	Actions  []filterExportAction `json:"actions"`
+}
+
+type filterExportAction struct {
+	Action domain.Action `json:"action"`
+	Client string        `json:"client,omitempty"`
+}
+
+func exportFilters(ctx context.Context, l logger.Logger, db *database.DB, path string) (int, error) {
+	exports, err := collectFilters(ctx, l, db)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writeFilterExports(path, exports); err != nil {
+		return 0, err
+	}
+
+	return len(exports), nil
+}
+
+func importFilters(ctx context.Context, l logger.Logger, db *database.DB, path string) (int, error) {
+	imports, err := readFilterExports(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := storeFilters(ctx, l, db, imports); err != nil {
+		return 0, err
+	}
+
+	return len(imports), nil
+}
+
+func writeFilterExports(path string, exports []filterExport) error {
+	data, err := json.MarshalIndent(exports, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal filters")
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write file: %s", path)
+	}
+
+	return nil
+}
+
+func readFilterExports(path string) ([]filterExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read file: %s", path)
+	}
+
+	var imports []filterExport
+	if err := json.Unmarshal(data, &imports); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal filters")
+	}
+
+	return imports, nil
+}
+
+// newFilterExport strips all database ids from the filter and references the group, indexers and clients by name and identifier
+func newFilterExport(filter domain.Filter, group string, indexers []domain.Indexer, actions []*domain.Action) filterExport {
+	export := filterExport{
+		Group:    group,
+		Indexers: indexerIdentifiers(indexers),
+		Actions:  newExportActions(actions),
+	}
+
+	external := make([]domain.FilterExternal, 0, len(filter.External))
+	for _, e := range filter.External {
+		e.ID = 0
+		e.FilterId = 0
+		external = append(external, e)
+	}
+
+	sources := make([]domain.FilterListSource, 0, len(filter.ListSources))
+	for _, source := range filter.ListSources {
+		sources = append(sources, domain.FilterListSource{
+			Field:           source.Field,
+			URL:             source.URL,
+			Format:          source.Format,
+			JSONKey:         source.JSONKey,
+			RefreshInterval: source.RefreshInterval,
+		})
+	}
+
+	filter.ID = 0
+	filter.GroupID = 0
+	filter.ActionsCount = 0
+	filter.Indexers = nil
+	filter.Actions = nil
+	filter.External = external
+	filter.ListSources = sources
+
+	export.Filter = filter
+
+	return export
+}
+
+// resolve maps the exported group, indexers and clients to the ones in groupMap, indexerMap and clientMap.
+// Missing groups and indexers are skipped and actions with a missing client are kept without a client.
+func (imp filterExport) resolve(groupMap map[string]int, indexerMap map[string]domain.Indexer, clientMap map[string]domain.DownloadClient) (domain.Filter, error) {
+	filter := imp.Filter
+	filter.ID = 0
+	filter.GroupID = 0
+
+	if filter.Name == "" {
+		return filter, errors.New("filter name can't be empty")
+	}
+
+	if imp.Group != "" {
+		groupID, ok := groupMap[imp.Group]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "filter %q: filter group %q not found, filter will have no group\n", filter.Name, imp.Group)
+		}
+		filter.GroupID = groupID
+	}
+
+	// empty lists are omitted from the json but the columns are not nullable
+	for _, list := range []*[]string{&filter.Resolutions, &filter.Codecs, &filter.Sources, &filter.Containers} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+
+	owner := fmt.Sprintf("filter %q", filter.Name)
+
+	filter.Indexers = resolveIndexers(owner, imp.Indexers, indexerMap)
+	filter.Actions = resolveActions(owner, imp.Actions, clientMap)
+
+	return filter, nil
+}
+
+func indexerIdentifiers(indexers []domain.Indexer) []string {
+	identifiers := make([]string, 0, len(indexers))
+	for _, indexer := range indexers {
+		identifiers = append(identifiers, indexer.Identifier)
+	}
+
+	return identifiers
+}
+
+// newExportActions strips the database ids from the actions and references their clients by name
+func newExportActions(actions []*domain.Action) []filterExportAction {
+	exported := make([]filterExportAction, 0, len(actions))
+
+	for _, action := range actions {
+		a := filterExportAction{Action: *action}
+
+		if action.Client != nil {
+			a.Client = action.Client.Name
+		}
+
+		a.Action.ID = 0
+		a.Action.FilterID = 0
+		a.Action.FilterGroupID = 0
+		a.Action.ClientID = 0
+		a.Action.Client = nil
+
+		exported = append(exported, a)
+	}
+
+	return exported
+}
+
+// resolveIndexers maps indexer identifiers to the indexers in indexerMap, owner is used in the warning for missing indexers
+func resolveIndexers(owner string, identifiers []string, indexerMap map[string]domain.Indexer) []domain.Indexer {
+	indexers := make([]domain.Indexer, 0, len(identifiers))
+
+	for _, identifier := range identifiers {
+		indexer, ok := indexerMap[identifier]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s: indexer %q not found, skipping\n", owner, identifier)
+			continue
+		}
+
+		indexers = append(indexers, indexer)
+	}
+
+	return indexers
+}
+
+// resolveActions maps the exported actions to the clients in clientMap, owner is used in the warning for missing clients
+func resolveActions(owner string, exported []filterExportAction, clientMap map[string]domain.DownloadClient) []*domain.Action {
+	actions := make([]*domain.Action, 0, len(exported))
+
+	for _, a := range exported {
+		action := a.Action
+		action.ID = 0
+
+		if a.Client != "" {
+			client, ok := clientMap[a.Client]
+			if !ok {
+				fmt.Fprintf(os.Stderr, "%s: download client %q for action %q not found, action will have no client\n", owner, a.Client, action.Name)
+			} else {
+				action.ClientID = int32(client.ID)
+			}
+		}
+
+		actions = append(actions, &action)
+	}
+
+	return actions
+}
+
+// collectFilters loads all filters with their indexers, actions and external filters
+func collectFilters(ctx context.Context, l logger.Logger, db *database.DB) ([]filterExport, error) {
+	var (
+		filterRepo = database.NewFilterRepo(l, db)
+		groupRepo  = database.NewFilterGroupRepo(l, db)
+		actionRepo = database.NewActionRepo(l, db, database.NewDownloadClientRepo(l, db))
+		indexRepo  = database.NewIndexerRepo(l, db)
+	)
+
+	filters, err := filterRepo.ListFilters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filters")
+	}
+
+	groups, err := groupRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filter groups")
+	}
+
+	groupNames := make(map[int]string, len(groups))
+	for _, group := range groups {
+		groupNames[group.ID] = group.Name
+	}
+
+	exports := make([]filterExport, 0, len(filters))
+
+	for _, f := range filters {
+		// ListFilters does not load every field, so fetch the full filter with externals
+		filter, err := filterRepo.FindByID(ctx, f.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %d", f.ID)
+		}
+
+		indexers, err := indexRepo.FindByFilterID(ctx, filter.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find indexers for filter: %s", filter.Name)
+		}
+
+		actions, err := actionRepo.FindByFilterID(ctx, filter.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find actions for filter: %s", filter.Name)
+		}
+
+		if filter.ListSources, err = filterRepo.FindListSources(ctx, filter.ID); err != nil {
+			return nil, errors.Wrap(err, "could not find list sources for filter: %s", filter.Name)
+		}
+
+		export := newFilterExport(*filter, groupNames[filter.GroupID], indexers, actions)
+
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// storeFilters creates the filters and maps indexers and download clients by identifier and name
+func storeFilters(ctx context.Context, l logger.Logger, db *database.DB, imports []filterExport) error {
+	var (
+		filterRepo = database.NewFilterRepo(l, db)
+		groupRepo  = database.NewFilterGroupRepo(l, db)
+		clientRepo = database.NewDownloadClientRepo(l, db)
+		actionRepo = database.NewActionRepo(l, db, clientRepo)
+		indexRepo  = database.NewIndexerRepo(l, db)
+	)
+
+	groups, err := groupRepo.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list filter groups")
+	}
+
+	groupMap := make(map[string]int, len(groups))
+	for _, group := range groups {
+		groupMap[group.Name] = group.ID
+	}
+
+	indexerMap, clientMap, err := loadResolveMaps(ctx, indexRepo, clientRepo)
+	if err != nil {
+		return err
+	}
+
+	// filters are stored with several repo calls that can't share a transaction,
+	// so on failure every filter created by this import is deleted again
+	created := make([]int, 0, len(imports))
+
+	for _, imp := range imports {
+		filter, err := imp.resolve(groupMap, indexerMap, clientMap)
+		if err == nil {
+			err = storeFilter(ctx, filterRepo, actionRepo, &filter)
+		}
+
+		if filter.ID != 0 {
+			created = append(created, filter.ID)
+		}
+
+		if err != nil {
+			for i := len(created) - 1; i >= 0; i-- {
+				if deleteErr := deleteFilter(ctx, filterRepo, actionRepo, created[i]); deleteErr != nil {
+					return errors.Wrap(err, "could not delete partially imported filter %d: %v", created[i], deleteErr)
+				}
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storeFilter creates the filter with its indexers, external filters, list sources and actions
+func storeFilter(ctx context.Context, filterRepo domain.FilterRepo, actionRepo domain.ActionRepo, filter *domain.Filter) error {
+	if err := filterRepo.Store(ctx, filter); err != nil {
+		return errors.Wrap(err, "could not store filter: %s", filter.Name)
+	}
+
+	if err := filterRepo.StoreIndexerConnections(ctx, filter.ID, filter.Indexers); err != nil {
+		return errors.Wrap(err, "could not store indexers for filter: %s", filter.Name)
+	}
+
+	if len(filter.External) > 0 {
+		if err := filterRepo.StoreFilterExternal(ctx, filter.ID, filter.External); err != nil {
+			return errors.Wrap(err, "could not store external filters for filter: %s", filter.Name)
+		}
+	}
+
+	if len(filter.ListSources) > 0 {
+		if err := filterRepo.StoreListSources(ctx, filter.ID, filter.ListSources); err != nil {
+			return errors.Wrap(err, "could not store list sources for filter: %s", filter.Name)
+		}
+	}
+
+	for _, action := range filter.Actions {
+		action.FilterID = filter.ID
+	}
+
+	if len(filter.Actions) > 0 {
+		if _, err := actionRepo.StoreFilterActions(ctx, int64(filter.ID), filter.Actions); err != nil {
+			return errors.Wrap(err, "could not store actions for filter: %s", filter.Name)
+		}
+	}
+
+	return nil
+}
+
+// deleteFilter removes a filter and everything storeFilter created for it
+func deleteFilter(ctx context.Context, filterRepo domain.FilterRepo, actionRepo domain.ActionRepo, filterID int) error {
+	if err := actionRepo.DeleteByFilterID(ctx, filterID); err != nil {
+		return err
+	}
+
+	if err := filterRepo.DeleteIndexerConnections(ctx, filterID); err != nil {
+		return err
+	}
+
+	if err := filterRepo.DeleteFilterExternal(ctx, filterID); err != nil {
+		return err
+	}
+
+	if err := filterRepo.DeleteListSources(ctx, filterID); err != nil {
+		return err
+	}
+
+	return filterRepo.Delete(ctx, filterID)
+}
+
+// loadResolveMaps returns the indexers by identifier and the download clients by name
+func loadResolveMaps(ctx context.Context, indexRepo domain.IndexerRepo, clientRepo domain.DownloadClientRepo) (map[string]domain.Indexer, map[string]domain.DownloadClient, error) {
+	indexers, err := indexRepo.List(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not list indexers")
+	}
+
+	indexerMap := make(map[string]domain.Indexer, len(indexers))
+	for _, indexer := range indexers {
+		indexerMap[indexer.Identifier] = indexer
+	}
+
+	clients, err := clientRepo.List(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	clientMap := make(map[string]domain.DownloadClient, len(clients))
+	for _, client := range clients {
+		clientMap[client.Name] = client
+	}
+
+	return indexerMap, clientMap, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type filterTestArgs struct {
+	title   string
+	indexer string
+	size    string
+}
+
+// parseFilterTestArgs accepts the flags before or after the release title
+func parseFilterTestArgs(args []string) (*filterTestArgs, error) {
+	var a filterTestArgs
+
+	fs := flag.NewFlagSet("filter:test", flag.ContinueOnError)
+	fs.StringVar(&a.indexer, "indexer", "", "only test filters enabled for this indexer identifier")
+	fs.StringVar(&a.size, "size", "", "release size, e.g. 4.2GB. Without it filters with size limits need an additional size check")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != 1 {
+		return nil, errors.New("expected exactly one release title, quote it if it contains spaces")
+	}
+
+	a.title = positional[0]
+
+	return &a, nil
+}
+
+// testFilters runs a release title through the enabled filters with the filter service dry run
+func testFilters(ctx context.Context, l logger.Logger, db *database.DB, args *filterTestArgs) error {
+	filterSvc := filter.NewService(l, database.NewFilterRepo(l, db), database.NewFilterGroupRepo(l, db), database.NewFilterRevisionRepo(l, db), database.NewFilterStatsRepo(l, db), database.NewFilterTemplateRepo(l, db), nil, database.NewReleaseRepo(l, db), nil, nil, nil)
+
+	release := domain.NewRelease(args.indexer)
+	release.ParseString(args.title)
+
+	if args.size != "" {
+		release.ParseSizeBytesString(args.size)
+	}
+
+	results, err := filterSvc.DryRun(ctx, release)
+	if err != nil {
+		return errors.Wrap(err, "could not run filters")
+	}
+
+	return printFilterTest(release, results)
+}
+
+func printFilterTest(release *domain.Release, results []domain.FilterDryRunResult) error {
+	v := struct {
+		Release *domain.Release             `json:"release"`
+		Results []domain.FilterDryRunResult `json:"results"`
+	}{Release: release, Results: results}
+
+	return render(v, func() error {
+		fmt.Printf("Release: %s\n", release.TorrentName)
+		fmt.Printf("  title: %s, year: %d, season: %d, episode: %d, group: %s\n", release.Title, release.Year, release.Season, release.Episode, release.Group)
+		fmt.Printf("  resolution: %s, source: %s, codec: %v, container: %s, hdr: %v\n", release.Resolution, release.Source, release.Codec, release.Container, release.HDR)
+
+		if release.Indexer == "" {
+			fmt.Println("  no --indexer given, testing all enabled filters regardless of indexer")
+		}
+
+		if len(results) == 0 {
+			fmt.Println("\nNo enabled filters found")
+			return nil
+		}
+
+		matched := 0
+
+		for _, result := range results {
+			fmt.Printf("\n%s (priority %d): ", result.Name, result.Priority)
+
+			if !result.Match {
+				fmt.Println("REJECTED")
+				for _, rejection := range result.Rejections {
+					fmt.Printf("  - %s\n", rejection)
+				}
+				continue
+			}
+
+			matched++
+			fmt.Println("MATCH")
+
+			for _, note := range result.Notes {
+				fmt.Printf("  ! %s\n", note)
+			}
+		}
+
+		fmt.Printf("\n%d of %d filters matched\n", matched, len(results))
+
+		return nil
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// indexerSummary is the indexer without its settings since those contain passkeys and api keys
+type indexerSummary struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Identifier     string `json:"identifier"`
+	Implementation string `json:"implementation"`
+	Enabled        bool   `json:"enabled"`
+}
+
+type indexerArgs struct {
+	patterns []string
+	all      bool
+}
+
+// parseIndexerArgs parses the --all flag and name globs. Enable and disable require one of them.
+func parseIndexerArgs(cmd string, args []string) (*indexerArgs, error) {
+	a := &indexerArgs{}
+
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.BoolVar(&a.all, "all", false, "select all indexers")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	a.patterns = fs.Args()
+
+	for _, pattern := range a.patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.New("invalid pattern: %s", pattern)
+		}
+	}
+
+	if cmd != "indexer:list" && !a.all && len(a.patterns) == 0 {
+		return nil, errors.New("no indexers given, use --all or one or more name globs")
+	}
+
+	if a.all && len(a.patterns) > 0 {
+		return nil, errors.New("--all can't be combined with name globs")
+	}
+
+	return a, nil
+}
+
+// selectIndexers returns the indexers whose name or identifier matches any of the patterns case-insensitively.
+// A pattern that matches nothing is an error so typos are not silently ignored.
+func selectIndexers(indexers []domain.Indexer, a *indexerArgs) ([]domain.Indexer, error) {
+	if a.all || len(a.patterns) == 0 {
+		return indexers, nil
+	}
+
+	matched := make(map[int64]bool)
+
+	for _, pattern := range a.patterns {
+		pattern = strings.ToLower(pattern)
+		found := false
+
+		for _, indexer := range indexers {
+			nameMatch, _ := path.Match(pattern, strings.ToLower(indexer.Name))
+			identifierMatch, _ := path.Match(pattern, strings.ToLower(indexer.Identifier))
+
+			if nameMatch || identifierMatch {
+				matched[indexer.ID] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, errors.New("no indexers match: %s", pattern)
+		}
+	}
+
+	selected := make([]domain.Indexer, 0, len(matched))
+	for _, indexer := range indexers {
+		if matched[indexer.ID] {
+			selected = append(selected, indexer)
+		}
+	}
+
+	return selected, nil
+}
+
+// toggleIndexers calls toggle for every selected indexer that is not already in the wanted state
+func toggleIndexers(indexers []domain.Indexer, a *indexerArgs, enabled bool, toggle func(indexer domain.Indexer) error) ([]domain.Indexer, error) {
+	selected, err := selectIndexers(indexers, a)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]domain.Indexer, 0, len(selected))
+
+	for _, indexer := range selected {
+		if indexer.Enabled == enabled {
+			continue
+		}
+
+		if err := toggle(indexer); err != nil {
+			return updated, errors.Wrap(err, "could not update indexer: %s", indexer.Name)
+		}
+
+		indexer.Enabled = enabled
+		updated = append(updated, indexer)
+	}
+
+	return updated, nil
+}
+
+func listIndexers(ctx context.Context, l logger.Logger, db *database.DB, a *indexerArgs) error {
+	indexers, err := database.NewIndexerRepo(l, db).List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list indexers")
+	}
+
+	selected, err := selectIndexers(indexers, a)
+	if err != nil {
+		return err
+	}
+
+	return printIndexers(selected)
+}
+
+// setIndexersEnabled updates the database directly. A running instance keeps feeds of disabled indexers
+// running until it is restarted, use remote mode to apply the change to a running instance.
+func setIndexersEnabled(ctx context.Context, l logger.Logger, db *database.DB, a *indexerArgs, enabled bool) ([]domain.Indexer, error) {
+	repo := database.NewIndexerRepo(l, db)
+
+	indexers, err := repo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list indexers")
+	}
+
+	return toggleIndexers(indexers, a, enabled, func(indexer domain.Indexer) error {
+		return repo.ToggleEnabled(ctx, int(indexer.ID), enabled)
+	})
+}
+
+func printIndexers(indexers []domain.Indexer) error {
+	summaries := make([]indexerSummary, 0, len(indexers))
+	for _, indexer := range indexers {
+		summaries = append(summaries, indexerSummary{
+			ID:             indexer.ID,
+			Name:           indexer.Name,
+			Identifier:     indexer.Identifier,
+			Implementation: indexer.Implementation,
+			Enabled:        indexer.Enabled,
+		})
+	}
+
+	return render(summaries, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tIDENTIFIER\tIMPLEMENTATION\tENABLED")
+
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", s.ID, s.Name, s.Identifier, s.Implementation, s.Enabled)
+		}
+
+		return w.Flush()
+	})
+}
+
+func printIndexersToggled(indexers []domain.Indexer, enabled bool) error {
+	names := make([]string, 0, len(indexers))
+	for _, indexer := range indexers {
+		names = append(names, indexer.Name)
+	}
+
+	action := "Disabled"
+	if enabled {
+		action = "Enabled"
+	}
+
+	if len(names) == 0 {
+		return printResult(map[string][]string{"updated": names}, "No indexers changed, all selected indexers are already %s", strings.ToLower(action))
+	}
+
+	return printResult(map[string][]string{"updated": names}, "%s %d indexers: %s", action, len(names), strings.Join(names, ", "))
+}
@@ -0,0 +1,416 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/config"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// logPollInterval is how often the log file is checked for new lines when following
+const logPollInterval = 500 * time.Millisecond
+
+type logsArgs struct {
+	follow    bool
+	component string
+	lines     int
+}
+
+// logEntry is a single log line from the log file or the live log stream
+type logEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Module  string `json:"module,omitempty"`
+	Message string `json:"message"`
+}
+
+func parseLogsArgs(args []string) (*logsArgs, error) {
+	a := &logsArgs{}
+
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	fs.BoolVar(&a.follow, "follow", false, "keep streaming new log lines")
+	fs.StringVar(&a.component, "component", "", "only show lines from this module, e.g. irc, filter or action")
+	fs.IntVar(&a.lines, "lines", 20, "number of existing lines to show")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if a.lines < 0 {
+		return nil, errors.New("--lines must not be negative")
+	}
+
+	a.component = strings.ToLower(a.component)
+
+	return a, nil
+}
+
+func (a *logsArgs) match(entry logEntry) bool {
+	return a.component == "" || entry.Module == a.component
+}
+
+func printLogEntry(entry logEntry) {
+	if outputFormat == outputJSON {
+		json.NewEncoder(os.Stdout).Encode(entry)
+		return
+	}
+
+	fmt.Printf("%s %s %s\n", entry.Time, entry.Level, entry.Message)
+}
+
+// parseLogLine parses a zerolog json line into an entry with the remaining fields appended to the message
+func parseLogLine(line string) (logEntry, bool) {
+	var evt map[string]any
+	if err := json.Unmarshal([]byte(line), &evt); err != nil {
+		return logEntry{}, false
+	}
+
+	entry := logEntry{}
+	entry.Time, _ = evt[zerolog.TimestampFieldName].(string)
+	entry.Module, _ = evt["module"].(string)
+	entry.Message, _ = evt[zerolog.MessageFieldName].(string)
+
+	if level, ok := evt[zerolog.LevelFieldName].(string); ok {
+		entry.Level = formatLogLevel(level)
+	}
+
+	fields := make([]string, 0, len(evt))
+	for field := range evt {
+		switch field {
+		case zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName:
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	b.WriteString(entry.Message)
+
+	for _, field := range fields {
+		value := evt[field]
+		if s, ok := value.(string); ok {
+			fmt.Fprintf(&b, " %s=%s", field, s)
+			continue
+		}
+
+		data, _ := json.Marshal(value)
+		fmt.Fprintf(&b, " %s=%s", field, data)
+	}
+
+	entry.Message = b.String()
+
+	return entry, true
+}
+
+// formatLogLevel uses the same short level names as the live log stream
+func formatLogLevel(level string) string {
+	switch level {
+	case zerolog.LevelTraceValue:
+		return "TRC"
+	case zerolog.LevelDebugValue:
+		return "DBG"
+	case zerolog.LevelInfoValue:
+		return "INF"
+	case zerolog.LevelWarnValue:
+		return "WRN"
+	case zerolog.LevelErrorValue:
+		return "ERR"
+	case zerolog.LevelFatalValue:
+		return "FTL"
+	case zerolog.LevelPanicValue:
+		return "PNC"
+	}
+
+	return strings.ToUpper(level)
+}
+
+// tailLogLines reads r and prints the last n matching lines
+func tailLogLines(r io.Reader, a *logsArgs) error {
+	if a.lines == 0 {
+		return nil
+	}
+
+	entries := make([]logEntry, 0, a.lines)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		entry, ok := parseLogLine(scanner.Text())
+		if !ok || !a.match(entry) {
+			continue
+		}
+
+		if len(entries) == a.lines {
+			entries = entries[1:]
+		}
+
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "could not read log")
+	}
+
+	for _, entry := range entries {
+		printLogEntry(entry)
+	}
+
+	return nil
+}
+
+// tailLogFile prints the end of the log file from the config and keeps following it if requested.
+// The file is reopened when it is rotated.
+func tailLogFile(ctx context.Context, configPath string, a *logsArgs) error {
+	cfg := config.New(configPath, version)
+
+	logPath := cfg.Config.LogPath
+	if logPath == "" {
+		return errors.New("logPath is not set in config")
+	}
+
+	if !filepath.IsAbs(logPath) {
+		logPath = filepath.Join(configPath, logPath)
+	}
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		return errors.Wrap(err, "could not open log file: %s", logPath)
+	}
+
+	defer func() {
+		f.Close()
+	}()
+
+	if a.lines > 0 {
+		if err := tailLogLines(f, a); err != nil {
+			return err
+		}
+	}
+
+	if !a.follow {
+		return nil
+	}
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrap(err, "could not read log file: %s", logPath)
+	}
+
+	reader := bufio.NewReader(f)
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+
+	var partial string
+
+	for {
+		line, err := reader.ReadString('\n')
+		offset += int64(len(line))
+
+		if err == nil {
+			if entry, ok := parseLogLine(partial + line); ok && a.match(entry) {
+				printLogEntry(entry)
+			}
+			partial = ""
+			continue
+		}
+
+		if err != io.EOF {
+			return errors.Wrap(err, "could not read log file: %s", logPath)
+		}
+
+		// keep incomplete lines until the rest is written
+		partial += line
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		rotated, err := logFileRotated(f, logPath, offset)
+		if err != nil {
+			return err
+		}
+
+		if rotated {
+			f.Close()
+
+			if f, err = os.Open(logPath); err != nil {
+				return errors.Wrap(err, "could not open log file: %s", logPath)
+			}
+
+			reader.Reset(f)
+			offset = 0
+			partial = ""
+		}
+	}
+}
+
+// logFileRotated returns true if the file at path was replaced or truncated
+func logFileRotated(f *os.File, path string, offset int64) (bool, error) {
+	current, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// the new file is not created until something is logged
+			return false, nil
+		}
+		return false, errors.Wrap(err, "could not stat log file: %s", path)
+	}
+
+	open, err := f.Stat()
+	if err != nil {
+		return false, errors.Wrap(err, "could not stat log file: %s", path)
+	}
+
+	return !os.SameFile(open, current) || current.Size() < offset, nil
+}
+
+// sseModuleRegex finds the module field in the formatted messages of the live log stream
+var sseModuleRegex = regexp.MustCompile(`(?:^|\s)module=(\S+)`)
+
+// tailRemoteLogs prints the end of the newest log file of the instance and follows the live log stream if requested
+func (c *apiClient) tailRemoteLogs(ctx context.Context, a *logsArgs) error {
+	// the live stream replays its recent history, which is only used if the log file can't be read
+	replay := false
+
+	if a.lines > 0 {
+		if err := c.tailRemoteLogFile(ctx, a); err != nil {
+			if !a.follow {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "could not read log file, showing recent history instead: %v\n", err)
+			replay = true
+		}
+	}
+
+	if !a.follow {
+		return nil
+	}
+
+	body, err := c.stream(ctx, "api/events", "stream=logs", replay)
+	if err != nil {
+		return errors.Wrap(err, "could not stream logs")
+	}
+
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		var entry logEntry
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &entry); err != nil {
+			continue
+		}
+
+		entry.Message = strings.TrimSpace(entry.Message)
+		if m := sseModuleRegex.FindStringSubmatch(entry.Message); m != nil {
+			entry.Module = m[1]
+		}
+
+		if a.match(entry) {
+			printLogEntry(entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "could not read log stream")
+	}
+
+	return nil
+}
+
+func (c *apiClient) tailRemoteLogFile(ctx context.Context, a *logsArgs) error {
+	var res struct {
+		Files []struct {
+			Name      string    `json:"filename"`
+			UpdatedAt time.Time `json:"updated_at"`
+		} `json:"files"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "api/logs/files", nil, nil, &res); err != nil {
+		return errors.Wrap(err, "could not list log files")
+	}
+
+	if len(res.Files) == 0 {
+		return errors.New("instance has no log file, set logPath or use --follow")
+	}
+
+	// backups have the rotation time in their name, the active file is the most recently updated
+	newest := res.Files[0]
+	for _, file := range res.Files[1:] {
+		if file.UpdatedAt.After(newest.UpdatedAt) {
+			newest = file
+		}
+	}
+
+	body, err := c.stream(ctx, "api/logs/files/"+newest.Name, "", false)
+	if err != nil {
+		return errors.Wrap(err, "could not download log file: %s", newest.Name)
+	}
+
+	defer body.Close()
+
+	return tailLogLines(body, a)
+}
+
+// stream sends a get request without a timeout and returns the response body.
+// Without replay event streams only send new events.
+func (c *apiClient) stream(ctx context.Context, path string, query string, replay bool) (io.ReadCloser, error) {
+	u := c.baseURL + path
+	if query != "" {
+		u += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("X-API-Token", c.apiKey)
+	if !replay {
+		// events with an id lower than this are not replayed
+		req.Header.Set("Last-Event-ID", strconv.Itoa(math.MaxInt32))
+	}
+
+	client := &http.Client{Transport: c.client.Transport}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not send request")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New("GET %s: %s", path, resp.Status)
+	}
+
+	return resp.Body, nil
+}
@@ -12,6 +12,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/config"
@@ -25,10 +27,43 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-const usage = `usage: autobrrctl --config path <action>
-
-  create-user		<username>	Create user
-  change-password	<username>	Change password for user
+const usage = `usage: autobrrctl [--output table|json] --config path <action>
+       autobrrctl [--output table|json] --remote url --api-key key <action>
+
+  In remote mode the api key can also be set with AUTOBRR_API_KEY. The user, filter, apikey,
+  indexer, logs, stats, release:purge, db:vacuum, db:check and doctor actions are supported in remote mode.
+  Creating, changing and deleting users, the apikey actions and db:vacuum need an api key created with --admin.
+
+  create-user		<username>	Create user, flags: --password-env VAR, --password-stdin, --if-not-exists
+  change-password	<username>	Change password for user, flags: --password-env VAR, --password-stdin
+  list-users				List users
+  delete-user		<username>	Delete user
+  reset-2fa		<username>	Reset two-factor authentication for user, exits with an error as 2FA is not supported yet
+  filter:export		<file>		Export all filters with indexers, actions and external filters to json
+  filter:import		<file>		Import filters from json created by filter:export
+  filter:test		<title>		Dry-run a release title against enabled filters and print rejection reasons, flags: --indexer x, --size 4GB
+  backup		<file>		Backup users, indexers, irc networks and triggers, filters, filter groups, filter templates, actions, clients, feeds, notifications and api keys to a tar.gz archive
+  restore		<file>		Restore a backup archive into an empty sqlite or postgres database
+  auth:recovery-enable	[flags]		Print a single use login link for a lost password, flags: --duration 15m, --url https://autobrr.example.com/
+  auth:recovery-disable			Revoke unused recovery login links
+  apikey:create		[--admin] [label]	Create api key with an optional label, --admin allows managing users, api keys and db:vacuum
+  apikey:list				List api keys
+  apikey:revoke		<key>		Revoke api key
+  indexer:list		[glob...]	List indexers, optionally matching name or identifier globs
+  indexer:enable	<glob...>	Enable indexers matching name or identifier globs, or --all
+  indexer:disable	<glob...>	Disable indexers matching name or identifier globs, or --all
+  logs			[flags]		Print the end of the log, flags: --follow, --component irc|filter|action, --lines 20
+  stats					Print snatches, rejections and push error rates by time window, filter and indexer
+  release:purge		[flags]		Purge release history, flags: --older-than 30d, --indexer x,y, --status PUSH_REJECTED, --all
+  doctor				Report schema version, instance health, irc state, download client reachability and stale feeds
+  config:validate			Validate config file and database connection
+  db:vacuum				Reclaim unused space and update statistics, then report table sizes
+  db:check				Run integrity checks and report table sizes and row counts
+  db:dump-seed		<db> <file>	Write data from a sqlite file or postgres url as a sql seed file, flags: --redact
+  db:seed		<file>		Load a seed file created by db:dump-seed into an empty database
+  db:migrate		<sqlite-db>	Migrate data from sqlite to the postgres database in the config, flags: --resume
+  db:migrate:pg2sqlite	<sqlite-db>	Migrate data from the postgres database in the config to a new sqlite database, flags: --resume
+  completion		<shell>		Print shell completion for bash, zsh or fish
   version				Can be run without --config
   help					Show this help message
 
@@ -50,13 +85,37 @@ func init() {
 }
 
 func main() {
-	var configPath string
+	var configPath, remoteURL, apiKey string
 	flag.StringVar(&configPath, "config", "", "path to configuration file")
+	flag.StringVar(&remoteURL, "remote", "", "base url of a running instance, e.g. https://host/autobrr/")
+	flag.StringVar(&apiKey, "api-key", os.Getenv("AUTOBRR_API_KEY"), "api key for remote mode")
+	flag.StringVar(&outputFormat, "output", outputTable, "output format: table or json")
 	flag.Parse()
 
+	if err := validateOutputFormat(outputFormat); err != nil {
+		log.Fatal(err)
+	}
+
+	if cmd := flag.Arg(0); remoteURL != "" && cmd != "version" && cmd != "completion" && cmd != "help" && cmd != "" {
+		if apiKey == "" {
+			log.Fatal("--api-key or AUTOBRR_API_KEY required with --remote")
+		}
+
+		if err := runRemote(context.Background(), newAPIClient(remoteURL, apiKey), cmd, flag.Args()[1:]); err != nil {
+			if errors.Is(err, errProblemsFound) {
+				os.Exit(1)
+			}
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	switch cmd := flag.Arg(0); cmd {
 	case "version":
-		fmt.Printf("Version: %v\nCommit: %v\nBuild: %v\n", version, commit, date)
+		if outputFormat == outputTable {
+			fmt.Printf("Version: %v\nCommit: %v\nBuild: %v\n", version, commit, date)
+		}
 
 		// get the latest release tag from brr-api
 		client := &http.Client{
@@ -87,7 +146,14 @@ func main() {
 			fmt.Printf("Failed to decode response from api: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Latest release: %v\n", rel.TagName)
+		if err := printResult(map[string]string{"version": version, "commit": commit, "date": date, "latest_release": rel.TagName}, "Latest release: %v", rel.TagName); err != nil {
+			log.Fatal(err)
+		}
+
+	case "completion":
+		if err := writeCompletion(os.Stdout, flag.Arg(1)); err != nil {
+			log.Fatal(err)
+		}
 
 	case "create-user":
 
@@ -109,13 +175,23 @@ func main() {
 
 		userRepo := database.NewUserRepo(l, db)
 
-		username := flag.Arg(1)
-		if username == "" {
-			flag.Usage()
-			os.Exit(1)
+		args, err := parseUserArgs(cmd, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		if args.ifNotExists {
+			if _, err := userRepo.FindByUsername(context.Background(), args.username); err == nil {
+				if err := printResult(map[string]string{"username": args.username, "status": "exists"}, "User %s already exists", args.username); err != nil {
+					log.Fatal(err)
+				}
+				break
+			} else if !errors.Is(err, domain.ErrRecordNotFound) {
+				log.Fatalf("failed to get user: %v", err)
+			}
 		}
 
-		password, err := readPassword()
+		password, err := args.password()
 		if err != nil {
 			log.Fatalf("failed to read password: %v", err)
 		}
@@ -125,7 +201,7 @@ func main() {
 		}
 
 		user := domain.CreateUserRequest{
-			Username: username,
+			Username: args.username,
 			Password: hashed,
 		}
 		if err := userRepo.Store(context.Background(), user); err != nil {
@@ -151,13 +227,12 @@ func main() {
 
 		userRepo := database.NewUserRepo(l, db)
 
-		username := flag.Arg(1)
-		if username == "" {
-			flag.Usage()
-			os.Exit(1)
+		args, err := parseUserArgs(cmd, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
 		}
 
-		user, err := userRepo.FindByUsername(context.Background(), username)
+		user, err := userRepo.FindByUsername(context.Background(), args.username)
 		if err != nil {
 			log.Fatalf("failed to get user: %v", err)
 		}
@@ -166,7 +241,7 @@ func main() {
 			log.Fatalf("failed to get user: %v", err)
 		}
 
-		password, err := readPassword()
+		password, err := args.password()
 		if err != nil {
 			log.Fatalf("failed to read password: %v", err)
 		}
@@ -179,6 +254,429 @@ func main() {
 		if err := userRepo.Update(context.Background(), *user); err != nil {
 			log.Fatalf("failed to create user: %v", err)
 		}
+	case "list-users":
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		userRepo := database.NewUserRepo(l, db)
+
+		users, err := userRepo.FindAll(context.Background())
+		if err != nil {
+			log.Fatalf("failed to list users: %v", err)
+		}
+
+		usernames := make([]string, 0, len(users))
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+
+		if err := printUsers(usernames); err != nil {
+			log.Fatal(err)
+		}
+
+	case "delete-user":
+		username := flag.Arg(1)
+		if username == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		userRepo := database.NewUserRepo(l, db)
+
+		if err := userRepo.Delete(context.Background(), username); err != nil {
+			if errors.Is(err, domain.ErrRecordNotFound) {
+				log.Fatalf("failed to delete user: user %s not found", username)
+			}
+			log.Fatalf("failed to delete user: %v", err)
+		}
+
+		if err := printResult(map[string]string{"deleted": username}, "Deleted user %s", username); err != nil {
+			log.Fatal(err)
+		}
+
+	case "reset-2fa":
+		username := flag.Arg(1)
+		if username == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		userRepo := database.NewUserRepo(l, db)
+
+		if _, err := userRepo.FindByUsername(context.Background(), username); err != nil {
+			if errors.Is(err, domain.ErrRecordNotFound) {
+				log.Fatalf("failed to reset 2fa: user %s not found", username)
+			}
+			log.Fatalf("failed to get user: %v", err)
+		}
+
+		// there is no totp secret to clear until two-factor authentication is implemented
+		log.Fatalf("failed to reset 2fa: 2fa is not supported, user %s has no second factor. Use change-password or auth:recovery-enable to regain access", username)
+
+	case "filter:export":
+		path := flag.Arg(1)
+		if path == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		count, err := exportFilters(context.Background(), l, db, path)
+		if err != nil {
+			log.Fatalf("failed to export filters: %v", err)
+		}
+
+		if err := printResult(map[string]any{"count": count, "file": path}, "Exported %d filters to %s", count, path); err != nil {
+			log.Fatal(err)
+		}
+
+	case "filter:import":
+		path := flag.Arg(1)
+		if path == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		count, err := importFilters(context.Background(), l, db, path)
+		if err != nil {
+			log.Fatalf("failed to import filters: %v", err)
+		}
+
+		if err := printResult(map[string]any{"count": count, "file": path}, "Imported %d filters from %s", count, path); err != nil {
+			log.Fatal(err)
+		}
+
+	case "filter:test":
+		args, err := parseFilterTestArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse arguments: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := testFilters(context.Background(), l, db, args); err != nil {
+			log.Fatalf("failed to test filters: %v", err)
+		}
+
+	case "backup":
+		path := flag.Arg(1)
+		if path == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := createBackup(context.Background(), l, db, path); err != nil {
+			log.Fatalf("failed to create backup: %v", err)
+		}
+
+		if err := printResult(map[string]string{"file": path}, "Backup written to %s", path); err != nil {
+			log.Fatal(err)
+		}
+
+	case "restore":
+		path := flag.Arg(1)
+		if path == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := restoreBackup(context.Background(), l, db, path); err != nil {
+			log.Fatalf("failed to restore backup: %v", err)
+		}
+
+		if err := printResult(map[string]string{"file": path}, "Backup restored from %s", path); err != nil {
+			log.Fatal(err)
+		}
+
+	case "indexer:list":
+		args, err := parseIndexerArgs(cmd, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := listIndexers(context.Background(), l, db, args); err != nil {
+			log.Fatalf("failed to list indexers: %v", err)
+		}
+
+	case "indexer:enable", "indexer:disable":
+		args, err := parseIndexerArgs(cmd, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		enabled := cmd == "indexer:enable"
+
+		updated, err := setIndexersEnabled(context.Background(), l, db, args, enabled)
+		if err != nil {
+			log.Fatalf("failed to update indexers: %v", err)
+		}
+
+		if err := printIndexersToggled(updated, enabled); err != nil {
+			log.Fatal(err)
+		}
+
+		if len(updated) > 0 && outputFormat == outputTable {
+			fmt.Fprintln(os.Stderr, "A running instance picks up feed changes after a restart, use --remote to apply them immediately")
+		}
+
+	case "logs":
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		args, err := parseLogsArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := tailLogFile(ctx, configPath, args); err != nil {
+			log.Fatalf("failed to read logs: %v", err)
+		}
+
+	case "stats":
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		report, err := collectStats(context.Background(), l, db)
+		if err != nil {
+			log.Fatalf("failed to collect stats: %v", err)
+		}
+
+		if err := printStats(report); err != nil {
+			log.Fatalf("failed to print stats: %v", err)
+		}
+
+	case "release:purge":
+		req, err := parseReleasePurgeArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		count, err := purgeReleases(context.Background(), l, db, req)
+		if err != nil {
+			log.Fatalf("failed to purge releases: %v", err)
+		}
+
+		if err := printResult(map[string]int64{"purged": count}, "Purged %d releases", count); err != nil {
+			log.Fatal(err)
+		}
+
+	case "doctor":
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		report, err := runDoctor(context.Background(), configPath)
+		if err != nil {
+			log.Fatalf("failed to run doctor: %v", err)
+		}
+
+		if err := render(report, func() error { return printDoctor(report) }); err != nil {
+			log.Fatal(err)
+		}
+
+		if len(report.Problems) > 0 {
+			os.Exit(1)
+		}
+
+	case "config:validate":
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		ok, err := validateConfig(configPath)
+		if err != nil {
+			log.Fatalf("failed to validate config: %v", err)
+		}
+
+		if !ok {
+			os.Exit(1)
+		}
+
+	case "db:vacuum":
+		_, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := vacuumDatabase(context.Background(), db); err != nil {
+			log.Fatalf("failed to vacuum database: %v", err)
+		}
+
+	case "db:check":
+		_, db := openDatabase(configPath)
+		defer db.Close()
+
+		ok, err := checkDatabaseIntegrity(context.Background(), db)
+		if err != nil {
+			log.Fatalf("failed to check database: %v", err)
+		}
+
+		if !ok {
+			os.Exit(1)
+		}
+
+	case "db:dump-seed":
+		args, err := parseDumpSeedArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		if err := dumpSeed(context.Background(), logger.Mock(), args); err != nil {
+			log.Fatalf("failed to dump seed: %v", err)
+		}
+
+		if err := printResult(map[string]string{"file": args.output}, "Seed written to %s", args.output); err != nil {
+			log.Fatal(err)
+		}
+
+	case "db:seed":
+		seedPath := flag.Arg(1)
+		if seedPath == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		_, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := loadSeed(context.Background(), db, seedPath); err != nil {
+			log.Fatalf("failed to load seed: %v", err)
+		}
+
+		if err := printResult(map[string]string{"file": seedPath}, "Seed loaded from %s", seedPath); err != nil {
+			log.Fatal(err)
+		}
+
+	case "db:migrate", "db:migrate:pg2sqlite":
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		args, err := parseMigrateArgs(cmd, flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		direction := migrateSQLiteToPostgres
+		if cmd == "db:migrate:pg2sqlite" {
+			direction = migratePostgresToSQLite
+		}
+
+		results, err := migrateDatabase(context.Background(), configPath, args, direction)
+		if err != nil {
+			log.Fatalf("failed to migrate database: %v", err)
+		}
+
+		if err := printMigrateResults(results); err != nil {
+			log.Fatal(err)
+		}
+
+	case "auth:recovery-enable":
+		opts, err := parseRecoveryArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		cfg := config.New(configPath, version)
+
+		token, err := enableRecovery(context.Background(), l, db, cfg.Config, opts)
+		if err != nil {
+			log.Fatalf("failed to enable recovery: %v", err)
+		}
+
+		if err := printRecoveryToken(token); err != nil {
+			log.Fatal(err)
+		}
+
+	case "auth:recovery-disable":
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		count, err := newAuthService(l, db).RevokeRecoveryTokens(context.Background())
+		if err != nil {
+			log.Fatalf("failed to disable recovery: %v", err)
+		}
+
+		if err := printResult(map[string]int64{"revoked": count}, "Revoked %d recovery links", count); err != nil {
+			log.Fatal(err)
+		}
+
+	case "apikey:create":
+		key, err := parseAPIKeyArgs(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("failed to parse flags: %v", err)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		key, err = createAPIKey(context.Background(), l, db, key)
+		if err != nil {
+			log.Fatalf("failed to create api key: %v", err)
+		}
+
+		if err := printResult(key, "%s", key.Key); err != nil {
+			log.Fatal(err)
+		}
+
+	case "apikey:list":
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := listAPIKeys(context.Background(), l, db); err != nil {
+			log.Fatalf("failed to list api keys: %v", err)
+		}
+
+	case "apikey:revoke":
+		key := flag.Arg(1)
+		if key == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		l, db := openDatabase(configPath)
+		defer db.Close()
+
+		if err := revokeAPIKey(context.Background(), l, db, key); err != nil {
+			log.Fatalf("failed to revoke api key: %v", err)
+		}
+
+		if err := printResult(map[string]string{"revoked": key}, "Revoked api key %s", key); err != nil {
+			log.Fatal(err)
+		}
+
 	default:
 		flag.Usage()
 		if cmd != "help" {
@@ -187,6 +685,31 @@ func main() {
 	}
 }
 
+// openDatabase reads the config and opens the database it points to
+func openDatabase(configPath string) (logger.Logger, *database.DB) {
+	if configPath == "" {
+		log.Fatal("--config required")
+	}
+
+	// read config
+	cfg := config.New(configPath, version)
+
+	// init new logger
+	l := logger.New(cfg.Config)
+
+	// open database connection
+	db, err := database.NewDB(cfg.Config, l)
+	if err != nil {
+		log.Fatalf("could not create database: %v", err)
+	}
+
+	if err := db.Open(); err != nil {
+		log.Fatal("could not open db connection")
+	}
+
+	return l, db
+}
+
 func readPassword() ([]byte, error) {
 	var password []byte
 	var err error
@@ -217,3 +740,12 @@ func readPassword() ([]byte, error) {
 
 	return password, nil
 }
+
+func printUsers(usernames []string) error {
+	return render(usernames, func() error {
+		for _, username := range usernames {
+			fmt.Println(username)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+func vacuumDatabase(ctx context.Context, db *database.DB) error {
+	before, err := db.TableStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Vacuum(ctx); err != nil {
+		return errors.Wrap(err, "could not vacuum database")
+	}
+
+	after, err := db.TableStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printVacuum(before, after)
+}
+
+func printVacuum(before, after []database.TableStats) error {
+	v := struct {
+		Before []database.TableStats `json:"before"`
+		After  []database.TableStats `json:"after"`
+	}{Before: before, After: after}
+
+	return render(v, func() error {
+		sizes := make(map[string]int64, len(before))
+		for _, s := range before {
+			sizes[s.Name] = s.Size
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tROWS\tSIZE BEFORE\tSIZE AFTER")
+		for _, s := range after {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", s.Name, s.Rows, formatSize(sizes[s.Name]), formatSize(s.Size))
+		}
+
+		return w.Flush()
+	})
+}
+
+// checkDatabaseIntegrity prints the table stats and integrity problems.
+// It returns false if any problems were found.
+func checkDatabaseIntegrity(ctx context.Context, db *database.DB) (bool, error) {
+	stats, err := db.TableStats(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	problems, err := db.Check(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "could not check database")
+	}
+
+	return printCheck(stats, problems)
+}
+
+// printCheck prints the table stats and problems and returns false if there are any problems
+func printCheck(stats []database.TableStats, problems []string) (bool, error) {
+	if problems == nil {
+		problems = []string{}
+	}
+
+	v := struct {
+		Tables   []database.TableStats `json:"tables"`
+		Problems []string              `json:"problems"`
+	}{Tables: stats, Problems: problems}
+
+	err := render(v, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tROWS\tSIZE")
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", s.Name, s.Rows, formatSize(s.Size))
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintln(w, "\nIntegrity check: ok")
+			return w.Flush()
+		}
+
+		fmt.Fprintf(w, "\nIntegrity check: %d problems found\n", len(problems))
+		for _, problem := range problems {
+			fmt.Fprintf(w, "  %s\n", problem)
+		}
+
+		return w.Flush()
+	})
+
+	return len(problems) == 0, err
+}
+
+func formatSize(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+
+	return humanize.Bytes(uint64(size))
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autobrr/autobrr/internal/config"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/term"
+)
+
+type migrateDirection int
+
+const (
+	migrateSQLiteToPostgres migrateDirection = iota
+	migratePostgresToSQLite
+)
+
+type migrateArgs struct {
+	sqlitePath string
+	resume     bool
+}
+
+func parseMigrateArgs(cmd string, args []string) (*migrateArgs, error) {
+	a := &migrateArgs{}
+
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.BoolVar(&a.resume, "resume", false, "continue an interrupted migration")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	a.sqlitePath = fs.Arg(0)
+	if a.sqlitePath == "" {
+		return nil, errors.New("path to sqlite database required")
+	}
+
+	return a, nil
+}
+
+// migrateDatabase copies all data between the sqlite database at sqlitePath and the postgres database from the config
+func migrateDatabase(ctx context.Context, configPath string, args *migrateArgs, direction migrateDirection) ([]database.MigrateTableResult, error) {
+	cfg := config.New(configPath, version)
+
+	if cfg.Config.DatabaseType != "postgres" {
+		return nil, errors.New("config must use postgres as database type, got: %s", cfg.Config.DatabaseType)
+	}
+
+	l := logger.New(cfg.Config)
+
+	pgDB, err := database.NewDB(cfg.Config, l)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create postgres database")
+	}
+
+	sqliteDB, err := database.NewDBFromDSN(l, "sqlite", args.sqlitePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create sqlite database")
+	}
+
+	// open runs the schema migrations so both sides end up on the same schema version
+	if err := pgDB.Open(); err != nil {
+		return nil, errors.Wrap(err, "could not open postgres database")
+	}
+	defer pgDB.Close()
+
+	if err := sqliteDB.Open(); err != nil {
+		return nil, errors.Wrap(err, "could not open sqlite database")
+	}
+	defer sqliteDB.Close()
+
+	src, dst := sqliteDB, pgDB
+	if direction == migratePostgresToSQLite {
+		src, dst = pgDB, sqliteDB
+	}
+
+	migrator := database.NewMigrator(l, src, dst)
+	migrator.Resume = args.resume
+	migrator.Progress = newMigrateProgress()
+
+	results, err := migrator.Migrate(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migration interrupted, fix the error and run again with --resume")
+	}
+
+	return results, nil
+}
+
+// newMigrateProgress prints the progress of the current table to stderr, overwriting the line on a terminal
+func newMigrateProgress() func(p database.MigrateProgress) {
+	interactive := term.IsTerminal(int(os.Stderr.Fd()))
+	current := ""
+
+	return func(p database.MigrateProgress) {
+		if !interactive {
+			if p.Copied == p.Total {
+				fmt.Fprintf(os.Stderr, "%s: %d/%d rows\n", p.Table, p.Copied, p.Total)
+			}
+			return
+		}
+
+		if current != "" && current != p.Table {
+			fmt.Fprintln(os.Stderr)
+		}
+		current = p.Table
+
+		percent := 100.0
+		if p.Total > 0 {
+			percent = float64(p.Copied) / float64(p.Total) * 100
+		}
+
+		fmt.Fprintf(os.Stderr, "\r%-22s %d/%d rows (%.1f%%)", p.Table, p.Copied, p.Total, percent)
+	}
+}
+
+func printMigrateResults(results []database.MigrateTableResult) error {
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	return render(results, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tSOURCE\tMIGRATED\tSKIPPED\tDESTINATION\tDIFF")
+
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", r.Table, r.Source, r.Migrated, r.Skipped, r.Destination, r.Destination-r.Source)
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Println("Database migration completed")
+
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// outputFormat is set by the global --output flag
+var outputFormat = outputTable
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputTable, outputJSON:
+		return nil
+	}
+
+	return errors.New("invalid output format %q, must be table or json", format)
+}
+
+// render writes v as json or calls table to write the human readable output
+func render(v any, table func() error) error {
+	if outputFormat == outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	}
+
+	return table()
+}
+
+// printResult writes v as json or prints the formatted message
+func printResult(v any, format string, args ...any) error {
+	return render(v, func() error {
+		_, err := fmt.Printf(format+"\n", args...)
+		return err
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// stringList is a flag that can be repeated or given as a comma separated list
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+
+	return nil
+}
+
+// parseReleasePurgeArgs parses the release:purge flags into a delete request
+func parseReleasePurgeArgs(args []string) (*domain.DeleteReleaseRequest, error) {
+	var (
+		olderThan string
+		indexers  stringList
+		statuses  stringList
+		all       bool
+	)
+
+	fs := flag.NewFlagSet("release:purge", flag.ContinueOnError)
+	fs.StringVar(&olderThan, "older-than", "", "only purge releases older than this, e.g. 720h or 30d")
+	fs.Var(&indexers, "indexer", "only purge releases from these indexers")
+	fs.Var(&statuses, "status", "only purge releases with an action status of PENDING, PUSH_APPROVED, PUSH_REJECTED or PUSH_ERROR")
+	fs.BoolVar(&all, "all", false, "purge all releases")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	req := &domain.DeleteReleaseRequest{
+		Indexers: indexers,
+	}
+
+	if olderThan != "" {
+		d, err := parseDuration(olderThan)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid --older-than: %s", olderThan)
+		}
+
+		// the repo works in whole hours
+		req.OlderThan = int((d + time.Hour - 1) / time.Hour)
+	}
+
+	for _, status := range statuses {
+		status = strings.ToUpper(status)
+		if !domain.ValidReleasePushStatus(status) {
+			return nil, errors.New("invalid --status: %s", status)
+		}
+
+		req.ReleaseStatuses = append(req.ReleaseStatuses, domain.ReleasePushStatus(status))
+	}
+
+	if !all && req.OlderThan == 0 && len(req.Indexers) == 0 && len(req.ReleaseStatuses) == 0 {
+		return nil, errors.New("no criteria given, use --all to purge all releases")
+	}
+
+	return req, nil
+}
+
+// parseDuration extends time.ParseDuration with a d suffix for days
+func parseDuration(value string) (time.Duration, error) {
+	var d time.Duration
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+
+	if d <= 0 {
+		return 0, errors.New("duration must be positive")
+	}
+
+	return d, nil
+}
+
+// purgeReleases deletes the releases matching req and returns how many were deleted
+func purgeReleases(ctx context.Context, l logger.Logger, db *database.DB, req *domain.DeleteReleaseRequest) (int64, error) {
+	count, err := database.NewReleaseRepo(l, db).Delete(ctx, req)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not delete releases")
+	}
+
+	return count, nil
+}
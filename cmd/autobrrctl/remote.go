@@ -0,0 +1,552 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// errProblemsFound is returned by commands that completed but found problems, it maps to exit code 1
+var errProblemsFound = errors.New("problems found")
+
+// apiClient talks to a running autobrr instance over the http api
+type apiClient struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// newAPIClient creates a client for the instance at baseURL, which includes the base url path if one is configured
+func newAPIClient(baseURL string, apiKey string) *apiClient {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	return &apiClient{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// do sends a json request to the api and decodes the response into out if it is not nil.
+// A 404 response returns domain.ErrRecordNotFound.
+func (c *apiClient) do(ctx context.Context, method string, path string, query url.Values, body any, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal request")
+		}
+
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("X-API-Token", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not send request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Wrap(domain.ErrRecordNotFound, "%s %s", method, path)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var res struct {
+			Message string `json:"message"`
+		}
+
+		data, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(data, &res); err != nil || res.Message == "" {
+			res.Message = strings.TrimSpace(string(data))
+		}
+
+		return errors.New("%s %s: %s: %s", method, path, resp.Status, res.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "could not decode response")
+	}
+
+	return nil
+}
+
+// healthy checks the unauthenticated health endpoints
+func (c *apiClient) healthy(ctx context.Context, check string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"api/healthz/"+check, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// runRemote runs cmd against a running instance.
+// Commands that need direct access to the database or config files are not supported.
+func runRemote(ctx context.Context, c *apiClient, cmd string, args []string) error {
+	arg := func() (string, error) {
+		if len(args) == 0 || args[0] == "" {
+			return "", errors.New("%s: missing argument", cmd)
+		}
+		return args[0], nil
+	}
+
+	switch cmd {
+	case "list-users":
+		var usernames []string
+		if err := c.do(ctx, http.MethodGet, "api/users", nil, nil, &usernames); err != nil {
+			return errors.Wrap(err, "failed to list users")
+		}
+
+		return printUsers(usernames)
+
+	case "create-user":
+		a, err := parseUserArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if a.ifNotExists {
+			var usernames []string
+			if err := c.do(ctx, http.MethodGet, "api/users", nil, nil, &usernames); err != nil {
+				return errors.Wrap(err, "failed to create user")
+			}
+
+			for _, username := range usernames {
+				if username == a.username {
+					return printResult(map[string]string{"username": a.username, "status": "exists"}, "User %s already exists", a.username)
+				}
+			}
+		}
+
+		password, err := a.password()
+		if err != nil {
+			return errors.Wrap(err, "failed to read password")
+		}
+
+		req := domain.CreateUserRequest{Username: a.username, Password: string(password)}
+		if err := c.do(ctx, http.MethodPost, "api/users", nil, req, nil); err != nil {
+			return errors.Wrap(err, "failed to create user")
+		}
+
+	case "change-password":
+		a, err := parseUserArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		username := a.username
+
+		password, err := a.password()
+		if err != nil {
+			return errors.Wrap(err, "failed to read password")
+		}
+
+		req := map[string]string{"password": string(password)}
+		if err := c.do(ctx, http.MethodPut, "api/users/"+url.PathEscape(username)+"/password", nil, req, nil); err != nil {
+			if errors.Is(err, domain.ErrRecordNotFound) {
+				return errors.New("failed to change password: user %s not found", username)
+			}
+			return errors.Wrap(err, "failed to change password")
+		}
+
+	case "delete-user":
+		username, err := arg()
+		if err != nil {
+			return err
+		}
+
+		if err := c.do(ctx, http.MethodDelete, "api/users/"+url.PathEscape(username), nil, nil, nil); err != nil {
+			if errors.Is(err, domain.ErrRecordNotFound) {
+				return errors.New("failed to delete user: user %s not found", username)
+			}
+			return errors.Wrap(err, "failed to delete user")
+		}
+
+		return printResult(map[string]string{"deleted": username}, "Deleted user %s", username)
+
+	case "reset-2fa":
+		username, err := arg()
+		if err != nil {
+			return err
+		}
+
+		return errors.New("failed to reset 2fa: 2fa is not supported, user %s has no second factor. Use change-password to regain access", username)
+
+	case "filter:export":
+		path, err := arg()
+		if err != nil {
+			return err
+		}
+
+		exports, err := c.collectFilters(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to export filters")
+		}
+
+		if err := writeFilterExports(path, exports); err != nil {
+			return errors.Wrap(err, "failed to export filters")
+		}
+
+		return printResult(map[string]any{"count": len(exports), "file": path}, "Exported %d filters to %s", len(exports), path)
+
+	case "filter:import":
+		path, err := arg()
+		if err != nil {
+			return err
+		}
+
+		imports, err := readFilterExports(path)
+		if err != nil {
+			return errors.Wrap(err, "failed to import filters")
+		}
+
+		if err := c.storeFilters(ctx, imports); err != nil {
+			return errors.Wrap(err, "failed to import filters")
+		}
+
+		return printResult(map[string]any{"count": len(imports), "file": path}, "Imported %d filters from %s", len(imports), path)
+
+	case "filter:test":
+		testArgs, err := parseFilterTestArgs(args)
+		if err != nil {
+			return err
+		}
+
+		req := map[string]string{"title": testArgs.title, "indexer": testArgs.indexer, "size": testArgs.size}
+
+		var res struct {
+			Release domain.Release              `json:"release"`
+			Results []domain.FilterDryRunResult `json:"results"`
+		}
+
+		if err := c.do(ctx, http.MethodPost, "api/filters/dry-run", nil, req, &res); err != nil {
+			return errors.Wrap(err, "failed to test filters")
+		}
+
+		return printFilterTest(&res.Release, res.Results)
+
+	case "apikey:create":
+		key, err := parseAPIKeyArgs(args)
+		if err != nil {
+			return err
+		}
+
+		if err := c.do(ctx, http.MethodPost, "api/keys", nil, key, key); err != nil {
+			return errors.Wrap(err, "failed to create api key")
+		}
+
+		return printResult(key, "%s", key.Key)
+
+	case "apikey:list":
+		var keys []domain.APIKey
+		if err := c.do(ctx, http.MethodGet, "api/keys", nil, nil, &keys); err != nil {
+			return errors.Wrap(err, "failed to list api keys")
+		}
+
+		return printAPIKeys(keys)
+
+	case "apikey:revoke":
+		key, err := arg()
+		if err != nil {
+			return err
+		}
+
+		var keys []domain.APIKey
+		if err := c.do(ctx, http.MethodGet, "api/keys", nil, nil, &keys); err != nil {
+			return errors.Wrap(err, "failed to revoke api key")
+		}
+
+		found := false
+		for _, k := range keys {
+			found = found || k.Key == key
+		}
+
+		if !found {
+			return errors.New("failed to revoke api key: api key not found")
+		}
+
+		if err := c.do(ctx, http.MethodDelete, "api/keys/"+url.PathEscape(key), nil, nil, nil); err != nil {
+			return errors.Wrap(err, "failed to revoke api key")
+		}
+
+		return printResult(map[string]string{"revoked": key}, "Revoked api key %s", key)
+
+	case "indexer:list":
+		a, err := parseIndexerArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		var indexers []domain.Indexer
+		if err := c.do(ctx, http.MethodGet, "api/indexer/options", nil, nil, &indexers); err != nil {
+			return errors.Wrap(err, "failed to list indexers")
+		}
+
+		selected, err := selectIndexers(indexers, a)
+		if err != nil {
+			return err
+		}
+
+		return printIndexers(selected)
+
+	case "indexer:enable", "indexer:disable":
+		a, err := parseIndexerArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		var indexers []domain.Indexer
+		if err := c.do(ctx, http.MethodGet, "api/indexer/options", nil, nil, &indexers); err != nil {
+			return errors.Wrap(err, "failed to update indexers")
+		}
+
+		enabled := cmd == "indexer:enable"
+
+		updated, err := toggleIndexers(indexers, a, enabled, func(indexer domain.Indexer) error {
+			body := map[string]bool{"enabled": enabled}
+			return c.do(ctx, http.MethodPatch, fmt.Sprintf("api/indexer/%d/enabled", indexer.ID), nil, body, nil)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to update indexers")
+		}
+
+		return printIndexersToggled(updated, enabled)
+
+	case "logs":
+		a, err := parseLogsArgs(args)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return c.tailRemoteLogs(ctx, a)
+
+	case "stats":
+		var report releaseStatsReport
+		if err := c.do(ctx, http.MethodGet, "api/release/stats", nil, nil, &report.Summary); err != nil {
+			return errors.Wrap(err, "failed to collect stats")
+		}
+
+		if err := c.do(ctx, http.MethodGet, "api/release/stats/breakdown", nil, nil, &report.Breakdown); err != nil {
+			return errors.Wrap(err, "failed to collect stats")
+		}
+
+		return printStats(&report)
+
+	case "release:purge":
+		req, err := parseReleasePurgeArgs(args)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if req.OlderThan > 0 {
+			query.Set("olderThan", strconv.Itoa(req.OlderThan))
+		}
+		for _, indexer := range req.Indexers {
+			query.Add("indexer", indexer)
+		}
+		for _, status := range req.ReleaseStatuses {
+			query.Add("push_status", string(status))
+		}
+
+		var res struct {
+			Deleted int64 `json:"deleted"`
+		}
+		if err := c.do(ctx, http.MethodDelete, "api/release", query, nil, &res); err != nil {
+			return errors.Wrap(err, "failed to purge releases")
+		}
+
+		return printResult(map[string]int64{"purged": res.Deleted}, "Purged %d releases", res.Deleted)
+
+	case "db:vacuum":
+		var res struct {
+			Before []database.TableStats `json:"before"`
+			After  []database.TableStats `json:"after"`
+		}
+
+		if err := c.do(ctx, http.MethodPost, "api/database/vacuum", nil, nil, &res); err != nil {
+			return errors.Wrap(err, "failed to vacuum database")
+		}
+
+		return printVacuum(res.Before, res.After)
+
+	case "db:check":
+		var res struct {
+			Tables   []database.TableStats `json:"tables"`
+			Problems []string              `json:"problems"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, "api/database/check", nil, nil, &res); err != nil {
+			return errors.Wrap(err, "failed to check database")
+		}
+
+		ok, err := printCheck(res.Tables, res.Problems)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errProblemsFound
+		}
+
+	case "doctor":
+		report, err := runRemoteDoctor(ctx, c)
+		if err != nil {
+			return errors.Wrap(err, "failed to run doctor")
+		}
+
+		if err := render(report, func() error { return printDoctor(report) }); err != nil {
+			return err
+		}
+
+		if len(report.Problems) > 0 {
+			return errProblemsFound
+		}
+
+	default:
+		return errors.New("%s is not supported in remote mode", cmd)
+	}
+
+	return nil
+}
+
+// collectFilters loads all filters over the api in the filter:export format
+func (c *apiClient) collectFilters(ctx context.Context) ([]filterExport, error) {
+	var filters []domain.Filter
+	if err := c.do(ctx, http.MethodGet, "api/filters", nil, nil, &filters); err != nil {
+		return nil, err
+	}
+
+	var groups []domain.FilterGroup
+	if err := c.do(ctx, http.MethodGet, "api/filter_groups", nil, nil, &groups); err != nil {
+		return nil, errors.Wrap(err, "could not list filter groups")
+	}
+
+	groupNames := make(map[int]string, len(groups))
+	for _, group := range groups {
+		groupNames[group.ID] = group.Name
+	}
+
+	exports := make([]filterExport, 0, len(filters))
+
+	for _, f := range filters {
+		var filter domain.Filter
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("api/filters/%d", f.ID), nil, nil, &filter); err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %s", f.Name)
+		}
+
+		exports = append(exports, newFilterExport(filter, groupNames[filter.GroupID], filter.Indexers, filter.Actions))
+	}
+
+	return exports, nil
+}
+
+// storeFilters creates the filters over the api, mapping indexers and download clients by identifier and name
+func (c *apiClient) storeFilters(ctx context.Context, imports []filterExport) error {
+	var groups []domain.FilterGroup
+	if err := c.do(ctx, http.MethodGet, "api/filter_groups", nil, nil, &groups); err != nil {
+		return errors.Wrap(err, "could not list filter groups")
+	}
+
+	groupMap := make(map[string]int, len(groups))
+	for _, group := range groups {
+		groupMap[group.Name] = group.ID
+	}
+
+	var indexers []domain.Indexer
+	if err := c.do(ctx, http.MethodGet, "api/indexer/options", nil, nil, &indexers); err != nil {
+		return errors.Wrap(err, "could not list indexers")
+	}
+
+	indexerMap := make(map[string]domain.Indexer, len(indexers))
+	for _, indexer := range indexers {
+		indexerMap[indexer.Identifier] = indexer
+	}
+
+	var clients []domain.DownloadClient
+	if err := c.do(ctx, http.MethodGet, "api/download_clients", nil, nil, &clients); err != nil {
+		return errors.Wrap(err, "could not list download clients")
+	}
+
+	clientMap := make(map[string]domain.DownloadClient, len(clients))
+	for _, client := range clients {
+		clientMap[client.Name] = client
+	}
+
+	for _, imp := range imports {
+		filter, err := imp.resolve(groupMap, indexerMap, clientMap)
+		if err != nil {
+			return err
+		}
+
+		// store only creates the filter, update takes care of indexers, actions and external filters
+		var stored domain.Filter
+		if err := c.do(ctx, http.MethodPost, "api/filters", nil, filter, &stored); err != nil {
+			return errors.Wrap(err, "could not store filter: %s", filter.Name)
+		}
+
+		filter.ID = stored.ID
+		for _, action := range filter.Actions {
+			action.FilterID = filter.ID
+		}
+
+		if err := c.do(ctx, http.MethodPut, fmt.Sprintf("api/filters/%d", filter.ID), nil, filter, nil); err != nil {
+			return errors.Wrap(err, "could not update filter: %s", filter.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type dumpSeedArgs struct {
+	source string
+	output string
+	redact bool
+}
+
+func parseDumpSeedArgs(args []string) (*dumpSeedArgs, error) {
+	a := &dumpSeedArgs{}
+
+	fs := flag.NewFlagSet("db:dump-seed", flag.ContinueOnError)
+	fs.BoolVar(&a.redact, "redact", false, "redact passwords, passkeys and tokens and leave out api keys")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	a.source, a.output = fs.Arg(0), fs.Arg(1)
+	if a.source == "" || a.output == "" {
+		return nil, errors.New("source database and output file required")
+	}
+
+	return a, nil
+}
+
+// dumpSeed writes a seed file from the sqlite database file or postgres connection string in source.
+// The source is opened without running migrations so a live database is left untouched.
+func dumpSeed(ctx context.Context, l logger.Logger, args *dumpSeedArgs) error {
+	driver := "sqlite"
+	if strings.HasPrefix(args.source, "postgres://") || strings.HasPrefix(args.source, "postgresql://") {
+		driver = "postgres"
+	}
+
+	db, err := database.NewDBFromDSN(l, driver, args.source)
+	if err != nil {
+		return err
+	}
+
+	if driver == "sqlite" {
+		if _, err := os.Stat(args.source); err != nil {
+			return errors.Wrap(err, "could not find database: %s", args.source)
+		}
+	}
+
+	if err := db.Connect(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	f, err := os.OpenFile(args.output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create file: %s", args.output)
+	}
+
+	defer f.Close()
+
+	if err := db.DumpSeed(ctx, f, args.redact); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func loadSeed(ctx context.Context, db *database.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open file: %s", path)
+	}
+
+	defer f.Close()
+
+	return db.Seed(ctx, f)
+}
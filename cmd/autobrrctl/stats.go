@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type releaseStatsReport struct {
+	Summary   *domain.ReleaseStats          `json:"summary"`
+	Breakdown *domain.ReleaseStatsBreakdown `json:"breakdown"`
+}
+
+func collectStats(ctx context.Context, l logger.Logger, db *database.DB) (*releaseStatsReport, error) {
+	releaseRepo := database.NewReleaseRepo(l, db)
+
+	summary, err := releaseRepo.Stats(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get release stats")
+	}
+
+	breakdown, err := releaseRepo.StatsBreakdown(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get release stats breakdown")
+	}
+
+	return &releaseStatsReport{Summary: summary, Breakdown: breakdown}, nil
+}
+
+func printStats(report *releaseStatsReport) error {
+	return render(report, func() error {
+		s := report.Summary
+		fmt.Printf("Releases: %d total, %d filter approved, %d filter rejected\n", s.TotalCount, s.FilteredCount, s.FilterRejectedCount)
+
+		sections := []struct {
+			title  string
+			groups []domain.ReleaseStatsGroup
+		}{
+			{title: "PERIOD", groups: report.Breakdown.Windows},
+			{title: "FILTER", groups: report.Breakdown.Filters},
+			{title: "INDEXER", groups: report.Breakdown.Indexers},
+		}
+
+		for _, section := range sections {
+			fmt.Println()
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "%s\tSNATCHED\tREJECTED\tERRORS\tERROR RATE\n", section.title)
+
+			for _, g := range section.groups {
+				name := g.Name
+				if name == "" {
+					name = "-"
+				}
+
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\n", name, g.Snatched, g.Rejected, g.Errors, g.ErrorRate*100)
+			}
+
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// userArgs are the arguments of create-user and change-password.
+// The password is read from the environment, stdin or the terminal in that order.
+type userArgs struct {
+	username      string
+	passwordEnv   string
+	passwordStdin bool
+	ifNotExists   bool
+}
+
+// parseUserArgs accepts the username before or after the flags
+func parseUserArgs(cmd string, args []string) (*userArgs, error) {
+	a := &userArgs{}
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		a.username = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.StringVar(&a.passwordEnv, "password-env", "", "read the password from this environment variable")
+	fs.BoolVar(&a.passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	if cmd == "create-user" {
+		fs.BoolVar(&a.ifNotExists, "if-not-exists", false, "exit successfully if the user already exists")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if a.username == "" {
+		a.username = fs.Arg(0)
+	}
+
+	if a.username == "" {
+		return nil, errors.New("%s: missing username", cmd)
+	}
+
+	if a.passwordEnv != "" && a.passwordStdin {
+		return nil, errors.New("--password-env and --password-stdin can't be combined")
+	}
+
+	return a, nil
+}
+
+func (a *userArgs) password() ([]byte, error) {
+	switch {
+	case a.passwordEnv != "":
+		password := os.Getenv(a.passwordEnv)
+		if password == "" {
+			return nil, errors.New("environment variable %s is not set or empty", a.passwordEnv)
+		}
+
+		return []byte(password), nil
+
+	case a.passwordStdin:
+		scanner := bufio.NewScanner(os.Stdin)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, errors.Wrap(err, "could not read password from stdin")
+			}
+			return nil, errors.New("stdin is empty")
+		}
+
+		password := scanner.Bytes()
+		if len(password) == 0 {
+			return nil, errors.New("zero length password")
+		}
+
+		return password, nil
+	}
+
+	return readPassword()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"os"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/aria2"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dcarbone/zadapters/zstdlog"
+	"github.com/rs/zerolog"
+)
+
+func (s *service) aria2(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+	s.log.Debug().Msgf("action aria2: %s", action.Name)
+
+	client, err := s.clientSvc.FindByID(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding client: %d", action.ClientID)
+	}
+
+	if client == nil {
+		return nil, errors.New("could not find client by id: %d", action.ClientID)
+	}
+
+	a := aria2.New(aria2.Options{
+		Addr:          client.Host,
+		Secret:        client.Settings.APIKey,
+		TLSSkipVerify: client.TLSSkipVerify,
+		Log:           zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "aria2").Str("client", client.Name).Logger(), zerolog.TraceLevel),
+	})
+
+	opts := aria2.AddOptions{
+		Dir:              action.SavePath,
+		MaxDownloadLimit: action.LimitDownloadSpeed,
+		MaxUploadLimit:   action.LimitUploadSpeed,
+		Pause:            action.Paused,
+		SeedRatio:        action.LimitRatio,
+		SeedTime:         action.LimitSeedTime,
+	}
+
+	if release.HasMagnetUri() {
+		gid, err := a.AddUri(ctx, release.MagnetURI, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not add torrent from magnet %s to client: %s", release.MagnetURI, client.Name)
+		}
+
+		s.log.Info().Msgf("torrent from magnet with gid %s successfully added to client: '%s'", gid, client.Name)
+
+		return nil, nil
+	}
+
+	if release.TorrentTmpFile == "" {
+		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			return nil, errors.Wrap(err, "error downloading torrent file for release: %s", release.TorrentName)
+		}
+	}
+
+	content, err := os.ReadFile(release.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read file: %s", release.TorrentTmpFile)
+	}
+
+	gid, err := a.AddTorrent(ctx, content, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add torrent %s to client: %s", release.TorrentTmpFile, client.Name)
+	}
+
+	s.log.Info().Msgf("torrent with hash %s and gid %s successfully added to client: '%s'", release.TorrentHash, gid, client.Name)
+
+	return nil, nil
+}
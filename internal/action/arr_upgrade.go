@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// radarrUpgradeRejections checks the release against the quality profile of the movie in radarr.
+// It returns rejections when radarr would not want the release so it is not pushed.
+func radarrUpgradeRejections(ctx context.Context, arr radarr.Client, title string) ([]string, error) {
+	parsed, err := arr.Parse(ctx, title)
+	if err != nil {
+		return nil, errors.Wrap(err, "radarr could not parse release: %s", title)
+	}
+
+	if parsed.Movie == nil {
+		return []string{"upgrade mode: unknown movie"}, nil
+	}
+
+	profile, err := arr.GetQualityProfile(ctx, parsed.Movie.QualityProfileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "radarr could not get quality profile for: %s", parsed.Movie.Title)
+	}
+
+	var current *radarr.Quality
+	if parsed.Movie.HasFile && parsed.Movie.MovieFile != nil {
+		current = &parsed.Movie.MovieFile.Quality
+	}
+
+	if upgrade, reason := profile.IsUpgrade(current, parsed.ParsedMovieInfo.Quality); !upgrade {
+		return []string{"upgrade mode: " + reason}, nil
+	}
+
+	return nil, nil
+}
+
+// sonarrUpgradeRejections checks the release against the quality profile of the series in sonarr.
+// Like sonarr itself every episode of a pack has to be missing or upgradable.
+func sonarrUpgradeRejections(ctx context.Context, arr sonarr.Client, title string) ([]string, error) {
+	parsed, err := arr.Parse(ctx, title)
+	if err != nil {
+		return nil, errors.Wrap(err, "sonarr could not parse release: %s", title)
+	}
+
+	if parsed.Series == nil || len(parsed.Episodes) == 0 {
+		return []string{"upgrade mode: unknown series or episode"}, nil
+	}
+
+	profile, err := arr.GetQualityProfile(ctx, parsed.Series.QualityProfileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "sonarr could not get quality profile for: %s", parsed.Series.Title)
+	}
+
+	var rejections []string
+	for _, episode := range parsed.Episodes {
+		var current *sonarr.Quality
+		if episode.HasFile && episode.EpisodeFileID > 0 {
+			file, err := arr.GetEpisodeFile(ctx, episode.EpisodeFileID)
+			if err != nil {
+				return nil, errors.Wrap(err, "sonarr could not get episode file for: %s", parsed.Series.Title)
+			}
+
+			current = &file.Quality
+		}
+
+		if upgrade, reason := profile.IsUpgrade(current, parsed.ParsedEpisodeInfo.Quality); !upgrade {
+			rejections = append(rejections, fmt.Sprintf("upgrade mode: S%02dE%02d %s", episode.SeasonNumber, episode.EpisodeNumber, reason))
+		}
+	}
+
+	return rejections, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/nzbget"
+)
+
+func (s *service) nzbget(ctx context.Context, action *domain.Action, release domain.Release) ([]string, error) {
+	s.log.Trace().Msg("action NZBGet")
+
+	if release.Protocol != domain.ReleaseProtocolNzb {
+		return nil, errors.New("action type: %s invalid protocol: %s", action.Type, release.Protocol)
+	}
+
+	// get client for action
+	client, err := s.clientSvc.FindByID(ctx, action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "nzbget could not find client: %d", action.ClientID)
+	}
+
+	// return early if no client found
+	if client == nil {
+		return nil, errors.New("no nzbget client found by id: %d", action.ClientID)
+	}
+
+	nzb := nzbget.New(nzbget.Options{
+		Addr:     client.Host,
+		Username: client.Username,
+		Password: client.Password,
+	})
+
+	id, err := nzb.Append(ctx, nzbget.AppendRequest{
+		Name:      release.TorrentName + ".nzb",
+		Url:       release.DownloadURL,
+		Category:  action.Category,
+		Priority:  action.Priority,
+		AddPaused: action.Paused,
+		DupeKey:   action.DupeKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add nzb to nzbget")
+	}
+
+	s.log.Trace().Msgf("nzb successfully added to client: %d", id)
+
+	s.log.Info().Msgf("nzb successfully added to client: '%s'", client.Name)
+
+	return nil, nil
+}
@@ -42,6 +42,8 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 
 		s.log.Info().Msgf("torrent from magnet successfully added to client: '%s'", c.Dc.Name)
 
+		s.qbittorrentRemoveUpgraded(ctx, action, c.Qbt, release)
+
 		return nil, nil
 	} else {
 		if release.TorrentTmpFile == "" {
@@ -73,11 +75,46 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		}
 
 		s.log.Info().Msgf("torrent with hash %s successfully added to client: '%s'", release.TorrentHash, c.Dc.Name)
+
+		s.qbittorrentRemoveUpgraded(ctx, action, c.Qbt, release)
 	}
 
 	return nil, nil
 }
 
+// qbittorrentRemoveUpgraded removes the torrent that a PROPER or REPACK replaces when the filter is set to replace it.
+// Only torrents in the category of the action are considered and their data is kept.
+func (s *service) qbittorrentRemoveUpgraded(ctx context.Context, action *domain.Action, qbt *qbittorrent.Client, release domain.Release) {
+	if release.UpgradeOf == "" || release.Filter == nil || !release.Filter.ProperUpgradesReplace {
+		return
+	}
+
+	torrents, err := qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Category: strings.TrimSpace(action.Category)})
+	if err != nil {
+		s.log.Error().Err(err).Msgf("action qBittorrent: could not get torrents to replace: %s", release.UpgradeOf)
+		return
+	}
+
+	var hashes []string
+	for _, torrent := range torrents {
+		if strings.EqualFold(torrent.Name, release.UpgradeOf) && !strings.EqualFold(torrent.Hash, release.TorrentHash) {
+			hashes = append(hashes, torrent.Hash)
+		}
+	}
+
+	if len(hashes) == 0 {
+		s.log.Debug().Msgf("action qBittorrent: torrent to replace not found: %s", release.UpgradeOf)
+		return
+	}
+
+	if err := qbt.DeleteTorrentsCtx(ctx, hashes, false); err != nil {
+		s.log.Error().Err(err).Msgf("action qBittorrent: could not remove replaced torrent: %s", release.UpgradeOf)
+		return
+	}
+
+	s.log.Info().Msgf("removed torrent '%s' replaced by '%s'", release.UpgradeOf, release.TorrentName)
+}
+
 func (s *service) prepareQbitOptions(action *domain.Action) (map[string]string, error) {
 	opts := &qbittorrent.TorrentAddOptions{}
 
@@ -64,6 +64,19 @@ func (s *service) radarr(ctx context.Context, action *domain.Action, release dom
 
 	arr := radarr.New(cfg)
 
+	if release.Filter != nil && release.Filter.ArrUpgradeOnly {
+		rejections, err := radarrUpgradeRejections(ctx, arr, r.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rejections) > 0 {
+			s.log.Debug().Msgf("radarr: release not wanted by quality profile: %v, indexer %v to %v reasons: '%v'", r.Title, r.Indexer, client.Host, rejections)
+
+			return rejections, nil
+		}
+	}
+
 	rejections, err := arr.Push(ctx, r)
 	if err != nil {
 		return nil, errors.Wrap(err, "radarr failed to push release: %v", r)
@@ -40,8 +40,18 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		return nil, err
 	}
 
+	// actions get the release name rewritten by the filter, the stored release keeps the original name
+	actionRelease := release
+	if release.Filter != nil && len(release.Filter.RewriteRules) > 0 {
+		rewritten := *release
+		rewritten.TorrentName = release.Filter.RewriteReleaseName(release.TorrentName)
+		actionRelease = &rewritten
+
+		s.log.Debug().Msgf("action %s: release name rewritten: '%s' to '%s'", action.Name, release.TorrentName, rewritten.TorrentName)
+	}
+
 	// parse all macros in one go
-	if err := action.ParseMacros(release); err != nil {
+	if err := action.ParseMacros(actionRelease); err != nil {
 		return nil, err
 	}
 
@@ -50,46 +60,52 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		s.test(action.Name)
 
 	case domain.ActionTypeExec:
-		err = s.execCmd(ctx, action, *release)
+		err = s.execCmd(ctx, action, *actionRelease)
 
 	case domain.ActionTypeWatchFolder:
-		err = s.watchFolder(ctx, action, *release)
+		err = s.watchFolder(ctx, action, *actionRelease)
 
 	case domain.ActionTypeWebhook:
-		err = s.webhook(ctx, action, *release)
+		err = s.webhook(ctx, action, *actionRelease)
 
 	case domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2:
-		rejections, err = s.deluge(ctx, action, *release)
+		rejections, err = s.deluge(ctx, action, *actionRelease)
 
 	case domain.ActionTypeQbittorrent:
-		rejections, err = s.qbittorrent(ctx, action, *release)
+		rejections, err = s.qbittorrent(ctx, action, *actionRelease)
 
 	case domain.ActionTypeRTorrent:
-		rejections, err = s.rtorrent(ctx, action, *release)
+		rejections, err = s.rtorrent(ctx, action, *actionRelease)
 
 	case domain.ActionTypeTransmission:
-		rejections, err = s.transmission(ctx, action, *release)
+		rejections, err = s.transmission(ctx, action, *actionRelease)
 
 	case domain.ActionTypePorla:
-		rejections, err = s.porla(ctx, action, *release)
+		rejections, err = s.porla(ctx, action, *actionRelease)
 
 	case domain.ActionTypeRadarr:
-		rejections, err = s.radarr(ctx, action, *release)
+		rejections, err = s.radarr(ctx, action, *actionRelease)
 
 	case domain.ActionTypeSonarr:
-		rejections, err = s.sonarr(ctx, action, *release)
+		rejections, err = s.sonarr(ctx, action, *actionRelease)
 
 	case domain.ActionTypeLidarr:
-		rejections, err = s.lidarr(ctx, action, *release)
+		rejections, err = s.lidarr(ctx, action, *actionRelease)
 
 	case domain.ActionTypeWhisparr:
-		rejections, err = s.whisparr(ctx, action, *release)
+		rejections, err = s.whisparr(ctx, action, *actionRelease)
 
 	case domain.ActionTypeReadarr:
-		rejections, err = s.readarr(ctx, action, *release)
+		rejections, err = s.readarr(ctx, action, *actionRelease)
 
 	case domain.ActionTypeSabnzbd:
-		rejections, err = s.sabnzbd(ctx, action, *release)
+		rejections, err = s.sabnzbd(ctx, action, *actionRelease)
+
+	case domain.ActionTypeNzbget:
+		rejections, err = s.nzbget(ctx, action, *actionRelease)
+
+	case domain.ActionTypeAria2:
+		rejections, err = s.aria2(ctx, action, *actionRelease)
 
 	default:
 		return nil, errors.New("unsupported action type: %s", action.Type)
@@ -21,6 +21,7 @@ type Service interface {
 	List(ctx context.Context) ([]domain.Action, error)
 	Get(ctx context.Context, req *domain.GetActionRequest) (*domain.Action, error)
 	FindByFilterID(ctx context.Context, filterID int) ([]*domain.Action, error)
+	FindByFilterGroupID(ctx context.Context, groupID int) ([]*domain.Action, error)
 	Delete(ctx context.Context, req *domain.DeleteActionRequest) error
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	ToggleEnabled(actionID int) error
@@ -80,6 +81,10 @@ func (s *service) FindByFilterID(ctx context.Context, filterID int) ([]*domain.A
 	return s.repo.FindByFilterID(ctx, filterID)
 }
 
+func (s *service) FindByFilterGroupID(ctx context.Context, groupID int) ([]*domain.Action, error) {
+	return s.repo.FindByFilterGroupID(ctx, groupID)
+}
+
 func (s *service) Delete(ctx context.Context, req *domain.DeleteActionRequest) error {
 	return s.repo.Delete(ctx, req)
 }
@@ -64,6 +64,19 @@ func (s *service) sonarr(ctx context.Context, action *domain.Action, release dom
 
 	arr := sonarr.New(cfg)
 
+	if release.Filter != nil && release.Filter.ArrUpgradeOnly {
+		rejections, err := sonarrUpgradeRejections(ctx, arr, r.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rejections) > 0 {
+			s.log.Debug().Msgf("sonarr: release not wanted by quality profile: %v, indexer %v to %v reasons: '%v'", r.Title, r.Indexer, client.Host, rejections)
+
+			return rejections, nil
+		}
+	}
+
 	rejections, err := arr.Push(ctx, r)
 	if err != nil {
 		return nil, errors.Wrap(err, "sonarr: failed to push release: %v", r)
@@ -8,7 +8,9 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/release"
@@ -18,7 +20,17 @@ import (
 )
 
 type Processor interface {
-	AddLineToQueue(channel string, line string) error
+	AddLineToQueue(channel string, line string, timestamp time.Time) error
+	Stop()
+}
+
+// errProcessorStopped is returned for lines queued after the processor was stopped
+var errProcessorStopped = errors.Sentinel("announce processor stopped")
+
+// announceLine is a queued line with the time it was sent to the channel
+type announceLine struct {
+	line      string
+	timestamp time.Time
 }
 
 type announceProcessor struct {
@@ -27,7 +39,11 @@ type announceProcessor struct {
 
 	releaseSvc release.Service
 
-	queues map[string]chan string
+	queues map[string]chan announceLine
+
+	// done is closed to stop the queue consumers, like when the definition is reloaded
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewAnnounceProcessor(log zerolog.Logger, releaseSvc release.Service, indexer *domain.IndexerDefinition) Processor {
@@ -35,6 +51,7 @@ func NewAnnounceProcessor(log zerolog.Logger, releaseSvc release.Service, indexe
 		log:        log.With().Str("module", "announce_processor").Logger(),
 		releaseSvc: releaseSvc,
 		indexer:    indexer,
+		done:       make(chan struct{}),
 	}
 
 	// setup queues and consumers
@@ -45,11 +62,11 @@ func NewAnnounceProcessor(log zerolog.Logger, releaseSvc release.Service, indexe
 }
 
 func (a *announceProcessor) setupQueues() {
-	queues := make(map[string]chan string)
+	queues := make(map[string]chan announceLine)
 	for _, channel := range a.indexer.IRC.Channels {
 		channel = strings.ToLower(channel)
 
-		queues[channel] = make(chan string, 128)
+		queues[channel] = make(chan announceLine, 128)
 		a.log.Trace().Msgf("announce: setup queue: %v", channel)
 	}
 
@@ -58,26 +75,42 @@ func (a *announceProcessor) setupQueues() {
 
 func (a *announceProcessor) setupQueueConsumers() {
 	for queueName, queue := range a.queues {
-		go func(name string, q chan string) {
+		go func(name string, q chan announceLine) {
 			a.log.Trace().Msgf("announce: setup queue consumer: %v", name)
-			a.processQueue(q)
+			if a.indexer.IRC.Parse != nil && a.indexer.IRC.Parse.Assemble != nil {
+				a.processAssembledQueue(q)
+			} else {
+				a.processQueue(q)
+			}
 			a.log.Trace().Msgf("announce: queue consumer stopped: %v", name)
 		}(queueName, queue)
 	}
 }
 
-func (a *announceProcessor) processQueue(queue chan string) {
+func (a *announceProcessor) processQueue(queue chan announceLine) {
 	for {
 		tmpVars := map[string]string{}
 		parseFailed := false
 		//patternParsed := false
 
-		for _, parseLine := range a.indexer.IRC.Parse.Lines {
-			line, err := a.getNextLine(queue)
+		// the release is announced when its first line was sent
+		var timestamp time.Time
+
+		for i, parseLine := range a.indexer.IRC.Parse.Lines {
+			next, err := a.getNextLine(queue)
 			if err != nil {
+				if errors.Is(err, errProcessorStopped) {
+					return
+				}
+
 				a.log.Error().Err(err).Msg("could not get line from queue")
 				return
 			}
+
+			line := next.line
+			if i == 0 {
+				timestamp = next.timestamp
+			}
 			a.log.Trace().Msgf("announce: process line: %v", line)
 
 			// check should ignore
@@ -101,39 +134,247 @@ func (a *announceProcessor) processQueue(queue chan string) {
 			continue
 		}
 
-		rls := domain.NewRelease(a.indexer.Identifier)
-		rls.Protocol = domain.ReleaseProtocol(a.indexer.Protocol)
-
-		// on lines matched
-		if err := a.onLinesMatched(a.indexer, tmpVars, rls); err != nil {
+		rls, err := a.newRelease(tmpVars)
+		if err != nil {
 			a.log.Error().Err(err).Msg("error match line")
 			continue
 		}
 
+		if !timestamp.IsZero() {
+			rls.Timestamp = timestamp
+		}
+
 		// process release in a new go routine
 		go a.releaseSvc.Process(rls)
 	}
 }
 
-func (a *announceProcessor) getNextLine(queue chan string) (string, error) {
+// processAssembledQueue joins the lines of announces that span a varying number of lines and parses them as one line
+func (a *announceProcessor) processAssembledQueue(queue chan announceLine) {
+	as, err := newAssembler(a.indexer.IRC.Parse.Assemble)
+	if err != nil {
+		a.log.Error().Err(err).Msgf("could not setup announce assembler for indexer: %s", a.indexer.Identifier)
+
+		// keep the queue from blocking the irc handler
+		for {
+			select {
+			case <-queue:
+			case <-a.done:
+				return
+			}
+		}
+	}
+
 	for {
-		line, ok := <-queue
+		// an announce that does not get its next line in time is finished by the timeout
+		var timeout <-chan time.Time
+		if len(as.pending) > 0 {
+			timeout = time.After(as.timeout)
+		}
+
+		select {
+		case next, ok := <-queue:
+			if !ok {
+				a.log.Error().Msg("could not get line from queue")
+				return
+			}
+
+			a.log.Trace().Msgf("announce: process line: %v", next.line)
+
+			for _, announce := range as.add(next) {
+				a.processAssembled(announce)
+			}
+
+		case <-timeout:
+			for _, announce := range as.flush() {
+				a.processAssembled(announce)
+			}
+
+		case <-a.done:
+			return
+		}
+	}
+}
+
+func (a *announceProcessor) processAssembled(announce AssembledAnnounce) {
+	a.log.Trace().Msgf("announce: assembled %d lines: %v", len(announce.Lines), announce.Line)
+
+	vars := map[string]string{}
+	for _, parseLine := range a.indexer.IRC.Parse.Lines {
+		match, err := a.parseLine(parseLine.Pattern, parseLine.Vars, vars, announce.Line, parseLine.Ignore)
+		if err != nil {
+			a.log.Error().Err(err).Msgf("error parsing extract for line: %v", announce.Line)
+			return
+		}
+
+		if !match {
+			a.log.Debug().Msgf("line not matching expected regex pattern: %v", announce.Line)
+			return
+		}
+	}
+
+	rls, err := a.newRelease(vars)
+	if err != nil {
+		a.log.Error().Err(err).Msg("error match line")
+		return
+	}
+
+	if !announce.Timestamp.IsZero() {
+		rls.Timestamp = announce.Timestamp
+	}
+
+	// process release in a new go routine
+	go a.releaseSvc.Process(rls)
+}
+
+// newRelease creates the release from the vars of the matched lines
+func (a *announceProcessor) newRelease(vars map[string]string) (*domain.Release, error) {
+	rls := domain.NewRelease(a.indexer.Identifier)
+	rls.Protocol = domain.ReleaseProtocol(a.indexer.Protocol)
+
+	// on lines matched
+	if err := a.onLinesMatched(a.indexer, vars, rls); err != nil {
+		return nil, err
+	}
+
+	return rls, nil
+}
+
+// ParseLines parses announce lines with the parse lines of the indexer definition like an announce from irc,
+// without processing the release. The release is only set when all lines matched.
+func ParseLines(log zerolog.Logger, def *domain.IndexerDefinition, lines []string) (*domain.AnnounceTestResult, error) {
+	if def.IRC == nil || def.IRC.Parse == nil {
+		return nil, errors.New("indexer %s has no irc announce parser", def.Identifier)
+	}
+
+	if def.IRC.Parse.Assemble != nil {
+		logLines := make([]domain.IrcLogLine, 0, len(lines))
+		for _, line := range lines {
+			logLines = append(logLines, domain.IrcLogLine{Message: line})
+		}
+
+		announces, err := AssembleLines(def, logLines)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(announces) != 1 {
+			return nil, errors.New("indexer %s assembles %d announces from the lines, expected 1", def.Identifier, len(announces))
+		}
+
+		return ParseAssembled(log, def, announces[0].Line)
+	}
+
+	if len(lines) != len(def.IRC.Parse.Lines) {
+		return nil, errors.New("indexer %s announces %d lines, got %d", def.Identifier, len(def.IRC.Parse.Lines), len(lines))
+	}
+
+	return parseAnnounce(log, def, lines)
+}
+
+// ParseAssembled parses an assembled announce by matching every parse line of the indexer definition against it
+func ParseAssembled(log zerolog.Logger, def *domain.IndexerDefinition, announce string) (*domain.AnnounceTestResult, error) {
+	if def.IRC == nil || def.IRC.Parse == nil {
+		return nil, errors.New("indexer %s has no irc announce parser", def.Identifier)
+	}
+
+	lines := make([]string, len(def.IRC.Parse.Lines))
+	for i := range lines {
+		lines[i] = announce
+	}
+
+	return parseAnnounce(log, def, lines)
+}
+
+// parseAnnounce matches each line with the parse line at the same position
+func parseAnnounce(log zerolog.Logger, def *domain.IndexerDefinition, lines []string) (*domain.AnnounceTestResult, error) {
+	a := &announceProcessor{
+		log:     log.With().Str("module", "announce_processor").Logger(),
+		indexer: def,
+	}
+
+	result := &domain.AnnounceTestResult{
+		Indexer: def.Identifier,
+		Lines:   make([]domain.AnnounceTestLine, 0, len(lines)),
+		Vars:    map[string]string{},
+		Results: []domain.FilterDryRunResult{},
+	}
+
+	result.Matched = true
+	for i, parseLine := range def.IRC.Parse.Lines {
+		line := domain.AnnounceTestLine{Line: lines[i], Pattern: parseLine.Pattern}
+
+		match, err := a.parseLine(parseLine.Pattern, parseLine.Vars, result.Vars, lines[i], parseLine.Ignore)
+		if err != nil {
+			line.Error = err.Error()
+		}
+
+		line.Matched = match && err == nil
+		result.Lines = append(result.Lines, line)
+
+		if !line.Matched {
+			result.Matched = false
+			break
+		}
+	}
+
+	if !result.Matched {
+		return result, nil
+	}
+
+	// the release mapping changes vars, keep the parsed ones in the result
+	vars := mergeVars(result.Vars)
+
+	rls, err := a.newRelease(vars)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create release from announce")
+	}
+
+	result.Release = rls
+
+	return result, nil
+}
+
+func (a *announceProcessor) getNextLine(queue chan announceLine) (announceLine, error) {
+	select {
+	case line, ok := <-queue:
 		if !ok {
-			return "", errors.New("could not queue line")
+			return announceLine{}, errors.New("could not queue line")
 		}
 
 		return line, nil
+
+	case <-a.done:
+		return announceLine{}, errProcessorStopped
 	}
 }
 
-func (a *announceProcessor) AddLineToQueue(channel string, line string) error {
+// Stop stops the queue consumers, lines queued afterwards are rejected
+func (a *announceProcessor) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.done)
+	})
+}
+
+func (a *announceProcessor) AddLineToQueue(channel string, line string, timestamp time.Time) error {
 	channel = strings.ToLower(channel)
 	queue, ok := a.queues[channel]
 	if !ok {
 		return errors.New("no queue for channel (%v) found", channel)
 	}
 
-	queue <- line
+	// the queue has room after the processor was stopped, so check that first
+	select {
+	case <-a.done:
+		return errProcessorStopped
+	default:
+	}
+
+	select {
+	case queue <- announceLine{line: line, timestamp: timestamp}:
+	case <-a.done:
+		return errProcessorStopped
+	}
 	a.log.Trace().Msgf("announce: queued line: %v", line)
 
 	return nil
@@ -212,6 +453,10 @@ func (a *announceProcessor) onLinesMatched(def *domain.IndexerDefinition, vars m
 	// run before ParseMatch to not potentially use a reconstructed TorrentName
 	rls.ParseString(rls.TorrentName)
 
+	if def.Anime {
+		rls.ParseAnime()
+	}
+
 	// set baseUrl to default domain
 	baseUrl := def.URLS[0]
 
@@ -5,7 +5,11 @@ package announce
 
 import (
 	"testing"
+	"time"
 
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,3 +73,173 @@ func Test_announceProcessor_processTorrentUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLines(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		Protocol:   "torrent",
+		URLS:       []string{"https://mock.test/"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Type: "single",
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New Torrent: (.+) - Size: (.+) - (https?://.+?/).+id=(\d+)(?: - (Internal))?`,
+						Vars:    []string{"torrentName", "torrentSize", "baseUrl", "torrentId", "origin"},
+					},
+				},
+				Match: domain.IndexerIRCParseMatch{
+					InfoURL:    "/details.php?id={{ .torrentId }}",
+					TorrentURL: "/download.php?id={{ .torrentId }}&passkey={{ .passkey }}",
+				},
+			},
+		},
+		SettingsMap: map[string]string{"passkey": "secret"},
+	}
+
+	result, err := ParseLines(zerolog.Nop(), def, []string{"New Torrent: That.Movie.2023.1080p.BluRay.x264-GROUP - Size: 8.5 GiB - https://mock.test/details.php?id=12345 - Internal"})
+	assert.NoError(t, err)
+	assert.True(t, result.Matched)
+	assert.Equal(t, "12345", result.Vars["torrentId"])
+	assert.Equal(t, "Internal", result.Vars["origin"])
+	assert.Equal(t, "That.Movie.2023.1080p.BluRay.x264-GROUP", result.Release.TorrentName)
+	assert.Equal(t, "INTERNAL", result.Release.Origin)
+	assert.Equal(t, "1080p", result.Release.Resolution)
+	assert.Equal(t, "https://mock.test/download.php?id=12345&passkey=secret", result.Release.DownloadURL)
+
+	result, err = ParseLines(zerolog.Nop(), def, []string{"Something else"})
+	assert.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.False(t, result.Lines[0].Matched)
+	assert.Nil(t, result.Release)
+
+	_, err = ParseLines(zerolog.Nop(), def, []string{"one", "two"})
+	assert.Error(t, err)
+}
+
+func TestAssembleLines(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Assemble: &domain.IndexerIRCParseAssemble{
+					Start:     `^New: `,
+					Continue:  `^(Size|Tags): `,
+					End:       `^Link: `,
+					Separator: " | ",
+					Timeout:   10,
+				},
+			},
+		},
+	}
+
+	now := time.Now()
+	lines := []domain.IrcLogLine{
+		{Message: "New: First.Release-GRP", Timestamp: now},
+		{Message: "Size: 1 GiB", Timestamp: now.Add(time.Second)},
+		{Message: "someone chatting", Timestamp: now.Add(2 * time.Second)},
+		{Message: "Link: https://mock.test/1", Timestamp: now.Add(3 * time.Second)},
+		// never ends so it is dropped when the next announce starts
+		{Message: "New: Incomplete.Release-GRP", Timestamp: now.Add(4 * time.Second)},
+		{Message: "New: Second.Release-GRP", Timestamp: now.Add(5 * time.Second)},
+		{Message: "Size: 2 GiB", Timestamp: now.Add(6 * time.Second)},
+		{Message: "Tags: action", Timestamp: now.Add(7 * time.Second)},
+		{Message: "Link: https://mock.test/2", Timestamp: now.Add(8 * time.Second)},
+		// times out before its end line
+		{Message: "New: Slow.Release-GRP", Timestamp: now.Add(9 * time.Second)},
+		{Message: "Link: https://mock.test/3", Timestamp: now.Add(time.Minute)},
+	}
+
+	announces, err := AssembleLines(def, lines)
+	assert.NoError(t, err)
+	assert.Len(t, announces, 2)
+	assert.Equal(t, "New: First.Release-GRP | Size: 1 GiB | Link: https://mock.test/1", announces[0].Line)
+	assert.Equal(t, now, announces[0].Timestamp)
+	assert.Equal(t, "New: Second.Release-GRP | Size: 2 GiB | Tags: action | Link: https://mock.test/2", announces[1].Line)
+	assert.Len(t, announces[1].Lines, 4)
+
+	// without an end pattern the announce ends on the first line that does not continue it
+	def.IRC.Parse.Assemble = &domain.IndexerIRCParseAssemble{Start: `^New: `, Continue: `^(Size|Tags): `}
+
+	announces, err = AssembleLines(def, []domain.IrcLogLine{
+		{Message: "New: First.Release-GRP"},
+		{Message: "Size: 1 GiB"},
+		{Message: "someone chatting"},
+		{Message: "New: Second.Release-GRP"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, announces, 2)
+	assert.Equal(t, "New: First.Release-GRP Size: 1 GiB", announces[0].Line)
+	assert.Equal(t, "New: Second.Release-GRP", announces[1].Line)
+
+	def.IRC.Parse.Assemble = &domain.IndexerIRCParseAssemble{Start: `^New: `, MaxLines: 2}
+
+	announces, err = AssembleLines(def, []domain.IrcLogLine{
+		{Message: "New: First.Release-GRP"},
+		{Message: "Size: 1 GiB"},
+		{Message: "Size: 2 GiB"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, announces, 1)
+	assert.Equal(t, "New: First.Release-GRP Size: 1 GiB", announces[0].Line)
+}
+
+func TestParseLines_Assemble(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		Protocol:   "torrent",
+		URLS:       []string{"https://mock.test/"},
+		IRC: &domain.IndexerIRC{
+			Parse: &domain.IndexerIRCParse{
+				Type: "multi",
+				Lines: []domain.IndexerIRCParseLine{
+					{
+						Pattern: `New: (\S+)`,
+						Vars:    []string{"torrentName"},
+					},
+					{
+						Pattern: `Link: (https?://.+?/)(\d+)`,
+						Vars:    []string{"baseUrl", "torrentId"},
+					},
+				},
+				Assemble: &domain.IndexerIRCParseAssemble{
+					Start: `^New: `,
+					End:   `^Link: `,
+				},
+				Match: domain.IndexerIRCParseMatch{
+					TorrentURL: "/download/{{ .torrentId }}",
+				},
+			},
+		},
+	}
+
+	result, err := ParseLines(zerolog.Nop(), def, []string{"New: That.Movie.2023.1080p.BluRay.x264-GROUP", "Size: 8.5 GiB", "Link: https://mock.test/12345"})
+	assert.NoError(t, err)
+	assert.True(t, result.Matched)
+	assert.Equal(t, "12345", result.Vars["torrentId"])
+	assert.Equal(t, "That.Movie.2023.1080p.BluRay.x264-GROUP", result.Release.TorrentName)
+	assert.Equal(t, "https://mock.test/download/12345", result.Release.DownloadURL)
+
+	_, err = ParseLines(zerolog.Nop(), def, []string{"New: That.Movie.2023.1080p.BluRay.x264-GROUP", "Size: 8.5 GiB"})
+	assert.Error(t, err)
+}
+
+func TestAnnounceProcessor_Stop(t *testing.T) {
+	def := &domain.IndexerDefinition{
+		Identifier: "mock",
+		IRC: &domain.IndexerIRC{
+			Channels: []string{"#announce"},
+			Parse: &domain.IndexerIRCParse{
+				Type:  "single",
+				Lines: []domain.IndexerIRCParseLine{{Pattern: `New: (.+)`, Vars: []string{"torrentName"}}},
+			},
+		},
+	}
+
+	p := NewAnnounceProcessor(zerolog.Nop(), nil, def)
+	p.Stop()
+	p.Stop()
+
+	err := p.AddLineToQueue("#announce", "New: That.Movie.2023.1080p.BluRay.x264-GROUP", time.Now())
+	assert.ErrorIs(t, err, errProcessorStopped)
+}
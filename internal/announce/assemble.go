@@ -0,0 +1,167 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package announce
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// defaultAssembleTimeout is how long an announce waits for its next line when the definition sets no timeout
+const defaultAssembleTimeout = 30 * time.Second
+
+// AssembledAnnounce is an announce joined from the lines it was sent in
+type AssembledAnnounce struct {
+	Line      string
+	Lines     []string
+	Timestamp time.Time
+}
+
+// assembler joins the lines of announces with the assemble patterns of an indexer definition
+type assembler struct {
+	start     *regexp.Regexp
+	cont      *regexp.Regexp
+	end       *regexp.Regexp
+	separator string
+	maxLines  int
+	timeout   time.Duration
+
+	pending  []announceLine
+	lastSeen time.Time
+}
+
+func newAssembler(cfg *domain.IndexerIRCParseAssemble) (*assembler, error) {
+	if cfg.Start == "" {
+		return nil, errors.New("assemble needs a start pattern")
+	}
+
+	as := &assembler{
+		separator: cfg.Separator,
+		maxLines:  cfg.MaxLines,
+		timeout:   time.Duration(cfg.Timeout) * time.Second,
+	}
+
+	if as.separator == "" {
+		as.separator = " "
+	}
+
+	if as.timeout <= 0 {
+		as.timeout = defaultAssembleTimeout
+	}
+
+	var err error
+	if as.start, err = regexp.Compile(cfg.Start); err != nil {
+		return nil, errors.Wrap(err, "invalid assemble start pattern")
+	}
+
+	if cfg.Continue != "" {
+		if as.cont, err = regexp.Compile(cfg.Continue); err != nil {
+			return nil, errors.Wrap(err, "invalid assemble continue pattern")
+		}
+	}
+
+	if cfg.End != "" {
+		if as.end, err = regexp.Compile(cfg.End); err != nil {
+			return nil, errors.Wrap(err, "invalid assemble end pattern")
+		}
+	}
+
+	return as, nil
+}
+
+// add adds a line and returns the announces it completed
+func (as *assembler) add(line announceLine) []AssembledAnnounce {
+	var done []AssembledAnnounce
+
+	// a pending announce that waited too long for its next line is finished before the line is handled
+	if len(as.pending) > 0 && !line.timestamp.IsZero() && !as.lastSeen.IsZero() && line.timestamp.Sub(as.lastSeen) > as.timeout {
+		done = as.appendFinished(done)
+	}
+
+	switch {
+	case as.start.MatchString(line.line):
+		done = as.appendFinished(done)
+		as.pending = []announceLine{line}
+
+	case len(as.pending) == 0:
+		return done
+
+	case as.end != nil && as.end.MatchString(line.line), as.cont == nil || as.cont.MatchString(line.line):
+		as.pending = append(as.pending, line)
+
+	case as.end != nil:
+		// the line does not belong to the announce which keeps waiting for its end line
+		return done
+
+	default:
+		// without an end pattern the first line that does not continue the announce finishes it
+		return as.appendFinished(done)
+	}
+
+	as.lastSeen = line.timestamp
+
+	if (as.end != nil && as.end.MatchString(line.line)) || (as.maxLines > 0 && len(as.pending) >= as.maxLines) {
+		done = append(done, as.join())
+	}
+
+	return done
+}
+
+// flush finishes the pending announce when no more lines arrive, it is only complete without an end pattern
+func (as *assembler) flush() []AssembledAnnounce {
+	return as.appendFinished(nil)
+}
+
+func (as *assembler) appendFinished(done []AssembledAnnounce) []AssembledAnnounce {
+	if len(as.pending) == 0 {
+		return done
+	}
+
+	if as.end != nil {
+		// without its end line the announce is incomplete
+		as.pending = nil
+		return done
+	}
+
+	return append(done, as.join())
+}
+
+func (as *assembler) join() AssembledAnnounce {
+	announce := AssembledAnnounce{
+		Lines:     make([]string, 0, len(as.pending)),
+		Timestamp: as.pending[0].timestamp,
+	}
+
+	for _, l := range as.pending {
+		announce.Lines = append(announce.Lines, l.line)
+	}
+	announce.Line = strings.Join(announce.Lines, as.separator)
+
+	as.pending = nil
+
+	return announce
+}
+
+// AssembleLines joins logged announce lines with the assemble patterns of the indexer definition
+func AssembleLines(def *domain.IndexerDefinition, lines []domain.IrcLogLine) ([]AssembledAnnounce, error) {
+	if def.IRC == nil || def.IRC.Parse == nil || def.IRC.Parse.Assemble == nil {
+		return nil, errors.New("indexer %s does not assemble announces", def.Identifier)
+	}
+
+	as, err := newAssembler(def.IRC.Parse.Assemble)
+	if err != nil {
+		return nil, err
+	}
+
+	var announces []AssembledAnnounce
+	for _, line := range lines {
+		announces = append(announces, as.add(announceLine{line: line.Message, timestamp: line.Timestamp})...)
+	}
+
+	return append(announces, as.flush()...), nil
+}
@@ -20,6 +20,7 @@ type Service interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	FindAPIKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type service struct {
@@ -72,17 +73,24 @@ func (s *service) Delete(ctx context.Context, key string) error {
 }
 
 func (s *service) ValidateAPIKey(ctx context.Context, key string) bool {
+	_, err := s.FindAPIKey(ctx, key)
+	return err == nil
+}
+
+// FindAPIKey returns the api key with its scopes, or domain.ErrRecordNotFound
+func (s *service) FindAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
 	keys, err := s.repo.GetKeys(ctx)
 	if err != nil {
-		return false
+		return nil, err
 	}
 
 	for _, k := range keys {
 		if k.Key == key {
-			return true
+			return &k, nil
 		}
 	}
-	return false
+
+	return nil, domain.ErrRecordNotFound
 }
 
 func GenerateSecureToken(length int) string {
@@ -5,7 +5,11 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
 
+	"github.com/autobrr/autobrr/internal/api"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/user"
@@ -19,17 +23,25 @@ type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	ListUsers(ctx context.Context) ([]string, error)
+	UpdatePassword(ctx context.Context, username, password string) error
+	DeleteUser(ctx context.Context, username string) error
+	CreateRecoveryToken(ctx context.Context, ttl time.Duration) (string, error)
+	RecoveryLogin(ctx context.Context, token string) error
+	RevokeRecoveryTokens(ctx context.Context) (int64, error)
 }
 
 type service struct {
-	log     zerolog.Logger
-	userSvc user.Service
+	log          zerolog.Logger
+	userSvc      user.Service
+	recoveryRepo domain.AuthRecoveryRepo
 }
 
-func NewService(log logger.Logger, userSvc user.Service) Service {
+func NewService(log logger.Logger, userSvc user.Service, recoveryRepo domain.AuthRecoveryRepo) Service {
 	return &service{
-		log:     log.With().Str("module", "auth").Logger(),
-		userSvc: userSvc,
+		log:          log.With().Str("module", "auth").Logger(),
+		userSvc:      userSvc,
+		recoveryRepo: recoveryRepo,
 	}
 }
 
@@ -97,3 +109,111 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 
 	return nil
 }
+
+// ListUsers returns the usernames of all users
+func (s *service) ListUsers(ctx context.Context) ([]string, error) {
+	users, err := s.userSvc.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, u := range users {
+		usernames = append(usernames, u.Username)
+	}
+
+	return usernames, nil
+}
+
+func (s *service) UpdatePassword(ctx context.Context, username, password string) error {
+	if password == "" {
+		return errors.New("validation error: empty password supplied")
+	}
+
+	u, err := s.userSvc.FindByUsername(ctx, username)
+	if err != nil {
+		return errors.Wrapf(err, "could not find user: %s", username)
+	}
+
+	hashed, err := argon2id.CreateHash(password, argon2id.DefaultParams)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	u.Password = hashed
+
+	if err := s.userSvc.Update(ctx, *u); err != nil {
+		s.log.Error().Err(err).Msgf("could not update password for user: %s", username)
+		return errors.New("failed to update password")
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the user unless it is the last one, which would lock everyone out
+func (s *service) DeleteUser(ctx context.Context, username string) error {
+	if _, err := s.userSvc.FindByUsername(ctx, username); err != nil {
+		return err
+	}
+
+	userCount, err := s.userSvc.GetUserCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userCount <= 1 {
+		return domain.ErrLastUser
+	}
+
+	return s.userSvc.Delete(ctx, username)
+}
+
+// CreateRecoveryToken creates a single use token that logs in without credentials until ttl has passed.
+// The token is only returned here, the database only has its hash.
+func (s *service) CreateRecoveryToken(ctx context.Context, ttl time.Duration) (string, error) {
+	userCount, err := s.userSvc.GetUserCount(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if userCount == 0 {
+		return "", errors.New("no user exists, create one with onboarding instead")
+	}
+
+	token := api.GenerateSecureToken(32)
+	if token == "" {
+		return "", errors.New("could not generate token")
+	}
+
+	if err := s.recoveryRepo.Store(ctx, domain.AuthRecoveryToken{
+		TokenHash: hashRecoveryToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", errors.Wrap(err, "could not store recovery token")
+	}
+
+	s.log.Warn().Msgf("auth recovery token created, valid for %s", ttl)
+
+	return token, nil
+}
+
+func (s *service) RecoveryLogin(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.New("empty recovery token supplied")
+	}
+
+	if err := s.recoveryRepo.Consume(ctx, hashRecoveryToken(token)); err != nil {
+		return errors.New("invalid or expired recovery token")
+	}
+
+	return nil
+}
+
+func (s *service) RevokeRecoveryTokens(ctx context.Context) (int64, error) {
+	return s.recoveryRepo.DeleteUnused(ctx)
+}
+
+func hashRecoveryToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -81,6 +81,69 @@ logLevel = "DEBUG"
 #
 checkForUpdates = true
 
+# Filter match policy
+# How releases matched by more than one filter are handled.
+# first-match runs the actions of the highest priority matching filter only.
+# all-match runs the actions of every matching filter.
+# per-action-dedupe runs the actions of every matching filter, but never sends a release to the same download client, exec command, webhook or watch folder twice.
+#
+# Default: "first-match"
+#
+# Options: "first-match", "all-match", "per-action-dedupe"
+#
+#filterMatchPolicy = "first-match"
+
+# IRC log retention
+# How many days irc channel lines are kept in the database to search missed announces.
+# Set to 0 to not store them.
+#
+# Default: 7
+#
+#ircLogRetentionDays = 7
+
+# Feed cache retention
+# Feeds remember the items they processed. Items older than the max age, and the oldest items of feeds
+# with more than the max entries, are removed by the hourly feed cache cleanup. Set to 0 to not limit.
+#
+# Default: 30 days and 5000 entries per feed
+#
+#feedCacheMaxAgeDays = 30
+#feedCacheMaxEntries = 5000
+
+# Release deduplication
+# Minutes a release announced on IRC is not processed again from a feed of the same indexer, and the other way around.
+# Releases match on the title with punctuation ignored and a size within 5%. Set to 0 to process both.
+#
+# Default: 60
+#
+#releaseDedupMinutes = 60
+
+# Feed quiet hours
+# Windows in which no feed is refreshed, in server local time, in the format of filter schedules
+# like "01:00-07:00" or "mon-fri 00:00-06:00; sun". Feeds can add their own windows with not between.
+#
+# Default: ""
+#
+#feedQuietHours = "01:00-07:00"
+
+# Feed concurrency
+# How many feeds are refreshed at once, the others wait for their turn. Keeps feeds that share an interval
+# from all fetching in the same second. Set to 0 to not limit.
+#
+# Default: 5
+#
+#feedConcurrency = 5
+
+# Feed torrent prefetch
+# Download the torrent file of a release from a feed once it matched a filter, before its actions run,
+# and keep it in an on-disk cache of the max size in MB. Actions then use the cached file instead of
+# each downloading it from the indexer. Set the cache size to 0 to prefetch without caching.
+#
+# Default: false and 50
+#
+#feedTorrentPrefetch = false
+#feedTorrentCacheMB = 50
+
 # Session secret
 #
 sessionSecret = "{{ .sessionSecret }}"
@@ -182,23 +245,32 @@ func New(configPath string, version string) *AppConfig {
 
 func (c *AppConfig) defaults() {
 	c.Config = &domain.Config{
-		Version:           "dev",
-		Host:              "localhost",
-		Port:              7474,
-		LogLevel:          "TRACE",
-		LogPath:           "",
-		LogMaxSize:        50,
-		LogMaxBackups:     3,
-		BaseURL:           "/",
-		SessionSecret:     api.GenerateSecureToken(16),
-		CustomDefinitions: "",
-		CheckForUpdates:   true,
-		DatabaseType:      "sqlite",
-		PostgresHost:      "",
-		PostgresPort:      0,
-		PostgresDatabase:  "",
-		PostgresUser:      "",
-		PostgresPass:      "",
+		Version:             "dev",
+		Host:                "localhost",
+		Port:                7474,
+		LogLevel:            "TRACE",
+		LogPath:             "",
+		LogMaxSize:          50,
+		LogMaxBackups:       3,
+		BaseURL:             "/",
+		SessionSecret:       api.GenerateSecureToken(16),
+		CustomDefinitions:   "",
+		CheckForUpdates:     true,
+		FilterMatchPolicy:   "first-match",
+		IrcLogRetentionDays: 7,
+		FeedCacheMaxAgeDays: 30,
+		FeedCacheMaxEntries: 5000,
+		ReleaseDedupMinutes: 60,
+		FeedQuietHours:      "",
+		FeedConcurrency:     5,
+		FeedTorrentPrefetch: false,
+		FeedTorrentCacheMB:  50,
+		DatabaseType:        "sqlite",
+		PostgresHost:        "",
+		PostgresPort:        0,
+		PostgresDatabase:    "",
+		PostgresUser:        "",
+		PostgresPass:        "",
 	}
 
 }
@@ -268,6 +340,46 @@ func (c *AppConfig) DynamicReload(log logger.Logger) {
 		checkUpdates := viper.GetBool("checkForUpdates")
 		c.Config.CheckForUpdates = checkUpdates
 
+		if viper.IsSet("filterMatchPolicy") {
+			c.Config.FilterMatchPolicy = viper.GetString("filterMatchPolicy")
+
+			if !domain.ValidFilterMatchPolicy(c.Config.FilterMatchPolicy) {
+				log.Warn().Msgf("unknown filterMatchPolicy %q, using first-match", c.Config.FilterMatchPolicy)
+			}
+		}
+
+		if viper.IsSet("ircLogRetentionDays") {
+			c.Config.IrcLogRetentionDays = viper.GetInt("ircLogRetentionDays")
+		}
+
+		if viper.IsSet("feedCacheMaxAgeDays") {
+			c.Config.FeedCacheMaxAgeDays = viper.GetInt("feedCacheMaxAgeDays")
+		}
+
+		if viper.IsSet("feedCacheMaxEntries") {
+			c.Config.FeedCacheMaxEntries = viper.GetInt("feedCacheMaxEntries")
+		}
+
+		if viper.IsSet("releaseDedupMinutes") {
+			c.Config.ReleaseDedupMinutes = viper.GetInt("releaseDedupMinutes")
+		}
+
+		if viper.IsSet("feedQuietHours") {
+			c.Config.FeedQuietHours = viper.GetString("feedQuietHours")
+		}
+
+		if viper.IsSet("feedConcurrency") {
+			c.Config.FeedConcurrency = viper.GetInt("feedConcurrency")
+		}
+
+		if viper.IsSet("feedTorrentPrefetch") {
+			c.Config.FeedTorrentPrefetch = viper.GetBool("feedTorrentPrefetch")
+		}
+
+		if viper.IsSet("feedTorrentCacheMB") {
+			c.Config.FeedTorrentCacheMB = viper.GetInt("feedTorrentCacheMB")
+		}
+
 		log.Debug().Msg("config file reloaded!")
 
 		c.m.Unlock()
@@ -0,0 +1,265 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/spf13/viper"
+)
+
+// minSessionSecretLength is the shortest session secret we accept.
+// Generated secrets are 32 characters.
+const minSessionSecretLength = 16
+
+type ValidationLevel string
+
+const (
+	ValidationLevelError   ValidationLevel = "ERROR"
+	ValidationLevelWarning ValidationLevel = "WARN"
+)
+
+type ValidationIssue struct {
+	Level   ValidationLevel `json:"level"`
+	Key     string          `json:"key"`
+	Message string          `json:"message"`
+}
+
+type ValidationReport struct {
+	File   string            `json:"file"`
+	Config *domain.Config    `json:"-"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (r *ValidationReport) add(level ValidationLevel, key string, message string) {
+	r.Issues = append(r.Issues, ValidationIssue{Level: level, Key: key, Message: message})
+}
+
+// HasErrors returns true if any issue is an error
+func (r *ValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Level == ValidationLevelError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate reads config.toml in configPath without creating it and checks the values.
+// Database connectivity is not checked here.
+func Validate(configPath string) (*ValidationReport, error) {
+	file := filepath.Join(configPath, "config.toml")
+
+	report := &ValidationReport{File: file}
+
+	if _, err := os.Stat(file); err != nil {
+		return nil, errors.Wrap(err, "could not find config file: %s", file)
+	}
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	v.SetConfigFile(file)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrap(err, "could not parse config file: %s", file)
+	}
+
+	known := knownKeys()
+
+	for _, key := range v.AllKeys() {
+		if _, ok := known[key]; ok {
+			continue
+		}
+
+		message := "unknown key"
+		if suggestion := closestKey(key, known); suggestion != "" {
+			message = "unknown key, did you mean " + suggestion + "?"
+		}
+
+		report.add(ValidationLevelError, key, message)
+	}
+
+	c := &AppConfig{}
+	c.defaults()
+	c.Config.ConfigPath = configPath
+
+	if err := v.Unmarshal(c.Config); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal config file: %s", file)
+	}
+
+	report.Config = c.Config
+
+	validateValues(report, c.Config)
+
+	return report, nil
+}
+
+func validateValues(report *ValidationReport, cfg *domain.Config) {
+	if cfg.Host == "" {
+		report.add(ValidationLevelError, "host", "must not be empty")
+	}
+
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		report.add(ValidationLevelError, "port", "must be between 1 and 65535")
+	}
+
+	switch strings.ToUpper(cfg.LogLevel) {
+	case "ERROR", "DEBUG", "INFO", "WARN", "TRACE":
+	default:
+		report.add(ValidationLevelError, "logLevel", "must be one of ERROR, DEBUG, INFO, WARN, TRACE")
+	}
+
+	if cfg.LogPath != "" {
+		logPath := cfg.LogPath
+		if !filepath.IsAbs(logPath) {
+			logPath = filepath.Join(cfg.ConfigPath, logPath)
+		}
+
+		if info, err := os.Stat(logPath); err == nil && info.IsDir() {
+			report.add(ValidationLevelError, "logPath", "must be a file, not a directory")
+		} else if err := checkWritableDir(filepath.Dir(logPath)); err != nil {
+			report.add(ValidationLevelError, "logPath", err.Error())
+		}
+	}
+
+	if cfg.LogMaxSize < 1 {
+		report.add(ValidationLevelError, "logMaxSize", "must be greater than 0")
+	}
+
+	if cfg.LogMaxBackups < 0 {
+		report.add(ValidationLevelError, "logMaxBackups", "must not be negative")
+	}
+
+	if !strings.HasPrefix(cfg.BaseURL, "/") || !strings.HasSuffix(cfg.BaseURL, "/") {
+		report.add(ValidationLevelError, "baseUrl", "must start and end with /")
+	}
+
+	if len(cfg.SessionSecret) < minSessionSecretLength {
+		report.add(ValidationLevelError, "sessionSecret", "must be at least 16 characters")
+	}
+
+	if !domain.ValidFilterMatchPolicy(cfg.FilterMatchPolicy) {
+		report.add(ValidationLevelError, "filterMatchPolicy", "must be one of first-match, all-match, per-action-dedupe")
+	}
+
+	if cfg.CustomDefinitions != "" {
+		if info, err := os.Stat(cfg.CustomDefinitions); err != nil || !info.IsDir() {
+			report.add(ValidationLevelWarning, "customDefinitions", "directory does not exist")
+		}
+	}
+
+	switch cfg.DatabaseType {
+	case "sqlite":
+	case "postgres":
+		if cfg.PostgresHost == "" {
+			report.add(ValidationLevelError, "postgresHost", "required when databaseType is postgres")
+		}
+		if cfg.PostgresPort == 0 {
+			report.add(ValidationLevelError, "postgresPort", "required when databaseType is postgres")
+		}
+		if cfg.PostgresDatabase == "" {
+			report.add(ValidationLevelError, "postgresDatabase", "required when databaseType is postgres")
+		}
+	default:
+		report.add(ValidationLevelError, "databaseType", "must be sqlite or postgres")
+	}
+}
+
+// checkWritableDir checks that dir exists and that we can create files in it
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return errors.New("directory %s does not exist", dir)
+	}
+
+	if !info.IsDir() {
+		return errors.New("%s is not a directory", dir)
+	}
+
+	f, err := os.CreateTemp(dir, ".autobrr-validate-*")
+	if err != nil {
+		return errors.New("directory %s is not writable", dir)
+	}
+
+	f.Close()
+	os.Remove(f.Name())
+
+	return nil
+}
+
+// knownKeys returns the lower case toml keys of domain.Config since viper lower cases all keys
+func knownKeys() map[string]string {
+	keys := make(map[string]string)
+
+	t := reflect.TypeOf(domain.Config{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("toml")
+		if tag == "" {
+			continue
+		}
+
+		keys[strings.ToLower(tag)] = tag
+	}
+
+	return keys
+}
+
+// closestKey returns the known key closest to key if it looks like a typo
+func closestKey(key string, known map[string]string) string {
+	best := ""
+	bestDistance := 3
+
+	for lower, name := range known {
+		if d := levenshtein(key, lower); d < bestDistance {
+			best = name
+			bestDistance = d
+		}
+	}
+
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		wantKeys   []string
+		wantErrors bool
+	}{
+		{
+			name: "valid",
+			config: `host = "127.0.0.1"
+port = 7474
+logLevel = "DEBUG"
+sessionSecret = "0123456789abcdef0123456789abcdef"
+`,
+			wantKeys:   nil,
+			wantErrors: false,
+		},
+		{
+			name: "typo_and_bad_values",
+			config: `host = "127.0.0.1"
+port = 99999
+logLevle = "DEBUG"
+baseUrl = "autobrr"
+sessionSecret = "short"
+filterMatchPolicy = "per-action-dedup"
+`,
+			wantKeys:   []string{"loglevle", "port", "baseUrl", "sessionSecret", "filterMatchPolicy"},
+			wantErrors: true,
+		},
+		{
+			name: "postgres_missing_values",
+			config: `host = "127.0.0.1"
+sessionSecret = "0123456789abcdef0123456789abcdef"
+databaseType = "postgres"
+`,
+			wantKeys:   []string{"postgresHost", "postgresPort", "postgresDatabase"},
+			wantErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := Validate(dir)
+			assert.NoError(t, err)
+
+			var keys []string
+			for _, issue := range report.Issues {
+				keys = append(keys, issue.Key)
+			}
+
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.Equal(t, tt.wantErrors, report.HasErrors())
+		})
+	}
+}
+
+func Test_closestKey(t *testing.T) {
+	assert.Equal(t, "logLevel", closestKey("loglevle", knownKeys()))
+	assert.Equal(t, "", closestKey("somethingelse", knownKeys()))
+}
@@ -31,6 +31,15 @@ func NewActionRepo(log logger.Logger, db *DB, clientRepo domain.DownloadClientRe
 }
 
 func (r *ActionRepo) FindByFilterID(ctx context.Context, filterID int) ([]*domain.Action, error) {
+	return r.findWithClients(ctx, sq.Eq{"filter_id": filterID})
+}
+
+// FindByFilterGroupID finds the actions of a filter group, which member filters without actions of their own run
+func (r *ActionRepo) FindByFilterGroupID(ctx context.Context, groupID int) ([]*domain.Action, error) {
+	return r.findWithClients(ctx, sq.Eq{"filter_group_id": groupID})
+}
+
+func (r *ActionRepo) findWithClients(ctx context.Context, where sq.Eq) ([]*domain.Action, error) {
 	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
 	if err != nil {
 		return nil, err
@@ -38,7 +47,7 @@ func (r *ActionRepo) FindByFilterID(ctx context.Context, filterID int) ([]*domai
 
 	defer tx.Rollback()
 
-	actions, err := r.findByFilterID(ctx, tx, filterID)
+	actions, err := r.find(ctx, tx, where)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +68,7 @@ func (r *ActionRepo) FindByFilterID(ctx context.Context, filterID int) ([]*domai
 	return actions, nil
 }
 
-func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) ([]*domain.Action, error) {
+func (r *ActionRepo) find(ctx context.Context, tx *Tx, where sq.Eq) ([]*domain.Action, error) {
 	queryBuilder := r.db.squirrel.
 		Select(
 			"id",
@@ -89,11 +98,15 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"priority",
+			"dupe_key",
 			"external_client_id",
 			"client_id",
+			"filter_id",
+			"filter_group_id",
 		).
 		From("action").
-		Where(sq.Eq{"filter_id": filterID})
+		Where(where)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -111,14 +124,14 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, dupeKey sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
-		var externalClientID, clientID sql.NullInt32
+		var priority, externalClientID, clientID, filterID, filterGroupID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &priority, &dupeKey, &externalClientID, &clientID, &filterID, &filterGroupID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -143,8 +156,13 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
 
+		a.Priority = int(priority.Int32)
+		a.DupeKey = dupeKey.String
+
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ClientID = clientID.Int32
+		a.FilterID = int(filterID.Int32)
+		a.FilterGroupID = int(filterGroupID.Int32)
 
 		actions = append(actions, &a)
 	}
@@ -235,6 +253,8 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"priority",
+			"dupe_key",
 			"external_client_id",
 			"client_id",
 		).
@@ -256,13 +276,13 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, dupeKey sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
-		var externalClientID, clientID sql.NullInt32
+		var priority, externalClientID, clientID, filterID, filterGroupID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &priority, &dupeKey, &externalClientID, &clientID, &filterID, &filterGroupID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -284,6 +304,9 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
 
+		a.Priority = int(priority.Int32)
+		a.DupeKey = dupeKey.String
+
 		a.ExternalDownloadClientID = externalClientID.Int32
 		a.ClientID = clientID.Int32
 
@@ -327,9 +350,12 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"priority",
+			"dupe_key",
 			"external_client_id",
 			"client_id",
 			"filter_id",
+			"filter_group_id",
 		).
 		From("action").
 		Where(sq.Eq{"id": req.Id})
@@ -350,13 +376,13 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 
 	var a domain.Action
 
-	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, dupeKey sql.NullString
 	var limitUl, limitDl, limitSeedTime sql.NullInt64
 	var limitRatio sql.NullFloat64
-	var externalClientID, clientID, filterID sql.NullInt32
+	var priority, externalClientID, clientID, filterID, filterGroupID sql.NullInt32
 	var paused, ignoreRules sql.NullBool
 
-	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &externalClientID, &clientID, &filterID); err != nil {
+	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &priority, &dupeKey, &externalClientID, &clientID, &filterID, &filterGroupID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrRecordNotFound
 		}
@@ -385,9 +411,13 @@ func (r *ActionRepo) Get(ctx context.Context, req *domain.GetActionRequest) (*do
 	a.WebhookMethod = webhookMethod.String
 	a.WebhookData = webhookData.String
 
+	a.Priority = int(priority.Int32)
+	a.DupeKey = dupeKey.String
+
 	a.ExternalDownloadClientID = externalClientID.Int32
 	a.ClientID = clientID.Int32
 	a.FilterID = int(filterID.Int32)
+	a.FilterGroupID = int(filterGroupID.Int32)
 
 	return &a, nil
 }
@@ -460,9 +490,12 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"priority",
+			"dupe_key",
 			"external_client_id",
 			"client_id",
 			"filter_id",
+			"filter_group_id",
 		).
 		Values(
 			action.Name,
@@ -491,9 +524,12 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			toNullString(action.WebhookType),
 			toNullString(action.WebhookMethod),
 			toNullString(action.WebhookData),
+			action.Priority,
+			toNullString(action.DupeKey),
 			toNullInt32(action.ExternalDownloadClientID),
 			toNullInt32(action.ClientID),
 			toNullInt32(int32(action.FilterID)),
+			toNullInt32(int32(action.FilterGroupID)),
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -540,9 +576,12 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("webhook_type", toNullString(action.WebhookType)).
 		Set("webhook_method", toNullString(action.WebhookMethod)).
 		Set("webhook_data", toNullString(action.WebhookData)).
+		Set("priority", action.Priority).
+		Set("dupe_key", toNullString(action.DupeKey)).
 		Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 		Set("client_id", toNullInt32(action.ClientID)).
 		Set("filter_id", toNullInt32(int32(action.FilterID))).
+		Set("filter_group_id", toNullInt32(int32(action.FilterGroupID))).
 		Where(sq.Eq{"id": action.ID})
 
 	query, args, err := queryBuilder.ToSql()
@@ -560,6 +599,16 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 }
 
 func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, actions []*domain.Action) ([]*domain.Action, error) {
+	return r.storeActions(ctx, "filter_id", filterID, actions)
+}
+
+// StoreFilterGroupActions stores the actions of a filter group
+func (r *ActionRepo) StoreFilterGroupActions(ctx context.Context, groupID int64, actions []*domain.Action) ([]*domain.Action, error) {
+	return r.storeActions(ctx, "filter_group_id", groupID, actions)
+}
+
+// storeActions updates or creates the actions owned by the filter or filter group in ownerColumn
+func (r *ActionRepo) storeActions(ctx context.Context, ownerColumn string, ownerID int64, actions []*domain.Action) ([]*domain.Action, error) {
 	tx, err := r.db.handler.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "error begin transaction")
@@ -599,9 +648,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 				Set("webhook_type", toNullString(action.WebhookType)).
 				Set("webhook_method", toNullString(action.WebhookMethod)).
 				Set("webhook_data", toNullString(action.WebhookData)).
+				Set("priority", action.Priority).
+				Set("dupe_key", toNullString(action.DupeKey)).
 				Set("external_client_id", toNullInt32(action.ExternalDownloadClientID)).
 				Set("client_id", toNullInt32(action.ClientID)).
-				Set("filter_id", toNullInt64(filterID)).
+				Set(ownerColumn, toNullInt64(ownerID)).
 				Where(sq.Eq{"id": action.ID})
 
 			query, args, err := queryBuilder.ToSql()
@@ -645,9 +696,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					"webhook_type",
 					"webhook_method",
 					"webhook_data",
+					"priority",
+					"dupe_key",
 					"external_client_id",
 					"client_id",
-					"filter_id",
+					ownerColumn,
 				).
 				Values(
 					action.Name,
@@ -676,9 +729,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 					toNullString(action.WebhookType),
 					toNullString(action.WebhookMethod),
 					toNullString(action.WebhookData),
+					action.Priority,
+					toNullString(action.DupeKey),
 					toNullInt32(action.ExternalDownloadClientID),
 					toNullInt32(action.ClientID),
-					toNullInt64(filterID),
+					toNullInt64(ownerID),
 				).
 				Suffix("RETURNING id").RunWith(tx)
 
@@ -694,7 +749,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, filterID int64, act
 			r.log.Trace().Msgf("action.StoreFilterActions: store %d", action.ID)
 		}
 
-		r.log.Debug().Msgf("action.StoreFilterActions: store '%s' type: '%v' on filter: %d", action.Name, action.Type, filterID)
+		r.log.Debug().Msgf("action.StoreFilterActions: store '%s' type: '%v' on %s: %d", action.Name, action.Type, ownerColumn, ownerID)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+func NewAuthRecoveryRepo(log logger.Logger, db *DB) domain.AuthRecoveryRepo {
+	return &AuthRecoveryRepo{
+		log: log.With().Str("repo", "auth_recovery").Logger(),
+		db:  db,
+	}
+}
+
+type AuthRecoveryRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func (r *AuthRecoveryRepo) Store(ctx context.Context, token domain.AuthRecoveryToken) error {
+	queryBuilder := r.db.squirrel.
+		Insert("auth_recovery_token").
+		Columns("token_hash", "expires_at").
+		Values(token.TokenHash, token.ExpiresAt.UTC().Truncate(time.Second))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Consume marks an unused and unexpired token as used. It returns domain.ErrRecordNotFound for any other token.
+func (r *AuthRecoveryRepo) Consume(ctx context.Context, tokenHash string) error {
+	// times are stored in utc with second precision so they compare the same on sqlite and postgres
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queryBuilder := r.db.squirrel.
+		Update("auth_recovery_token").
+		Set("used_at", now).
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Where(sq.Eq{"used_at": nil}).
+		Where(sq.Gt{"expires_at": now})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteUnused deletes all tokens that have not been used and returns how many were deleted
+func (r *AuthRecoveryRepo) DeleteUnused(ctx context.Context) (int64, error) {
+	queryBuilder := r.db.squirrel.
+		Delete("auth_recovery_token").
+		Where(sq.Eq{"used_at": nil})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows, nil
+}
@@ -82,6 +82,60 @@ func (db *DB) Open() error {
 	return nil
 }
 
+// Connect opens the database connection without running migrations.
+// Use it for tools that inspect the database but must not change it.
+func (db *DB) Connect() error {
+	if db.DSN == "" {
+		return errors.New("DSN required")
+	}
+
+	dsn := db.DSN
+	if db.Driver == "sqlite" {
+		dsn += "?_pragma=busy_timeout%3d1000"
+	}
+
+	var err error
+	if db.handler, err = sql.Open(db.Driver, dsn); err != nil {
+		return errors.Wrap(err, "could not open %s connection", db.Driver)
+	}
+
+	if err := db.handler.PingContext(db.ctx); err != nil {
+		return errors.Wrap(err, "could not ping %s database", db.Driver)
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the current schema version of the database and the latest version this build knows about
+func (db *DB) SchemaVersion(ctx context.Context) (int, int, error) {
+	var version int
+
+	switch db.Driver {
+	case "sqlite":
+		if err := db.handler.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+			return 0, 0, errors.Wrap(err, "failed to query schema version")
+		}
+
+		return version, len(sqliteMigrations), nil
+
+	case "postgres":
+		var table sql.NullString
+		if err := db.handler.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations')::text`).Scan(&table); err != nil {
+			return 0, 0, errors.Wrap(err, "failed to query schema version")
+		}
+
+		if table.Valid {
+			if err := db.handler.QueryRowContext(ctx, `SELECT version FROM schema_migrations`).Scan(&version); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return 0, 0, errors.Wrap(err, "failed to query schema version")
+			}
+		}
+
+		return version, len(postgresMigrations), nil
+	}
+
+	return 0, 0, errors.New("unsupported database: %v", db.Driver)
+}
+
 func (db *DB) Close() error {
 	// cancel background context
 	db.cancel()
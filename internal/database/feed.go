@@ -33,6 +33,8 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 		Select(
 			"f.id",
 			"i.identifier",
+			"f.indexer_id",
+			"f.priority",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -42,7 +44,11 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 			"f.max_age",
 			"f.api_key",
 			"f.cookie",
+			"f.last_run",
 			"f.settings",
+			"f.etag",
+			"f.last_modified",
+			"f.caps",
 			"f.created_at",
 			"f.updated_at",
 		).
@@ -62,14 +68,18 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 
 	var f domain.Feed
 
-	var apiKey, cookie, settings sql.NullString
+	var apiKey, cookie, settings, etag, lastModified, caps sql.NullString
+	var lastRun sql.NullTime
 
-	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer, &f.IndexerID, &f.Priority, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &lastRun, &settings, &etag, &lastModified, &caps, &f.CreatedAt, &f.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	f.LastRun = lastRun.Time
 	f.ApiKey = apiKey.String
 	f.Cookie = cookie.String
+	f.ETag = etag.String
+	f.LastModified = lastModified.String
 
 	if settings.Valid {
 		var settingsJson domain.FeedSettingsJSON
@@ -80,6 +90,10 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 		f.Settings = &settingsJson
 	}
 
+	if f.Caps, err = scanFeedCaps(caps); err != nil {
+		return nil, err
+	}
+
 	return &f, nil
 }
 
@@ -88,6 +102,8 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 		Select(
 			"f.id",
 			"i.identifier",
+			"f.indexer_id",
+			"f.priority",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -98,12 +114,16 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 			"f.api_key",
 			"f.cookie",
 			"f.settings",
+			"f.etag",
+			"f.last_modified",
 			"f.created_at",
 			"f.updated_at",
 		).
 		From("feed f").
 		Join("indexer i ON f.indexer_id = i.id").
-		Where(sq.Eq{"i.name": indexer})
+		Where(sq.Eq{"i.name": indexer}).
+		OrderBy("f.priority DESC", "f.id ASC").
+		Limit(1)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -117,14 +137,16 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 
 	var f domain.Feed
 
-	var apiKey, cookie, settings sql.NullString
+	var apiKey, cookie, settings, etag, lastModified sql.NullString
 
-	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer, &f.IndexerID, &f.Priority, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &settings, &etag, &lastModified, &f.CreatedAt, &f.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
 	f.ApiKey = apiKey.String
 	f.Cookie = cookie.String
+	f.ETag = etag.String
+	f.LastModified = lastModified.String
 
 	var settingsJson domain.FeedSettingsJSON
 	if err = json.Unmarshal([]byte(settings.String), &settingsJson); err != nil {
@@ -141,6 +163,8 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 		Select(
 			"f.id",
 			"i.identifier",
+			"f.indexer_id",
+			"f.priority",
 			"f.name",
 			"f.type",
 			"f.enabled",
@@ -153,12 +177,15 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 			"f.last_run",
 			"f.last_run_data",
 			"f.settings",
+			"f.etag",
+			"f.last_modified",
+			"f.caps",
 			"f.created_at",
 			"f.updated_at",
 		).
 		From("feed f").
 		Join("indexer i ON f.indexer_id = i.id").
-		OrderBy("f.name ASC")
+		OrderBy("f.name ASC", "f.priority DESC")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -176,10 +203,10 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 	for rows.Next() {
 		var f domain.Feed
 
-		var apiKey, cookie, lastRunData, settings sql.NullString
+		var apiKey, cookie, lastRunData, settings, etag, lastModified, caps sql.NullString
 		var lastRun sql.NullTime
 
-		if err := rows.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &lastRun, &lastRunData, &settings, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if err := rows.Scan(&f.ID, &f.Indexer, &f.IndexerID, &f.Priority, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Timeout, &f.MaxAge, &apiKey, &cookie, &lastRun, &lastRunData, &settings, &etag, &lastModified, &caps, &f.CreatedAt, &f.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -187,6 +214,8 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 		f.LastRunData = lastRunData.String
 		f.ApiKey = apiKey.String
 		f.Cookie = cookie.String
+		f.ETag = etag.String
+		f.LastModified = lastModified.String
 
 		f.Settings = &domain.FeedSettingsJSON{
 			DownloadType: domain.FeedDownloadTypeTorrent,
@@ -201,6 +230,10 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 			f.Settings = &settingsJson
 		}
 
+		if f.Caps, err = scanFeedCaps(caps); err != nil {
+			return nil, err
+		}
+
 		feeds = append(feeds, f)
 	}
 
@@ -250,7 +283,9 @@ func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
 			"interval",
 			"timeout",
 			"api_key",
+			"cookie",
 			"indexer_id",
+			"priority",
 			"settings",
 		).
 		Values(
@@ -261,7 +296,9 @@ func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
 			feed.Interval,
 			feed.Timeout,
 			feed.ApiKey,
+			feed.Cookie,
 			feed.IndexerID,
+			feed.Priority,
 			settings,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
@@ -294,6 +331,7 @@ func (r *FeedRepo) Update(ctx context.Context, feed *domain.Feed) error {
 		Set("max_age", feed.MaxAge).
 		Set("api_key", feed.ApiKey).
 		Set("cookie", feed.Cookie).
+		Set("priority", feed.Priority).
 		Set("settings", settings).
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{"id": feed.ID})
@@ -350,6 +388,89 @@ func (r *FeedRepo) UpdateLastRunWithData(ctx context.Context, feedID int, data s
 	return nil
 }
 
+// UpdateValidators stores the ETag and Last-Modified of the last response, sent with the next request of the feed
+func (r *FeedRepo) UpdateValidators(ctx context.Context, feedID int, etag string, lastModified string) error {
+	queryBuilder := r.db.squirrel.
+		Update("feed").
+		Set("etag", etag).
+		Set("last_modified", lastModified).
+		Where(sq.Eq{"id": feedID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	_, err = r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FeedRepo) UpdateCookie(ctx context.Context, feedID int, cookie string) error {
+	queryBuilder := r.db.squirrel.
+		Update("feed").
+		Set("cookie", cookie).
+		Where(sq.Eq{"id": feedID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	_, err = r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FeedRepo) UpdateApiKey(ctx context.Context, feedID int, apiKey string) error {
+	queryBuilder := r.db.squirrel.
+		Update("feed").
+		Set("api_key", apiKey).
+		Where(sq.Eq{"id": feedID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	_, err = r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FeedRepo) UpdateCaps(ctx context.Context, feedID int, caps *domain.FeedCaps) error {
+	data, err := json.Marshal(caps)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling feed caps json data")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Update("feed").
+		Set("caps", string(data)).
+		Where(sq.Eq{"id": feedID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	_, err = r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
 func (r *FeedRepo) ToggleEnabled(ctx context.Context, id int, enabled bool) error {
 	var err error
 
@@ -390,3 +511,17 @@ func (r *FeedRepo) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+// scanFeedCaps unmarshals the caps column, nil when the caps were never fetched
+func scanFeedCaps(caps sql.NullString) (*domain.FeedCaps, error) {
+	if !caps.Valid || caps.String == "" {
+		return nil, nil
+	}
+
+	var c domain.FeedCaps
+	if err := json.Unmarshal([]byte(caps.String), &c); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal caps")
+	}
+
+	return &c, nil
+}
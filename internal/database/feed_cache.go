@@ -6,6 +6,8 @@ package database
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -63,16 +65,22 @@ func (r *FeedCacheRepo) Get(feedId int, key string) ([]byte, error) {
 	return value, nil
 }
 
-func (r *FeedCacheRepo) GetByFeed(ctx context.Context, feedId int) ([]domain.FeedCacheItem, error) {
+func (r *FeedCacheRepo) GetByFeed(ctx context.Context, feedId int, limit int) ([]domain.FeedCacheItem, error) {
 	queryBuilder := r.db.squirrel.
 		Select(
 			"feed_id",
 			"key",
 			"value",
 			"ttl",
+			"created",
 		).
 		From("feed_cache").
-		Where(sq.Eq{"feed_id": feedId})
+		Where(sq.Eq{"feed_id": feedId}).
+		OrderBy("created DESC")
+
+	if limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(limit))
+	}
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -86,15 +94,20 @@ func (r *FeedCacheRepo) GetByFeed(ctx context.Context, feedId int) ([]domain.Fee
 
 	defer rows.Close()
 
-	var data []domain.FeedCacheItem
+	data := make([]domain.FeedCacheItem, 0)
 
 	for rows.Next() {
 		var d domain.FeedCacheItem
+		var value sql.NullString
+		var created sql.NullTime
 
-		if err := rows.Scan(&d.FeedId, &d.Key, &d.Value, &d.TTL); err != nil {
+		if err := rows.Scan(&d.FeedId, &d.Key, &value, &d.TTL, &created); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		d.Value = value.String
+		d.Created = created.Time
+
 		data = append(data, d)
 	}
 
@@ -160,8 +173,8 @@ func (r *FeedCacheRepo) Exists(feedId int, key string) (bool, error) {
 func (r *FeedCacheRepo) Put(feedId int, key string, val []byte, ttl time.Time) error {
 	queryBuilder := r.db.squirrel.
 		Insert("feed_cache").
-		Columns("feed_id", "key", "value", "ttl").
-		Values(feedId, key, val, ttl)
+		Columns("feed_id", "key", "value", "ttl", "created").
+		Values(feedId, key, val, ttl, time.Now())
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -245,3 +258,100 @@ func (r *FeedCacheRepo) DeleteStale(ctx context.Context) error {
 
 	return nil
 }
+
+func (r *FeedCacheRepo) GetStatsByFeed(ctx context.Context, feedId int) (*domain.FeedCacheStats, error) {
+	queryBuilder := r.db.squirrel.
+		Select("COUNT(*)", "MIN(created)", "MAX(created)").
+		From("feed_cache").
+		Where(sq.Eq{"feed_id": feedId})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	stats := domain.FeedCacheStats{FeedID: feedId}
+
+	// sqlite returns the aggregated timestamps as text
+	var oldest, newest sql.NullString
+
+	if err := r.db.handler.QueryRowContext(ctx, query, args...).Scan(&stats.Count, &oldest, &newest); err != nil {
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	stats.Oldest = parseCacheTime(oldest.String)
+	stats.Newest = parseCacheTime(newest.String)
+
+	return &stats, nil
+}
+
+// parseCacheTime parses the aggregated timestamps of the feed cache, zero when it can not be parsed.
+// Sqlite stores them like time.Time.String, postgres returns them as time.Time which is scanned as RFC3339.
+func parseCacheTime(value string) time.Time {
+	// drop the monotonic clock reading
+	value, _, _ = strings.Cut(value, " m=")
+
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999 -0700 MST", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// DeleteOlderThan deletes the cache items created more than days ago
+func (r *FeedCacheRepo) DeleteOlderThan(ctx context.Context, days int) (int64, error) {
+	queryBuilder := r.db.squirrel.Delete("feed_cache")
+
+	if r.db.Driver == "sqlite" {
+		queryBuilder = queryBuilder.Where(sq.Expr("created < datetime('now', 'localtime', ?)", fmt.Sprintf("-%d days", days)))
+	} else {
+		queryBuilder = queryBuilder.Where(sq.Lt{"created": time.Now().AddDate(0, 0, -days)})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error exec result")
+	}
+
+	r.log.Debug().Msgf("deleted %d rows older than %d days from feed cache", rows, days)
+
+	return rows, nil
+}
+
+// DeleteOverLimit deletes the oldest cache items of the feeds with more than maxEntries items
+func (r *FeedCacheRepo) DeleteOverLimit(ctx context.Context, maxEntries int) (int64, error) {
+	query := `DELETE FROM feed_cache
+WHERE (feed_id, key) IN (
+	SELECT feed_id, key FROM (
+		SELECT feed_id, key, ROW_NUMBER() OVER (PARTITION BY feed_id ORDER BY created DESC) AS row_num
+		FROM feed_cache
+	) ranked
+	WHERE row_num > $1
+)`
+
+	result, err := r.db.handler.ExecContext(ctx, query, maxEntries)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error exec result")
+	}
+
+	r.log.Debug().Msgf("deleted %d rows over the limit of %d per feed from feed cache", rows, maxEntries)
+
+	return rows, nil
+}
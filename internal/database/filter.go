@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -63,8 +64,10 @@ func (r *FilterRepo) find(ctx context.Context, tx *Tx, params domain.FilterQuery
 			"f.enabled",
 			"f.name",
 			"f.priority",
+			"f.group_id",
 			"f.created_at",
 			"f.updated_at",
+			"f.filter_tags",
 		).
 		Distinct().
 		Column(sq.Alias(actionCountQuery, "action_count")).
@@ -108,11 +111,18 @@ func (r *FilterRepo) find(ctx context.Context, tx *Tx, params domain.FilterQuery
 	var filters []domain.Filter
 	for rows.Next() {
 		var f domain.Filter
+		var groupID sql.NullInt32
 
-		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &f.CreatedAt, &f.UpdatedAt, &f.ActionsCount); err != nil {
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &groupID, &f.CreatedAt, &f.UpdatedAt, pq.Array(&f.FilterTags), &f.ActionsCount); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		f.GroupID = int(groupID.Int32)
+
+		if len(params.Filters.Tags) > 0 && !f.HasFilterTag(params.Filters.Tags...) {
+			continue
+		}
+
 		filters = append(filters, f)
 	}
 	if err := rows.Err(); err != nil {
@@ -134,8 +144,10 @@ func (r *FilterRepo) ListFilters(ctx context.Context) ([]domain.Filter, error) {
 			"f.enabled",
 			"f.name",
 			"f.priority",
+			"f.group_id",
 			"f.created_at",
 			"f.updated_at",
+			"f.filter_tags",
 		).
 		Column(sq.Alias(actionCountQuery, "action_count")).
 		From("filter f").
@@ -156,11 +168,14 @@ func (r *FilterRepo) ListFilters(ctx context.Context) ([]domain.Filter, error) {
 	var filters []domain.Filter
 	for rows.Next() {
 		var f domain.Filter
+		var groupID sql.NullInt32
 
-		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &f.CreatedAt, &f.UpdatedAt, &f.ActionsCount); err != nil {
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Priority, &groupID, &f.CreatedAt, &f.UpdatedAt, pq.Array(&f.FilterTags), &f.ActionsCount); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
+		f.GroupID = int(groupID.Int32)
+
 		filters = append(filters, f)
 	}
 
@@ -232,6 +247,56 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"f.except_tags_match_logic",
 			"f.origins",
 			"f.except_origins",
+			"f.expression",
+			"f.schedule",
+			"f.use_regex_shows",
+			"f.use_regex_release_groups",
+			"f.use_regex_tags",
+			"f.use_regex_uploaders",
+			"f.prefer_season_packs",
+			"f.season_pack_hold",
+			"f.proper_upgrades",
+			"f.proper_upgrades_replace",
+			"f.arr_upgrade_only",
+			"f.freeleech_tokens",
+			"f.min_seeders",
+			"f.min_leechers",
+			"f.on_reject",
+			"f.on_reject_filter_id",
+			"f.on_action_failure",
+			"f.on_action_failure_filter_id",
+			"f.delay_jitter",
+			"f.max_downloads_interval",
+			"f.max_downloads_per_indexer",
+			"f.hdr_preference",
+			"f.language_preference",
+			"f.subtitle_preference",
+			"f.except_language_only",
+			"f.rewrite_rules",
+			"f.reject_dv_without_fallback",
+			"f.bit_depths",
+			"f.sample_rates",
+			"f.audio_channels",
+			"f.absolute_episodes",
+			"f.air_dates",
+			"f.max_air_date_age",
+			"f.anime_batch",
+			"f.anime_sub_type",
+			"f.anime_group_tiers",
+			"f.anime_max_group_tier",
+			"f.preset_id",
+			"f.preset_source",
+			"f.preset_managed",
+			"f.preset_hash",
+			"f.filter_tags",
+			"f.verify_torrent",
+			"f.min_file_count",
+			"f.max_file_count",
+			"f.match_file_extensions",
+			"f.except_file_extensions",
+			"f.flags",
+			"f.except_flags",
+			"f.group_id",
 			"f.created_at",
 			"f.updated_at",
 			"fe.id as external_id",
@@ -242,11 +307,19 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"fe.exec_cmd",
 			"fe.exec_args",
 			"fe.exec_expect_status",
+			"fe.exec_mode",
+			"fe.exec_timeout",
+			"fe.exec_env",
 			"fe.webhook_host",
 			"fe.webhook_method",
 			"fe.webhook_data",
 			"fe.webhook_headers",
 			"fe.webhook_expect_status",
+			"fe.webhook_retry_status",
+			"fe.webhook_retry_attempts",
+			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_expect_json_path",
+			"fe.webhook_expect_json_value",
 		).
 		From("filter f").
 		LeftJoin("filter_external fe ON f.id = fe.filter_id").
@@ -265,19 +338,21 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 		return nil, errors.Wrap(err, "error executing query")
 	}
 
+	defer rows.Close()
+
 	var f domain.Filter
 
 	externalMap := make(map[int]domain.FilterExternal)
 
 	for rows.Next() {
 		// filter
-		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic sql.NullString
+		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic, expression, schedule, onReject, onActionFailure, absoluteEpisodes, airDates, animeBatch, animeSubType, animeGroupTiers, presetID, presetSource, presetHash, matchFileExtensions, exceptFileExtensions, rewriteRules, flags, exceptFlags sql.NullString
 		var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac sql.NullBool
-		var delay, maxDownloads, logScore sql.NullInt32
+		var delay, maxDownloads, logScore, groupID sql.NullInt32
 
 		// filter external
-		var extName, extType, extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData sql.NullString
-		var extId, extIndex, extWebhookStatus, extExecStatus sql.NullInt32
+		var extName, extType, extExecCmd, extExecArgs, extExecMode, extExecEnv, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus, extWebhookJSONPath, extWebhookJSONValue sql.NullString
+		var extId, extIndex, extWebhookStatus, extExecStatus, extExecTimeout, extWebhookRetryAttempts, extWebhookRetryDelay sql.NullInt32
 		var extEnabled sql.NullBool
 
 		if err := rows.Scan(
@@ -339,6 +414,56 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			&exceptTagsMatchLogic,
 			pq.Array(&f.Origins),
 			pq.Array(&f.ExceptOrigins),
+			&expression,
+			&schedule,
+			&f.UseRegexShows,
+			&f.UseRegexReleaseGroups,
+			&f.UseRegexTags,
+			&f.UseRegexUploaders,
+			&f.PreferSeasonPacks,
+			&f.SeasonPackHold,
+			&f.ProperUpgrades,
+			&f.ProperUpgradesReplace,
+			&f.ArrUpgradeOnly,
+			&f.FreeleechTokens,
+			&f.MinSeeders,
+			&f.MinLeechers,
+			&onReject,
+			&f.OnRejectFilterID,
+			&onActionFailure,
+			&f.OnActionFailureFilterID,
+			&f.DelayJitter,
+			&f.MaxDownloadsInterval,
+			&f.MaxDownloadsPerIndexer,
+			pq.Array(&f.HDRPreference),
+			pq.Array(&f.LanguagePreference),
+			pq.Array(&f.SubtitlePreference),
+			pq.Array(&f.ExceptLanguageOnly),
+			&rewriteRules,
+			&f.RejectDVWithoutFallback,
+			pq.Array(&f.BitDepths),
+			pq.Array(&f.SampleRates),
+			pq.Array(&f.AudioChannels),
+			&absoluteEpisodes,
+			&airDates,
+			&f.MaxAirDateAge,
+			&animeBatch,
+			&animeSubType,
+			&animeGroupTiers,
+			&f.AnimeMaxGroupTier,
+			&presetID,
+			&presetSource,
+			&f.PresetManaged,
+			&presetHash,
+			pq.Array(&f.FilterTags),
+			&f.VerifyTorrent,
+			&f.MinFileCount,
+			&f.MaxFileCount,
+			&matchFileExtensions,
+			&exceptFileExtensions,
+			&flags,
+			&exceptFlags,
+			&groupID,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 			&extId,
@@ -349,11 +474,19 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			&extExecCmd,
 			&extExecArgs,
 			&extExecStatus,
+			&extExecMode,
+			&extExecTimeout,
+			&extExecEnv,
 			&extWebhookHost,
 			&extWebhookMethod,
 			&extWebhookData,
 			&extWebhookHeaders,
 			&extWebhookStatus,
+			&extWebhookRetryStatus,
+			&extWebhookRetryAttempts,
+			&extWebhookRetryDelay,
+			&extWebhookJSONPath,
+			&extWebhookJSONValue,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
@@ -386,34 +519,71 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 		f.ExceptCategories = exceptCategories.String
 		f.MatchUploaders = matchUploaders.String
 		f.ExceptUploaders = exceptUploaders.String
+		f.Flags = flags.String
+		f.ExceptFlags = exceptFlags.String
 		f.Tags = tags.String
 		f.ExceptTags = exceptTags.String
 		f.TagsMatchLogic = tagsMatchLogic.String
 		f.ExceptTagsMatchLogic = exceptTagsMatchLogic.String
+		f.Expression = expression.String
+		f.Schedule = schedule.String
+		f.OnReject = domain.FilterChainMode(onReject.String)
+		f.OnActionFailure = domain.FilterChainMode(onActionFailure.String)
+		f.AbsoluteEpisodes = absoluteEpisodes.String
+		f.AirDates = airDates.String
+		f.AnimeBatch = domain.FilterAnimeBatch(animeBatch.String)
+		f.AnimeSubType = domain.AnimeSubType(animeSubType.String)
+		f.AnimeGroupTiers = animeGroupTiers.String
+		f.PresetID = presetID.String
+		f.PresetSource = presetSource.String
+		f.PresetHash = presetHash.String
+		f.MatchFileExtensions = matchFileExtensions.String
+		f.ExceptFileExtensions = exceptFileExtensions.String
+
+		if f.RewriteRules, err = unmarshalRewriteRules(rewriteRules); err != nil {
+			return nil, err
+		}
+		f.GroupID = int(groupID.Int32)
 		f.UseRegex = useRegex.Bool
 		f.Scene = scene.Bool
 		f.Freeleech = freeleech.Bool
 
 		if extId.Valid {
 			external := domain.FilterExternal{
-				ID:                  int(extId.Int32),
-				Name:                extName.String,
-				Index:               int(extIndex.Int32),
-				Type:                domain.FilterExternalType(extType.String),
-				Enabled:             extEnabled.Bool,
-				ExecCmd:             extExecCmd.String,
-				ExecArgs:            extExecArgs.String,
-				ExecExpectStatus:    int(extExecStatus.Int32),
-				WebhookHost:         extWebhookHost.String,
-				WebhookMethod:       extWebhookMethod.String,
-				WebhookData:         extWebhookData.String,
-				WebhookHeaders:      extWebhookHeaders.String,
-				WebhookExpectStatus: int(extWebhookStatus.Int32),
+				ID:                   int(extId.Int32),
+				Name:                 extName.String,
+				Index:                int(extIndex.Int32),
+				Type:                 domain.FilterExternalType(extType.String),
+				Enabled:              extEnabled.Bool,
+				ExecCmd:              extExecCmd.String,
+				ExecArgs:             extExecArgs.String,
+				ExecExpectStatus:     int(extExecStatus.Int32),
+				ExecMode:             domain.FilterExternalExecMode(extExecMode.String),
+				ExecTimeout:          int(extExecTimeout.Int32),
+				ExecEnv:              extExecEnv.String,
+				WebhookHost:          extWebhookHost.String,
+				WebhookMethod:        extWebhookMethod.String,
+				WebhookData:          extWebhookData.String,
+				WebhookHeaders:       extWebhookHeaders.String,
+				WebhookExpectStatus:  int(extWebhookStatus.Int32),
+				WebhookRetryStatus:   extWebhookRetryStatus.String,
+				WebhookRetryAttempts: int(extWebhookRetryAttempts.Int32),
+				WebhookRetryDelay:    int(extWebhookRetryDelay.Int32),
+				WebhookJSONPath:      extWebhookJSONPath.String,
+				WebhookJSONValue:     extWebhookJSONValue.String,
 			}
 			externalMap[external.ID] = external
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	if f.ID == 0 {
+		return nil, domain.ErrRecordNotFound
+	}
+
 	for _, external := range externalMap {
 		f.External = append(f.External, external)
 	}
@@ -487,6 +657,56 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"f.except_tags_match_logic",
 			"f.origins",
 			"f.except_origins",
+			"f.expression",
+			"f.schedule",
+			"f.use_regex_shows",
+			"f.use_regex_release_groups",
+			"f.use_regex_tags",
+			"f.use_regex_uploaders",
+			"f.prefer_season_packs",
+			"f.season_pack_hold",
+			"f.proper_upgrades",
+			"f.proper_upgrades_replace",
+			"f.arr_upgrade_only",
+			"f.freeleech_tokens",
+			"f.min_seeders",
+			"f.min_leechers",
+			"f.on_reject",
+			"f.on_reject_filter_id",
+			"f.on_action_failure",
+			"f.on_action_failure_filter_id",
+			"f.delay_jitter",
+			"f.max_downloads_interval",
+			"f.max_downloads_per_indexer",
+			"f.hdr_preference",
+			"f.language_preference",
+			"f.subtitle_preference",
+			"f.except_language_only",
+			"f.rewrite_rules",
+			"f.reject_dv_without_fallback",
+			"f.bit_depths",
+			"f.sample_rates",
+			"f.audio_channels",
+			"f.absolute_episodes",
+			"f.air_dates",
+			"f.max_air_date_age",
+			"f.anime_batch",
+			"f.anime_sub_type",
+			"f.anime_group_tiers",
+			"f.anime_max_group_tier",
+			"f.preset_id",
+			"f.preset_source",
+			"f.preset_managed",
+			"f.preset_hash",
+			"f.filter_tags",
+			"f.verify_torrent",
+			"f.min_file_count",
+			"f.max_file_count",
+			"f.match_file_extensions",
+			"f.except_file_extensions",
+			"f.flags",
+			"f.except_flags",
+			"f.group_id",
 			"f.created_at",
 			"f.updated_at",
 			"fe.id as external_id",
@@ -497,16 +717,26 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			"fe.exec_cmd",
 			"fe.exec_args",
 			"fe.exec_expect_status",
+			"fe.exec_mode",
+			"fe.exec_timeout",
+			"fe.exec_env",
 			"fe.webhook_host",
 			"fe.webhook_method",
 			"fe.webhook_data",
 			"fe.webhook_headers",
 			"fe.webhook_expect_status",
+			"fe.webhook_retry_status",
+			"fe.webhook_retry_attempts",
+			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_expect_json_path",
+			"fe.webhook_expect_json_value",
 			"fe.filter_id",
 		).
 		From("filter f").
-		Join("filter_indexer fi ON f.id = fi.filter_id").
-		Join("indexer i ON i.id = fi.indexer_id").
+		// filters without indexers of their own use the indexers of their group
+		Join(`indexer i ON i.id IN (SELECT fi.indexer_id FROM filter_indexer fi WHERE fi.filter_id = f.id)
+			OR (NOT EXISTS (SELECT 1 FROM filter_indexer fi WHERE fi.filter_id = f.id)
+				AND i.id IN (SELECT gi.indexer_id FROM filter_group_indexer gi WHERE gi.filter_group_id = f.group_id))`).
 		LeftJoin("filter_external fe ON f.id = fe.filter_id").
 		Where(sq.Eq{"i.identifier": indexer}).
 		Where(sq.Eq{"i.enabled": true}).
@@ -532,13 +762,13 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 	for rows.Next() {
 		var f domain.Filter
 
-		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic sql.NullString
+		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, matchReleaseTags, exceptReleaseTags, matchDescription, exceptDescription, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, tagsMatchLogic, exceptTagsMatchLogic, expression, schedule, onReject, onActionFailure, absoluteEpisodes, airDates, animeBatch, animeSubType, animeGroupTiers, presetID, presetSource, presetHash, matchFileExtensions, exceptFileExtensions, rewriteRules, flags, exceptFlags sql.NullString
 		var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac sql.NullBool
-		var delay, maxDownloads, logScore sql.NullInt32
+		var delay, maxDownloads, logScore, groupID sql.NullInt32
 
 		// filter external
-		var extName, extType, extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData sql.NullString
-		var extId, extIndex, extWebhookStatus, extExecStatus, extFilterId sql.NullInt32
+		var extName, extType, extExecCmd, extExecArgs, extExecMode, extExecEnv, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus, extWebhookJSONPath, extWebhookJSONValue sql.NullString
+		var extId, extIndex, extWebhookStatus, extExecStatus, extExecTimeout, extWebhookRetryAttempts, extWebhookRetryDelay, extFilterId sql.NullInt32
 		var extEnabled sql.NullBool
 
 		if err := rows.Scan(
@@ -600,6 +830,56 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&exceptTagsMatchLogic,
 			pq.Array(&f.Origins),
 			pq.Array(&f.ExceptOrigins),
+			&expression,
+			&schedule,
+			&f.UseRegexShows,
+			&f.UseRegexReleaseGroups,
+			&f.UseRegexTags,
+			&f.UseRegexUploaders,
+			&f.PreferSeasonPacks,
+			&f.SeasonPackHold,
+			&f.ProperUpgrades,
+			&f.ProperUpgradesReplace,
+			&f.ArrUpgradeOnly,
+			&f.FreeleechTokens,
+			&f.MinSeeders,
+			&f.MinLeechers,
+			&onReject,
+			&f.OnRejectFilterID,
+			&onActionFailure,
+			&f.OnActionFailureFilterID,
+			&f.DelayJitter,
+			&f.MaxDownloadsInterval,
+			&f.MaxDownloadsPerIndexer,
+			pq.Array(&f.HDRPreference),
+			pq.Array(&f.LanguagePreference),
+			pq.Array(&f.SubtitlePreference),
+			pq.Array(&f.ExceptLanguageOnly),
+			&rewriteRules,
+			&f.RejectDVWithoutFallback,
+			pq.Array(&f.BitDepths),
+			pq.Array(&f.SampleRates),
+			pq.Array(&f.AudioChannels),
+			&absoluteEpisodes,
+			&airDates,
+			&f.MaxAirDateAge,
+			&animeBatch,
+			&animeSubType,
+			&animeGroupTiers,
+			&f.AnimeMaxGroupTier,
+			&presetID,
+			&presetSource,
+			&f.PresetManaged,
+			&presetHash,
+			pq.Array(&f.FilterTags),
+			&f.VerifyTorrent,
+			&f.MinFileCount,
+			&f.MaxFileCount,
+			&matchFileExtensions,
+			&exceptFileExtensions,
+			&flags,
+			&exceptFlags,
+			&groupID,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 			&extId,
@@ -610,11 +890,19 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 			&extExecCmd,
 			&extExecArgs,
 			&extExecStatus,
+			&extExecMode,
+			&extExecTimeout,
+			&extExecEnv,
 			&extWebhookHost,
 			&extWebhookMethod,
 			&extWebhookData,
 			&extWebhookHeaders,
 			&extWebhookStatus,
+			&extWebhookRetryStatus,
+			&extWebhookRetryAttempts,
+			&extWebhookRetryDelay,
+			&extWebhookJSONPath,
+			&extWebhookJSONValue,
 			&extFilterId,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
@@ -648,30 +936,59 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, indexer string
 		f.ExceptCategories = exceptCategories.String
 		f.MatchUploaders = matchUploaders.String
 		f.ExceptUploaders = exceptUploaders.String
+		f.Flags = flags.String
+		f.ExceptFlags = exceptFlags.String
 		f.Tags = tags.String
 		f.ExceptTags = exceptTags.String
 		f.TagsMatchLogic = tagsMatchLogic.String
 		f.ExceptTagsMatchLogic = exceptTagsMatchLogic.String
+		f.Expression = expression.String
+		f.Schedule = schedule.String
+		f.OnReject = domain.FilterChainMode(onReject.String)
+		f.OnActionFailure = domain.FilterChainMode(onActionFailure.String)
+		f.AbsoluteEpisodes = absoluteEpisodes.String
+		f.AirDates = airDates.String
+		f.AnimeBatch = domain.FilterAnimeBatch(animeBatch.String)
+		f.AnimeSubType = domain.AnimeSubType(animeSubType.String)
+		f.AnimeGroupTiers = animeGroupTiers.String
+		f.PresetID = presetID.String
+		f.PresetSource = presetSource.String
+		f.PresetHash = presetHash.String
+		f.MatchFileExtensions = matchFileExtensions.String
+		f.ExceptFileExtensions = exceptFileExtensions.String
+
+		if f.RewriteRules, err = unmarshalRewriteRules(rewriteRules); err != nil {
+			return nil, err
+		}
+		f.GroupID = int(groupID.Int32)
 		f.UseRegex = useRegex.Bool
 		f.Scene = scene.Bool
 		f.Freeleech = freeleech.Bool
 
 		if extId.Valid {
 			external := domain.FilterExternal{
-				ID:                  int(extId.Int32),
-				Name:                extName.String,
-				Index:               int(extIndex.Int32),
-				Type:                domain.FilterExternalType(extType.String),
-				Enabled:             extEnabled.Bool,
-				ExecCmd:             extExecCmd.String,
-				ExecArgs:            extExecArgs.String,
-				ExecExpectStatus:    int(extExecStatus.Int32),
-				WebhookHost:         extWebhookHost.String,
-				WebhookMethod:       extWebhookMethod.String,
-				WebhookData:         extWebhookData.String,
-				WebhookHeaders:      extWebhookHeaders.String,
-				WebhookExpectStatus: int(extWebhookStatus.Int32),
-				FilterId:            int(extFilterId.Int32),
+				ID:                   int(extId.Int32),
+				Name:                 extName.String,
+				Index:                int(extIndex.Int32),
+				Type:                 domain.FilterExternalType(extType.String),
+				Enabled:              extEnabled.Bool,
+				ExecCmd:              extExecCmd.String,
+				ExecArgs:             extExecArgs.String,
+				ExecExpectStatus:     int(extExecStatus.Int32),
+				ExecMode:             domain.FilterExternalExecMode(extExecMode.String),
+				ExecTimeout:          int(extExecTimeout.Int32),
+				ExecEnv:              extExecEnv.String,
+				WebhookHost:          extWebhookHost.String,
+				WebhookMethod:        extWebhookMethod.String,
+				WebhookData:          extWebhookData.String,
+				WebhookHeaders:       extWebhookHeaders.String,
+				WebhookExpectStatus:  int(extWebhookStatus.Int32),
+				WebhookRetryStatus:   extWebhookRetryStatus.String,
+				WebhookRetryAttempts: int(extWebhookRetryAttempts.Int32),
+				WebhookRetryDelay:    int(extWebhookRetryDelay.Int32),
+				WebhookJSONPath:      extWebhookJSONPath.String,
+				WebhookJSONValue:     extWebhookJSONValue.String,
+				FilterId:             int(extFilterId.Int32),
 			}
 			externalMap[external.FilterId] = append(externalMap[external.FilterId], external)
 		}
@@ -704,11 +1021,19 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			"fe.exec_cmd",
 			"fe.exec_args",
 			"fe.exec_expect_status",
+			"fe.exec_mode",
+			"fe.exec_timeout",
+			"fe.exec_env",
 			"fe.webhook_host",
 			"fe.webhook_method",
 			"fe.webhook_data",
 			"fe.webhook_headers",
 			"fe.webhook_expect_status",
+			"fe.webhook_retry_status",
+			"fe.webhook_retry_attempts",
+			"fe.webhook_retry_delay_seconds",
+			"fe.webhook_expect_json_path",
+			"fe.webhook_expect_json_value",
 		).
 		From("filter_external fe").
 		Where(sq.Eq{"fe.filter_id": filterId})
@@ -732,8 +1057,8 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 		var external domain.FilterExternal
 
 		// filter external
-		var extExecCmd, extExecArgs, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData sql.NullString
-		var extWebhookStatus, extExecStatus sql.NullInt32
+		var extExecCmd, extExecArgs, extExecMode, extExecEnv, extWebhookHost, extWebhookMethod, extWebhookHeaders, extWebhookData, extWebhookRetryStatus, extWebhookJSONPath, extWebhookJSONValue sql.NullString
+		var extWebhookStatus, extExecStatus, extExecTimeout, extWebhookRetryAttempts, extWebhookRetryDelay sql.NullInt32
 
 		if err := rows.Scan(
 			&external.ID,
@@ -744,11 +1069,19 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 			&extExecCmd,
 			&extExecArgs,
 			&extExecStatus,
+			&extExecMode,
+			&extExecTimeout,
+			&extExecEnv,
 			&extWebhookHost,
 			&extWebhookMethod,
 			&extWebhookData,
 			&extWebhookHeaders,
 			&extWebhookStatus,
+			&extWebhookRetryStatus,
+			&extWebhookRetryAttempts,
+			&extWebhookRetryDelay,
+			&extWebhookJSONPath,
+			&extWebhookJSONValue,
 		); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
@@ -756,12 +1089,20 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 		external.ExecCmd = extExecCmd.String
 		external.ExecArgs = extExecArgs.String
 		external.ExecExpectStatus = int(extExecStatus.Int32)
+		external.ExecMode = domain.FilterExternalExecMode(extExecMode.String)
+		external.ExecTimeout = int(extExecTimeout.Int32)
+		external.ExecEnv = extExecEnv.String
 
 		external.WebhookHost = extWebhookHost.String
 		external.WebhookMethod = extWebhookMethod.String
 		external.WebhookData = extWebhookData.String
 		external.WebhookHeaders = extWebhookHeaders.String
 		external.WebhookExpectStatus = int(extWebhookStatus.Int32)
+		external.WebhookRetryStatus = extWebhookRetryStatus.String
+		external.WebhookRetryAttempts = int(extWebhookRetryAttempts.Int32)
+		external.WebhookRetryDelay = int(extWebhookRetryDelay.Int32)
+		external.WebhookJSONPath = extWebhookJSONPath.String
+		external.WebhookJSONValue = extWebhookJSONValue.String
 
 		externalFilters = append(externalFilters, external)
 	}
@@ -770,6 +1111,11 @@ func (r *FilterRepo) FindExternalFiltersByID(ctx context.Context, filterId int)
 }
 
 func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
+	rewriteRules, err := marshalRewriteRules(filter.RewriteRules)
+	if err != nil {
+		return err
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("filter").
 		Columns(
@@ -830,6 +1176,56 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			"perfect_flac",
 			"origins",
 			"except_origins",
+			"expression",
+			"schedule",
+			"use_regex_shows",
+			"use_regex_release_groups",
+			"use_regex_tags",
+			"use_regex_uploaders",
+			"prefer_season_packs",
+			"season_pack_hold",
+			"proper_upgrades",
+			"proper_upgrades_replace",
+			"arr_upgrade_only",
+			"freeleech_tokens",
+			"min_seeders",
+			"min_leechers",
+			"on_reject",
+			"on_reject_filter_id",
+			"on_action_failure",
+			"on_action_failure_filter_id",
+			"delay_jitter",
+			"max_downloads_interval",
+			"max_downloads_per_indexer",
+			"hdr_preference",
+			"language_preference",
+			"subtitle_preference",
+			"except_language_only",
+			"rewrite_rules",
+			"reject_dv_without_fallback",
+			"bit_depths",
+			"sample_rates",
+			"audio_channels",
+			"absolute_episodes",
+			"air_dates",
+			"max_air_date_age",
+			"anime_batch",
+			"anime_sub_type",
+			"anime_group_tiers",
+			"anime_max_group_tier",
+			"preset_id",
+			"preset_source",
+			"preset_managed",
+			"preset_hash",
+			"filter_tags",
+			"verify_torrent",
+			"min_file_count",
+			"max_file_count",
+			"match_file_extensions",
+			"except_file_extensions",
+			"flags",
+			"except_flags",
+			"group_id",
 		).
 		Values(
 			filter.Name,
@@ -889,6 +1285,56 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 			filter.PerfectFlac,
 			pq.Array(filter.Origins),
 			pq.Array(filter.ExceptOrigins),
+			filter.Expression,
+			filter.Schedule,
+			filter.UseRegexShows,
+			filter.UseRegexReleaseGroups,
+			filter.UseRegexTags,
+			filter.UseRegexUploaders,
+			filter.PreferSeasonPacks,
+			filter.SeasonPackHold,
+			filter.ProperUpgrades,
+			filter.ProperUpgradesReplace,
+			filter.ArrUpgradeOnly,
+			filter.FreeleechTokens,
+			filter.MinSeeders,
+			filter.MinLeechers,
+			filter.OnReject,
+			filter.OnRejectFilterID,
+			filter.OnActionFailure,
+			filter.OnActionFailureFilterID,
+			filter.DelayJitter,
+			filter.MaxDownloadsInterval,
+			filter.MaxDownloadsPerIndexer,
+			pq.Array(filter.HDRPreference),
+			pq.Array(filter.LanguagePreference),
+			pq.Array(filter.SubtitlePreference),
+			pq.Array(filter.ExceptLanguageOnly),
+			rewriteRules,
+			filter.RejectDVWithoutFallback,
+			pq.Array(filter.BitDepths),
+			pq.Array(filter.SampleRates),
+			pq.Array(filter.AudioChannels),
+			filter.AbsoluteEpisodes,
+			filter.AirDates,
+			filter.MaxAirDateAge,
+			filter.AnimeBatch,
+			filter.AnimeSubType,
+			filter.AnimeGroupTiers,
+			filter.AnimeMaxGroupTier,
+			filter.PresetID,
+			filter.PresetSource,
+			filter.PresetManaged,
+			filter.PresetHash,
+			pq.Array(filter.FilterTags),
+			filter.VerifyTorrent,
+			filter.MinFileCount,
+			filter.MaxFileCount,
+			filter.MatchFileExtensions,
+			filter.ExceptFileExtensions,
+			filter.Flags,
+			filter.ExceptFlags,
+			toNullInt32(int32(filter.GroupID)),
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -905,7 +1351,10 @@ func (r *FilterRepo) Store(ctx context.Context, filter *domain.Filter) error {
 }
 
 func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
-	var err error
+	rewriteRules, err := marshalRewriteRules(filter.RewriteRules)
+	if err != nil {
+		return err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Update("filter").
@@ -966,6 +1415,56 @@ func (r *FilterRepo) Update(ctx context.Context, filter *domain.Filter) error {
 		Set("perfect_flac", filter.PerfectFlac).
 		Set("origins", pq.Array(filter.Origins)).
 		Set("except_origins", pq.Array(filter.ExceptOrigins)).
+		Set("expression", filter.Expression).
+		Set("schedule", filter.Schedule).
+		Set("use_regex_shows", filter.UseRegexShows).
+		Set("use_regex_release_groups", filter.UseRegexReleaseGroups).
+		Set("use_regex_tags", filter.UseRegexTags).
+		Set("use_regex_uploaders", filter.UseRegexUploaders).
+		Set("prefer_season_packs", filter.PreferSeasonPacks).
+		Set("season_pack_hold", filter.SeasonPackHold).
+		Set("proper_upgrades", filter.ProperUpgrades).
+		Set("proper_upgrades_replace", filter.ProperUpgradesReplace).
+		Set("arr_upgrade_only", filter.ArrUpgradeOnly).
+		Set("freeleech_tokens", filter.FreeleechTokens).
+		Set("min_seeders", filter.MinSeeders).
+		Set("min_leechers", filter.MinLeechers).
+		Set("on_reject", filter.OnReject).
+		Set("on_reject_filter_id", filter.OnRejectFilterID).
+		Set("on_action_failure", filter.OnActionFailure).
+		Set("on_action_failure_filter_id", filter.OnActionFailureFilterID).
+		Set("delay_jitter", filter.DelayJitter).
+		Set("max_downloads_interval", filter.MaxDownloadsInterval).
+		Set("max_downloads_per_indexer", filter.MaxDownloadsPerIndexer).
+		Set("hdr_preference", pq.Array(filter.HDRPreference)).
+		Set("language_preference", pq.Array(filter.LanguagePreference)).
+		Set("subtitle_preference", pq.Array(filter.SubtitlePreference)).
+		Set("except_language_only", pq.Array(filter.ExceptLanguageOnly)).
+		Set("rewrite_rules", rewriteRules).
+		Set("reject_dv_without_fallback", filter.RejectDVWithoutFallback).
+		Set("bit_depths", pq.Array(filter.BitDepths)).
+		Set("sample_rates", pq.Array(filter.SampleRates)).
+		Set("audio_channels", pq.Array(filter.AudioChannels)).
+		Set("absolute_episodes", filter.AbsoluteEpisodes).
+		Set("air_dates", filter.AirDates).
+		Set("max_air_date_age", filter.MaxAirDateAge).
+		Set("anime_batch", filter.AnimeBatch).
+		Set("anime_sub_type", filter.AnimeSubType).
+		Set("anime_group_tiers", filter.AnimeGroupTiers).
+		Set("anime_max_group_tier", filter.AnimeMaxGroupTier).
+		Set("preset_id", filter.PresetID).
+		Set("preset_source", filter.PresetSource).
+		Set("preset_managed", filter.PresetManaged).
+		Set("preset_hash", filter.PresetHash).
+		Set("filter_tags", pq.Array(filter.FilterTags)).
+		Set("verify_torrent", filter.VerifyTorrent).
+		Set("min_file_count", filter.MinFileCount).
+		Set("max_file_count", filter.MaxFileCount).
+		Set("match_file_extensions", filter.MatchFileExtensions).
+		Set("except_file_extensions", filter.ExceptFileExtensions).
+		Set("flags", filter.Flags).
+		Set("except_flags", filter.ExceptFlags).
+		Set("group_id", toNullInt32(int32(filter.GroupID))).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": filter.ID})
 
@@ -1158,6 +1657,160 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.ExceptOrigins != nil {
 		q = q.Set("except_origins", pq.Array(filter.ExceptOrigins))
 	}
+	if filter.Expression != nil {
+		q = q.Set("expression", filter.Expression)
+	}
+	if filter.Schedule != nil {
+		q = q.Set("schedule", filter.Schedule)
+	}
+	if filter.UseRegexShows != nil {
+		q = q.Set("use_regex_shows", filter.UseRegexShows)
+	}
+	if filter.UseRegexReleaseGroups != nil {
+		q = q.Set("use_regex_release_groups", filter.UseRegexReleaseGroups)
+	}
+	if filter.UseRegexTags != nil {
+		q = q.Set("use_regex_tags", filter.UseRegexTags)
+	}
+	if filter.UseRegexUploaders != nil {
+		q = q.Set("use_regex_uploaders", filter.UseRegexUploaders)
+	}
+	if filter.PreferSeasonPacks != nil {
+		q = q.Set("prefer_season_packs", filter.PreferSeasonPacks)
+	}
+	if filter.SeasonPackHold != nil {
+		q = q.Set("season_pack_hold", filter.SeasonPackHold)
+	}
+	if filter.ProperUpgrades != nil {
+		q = q.Set("proper_upgrades", filter.ProperUpgrades)
+	}
+	if filter.ProperUpgradesReplace != nil {
+		q = q.Set("proper_upgrades_replace", filter.ProperUpgradesReplace)
+	}
+	if filter.ArrUpgradeOnly != nil {
+		q = q.Set("arr_upgrade_only", filter.ArrUpgradeOnly)
+	}
+	if filter.FreeleechTokens != nil {
+		q = q.Set("freeleech_tokens", filter.FreeleechTokens)
+	}
+	if filter.MinSeeders != nil {
+		q = q.Set("min_seeders", filter.MinSeeders)
+	}
+	if filter.MinLeechers != nil {
+		q = q.Set("min_leechers", filter.MinLeechers)
+	}
+	if filter.OnReject != nil {
+		q = q.Set("on_reject", filter.OnReject)
+	}
+	if filter.OnRejectFilterID != nil {
+		q = q.Set("on_reject_filter_id", filter.OnRejectFilterID)
+	}
+	if filter.OnActionFailure != nil {
+		q = q.Set("on_action_failure", filter.OnActionFailure)
+	}
+	if filter.OnActionFailureFilterID != nil {
+		q = q.Set("on_action_failure_filter_id", filter.OnActionFailureFilterID)
+	}
+	if filter.DelayJitter != nil {
+		q = q.Set("delay_jitter", filter.DelayJitter)
+	}
+	if filter.MaxDownloadsInterval != nil {
+		q = q.Set("max_downloads_interval", filter.MaxDownloadsInterval)
+	}
+	if filter.MaxDownloadsPerIndexer != nil {
+		q = q.Set("max_downloads_per_indexer", filter.MaxDownloadsPerIndexer)
+	}
+	if filter.HDRPreference != nil {
+		q = q.Set("hdr_preference", pq.Array(filter.HDRPreference))
+	}
+	if filter.LanguagePreference != nil {
+		q = q.Set("language_preference", pq.Array(filter.LanguagePreference))
+	}
+	if filter.SubtitlePreference != nil {
+		q = q.Set("subtitle_preference", pq.Array(filter.SubtitlePreference))
+	}
+	if filter.ExceptLanguageOnly != nil {
+		q = q.Set("except_language_only", pq.Array(filter.ExceptLanguageOnly))
+	}
+	if filter.RewriteRules != nil {
+		rewriteRules, err := marshalRewriteRules(*filter.RewriteRules)
+		if err != nil {
+			return err
+		}
+		q = q.Set("rewrite_rules", rewriteRules)
+	}
+	if filter.RejectDVWithoutFallback != nil {
+		q = q.Set("reject_dv_without_fallback", filter.RejectDVWithoutFallback)
+	}
+	if filter.BitDepths != nil {
+		q = q.Set("bit_depths", pq.Array(filter.BitDepths))
+	}
+	if filter.SampleRates != nil {
+		q = q.Set("sample_rates", pq.Array(filter.SampleRates))
+	}
+	if filter.AudioChannels != nil {
+		q = q.Set("audio_channels", pq.Array(filter.AudioChannels))
+	}
+	if filter.AbsoluteEpisodes != nil {
+		q = q.Set("absolute_episodes", filter.AbsoluteEpisodes)
+	}
+	if filter.AirDates != nil {
+		q = q.Set("air_dates", filter.AirDates)
+	}
+	if filter.MaxAirDateAge != nil {
+		q = q.Set("max_air_date_age", filter.MaxAirDateAge)
+	}
+	if filter.AnimeBatch != nil {
+		q = q.Set("anime_batch", filter.AnimeBatch)
+	}
+	if filter.AnimeSubType != nil {
+		q = q.Set("anime_sub_type", filter.AnimeSubType)
+	}
+	if filter.AnimeGroupTiers != nil {
+		q = q.Set("anime_group_tiers", filter.AnimeGroupTiers)
+	}
+	if filter.AnimeMaxGroupTier != nil {
+		q = q.Set("anime_max_group_tier", filter.AnimeMaxGroupTier)
+	}
+	if filter.PresetID != nil {
+		q = q.Set("preset_id", filter.PresetID)
+	}
+	if filter.PresetSource != nil {
+		q = q.Set("preset_source", filter.PresetSource)
+	}
+	if filter.PresetManaged != nil {
+		q = q.Set("preset_managed", filter.PresetManaged)
+	}
+	if filter.PresetHash != nil {
+		q = q.Set("preset_hash", filter.PresetHash)
+	}
+	if filter.FilterTags != nil {
+		q = q.Set("filter_tags", pq.Array(filter.FilterTags))
+	}
+	if filter.VerifyTorrent != nil {
+		q = q.Set("verify_torrent", filter.VerifyTorrent)
+	}
+	if filter.MinFileCount != nil {
+		q = q.Set("min_file_count", filter.MinFileCount)
+	}
+	if filter.MaxFileCount != nil {
+		q = q.Set("max_file_count", filter.MaxFileCount)
+	}
+	if filter.MatchFileExtensions != nil {
+		q = q.Set("match_file_extensions", filter.MatchFileExtensions)
+	}
+	if filter.ExceptFileExtensions != nil {
+		q = q.Set("except_file_extensions", filter.ExceptFileExtensions)
+	}
+	if filter.Flags != nil {
+		q = q.Set("flags", filter.Flags)
+	}
+	if filter.ExceptFlags != nil {
+		q = q.Set("except_flags", filter.ExceptFlags)
+	}
+	if filter.GroupID != nil {
+		q = q.Set("group_id", toNullInt32(int32(*filter.GroupID)))
+	}
 	if filter.ExternalScriptEnabled != nil {
 		q = q.Set("external_script_enabled", filter.ExternalScriptEnabled)
 	}
@@ -1183,6 +1836,9 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 		q = q.Set("external_webhook_expect_status", filter.ExternalWebhookExpectStatus)
 	}
 
+	// also makes the update valid when only relations like indexers or list sources are changed
+	q = q.Set("updated_at", sq.Expr("CURRENT_TIMESTAMP"))
+
 	q = q.Where(sq.Eq{"id": filter.ID})
 
 	query, args, err := q.ToSql()
@@ -1359,51 +2015,43 @@ func (r *FilterRepo) Delete(ctx context.Context, filterID int) error {
 	return nil
 }
 
-func (r *FilterRepo) GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
+func (r *FilterRepo) GetDownloadsByFilterId(ctx context.Context, params domain.FilterDownloadsParams) (*domain.FilterDownloads, error) {
+	return r.downloadsByFilter(ctx, params, time.Now())
+}
+
+// downloadsByFilter counts the downloads of a filter in the windows that end at now. The window starts are bound in
+// the format the timestamps are written in, so the counts do not depend on the time zone of the database session.
+func (r *FilterRepo) downloadsByFilter(ctx context.Context, params domain.FilterDownloadsParams, now time.Time) (*domain.FilterDownloads, error) {
+	// sqlite compares the timestamps as text, datetime normalizes their offsets to utc
+	column, start := "release_action_status.timestamp", "?"
 	if r.db.Driver == "sqlite" {
-		return r.downloadsByFilterSqlite(ctx, filterID)
+		column, start = "datetime(release_action_status.timestamp)", "datetime(?)"
 	}
 
-	return r.downloadsByFilterPostgres(ctx, filterID)
-}
+	queryBuilder := r.db.squirrel.Select()
 
-func (r *FilterRepo) downloadsByFilterSqlite(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
-	query := `SELECT
-	COUNT(CASE WHEN CAST(strftime('%s', datetime(release_action_status.timestamp, 'localtime')) AS INTEGER) >= CAST(strftime('%s', strftime('%Y-%m-%dT%H:00:00', datetime('now','localtime'))) AS INTEGER) THEN 1 END) as "hour_count",
-	COUNT(CASE WHEN CAST(strftime('%s', datetime(release_action_status.timestamp, 'localtime')) AS INTEGER) >= CAST(strftime('%s', datetime('now', 'localtime', 'start of day')) AS INTEGER) THEN 1 END) as "day_count",
-	COUNT(CASE WHEN CAST(strftime('%s', datetime(release_action_status.timestamp, 'localtime')) AS INTEGER) >= CAST(strftime('%s', datetime('now', 'localtime', 'weekday 0', '-7 days', 'start of day')) AS INTEGER) THEN 1 END) as "week_count",
-	COUNT(CASE WHEN CAST(strftime('%s', datetime(release_action_status.timestamp, 'localtime')) AS INTEGER) >= CAST(strftime('%s', datetime('now', 'localtime', 'start of month')) AS INTEGER) THEN 1 END) as "month_count",
-	COUNT(*) as "total_count"
-FROM release_action_status
-WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.status = 'PENDING') AND release_action_status.filter_id = ?;`
-
-	row := r.db.handler.QueryRowContext(ctx, query, filterID)
-	if err := row.Err(); err != nil {
-		return nil, errors.Wrap(err, "error executing query")
+	for _, unit := range []domain.FilterMaxDownloadsUnit{domain.FilterMaxDownloadsHour, domain.FilterMaxDownloadsDay, domain.FilterMaxDownloadsWeek, domain.FilterMaxDownloadsMonth} {
+		windowStart := now.Add(-params.Window(unit)).Format(time.RFC3339)
+		queryBuilder = queryBuilder.Column(fmt.Sprintf("COUNT(CASE WHEN %s >= %s THEN 1 END)", column, start), windowStart)
 	}
 
-	var f domain.FilterDownloads
+	queryBuilder = queryBuilder.
+		Column("COUNT(*)").
+		From("release_action_status").
+		LeftJoin(`"release" ON "release".id = release_action_status.release_id`).
+		Where(sq.Eq{"release_action_status.status": []string{string(domain.ReleasePushStatusApproved), string(domain.ReleasePushStatusPending)}}).
+		Where(sq.Eq{"release_action_status.filter_id": params.FilterID})
 
-	if err := row.Scan(&f.HourCount, &f.DayCount, &f.WeekCount, &f.MonthCount, &f.TotalCount); err != nil {
-		return nil, errors.Wrap(err, "error scanning stats data sqlite")
+	if params.Indexer != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{`"release".indexer`: params.Indexer})
 	}
 
-	r.log.Trace().Msgf("filter %v downloads: %+v", filterID, &f)
-
-	return &f, nil
-}
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
 
-func (r *FilterRepo) downloadsByFilterPostgres(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
-	query := `SELECT
-    COALESCE(SUM(CASE WHEN release_action_status.timestamp >= date_trunc('hour', CURRENT_TIMESTAMP) THEN 1 ELSE 0 END),0) as "hour_count",
-    COALESCE(SUM(CASE WHEN release_action_status.timestamp >= date_trunc('day', CURRENT_DATE) THEN 1 ELSE 0 END),0) as "day_count",
-    COALESCE(SUM(CASE WHEN release_action_status.timestamp >= date_trunc('week', CURRENT_DATE) THEN 1 ELSE 0 END),0) as "week_count",
-    COALESCE(SUM(CASE WHEN release_action_status.timestamp >= date_trunc('month', CURRENT_DATE) THEN 1 ELSE 0 END),0) as "month_count",
-    count(*) as "total_count"
-FROM release_action_status
-WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.status = 'PENDING') AND release_action_status.filter_id = $1;`
-
-	row := r.db.handler.QueryRowContext(ctx, query, filterID)
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
 	if err := row.Err(); err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -1411,9 +2059,11 @@ WHERE (release_action_status.status = 'PUSH_APPROVED' OR release_action_status.s
 	var f domain.FilterDownloads
 
 	if err := row.Scan(&f.HourCount, &f.DayCount, &f.WeekCount, &f.MonthCount, &f.TotalCount); err != nil {
-		return nil, errors.Wrap(err, "error scanning stats data postgres")
+		return nil, errors.Wrap(err, "error scanning stats data")
 	}
 
+	r.log.Trace().Msgf("filter %v downloads: %+v", params.FilterID, &f)
+
 	return &f, nil
 }
 
@@ -1457,11 +2107,19 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			"exec_cmd",
 			"exec_args",
 			"exec_expect_status",
+			"exec_mode",
+			"exec_timeout",
+			"exec_env",
 			"webhook_host",
 			"webhook_method",
 			"webhook_data",
 			"webhook_headers",
 			"webhook_expect_status",
+			"webhook_retry_status",
+			"webhook_retry_attempts",
+			"webhook_retry_delay_seconds",
+			"webhook_expect_json_path",
+			"webhook_expect_json_value",
 			"filter_id",
 		)
 
@@ -1474,11 +2132,19 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 			toNullString(external.ExecCmd),
 			toNullString(external.ExecArgs),
 			toNullInt32(int32(external.ExecExpectStatus)),
+			toNullString(string(external.ExecMode)),
+			toNullInt32(int32(external.ExecTimeout)),
+			toNullString(external.ExecEnv),
 			toNullString(external.WebhookHost),
 			toNullString(external.WebhookMethod),
 			toNullString(external.WebhookData),
 			toNullString(external.WebhookHeaders),
 			toNullInt32(int32(external.WebhookExpectStatus)),
+			toNullString(external.WebhookRetryStatus),
+			toNullInt32(int32(external.WebhookRetryAttempts)),
+			toNullInt32(int32(external.WebhookRetryDelay)),
+			toNullString(external.WebhookJSONPath),
+			toNullString(external.WebhookJSONValue),
 			filterID,
 		)
 	}
@@ -1501,3 +2167,30 @@ func (r *FilterRepo) StoreFilterExternal(ctx context.Context, filterID int, exte
 
 	return nil
 }
+
+// marshalRewriteRules encodes the rewrite rules of a filter for the rewrite_rules column
+func marshalRewriteRules(rules []domain.FilterRewriteRule) (string, error) {
+	if len(rules) == 0 {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return "", errors.Wrap(err, "could not marshal rewrite rules")
+	}
+
+	return string(data), nil
+}
+
+func unmarshalRewriteRules(value sql.NullString) ([]domain.FilterRewriteRule, error) {
+	if value.String == "" {
+		return nil, nil
+	}
+
+	var rules []domain.FilterRewriteRule
+	if err := json.Unmarshal([]byte(value.String), &rules); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal rewrite rules")
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FilterGroupRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFilterGroupRepo(log logger.Logger, db *DB) domain.FilterGroupRepo {
+	return &FilterGroupRepo{
+		log: log.With().Str("repo", "filter_group").Logger(),
+		db:  db,
+	}
+}
+
+func (r *FilterGroupRepo) List(ctx context.Context) ([]domain.FilterGroup, error) {
+	filtersCountQuery := r.db.squirrel.
+		Select("COUNT(*)").
+		From("filter f").
+		Where("f.group_id = g.id")
+
+	queryBuilder := r.db.squirrel.
+		Select("g.id", "g.name", "g.min_size", "g.max_size", "g.created_at", "g.updated_at").
+		Column(sq.Alias(filtersCountQuery, "filters_count")).
+		From("filter_group g").
+		OrderBy("g.name ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	groups := make([]domain.FilterGroup, 0)
+	for rows.Next() {
+		var g domain.FilterGroup
+		var minSize, maxSize sql.NullString
+
+		if err := rows.Scan(&g.ID, &g.Name, &minSize, &maxSize, &g.CreatedAt, &g.UpdatedAt, &g.FiltersCount); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		g.MinSize = minSize.String
+		g.MaxSize = maxSize.String
+
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return groups, nil
+}
+
+func (r *FilterGroupRepo) FindByID(ctx context.Context, groupID int) (*domain.FilterGroup, error) {
+	filtersCountQuery := r.db.squirrel.
+		Select("COUNT(*)").
+		From("filter f").
+		Where("f.group_id = g.id")
+
+	queryBuilder := r.db.squirrel.
+		Select("g.id", "g.name", "g.min_size", "g.max_size", "g.created_at", "g.updated_at").
+		Column(sq.Alias(filtersCountQuery, "filters_count")).
+		From("filter_group g").
+		Where(sq.Eq{"g.id": groupID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+
+	var g domain.FilterGroup
+	var minSize, maxSize sql.NullString
+
+	if err := row.Scan(&g.ID, &g.Name, &minSize, &maxSize, &g.CreatedAt, &g.UpdatedAt, &g.FiltersCount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	g.MinSize = minSize.String
+	g.MaxSize = maxSize.String
+
+	return &g, nil
+}
+
+func (r *FilterGroupRepo) Store(ctx context.Context, group *domain.FilterGroup) error {
+	queryBuilder := r.db.squirrel.
+		Insert("filter_group").
+		Columns("name", "min_size", "max_size").
+		Values(group.Name, group.MinSize, group.MaxSize).
+		Suffix("RETURNING id, created_at, updated_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FilterGroupRepo) Update(ctx context.Context, group *domain.FilterGroup) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter_group").
+		Set("name", group.Name).
+		Set("min_size", group.MinSize).
+		Set("max_size", group.MaxSize).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": group.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the group with its indexers and actions, member filters are kept without a group
+func (r *FilterGroupRepo) Delete(ctx context.Context, groupID int) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	queries := []sq.Sqlizer{
+		r.db.squirrel.Update("filter").Set("group_id", nil).Where(sq.Eq{"group_id": groupID}),
+		r.db.squirrel.Delete("action").Where(sq.Eq{"filter_group_id": groupID}),
+		r.db.squirrel.Delete("filter_group_indexer").Where(sq.Eq{"filter_group_id": groupID}),
+		r.db.squirrel.Delete("filter_group").Where(sq.Eq{"id": groupID}),
+	}
+
+	for _, queryBuilder := range queries {
+		query, args, err := queryBuilder.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	r.log.Info().Msgf("filter_group.delete: successfully deleted: %v", groupID)
+
+	return nil
+}
+
+func (r *FilterGroupRepo) FindIndexers(ctx context.Context, groupID int) ([]domain.Indexer, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "enabled", "name", "identifier", "base_url", "settings").
+		From("indexer").
+		Join("filter_group_indexer ON indexer.id = filter_group_indexer.indexer_id").
+		Where(sq.Eq{"filter_group_indexer.filter_group_id": groupID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	indexers := make([]domain.Indexer, 0)
+	for rows.Next() {
+		var i domain.Indexer
+
+		var settings string
+		var baseURL sql.NullString
+
+		if err := rows.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &baseURL, &settings); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if err := json.Unmarshal([]byte(settings), &i.Settings); err != nil {
+			return nil, errors.Wrap(err, "error unmarshal settings")
+		}
+
+		i.BaseURL = baseURL.String
+
+		indexers = append(indexers, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return indexers, nil
+}
+
+func (r *FilterGroupRepo) StoreIndexerConnections(ctx context.Context, groupID int, indexers []domain.Indexer) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	deleteQuery, deleteArgs, err := r.db.squirrel.
+		Delete("filter_group_indexer").
+		Where(sq.Eq{"filter_group_id": groupID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if len(indexers) > 0 {
+		queryBuilder := r.db.squirrel.
+			Insert("filter_group_indexer").
+			Columns("filter_group_id", "indexer_id")
+
+		for _, indexer := range indexers {
+			queryBuilder = queryBuilder.Values(groupID, indexer.ID)
+		}
+
+		query, args, err := queryBuilder.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error store indexers for filter group: %d", groupID)
+	}
+
+	return nil
+}
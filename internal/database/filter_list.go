@@ -0,0 +1,158 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// FindListSources returns the list sources of a filter, or of all filters if filterID is 0
+func (r *FilterRepo) FindListSources(ctx context.Context, filterID int) ([]domain.FilterListSource, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"filter_id",
+			"field",
+			"url",
+			"format",
+			"json_key",
+			"refresh_interval",
+			"last_refreshed_at",
+			"last_error",
+		).
+		From("filter_list_source").
+		OrderBy("filter_id ASC", "id ASC")
+
+	if filterID > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"filter_id": filterID})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	sources := make([]domain.FilterListSource, 0)
+	for rows.Next() {
+		var s domain.FilterListSource
+		var jsonKey, lastError sql.NullString
+		var lastRefreshedAt sql.NullTime
+
+		if err := rows.Scan(&s.ID, &s.FilterID, &s.Field, &s.URL, &s.Format, &jsonKey, &s.RefreshInterval, &lastRefreshedAt, &lastError); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		s.JSONKey = jsonKey.String
+		s.LastError = lastError.String
+
+		if lastRefreshedAt.Valid {
+			s.LastRefreshedAt = &lastRefreshedAt.Time
+		}
+
+		sources = append(sources, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return sources, nil
+}
+
+// StoreListSources replaces the list sources of a filter, the new sources are refreshed on the next run
+func (r *FilterRepo) StoreListSources(ctx context.Context, filterID int, sources []domain.FilterListSource) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	deleteQuery, deleteArgs, err := r.db.squirrel.
+		Delete("filter_list_source").
+		Where(sq.Eq{"filter_id": filterID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if len(sources) > 0 {
+		queryBuilder := r.db.squirrel.
+			Insert("filter_list_source").
+			Columns("filter_id", "field", "url", "format", "json_key", "refresh_interval")
+
+		for _, s := range sources {
+			queryBuilder = queryBuilder.Values(filterID, s.Field, s.URL, s.Format, toNullString(s.JSONKey), s.RefreshInterval)
+		}
+
+		query, args, err := queryBuilder.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error store list sources for filter: %d", filterID)
+	}
+
+	return nil
+}
+
+func (r *FilterRepo) UpdateListSourceRefresh(ctx context.Context, sourceID int, refreshedAt time.Time, lastError string) error {
+	queryBuilder := r.db.squirrel.
+		Update("filter_list_source").
+		Set("last_refreshed_at", refreshedAt).
+		Set("last_error", toNullString(lastError)).
+		Where(sq.Eq{"id": sourceID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FilterRepo) DeleteListSources(ctx context.Context, filterID int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("filter_list_source").
+		Where(sq.Eq{"filter_id": filterID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
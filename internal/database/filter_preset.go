@@ -0,0 +1,65 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// FindPresets returns the filters that were imported from a preset with their preset fields
+func (r *FilterRepo) FindPresets(ctx context.Context) ([]domain.Filter, error) {
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"preset_id",
+			"preset_source",
+			"preset_managed",
+			"preset_hash",
+		).
+		From("filter").
+		Where(sq.And{sq.NotEq{"preset_id": nil}, sq.NotEq{"preset_id": ""}}).
+		OrderBy("id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	filters := make([]domain.Filter, 0)
+	for rows.Next() {
+		var f domain.Filter
+		var presetSource, presetHash sql.NullString
+		var presetManaged sql.NullBool
+
+		if err := rows.Scan(&f.ID, &f.Name, &f.PresetID, &presetSource, &presetManaged, &presetHash); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		f.PresetSource = presetSource.String
+		f.PresetManaged = presetManaged.Bool
+		f.PresetHash = presetHash.String
+
+		filters = append(filters, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FilterRevisionRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFilterRevisionRepo(log logger.Logger, db *DB) domain.FilterRevisionRepo {
+	return &FilterRevisionRepo{
+		log: log.With().Str("repo", "filter_revision").Logger(),
+		db:  db,
+	}
+}
+
+// List returns the revisions of a filter without snapshots, newest first
+func (r *FilterRevisionRepo) List(ctx context.Context, filterID int) ([]domain.FilterRevision, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "filter_id", "revision", "username", "note", "changes", "created_at").
+		From("filter_revision").
+		Where(sq.Eq{"filter_id": filterID}).
+		OrderBy("revision DESC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	revisions := make([]domain.FilterRevision, 0)
+	for rows.Next() {
+		var rev domain.FilterRevision
+		var username, note, changes sql.NullString
+
+		if err := rows.Scan(&rev.ID, &rev.FilterID, &rev.Revision, &username, &note, &changes, &rev.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		rev.Username = username.String
+		rev.Note = note.String
+
+		if err := unmarshalChanges(changes, &rev); err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return revisions, nil
+}
+
+func (r *FilterRevisionRepo) FindByRevision(ctx context.Context, filterID int, revision int) (*domain.FilterRevision, error) {
+	return r.findOne(ctx, r.db.squirrel.
+		Select("id", "filter_id", "revision", "username", "note", "changes", "snapshot", "created_at").
+		From("filter_revision").
+		Where(sq.Eq{"filter_id": filterID, "revision": revision}))
+}
+
+func (r *FilterRevisionRepo) FindLatest(ctx context.Context, filterID int) (*domain.FilterRevision, error) {
+	return r.findOne(ctx, r.db.squirrel.
+		Select("id", "filter_id", "revision", "username", "note", "changes", "snapshot", "created_at").
+		From("filter_revision").
+		Where(sq.Eq{"filter_id": filterID}).
+		OrderBy("revision DESC").
+		Limit(1))
+}
+
+func (r *FilterRevisionRepo) findOne(ctx context.Context, queryBuilder sq.SelectBuilder) (*domain.FilterRevision, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+
+	var rev domain.FilterRevision
+	var username, note, changes sql.NullString
+	var snapshot string
+
+	if err := row.Scan(&rev.ID, &rev.FilterID, &rev.Revision, &username, &note, &changes, &snapshot, &rev.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	rev.Username = username.String
+	rev.Note = note.String
+
+	if err := unmarshalChanges(changes, &rev); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(snapshot), &rev.Snapshot); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal snapshot")
+	}
+
+	return &rev, nil
+}
+
+func unmarshalChanges(changes sql.NullString, rev *domain.FilterRevision) error {
+	rev.Changes = []domain.FilterFieldChange{}
+
+	if changes.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(changes.String), &rev.Changes); err != nil {
+		return errors.Wrap(err, "error unmarshal changes")
+	}
+
+	return nil
+}
+
+func (r *FilterRevisionRepo) Store(ctx context.Context, rev *domain.FilterRevision) error {
+	changes, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return errors.Wrap(err, "error marshal changes")
+	}
+
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return errors.Wrap(err, "error marshal snapshot")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("filter_revision").
+		Columns("filter_id", "revision", "username", "note", "changes", "snapshot").
+		Values(rev.FilterID, rev.Revision, toNullString(rev.Username), toNullString(rev.Note), string(changes), string(snapshot)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&rev.ID, &rev.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// DeleteOld removes all but the newest keep revisions of a filter
+func (r *FilterRevisionRepo) DeleteOld(ctx context.Context, filterID int, keep int) error {
+	latest, err := r.FindLatest(ctx, filterID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	queryBuilder := r.db.squirrel.
+		Delete("filter_revision").
+		Where(sq.Eq{"filter_id": filterID}).
+		Where(sq.LtOrEq{"revision": latest.Revision - keep})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FilterRevisionRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("filter_revision").
+		Where(sq.Eq{"filter_id": filterID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FilterStatsRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFilterStatsRepo(log logger.Logger, db *DB) domain.FilterStatsRepo {
+	return &FilterStatsRepo{
+		log: log.With().Str("repo", "filter_stats").Logger(),
+		db:  db,
+	}
+}
+
+// Store adds the counters of the entries to the stored ones
+func (r *FilterStatsRepo) Store(ctx context.Context, entries []domain.FilterStatsEntry) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	for _, entry := range entries {
+		bucket := entry.Bucket.UTC()
+
+		query, args, err := r.db.squirrel.
+			Insert("filter_stats").
+			Columns("filter_id", "bucket", "evaluated", "matched", "rejected").
+			Values(entry.FilterID, bucket, entry.Evaluated, entry.Matched, entry.Rejected).
+			Suffix("ON CONFLICT (filter_id, bucket) DO UPDATE SET evaluated = filter_stats.evaluated + EXCLUDED.evaluated, matched = filter_stats.matched + EXCLUDED.matched, rejected = filter_stats.rejected + EXCLUDED.rejected").
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+
+		for rejection, count := range entry.Rejections {
+			query, args, err := r.db.squirrel.
+				Insert("filter_stats_rejection").
+				Columns("filter_id", "bucket", "rejection", "count").
+				Values(entry.FilterID, bucket, rejection, count).
+				Suffix("ON CONFLICT (filter_id, bucket, rejection) DO UPDATE SET count = filter_stats_rejection.count + EXCLUDED.count").
+				ToSql()
+			if err != nil {
+				return errors.Wrap(err, "error building query")
+			}
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return errors.Wrap(err, "error executing query")
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error store filter stats")
+	}
+
+	return nil
+}
+
+// Find returns the hourly entries of a filter from since, oldest first
+func (r *FilterStatsRepo) Find(ctx context.Context, filterID int, since time.Time) ([]domain.FilterStatsEntry, error) {
+	query, args, err := r.db.squirrel.
+		Select("bucket", "evaluated", "matched", "rejected").
+		From("filter_stats").
+		Where(sq.Eq{"filter_id": filterID}).
+		Where(sq.GtOrEq{"bucket": since.UTC()}).
+		OrderBy("bucket ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	entries := make([]domain.FilterStatsEntry, 0)
+	index := make(map[time.Time]int)
+
+	for rows.Next() {
+		entry := domain.FilterStatsEntry{FilterID: filterID, Rejections: map[string]int{}}
+
+		if err := rows.Scan(&entry.Bucket, &entry.Evaluated, &entry.Matched, &entry.Rejected); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		index[entry.Bucket.UTC()] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	query, args, err = r.db.squirrel.
+		Select("bucket", "rejection", "count").
+		From("filter_stats_rejection").
+		Where(sq.Eq{"filter_id": filterID}).
+		Where(sq.GtOrEq{"bucket": since.UTC()}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rejectionRows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rejectionRows.Close()
+
+	for rejectionRows.Next() {
+		var bucket time.Time
+		var rejection string
+		var count int
+
+		if err := rejectionRows.Scan(&bucket, &rejection, &count); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if i, ok := index[bucket.UTC()]; ok {
+			entries[i].Rejections[rejection] += count
+		}
+	}
+
+	if err := rejectionRows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return entries, nil
+}
+
+// FindActionStatuses returns the status and time of the actions run for a filter from since
+func (r *FilterStatsRepo) FindActionStatuses(ctx context.Context, filterID int, since time.Time) ([]domain.ReleaseActionStatus, error) {
+	query, args, err := r.db.squirrel.
+		Select("status", "timestamp").
+		From("release_action_status").
+		Where(sq.Eq{"filter_id": filterID}).
+		Where(sq.GtOrEq{"timestamp": since.UTC()}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	statuses := make([]domain.ReleaseActionStatus, 0)
+	for rows.Next() {
+		var status sql.NullString
+		var timestamp time.Time
+
+		if err := rows.Scan(&status, &timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		statuses = append(statuses, domain.ReleaseActionStatus{Status: domain.ReleasePushStatus(status.String), Timestamp: timestamp})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return statuses, nil
+}
+
+func (r *FilterStatsRepo) DeleteOld(ctx context.Context, before time.Time) error {
+	for _, table := range []string{"filter_stats", "filter_stats_rejection"} {
+		query, args, err := r.db.squirrel.
+			Delete(table).
+			Where(sq.Lt{"bucket": before.UTC()}).
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	return nil
+}
+
+func (r *FilterStatsRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
+	for _, table := range []string{"filter_stats", "filter_stats_rejection"} {
+		query, args, err := r.db.squirrel.
+			Delete(table).
+			Where(sq.Eq{"filter_id": filterID}).
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type FilterTemplateRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewFilterTemplateRepo(log logger.Logger, db *DB) domain.FilterTemplateRepo {
+	return &FilterTemplateRepo{
+		log: log.With().Str("repo", "filter_template").Logger(),
+		db:  db,
+	}
+}
+
+func (r *FilterTemplateRepo) List(ctx context.Context) ([]domain.FilterTemplate, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "name", "description", "variables", "filter", "created_at", "updated_at").
+		From("filter_template").
+		OrderBy("name ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	templates := make([]domain.FilterTemplate, 0)
+	for rows.Next() {
+		t, err := scanFilterTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return templates, nil
+}
+
+func (r *FilterTemplateRepo) FindByID(ctx context.Context, templateID int) (*domain.FilterTemplate, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "name", "description", "variables", "filter", "created_at", "updated_at").
+		From("filter_template").
+		Where(sq.Eq{"id": templateID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	t, err := scanFilterTemplate(r.db.handler.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFilterTemplate(row rowScanner) (*domain.FilterTemplate, error) {
+	var t domain.FilterTemplate
+	var description, variables sql.NullString
+	var filter string
+
+	if err := row.Scan(&t.ID, &t.Name, &description, &variables, &filter, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	t.Description = description.String
+	t.Variables = []domain.FilterTemplateVariable{}
+
+	if variables.String != "" {
+		if err := json.Unmarshal([]byte(variables.String), &t.Variables); err != nil {
+			return nil, errors.Wrap(err, "error unmarshal variables")
+		}
+	}
+
+	if err := json.Unmarshal([]byte(filter), &t.Filter); err != nil {
+		return nil, errors.Wrap(err, "error unmarshal filter")
+	}
+
+	return &t, nil
+}
+
+func (r *FilterTemplateRepo) Store(ctx context.Context, template *domain.FilterTemplate) error {
+	variables, filter, err := marshalFilterTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("filter_template").
+		Columns("name", "description", "variables", "filter").
+		Values(template.Name, toNullString(template.Description), variables, filter).
+		Suffix("RETURNING id, created_at, updated_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *FilterTemplateRepo) Update(ctx context.Context, template *domain.FilterTemplate) error {
+	variables, filter, err := marshalFilterTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	queryBuilder := r.db.squirrel.
+		Update("filter_template").
+		Set("name", template.Name).
+		Set("description", toNullString(template.Description)).
+		Set("variables", variables).
+		Set("filter", filter).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": template.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func marshalFilterTemplate(template *domain.FilterTemplate) (string, string, error) {
+	variables, err := json.Marshal(template.Variables)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error marshal variables")
+	}
+
+	filter, err := json.Marshal(template.Filter)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error marshal filter")
+	}
+
+	return string(variables), string(filter), nil
+}
+
+func (r *FilterTemplateRepo) Delete(ctx context.Context, templateID int) error {
+	queryBuilder := r.db.squirrel.
+		Delete("filter_template").
+		Where(sq.Eq{"id": templateID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("filter_template.delete: successfully deleted: %v", templateID)
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestFilterRepo_GetDownloadsByFilterId(t *testing.T) {
+	// timestamps are written with the local offset, windows must not shift with it
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	defer func() { time.Local = local }()
+
+	log := logger.Mock()
+
+	db, err := NewDB(&domain.Config{DatabaseType: "sqlite", ConfigPath: t.TempDir()}, log)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Open())
+	defer db.Close()
+
+	ctx := context.Background()
+	releaseRepo := NewReleaseRepo(log, db)
+	filterRepo := NewFilterRepo(log, db)
+
+	downloads := []struct {
+		indexer string
+		age     time.Duration
+	}{
+		{indexer: "mock", age: 30 * time.Minute},
+		{indexer: "mock", age: 3 * time.Hour},
+		{indexer: "other", age: 30 * time.Minute},
+		{indexer: "mock", age: 3 * 24 * time.Hour},
+	}
+
+	for _, d := range downloads {
+		release := domain.NewRelease(d.indexer)
+		release.TorrentName = "Test.Release-GROUP"
+		assert.NoError(t, releaseRepo.Store(ctx, release))
+
+		status := &domain.ReleaseActionStatus{
+			Status:     domain.ReleasePushStatusApproved,
+			Action:     "test",
+			Type:       domain.ActionTypeTest,
+			FilterID:   1,
+			ReleaseID:  release.ID,
+			Rejections: []string{},
+			Timestamp:  time.Now().Add(-d.age),
+		}
+		assert.NoError(t, releaseRepo.StoreReleaseActionStatus(ctx, status))
+	}
+
+	tests := []struct {
+		name   string
+		params domain.FilterDownloadsParams
+		want   domain.FilterDownloads
+	}{
+		{
+			name:   "filter",
+			params: domain.FilterDownloadsParams{FilterID: 1},
+			want:   domain.FilterDownloads{HourCount: 2, DayCount: 3, WeekCount: 4, MonthCount: 4, TotalCount: 4},
+		},
+		{
+			name:   "interval",
+			params: domain.FilterDownloadsParams{FilterID: 1, Interval: 6},
+			want:   domain.FilterDownloads{HourCount: 3, DayCount: 4, WeekCount: 4, MonthCount: 4, TotalCount: 4},
+		},
+		{
+			name:   "indexer",
+			params: domain.FilterDownloadsParams{FilterID: 1, Indexer: "mock"},
+			want:   domain.FilterDownloads{HourCount: 1, DayCount: 2, WeekCount: 3, MonthCount: 3, TotalCount: 3},
+		},
+		{
+			name:   "other_filter",
+			params: domain.FilterDownloadsParams{FilterID: 2},
+			want:   domain.FilterDownloads{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterRepo.GetDownloadsByFilterId(ctx, tt.params)
+			assert.NoError(t, err)
+			assert.Equal(t, &tt.want, got)
+		})
+	}
+}
@@ -30,7 +30,7 @@ func NewIrcRepo(log logger.Logger, db *DB) domain.IrcRepo {
 
 func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "tls_cert", "tls_key", "proxy", "reconnect_delay", "reconnect_max_delay", "reconnect_jitter", "reconnect_alert_attempts", "alt_servers", "send_burst", "send_delay", "tls_min_version", "tls_ca", "tls_skip_verify", "share_connection", "offline_schedule", "ident", "realname", "perform_commands", "split_quarantine", "split_action", "encoding").
 		From("irc_network").
 		Where(sq.Eq{"id": id})
 
@@ -42,12 +42,12 @@ func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetw
 
 	var n domain.IrcNetwork
 
-	var pass, nick, inviteCmd, bouncerAddr sql.NullString
+	var pass, nick, inviteCmd, bouncerAddr, tlsCert, tlsKey, proxy, altServers, tlsMinVersion, tlsCA, offlineSchedule, ident, realName, performCommands, splitAction, encoding sql.NullString
 	var account, password sql.NullString
 	var tls sql.NullBool
 
 	row := r.db.handler.QueryRowContext(ctx, query, args...)
-	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &nick, &n.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &n.UseBouncer); err != nil {
+	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &nick, &n.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &n.UseBouncer, &tlsCert, &tlsKey, &proxy, &n.ReconnectDelay, &n.ReconnectMaxDelay, &n.ReconnectJitter, &n.ReconnectAlertAttempts, &altServers, &n.SendBurst, &n.SendDelay, &tlsMinVersion, &tlsCA, &n.TLSSkipVerify, &n.ShareConnection, &offlineSchedule, &ident, &realName, &performCommands, &n.SplitQuarantine, &splitAction, &encoding); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
@@ -58,6 +58,18 @@ func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetw
 	n.Auth.Account = account.String
 	n.Auth.Password = password.String
 	n.BouncerAddr = bouncerAddr.String
+	n.TLSCert = tlsCert.String
+	n.TLSKey = tlsKey.String
+	n.Proxy = proxy.String
+	n.AltServers = altServers.String
+	n.TLSMinVersion = tlsMinVersion.String
+	n.TLSCA = tlsCA.String
+	n.OfflineSchedule = offlineSchedule.String
+	n.Ident = ident.String
+	n.RealName = realName.String
+	n.PerformCommands = performCommands.String
+	n.SplitAction = splitAction.String
+	n.Encoding = encoding.String
 
 	return &n, nil
 }
@@ -84,6 +96,16 @@ func (r *IrcRepo) DeleteNetwork(ctx context.Context, id int64) error {
 		return errors.Wrap(err, "error executing query")
 	}
 
+	// sqlite does not enforce the foreign key so remove the triggers of the network here
+	triggerQuery, triggerArgs, err := r.db.squirrel.Delete("irc_trigger").Where(sq.Eq{"network_id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, triggerQuery, triggerArgs...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
 	netQueryBuilder := r.db.squirrel.
 		Delete("irc_network").
 		Where(sq.Eq{"id": id})
@@ -107,7 +129,7 @@ func (r *IrcRepo) DeleteNetwork(ctx context.Context, id int64) error {
 
 func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "tls_cert", "tls_key", "proxy", "reconnect_delay", "reconnect_max_delay", "reconnect_jitter", "reconnect_alert_attempts", "alt_servers", "send_burst", "send_delay", "tls_min_version", "tls_ca", "tls_skip_verify", "share_connection", "offline_schedule", "ident", "realname", "perform_commands", "split_quarantine", "split_action", "encoding").
 		From("irc_network").
 		Where(sq.Eq{"enabled": true})
 
@@ -127,11 +149,11 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 	for rows.Next() {
 		var net domain.IrcNetwork
 
-		var pass, nick, inviteCmd, bouncerAddr sql.NullString
+		var pass, nick, inviteCmd, bouncerAddr, tlsCert, tlsKey, proxy, altServers, tlsMinVersion, tlsCA, offlineSchedule, ident, realName, performCommands, splitAction, encoding sql.NullString
 		var account, password sql.NullString
 		var tls sql.NullBool
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &tlsCert, &tlsKey, &proxy, &net.ReconnectDelay, &net.ReconnectMaxDelay, &net.ReconnectJitter, &net.ReconnectAlertAttempts, &altServers, &net.SendBurst, &net.SendDelay, &tlsMinVersion, &tlsCA, &net.TLSSkipVerify, &net.ShareConnection, &offlineSchedule, &ident, &realName, &performCommands, &net.SplitQuarantine, &splitAction, &encoding); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -140,6 +162,18 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 		net.Nick = nick.String
 		net.InviteCommand = inviteCmd.String
 		net.BouncerAddr = bouncerAddr.String
+		net.TLSCert = tlsCert.String
+		net.TLSKey = tlsKey.String
+		net.Proxy = proxy.String
+		net.AltServers = altServers.String
+		net.TLSMinVersion = tlsMinVersion.String
+		net.TLSCA = tlsCA.String
+		net.OfflineSchedule = offlineSchedule.String
+		net.Ident = ident.String
+		net.RealName = realName.String
+		net.PerformCommands = performCommands.String
+		net.SplitAction = splitAction.String
+		net.Encoding = encoding.String
 
 		net.Auth.Account = account.String
 		net.Auth.Password = password.String
@@ -155,7 +189,7 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 
 func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "tls_cert", "tls_key", "proxy", "reconnect_delay", "reconnect_max_delay", "reconnect_jitter", "reconnect_alert_attempts", "alt_servers", "send_burst", "send_delay", "tls_min_version", "tls_ca", "tls_skip_verify", "share_connection", "offline_schedule", "ident", "realname", "perform_commands", "split_quarantine", "split_action", "encoding").
 		From("irc_network").
 		OrderBy("name ASC")
 
@@ -175,11 +209,11 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 	for rows.Next() {
 		var net domain.IrcNetwork
 
-		var pass, nick, inviteCmd, bouncerAddr sql.NullString
+		var pass, nick, inviteCmd, bouncerAddr, tlsCert, tlsKey, proxy, altServers, tlsMinVersion, tlsCA, offlineSchedule, ident, realName, performCommands, splitAction, encoding sql.NullString
 		var account, password sql.NullString
 		var tls sql.NullBool
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &tlsCert, &tlsKey, &proxy, &net.ReconnectDelay, &net.ReconnectMaxDelay, &net.ReconnectJitter, &net.ReconnectAlertAttempts, &altServers, &net.SendBurst, &net.SendDelay, &tlsMinVersion, &tlsCA, &net.TLSSkipVerify, &net.ShareConnection, &offlineSchedule, &ident, &realName, &performCommands, &net.SplitQuarantine, &splitAction, &encoding); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -188,6 +222,18 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 		net.Nick = nick.String
 		net.InviteCommand = inviteCmd.String
 		net.BouncerAddr = bouncerAddr.String
+		net.TLSCert = tlsCert.String
+		net.TLSKey = tlsKey.String
+		net.Proxy = proxy.String
+		net.AltServers = altServers.String
+		net.TLSMinVersion = tlsMinVersion.String
+		net.TLSCA = tlsCA.String
+		net.OfflineSchedule = offlineSchedule.String
+		net.Ident = ident.String
+		net.RealName = realName.String
+		net.PerformCommands = performCommands.String
+		net.SplitAction = splitAction.String
+		net.Encoding = encoding.String
 
 		net.Auth.Account = account.String
 		net.Auth.Password = password.String
@@ -203,7 +249,7 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 
 func (r *IrcRepo) ListChannels(networkID int64) ([]domain.IrcChannel, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "name", "enabled", "password").
+		Select("id", "name", "enabled", "password", "announcers").
 		From("irc_channel").
 		Where(sq.Eq{"network_id": networkID})
 
@@ -221,13 +267,14 @@ func (r *IrcRepo) ListChannels(networkID int64) ([]domain.IrcChannel, error) {
 	var channels []domain.IrcChannel
 	for rows.Next() {
 		var ch domain.IrcChannel
-		var pass sql.NullString
+		var pass, announcers sql.NullString
 
-		if err := rows.Scan(&ch.ID, &ch.Name, &ch.Enabled, &pass); err != nil {
+		if err := rows.Scan(&ch.ID, &ch.Name, &ch.Enabled, &pass, &announcers); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		ch.Password = pass.String
+		ch.Announcers = announcers.String
 
 		channels = append(channels, ch)
 	}
@@ -240,11 +287,15 @@ func (r *IrcRepo) ListChannels(networkID int64) ([]domain.IrcChannel, error) {
 
 func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcNetwork) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "nick", "auth_mechanism", "auth_account", "auth_password", "invite_command", "bouncer_addr", "use_bouncer", "tls_cert", "tls_key", "proxy", "reconnect_delay", "reconnect_max_delay", "reconnect_jitter", "reconnect_alert_attempts", "alt_servers", "send_burst", "send_delay", "tls_min_version", "tls_ca", "tls_skip_verify", "share_connection", "offline_schedule", "ident", "realname", "perform_commands", "split_quarantine", "split_action", "encoding").
 		From("irc_network").
 		Where(sq.Eq{"server": network.Server}).
 		Where(sq.Eq{"port": network.Port}).
-		Where(sq.Eq{"nick": network.Nick})
+		// a network that shares its connection is used for other nicks when there is no network for the nick
+		Where(sq.Or{sq.Eq{"nick": network.Nick}, sq.Eq{"share_connection": true}}).
+		OrderByClause("CASE WHEN nick = ? THEN 0 ELSE 1 END", network.Nick).
+		OrderBy("id").
+		Limit(1)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -256,11 +307,11 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 
 	var net domain.IrcNetwork
 
-	var pass, nick, inviteCmd, bouncerAddr sql.NullString
+	var pass, nick, inviteCmd, bouncerAddr, tlsCert, tlsKey, proxy, altServers, tlsMinVersion, tlsCA, offlineSchedule, ident, realName, performCommands, splitAction, encoding sql.NullString
 	var account, password sql.NullString
 	var tls sql.NullBool
 
-	if err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer); err != nil {
+	if err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &nick, &net.Auth.Mechanism, &account, &password, &inviteCmd, &bouncerAddr, &net.UseBouncer, &tlsCert, &tlsKey, &proxy, &net.ReconnectDelay, &net.ReconnectMaxDelay, &net.ReconnectJitter, &net.ReconnectAlertAttempts, &altServers, &net.SendBurst, &net.SendDelay, &tlsMinVersion, &tlsCA, &net.TLSSkipVerify, &net.ShareConnection, &offlineSchedule, &ident, &realName, &performCommands, &net.SplitQuarantine, &splitAction, &encoding); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// no result is not an error in our case
 			return nil, nil
@@ -274,6 +325,18 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 	net.Nick = nick.String
 	net.InviteCommand = inviteCmd.String
 	net.BouncerAddr = bouncerAddr.String
+	net.TLSCert = tlsCert.String
+	net.TLSKey = tlsKey.String
+	net.Proxy = proxy.String
+	net.AltServers = altServers.String
+	net.TLSMinVersion = tlsMinVersion.String
+	net.TLSCA = tlsCA.String
+	net.OfflineSchedule = offlineSchedule.String
+	net.Ident = ident.String
+	net.RealName = realName.String
+	net.PerformCommands = performCommands.String
+	net.SplitAction = splitAction.String
+	net.Encoding = encoding.String
 	net.Auth.Account = account.String
 	net.Auth.Password = password.String
 
@@ -308,6 +371,27 @@ func (r *IrcRepo) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 			"invite_command",
 			"bouncer_addr",
 			"use_bouncer",
+			"tls_cert",
+			"tls_key",
+			"proxy",
+			"reconnect_delay",
+			"reconnect_max_delay",
+			"reconnect_jitter",
+			"reconnect_alert_attempts",
+			"alt_servers",
+			"send_burst",
+			"send_delay",
+			"tls_min_version",
+			"tls_ca",
+			"tls_skip_verify",
+			"share_connection",
+			"offline_schedule",
+			"ident",
+			"realname",
+			"perform_commands",
+			"split_quarantine",
+			"split_action",
+			"encoding",
 		).
 		Values(
 			network.Enabled,
@@ -323,6 +407,27 @@ func (r *IrcRepo) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 			inviteCmd,
 			bouncerAddr,
 			network.UseBouncer,
+			toNullString(network.TLSCert),
+			toNullString(network.TLSKey),
+			toNullString(network.Proxy),
+			network.ReconnectDelay,
+			network.ReconnectMaxDelay,
+			network.ReconnectJitter,
+			network.ReconnectAlertAttempts,
+			toNullString(network.AltServers),
+			network.SendBurst,
+			network.SendDelay,
+			toNullString(network.TLSMinVersion),
+			toNullString(network.TLSCA),
+			network.TLSSkipVerify,
+			network.ShareConnection,
+			toNullString(network.OfflineSchedule),
+			toNullString(network.Ident),
+			toNullString(network.RealName),
+			toNullString(network.PerformCommands),
+			network.SplitQuarantine,
+			toNullString(network.SplitAction),
+			toNullString(network.Encoding),
 		).
 		Suffix("RETURNING id").
 		RunWith(r.db.handler)
@@ -363,6 +468,27 @@ func (r *IrcRepo) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 		Set("invite_command", inviteCmd).
 		Set("bouncer_addr", bouncerAddr).
 		Set("use_bouncer", network.UseBouncer).
+		Set("tls_cert", toNullString(network.TLSCert)).
+		Set("tls_key", toNullString(network.TLSKey)).
+		Set("proxy", toNullString(network.Proxy)).
+		Set("reconnect_delay", network.ReconnectDelay).
+		Set("reconnect_max_delay", network.ReconnectMaxDelay).
+		Set("reconnect_jitter", network.ReconnectJitter).
+		Set("reconnect_alert_attempts", network.ReconnectAlertAttempts).
+		Set("alt_servers", toNullString(network.AltServers)).
+		Set("send_burst", network.SendBurst).
+		Set("send_delay", network.SendDelay).
+		Set("tls_min_version", toNullString(network.TLSMinVersion)).
+		Set("tls_ca", toNullString(network.TLSCA)).
+		Set("tls_skip_verify", network.TLSSkipVerify).
+		Set("share_connection", network.ShareConnection).
+		Set("offline_schedule", toNullString(network.OfflineSchedule)).
+		Set("ident", toNullString(network.Ident)).
+		Set("realname", toNullString(network.RealName)).
+		Set("perform_commands", toNullString(network.PerformCommands)).
+		Set("split_quarantine", network.SplitQuarantine).
+		Set("split_action", toNullString(network.SplitAction)).
+		Set("encoding", toNullString(network.Encoding)).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where(sq.Eq{"id": network.ID})
 
@@ -413,6 +539,7 @@ func (r *IrcRepo) StoreNetworkChannels(ctx context.Context, networkID int64, cha
 				"detached",
 				"name",
 				"password",
+				"announcers",
 				"network_id",
 			).
 			Values(
@@ -420,6 +547,7 @@ func (r *IrcRepo) StoreNetworkChannels(ctx context.Context, networkID int64, cha
 				true,
 				channel.Name,
 				pass,
+				toNullString(channel.Announcers),
 				networkID,
 			).
 			Suffix("RETURNING id").
@@ -467,6 +595,7 @@ func (r *IrcRepo) StoreChannel(ctx context.Context, networkID int64, channel *do
 			Set("detached", channel.Detached).
 			Set("name", channel.Name).
 			Set("pass", pass).
+			Set("announcers", toNullString(channel.Announcers)).
 			Where(sq.Eq{"id": channel.ID})
 
 		query, args, err := channelQueryBuilder.ToSql()
@@ -485,6 +614,7 @@ func (r *IrcRepo) StoreChannel(ctx context.Context, networkID int64, channel *do
 				"detached",
 				"name",
 				"password",
+				"announcers",
 				"network_id",
 			).
 			Values(
@@ -492,6 +622,7 @@ func (r *IrcRepo) StoreChannel(ctx context.Context, networkID int64, channel *do
 				true,
 				channel.Name,
 				pass,
+				toNullString(channel.Announcers),
 				networkID,
 			).
 			Suffix("RETURNING id").
@@ -535,6 +666,7 @@ func (r *IrcRepo) UpdateChannel(channel *domain.IrcChannel) error {
 		Set("detached", channel.Detached).
 		Set("name", channel.Name).
 		Set("pass", pass).
+		Set("announcers", toNullString(channel.Announcers)).
 		Where(sq.Eq{"id": channel.ID})
 
 	query, args, err := channelQueryBuilder.ToSql()
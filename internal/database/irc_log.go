@@ -0,0 +1,157 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type IrcLogRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewIrcLogRepo(log logger.Logger, db *DB) domain.IrcLogRepo {
+	return &IrcLogRepo{
+		log: log.With().Str("repo", "irc_log").Logger(),
+		db:  db,
+	}
+}
+
+// Store inserts the lines in a single statement
+func (r *IrcLogRepo) Store(ctx context.Context, lines []domain.IrcLogLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("irc_log").
+		Columns("network_id", "channel", "nick", "message", "timestamp")
+
+	for _, line := range lines {
+		queryBuilder = queryBuilder.Values(line.NetworkID, strings.ToLower(line.Channel), line.Nick, line.Message, line.Timestamp.UTC())
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *IrcLogRepo) Find(ctx context.Context, params domain.IrcLogQueryParams) ([]domain.IrcLogLine, int64, error) {
+	where := sq.And{sq.Eq{"network_id": params.NetworkID}}
+
+	if params.Channel != "" {
+		where = append(where, sq.Eq{"channel": strings.ToLower(params.Channel)})
+	}
+
+	if params.Nick != "" {
+		where = append(where, ILike("nick", params.Nick))
+	}
+
+	if params.Search != "" {
+		where = append(where, ILike("message", "%"+params.Search+"%"))
+	}
+
+	if !params.After.IsZero() {
+		where = append(where, sq.GtOrEq{"timestamp": params.After.UTC()})
+	}
+
+	if !params.Before.IsZero() {
+		where = append(where, sq.Lt{"timestamp": params.Before.UTC()})
+	}
+
+	countQuery, countArgs, err := r.db.squirrel.Select("COUNT(*)").From("irc_log").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "error building count query")
+	}
+
+	var count int64
+	if err := r.db.handler.QueryRowContext(ctx, countQuery, countArgs...).Scan(&count); err != nil {
+		return nil, 0, errors.Wrap(err, "error executing count query")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Select("id", "network_id", "channel", "nick", "message", "timestamp").
+		From("irc_log").
+		Where(where).
+		OrderBy("timestamp DESC", "id DESC")
+
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	} else {
+		queryBuilder = queryBuilder.Limit(100)
+	}
+
+	if params.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(params.Offset)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	lines := make([]domain.IrcLogLine, 0)
+	for rows.Next() {
+		var line domain.IrcLogLine
+
+		if err := rows.Scan(&line.ID, &line.NetworkID, &line.Channel, &line.Nick, &line.Message, &line.Timestamp); err != nil {
+			return nil, 0, errors.Wrap(err, "error scanning row")
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.Wrap(err, "row error")
+	}
+
+	return lines, count, nil
+}
+
+// DeleteOlderThan removes the lines logged before t and returns how many were removed
+func (r *IrcLogRepo) DeleteOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	query, args, err := r.db.squirrel.
+		Delete("irc_log").
+		Where(sq.Lt{"timestamp": t.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type IrcTriggerRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewIrcTriggerRepo(log logger.Logger, db *DB) domain.IrcTriggerRepo {
+	return &IrcTriggerRepo{
+		log: log.With().Str("repo", "irc_trigger").Logger(),
+		db:  db,
+	}
+}
+
+func (r *IrcTriggerRepo) selectTriggers() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select("id", "network_id", "name", "enabled", "channel", "pattern", "action", "flag", "flag_duration", "created_at", "updated_at").
+		From("irc_trigger")
+}
+
+func scanIrcTrigger(row interface{ Scan(...interface{}) error }) (*domain.IrcTrigger, error) {
+	var t domain.IrcTrigger
+	var flag sql.NullString
+
+	if err := row.Scan(&t.ID, &t.NetworkID, &t.Name, &t.Enabled, &t.Channel, &t.Pattern, &t.Action, &flag, &t.FlagDuration, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	t.Flag = flag.String
+
+	return &t, nil
+}
+
+// List returns the triggers of the network, or of all networks when networkID is 0
+func (r *IrcTriggerRepo) List(ctx context.Context, networkID int64) ([]domain.IrcTrigger, error) {
+	queryBuilder := r.selectTriggers().OrderBy("name ASC")
+
+	if networkID != 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"network_id": networkID})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	triggers := make([]domain.IrcTrigger, 0)
+	for rows.Next() {
+		t, err := scanIrcTrigger(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		triggers = append(triggers, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return triggers, nil
+}
+
+func (r *IrcTriggerRepo) FindByID(ctx context.Context, id int64) (*domain.IrcTrigger, error) {
+	query, args, err := r.selectTriggers().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	t, err := scanIrcTrigger(r.db.handler.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("no trigger found with id: %d", id)
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return t, nil
+}
+
+func (r *IrcTriggerRepo) Store(ctx context.Context, trigger *domain.IrcTrigger) error {
+	queryBuilder := r.db.squirrel.
+		Insert("irc_trigger").
+		Columns("network_id", "name", "enabled", "channel", "pattern", "action", "flag", "flag_duration").
+		Values(trigger.NetworkID, trigger.Name, trigger.Enabled, trigger.Channel, trigger.Pattern, trigger.Action, toNullString(trigger.Flag), trigger.FlagDuration).
+		Suffix("RETURNING id").
+		RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&trigger.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *IrcTriggerRepo) Update(ctx context.Context, trigger *domain.IrcTrigger) error {
+	queryBuilder := r.db.squirrel.
+		Update("irc_trigger").
+		Set("name", trigger.Name).
+		Set("enabled", trigger.Enabled).
+		Set("channel", trigger.Channel).
+		Set("pattern", trigger.Pattern).
+		Set("action", trigger.Action).
+		Set("flag", toNullString(trigger.Flag)).
+		Set("flag_duration", trigger.FlagDuration).
+		Set("updated_at", time.Now().Format(time.RFC3339)).
+		Where(sq.Eq{"id": trigger.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *IrcTriggerRepo) Delete(ctx context.Context, id int64) error {
+	query, args, err := r.db.squirrel.Delete("irc_trigger").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type TableStats struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+	// Size in bytes including indexes, 0 if unknown
+	Size int64 `json:"size"`
+}
+
+// Vacuum reclaims unused space and updates the query planner statistics
+func (db *DB) Vacuum(ctx context.Context) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var queries []string
+
+	switch db.Driver {
+	case "sqlite":
+		queries = []string{`VACUUM`, `ANALYZE`}
+	case "postgres":
+		queries = []string{`VACUUM ANALYZE`}
+	}
+
+	for _, query := range queries {
+		if _, err := db.handler.ExecContext(ctx, query); err != nil {
+			return errors.Wrap(err, "error executing query: %s", query)
+		}
+	}
+
+	return nil
+}
+
+// Check runs the integrity checks for the database and returns the problems found.
+// An empty list means the database is healthy.
+func (db *DB) Check(ctx context.Context) ([]string, error) {
+	switch db.Driver {
+	case "sqlite":
+		return db.checkSQLite(ctx)
+	case "postgres":
+		return db.checkPostgres(ctx)
+	}
+
+	return nil, errors.New("unsupported database: %v", db.Driver)
+}
+
+func (db *DB) checkSQLite(ctx context.Context) ([]string, error) {
+	var problems []string
+
+	rows, err := db.handler.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing integrity check")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	// foreign keys are not enforced so rows can reference deleted data
+	fkRows, err := db.handler.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing foreign key check")
+	}
+
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+
+		if err := fkRows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		problems = append(problems, fmt.Sprintf("%s row %d references missing row in %s", table, rowID.Int64, parent))
+	}
+	if err := fkRows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return problems, nil
+}
+
+func (db *DB) checkPostgres(ctx context.Context) ([]string, error) {
+	var problems []string
+
+	// postgres enforces constraints, so only look for indexes left invalid by failed builds
+	rows, err := db.handler.QueryContext(ctx, `SELECT indexrelid::regclass::text FROM pg_index WHERE NOT indisvalid`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		problems = append(problems, fmt.Sprintf("index %s is invalid", index))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return problems, nil
+}
+
+// TableStats returns row counts and sizes for all tables
+func (db *DB) TableStats(ctx context.Context) ([]TableStats, error) {
+	stats := make([]TableStats, 0, len(migrateTables))
+
+	for _, table := range migrateTables {
+		s := TableStats{Name: table}
+
+		if err := db.handler.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, quoteIdent(table))).Scan(&s.Rows); err != nil {
+			return nil, errors.Wrap(err, "could not count rows in table: %s", table)
+		}
+
+		size, err := db.tableSize(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Size = size
+
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+func (db *DB) tableSize(ctx context.Context, table string) (int64, error) {
+	var size sql.NullInt64
+
+	switch db.Driver {
+	case "sqlite":
+		// dbstat is an optional virtual table, report unknown size if it is not available
+		query := `SELECT SUM(pgsize) FROM dbstat WHERE name = $1 OR name IN (SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = $1)`
+		if err := db.handler.QueryRowContext(ctx, query, table).Scan(&size); err != nil {
+			db.log.Debug().Err(err).Msgf("could not get size of table: %s", table)
+			return 0, nil
+		}
+
+	case "postgres":
+		if err := db.handler.QueryRowContext(ctx, `SELECT pg_total_relation_size($1)`, quoteIdent(table)).Scan(&size); err != nil {
+			return 0, errors.Wrap(err, "could not get size of table: %s", table)
+		}
+	}
+
+	return size.Int64, nil
+}
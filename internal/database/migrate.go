@@ -0,0 +1,756 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// migrateTables lists all tables in foreign key dependency order.
+// irc_log is left out on purpose, it is a rolling log of channel lines and not configuration.
+var migrateTables = []string{
+	"users",
+	"indexer",
+	"irc_network",
+	"irc_channel",
+	"irc_trigger",
+	"filter_group",
+	"filter_template",
+	"filter",
+	"filter_external",
+	"filter_list_source",
+	"filter_revision",
+	"filter_stats",
+	"filter_stats_rejection",
+	"filter_indexer",
+	"filter_group_indexer",
+	"client",
+	"action",
+	"release",
+	"release_action_status",
+	"release_hold",
+	"notification",
+	"feed",
+	"feed_cache",
+	"api_key",
+}
+
+// NewDBFromDSN creates a DB for the given driver and dsn without reading them from the config.
+// For sqlite the dsn is the path to the database file.
+func NewDBFromDSN(log logger.Logger, driver string, dsn string) (*DB, error) {
+	switch driver {
+	case "sqlite", "postgres":
+	default:
+		return nil, errors.New("unsupported database: %v", driver)
+	}
+
+	db := &DB{
+		squirrel: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		log:      log.With().Str("module", "database").Logger(),
+		Driver:   driver,
+		DSN:      dsn,
+	}
+	db.ctx, db.cancel = context.WithCancel(context.Background())
+
+	return db, nil
+}
+
+// migrateBatchSize is the number of rows read and inserted per statement
+const migrateBatchSize = 500
+
+// migrateMaxParams keeps batches below the bind parameter limits of sqlite (32766) and postgres (65535)
+const migrateMaxParams = 30000
+
+// migrateCheckpointTable stores the progress per table in the destination so an interrupted migration can be resumed.
+// It is written in the same transaction as the rows and dropped when the migration completes.
+const migrateCheckpointTable = "autobrr_migrate_checkpoint"
+
+// MigrateProgress is reported after every batch
+type MigrateProgress struct {
+	Table  string
+	Copied int64
+	Total  int64
+}
+
+// MigrateTableResult compares the row counts of a table after the migration
+type MigrateTableResult struct {
+	Table       string `json:"table"`
+	Source      int64  `json:"source"`
+	Migrated    int64  `json:"migrated"`
+	Skipped     int64  `json:"skipped"`
+	Destination int64  `json:"destination"`
+}
+
+type migrateCheckpoint struct {
+	table   string
+	lastID  int64
+	copied  int64
+	skipped int64
+	done    bool
+}
+
+// Migrator copies all data between two databases with the same schema version.
+// Both databases must be opened, which makes sure the schema is migrated to the latest version.
+//
+// Tables with an id column are copied in batches ordered by id with a checkpoint per batch,
+// other tables are copied in a single transaction.
+type Migrator struct {
+	log zerolog.Logger
+	src *DB
+	dst *DB
+
+	// Resume continues an interrupted migration instead of requiring an empty destination
+	Resume bool
+
+	// Progress is called after every batch if set
+	Progress func(p MigrateProgress)
+}
+
+func NewMigrator(log logger.Logger, src *DB, dst *DB) *Migrator {
+	return &Migrator{
+		log: log.With().Str("module", "migrator").Logger(),
+		src: src,
+		dst: dst,
+	}
+}
+
+func (m *Migrator) Migrate(ctx context.Context) ([]MigrateTableResult, error) {
+	if m.src.Driver == m.dst.Driver {
+		return nil, errors.New("source and destination use the same driver: %s", m.src.Driver)
+	}
+
+	checkpoints, found, err := m.loadCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Resume {
+		if !found {
+			return nil, errors.New("no interrupted migration found in destination")
+		}
+	} else {
+		if found {
+			return nil, errors.New("destination contains an interrupted migration, resume it or start over with an empty database")
+		}
+
+		if err := m.checkEmpty(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := m.createCheckpointTable(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]MigrateTableResult, 0, len(migrateTables))
+
+	for _, table := range migrateTables {
+		cp, ok := checkpoints[table]
+		if !ok {
+			cp = &migrateCheckpoint{table: table}
+		}
+
+		source, err := m.src.countRows(ctx, table)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not count rows in source table: %s", table)
+		}
+
+		if cp.done {
+			m.log.Info().Msgf("table %s already migrated, skipping", table)
+		} else if err := m.migrateTable(ctx, table, cp, source); err != nil {
+			return nil, errors.Wrap(err, "could not migrate table: %s", table)
+		}
+
+		destination, err := m.dst.countRows(ctx, table)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not count rows in destination table: %s", table)
+		}
+
+		results = append(results, MigrateTableResult{
+			Table:       table,
+			Source:      source,
+			Migrated:    cp.copied,
+			Skipped:     cp.skipped,
+			Destination: destination,
+		})
+	}
+
+	if m.dst.Driver == "postgres" {
+		if err := m.dst.resetSequences(ctx); err != nil {
+			return nil, errors.Wrap(err, "could not reset sequences")
+		}
+	}
+
+	if _, err := m.dst.handler.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, migrateCheckpointTable)); err != nil {
+		return nil, errors.Wrap(err, "could not drop checkpoint table")
+	}
+
+	return results, nil
+}
+
+// checkEmpty makes sure we do not mix existing data with migrated data
+func (m *Migrator) checkEmpty(ctx context.Context) error {
+	if err := m.dst.CheckEmpty(ctx); err != nil {
+		return errors.Wrap(err, "destination")
+	}
+
+	return nil
+}
+
+func (m *Migrator) createCheckpointTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE %s (
+		table_name TEXT PRIMARY KEY,
+		last_id    BIGINT  NOT NULL DEFAULT 0,
+		copied     BIGINT  NOT NULL DEFAULT 0,
+		skipped    BIGINT  NOT NULL DEFAULT 0,
+		done       BOOLEAN NOT NULL DEFAULT FALSE
+	)`, migrateCheckpointTable)
+
+	if _, err := m.dst.handler.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, "could not create checkpoint table")
+	}
+
+	return nil
+}
+
+// loadCheckpoints returns the checkpoints of an interrupted migration and whether the checkpoint table exists
+func (m *Migrator) loadCheckpoints(ctx context.Context) (map[string]*migrateCheckpoint, bool, error) {
+	exists, err := m.dst.tableExists(ctx, migrateCheckpointTable)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not check for checkpoint table")
+	}
+
+	if !exists {
+		return nil, false, nil
+	}
+
+	queryBuilder := m.dst.squirrel.
+		Select("table_name", "last_id", "copied", "skipped", "done").
+		From(migrateCheckpointTable)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := m.dst.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	checkpoints := make(map[string]*migrateCheckpoint)
+	for rows.Next() {
+		var cp migrateCheckpoint
+		if err := rows.Scan(&cp.table, &cp.lastID, &cp.copied, &cp.skipped, &cp.done); err != nil {
+			return nil, false, errors.Wrap(err, "error scanning row")
+		}
+
+		checkpoints[cp.table] = &cp
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, errors.Wrap(err, "error rows")
+	}
+
+	return checkpoints, true, nil
+}
+
+func (m *Migrator) saveCheckpoint(ctx context.Context, tx *sql.Tx, cp *migrateCheckpoint) error {
+	queryBuilder := m.dst.squirrel.
+		Insert(migrateCheckpointTable).
+		Columns("table_name", "last_id", "copied", "skipped", "done").
+		Values(cp.table, cp.lastID, cp.copied, cp.skipped, cp.done).
+		Suffix("ON CONFLICT (table_name) DO UPDATE SET last_id = EXCLUDED.last_id, copied = EXCLUDED.copied, skipped = EXCLUDED.skipped, done = EXCLUDED.done")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "could not save checkpoint")
+	}
+
+	return nil
+}
+
+func (m *Migrator) migrateTable(ctx context.Context, table string, cp *migrateCheckpoint, total int64) error {
+	dstColumns, err := m.dst.columnTypes(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	srcColumns, err := m.src.columnTypes(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	_, srcID := srcColumns["id"]
+	_, dstID := dstColumns["id"]
+
+	if srcID && dstID {
+		err = m.migrateBatches(ctx, table, dstColumns, cp, total)
+	} else {
+		err = m.migrateAll(ctx, table, dstColumns, cp, total)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.log.Info().Msgf("migrated table %s: %d rows, %d skipped", table, cp.copied, cp.skipped)
+
+	return nil
+}
+
+// migrateBatches copies the table in batches ordered by id and commits a checkpoint with every batch
+func (m *Migrator) migrateBatches(ctx context.Context, table string, dstColumns map[string]string, cp *migrateCheckpoint, total int64) error {
+	for {
+		query := fmt.Sprintf(`SELECT * FROM %s WHERE id > $1 ORDER BY id LIMIT %d`, quoteIdent(table), migrateBatchSize)
+
+		columns, values, err := m.src.readRows(ctx, query, cp.lastID)
+		if err != nil {
+			return err
+		}
+
+		tx, err := m.dst.handler.BeginTx(ctx, nil)
+		if err != nil {
+			return errors.Wrap(err, "error begin transaction")
+		}
+
+		if len(values) == 0 {
+			cp.done = true
+		} else {
+			migrated, skipped, err := m.insertRows(ctx, tx, table, columns, dstColumns, values)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			cp.copied += migrated
+			cp.skipped += skipped
+			cp.lastID = toInt64(values[len(values)-1][indexOf(columns, "id")])
+		}
+
+		if err := m.saveCheckpoint(ctx, tx, cp); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return errors.Wrap(err, "error commit transaction")
+		}
+
+		if cp.done {
+			return nil
+		}
+
+		m.progress(table, cp, total)
+	}
+}
+
+// migrateAll copies a table without an id column in a single transaction
+func (m *Migrator) migrateAll(ctx context.Context, table string, dstColumns map[string]string, cp *migrateCheckpoint, total int64) error {
+	columns, values, err := m.src.readRows(ctx, fmt.Sprintf(`SELECT * FROM %s`, quoteIdent(table)))
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.dst.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	// a previous attempt rolled back completely, so start from zero
+	cp.copied, cp.skipped = 0, 0
+
+	for start := 0; start < len(values); start += migrateBatchSize {
+		end := start + migrateBatchSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		migrated, skipped, err := m.insertRows(ctx, tx, table, columns, dstColumns, values[start:end])
+		if err != nil {
+			return err
+		}
+
+		cp.copied += migrated
+		cp.skipped += skipped
+
+		m.progress(table, cp, total)
+	}
+
+	cp.done = true
+
+	if err := m.saveCheckpoint(ctx, tx, cp); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	return nil
+}
+
+func (m *Migrator) progress(table string, cp *migrateCheckpoint, total int64) {
+	if m.Progress != nil {
+		m.Progress(MigrateProgress{Table: table, Copied: cp.copied + cp.skipped, Total: total})
+	}
+}
+
+// insertRows inserts the rows with multi row inserts. If a batch fails the rows are retried one by one
+// so rows with a foreign key violation can be skipped, any other error is returned.
+func (m *Migrator) insertRows(ctx context.Context, tx *sql.Tx, table string, srcColumns []string, dstColumns map[string]string, values [][]any) (int64, int64, error) {
+	// only copy columns that exist on both sides
+	columns := make([]string, 0, len(srcColumns))
+	indexes := make([]int, 0, len(srcColumns))
+	for i, column := range srcColumns {
+		if _, ok := dstColumns[column]; ok {
+			columns = append(columns, quoteIdent(column))
+			indexes = append(indexes, i)
+		}
+	}
+
+	rows := make([][]any, 0, len(values))
+	for _, row := range values {
+		args := make([]any, 0, len(indexes))
+		for _, i := range indexes {
+			args = append(args, convertValue(row[i], dstColumns[srcColumns[i]], m.dst.Driver))
+		}
+
+		rows = append(rows, args)
+	}
+
+	batchSize := migrateMaxParams / len(columns)
+	if batchSize > migrateBatchSize {
+		batchSize = migrateBatchSize
+	}
+
+	var migrated, skipped int64
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		batch := rows[start:end]
+
+		err := m.withSavepoint(ctx, tx, "migrate_batch", func() error {
+			return m.insert(ctx, tx, table, columns, batch...)
+		})
+		if err == nil {
+			migrated += int64(len(batch))
+			continue
+		}
+
+		m.log.Debug().Err(err).Msgf("batch insert into %s failed, retrying row by row", table)
+
+		for _, row := range batch {
+			err := m.withSavepoint(ctx, tx, "migrate_row", func() error {
+				return m.insert(ctx, tx, table, columns, row)
+			})
+			if err != nil {
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+					m.log.Warn().Msgf("skipping row in table %s with foreign key violation: %v", table, pqErr.Detail)
+					skipped++
+					continue
+				}
+
+				return migrated, skipped, errors.Wrap(err, "could not insert row")
+			}
+
+			migrated++
+		}
+	}
+
+	return migrated, skipped, nil
+}
+
+func (m *Migrator) insert(ctx context.Context, tx *sql.Tx, table string, columns []string, rows ...[]any) error {
+	queryBuilder := m.dst.squirrel.
+		Insert(quoteIdent(table)).
+		Columns(columns...)
+
+	for _, row := range rows {
+		queryBuilder = queryBuilder.Values(row...)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// withSavepoint runs fn inside a savepoint and rolls back to it on error.
+// Postgres aborts the whole transaction on a failed statement unless we do this.
+func (m *Migrator) withSavepoint(ctx context.Context, tx *sql.Tx, name string, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT `+name); err != nil {
+		return errors.Wrap(err, "could not create savepoint")
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT `+name); rbErr != nil {
+			return errors.Wrap(rbErr, "could not rollback to savepoint")
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT `+name); err != nil {
+		return errors.Wrap(err, "could not release savepoint")
+	}
+
+	return nil
+}
+
+// CheckEmpty returns an error if any of the data tables contains rows
+func (db *DB) CheckEmpty(ctx context.Context) error {
+	for _, table := range migrateTables {
+		count, err := db.countRows(ctx, table)
+		if err != nil {
+			return errors.Wrap(err, "could not count rows in table: %s", table)
+		}
+
+		if count > 0 {
+			return errors.New("table %s is not empty", table)
+		}
+	}
+
+	return nil
+}
+
+// Truncate deletes the rows of all data tables in a single transaction
+func (db *DB) Truncate(ctx context.Context) error {
+	tx, err := db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	// reverse dependency order so no row is deleted before the rows referencing it
+	for i := len(migrateTables) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s`, quoteIdent(migrateTables[i]))); err != nil {
+			return errors.Wrap(err, "could not delete rows in table: %s", migrateTables[i])
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	return nil
+}
+
+// resetSequences sets the postgres serial sequences to the max id so new rows do not collide with copied ones
+func (db *DB) resetSequences(ctx context.Context) error {
+	for _, table := range migrateTables {
+		var sequence sql.NullString
+		if err := db.handler.QueryRowContext(ctx, `SELECT pg_get_serial_sequence($1, 'id')`, quoteIdent(table)).Scan(&sequence); err != nil {
+			// table does not have an id column
+			continue
+		}
+
+		if !sequence.Valid {
+			continue
+		}
+
+		query := fmt.Sprintf(`SELECT setval($1, COALESCE((SELECT MAX(id) FROM %s), 0) + 1, false)`, quoteIdent(table))
+		if _, err := db.handler.ExecContext(ctx, query, sequence.String); err != nil {
+			return errors.Wrap(err, "could not reset sequence for table: %s", table)
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) tableExists(ctx context.Context, table string) (bool, error) {
+	var query string
+
+	switch db.Driver {
+	case "sqlite":
+		query = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)`
+	case "postgres":
+		query = `SELECT to_regclass($1) IS NOT NULL`
+	}
+
+	var exists bool
+	if err := db.handler.QueryRowContext(ctx, query, table).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (db *DB) countRows(ctx context.Context, table string) (int64, error) {
+	var count int64
+	if err := db.handler.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, quoteIdent(table))).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// readRows reads all rows of query into memory with their column names
+func (db *DB) readRows(ctx context.Context, query string, args ...any) ([]string, [][]any, error) {
+	rows, err := db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get columns")
+	}
+
+	var values [][]any
+
+	for rows.Next() {
+		row := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, nil, errors.Wrap(err, "error scanning row")
+		}
+
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "error rows")
+	}
+
+	return columns, values, nil
+}
+
+// columnTypes returns the lower case column types of a table
+func (db *DB) columnTypes(ctx context.Context, table string) (map[string]string, error) {
+	var query string
+
+	switch db.Driver {
+	case "sqlite":
+		query = `SELECT name, type FROM pragma_table_info($1)`
+	case "postgres":
+		query = `SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1`
+	}
+
+	rows, err := db.handler.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get columns for table: %s", table)
+	}
+
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var name, columnType string
+		if err := rows.Scan(&name, &columnType); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		columns[name] = strings.ToLower(columnType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("table %s not found", table)
+	}
+
+	return columns, nil
+}
+
+// convertValue converts a value read from one driver into something the destination column accepts
+func convertValue(value any, columnType string, driver string) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+
+	case []byte:
+		// postgres returns arrays, json and text as bytes
+		return string(v)
+
+	case int64:
+		// sqlite stores booleans as integers
+		if driver == "postgres" && columnType == "boolean" {
+			return v != 0
+		}
+
+	case string:
+		if driver == "postgres" && strings.HasPrefix(columnType, "timestamp") {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05"} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t
+				}
+			}
+		}
+
+		if driver == "postgres" && columnType == "boolean" {
+			return v == "1" || strings.EqualFold(v, "true")
+		}
+	}
+
+	return value
+}
+
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case []byte:
+		i, _ := strconv.ParseInt(string(v), 10, 64)
+		return i
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	}
+
+	return 0
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
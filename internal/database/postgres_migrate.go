@@ -47,6 +47,27 @@ CREATE TABLE irc_network
     invite_command      TEXT,
     use_bouncer         BOOLEAN,
     bouncer_addr        TEXT,
+    tls_cert            TEXT,
+    tls_key             TEXT,
+    proxy               TEXT,
+    reconnect_delay     INTEGER DEFAULT 15,
+    reconnect_max_delay INTEGER DEFAULT 600,
+    reconnect_jitter    INTEGER DEFAULT 0,
+    reconnect_alert_attempts INTEGER DEFAULT 0,
+    alt_servers         TEXT,
+    send_burst          INTEGER DEFAULT 5,
+    send_delay          INTEGER DEFAULT 1000,
+    tls_min_version     TEXT,
+    tls_ca              TEXT,
+    tls_skip_verify     BOOLEAN DEFAULT TRUE,
+    share_connection    BOOLEAN DEFAULT FALSE,
+    offline_schedule    TEXT,
+    ident               TEXT,
+    realname            TEXT,
+    perform_commands    TEXT,
+    split_quarantine    INTEGER DEFAULT 0,
+    split_action        TEXT DEFAULT 'DELAY',
+    encoding            TEXT,
     connected           BOOLEAN,
     connected_since     TIMESTAMP,
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -61,11 +82,22 @@ CREATE TABLE irc_channel
     name        TEXT NOT NULL,
     password    TEXT,
     detached    BOOLEAN,
+    announcers  TEXT,
     network_id  INTEGER NOT NULL,
     FOREIGN KEY (network_id) REFERENCES irc_network(id),
     UNIQUE (network_id, name)
 );
 
+CREATE TABLE filter_group
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    min_size   TEXT,
+    max_size   TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE filter
 (
     id                             SERIAL PRIMARY KEY,
@@ -127,8 +159,59 @@ CREATE TABLE filter
     except_tags_match_logic        TEXT,
     origins                        TEXT []   DEFAULT '{}',
     except_origins                 TEXT []   DEFAULT '{}',
+    expression                     TEXT,
+    schedule                       TEXT,
+    use_regex_shows                BOOLEAN DEFAULT FALSE,
+    use_regex_release_groups       BOOLEAN DEFAULT FALSE,
+    use_regex_tags                 BOOLEAN DEFAULT FALSE,
+    use_regex_uploaders            BOOLEAN DEFAULT FALSE,
+    prefer_season_packs            BOOLEAN DEFAULT FALSE,
+    season_pack_hold               INTEGER DEFAULT 0,
+    proper_upgrades                BOOLEAN DEFAULT FALSE,
+    proper_upgrades_replace        BOOLEAN DEFAULT FALSE,
+    arr_upgrade_only               BOOLEAN DEFAULT FALSE,
+    freeleech_tokens               BOOLEAN DEFAULT FALSE,
+    min_seeders                    INTEGER DEFAULT 0,
+    min_leechers                   INTEGER DEFAULT 0,
+    on_reject                      TEXT,
+    on_reject_filter_id            INTEGER DEFAULT 0,
+    on_action_failure              TEXT,
+    on_action_failure_filter_id    INTEGER DEFAULT 0,
+    delay_jitter                   INTEGER DEFAULT 0,
+    max_downloads_interval         INTEGER DEFAULT 1,
+    max_downloads_per_indexer      BOOLEAN DEFAULT FALSE,
+    hdr_preference                 TEXT []   DEFAULT '{}',
+    language_preference            TEXT []   DEFAULT '{}',
+    subtitle_preference            TEXT []   DEFAULT '{}',
+    except_language_only           TEXT []   DEFAULT '{}',
+    rewrite_rules                  TEXT      DEFAULT '[]',
+    reject_dv_without_fallback     BOOLEAN DEFAULT FALSE,
+    bit_depths                     TEXT []   DEFAULT '{}',
+    sample_rates                   TEXT []   DEFAULT '{}',
+    audio_channels                 TEXT []   DEFAULT '{}',
+    absolute_episodes              TEXT,
+    air_dates                      TEXT,
+    max_air_date_age               INTEGER DEFAULT 0,
+    anime_batch                    TEXT,
+    anime_sub_type                 TEXT,
+    anime_group_tiers              TEXT,
+    anime_max_group_tier           INTEGER DEFAULT 0,
+    preset_id                      TEXT,
+    preset_source                  TEXT,
+    preset_managed                 BOOLEAN DEFAULT FALSE,
+    preset_hash                    TEXT,
+    filter_tags                    TEXT []   DEFAULT '{}',
+    verify_torrent                 BOOLEAN DEFAULT FALSE,
+    min_file_count                 INTEGER DEFAULT 0,
+    max_file_count                 INTEGER DEFAULT 0,
+    match_file_extensions          TEXT,
+    except_file_extensions         TEXT,
+    flags                          TEXT,
+    except_flags                   TEXT,
+    group_id                       INTEGER,
     created_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES filter_group (id) ON DELETE SET NULL
 );
 
 CREATE TABLE filter_external
@@ -141,15 +224,93 @@ CREATE TABLE filter_external
 	exec_cmd                TEXT,
 	exec_args               TEXT,
 	exec_expect_status      INTEGER,
+	exec_mode               TEXT,
+	exec_timeout            INTEGER,
+	exec_env                TEXT,
 	webhook_host            TEXT,
 	webhook_method          TEXT,
 	webhook_data            TEXT,
 	webhook_headers         TEXT,
 	webhook_expect_status   INTEGER,
+	webhook_retry_status    TEXT,
+	webhook_retry_attempts  INTEGER,
+	webhook_retry_delay_seconds INTEGER,
+	webhook_expect_json_path TEXT,
+	webhook_expect_json_value TEXT,
 	filter_id               INTEGER NOT NULL,
 	FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
 );
 
+CREATE TABLE filter_list_source
+(
+    id                SERIAL PRIMARY KEY,
+    filter_id         INTEGER NOT NULL,
+    field             TEXT    NOT NULL,
+    url               TEXT    NOT NULL,
+    format            TEXT    NOT NULL DEFAULT 'TEXT',
+    json_key          TEXT,
+    refresh_interval  INTEGER NOT NULL DEFAULT 60,
+    last_refreshed_at TIMESTAMP,
+    last_error        TEXT,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+
+CREATE TABLE filter_revision
+(
+    id         SERIAL PRIMARY KEY,
+    filter_id  INTEGER NOT NULL,
+    revision   INTEGER NOT NULL,
+    username   TEXT,
+    note       TEXT,
+    changes    TEXT,
+    snapshot   TEXT    NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    UNIQUE (filter_id, revision)
+);
+
+CREATE TABLE filter_stats
+(
+    filter_id INTEGER   NOT NULL,
+    bucket    TIMESTAMP NOT NULL,
+    evaluated INTEGER   NOT NULL DEFAULT 0,
+    matched   INTEGER   NOT NULL DEFAULT 0,
+    rejected  INTEGER   NOT NULL DEFAULT 0,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_id, bucket)
+);
+
+CREATE TABLE filter_stats_rejection
+(
+    filter_id INTEGER   NOT NULL,
+    bucket    TIMESTAMP NOT NULL,
+    rejection TEXT      NOT NULL,
+    count     INTEGER   NOT NULL DEFAULT 0,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_id, bucket, rejection)
+);
+
+CREATE TABLE filter_template
+(
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT,
+    variables   TEXT,
+    filter      TEXT NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE filter_group_indexer
+(
+    filter_group_id INTEGER,
+    indexer_id      INTEGER,
+    FOREIGN KEY (filter_group_id) REFERENCES filter_group (id) ON DELETE CASCADE,
+    FOREIGN KEY (indexer_id) REFERENCES indexer (id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_group_id, indexer_id)
+);
+
 CREATE TABLE filter_indexer
 (
     filter_id  INTEGER,
@@ -204,10 +365,14 @@ CREATE TABLE action
     webhook_type            TEXT,
     webhook_data            TEXT,
     webhook_headers         TEXT[] DEFAULT '{}',
+    priority                INTEGER DEFAULT 0,
+    dupe_key                TEXT,
     external_client_id      INTEGER,
     client_id               INTEGER,
     filter_id               INTEGER,
+    filter_group_id         INTEGER,
     FOREIGN KEY (filter_id) REFERENCES filter (id),
+    FOREIGN KEY (filter_group_id) REFERENCES filter_group (id) ON DELETE CASCADE,
     FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL
 );
 
@@ -303,6 +468,24 @@ CREATE TABLE release_action_status
 CREATE INDEX release_action_status_release_id_index
     ON release_action_status (release_id);
 
+CREATE TABLE release_hold
+(
+    id         SERIAL PRIMARY KEY,
+    type       TEXT      NOT NULL DEFAULT 'SEASON_PACK',
+    release_id INTEGER   NOT NULL,
+    filter_id  INTEGER   NOT NULL,
+    title      TEXT      NOT NULL,
+    season     INTEGER   NOT NULL,
+    episode    INTEGER   NOT NULL,
+    hold_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_hold_hold_until_index
+    ON release_hold (hold_until);
+
 CREATE TABLE notification
 (
 	id         SERIAL PRIMARY KEY,
@@ -347,6 +530,10 @@ CREATE TABLE feed
     indexer_id    INTEGER,
     last_run      TIMESTAMP,
     last_run_data TEXT,
+    etag          TEXT,
+    last_modified TEXT,
+    caps          TEXT,
+    priority      INTEGER DEFAULT 0 NOT NULL,
     created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (indexer_id) REFERENCES indexer(id) ON DELETE SET NULL
@@ -358,12 +545,49 @@ CREATE TABLE feed_cache
 	key     TEXT,
 	value   TEXT,
 	ttl     TIMESTAMP,
+	created TIMESTAMP,
 	FOREIGN KEY (feed_id) REFERENCES feed (id) ON DELETE cascade
 );
 
 CREATE INDEX feed_cache_feed_id_key_index
     ON feed_cache (feed_id, key);
 
+CREATE INDEX feed_cache_feed_id_created_index
+    ON feed_cache (feed_id, created);
+
+CREATE TABLE irc_log
+(
+    id         SERIAL PRIMARY KEY,
+    network_id INTEGER   NOT NULL,
+    channel    TEXT      NOT NULL,
+    nick       TEXT,
+    message    TEXT,
+    timestamp  TIMESTAMP NOT NULL,
+    FOREIGN KEY (network_id) REFERENCES irc_network(id) ON DELETE CASCADE
+);
+
+CREATE INDEX irc_log_network_id_channel_timestamp_index
+    ON irc_log (network_id, channel, timestamp);
+
+CREATE INDEX irc_log_timestamp_index
+    ON irc_log (timestamp);
+
+CREATE TABLE irc_trigger
+(
+    id            SERIAL PRIMARY KEY,
+    network_id    INTEGER NOT NULL,
+    name          TEXT    NOT NULL,
+    enabled       BOOLEAN DEFAULT TRUE,
+    channel       TEXT    NOT NULL,
+    pattern       TEXT    NOT NULL,
+    action        TEXT    NOT NULL,
+    flag          TEXT,
+    flag_duration INTEGER DEFAULT 0,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (network_id) REFERENCES irc_network(id) ON DELETE CASCADE
+);
+
 CREATE TABLE api_key
 (
 	name       TEXT,
@@ -371,6 +595,15 @@ CREATE TABLE api_key
 	scopes     TEXT []   DEFAULT '{}' NOT NULL,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE auth_recovery_token
+(
+	id         SERIAL PRIMARY KEY,
+	token_hash TEXT      NOT NULL UNIQUE,
+	expires_at TIMESTAMP NOT NULL,
+	used_at    TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
 `
 
 var postgresMigrations = []string{
@@ -797,5 +1030,417 @@ CREATE INDEX feed_cache_feed_id_key_index
 `,
 	`ALTER TABLE action
 ADD COLUMN external_client_id INTEGER;
+`,
+	`CREATE TABLE auth_recovery_token
+(
+	id         SERIAL PRIMARY KEY,
+	token_hash TEXT      NOT NULL UNIQUE,
+	expires_at TIMESTAMP NOT NULL,
+	used_at    TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN expression TEXT;
+`,
+	`CREATE TABLE filter_group
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    min_size   TEXT,
+    max_size   TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE filter_group_indexer
+(
+    filter_group_id INTEGER,
+    indexer_id      INTEGER,
+    FOREIGN KEY (filter_group_id) REFERENCES filter_group (id) ON DELETE CASCADE,
+    FOREIGN KEY (indexer_id) REFERENCES indexer (id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_group_id, indexer_id)
+);
+
+ALTER TABLE "filter"
+    ADD COLUMN group_id INTEGER REFERENCES filter_group (id) ON DELETE SET NULL;
+
+ALTER TABLE action
+    ADD COLUMN filter_group_id INTEGER REFERENCES filter_group (id) ON DELETE CASCADE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN schedule TEXT;
+`,
+	`CREATE TABLE filter_list_source
+(
+    id                SERIAL PRIMARY KEY,
+    filter_id         INTEGER NOT NULL,
+    field             TEXT    NOT NULL,
+    url               TEXT    NOT NULL,
+    format            TEXT    NOT NULL DEFAULT 'TEXT',
+    json_key          TEXT,
+    refresh_interval  INTEGER NOT NULL DEFAULT 60,
+    last_refreshed_at TIMESTAMP,
+    last_error        TEXT,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+`,
+	`CREATE TABLE filter_revision
+(
+    id         SERIAL PRIMARY KEY,
+    filter_id  INTEGER NOT NULL,
+    revision   INTEGER NOT NULL,
+    username   TEXT,
+    note       TEXT,
+    changes    TEXT,
+    snapshot   TEXT    NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    UNIQUE (filter_id, revision)
+);
+`,
+	`CREATE TABLE filter_stats
+(
+    filter_id INTEGER   NOT NULL,
+    bucket    TIMESTAMP NOT NULL,
+    evaluated INTEGER   NOT NULL DEFAULT 0,
+    matched   INTEGER   NOT NULL DEFAULT 0,
+    rejected  INTEGER   NOT NULL DEFAULT 0,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_id, bucket)
+);
+
+CREATE TABLE filter_stats_rejection
+(
+    filter_id INTEGER   NOT NULL,
+    bucket    TIMESTAMP NOT NULL,
+    rejection TEXT      NOT NULL,
+    count     INTEGER   NOT NULL DEFAULT 0,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE,
+    PRIMARY KEY (filter_id, bucket, rejection)
+);
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN use_regex_shows BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN use_regex_release_groups BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN use_regex_tags BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN use_regex_uploaders BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN prefer_season_packs BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN season_pack_hold INTEGER DEFAULT 0;
+
+CREATE TABLE release_hold
+(
+    id         SERIAL PRIMARY KEY,
+    release_id INTEGER   NOT NULL,
+    filter_id  INTEGER   NOT NULL,
+    title      TEXT      NOT NULL,
+    season     INTEGER   NOT NULL,
+    episode    INTEGER   NOT NULL,
+    hold_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_hold_hold_until_index
+    ON release_hold (hold_until);
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN proper_upgrades BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN proper_upgrades_replace BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN freeleech_tokens BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE filter_external
+		ADD COLUMN exec_mode TEXT;
+
+	ALTER TABLE filter_external
+		ADD COLUMN exec_timeout INTEGER;
+
+	ALTER TABLE filter_external
+		ADD COLUMN exec_env TEXT;
+`,
+	`ALTER TABLE filter_external
+		ADD COLUMN webhook_retry_status TEXT;
+
+	ALTER TABLE filter_external
+		ADD COLUMN webhook_retry_attempts INTEGER;
+
+	ALTER TABLE filter_external
+		ADD COLUMN webhook_retry_delay_seconds INTEGER;
+
+	ALTER TABLE filter_external
+		ADD COLUMN webhook_expect_json_path TEXT;
+
+	ALTER TABLE filter_external
+		ADD COLUMN webhook_expect_json_value TEXT;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN on_reject TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN on_reject_filter_id INTEGER DEFAULT 0;
+
+	ALTER TABLE "filter"
+		ADD COLUMN on_action_failure TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN on_action_failure_filter_id INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN delay_jitter INTEGER DEFAULT 0;
+
+	ALTER TABLE release_hold
+		ADD COLUMN type TEXT NOT NULL DEFAULT 'SEASON_PACK';
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN max_downloads_interval INTEGER DEFAULT 1;
+
+	ALTER TABLE "filter"
+		ADD COLUMN max_downloads_per_indexer BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN hdr_preference TEXT []   DEFAULT '{}';
+
+	ALTER TABLE "filter"
+		ADD COLUMN reject_dv_without_fallback BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN bit_depths TEXT []   DEFAULT '{}';
+
+	ALTER TABLE "filter"
+		ADD COLUMN sample_rates TEXT []   DEFAULT '{}';
+
+	ALTER TABLE "filter"
+		ADD COLUMN audio_channels TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN absolute_episodes TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN anime_batch TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN anime_sub_type TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN anime_group_tiers TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN anime_max_group_tier INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN preset_id TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN preset_source TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN preset_managed BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN preset_hash TEXT;
+`,
+	`CREATE TABLE filter_template
+(
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT,
+    variables   TEXT,
+    filter      TEXT NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN filter_tags TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN verify_torrent BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE "filter"
+		ADD COLUMN min_file_count INTEGER DEFAULT 0;
+
+	ALTER TABLE "filter"
+		ADD COLUMN max_file_count INTEGER DEFAULT 0;
+
+	ALTER TABLE "filter"
+		ADD COLUMN match_file_extensions TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN except_file_extensions TEXT;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN min_seeders INTEGER DEFAULT 0;
+
+	ALTER TABLE "filter"
+		ADD COLUMN min_leechers INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN language_preference TEXT []   DEFAULT '{}';
+
+	ALTER TABLE "filter"
+		ADD COLUMN subtitle_preference TEXT []   DEFAULT '{}';
+
+	ALTER TABLE "filter"
+		ADD COLUMN except_language_only TEXT []   DEFAULT '{}';
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN rewrite_rules TEXT DEFAULT '[]';
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN air_dates TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN max_air_date_age INTEGER DEFAULT 0;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN arr_upgrade_only BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN tls_cert TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN tls_key TEXT;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN proxy TEXT;
+`,
+	`CREATE TABLE irc_log
+(
+    id         SERIAL PRIMARY KEY,
+    network_id INTEGER   NOT NULL,
+    channel    TEXT      NOT NULL,
+    nick       TEXT,
+    message    TEXT,
+    timestamp  TIMESTAMP NOT NULL,
+    FOREIGN KEY (network_id) REFERENCES irc_network(id) ON DELETE CASCADE
+);
+
+CREATE INDEX irc_log_network_id_channel_timestamp_index
+    ON irc_log (network_id, channel, timestamp);
+
+CREATE INDEX irc_log_timestamp_index
+    ON irc_log (timestamp);
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN reconnect_delay INTEGER DEFAULT 15;
+
+	ALTER TABLE irc_network
+		ADD COLUMN reconnect_max_delay INTEGER DEFAULT 600;
+
+	ALTER TABLE irc_network
+		ADD COLUMN reconnect_jitter INTEGER DEFAULT 0;
+
+	ALTER TABLE irc_network
+		ADD COLUMN reconnect_alert_attempts INTEGER DEFAULT 0;
+
+	ALTER TABLE irc_network
+		ADD COLUMN alt_servers TEXT;
+`,
+	`ALTER TABLE irc_channel
+		ADD COLUMN announcers TEXT;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN send_burst INTEGER DEFAULT 5;
+
+	ALTER TABLE irc_network
+		ADD COLUMN send_delay INTEGER DEFAULT 1000;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN tls_min_version TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN tls_ca TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN tls_skip_verify BOOLEAN DEFAULT TRUE;
+`,
+	`ALTER TABLE "filter"
+		ADD COLUMN flags TEXT;
+
+	ALTER TABLE "filter"
+		ADD COLUMN except_flags TEXT;
+
+	CREATE TABLE irc_trigger
+(
+    id            SERIAL PRIMARY KEY,
+    network_id    INTEGER NOT NULL,
+    name          TEXT    NOT NULL,
+    enabled       BOOLEAN DEFAULT TRUE,
+    channel       TEXT    NOT NULL,
+    pattern       TEXT    NOT NULL,
+    action        TEXT    NOT NULL,
+    flag          TEXT,
+    flag_duration INTEGER DEFAULT 0,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (network_id) REFERENCES irc_network(id) ON DELETE CASCADE
+);
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN share_connection BOOLEAN DEFAULT FALSE;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN offline_schedule TEXT;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN ident TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN realname TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN perform_commands TEXT;
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN split_quarantine INTEGER DEFAULT 0;
+
+	ALTER TABLE irc_network
+		ADD COLUMN split_action TEXT DEFAULT 'DELAY';
+`,
+	`ALTER TABLE irc_network
+		ADD COLUMN encoding TEXT;
+`,
+	`ALTER TABLE feed
+		ADD COLUMN etag TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN last_modified TEXT;
+`,
+	`ALTER TABLE feed_cache
+		ADD COLUMN created TIMESTAMP;
+
+	UPDATE feed_cache
+		SET created = ttl - INTERVAL '1 month';
+
+	CREATE INDEX feed_cache_feed_id_created_index
+		ON feed_cache (feed_id, created);
+`,
+	`ALTER TABLE feed
+		ADD COLUMN caps TEXT;
+`,
+	`ALTER TABLE feed
+		ADD COLUMN priority INTEGER DEFAULT 0 NOT NULL;
+`,
+	`ALTER TABLE action
+		ADD COLUMN priority INTEGER DEFAULT 0;
+
+	ALTER TABLE action
+		ADD COLUMN dupe_key TEXT;
 `,
 }
@@ -569,10 +569,125 @@ CROSS JOIN (
 	return &rls, nil
 }
 
-func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error {
+// releaseStatsWindows are the time windows of the stats breakdown, a zero duration means all time
+var releaseStatsWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "24h", duration: 24 * time.Hour},
+	{name: "7d", duration: 7 * 24 * time.Hour},
+	{name: "30d", duration: 30 * 24 * time.Hour},
+	{name: "all", duration: 0},
+}
+
+func (repo *ReleaseRepo) StatsBreakdown(ctx context.Context) (*domain.ReleaseStatsBreakdown, error) {
+	var (
+		stats domain.ReleaseStatsBreakdown
+		err   error
+	)
+
+	if stats.Filters, err = repo.pushStats(ctx, "COALESCE(ras.filter, '')", nil); err != nil {
+		return nil, errors.Wrap(err, "could not get stats by filter")
+	}
+
+	if stats.Indexers, err = repo.pushStats(ctx, "r.indexer", nil); err != nil {
+		return nil, errors.Wrap(err, "could not get stats by indexer")
+	}
+
+	stats.Windows = make([]domain.ReleaseStatsGroup, 0, len(releaseStatsWindows))
+
+	for _, window := range releaseStatsWindows {
+		var where sq.Sqlizer
+		if window.duration > 0 {
+			if repo.db.Driver == "sqlite" {
+				// timestamps are stored in different formats, datetime normalizes them
+				where = sq.Expr(fmt.Sprintf("datetime(ras.timestamp) >= datetime('now', '-%d seconds')", int64(window.duration.Seconds())))
+			} else {
+				where = sq.GtOrEq{"ras.timestamp": time.Now().Add(-window.duration)}
+			}
+		}
+
+		groups, err := repo.pushStats(ctx, "", where)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get stats for window: %s", window.name)
+		}
+
+		group := domain.ReleaseStatsGroup{}
+		if len(groups) > 0 {
+			group = groups[0]
+		}
+		group.Name = window.name
+
+		stats.Windows = append(stats.Windows, group)
+	}
+
+	return &stats, nil
+}
+
+// pushStats counts action statuses grouped by the groupBy expression, or in total if it is empty
+func (repo *ReleaseRepo) pushStats(ctx context.Context, groupBy string, where sq.Sqlizer) ([]domain.ReleaseStatsGroup, error) {
+	name := "''"
+	if groupBy != "" {
+		name = groupBy
+	}
+
+	queryBuilder := repo.db.squirrel.
+		Select(
+			name+" AS name",
+			"COUNT(CASE WHEN ras.status = 'PUSH_APPROVED' THEN 1 END) AS snatched",
+			"COUNT(CASE WHEN ras.status = 'PUSH_REJECTED' THEN 1 END) AS rejected",
+			"COUNT(CASE WHEN ras.status = 'PUSH_ERROR' THEN 1 END) AS errors",
+		).
+		From("release_action_status ras").
+		Join("release r ON r.id = ras.release_id")
+
+	if where != nil {
+		queryBuilder = queryBuilder.Where(where)
+	}
+
+	if groupBy != "" {
+		queryBuilder = queryBuilder.GroupBy(groupBy).OrderBy("snatched DESC", "name ASC")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	groups := make([]domain.ReleaseStatsGroup, 0)
+
+	for rows.Next() {
+		var g domain.ReleaseStatsGroup
+
+		if err := rows.Scan(&g.Name, &g.Snatched, &g.Rejected, &g.Errors); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		if total := g.Snatched + g.Rejected + g.Errors; total > 0 {
+			g.ErrorRate = float64(g.Errors) / float64(total)
+		}
+
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return groups, nil
+}
+
+// Delete deletes the releases matching req and returns how many were deleted
+func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(err, "could not start transaction")
+		return 0, errors.Wrap(err, "could not start transaction")
 	}
 
 	defer tx.Rollback()
@@ -592,35 +707,45 @@ func (repo *ReleaseRepo) Delete(ctx context.Context, req *domain.DeleteReleaseRe
 		}
 	}
 
+	if len(req.Indexers) > 0 {
+		qb = qb.Where(sq.Eq{"indexer": req.Indexers})
+	}
+
+	if len(req.ReleaseStatuses) > 0 {
+		// nested queries must use ? placeholders, the outer query rebinds them
+		statusQuery := sq.Select("release_id").From("release_action_status").Where(sq.Eq{"status": req.ReleaseStatuses})
+		qb = qb.Where(sq.Expr("id IN (?)", statusQuery))
+	}
+
 	query, args, err := qb.ToSql()
 	if err != nil {
-		return errors.Wrap(err, "error executing query")
+		return 0, errors.Wrap(err, "error executing query")
 	}
 
 	repo.log.Debug().Str("repo", "release").Str("query", query).Msgf("release.delete: args: %v", args)
 
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return errors.Wrap(err, "error executing query")
+		return 0, errors.Wrap(err, "error executing query")
 	}
 
 	deletedRows, err := result.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "error fetching rows affected")
+		return 0, errors.Wrap(err, "error fetching rows affected")
 	}
 
 	_, err = tx.ExecContext(ctx, `DELETE FROM release_action_status WHERE release_id NOT IN (SELECT id FROM "release")`)
 	if err != nil {
-		return errors.Wrap(err, "error executing query")
+		return 0, errors.Wrap(err, "error executing query")
 	}
 
 	if err := tx.Commit(); err != nil {
-		return errors.Wrap(err, "error commit transaction delete")
+		return 0, errors.Wrap(err, "error commit transaction delete")
 	}
 
 	repo.log.Debug().Msgf("deleted %d rows from release table", deletedRows)
 
-	return nil
+	return deletedRows, nil
 }
 
 func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error) {
@@ -689,3 +814,64 @@ func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, seas
 
 	return true, nil
 }
+
+// FindGrabbed returns the releases of a title that were pushed successfully, newest first
+func (repo *ReleaseRepo) FindGrabbed(ctx context.Context, params domain.FindGrabbedParams) ([]*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.torrent_name", "r.filter_id", "r.resolution", "r.source", "r.hdr").
+		Distinct().
+		From("release r").
+		InnerJoin("release_action_status ras ON r.id = ras.release_id").
+		Where(ILike("r.title", params.Title)).
+		Where(sq.Eq{"r.season": params.Season, "r.episode": params.Episode, "ras.status": domain.ReleasePushStatusApproved}).
+		Where(sq.NotEq{"r.id": params.ExcludeID}).
+		OrderBy("r.id DESC")
+
+	if params.Season == 0 && params.Episode == 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.year": params.Year})
+	}
+
+	if params.FilterID > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.filter_id": params.FilterID})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	releases := make([]*domain.Release, 0)
+	for rows.Next() {
+		var rls domain.Release
+		var torrentName, resolution, source, hdr sql.NullString
+		var filterID sql.NullInt64
+
+		if err := rows.Scan(&rls.ID, &torrentName, &filterID, &resolution, &source, &hdr); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		rls.TorrentName = torrentName.String
+		rls.FilterID = int(filterID.Int64)
+		rls.Resolution = resolution.String
+		rls.Source = source.String
+		rls.HDR = []string{}
+		if hdr.String != "" {
+			rls.HDR = strings.Split(hdr.String, ",")
+		}
+
+		releases = append(releases, &rls)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return releases, nil
+}
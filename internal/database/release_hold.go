@@ -0,0 +1,147 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ReleaseHoldRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewReleaseHoldRepo(log logger.Logger, db *DB) domain.ReleaseHoldRepo {
+	return &ReleaseHoldRepo{
+		log: log.With().Str("repo", "release_hold").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ReleaseHoldRepo) Store(ctx context.Context, hold *domain.ReleaseHold) error {
+	queryBuilder := r.db.squirrel.
+		Insert("release_hold").
+		Columns("type", "release_id", "filter_id", "title", "season", "episode", "hold_until").
+		Values(hold.Type, hold.ReleaseID, hold.FilterID, hold.Title, hold.Season, hold.Episode, hold.HoldUntil.UTC()).
+		Suffix("RETURNING id").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&hold.ID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// FindDue returns the holds that expire at or before now, oldest first
+func (r *ReleaseHoldRepo) FindDue(ctx context.Context, now time.Time) ([]domain.ReleaseHold, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "type", "release_id", "filter_id", "title", "season", "episode", "hold_until", "created_at").
+		From("release_hold").
+		Where(sq.LtOrEq{"hold_until": now.UTC()}).
+		OrderBy("hold_until ASC")
+
+	return r.find(ctx, queryBuilder)
+}
+
+// FindByType returns the holds of a type, soonest first
+func (r *ReleaseHoldRepo) FindByType(ctx context.Context, holdType domain.ReleaseHoldType) ([]domain.ReleaseHold, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "type", "release_id", "filter_id", "title", "season", "episode", "hold_until", "created_at").
+		From("release_hold").
+		Where(sq.Eq{"type": holdType}).
+		OrderBy("hold_until ASC")
+
+	return r.find(ctx, queryBuilder)
+}
+
+// FindBySeason returns the season pack holds of the episodes of a show season
+func (r *ReleaseHoldRepo) FindBySeason(ctx context.Context, title string, season int) ([]domain.ReleaseHold, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "type", "release_id", "filter_id", "title", "season", "episode", "hold_until", "created_at").
+		From("release_hold").
+		Where(ILike("title", title)).
+		Where(sq.Eq{"season": season}).
+		Where(sq.Eq{"type": domain.ReleaseHoldTypeSeasonPack}).
+		OrderBy("id ASC")
+
+	return r.find(ctx, queryBuilder)
+}
+
+func (r *ReleaseHoldRepo) find(ctx context.Context, queryBuilder sq.SelectBuilder) ([]domain.ReleaseHold, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	holds := make([]domain.ReleaseHold, 0)
+	for rows.Next() {
+		var hold domain.ReleaseHold
+
+		if err := rows.Scan(&hold.ID, &hold.Type, &hold.ReleaseID, &hold.FilterID, &hold.Title, &hold.Season, &hold.Episode, &hold.HoldUntil, &hold.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		holds = append(holds, hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return holds, nil
+}
+
+// Claim deletes the hold and returns true if it still existed, so only one caller runs the actions of a hold
+func (r *ReleaseHoldRepo) Claim(ctx context.Context, id int) (bool, error) {
+	query, args, err := r.db.squirrel.
+		Delete("release_hold").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+func (r *ReleaseHoldRepo) Delete(ctx context.Context, id int) error {
+	query, args, err := r.db.squirrel.
+		Delete("release_hold").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const redactedValue = "REDACTED"
+
+// seedSkipTables are not written to seed files, the feed cache is rebuilt by the feeds
+var seedSkipTables = map[string]bool{
+	"feed_cache": true,
+}
+
+type redactFunc func(value string) string
+
+// seedRedactions lists the columns that contain passwords, passkeys and tokens
+var seedRedactions = map[string]map[string]redactFunc{
+	"users":           {"password": redactAll},
+	"indexer":         {"settings": redactJSON},
+	"irc_network":     {"pass": redactAll, "auth_password": redactAll, "invite_command": redactAll, "tls_key": redactAll, "proxy": redactURL, "perform_commands": redactAll},
+	"irc_channel":     {"password": redactAll},
+	"filter_external": {"external_webhook_host": redactURL, "external_exec_args": redactAll},
+	"client":          {"password": redactAll, "settings": redactJSON},
+	"action":          {"webhook_host": redactURL, "webhook_headers": redactAll, "exec_args": redactAll},
+	"release":         {"info_url": redactURL, "download_url": redactURL},
+	"notification":    {"token": redactAll, "api_key": redactAll, "webhook": redactURL, "password": redactAll},
+	"feed":            {"url": redactURL, "api_key": redactAll, "cookie": redactAll, "settings": redactFeedSettings},
+}
+
+// DumpSeed writes all data as sql insert statements that can be loaded into an empty database with Seed.
+// With redact passwords, passkeys and tokens are replaced and api keys are left out.
+func (db *DB) DumpSeed(ctx context.Context, w io.Writer, redact bool) error {
+	current, _, err := db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "-- autobrr seed from %s schema version %d, created %s\n", db.Driver, current, time.Now().UTC().Format(time.RFC3339))
+	if redact {
+		fmt.Fprintf(bw, "-- secrets are redacted, reset user passwords with change-password\n")
+	}
+
+	for _, table := range migrateTables {
+		if seedSkipTables[table] || (redact && table == "api_key") {
+			continue
+		}
+
+		if err := db.dumpTable(ctx, bw, table, redact); err != nil {
+			return errors.Wrap(err, "could not dump table: %s", table)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (db *DB) dumpTable(ctx context.Context, w io.Writer, table string, redact bool) error {
+	columnTypes, err := db.columnTypes(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	orderBy := ""
+	if _, ok := columnTypes["id"]; ok {
+		orderBy = " ORDER BY id"
+	}
+
+	rows, err := db.handler.QueryContext(ctx, fmt.Sprintf(`SELECT * FROM %s%s`, quoteIdent(table), orderBy))
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return errors.Wrap(err, "could not get columns")
+	}
+
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, quoteIdent(column))
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES", quoteIdent(table), strings.Join(quoted, ", "))
+
+	fmt.Fprintf(w, "\n-- %s\n", table)
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return errors.Wrap(err, "error scanning row")
+		}
+
+		literals := make([]string, 0, len(columns))
+		for i, column := range columns {
+			value := values[i]
+
+			if redact {
+				if fn, ok := seedRedactions[table][column]; ok {
+					value = redactValue(value, fn)
+				}
+			}
+
+			literals = append(literals, sqlLiteral(value, columnTypes[column]))
+		}
+
+		if _, err := fmt.Fprintf(w, "%s (%s);\n", insert, strings.Join(literals, ", ")); err != nil {
+			return errors.Wrap(err, "could not write row")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error rows")
+	}
+
+	return nil
+}
+
+// Seed loads a seed file created by DumpSeed into an empty database in a single transaction
+func (db *DB) Seed(ctx context.Context, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "could not read seed")
+	}
+
+	if err := db.CheckEmpty(ctx); err != nil {
+		return errors.Wrap(err, "database must be empty")
+	}
+
+	tx, err := db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(data)); err != nil {
+		return errors.Wrap(err, "could not execute seed")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	if db.Driver == "postgres" {
+		if err := db.resetSequences(ctx); err != nil {
+			return errors.Wrap(err, "could not reset sequences")
+		}
+	}
+
+	return nil
+}
+
+// sqlLiteral formats a value so both sqlite and postgres accept it
+func sqlLiteral(value any, columnType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+
+	case int64:
+		// sqlite stores booleans as integers
+		if columnType == "boolean" {
+			return sqlLiteral(v != 0, columnType)
+		}
+		return strconv.FormatInt(v, 10)
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+
+	case time.Time:
+		return quoteLiteral(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+
+	case []byte:
+		return quoteLiteral(string(v))
+
+	case string:
+		return quoteLiteral(v)
+	}
+
+	return quoteLiteral(fmt.Sprint(value))
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func redactValue(value any, fn redactFunc) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		return fn(v)
+
+	case []byte:
+		if len(v) == 0 {
+			return v
+		}
+		return fn(string(v))
+	}
+
+	return value
+}
+
+func redactAll(string) string {
+	return redactedValue
+}
+
+// redactJSON replaces the values of secret looking keys in a json object, anything else is redacted completely
+func redactJSON(value string) string {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return redactedValue
+	}
+
+	redactMap(data)
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return redactedValue
+	}
+
+	return string(out)
+}
+
+func redactMap(data map[string]any) {
+	for key, value := range data {
+		switch v := value.(type) {
+		case map[string]any:
+			redactMap(v)
+		case string:
+			if v != "" && isSecretKey(key) {
+				data[key] = redactedValue
+			}
+		}
+	}
+}
+
+// redactFeedSettings blanks the login body, the request headers and the proxy credentials of feed settings
+func redactFeedSettings(value string) string {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return redactedValue
+	}
+
+	if login, ok := data["login"].(map[string]any); ok {
+		if body, ok := login["body"].(string); ok && body != "" {
+			login["body"] = redactedValue
+		}
+	}
+
+	if headers, ok := data["headers"].(string); ok && headers != "" {
+		data["headers"] = redactedValue
+	}
+
+	if proxy, ok := data["proxy"].(string); ok && proxy != "" {
+		data["proxy"] = redactURL(proxy)
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return redactedValue
+	}
+
+	return string(out)
+}
+
+// redactURL removes credentials, secret looking query parameters and passkeys in the path from a url
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return redactedValue
+	}
+
+	if u.User != nil {
+		u.User = url.UserPassword(redactedValue, redactedValue)
+	}
+
+	query := u.Query()
+	for key := range query {
+		if isSecretKey(key) {
+			query.Set(key, redactedValue)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if looksLikePasskey(segment) {
+			segments[i] = redactedValue
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+
+	return u.String()
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, s := range []string{"key", "pass", "token", "cookie", "secret", "auth", "uid", "sid"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// looksLikePasskey returns true for long alphanumeric path segments, which trackers use for passkeys in rss and download urls
+func looksLikePasskey(segment string) bool {
+	if len(segment) < 20 {
+		return false
+	}
+
+	for _, r := range segment {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -78,6 +78,41 @@ func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain
 	return &user, nil
 }
 
+func (r *UserRepo) FindAll(ctx context.Context) ([]*domain.User, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "username", "password").
+		From("users").
+		OrderBy("id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		var user domain.User
+
+		if err := rows.Scan(&user.ID, &user.Username, &user.Password); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return users, nil
+}
+
 func (r *UserRepo) Store(ctx context.Context, req domain.CreateUserRequest) error {
 
 	var err error
@@ -122,3 +157,32 @@ func (r *UserRepo) Update(ctx context.Context, user domain.User) error {
 
 	return err
 }
+
+func (r *UserRepo) Delete(ctx context.Context, username string) error {
+	queryBuilder := r.db.squirrel.
+		Delete("users").
+		Where(sq.Eq{"username": username})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+
+	r.log.Debug().Msgf("user.delete: successfully deleted user: %s", username)
+
+	return nil
+}
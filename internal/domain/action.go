@@ -15,6 +15,8 @@ type ActionRepo interface {
 	Store(ctx context.Context, action Action) (*Action, error)
 	StoreFilterActions(ctx context.Context, filterID int64, actions []*Action) ([]*Action, error)
 	FindByFilterID(ctx context.Context, filterID int) ([]*Action, error)
+	StoreFilterGroupActions(ctx context.Context, groupID int64, actions []*Action) ([]*Action, error)
+	FindByFilterGroupID(ctx context.Context, groupID int) ([]*Action, error)
 	List(ctx context.Context) ([]Action, error)
 	Get(ctx context.Context, req *GetActionRequest) (*Action, error)
 	Delete(ctx context.Context, req *DeleteActionRequest) error
@@ -51,8 +53,11 @@ type Action struct {
 	WebhookMethod            string              `json:"webhook_method,omitempty"`
 	WebhookData              string              `json:"webhook_data,omitempty"`
 	WebhookHeaders           []string            `json:"webhook_headers,omitempty"`
+	Priority                 int                 `json:"priority,omitempty"` // nzbget priority, -100 very low to 100 very high and 900 force
+	DupeKey                  string              `json:"dupe_key,omitempty"` // nzbget duplicate key, releases with the same key are duplicates
 	ExternalDownloadClientID int32               `json:"external_download_client_id,omitempty"`
 	FilterID                 int                 `json:"filter_id,omitempty"`
+	FilterGroupID            int                 `json:"filter_group_id,omitempty"`
 	ClientID                 int32               `json:"client_id,omitempty"`
 	Client                   *DownloadClient     `json:"client,omitempty"`
 }
@@ -87,6 +92,7 @@ func (a *Action) ParseMacros(release *Release) error {
 	a.ExecArgs, err = m.Parse(a.ExecArgs)
 	a.WatchFolder, err = m.Parse(a.WatchFolder)
 	a.Category, err = m.Parse(a.Category)
+	a.DupeKey, err = m.Parse(a.DupeKey)
 	a.Tags, err = m.Parse(a.Tags)
 	a.Label, err = m.Parse(a.Label)
 	a.SavePath, err = m.Parse(a.SavePath)
@@ -118,8 +124,25 @@ const (
 	ActionTypeWhisparr     ActionType = "WHISPARR"
 	ActionTypeReadarr      ActionType = "READARR"
 	ActionTypeSabnzbd      ActionType = "SABNZBD"
+	ActionTypeNzbget       ActionType = "NZBGET"
+	ActionTypeAria2        ActionType = "ARIA2"
 )
 
+// SupportsProtocol reports whether actions of the type can handle releases of the protocol.
+// Torrent clients only take torrents and usenet clients only take nzbs, the others pass on the download url.
+func (t ActionType) SupportsProtocol(protocol ReleaseProtocol) bool {
+	switch t {
+	case ActionTypeQbittorrent, ActionTypeDelugeV1, ActionTypeDelugeV2, ActionTypeRTorrent, ActionTypeTransmission, ActionTypePorla,
+		ActionTypeAria2:
+		return protocol != ReleaseProtocolNzb
+
+	case ActionTypeSabnzbd, ActionTypeNzbget:
+		return protocol == ReleaseProtocolNzb
+	}
+
+	return true
+}
+
 type ActionContentLayout string
 
 const (
@@ -0,0 +1,35 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionType_SupportsProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   ActionType
+		protocol ReleaseProtocol
+		want     bool
+	}{
+		{name: "qbittorrent_torrent", action: ActionTypeQbittorrent, protocol: ReleaseProtocolTorrent, want: true},
+		{name: "qbittorrent_usenet", action: ActionTypeQbittorrent, protocol: ReleaseProtocolNzb, want: false},
+		{name: "sabnzbd_usenet", action: ActionTypeSabnzbd, protocol: ReleaseProtocolNzb, want: true},
+		{name: "sabnzbd_torrent", action: ActionTypeSabnzbd, protocol: ReleaseProtocolTorrent, want: false},
+		{name: "nzbget_usenet", action: ActionTypeNzbget, protocol: ReleaseProtocolNzb, want: true},
+		{name: "nzbget_torrent", action: ActionTypeNzbget, protocol: ReleaseProtocolTorrent, want: false},
+		{name: "aria2_torrent", action: ActionTypeAria2, protocol: ReleaseProtocolTorrent, want: true},
+		{name: "aria2_usenet", action: ActionTypeAria2, protocol: ReleaseProtocolNzb, want: false},
+		{name: "webhook_usenet", action: ActionTypeWebhook, protocol: ReleaseProtocolNzb, want: true},
+		{name: "sonarr_torrent", action: ActionTypeSonarr, protocol: ReleaseProtocolTorrent, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.action.SupportsProtocol(tt.protocol))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// AnimeSubType tells if an anime release has subtitles from a fansub group or from an official release
+type AnimeSubType string
+
+const (
+	AnimeSubTypeFansub   AnimeSubType = "FANSUB"
+	AnimeSubTypeOfficial AnimeSubType = "OFFICIAL"
+)
+
+// FilterAnimeBatch decides if a filter matches anime batches, single episodes or both
+type FilterAnimeBatch string
+
+const (
+	FilterAnimeBatchOnly   FilterAnimeBatch = "BATCH"
+	FilterAnimeBatchSingle FilterAnimeBatch = "SINGLE"
+)
+
+var (
+	animeGroupRegexp    = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+	animeEpisodeRegexp  = regexp.MustCompile(`\s-\s(\d{1,4})(?:v\d)?(?:\s|$|\[|\()`)
+	animeRangeRegexp    = regexp.MustCompile(`\s(\d{1,4})\s?[-~]\s?(\d{1,4})(?:\s|$|\[|\()`)
+	animeBatchRegexp    = regexp.MustCompile(`(?i)\b(batch|complete)\b`)
+	animeFansubRegexp   = regexp.MustCompile(`(?i)\bfan[\s\-]?sub(?:s|bed)?\b`)
+	animeOfficialRegexp = regexp.MustCompile(`(?i)\b(?:CR|crunchyroll|funi|funimation|hidive|amzn|nf|netflix|dsnp|b-global|bilibili|adn|wakanim|official)\b`)
+)
+
+// animeOfficialGroups release official subtitles from streaming services without tagging the service
+var animeOfficialGroups = []string{"SubsPlease", "Erai-raws", "HorribleSubs", "ToonsHub", "VARYG"}
+
+// ParseAnime populates the anime fields of a release from an anime tracker, run after ParseString.
+// Anime names usually start with the group in brackets and number episodes absolute, eg. "[Group] Title - 47 (1080p)".
+func (r *Release) ParseAnime() {
+	r.Anime = true
+
+	if m := animeGroupRegexp.FindStringSubmatch(r.TorrentName); m != nil {
+		r.Group = strings.TrimSpace(m[1])
+	}
+
+	if m := animeRangeRegexp.FindStringSubmatch(r.TorrentName); m != nil && m[1] != m[2] {
+		r.Batch = true
+		r.Episode = 0
+	} else if animeBatchRegexp.MatchString(r.TorrentName) || (r.Season > 0 && r.Episode == 0) {
+		r.Batch = true
+	}
+
+	if !r.Batch {
+		if r.Season == 0 && r.Episode > 0 {
+			r.AbsoluteEpisode = r.Episode
+		} else if m := animeEpisodeRegexp.FindStringSubmatch(r.TorrentName); m != nil && r.Season == 0 {
+			r.AbsoluteEpisode, _ = strconv.Atoi(m[1])
+			r.Episode = r.AbsoluteEpisode
+			r.Title = strings.TrimSpace(strings.TrimSuffix(r.Title, "- "+m[1]))
+		}
+	}
+
+	r.AnimeSubType = parseAnimeSubType(r.TorrentName+" "+r.ReleaseTags, r.Group)
+}
+
+func parseAnimeSubType(s string, group string) AnimeSubType {
+	switch {
+	case animeFansubRegexp.MatchString(s):
+		return AnimeSubTypeFansub
+	case animeOfficialRegexp.MatchString(s):
+		return AnimeSubTypeOfficial
+	}
+
+	for _, g := range animeOfficialGroups {
+		if strings.EqualFold(g, group) {
+			return AnimeSubTypeOfficial
+		}
+	}
+
+	if animeGroupRegexp.MatchString(s) {
+		return AnimeSubTypeFansub
+	}
+
+	return ""
+}
+
+// AnimeGroupTier returns the tier of group in tiers, starting at 1 for the best tier, or 0 if the group is not listed.
+// Tiers are separated by new lines and the groups of a tier by commas.
+func AnimeGroupTier(tiers string, group string) int {
+	if group == "" {
+		return 0
+	}
+
+	tier := 0
+	for _, line := range strings.Split(tiers, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		tier++
+
+		for _, g := range strings.Split(line, ",") {
+			if strings.EqualFold(strings.TrimSpace(g), group) {
+				return tier
+			}
+		}
+	}
+
+	return 0
+}
+
+// ValidateAnime checks the anime settings of the filter
+func (f *Filter) ValidateAnime() error {
+	switch f.AnimeBatch {
+	case "", FilterAnimeBatchOnly, FilterAnimeBatchSingle:
+	default:
+		return errors.New("invalid anime batch: %s", f.AnimeBatch)
+	}
+
+	switch f.AnimeSubType {
+	case "", AnimeSubTypeFansub, AnimeSubTypeOfficial:
+	default:
+		return errors.New("invalid anime sub type: %s", f.AnimeSubType)
+	}
+
+	if f.AnimeMaxGroupTier < 0 {
+		return errors.New("anime max group tier can not be negative")
+	}
+
+	return nil
+}
+
+func (f Filter) checkAnime(r *Release) {
+	if f.AbsoluteEpisodes != "" && !containsIntStrings(r.AbsoluteEpisode, f.AbsoluteEpisodes) {
+		r.addRejectionF("absolute episodes not matching. got: %d want: %v", r.AbsoluteEpisode, f.AbsoluteEpisodes)
+	}
+
+	switch f.AnimeBatch {
+	case FilterAnimeBatchOnly:
+		if !r.Batch {
+			r.addRejection("wanted: batch")
+		}
+	case FilterAnimeBatchSingle:
+		if r.Batch {
+			r.addRejection("unwanted: batch")
+		}
+	}
+
+	if f.AnimeSubType != "" && r.AnimeSubType != f.AnimeSubType {
+		got := string(r.AnimeSubType)
+		if got == "" {
+			got = "unknown"
+		}
+
+		r.addRejectionF("sub type not matching. got: %v want: %v", got, f.AnimeSubType)
+	}
+
+	if strings.TrimSpace(f.AnimeGroupTiers) != "" {
+		tier := AnimeGroupTier(f.AnimeGroupTiers, r.Group)
+		if tier == 0 {
+			r.addRejectionF("group not in tier list. got: %v", r.Group)
+		} else if f.AnimeMaxGroupTier > 0 && tier > f.AnimeMaxGroupTier {
+			r.addRejectionF("group tier not matching. got: %v (tier %d) want: tier %d or better", r.Group, tier, f.AnimeMaxGroupTier)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease_ParseAnime(t *testing.T) {
+	tests := []struct {
+		name                string
+		torrentName         string
+		wantTitle           string
+		wantGroup           string
+		wantAbsoluteEpisode int
+		wantBatch           bool
+		wantSubType         AnimeSubType
+	}{
+		{
+			name:                "absolute episode",
+			torrentName:         "[SubsPlease] Jujutsu Kaisen - 47 (1080p) [ABCD1234].mkv",
+			wantTitle:           "Jujutsu Kaisen",
+			wantGroup:           "SubsPlease",
+			wantAbsoluteEpisode: 47,
+			wantSubType:         AnimeSubTypeOfficial,
+		},
+		{
+			name:                "four digit episode",
+			torrentName:         "[Fansub-Group] One Piece - 1085 [1080p]",
+			wantTitle:           "One Piece",
+			wantGroup:           "Fansub-Group",
+			wantAbsoluteEpisode: 1085,
+			wantSubType:         AnimeSubTypeFansub,
+		},
+		{
+			name:        "episode range batch",
+			torrentName: "[Erai-raws] Sousou no Frieren - 01 ~ 28 [1080p][Multiple Subtitle] [Batch]",
+			wantTitle:   "Sousou no Frieren",
+			wantGroup:   "Erai-raws",
+			wantBatch:   true,
+			wantSubType: AnimeSubTypeOfficial,
+		},
+		{
+			name:        "season batch",
+			torrentName: "[Judas] Vinland Saga (Season 2) [1080p][HEVC x265 10bit][Multi-Subs] (Batch)",
+			wantTitle:   "Vinland Saga",
+			wantGroup:   "Judas",
+			wantBatch:   true,
+			wantSubType: AnimeSubTypeFansub,
+		},
+		{
+			name:        "scene style",
+			torrentName: "Sousou.no.Frieren.S01E05.1080p.CR.WEB-DL.AAC2.0.H.264-GRP",
+			wantTitle:   "Sousou no Frieren",
+			wantGroup:   "GRP",
+			wantSubType: AnimeSubTypeOfficial,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("animebytes")
+			r.ParseString(tt.torrentName)
+			r.ParseAnime()
+
+			assert.True(t, r.Anime)
+			assert.Equal(t, tt.wantTitle, r.Title)
+			assert.Equal(t, tt.wantGroup, r.Group)
+			assert.Equal(t, tt.wantAbsoluteEpisode, r.AbsoluteEpisode)
+			assert.Equal(t, tt.wantBatch, r.Batch)
+			assert.Equal(t, tt.wantSubType, r.AnimeSubType)
+		})
+	}
+}
+
+func TestAnimeGroupTier(t *testing.T) {
+	tiers := "Judas, Commie\n\nSubsPlease,Erai-raws\nHorribleSubs"
+
+	assert.Equal(t, 1, AnimeGroupTier(tiers, "judas"))
+	assert.Equal(t, 2, AnimeGroupTier(tiers, "Erai-raws"))
+	assert.Equal(t, 3, AnimeGroupTier(tiers, "HorribleSubs"))
+	assert.Equal(t, 0, AnimeGroupTier(tiers, "Other"))
+	assert.Equal(t, 0, AnimeGroupTier(tiers, ""))
+}
+
+func TestFilter_CheckFilter_Anime(t *testing.T) {
+	r := &Release{TorrentName: "[SubsPlease] Jujutsu Kaisen - 47 (1080p)", Group: "SubsPlease", Episode: 47, AbsoluteEpisode: 47, AnimeSubType: AnimeSubTypeOfficial, Anime: true}
+
+	tests := []struct {
+		name           string
+		filter         Filter
+		wantRejections []string
+		wantMatch      bool
+	}{
+		{name: "match", filter: Filter{AbsoluteEpisodes: "40-50", AnimeBatch: FilterAnimeBatchSingle, AnimeSubType: AnimeSubTypeOfficial, AnimeGroupTiers: "Judas\nSubsPlease", AnimeMaxGroupTier: 2}, wantMatch: true},
+		{name: "absolute episodes", filter: Filter{AbsoluteEpisodes: "1-24"}, wantRejections: []string{"absolute episodes not matching. got: 47 want: 1-24"}},
+		{name: "batch only", filter: Filter{AnimeBatch: FilterAnimeBatchOnly}, wantRejections: []string{"wanted: batch"}},
+		{name: "fansub only", filter: Filter{AnimeSubType: AnimeSubTypeFansub}, wantRejections: []string{"sub type not matching. got: OFFICIAL want: FANSUB"}},
+		{name: "group tier", filter: Filter{AnimeGroupTiers: "Judas\nSubsPlease", AnimeMaxGroupTier: 1}, wantRejections: []string{"group tier not matching. got: SubsPlease (tier 2) want: tier 1 or better"}},
+		{name: "group not listed", filter: Filter{AnimeGroupTiers: "Judas"}, wantRejections: []string{"group not in tier list. got: SubsPlease"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.resetRejections()
+
+			rejections, match := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.wantRejections, rejections)
+			assert.Equal(t, tt.wantMatch, match)
+		})
+	}
+}
+
+func TestFilter_ValidateAnime(t *testing.T) {
+	assert.NoError(t, (&Filter{AnimeBatch: FilterAnimeBatchOnly, AnimeSubType: AnimeSubTypeFansub, AnimeMaxGroupTier: 2}).ValidateAnime())
+	assert.Error(t, (&Filter{AnimeBatch: "SOME"}).ValidateAnime())
+	assert.Error(t, (&Filter{AnimeSubType: "DUB"}).ValidateAnime())
+	assert.Error(t, (&Filter{AnimeMaxGroupTier: -1}).ValidateAnime())
+}
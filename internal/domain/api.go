@@ -14,9 +14,23 @@ type APIRepo interface {
 	GetKeys(ctx context.Context) ([]APIKey, error)
 }
 
+// APIKeyScopeAdmin allows an api key to manage users, api keys and database maintenance
+const APIKeyScopeAdmin = "admin"
+
 type APIKey struct {
 	Name      string    `json:"name"`
 	Key       string    `json:"key"`
 	Scopes    []string  `json:"scopes"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// HasScope reports if the key was created with scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -84,6 +84,8 @@ const (
 	DownloadClientTypeWhisparr     DownloadClientType = "WHISPARR"
 	DownloadClientTypeReadarr      DownloadClientType = "READARR"
 	DownloadClientTypeSabnzbd      DownloadClientType = "SABNZBD"
+	DownloadClientTypeNzbget       DownloadClientType = "NZBGET"
+	DownloadClientTypeAria2        DownloadClientType = "ARIA2"
 )
 
 // Validate basic validation of client
@@ -3,8 +3,14 @@
 
 package domain
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
 
 var (
-	ErrRecordNotFound = sql.ErrNoRows
+	ErrRecordNotFound      = sql.ErrNoRows
+	ErrFeedBackfillRunning = errors.Sentinel("feed backfill already running")
+	ErrLastUser            = errors.Sentinel("can't delete the last user")
 )
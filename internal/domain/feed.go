@@ -5,18 +5,29 @@ package domain
 
 import (
 	"context"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jsonpath"
+
+	"github.com/robfig/cron/v3"
 )
 
 type FeedCacheRepo interface {
 	Get(feedId int, key string) ([]byte, error)
-	GetByFeed(ctx context.Context, feedId int) ([]FeedCacheItem, error)
+	GetByFeed(ctx context.Context, feedId int, limit int) ([]FeedCacheItem, error)
 	GetCountByFeed(ctx context.Context, feedId int) (int, error)
+	GetStatsByFeed(ctx context.Context, feedId int) (*FeedCacheStats, error)
 	Exists(feedId int, key string) (bool, error)
 	Put(feedId int, key string, val []byte, ttl time.Time) error
 	Delete(ctx context.Context, feedId int, key string) error
 	DeleteByFeed(ctx context.Context, feedId int) error
 	DeleteStale(ctx context.Context) error
+	DeleteOlderThan(ctx context.Context, days int) (int64, error)
+	DeleteOverLimit(ctx context.Context, maxEntries int) (int64, error)
 }
 
 type FeedRepo interface {
@@ -28,6 +39,10 @@ type FeedRepo interface {
 	Update(ctx context.Context, feed *Feed) error
 	UpdateLastRun(ctx context.Context, feedID int) error
 	UpdateLastRunWithData(ctx context.Context, feedID int, data string) error
+	UpdateValidators(ctx context.Context, feedID int, etag string, lastModified string) error
+	UpdateCookie(ctx context.Context, feedID int, cookie string) error
+	UpdateApiKey(ctx context.Context, feedID int, apiKey string) error
+	UpdateCaps(ctx context.Context, feedID int, caps *FeedCaps) error
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Delete(ctx context.Context, id int) error
 }
@@ -53,10 +68,440 @@ type Feed struct {
 	LastRun      time.Time         `json:"last_run"`
 	LastRunData  string            `json:"last_run_data"`
 	NextRun      time.Time         `json:"next_run"`
+	Health       *FeedHealth       `json:"health,omitempty"`
+	Caps         *FeedCaps         `json:"caps,omitempty"`
+	Runs         *FeedRunStats     `json:"runs,omitempty"`
+
+	// Priority orders the feeds of an indexer. They share their cache so an item is processed by one of them only,
+	// and refresh one at a time with the waiting feed of the highest priority going next.
+	Priority int `json:"priority"`
+
+	// ETag and LastModified of the last response make the next request conditional
+	ETag         string `json:"-"`
+	LastModified string `json:"-"`
 }
 
 type FeedSettingsJSON struct {
-	DownloadType FeedDownloadType `json:"download_type"`
+	DownloadType FeedDownloadType  `json:"download_type"`
+	FieldMapping *FeedFieldMapping `json:"field_mapping,omitempty"`
+	JSON         *FeedJSONSettings `json:"json,omitempty"`
+
+	// Cron is a cron expression like "*/10 8-23 * * *" or "@hourly" that replaces the refresh interval
+	Cron string `json:"cron,omitempty"`
+
+	// NotBetween are windows in which the feed is not refreshed, in the format of filter schedules like "02:00-07:00"
+	NotBetween string `json:"not_between,omitempty"`
+
+	// CatchUp refreshes the feed once when a not between window or the global feed quiet hours end,
+	// if a refresh was skipped in it
+	CatchUp bool `json:"catch_up,omitempty"`
+
+	Backfill *FeedBackfillSettings `json:"backfill,omitempty"`
+
+	// FailingAlertAfter is the number of minutes a feed fails before FEED_FAILING is sent, 0 is one hour
+	FailingAlertAfter int `json:"failing_alert_after,omitempty"`
+
+	// Proxy is an http, https or socks5 proxy url the feed is requested through
+	Proxy string `json:"proxy,omitempty"`
+
+	// Headers are extra request headers of the feed, one "Name: value" per line
+	Headers string `json:"headers,omitempty"`
+
+	Login *FeedLoginSettings `json:"login,omitempty"`
+
+	// CategoryMapping maps torznab categories to release categories
+	CategoryMapping []FeedCategoryMapping `json:"category_mapping,omitempty"`
+
+	// Push are the stream settings of a push feed, its messages are mapped with the json settings
+	Push *FeedPushSettings `json:"push,omitempty"`
+
+	// TitleInclude and TitleExclude are comma separated patterns matched on the raw item titles before they are
+	// parsed, to trim large feeds cheaply. A pattern with * or ? is a wildcard on the whole title, else a substring.
+	TitleInclude string `json:"title_include,omitempty"`
+	TitleExclude string `json:"title_exclude,omitempty"`
+}
+
+// FeedLoginSettings is the login request of a feed behind session auth. The cookies it sets replace the cookie of the feed,
+// it is sent when the feed has no cookie and again when the feed answers 401 or 403.
+type FeedLoginSettings struct {
+	URL    string `json:"url"`
+	Method string `json:"method,omitempty"`
+
+	// Body is the raw request body like "username=user&password=pass"
+	Body        string `json:"body,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+
+	// APIKeyPath is the JSONPath of a renewed api key in the login response like $.api_key, the key replaces
+	// the api key of the feed. The login may then set no cookies.
+	APIKeyPath string `json:"api_key_path,omitempty"`
+}
+
+func (l FeedLoginSettings) Validate() error {
+	u, err := url.Parse(l.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid login url: %s", l.URL)
+	}
+
+	switch strings.ToUpper(l.Method) {
+	case "", "GET", "POST":
+	default:
+		return errors.New("unsupported login method: %s", l.Method)
+	}
+
+	return nil
+}
+
+// HasLogin reports whether the feed refreshes its cookie with a login step, a login without url is unset
+func (s *FeedSettingsJSON) HasLogin() bool {
+	return s != nil && s.Login != nil && s.Login.URL != ""
+}
+
+// TitleAllowed reports whether the raw title of an item passes the title include and exclude patterns of the feed
+func (s *FeedSettingsJSON) TitleAllowed(title string) bool {
+	if s == nil {
+		return true
+	}
+
+	if include := feedTitlePatterns(s.TitleInclude); len(include) > 0 && !containsMatchFuzzy([]string{title}, include) {
+		return false
+	}
+
+	if exclude := feedTitlePatterns(s.TitleExclude); len(exclude) > 0 && containsMatchFuzzy([]string{title}, exclude) {
+		return false
+	}
+
+	return true
+}
+
+// feedTitlePatterns splits the comma separated patterns, dropping empty ones so "a, " does not match every title
+func feedTitlePatterns(raw string) []string {
+	patterns := make([]string, 0)
+	for _, pattern := range strings.Split(raw, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// ParseFeedHeaders parses request headers in the format "Name: value", one per line. Empty lines are skipped.
+func ParseFeedHeaders(raw string) (map[string]string, error) {
+	headers := map[string]string{}
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, errors.New("invalid header: %s", line)
+		}
+
+		headers[name] = strings.TrimSpace(value)
+	}
+
+	return headers, nil
+}
+
+// ValidateFeedProxy checks the proxy is an http, https or socks5 url
+func ValidateFeedProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return errors.New("invalid proxy url: %s", proxy)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+
+	return errors.New("unsupported proxy scheme: %s", u.Scheme)
+}
+
+// FailingAlertDuration returns the duration a feed fails before it is notified
+func (s *FeedSettingsJSON) FailingAlertDuration() time.Duration {
+	if s == nil || s.FailingAlertAfter == 0 {
+		return FeedFailingAlertDefault
+	}
+
+	return time.Duration(s.FailingAlertAfter) * time.Minute
+}
+
+const (
+	FeedBackfillDefaultCount = 100
+	FeedBackfillDefaultDelay = 10
+	FeedBackfillMaxCount     = 1000
+)
+
+// FeedBackfillSettings configures the backfill of a feed, which pages back through its older items and processes them
+// like new ones. The backfill runs when the feed is enabled the first time, or on demand.
+type FeedBackfillSettings struct {
+	OnFirstRun bool `json:"on_first_run"`
+
+	// Count is the maximum number of older items to process
+	Count int `json:"count,omitempty"`
+
+	// MaxAge in seconds, the backfill ends at the first older item
+	MaxAge int `json:"max_age,omitempty"`
+
+	// Delay in seconds between the pages
+	Delay int `json:"delay,omitempty"`
+}
+
+func (b FeedBackfillSettings) Validate() error {
+	if b.Count < 0 || b.MaxAge < 0 || b.Delay < 0 {
+		return errors.New("count, max age and delay can not be negative")
+	}
+
+	if b.Count > FeedBackfillMaxCount {
+		return errors.New("count can not be more than %d", FeedBackfillMaxCount)
+	}
+
+	return nil
+}
+
+// WithDefaults returns the settings with the default count and delay when they are not set
+func (b FeedBackfillSettings) WithDefaults() FeedBackfillSettings {
+	if b.Count == 0 {
+		b.Count = FeedBackfillDefaultCount
+	}
+
+	if b.Delay == 0 {
+		b.Delay = FeedBackfillDefaultDelay
+	}
+
+	return b
+}
+
+// CanBackfill reports whether the feed can be paged back, rss feeds and json feeds without a page parameter can not
+func (f *Feed) CanBackfill() bool {
+	switch FeedType(f.Type) {
+	case FeedTypeTorznab, FeedTypeNewznab:
+		return true
+	case FeedTypeJSON:
+		return f.Settings != nil && f.Settings.JSON != nil && f.Settings.JSON.PageParam != ""
+	}
+
+	return false
+}
+
+// Validate checks the settings of the feed that can not be checked by fetching it
+func (f *Feed) Validate() error {
+	if f.Type == string(FeedTypeJSON) || f.Type == string(FeedTypePush) {
+		if f.Settings == nil || f.Settings.JSON == nil {
+			return errors.New("%s feed requires json settings", strings.ToLower(f.Type))
+		}
+
+		if err := f.Settings.JSON.Validate(); err != nil {
+			return errors.Wrap(err, "invalid json settings")
+		}
+	}
+
+	if f.Type == string(FeedTypePush) {
+		if f.Settings.Push == nil {
+			return errors.New("push feed requires push settings")
+		}
+
+		if err := f.Settings.Push.Validate(f.URL); err != nil {
+			return errors.Wrap(err, "invalid push settings")
+		}
+	}
+
+	if f.Settings == nil {
+		return nil
+	}
+
+	if f.Settings.Cron != "" {
+		if _, err := cron.ParseStandard(f.Settings.Cron); err != nil {
+			return errors.Wrap(err, "invalid cron expression")
+		}
+	}
+
+	if f.Settings.NotBetween != "" {
+		if _, err := ParseFilterSchedule(f.Settings.NotBetween); err != nil {
+			return errors.Wrap(err, "invalid not between window")
+		}
+	}
+
+	if f.Settings.FailingAlertAfter < 0 {
+		return errors.New("failing alert after can not be negative")
+	}
+
+	if f.Settings.Proxy != "" {
+		if err := ValidateFeedProxy(f.Settings.Proxy); err != nil {
+			return err
+		}
+
+		// websockets are dialed through socks5 proxies only, an http proxy would be bypassed
+		if f.Type == string(FeedTypePush) && f.Settings.Push.Protocol == FeedPushProtocolWebSocket && !strings.HasPrefix(f.Settings.Proxy, "socks5") {
+			return errors.New("websocket push feeds only support socks5 proxies")
+		}
+	}
+
+	if _, err := ParseFeedHeaders(f.Settings.Headers); err != nil {
+		return errors.Wrap(err, "invalid headers")
+	}
+
+	if f.Settings.HasLogin() {
+		if err := f.Settings.Login.Validate(); err != nil {
+			return errors.Wrap(err, "invalid login settings")
+		}
+	}
+
+	for _, m := range f.Settings.CategoryMapping {
+		if err := m.Validate(); err != nil {
+			return errors.Wrap(err, "invalid category mapping")
+		}
+	}
+
+	if f.Settings.Backfill != nil {
+		if err := f.Settings.Backfill.Validate(); err != nil {
+			return errors.Wrap(err, "invalid backfill settings")
+		}
+
+		if f.Settings.Backfill.OnFirstRun && !f.CanBackfill() {
+			return errors.New("%s feed can not be backfilled", f.Type)
+		}
+	}
+
+	if f.Settings.FieldMapping != nil {
+		if err := f.Settings.FieldMapping.Validate(); err != nil {
+			return errors.Wrap(err, "invalid field mapping")
+		}
+	}
+
+	return nil
+}
+
+// FeedFieldMapping maps elements of the items of a generic rss or atom feed to release fields, for trackers that
+// put the size, category or info hash in their own elements. Empty fields keep the default parsing.
+//
+// Each field is an element path, see ParseFeedElementPath.
+type FeedFieldMapping struct {
+	Title       string `json:"title,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	MagnetURI   string `json:"magnet_uri,omitempty"`
+	Size        string `json:"size,omitempty"`
+	Category    string `json:"category,omitempty"`
+	InfoHash    string `json:"info_hash,omitempty"`
+	Uploader    string `json:"uploader,omitempty"`
+	Freeleech   string `json:"freeleech,omitempty"`
+}
+
+func (m *FeedFieldMapping) Validate() error {
+	for _, path := range []string{m.Title, m.DownloadURL, m.MagnetURI, m.Size, m.Category, m.InfoHash, m.Uploader, m.Freeleech} {
+		if path == "" {
+			continue
+		}
+
+		if _, err := ParseFeedElementPath(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FeedElementPath selects an element of a feed item, and optionally one of its attributes
+type FeedElementPath struct {
+	// Prefix is the namespace prefix of extension elements, like torznab
+	Prefix string
+	Name   string
+
+	// MatchAttr and MatchValue select the element among those with the same name, like <torznab:attr name="size">
+	MatchAttr  string
+	MatchValue string
+
+	// Attr reads the attribute instead of the value of the element
+	Attr string
+}
+
+var rxFeedElementPath = regexp.MustCompile(`^(?:([\w.-]+):)?([\w.-]+)(?:\[([\w:.-]+)=([^\]]*)\])?(?:@([\w:.-]+))?$`)
+
+// ParseFeedElementPath parses an element path like title, enclosure@length, size or torznab:attr[name=size]@value.
+// Elements without a prefix are the standard item elements or else custom elements of the item.
+func ParseFeedElementPath(path string) (FeedElementPath, error) {
+	m := rxFeedElementPath.FindStringSubmatch(path)
+	if m == nil {
+		return FeedElementPath{}, errors.New("invalid element path %s, want element, prefix:element, element[attr=value] or element@attr", path)
+	}
+
+	return FeedElementPath{
+		Prefix:     m[1],
+		Name:       m[2],
+		MatchAttr:  m[3],
+		MatchValue: m[4],
+		Attr:       m[5],
+	}, nil
+}
+
+// FeedJSONSettings configures feeds of plain json apis that list releases, like /api/releases of some trackers
+type FeedJSONSettings struct {
+	// ItemsPath is the JSONPath of the releases in the response, like $.data or $.results[*]
+	ItemsPath string               `json:"items_path"`
+	Fields    FeedJSONFieldMapping `json:"fields"`
+
+	// Headers are sent with each request, like the Authorization header of the api
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// PageParam is the query parameter of the page number, only the first page is fetched when empty.
+	// Pages are fetched from PageStart until a page has no new releases or MaxPages pages are fetched.
+	PageParam     string `json:"page_param,omitempty"`
+	PageStart     int    `json:"page_start,omitempty"`
+	MaxPages      int    `json:"max_pages,omitempty"`
+	PageSizeParam string `json:"page_size_param,omitempty"`
+	PageSize      int    `json:"page_size,omitempty"`
+}
+
+// FeedJSONFieldMapping maps the values of each release of a json feed to release fields.
+// The fields are JSONPaths relative to the release, like $.name or $.files[0].size.
+type FeedJSONFieldMapping struct {
+	GUID        string `json:"guid,omitempty"`
+	Title       string `json:"title"`
+	DownloadURL string `json:"download_url,omitempty"`
+	MagnetURI   string `json:"magnet_uri,omitempty"`
+	InfoURL     string `json:"info_url,omitempty"`
+	Size        string `json:"size,omitempty"`
+	Category    string `json:"category,omitempty"`
+	InfoHash    string `json:"info_hash,omitempty"`
+	Uploader    string `json:"uploader,omitempty"`
+	Freeleech   string `json:"freeleech,omitempty"`
+	Published   string `json:"published,omitempty"`
+}
+
+func (s *FeedJSONSettings) Validate() error {
+	if s.ItemsPath == "" {
+		return errors.New("items path required")
+	}
+
+	if s.Fields.Title == "" {
+		return errors.New("title field required")
+	}
+
+	if s.Fields.DownloadURL == "" && s.Fields.MagnetURI == "" {
+		return errors.New("download url or magnet uri field required")
+	}
+
+	if s.MaxPages < 0 || s.PageSize < 0 {
+		return errors.New("max pages and page size can not be negative")
+	}
+
+	f := s.Fields
+	for _, path := range []string{s.ItemsPath, f.GUID, f.Title, f.DownloadURL, f.MagnetURI, f.InfoURL, f.Size, f.Category, f.InfoHash, f.Uploader, f.Freeleech, f.Published} {
+		if path == "" {
+			continue
+		}
+
+		if _, err := jsonpath.Parse(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type FeedIndexer struct {
@@ -71,6 +516,8 @@ const (
 	FeedTypeTorznab FeedType = "TORZNAB"
 	FeedTypeNewznab FeedType = "NEWZNAB"
 	FeedTypeRSS     FeedType = "RSS"
+	FeedTypeJSON    FeedType = "JSON"
+	FeedTypePush    FeedType = "PUSH"
 )
 
 type FeedDownloadType string
@@ -81,8 +528,37 @@ const (
 )
 
 type FeedCacheItem struct {
-	FeedId string    `json:"feed_id"`
-	Key    string    `json:"key"`
-	Value  []byte    `json:"value"`
-	TTL    time.Time `json:"ttl"`
+	FeedId  string    `json:"feed_id"`
+	Key     string    `json:"key"`
+	Value   string    `json:"value"`
+	TTL     time.Time `json:"ttl"`
+	Created time.Time `json:"created"`
+}
+
+type FeedCacheStats struct {
+	FeedID int       `json:"feed_id"`
+	Count  int       `json:"count"`
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+}
+
+// FeedCache is the cache of a feed with its newest items
+type FeedCache struct {
+	FeedCacheStats
+	Items []FeedCacheItem `json:"items"`
+}
+
+// FeedPreview is a fetch of a feed parsed into releases, without the cache and filters
+type FeedPreview struct {
+	Items []FeedPreviewItem `json:"items"`
+}
+
+// FeedPreviewItem is an item of the feed with its release, or nil when it can not be parsed, and what may stop it from
+// being processed or grabbed
+type FeedPreviewItem struct {
+	Key       string    `json:"key"`
+	Title     string    `json:"title"`
+	Published time.Time `json:"published"`
+	Release   *Release  `json:"release"`
+	Warnings  []string  `json:"warnings"`
 }
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FeedCapsRefreshInterval is the age after which the caps of a torznab feed are refreshed
+const FeedCapsRefreshInterval = 24 * time.Hour
+
+// FeedCaps are the capabilities of a torznab feed with its category tree
+type FeedCaps struct {
+	Categories []FeedCategory `json:"categories"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type FeedCategory struct {
+	ID            int            `json:"id"`
+	Name          string         `json:"name"`
+	SubCategories []FeedCategory `json:"subcategories,omitempty"`
+}
+
+// FeedCategoryMapping adds Category to the releases of the items in the torznab category ID. Standard categories below
+// 10000 also match their subcategories, a mapping of 5000 matches items in 5040.
+type FeedCategoryMapping struct {
+	ID       int    `json:"id"`
+	Category string `json:"category"`
+}
+
+func (m FeedCategoryMapping) Validate() error {
+	if m.ID <= 0 {
+		return errors.New("invalid torznab category: %d", m.ID)
+	}
+
+	if m.Category == "" {
+		return errors.New("torznab category %d is mapped to an empty category", m.ID)
+	}
+
+	return nil
+}
+
+// Matches reports whether the mapping matches the torznab category
+func (m FeedCategoryMapping) Matches(id int) bool {
+	if m.ID == id {
+		return true
+	}
+
+	// standard parent categories like 5000 match their subcategories 5000-5999
+	return m.ID < 10000 && m.ID%1000 == 0 && id < 10000 && id-id%1000 == m.ID
+}
+
+// Stale reports whether the caps are missing or older than FeedCapsRefreshInterval
+func (c *FeedCaps) Stale(now time.Time) bool {
+	return c == nil || now.Sub(c.UpdatedAt) >= FeedCapsRefreshInterval
+}
+
+// HasCategory reports whether the category is in the category tree
+func (c *FeedCaps) HasCategory(id int) bool {
+	if c == nil {
+		return false
+	}
+
+	var has func(categories []FeedCategory) bool
+	has = func(categories []FeedCategory) bool {
+		for _, category := range categories {
+			if category.ID == id || has(category.SubCategories) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return has(c.Categories)
+}
+
+// StaleCategoryMappings returns the mappings of categories the indexer does not have anymore
+func (c *FeedCaps) StaleCategoryMappings(mappings []FeedCategoryMapping) []FeedCategoryMapping {
+	stale := make([]FeedCategoryMapping, 0)
+
+	if c == nil {
+		return stale
+	}
+
+	for _, m := range mappings {
+		if !c.HasCategory(m.ID) {
+			stale = append(stale, m)
+		}
+	}
+
+	return stale
+}
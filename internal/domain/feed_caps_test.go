@@ -0,0 +1,54 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedCategoryMapping_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping int
+		id      int
+		want    bool
+	}{
+		{name: "same", mapping: 5040, id: 5040, want: true},
+		{name: "parent", mapping: 5000, id: 5040, want: true},
+		{name: "other_parent", mapping: 5000, id: 2040, want: false},
+		{name: "subcategory", mapping: 5040, id: 5000, want: false},
+		{name: "custom", mapping: 100001, id: 100001, want: true},
+		{name: "custom_not_parent", mapping: 100000, id: 100001, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FeedCategoryMapping{ID: tt.mapping, Category: "TV"}.Matches(tt.id))
+		})
+	}
+}
+
+func TestFeedCaps_StaleCategoryMappings(t *testing.T) {
+	now := time.Now()
+
+	caps := &FeedCaps{
+		Categories: []FeedCategory{
+			{ID: 2000, Name: "Movies", SubCategories: []FeedCategory{{ID: 2040, Name: "Movies/HD"}}},
+			{ID: 100001, Name: "Anime"},
+		},
+		UpdatedAt: now.Add(-time.Hour),
+	}
+
+	mappings := []FeedCategoryMapping{{ID: 2040, Category: "Movies HD"}, {ID: 100001, Category: "Anime"}, {ID: 5000, Category: "TV"}}
+
+	assert.Equal(t, []FeedCategoryMapping{{ID: 5000, Category: "TV"}}, caps.StaleCategoryMappings(mappings))
+	assert.False(t, caps.Stale(now))
+	assert.True(t, caps.Stale(now.Add(FeedCapsRefreshInterval)))
+
+	var missing *FeedCaps
+	assert.True(t, missing.Stale(now))
+	assert.Empty(t, missing.StaleCategoryMappings(mappings))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type FeedHealthStatus string
+
+const (
+	FeedHealthOK       FeedHealthStatus = "OK"
+	FeedHealthDegraded FeedHealthStatus = "DEGRADED"
+	FeedHealthFailing  FeedHealthStatus = "FAILING"
+)
+
+const (
+	// FeedHealthFailingErrors is the number of errors in a row after which a feed is failing
+	FeedHealthFailingErrors = 3
+
+	// FeedBackoffMax caps the time between the refreshes of a failing feed
+	FeedBackoffMax = 6 * time.Hour
+
+	// FeedFailingAlertDefault is the default duration a feed fails before it is notified
+	FeedFailingAlertDefault = time.Hour
+)
+
+// FeedHealth is the state of the refreshes of a feed. A feed is degraded after an error and failing after
+// FeedHealthFailingErrors errors in a row. Failing feeds back off and are refreshed less often, until a refresh succeeds.
+type FeedHealth struct {
+	Status       FeedHealthStatus `json:"status"`
+	Errors       int              `json:"errors"`
+	LastError    string           `json:"last_error,omitempty"`
+	LastErrorAt  time.Time        `json:"last_error_at"`
+	FailingSince time.Time        `json:"failing_since"`
+	BackoffUntil time.Time        `json:"backoff_until"`
+
+	// Notified is set when the failing feed was notified
+	Notified bool `json:"-"`
+}
+
+func NewFeedHealth() *FeedHealth {
+	return &FeedHealth{Status: FeedHealthOK}
+}
+
+// RecordError records a failed refresh and backs off from the interval of the feed
+func (h *FeedHealth) RecordError(err error, now time.Time, interval time.Duration) {
+	if h.Errors == 0 {
+		h.FailingSince = now
+	}
+
+	h.Errors++
+	h.LastError = err.Error()
+	h.LastErrorAt = now
+	h.BackoffUntil = now.Add(FeedBackoff(h.Errors, interval))
+
+	h.Status = FeedHealthDegraded
+	if h.Errors >= FeedHealthFailingErrors {
+		h.Status = FeedHealthFailing
+	}
+}
+
+// RecordSuccess records a successful refresh and returns true when the feed recovered from errors
+func (h *FeedHealth) RecordSuccess() bool {
+	recovered := h.Errors > 0
+
+	h.Status = FeedHealthOK
+	h.Errors = 0
+	h.FailingSince = time.Time{}
+	h.BackoffUntil = time.Time{}
+
+	return recovered
+}
+
+// InBackoff reports whether the refresh at now should be skipped
+func (h *FeedHealth) InBackoff(now time.Time) bool {
+	return now.Before(h.BackoffUntil)
+}
+
+// ShouldNotify reports whether the feed failed for longer than alertAfter and was not notified yet
+func (h *FeedHealth) ShouldNotify(now time.Time, alertAfter time.Duration) bool {
+	return h.Status == FeedHealthFailing && !h.Notified && now.Sub(h.FailingSince) >= alertAfter
+}
+
+// FeedBackoff returns the time to wait after the errors in a row. The first error waits for the next refresh,
+// the next ones double the interval up to FeedBackoffMax.
+func FeedBackoff(errors int, interval time.Duration) time.Duration {
+	if errors < 2 || interval <= 0 {
+		return 0
+	}
+
+	backoff := interval
+	for i := 1; i < errors; i++ {
+		backoff *= 2
+		if backoff >= FeedBackoffMax {
+			return FeedBackoffMax
+		}
+	}
+
+	return backoff
+}
+
+// FeedAuthFailedNotification returns the notification of a feed that is refused even after logging in again,
+// or whose login fails
+func FeedAuthFailedNotification(feed *Feed, err error) (NotificationEvent, NotificationPayload) {
+	return NotificationEventFeedAuthFailed, NotificationPayload{
+		Subject:   "Feed authentication failed",
+		Message:   fmt.Sprintf("Feed: %s could not authenticate, check its api key, cookie or login\nError: %s", feed.Name, err),
+		Indexer:   feed.Indexer,
+		Reason:    err.Error(),
+		Timestamp: time.Now(),
+	}
+}
+
+// FeedHealthNotification returns the notification of a failing or recovered feed
+func FeedHealthNotification(feed *Feed, health *FeedHealth, recovered bool) (NotificationEvent, NotificationPayload) {
+	if recovered {
+		return NotificationEventFeedRecovered, NotificationPayload{
+			Subject:   "Feed recovered",
+			Message:   fmt.Sprintf("Feed: %s is refreshing again", feed.Name),
+			Indexer:   feed.Indexer,
+			Timestamp: time.Now(),
+		}
+	}
+
+	return NotificationEventFeedFailing, NotificationPayload{
+		Subject:   "Feed failing",
+		Message:   fmt.Sprintf("Feed: %s failed %d times in a row since %s\nError: %s", feed.Name, health.Errors, health.FailingSince.Format(time.RFC3339), health.LastError),
+		Indexer:   feed.Indexer,
+		Reason:    health.LastError,
+		Timestamp: time.Now(),
+	}
+}
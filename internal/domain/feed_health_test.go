@@ -0,0 +1,59 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		errors   int
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "first_error", errors: 1, interval: 15 * time.Minute, want: 0},
+		{name: "second_error", errors: 2, interval: 15 * time.Minute, want: 30 * time.Minute},
+		{name: "fourth_error", errors: 4, interval: 15 * time.Minute, want: 2 * time.Hour},
+		{name: "capped", errors: 10, interval: 15 * time.Minute, want: FeedBackoffMax},
+		{name: "no_interval", errors: 3, interval: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FeedBackoff(tt.errors, tt.interval))
+		})
+	}
+}
+
+func TestFeedHealth(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewFeedHealth()
+
+	h.RecordError(errors.New("timeout"), start, 15*time.Minute)
+	assert.Equal(t, FeedHealthDegraded, h.Status)
+	assert.False(t, h.InBackoff(start.Add(time.Minute)))
+
+	h.RecordError(errors.New("timeout"), start.Add(15*time.Minute), 15*time.Minute)
+	assert.Equal(t, FeedHealthDegraded, h.Status)
+	assert.True(t, h.InBackoff(start.Add(30*time.Minute)))
+	assert.False(t, h.InBackoff(start.Add(45*time.Minute)))
+
+	h.RecordError(errors.New("bad gateway"), start.Add(45*time.Minute), 15*time.Minute)
+	assert.Equal(t, FeedHealthFailing, h.Status)
+	assert.Equal(t, "bad gateway", h.LastError)
+	assert.Equal(t, start, h.FailingSince)
+	assert.False(t, h.ShouldNotify(start.Add(45*time.Minute), time.Hour))
+	assert.True(t, h.ShouldNotify(start.Add(time.Hour), time.Hour))
+
+	assert.True(t, h.RecordSuccess())
+	assert.Equal(t, FeedHealthOK, h.Status)
+	assert.Equal(t, 0, h.Errors)
+	assert.False(t, h.InBackoff(start.Add(46*time.Minute)))
+	assert.False(t, h.RecordSuccess())
+}
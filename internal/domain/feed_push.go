@@ -0,0 +1,54 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"net/url"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type FeedPushProtocol string
+
+const (
+	FeedPushProtocolSSE       FeedPushProtocol = "SSE"
+	FeedPushProtocolWebSocket FeedPushProtocol = "WEBSOCKET"
+)
+
+// FeedPushSettings are the settings of a feed that streams new uploads over server-sent events or a websocket
+// instead of being polled. Each message is json, its releases are selected and mapped with the json settings.
+type FeedPushSettings struct {
+	Protocol FeedPushProtocol `json:"protocol"`
+
+	// Event is the server-sent event type of new uploads, all events are read when empty
+	Event string `json:"event,omitempty"`
+
+	// Subscribe is sent as a text message once the websocket is connected, like {"op":"subscribe","channel":"uploads"}
+	Subscribe string `json:"subscribe,omitempty"`
+}
+
+// Validate checks the protocol and that the url of the feed has a scheme of the protocol
+func (s *FeedPushSettings) Validate(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return errors.New("invalid url: %s", rawURL)
+	}
+
+	switch s.Protocol {
+	case FeedPushProtocolSSE:
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.New("server-sent events require an http or https url: %s", rawURL)
+		}
+
+	case FeedPushProtocolWebSocket:
+		if u.Scheme != "ws" && u.Scheme != "wss" {
+			return errors.New("websocket requires a ws or wss url: %s", rawURL)
+		}
+
+	default:
+		return errors.New("unsupported push protocol: %s", s.Protocol)
+	}
+
+	return nil
+}
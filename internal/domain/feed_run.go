@@ -0,0 +1,31 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import "time"
+
+// FeedRunsKept is the number of runs kept in memory per feed
+const FeedRunsKept = 50
+
+// FeedRun is the diagnostics of a refresh of a feed. Matched is counted when the filters are done with the new
+// releases, which can be after the refresh finished.
+type FeedRun struct {
+	FeedID     int       `json:"feed_id"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Items      int       `json:"items"`
+	NewItems   int       `json:"new_items"`
+	Matched    int       `json:"matched"`
+	HTTPStatus int       `json:"http_status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// FeedRunStats are the last run of a feed and the totals of its runs since start
+type FeedRunStats struct {
+	LastRun  *FeedRun `json:"last_run,omitempty"`
+	Runs     int      `json:"runs"`
+	Errors   int      `json:"errors"`
+	NewItems int      `json:"new_items"`
+	Matched  int      `json:"matched"`
+}
@@ -5,14 +5,19 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/wildcard"
 
 	"github.com/dustin/go-humanize"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
 )
 
 /*
@@ -36,9 +41,44 @@ type FilterRepo interface {
 	StoreFilterExternal(ctx context.Context, filterID int, externalFilters []FilterExternal) error
 	DeleteIndexerConnections(ctx context.Context, filterID int) error
 	DeleteFilterExternal(ctx context.Context, filterID int) error
-	GetDownloadsByFilterId(ctx context.Context, filterID int) (*FilterDownloads, error)
+	FindListSources(ctx context.Context, filterID int) ([]FilterListSource, error)
+	StoreListSources(ctx context.Context, filterID int, sources []FilterListSource) error
+	UpdateListSourceRefresh(ctx context.Context, sourceID int, refreshedAt time.Time, lastError string) error
+	DeleteListSources(ctx context.Context, filterID int) error
+	GetDownloadsByFilterId(ctx context.Context, params FilterDownloadsParams) (*FilterDownloads, error)
+	FindPresets(ctx context.Context) ([]Filter, error)
 }
 
+// FilterDryRunResult is the outcome of checking a release against a filter without taking any action
+type FilterDryRunResult struct {
+	FilterID   int      `json:"filter_id"`
+	Name       string   `json:"name"`
+	Priority   int32    `json:"priority"`
+	Match      bool     `json:"match"`
+	Rejections []string `json:"rejections"`
+	// Notes lists checks that were skipped because they have side effects
+	Notes []string `json:"notes"`
+}
+
+// FilterSimulation is the outcome of replaying stored releases through a filter
+type FilterSimulation struct {
+	Total   int                      `json:"total"`
+	Matched int                      `json:"matched"`
+	Results []FilterSimulationResult `json:"results"`
+}
+
+type FilterSimulationResult struct {
+	ReleaseID   int64     `json:"release_id"`
+	TorrentName string    `json:"torrent_name"`
+	Indexer     string    `json:"indexer"`
+	Timestamp   time.Time `json:"timestamp"`
+	Match       bool      `json:"match"`
+	Rejections  []string  `json:"rejections"`
+	Notes       []string  `json:"notes"`
+}
+
+// FilterDownloads holds the downloads of a filter within sliding windows ending now.
+// Each window is Interval units long, so with an interval of 6 HourCount holds the downloads of the last 6 hours.
 type FilterDownloads struct {
 	HourCount  int
 	DayCount   int
@@ -47,6 +87,38 @@ type FilterDownloads struct {
 	TotalCount int
 }
 
+// FilterDownloadsParams selects which downloads of a filter are counted
+type FilterDownloadsParams struct {
+	FilterID int
+
+	// Indexer only counts downloads of releases from this indexer when set
+	Indexer string
+
+	// Interval is the number of units in each window, defaults to 1
+	Interval int
+}
+
+// Window returns the length of the sliding window for the unit, or 0 for EVER
+func (p FilterDownloadsParams) Window(unit FilterMaxDownloadsUnit) time.Duration {
+	interval := p.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch unit {
+	case FilterMaxDownloadsHour:
+		return time.Duration(interval) * time.Hour
+	case FilterMaxDownloadsDay:
+		return time.Duration(interval) * 24 * time.Hour
+	case FilterMaxDownloadsWeek:
+		return time.Duration(interval) * 7 * 24 * time.Hour
+	case FilterMaxDownloadsMonth:
+		return time.Duration(interval) * 30 * 24 * time.Hour
+	}
+
+	return 0
+}
+
 type FilterMaxDownloadsUnit string
 
 const (
@@ -61,97 +133,157 @@ type FilterQueryParams struct {
 	Sort    map[string]string
 	Filters struct {
 		Indexers []string
+		Tags     []string
 	}
 	Search string
 }
 
 type Filter struct {
-	ID                   int                    `json:"id"`
-	Name                 string                 `json:"name"`
-	Enabled              bool                   `json:"enabled"`
-	CreatedAt            time.Time              `json:"created_at"`
-	UpdatedAt            time.Time              `json:"updated_at"`
-	MinSize              string                 `json:"min_size,omitempty"`
-	MaxSize              string                 `json:"max_size,omitempty"`
-	Delay                int                    `json:"delay,omitempty"`
-	Priority             int32                  `json:"priority"`
-	MaxDownloads         int                    `json:"max_downloads,omitempty"`
-	MaxDownloadsUnit     FilterMaxDownloadsUnit `json:"max_downloads_unit,omitempty"`
-	MatchReleases        string                 `json:"match_releases,omitempty"`
-	ExceptReleases       string                 `json:"except_releases,omitempty"`
-	UseRegex             bool                   `json:"use_regex,omitempty"`
-	MatchReleaseGroups   string                 `json:"match_release_groups,omitempty"`
-	ExceptReleaseGroups  string                 `json:"except_release_groups,omitempty"`
-	Scene                bool                   `json:"scene,omitempty"`
-	Origins              []string               `json:"origins,omitempty"`
-	ExceptOrigins        []string               `json:"except_origins,omitempty"`
-	Bonus                []string               `json:"bonus,omitempty"`
-	Freeleech            bool                   `json:"freeleech,omitempty"`
-	FreeleechPercent     string                 `json:"freeleech_percent,omitempty"`
-	SmartEpisode         bool                   `json:"smart_episode"`
-	Shows                string                 `json:"shows,omitempty"`
-	Seasons              string                 `json:"seasons,omitempty"`
-	Episodes             string                 `json:"episodes,omitempty"`
-	Resolutions          []string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
-	Codecs               []string               `json:"codecs,omitempty"`      // XviD, DivX, x264, h.264 (or h264), mpeg2 (or mpeg-2), VC-1 (or VC1), WMV, Remux, h.264 Remux (or h264 Remux), VC-1 Remux (or VC1 Remux).
-	Sources              []string               `json:"sources,omitempty"`     // DSR, PDTV, HDTV, HR.PDTV, HR.HDTV, DVDRip, DVDScr, BDr, BD5, BD9, BDRip, BRRip, DVDR, MDVDR, HDDVD, HDDVDRip, BluRay, WEB-DL, TVRip, CAM, R5, TELESYNC, TS, TELECINE, TC. TELESYNC and TS are synonyms (you don't need both). Same for TELECINE and TC
-	Containers           []string               `json:"containers,omitempty"`
-	MatchHDR             []string               `json:"match_hdr,omitempty"`
-	ExceptHDR            []string               `json:"except_hdr,omitempty"`
-	MatchOther           []string               `json:"match_other,omitempty"`
-	ExceptOther          []string               `json:"except_other,omitempty"`
-	Years                string                 `json:"years,omitempty"`
-	Artists              string                 `json:"artists,omitempty"`
-	Albums               string                 `json:"albums,omitempty"`
-	MatchReleaseTypes    []string               `json:"match_release_types,omitempty"` // Album,Single,EP
-	ExceptReleaseTypes   string                 `json:"except_release_types,omitempty"`
-	Formats              []string               `json:"formats,omitempty"` // MP3, FLAC, Ogg, AAC, AC3, DTS
-	Quality              []string               `json:"quality,omitempty"` // 192, 320, APS (VBR), V2 (VBR), V1 (VBR), APX (VBR), V0 (VBR), q8.x (VBR), Lossless, 24bit Lossless, Other
-	Media                []string               `json:"media,omitempty"`   // CD, DVD, Vinyl, Soundboard, SACD, DAT, Cassette, WEB, Other
-	PerfectFlac          bool                   `json:"perfect_flac,omitempty"`
-	Cue                  bool                   `json:"cue,omitempty"`
-	Log                  bool                   `json:"log,omitempty"`
-	LogScore             int                    `json:"log_score,omitempty"`
-	MatchCategories      string                 `json:"match_categories,omitempty"`
-	ExceptCategories     string                 `json:"except_categories,omitempty"`
-	MatchUploaders       string                 `json:"match_uploaders,omitempty"`
-	ExceptUploaders      string                 `json:"except_uploaders,omitempty"`
-	MatchLanguage        []string               `json:"match_language,omitempty"`
-	ExceptLanguage       []string               `json:"except_language,omitempty"`
-	Tags                 string                 `json:"tags,omitempty"`
-	ExceptTags           string                 `json:"except_tags,omitempty"`
-	TagsAny              string                 `json:"tags_any,omitempty"`
-	ExceptTagsAny        string                 `json:"except_tags_any,omitempty"`
-	TagsMatchLogic       string                 `json:"tags_match_logic,omitempty"`
-	ExceptTagsMatchLogic string                 `json:"except_tags_match_logic,omitempty"`
-	MatchReleaseTags     string                 `json:"match_release_tags,omitempty"`
-	ExceptReleaseTags    string                 `json:"except_release_tags,omitempty"`
-	UseRegexReleaseTags  bool                   `json:"use_regex_release_tags,omitempty"`
-	MatchDescription     string                 `json:"match_description,omitempty"`
-	ExceptDescription    string                 `json:"except_description,omitempty"`
-	UseRegexDescription  bool                   `json:"use_regex_description,omitempty"`
-	ActionsCount         int                    `json:"actions_count"`
-	Actions              []*Action              `json:"actions,omitempty"`
-	External             []FilterExternal       `json:"external,omitempty"`
-	Indexers             []Indexer              `json:"indexers"`
-	Downloads            *FilterDownloads       `json:"-"`
+	ID                      int                    `json:"id"`
+	Name                    string                 `json:"name"`
+	Enabled                 bool                   `json:"enabled"`
+	CreatedAt               time.Time              `json:"created_at"`
+	UpdatedAt               time.Time              `json:"updated_at"`
+	MinSize                 string                 `json:"min_size,omitempty"`
+	MaxSize                 string                 `json:"max_size,omitempty"`
+	VerifyTorrent           bool                   `json:"verify_torrent,omitempty"`
+	MinFileCount            int                    `json:"min_file_count,omitempty"`
+	MaxFileCount            int                    `json:"max_file_count,omitempty"`
+	MatchFileExtensions     string                 `json:"match_file_extensions,omitempty"`
+	ExceptFileExtensions    string                 `json:"except_file_extensions,omitempty"`
+	Delay                   int                    `json:"delay,omitempty"`
+	DelayJitter             int                    `json:"delay_jitter,omitempty"`
+	Priority                int32                  `json:"priority"`
+	MaxDownloads            int                    `json:"max_downloads,omitempty"`
+	MaxDownloadsUnit        FilterMaxDownloadsUnit `json:"max_downloads_unit,omitempty"`
+	MaxDownloadsInterval    int                    `json:"max_downloads_interval,omitempty"`
+	MaxDownloadsPerIndexer  bool                   `json:"max_downloads_per_indexer,omitempty"`
+	MatchReleases           string                 `json:"match_releases,omitempty"`
+	ExceptReleases          string                 `json:"except_releases,omitempty"`
+	UseRegex                bool                   `json:"use_regex,omitempty"`
+	MatchReleaseGroups      string                 `json:"match_release_groups,omitempty"`
+	ExceptReleaseGroups     string                 `json:"except_release_groups,omitempty"`
+	UseRegexReleaseGroups   bool                   `json:"use_regex_release_groups,omitempty"`
+	Scene                   bool                   `json:"scene,omitempty"`
+	Origins                 []string               `json:"origins,omitempty"`
+	ExceptOrigins           []string               `json:"except_origins,omitempty"`
+	Bonus                   []string               `json:"bonus,omitempty"`
+	Freeleech               bool                   `json:"freeleech,omitempty"`
+	FreeleechPercent        string                 `json:"freeleech_percent,omitempty"`
+	FreeleechTokens         bool                   `json:"freeleech_tokens,omitempty"`
+	MinSeeders              int                    `json:"min_seeders,omitempty"`
+	MinLeechers             int                    `json:"min_leechers,omitempty"`
+	OnReject                FilterChainMode        `json:"on_reject,omitempty"`
+	OnRejectFilterID        int                    `json:"on_reject_filter_id,omitempty"`
+	OnActionFailure         FilterChainMode        `json:"on_action_failure,omitempty"`
+	OnActionFailureFilterID int                    `json:"on_action_failure_filter_id,omitempty"`
+	SmartEpisode            bool                   `json:"smart_episode"`
+	PreferSeasonPacks       bool                   `json:"prefer_season_packs,omitempty"`
+	SeasonPackHold          int                    `json:"season_pack_hold,omitempty"`
+	ProperUpgrades          bool                   `json:"proper_upgrades,omitempty"`
+	ProperUpgradesReplace   bool                   `json:"proper_upgrades_replace,omitempty"`
+	ArrUpgradeOnly          bool                   `json:"arr_upgrade_only,omitempty"`
+	Shows                   string                 `json:"shows,omitempty"`
+	UseRegexShows           bool                   `json:"use_regex_shows,omitempty"`
+	Seasons                 string                 `json:"seasons,omitempty"`
+	Episodes                string                 `json:"episodes,omitempty"`
+	AbsoluteEpisodes        string                 `json:"absolute_episodes,omitempty"`
+	AirDates                string                 `json:"air_dates,omitempty"`
+	MaxAirDateAge           int                    `json:"max_air_date_age,omitempty"`
+	AnimeBatch              FilterAnimeBatch       `json:"anime_batch,omitempty"`
+	AnimeSubType            AnimeSubType           `json:"anime_sub_type,omitempty"`
+	AnimeGroupTiers         string                 `json:"anime_group_tiers,omitempty"`
+	AnimeMaxGroupTier       int                    `json:"anime_max_group_tier,omitempty"`
+	Resolutions             []string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
+	Codecs                  []string               `json:"codecs,omitempty"`      // XviD, DivX, x264, h.264 (or h264), mpeg2 (or mpeg-2), VC-1 (or VC1), WMV, Remux, h.264 Remux (or h264 Remux), VC-1 Remux (or VC1 Remux).
+	Sources                 []string               `json:"sources,omitempty"`     // DSR, PDTV, HDTV, HR.PDTV, HR.HDTV, DVDRip, DVDScr, BDr, BD5, BD9, BDRip, BRRip, DVDR, MDVDR, HDDVD, HDDVDRip, BluRay, WEB-DL, TVRip, CAM, R5, TELESYNC, TS, TELECINE, TC. TELESYNC and TS are synonyms (you don't need both). Same for TELECINE and TC
+	Containers              []string               `json:"containers,omitempty"`
+	MatchHDR                []string               `json:"match_hdr,omitempty"`
+	ExceptHDR               []string               `json:"except_hdr,omitempty"`
+	HDRPreference           []string               `json:"hdr_preference,omitempty"`
+	RejectDVWithoutFallback bool                   `json:"reject_dv_without_fallback,omitempty"`
+	MatchOther              []string               `json:"match_other,omitempty"`
+	ExceptOther             []string               `json:"except_other,omitempty"`
+	Years                   string                 `json:"years,omitempty"`
+	Artists                 string                 `json:"artists,omitempty"`
+	Albums                  string                 `json:"albums,omitempty"`
+	MatchReleaseTypes       []string               `json:"match_release_types,omitempty"` // Album,Single,EP
+	ExceptReleaseTypes      string                 `json:"except_release_types,omitempty"`
+	Formats                 []string               `json:"formats,omitempty"` // MP3, FLAC, Ogg, AAC, AC3, DTS
+	Quality                 []string               `json:"quality,omitempty"` // 192, 320, APS (VBR), V2 (VBR), V1 (VBR), APX (VBR), V0 (VBR), q8.x (VBR), Lossless, 24bit Lossless, Other
+	Media                   []string               `json:"media,omitempty"`   // CD, DVD, Vinyl, Soundboard, SACD, DAT, Cassette, WEB, Other
+	BitDepths               []string               `json:"bit_depths,omitempty"`
+	SampleRates             []string               `json:"sample_rates,omitempty"`
+	AudioChannels           []string               `json:"audio_channels,omitempty"`
+	PerfectFlac             bool                   `json:"perfect_flac,omitempty"`
+	Cue                     bool                   `json:"cue,omitempty"`
+	Log                     bool                   `json:"log,omitempty"`
+	LogScore                int                    `json:"log_score,omitempty"`
+	MatchCategories         string                 `json:"match_categories,omitempty"`
+	ExceptCategories        string                 `json:"except_categories,omitempty"`
+	MatchUploaders          string                 `json:"match_uploaders,omitempty"`
+	ExceptUploaders         string                 `json:"except_uploaders,omitempty"`
+	UseRegexUploaders       bool                   `json:"use_regex_uploaders,omitempty"`
+	MatchLanguage           []string               `json:"match_language,omitempty"`
+	ExceptLanguage          []string               `json:"except_language,omitempty"`
+	LanguagePreference      []string               `json:"language_preference,omitempty"`
+	SubtitlePreference      []string               `json:"subtitle_preference,omitempty"`
+	ExceptLanguageOnly      []string               `json:"except_language_only,omitempty"`
+	RewriteRules            []FilterRewriteRule    `json:"rewrite_rules,omitempty"`
+	Tags                    string                 `json:"tags,omitempty"`
+	ExceptTags              string                 `json:"except_tags,omitempty"`
+	TagsAny                 string                 `json:"tags_any,omitempty"`
+	ExceptTagsAny           string                 `json:"except_tags_any,omitempty"`
+	TagsMatchLogic          string                 `json:"tags_match_logic,omitempty"`
+	ExceptTagsMatchLogic    string                 `json:"except_tags_match_logic,omitempty"`
+	UseRegexTags            bool                   `json:"use_regex_tags,omitempty"`
+	MatchReleaseTags        string                 `json:"match_release_tags,omitempty"`
+	ExceptReleaseTags       string                 `json:"except_release_tags,omitempty"`
+	UseRegexReleaseTags     bool                   `json:"use_regex_release_tags,omitempty"`
+	MatchDescription        string                 `json:"match_description,omitempty"`
+	ExceptDescription       string                 `json:"except_description,omitempty"`
+	UseRegexDescription     bool                   `json:"use_regex_description,omitempty"`
+	Expression              string                 `json:"expression,omitempty"`
+	Schedule                string                 `json:"schedule,omitempty"`
+	PresetID                string                 `json:"preset_id,omitempty"`
+	PresetSource            string                 `json:"preset_source,omitempty"`
+	PresetManaged           bool                   `json:"preset_managed,omitempty"`
+	PresetHash              string                 `json:"preset_hash,omitempty"`
+	FilterTags              []string               `json:"filter_tags,omitempty"`
+	Flags                   string                 `json:"flags,omitempty"`
+	ExceptFlags             string                 `json:"except_flags,omitempty"`
+	GroupID                 int                    `json:"group_id,omitempty"`
+	ActionsCount            int                    `json:"actions_count"`
+	Actions                 []*Action              `json:"actions,omitempty"`
+	External                []FilterExternal       `json:"external,omitempty"`
+	ListSources             []FilterListSource     `json:"list_sources,omitempty"`
+	Indexers                []Indexer              `json:"indexers"`
+	Downloads               *FilterDownloads       `json:"-"`
 }
 
 type FilterExternal struct {
-	ID                  int                `json:"id"`
-	Name                string             `json:"name"`
-	Index               int                `json:"index"`
-	Type                FilterExternalType `json:"type"`
-	Enabled             bool               `json:"enabled"`
-	ExecCmd             string             `json:"exec_cmd,omitempty"`
-	ExecArgs            string             `json:"exec_args,omitempty"`
-	ExecExpectStatus    int                `json:"exec_expect_status,omitempty"`
-	WebhookHost         string             `json:"webhook_host,omitempty"`
-	WebhookMethod       string             `json:"webhook_method,omitempty"`
-	WebhookData         string             `json:"webhook_data,omitempty"`
-	WebhookHeaders      string             `json:"webhook_headers,omitempty"`
-	WebhookExpectStatus int                `json:"webhook_expect_status,omitempty"`
-	FilterId            int                `json:"-"`
+	ID                   int                    `json:"id"`
+	Name                 string                 `json:"name"`
+	Index                int                    `json:"index"`
+	Type                 FilterExternalType     `json:"type"`
+	Enabled              bool                   `json:"enabled"`
+	ExecCmd              string                 `json:"exec_cmd,omitempty"`
+	ExecArgs             string                 `json:"exec_args,omitempty"`
+	ExecExpectStatus     int                    `json:"exec_expect_status,omitempty"`
+	ExecMode             FilterExternalExecMode `json:"exec_mode,omitempty"`
+	ExecTimeout          int                    `json:"exec_timeout,omitempty"`
+	ExecEnv              string                 `json:"exec_env,omitempty"`
+	WebhookHost          string                 `json:"webhook_host,omitempty"`
+	WebhookMethod        string                 `json:"webhook_method,omitempty"`
+	WebhookData          string                 `json:"webhook_data,omitempty"`
+	WebhookHeaders       string                 `json:"webhook_headers,omitempty"`
+	WebhookExpectStatus  int                    `json:"webhook_expect_status,omitempty"`
+	WebhookRetryStatus   string                 `json:"webhook_retry_status,omitempty"`
+	WebhookRetryAttempts int                    `json:"webhook_retry_attempts,omitempty"`
+	WebhookRetryDelay    int                    `json:"webhook_retry_delay_seconds,omitempty"`
+	WebhookJSONPath      string                 `json:"webhook_expect_json_path,omitempty"`
+	WebhookJSONValue     string                 `json:"webhook_expect_json_value,omitempty"`
+	FilterId             int                    `json:"-"`
 }
 
 type FilterExternalType string
@@ -167,37 +299,78 @@ type FilterUpdate struct {
 	Enabled                     *bool                   `json:"enabled,omitempty"`
 	MinSize                     *string                 `json:"min_size,omitempty"`
 	MaxSize                     *string                 `json:"max_size,omitempty"`
+	VerifyTorrent               *bool                   `json:"verify_torrent,omitempty"`
+	MinFileCount                *int                    `json:"min_file_count,omitempty"`
+	MaxFileCount                *int                    `json:"max_file_count,omitempty"`
+	MatchFileExtensions         *string                 `json:"match_file_extensions,omitempty"`
+	ExceptFileExtensions        *string                 `json:"except_file_extensions,omitempty"`
 	Delay                       *int                    `json:"delay,omitempty"`
+	DelayJitter                 *int                    `json:"delay_jitter,omitempty"`
 	Priority                    *int32                  `json:"priority,omitempty"`
 	MaxDownloads                *int                    `json:"max_downloads,omitempty"`
 	MaxDownloadsUnit            *FilterMaxDownloadsUnit `json:"max_downloads_unit,omitempty"`
+	MaxDownloadsInterval        *int                    `json:"max_downloads_interval,omitempty"`
+	MaxDownloadsPerIndexer      *bool                   `json:"max_downloads_per_indexer,omitempty"`
 	MatchReleases               *string                 `json:"match_releases,omitempty"`
 	ExceptReleases              *string                 `json:"except_releases,omitempty"`
 	UseRegex                    *bool                   `json:"use_regex,omitempty"`
 	MatchReleaseGroups          *string                 `json:"match_release_groups,omitempty"`
 	ExceptReleaseGroups         *string                 `json:"except_release_groups,omitempty"`
+	UseRegexReleaseGroups       *bool                   `json:"use_regex_release_groups,omitempty"`
 	MatchReleaseTags            *string                 `json:"match_release_tags,omitempty"`
 	ExceptReleaseTags           *string                 `json:"except_release_tags,omitempty"`
 	UseRegexReleaseTags         *bool                   `json:"use_regex_release_tags,omitempty"`
 	MatchDescription            *string                 `json:"match_description,omitempty"`
 	ExceptDescription           *string                 `json:"except_description,omitempty"`
 	UseRegexDescription         *bool                   `json:"use_regex_description,omitempty"`
+	Expression                  *string                 `json:"expression,omitempty"`
+	Schedule                    *string                 `json:"schedule,omitempty"`
+	PresetID                    *string                 `json:"preset_id,omitempty"`
+	PresetSource                *string                 `json:"preset_source,omitempty"`
+	PresetManaged               *bool                   `json:"preset_managed,omitempty"`
+	PresetHash                  *string                 `json:"preset_hash,omitempty"`
+	FilterTags                  *[]string               `json:"filter_tags,omitempty"`
+	Flags                       *string                 `json:"flags,omitempty"`
+	ExceptFlags                 *string                 `json:"except_flags,omitempty"`
+	GroupID                     *int                    `json:"group_id,omitempty"`
 	Scene                       *bool                   `json:"scene,omitempty"`
 	Origins                     *[]string               `json:"origins,omitempty"`
 	ExceptOrigins               *[]string               `json:"except_origins,omitempty"`
 	Bonus                       *[]string               `json:"bonus,omitempty"`
 	Freeleech                   *bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent            *string                 `json:"freeleech_percent,omitempty"`
+	FreeleechTokens             *bool                   `json:"freeleech_tokens,omitempty"`
+	MinSeeders                  *int                    `json:"min_seeders,omitempty"`
+	MinLeechers                 *int                    `json:"min_leechers,omitempty"`
+	OnReject                    *FilterChainMode        `json:"on_reject,omitempty"`
+	OnRejectFilterID            *int                    `json:"on_reject_filter_id,omitempty"`
+	OnActionFailure             *FilterChainMode        `json:"on_action_failure,omitempty"`
+	OnActionFailureFilterID     *int                    `json:"on_action_failure_filter_id,omitempty"`
 	SmartEpisode                *bool                   `json:"smart_episode,omitempty"`
+	PreferSeasonPacks           *bool                   `json:"prefer_season_packs,omitempty"`
+	SeasonPackHold              *int                    `json:"season_pack_hold,omitempty"`
+	ProperUpgrades              *bool                   `json:"proper_upgrades,omitempty"`
+	ProperUpgradesReplace       *bool                   `json:"proper_upgrades_replace,omitempty"`
+	ArrUpgradeOnly              *bool                   `json:"arr_upgrade_only,omitempty"`
 	Shows                       *string                 `json:"shows,omitempty"`
+	UseRegexShows               *bool                   `json:"use_regex_shows,omitempty"`
 	Seasons                     *string                 `json:"seasons,omitempty"`
 	Episodes                    *string                 `json:"episodes,omitempty"`
+	AbsoluteEpisodes            *string                 `json:"absolute_episodes,omitempty"`
+	AirDates                    *string                 `json:"air_dates,omitempty"`
+	MaxAirDateAge               *int                    `json:"max_air_date_age,omitempty"`
+	AnimeBatch                  *FilterAnimeBatch       `json:"anime_batch,omitempty"`
+	AnimeSubType                *AnimeSubType           `json:"anime_sub_type,omitempty"`
+	AnimeGroupTiers             *string                 `json:"anime_group_tiers,omitempty"`
+	AnimeMaxGroupTier           *int                    `json:"anime_max_group_tier,omitempty"`
 	Resolutions                 *[]string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
 	Codecs                      *[]string               `json:"codecs,omitempty"`      // XviD, DivX, x264, h.264 (or h264), mpeg2 (or mpeg-2), VC-1 (or VC1), WMV, Remux, h.264 Remux (or h264 Remux), VC-1 Remux (or VC1 Remux).
 	Sources                     *[]string               `json:"sources,omitempty"`     // DSR, PDTV, HDTV, HR.PDTV, HR.HDTV, DVDRip, DVDScr, BDr, BD5, BD9, BDRip, BRRip, DVDR, MDVDR, HDDVD, HDDVDRip, BluRay, WEB-DL, TVRip, CAM, R5, TELESYNC, TS, TELECINE, TC. TELESYNC and TS are synonyms (you don't need both). Same for TELECINE and TC
 	Containers                  *[]string               `json:"containers,omitempty"`
 	MatchHDR                    *[]string               `json:"match_hdr,omitempty"`
 	ExceptHDR                   *[]string               `json:"except_hdr,omitempty"`
+	HDRPreference               *[]string               `json:"hdr_preference,omitempty"`
+	RejectDVWithoutFallback     *bool                   `json:"reject_dv_without_fallback,omitempty"`
 	MatchOther                  *[]string               `json:"match_other,omitempty"`
 	ExceptOther                 *[]string               `json:"except_other,omitempty"`
 	Years                       *string                 `json:"years,omitempty"`
@@ -208,6 +381,9 @@ type FilterUpdate struct {
 	Formats                     *[]string               `json:"formats,omitempty"` // MP3, FLAC, Ogg, AAC, AC3, DTS
 	Quality                     *[]string               `json:"quality,omitempty"` // 192, 320, APS (VBR), V2 (VBR), V1 (VBR), APX (VBR), V0 (VBR), q8.x (VBR), Lossless, 24bit Lossless, Other
 	Media                       *[]string               `json:"media,omitempty"`   // CD, DVD, Vinyl, Soundboard, SACD, DAT, Cassette, WEB, Other
+	BitDepths                   *[]string               `json:"bit_depths,omitempty"`
+	SampleRates                 *[]string               `json:"sample_rates,omitempty"`
+	AudioChannels               *[]string               `json:"audio_channels,omitempty"`
 	PerfectFlac                 *bool                   `json:"perfect_flac,omitempty"`
 	Cue                         *bool                   `json:"cue,omitempty"`
 	Log                         *bool                   `json:"log,omitempty"`
@@ -216,14 +392,20 @@ type FilterUpdate struct {
 	ExceptCategories            *string                 `json:"except_categories,omitempty"`
 	MatchUploaders              *string                 `json:"match_uploaders,omitempty"`
 	ExceptUploaders             *string                 `json:"except_uploaders,omitempty"`
+	UseRegexUploaders           *bool                   `json:"use_regex_uploaders,omitempty"`
 	MatchLanguage               *[]string               `json:"match_language,omitempty"`
 	ExceptLanguage              *[]string               `json:"except_language,omitempty"`
+	LanguagePreference          *[]string               `json:"language_preference,omitempty"`
+	SubtitlePreference          *[]string               `json:"subtitle_preference,omitempty"`
+	ExceptLanguageOnly          *[]string               `json:"except_language_only,omitempty"`
+	RewriteRules                *[]FilterRewriteRule    `json:"rewrite_rules,omitempty"`
 	Tags                        *string                 `json:"tags,omitempty"`
 	ExceptTags                  *string                 `json:"except_tags,omitempty"`
 	TagsAny                     *string                 `json:"tags_any,omitempty"`
 	ExceptTagsAny               *string                 `json:"except_tags_any,omitempty"`
 	TagsMatchLogic              *string                 `json:"tags_match_logic,omitempty"`
 	ExceptTagsMatchLogic        *string                 `json:"except_tags_match_logic,omitempty"`
+	UseRegexTags                *bool                   `json:"use_regex_tags,omitempty"`
 	ExternalScriptEnabled       *bool                   `json:"external_script_enabled,omitempty"`
 	ExternalScriptCmd           *string                 `json:"external_script_cmd,omitempty"`
 	ExternalScriptArgs          *string                 `json:"external_script_args,omitempty"`
@@ -234,6 +416,7 @@ type FilterUpdate struct {
 	ExternalWebhookExpectStatus *int                    `json:"external_webhook_expect_status,omitempty"`
 	Actions                     []*Action               `json:"actions,omitempty"`
 	External                    []FilterExternal        `json:"external,omitempty"`
+	ListSources                 []FilterListSource      `json:"list_sources,omitempty"`
 	Indexers                    []Indexer               `json:"indexers,omitempty"`
 }
 
@@ -241,9 +424,35 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 	// reset rejections first to clean previous checks
 	r.resetRejections()
 
+	// schedule check against the time the release was announced. If outside of the schedule return early
+	now := r.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if f.Schedule != "" && !f.checkSchedule(now) {
+		r.addRejectionF("outside schedule. got: %v want: %v", now.Format("Mon 15:04"), f.Schedule)
+		return r.Rejections, false
+	}
+
+	// flags set by irc triggers, like a sitewide freeleech. If not matching return early
+	if f.Flags != "" && !checkFlags(r.Flags, f.Flags) {
+		r.addRejectionF("flags not active. got: %v want: %v", r.Flags, f.Flags)
+		return r.Rejections, false
+	}
+
+	if f.ExceptFlags != "" && checkAnyFlag(r.Flags, f.ExceptFlags) {
+		r.addRejectionF("unwanted flags active. got: %v unwanted: %v", r.Flags, f.ExceptFlags)
+		return r.Rejections, false
+	}
+
 	// max downloads check. If reached return early
 	if f.MaxDownloads > 0 && !f.checkMaxDownloads(f.MaxDownloads, f.MaxDownloadsUnit) {
-		r.addRejectionF("max downloads (%d) this (%v) reached", f.MaxDownloads, f.MaxDownloadsUnit)
+		if f.MaxDownloadsPerIndexer {
+			r.addRejectionF("max downloads (%d) per (%v) reached for indexer: %s", f.MaxDownloads, f.maxDownloadsWindow(), r.Indexer)
+		} else {
+			r.addRejectionF("max downloads (%d) per (%v) reached", f.MaxDownloads, f.maxDownloadsWindow())
+		}
 		return r.Rejections, false
 	}
 
@@ -267,7 +476,11 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 	}
 
 	// title is the parsed title
-	if f.Shows != "" && !contains(r.Title, f.Shows) {
+	if f.UseRegexShows {
+		if f.Shows != "" && !matchRegex(r.Title, f.Shows) {
+			r.addRejectionF("shows regex not matching. got: %v want: %v", r.Title, f.Shows)
+		}
+	} else if f.Shows != "" && !contains(r.Title, f.Shows) {
 		r.addRejectionF("shows not matching. got: %v want: %v", r.Title, f.Shows)
 	}
 
@@ -279,6 +492,12 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("episodes not matching. got: %d want: %v", r.Episode, f.Episodes)
 	}
 
+	if f.AirDates != "" || f.MaxAirDateAge > 0 {
+		f.checkAirDate(r, now)
+	}
+
+	f.checkAnime(r)
+
 	// matchRelease
 	// match against regex
 	if f.UseRegex {
@@ -300,12 +519,23 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		}
 	}
 
-	if f.MatchReleaseGroups != "" && !contains(r.Group, f.MatchReleaseGroups) {
-		r.addRejectionF("release groups not matching. got: %v want: %v", r.Group, f.MatchReleaseGroups)
-	}
+	if f.UseRegexReleaseGroups {
+		if f.MatchReleaseGroups != "" && !matchRegex(r.Group, f.MatchReleaseGroups) {
+			r.addRejectionF("release groups regex not matching. got: %v want: %v", r.Group, f.MatchReleaseGroups)
+		}
+
+		if f.ExceptReleaseGroups != "" && matchRegex(r.Group, f.ExceptReleaseGroups) {
+			r.addRejectionF("unwanted release group regex. got: %v unwanted: %v", r.Group, f.ExceptReleaseGroups)
+		}
+
+	} else {
+		if f.MatchReleaseGroups != "" && !contains(r.Group, f.MatchReleaseGroups) {
+			r.addRejectionF("release groups not matching. got: %v want: %v", r.Group, f.MatchReleaseGroups)
+		}
 
-	if f.ExceptReleaseGroups != "" && contains(r.Group, f.ExceptReleaseGroups) {
-		r.addRejectionF("unwanted release group. got: %v unwanted: %v", r.Group, f.ExceptReleaseGroups)
+		if f.ExceptReleaseGroups != "" && contains(r.Group, f.ExceptReleaseGroups) {
+			r.addRejectionF("unwanted release group. got: %v unwanted: %v", r.Group, f.ExceptReleaseGroups)
+		}
 	}
 
 	// check raw releaseTags string
@@ -328,12 +558,23 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		}
 	}
 
-	if f.MatchUploaders != "" && !contains(r.Uploader, f.MatchUploaders) {
-		r.addRejectionF("uploaders not matching. got: %v want: %v", r.Uploader, f.MatchUploaders)
-	}
+	if f.UseRegexUploaders {
+		if f.MatchUploaders != "" && !matchRegex(r.Uploader, f.MatchUploaders) {
+			r.addRejectionF("uploaders regex not matching. got: %v want: %v", r.Uploader, f.MatchUploaders)
+		}
 
-	if f.ExceptUploaders != "" && contains(r.Uploader, f.ExceptUploaders) {
-		r.addRejectionF("unwanted uploaders. got: %v unwanted: %v", r.Uploader, f.ExceptUploaders)
+		if f.ExceptUploaders != "" && matchRegex(r.Uploader, f.ExceptUploaders) {
+			r.addRejectionF("unwanted uploaders regex. got: %v unwanted: %v", r.Uploader, f.ExceptUploaders)
+		}
+
+	} else {
+		if f.MatchUploaders != "" && !contains(r.Uploader, f.MatchUploaders) {
+			r.addRejectionF("uploaders not matching. got: %v want: %v", r.Uploader, f.MatchUploaders)
+		}
+
+		if f.ExceptUploaders != "" && contains(r.Uploader, f.ExceptUploaders) {
+			r.addRejectionF("unwanted uploaders. got: %v unwanted: %v", r.Uploader, f.ExceptUploaders)
+		}
 	}
 
 	if len(f.MatchLanguage) > 0 && !sliceContainsSlice(r.Language, f.MatchLanguage) {
@@ -344,6 +585,26 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("language unwanted. got: %v want: %v", r.Language, f.ExceptLanguage)
 	}
 
+	if len(f.LanguagePreference) > 0 || len(f.ExceptLanguageOnly) > 0 {
+		languages := ReleaseLanguages(r)
+
+		if len(f.LanguagePreference) > 0 && PreferenceRank(f.LanguagePreference, languages) == 0 {
+			r.addRejectionF("language not preferred. got: %v want: %v", languages, f.LanguagePreference)
+		}
+
+		if len(f.ExceptLanguageOnly) > 0 && onlyLanguages(languages, f.ExceptLanguageOnly) {
+			r.addRejectionF("language unwanted without other languages. got: %v unwanted: %v", languages, f.ExceptLanguageOnly)
+		}
+	}
+
+	if len(f.SubtitlePreference) > 0 {
+		subtitles := ReleaseSubtitles(r)
+
+		if PreferenceRank(f.SubtitlePreference, subtitles) == 0 {
+			r.addRejectionF("subtitles not preferred. got: %v want: %v", subtitles, f.SubtitlePreference)
+		}
+	}
+
 	if len(f.Resolutions) > 0 && !containsSlice(r.Resolution, f.Resolutions) {
 		r.addRejectionF("resolution not matching. got: %v want: %v", r.Resolution, f.Resolutions)
 	}
@@ -370,6 +631,18 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("hdr unwanted. got: %v want: %v", r.HDR, f.ExceptHDR)
 	}
 
+	if len(f.HDRPreference) > 0 || f.RejectDVWithoutFallback {
+		format := NewHDRFormat(r.HDR)
+
+		if f.RejectDVWithoutFallback && format.IsDVWithoutFallback() {
+			r.addRejectionF("hdr unwanted. got: %v without fallback", format)
+		}
+
+		if len(f.HDRPreference) > 0 && HDRPreferenceRank(f.HDRPreference, format) == 0 {
+			r.addRejectionF("hdr format not preferred. got: %v want: %v", format, f.HDRPreference)
+		}
+	}
+
 	// Other is parsed into the Other slice from rls
 	if len(f.MatchOther) > 0 && !sliceContainsSlice(r.Other, f.MatchOther) {
 		r.addRejectionF("match other not matching. got: %v want: %v", r.Other, f.MatchOther)
@@ -380,7 +653,7 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("except other unwanted. got: %v unwanted: %v", r.Other, f.ExceptOther)
 	}
 
-	if f.Years != "" && !containsIntStrings(r.Year, f.Years) {
+	if f.Years != "" && !matchYears(r.Year, f.Years) {
 		r.addRejectionF("year not matching. got: %d want: %v", r.Year, f.Years)
 	}
 
@@ -410,11 +683,19 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("release type not matching. got: %v want: %v", r.Category, f.MatchReleaseTypes)
 	}
 
-	if (f.MinSize != "" || f.MaxSize != "") && !f.checkSizeFilter(r, f.MinSize, f.MaxSize) {
+	// with torrent verification the size is checked against the torrent file instead of the announced size
+	if (f.MinSize != "" || f.MaxSize != "") && !f.VerifyTorrent && !f.checkSizeFilter(r, f.MinSize, f.MaxSize) {
 		r.addRejectionF("size not matching. got: %v want min: %v max: %v", r.Size, f.MinSize, f.MaxSize)
 	}
 
-	if f.Tags != "" {
+	// with regex each comma separated pattern is matched against every tag
+	if f.Tags != "" && f.UseRegexTags {
+		if f.TagsMatchLogic == "ALL" && !matchRegexAll(r.Tags, f.Tags) {
+			r.addRejectionF("tags regex not matching. got: %v want(all): %v", r.Tags, f.Tags)
+		} else if !matchRegexAny(r.Tags, f.Tags) {
+			r.addRejectionF("tags regex not matching. got: %v want: %v", r.Tags, f.Tags)
+		}
+	} else if f.Tags != "" {
 		if f.TagsMatchLogic == "ALL" && !containsAll(r.Tags, f.Tags) {
 			r.addRejectionF("tags not matching. got: %v want(all): %v", r.Tags, f.Tags)
 		} else if !containsAny(r.Tags, f.Tags) { // TagsMatchLogic is set to "" by default, this makes sure that "" and "ANY" are treated the same way.
@@ -422,7 +703,13 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		}
 	}
 
-	if f.ExceptTags != "" {
+	if f.ExceptTags != "" && f.UseRegexTags {
+		if f.ExceptTagsMatchLogic == "ALL" && matchRegexAll(r.Tags, f.ExceptTags) {
+			r.addRejectionF("tags regex unwanted. got: %v don't want: %v", r.Tags, f.ExceptTags)
+		} else if matchRegexAny(r.Tags, f.ExceptTags) {
+			r.addRejectionF("tags regex unwanted. got: %v don't want: %v", r.Tags, f.ExceptTags)
+		}
+	} else if f.ExceptTags != "" {
 		if f.ExceptTagsMatchLogic == "ALL" && containsAll(r.Tags, f.ExceptTags) {
 			r.addRejectionF("tags unwanted. got: %v don't want: %v", r.Tags, f.ExceptTags)
 		} else if containsAny(r.Tags, f.ExceptTags) { // ExceptTagsMatchLogic is set to "" by default, this makes sure that "" and "ANY" are treated the same way.
@@ -455,6 +742,18 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("media not matching. got: %v want: %v", r.Source, f.Media)
 	}
 
+	if len(f.BitDepths) > 0 && !matchBitDepth(r.BitDepth, f.BitDepths) {
+		r.addRejectionF("bit depth not matching. got: %v want: %v", r.BitDepth, f.BitDepths)
+	}
+
+	if len(f.SampleRates) > 0 && !matchSampleRate(r.SampleRate, f.SampleRates) {
+		r.addRejectionF("sample rate not matching. got: %v want: %v", formatSampleRate(r.SampleRate), f.SampleRates)
+	}
+
+	if len(f.AudioChannels) > 0 && !containsSlice(r.AudioChannels, f.AudioChannels) {
+		r.addRejectionF("audio channels not matching. got: %v want: %v", r.AudioChannels, f.AudioChannels)
+	}
+
 	if f.Cue && !containsAny(r.Audio, "Cue") {
 		r.addRejection("wanted: cue")
 	}
@@ -487,6 +786,10 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		}
 	}
 
+	if f.Expression != "" {
+		f.checkExpression(r)
+	}
+
 	if len(r.Rejections) > 0 {
 		return r.Rejections, false
 	}
@@ -527,6 +830,29 @@ func (f Filter) checkMaxDownloads(max int, perTimeUnit FilterMaxDownloadsUnit) b
 	return true
 }
 
+// DownloadsParams returns the params to count the downloads of the filter for the max downloads check of release
+func (f Filter) DownloadsParams(r *Release) FilterDownloadsParams {
+	params := FilterDownloadsParams{
+		FilterID: f.ID,
+		Interval: f.MaxDownloadsInterval,
+	}
+
+	if f.MaxDownloadsPerIndexer {
+		params.Indexer = r.Indexer
+	}
+
+	return params
+}
+
+// maxDownloadsWindow describes the window of the max downloads limit, eg. HOUR or 6 HOUR
+func (f Filter) maxDownloadsWindow() string {
+	if f.MaxDownloadsInterval > 1 && f.MaxDownloadsUnit != FilterMaxDownloadsEver {
+		return fmt.Sprintf("%d %s", f.MaxDownloadsInterval, f.MaxDownloadsUnit)
+	}
+
+	return string(f.MaxDownloadsUnit)
+}
+
 // isPerfectFLAC Perfect is "CD FLAC Cue Log 100% Lossless or 24bit Lossless"
 func (f Filter) isPerfectFLAC(r *Release) bool {
 	if !contains(r.Source, "CD") {
@@ -600,6 +926,266 @@ func (f Filter) checkSizeFilter(r *Release, minSize string, maxSize string) bool
 	return true
 }
 
+// checkSchedule reports whether the filter is active at t, invalid schedules never are
+func (f Filter) checkSchedule(t time.Time) bool {
+	schedule, err := ParseFilterSchedule(f.Schedule)
+	if err != nil {
+		return false
+	}
+
+	return schedule.Active(t)
+}
+
+// ValidateFilterExpression checks that an expression compiles and only uses release fields
+func ValidateFilterExpression(expression string) error {
+	_, err := compileExpression(expression)
+	return err
+}
+
+// CheckExpression evaluates the filter expression against the release
+func (f Filter) CheckExpression(r *Release) (bool, error) {
+	program, err := compileExpression(f.Expression)
+	if err != nil {
+		return false, err
+	}
+
+	return runExpression(program, r)
+}
+
+// checkExpression adds a rejection if the expression does not match.
+// Like checkSizeFilter it flags releases without size for the additional size check if the expression uses Size,
+// the expression is then evaluated again once the size is known.
+func (f Filter) checkExpression(r *Release) {
+	program, err := compileExpression(f.Expression)
+	if err != nil {
+		r.addRejectionF("expression: invalid expression: %q", err)
+		return
+	}
+
+	if r.Size == 0 && expressionUses(program, "Size") {
+		r.AdditionalSizeCheckRequired = true
+		return
+	}
+
+	match, err := runExpression(program, r)
+	if err != nil {
+		r.addRejectionF("expression: could not evaluate: %q", err)
+		return
+	}
+
+	if !match {
+		r.addRejectionF("expression not matching. got: %v want: %v", r.TorrentName, f.Expression)
+	}
+}
+
+// expressionEnv holds the release fields that can be used in filter expressions
+type expressionEnv struct {
+	TorrentName      string
+	Title            string
+	Indexer          string
+	Protocol         string
+	Implementation   string
+	Size             uint64
+	Category         string
+	Categories       []string
+	Season           int
+	Episode          int
+	Year             int
+	Resolution       string
+	Source           string
+	Codec            []string
+	Container        string
+	HDR              []string
+	Audio            []string
+	AudioChannels    string
+	Anime            bool
+	AbsoluteEpisode  int
+	Batch            bool
+	AnimeSubType     string
+	BitDepth         int
+	SampleRate       int
+	Group            string
+	Region           string
+	Language         []string
+	Proper           bool
+	Repack           bool
+	Website          string
+	Artists          string
+	Type             string
+	LogScore         int
+	Origin           string
+	Tags             []string
+	ReleaseTags      string
+	Freeleech        bool
+	FreeleechPercent int
+	Bonus            []string
+	Uploader         string
+	PreTime          string
+	Other            []string
+	Description      string
+}
+
+func newExpressionEnv(r *Release) expressionEnv {
+	return expressionEnv{
+		TorrentName:      r.TorrentName,
+		Title:            r.Title,
+		Indexer:          r.Indexer,
+		Protocol:         string(r.Protocol),
+		Implementation:   string(r.Implementation),
+		Size:             r.Size,
+		Category:         r.Category,
+		Categories:       r.Categories,
+		Season:           r.Season,
+		Episode:          r.Episode,
+		Year:             r.Year,
+		Resolution:       r.Resolution,
+		Source:           r.Source,
+		Codec:            r.Codec,
+		Container:        r.Container,
+		HDR:              r.HDR,
+		Audio:            r.Audio,
+		AudioChannels:    r.AudioChannels,
+		Anime:            r.Anime,
+		AbsoluteEpisode:  r.AbsoluteEpisode,
+		Batch:            r.Batch,
+		AnimeSubType:     string(r.AnimeSubType),
+		BitDepth:         r.BitDepth,
+		SampleRate:       r.SampleRate,
+		Group:            r.Group,
+		Region:           r.Region,
+		Language:         r.Language,
+		Proper:           r.Proper,
+		Repack:           r.Repack,
+		Website:          r.Website,
+		Artists:          r.Artists,
+		Type:             r.Type,
+		LogScore:         r.LogScore,
+		Origin:           r.Origin,
+		Tags:             r.Tags,
+		ReleaseTags:      r.ReleaseTags,
+		Freeleech:        r.Freeleech,
+		FreeleechPercent: r.FreeleechPercent,
+		Bonus:            r.Bonus,
+		Uploader:         r.Uploader,
+		PreTime:          r.PreTime,
+		Other:            r.Other,
+		Description:      r.Description,
+	}
+}
+
+// compileExpression compiles a filter expression against the release fields, the result has to be a bool
+func compileExpression(expression string) (*vm.Program, error) {
+	expanded, err := expandExpressionSizes(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr.Compile(expanded, expr.Env(expressionEnv{}), expr.AsBool())
+}
+
+func runExpression(program *vm.Program, r *Release) (bool, error) {
+	out, err := expr.Run(program, newExpressionEnv(r))
+	if err != nil {
+		return false, err
+	}
+
+	match, _ := out.(bool)
+
+	return match, nil
+}
+
+// identifierVisitor looks for a release field in an expression
+type identifierVisitor struct {
+	name  string
+	found bool
+}
+
+func (v *identifierVisitor) Visit(node *ast.Node) {
+	if n, ok := (*node).(*ast.IdentifierNode); ok && n.Value == v.name {
+		v.found = true
+	}
+}
+
+// expressionUses reports whether the compiled expression uses the release field name
+func expressionUses(program *vm.Program, name string) bool {
+	node := program.Node()
+	v := &identifierVisitor{name: name}
+	ast.Walk(&node, v)
+
+	return v.found
+}
+
+// expandExpressionSizes replaces sizes like 40GB or 1.5GiB outside of string literals with their bytes,
+// so they can be compared with Size
+func expandExpressionSizes(expression string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(expression); {
+		c := expression[i]
+
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(expression) && expression[end] != c {
+				if expression[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end < len(expression) {
+				end++
+			}
+
+			b.WriteString(expression[i:end])
+			i = end
+
+		case isDigit(c) && (i == 0 || !isIdentifierChar(expression[i-1])):
+			end := i
+			for end < len(expression) && (isDigit(expression[end]) || expression[end] == '.') {
+				end++
+			}
+
+			unitEnd := end
+			for unitEnd < len(expression) && isLetter(expression[unitEnd]) {
+				unitEnd++
+			}
+
+			// plain numbers, and hex or exponent notation, are left to the expression parser
+			if unitEnd == end || unitEnd < len(expression) && isDigit(expression[unitEnd]) {
+				b.WriteString(expression[i:end])
+				i = end
+				continue
+			}
+
+			size, err := humanize.ParseBytes(expression[i:unitEnd])
+			if err != nil {
+				return "", errors.New("invalid size %q at position %d", expression[i:unitEnd], i+1)
+			}
+
+			b.WriteString(strconv.FormatUint(size, 10))
+			i = unitEnd
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isLetter(c) || isDigit(c) || c == '_' || c == '.'
+}
+
 func matchRegex(tag string, filterList string) bool {
 	if tag == "" {
 		return false
@@ -623,6 +1209,32 @@ func matchRegex(tag string, filterList string) bool {
 	return false
 }
 
+// matchRegexAny reports whether any of the tags matches any of the comma separated patterns
+func matchRegexAny(tags []string, filterList string) bool {
+	for _, tag := range tags {
+		if matchRegex(tag, filterList) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchRegexAll reports whether each of the comma separated patterns matches at least one of the tags
+func matchRegexAll(tags []string, filterList string) bool {
+	for _, filter := range strings.Split(filterList, ",") {
+		if filter == "" {
+			continue
+		}
+
+		if !matchRegexAny(tags, filter) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // checkFilterIntStrings "1,2,3-20"
 func containsIntStrings(value int, filterList string) bool {
 	filters := strings.Split(filterList, ",")
@@ -843,48 +1455,99 @@ func containsAnySlice(tags []string, filters []string) bool {
 }
 
 func checkFreeleechPercent(announcePercent int, filterPercent string) bool {
-	filters := strings.Split(filterPercent, ",")
+	for _, value := range strings.Split(filterPercent, ",") {
+		min, max, err := parseFreeleechPercent(value)
+		if err != nil {
+			continue
+		}
 
-	for _, s := range filters {
-		s = strings.Replace(s, "%", "", -1)
-		s = strings.Trim(s, " ")
+		if announcePercent >= min && announcePercent <= max {
+			return true
+		}
+	}
 
-		if strings.Contains(s, "-") {
-			minMax := strings.Split(s, "-")
+	return false
+}
 
-			// to int
-			min, err := strconv.ParseInt(minMax[0], 10, 32)
-			if err != nil {
-				return false
-			}
+// parseFreeleechPercent parses one freeleech percent value into an inclusive range.
+// Supported are exact values like 50, ranges like 25-75 and comparisons like >=50, >50, <=25 and <25.
+func parseFreeleechPercent(value string) (int, int, error) {
+	value = strings.ReplaceAll(value, "%", "")
+	value = strings.ReplaceAll(value, " ", "")
 
-			max, err := strconv.ParseInt(minMax[1], 10, 32)
-			if err != nil {
-				return false
-			}
+	parse := func(v string) (int, error) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			return 0, errors.New("invalid freeleech percent: %q", value)
+		}
+		return n, nil
+	}
 
-			if min > max {
-				// handle error
-				return false
-			} else {
-				// if announcePercent is greater than min and less than max return true
-				if announcePercent >= int(min) && announcePercent <= int(max) {
-					return true
-				}
-			}
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(value, op) {
+			continue
 		}
 
-		filterPercentInt, err := strconv.ParseInt(s, 10, 32)
+		n, err := parse(strings.TrimPrefix(value, op))
 		if err != nil {
-			return false
+			return 0, 0, err
 		}
 
-		if int(filterPercentInt) == announcePercent {
-			return true
+		switch op {
+		case ">=":
+			return n, 100, nil
+		case "<=":
+			return 0, n, nil
+		case ">":
+			return n + 1, 100, nil
+		default:
+			return 0, n - 1, nil
 		}
 	}
 
-	return false
+	if minValue, maxValue, found := strings.Cut(value, "-"); found {
+		min, err := parse(minValue)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		max, err := parse(maxValue)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if min > max {
+			return 0, 0, errors.New("invalid freeleech percent range: %q", value)
+		}
+
+		return min, max, nil
+	}
+
+	n, err := parse(value)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return n, n, nil
+}
+
+// ValidateFreeleechPercent checks that all comma separated freeleech percent values can be parsed
+func ValidateFreeleechPercent(filterPercent string) error {
+	if strings.TrimSpace(filterPercent) == "" {
+		return nil
+	}
+
+	for _, value := range strings.Split(filterPercent, ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		if _, _, err := parseFreeleechPercent(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func matchHDR(releaseValues []string, filterValues []string) bool {
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const airDateLayout = "2006-01-02"
+
+// AirDate returns the air date of daily episodes like Show.2023.10.12 which have no season and episode
+func (r *Release) AirDate() (time.Time, bool) {
+	if r.Season != 0 || r.Episode != 0 || r.Year == 0 || r.Month == 0 || r.Day == 0 {
+		return time.Time{}, false
+	}
+
+	date := time.Date(r.Year, time.Month(r.Month), r.Day, 0, 0, 0, 0, time.UTC)
+
+	// rls does not validate the day of the month, 2023.02.31 would roll over into march
+	if date.Month() != time.Month(r.Month) {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+// dateRange is an inclusive range of dates, zero means unbounded
+type dateRange struct {
+	min time.Time
+	max time.Time
+}
+
+func (d dateRange) contains(date time.Time) bool {
+	return (d.min.IsZero() || !date.Before(d.min)) && (d.max.IsZero() || !date.After(d.max))
+}
+
+// parseAirDates parses a comma separated list of dates like 2023-10-12, months like 2023-10, ranges like
+// 2023-10-01/2023-10-15 and comparisons like >=2023-10-01
+func parseAirDates(value string) ([]dateRange, error) {
+	var ranges []dateRange
+
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		r, err := parseDateRange(s)
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+func parseDateRange(s string) (dateRange, error) {
+	switch {
+	case strings.HasPrefix(s, ">="):
+		start, _, err := parseAirDate(s[2:])
+		return dateRange{min: start}, err
+
+	case strings.HasPrefix(s, "<="):
+		_, end, err := parseAirDate(s[2:])
+		return dateRange{max: end}, err
+
+	case strings.HasPrefix(s, ">"):
+		_, end, err := parseAirDate(s[1:])
+		return dateRange{min: end.AddDate(0, 0, 1)}, err
+
+	case strings.HasPrefix(s, "<"):
+		start, _, err := parseAirDate(s[1:])
+		return dateRange{max: start.AddDate(0, 0, -1)}, err
+
+	case strings.Contains(s, "/"):
+		minMax := strings.SplitN(s, "/", 2)
+
+		min, _, err := parseAirDate(minMax[0])
+		if err != nil {
+			return dateRange{}, err
+		}
+
+		_, max, err := parseAirDate(minMax[1])
+		if err != nil {
+			return dateRange{}, err
+		}
+
+		if min.After(max) {
+			return dateRange{}, errors.New("invalid air date range: %s", s)
+		}
+
+		return dateRange{min: min, max: max}, nil
+	}
+
+	start, end, err := parseAirDate(s)
+	return dateRange{min: start, max: end}, err
+}
+
+// parseAirDate parses a date or a month and returns its first and last day
+func parseAirDate(s string) (time.Time, time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if date, err := time.Parse(airDateLayout, s); err == nil {
+		return date, date, nil
+	}
+
+	if month, err := time.Parse("2006-01", s); err == nil {
+		return month, month.AddDate(0, 1, -1), nil
+	}
+
+	return time.Time{}, time.Time{}, errors.New("invalid air date: %s", s)
+}
+
+// checkAirDate rejects releases that are not daily episodes, do not match the air dates or aired more than
+// MaxAirDateAge days before they were announced
+func (f Filter) checkAirDate(r *Release, now time.Time) {
+	airDate, ok := r.AirDate()
+	if !ok {
+		r.addRejectionF("air date not found. got: S%02dE%02d want: daily episode", r.Season, r.Episode)
+		return
+	}
+
+	if f.AirDates != "" {
+		ranges, err := parseAirDates(f.AirDates)
+		if err != nil || !matchDateRanges(ranges, airDate) {
+			r.addRejectionF("air date not matching. got: %s want: %v", airDate.Format(airDateLayout), f.AirDates)
+		}
+	}
+
+	if f.MaxAirDateAge > 0 {
+		// compare calendar days so the time of the announce does not matter
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		age := int(today.Sub(airDate).Hours() / 24)
+
+		if age > f.MaxAirDateAge {
+			r.addRejectionF("air date too old. got: %s (%d days) want: max %d days", airDate.Format(airDateLayout), age, f.MaxAirDateAge)
+		}
+	}
+}
+
+func matchDateRanges(ranges []dateRange, date time.Time) bool {
+	for _, r := range ranges {
+		if r.contains(date) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAirDates checks the air dates and max air date age of a filter
+func ValidateAirDates(airDates string, maxAge int) error {
+	if maxAge < 0 {
+		return errors.New("max air date age can not be negative")
+	}
+
+	_, err := parseAirDates(airDates)
+	return err
+}
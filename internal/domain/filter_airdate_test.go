@@ -0,0 +1,85 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease_AirDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		release string
+		want    string
+		wantOk  bool
+	}{
+		{name: "dotted", release: "The.Daily.Show.2023.10.12.Guest.Name.1080p.WEB.h264-GROUP", want: "2023-10-12", wantOk: true},
+		{name: "dashed", release: "Late.Night.2023-10-12.720p.HDTV.x264-GROUP", want: "2023-10-12", wantOk: true},
+		{name: "episode", release: "That.Show.S01E02.1080p.WEB.h264-GROUP", wantOk: false},
+		{name: "movie", release: "That.Movie.2023.1080p.BluRay.x264-GROUP", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.release)
+
+			got, ok := r.AirDate()
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got.Format(airDateLayout))
+			}
+		})
+	}
+}
+
+func TestFilter_checkAirDate(t *testing.T) {
+	announced := time.Date(2023, 10, 14, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		date   string
+		want   bool
+	}{
+		{name: "date", filter: Filter{AirDates: "2023-10-12"}, date: "2023.10.12", want: true},
+		{name: "date_other", filter: Filter{AirDates: "2023-10-11, 2023-10-13"}, date: "2023.10.12", want: false},
+		{name: "month", filter: Filter{AirDates: "2023-10"}, date: "2023.10.31", want: true},
+		{name: "range", filter: Filter{AirDates: "2023-10-01/2023-10-12"}, date: "2023.10.12", want: true},
+		{name: "range_outside", filter: Filter{AirDates: "2023-10-01/2023-10-11"}, date: "2023.10.12", want: false},
+		{name: "after", filter: Filter{AirDates: ">2023-10-11"}, date: "2023.10.12", want: true},
+		{name: "before_month", filter: Filter{AirDates: "<2023-10"}, date: "2023.10.01", want: false},
+		{name: "max_age", filter: Filter{MaxAirDateAge: 2}, date: "2023.10.12", want: true},
+		{name: "max_age_old", filter: Filter{MaxAirDateAge: 1}, date: "2023.10.12", want: false},
+		{name: "both", filter: Filter{AirDates: ">=2023-10", MaxAirDateAge: 7}, date: "2023.10.12", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString("The.Daily.Show." + tt.date + ".1080p.WEB.h264-GROUP")
+
+			tt.filter.checkAirDate(r, announced)
+			assert.Equal(t, tt.want, len(r.Rejections) == 0, r.Rejections)
+		})
+	}
+}
+
+func TestFilter_checkAirDate_NotDaily(t *testing.T) {
+	r := NewRelease("mock")
+	r.ParseString("That.Show.S01E02.1080p.WEB.h264-GROUP")
+
+	Filter{MaxAirDateAge: 7}.checkAirDate(r, time.Now())
+	assert.Len(t, r.Rejections, 1)
+}
+
+func TestValidateAirDates(t *testing.T) {
+	assert.NoError(t, ValidateAirDates("", 0))
+	assert.NoError(t, ValidateAirDates("2023-10-12, 2023-11, 2023-10-01/2023-10-15, >=2023-10-01", 7))
+	assert.Error(t, ValidateAirDates("2023-10-15/2023-10-01", 0))
+	assert.Error(t, ValidateAirDates("2023-13-01", 0))
+	assert.Error(t, ValidateAirDates("yesterday", 0))
+	assert.Error(t, ValidateAirDates("", -1))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterChainMode decides which filter is checked next when a filter rejects a release or its actions fail
+type FilterChainMode string
+
+const (
+	// FilterChainNext continues with the next filter by priority, the default
+	FilterChainNext FilterChainMode = "NEXT"
+
+	// FilterChainStop stops processing the release
+	FilterChainStop FilterChainMode = "STOP"
+
+	// FilterChainFilter continues with the chosen filter, the filters in between are skipped
+	FilterChainFilter FilterChainMode = "FILTER"
+)
+
+// ValidateChain checks the on reject and on action failure settings of the filter
+func (f *Filter) ValidateChain() error {
+	if err := validateChainMode(f.ID, f.OnReject, f.OnRejectFilterID); err != nil {
+		return errors.Wrap(err, "on reject")
+	}
+
+	if err := validateChainMode(f.ID, f.OnActionFailure, f.OnActionFailureFilterID); err != nil {
+		return errors.Wrap(err, "on action failure")
+	}
+
+	return nil
+}
+
+func validateChainMode(filterID int, mode FilterChainMode, targetID int) error {
+	switch mode {
+	case "", FilterChainNext, FilterChainStop:
+		return nil
+
+	case FilterChainFilter:
+		if targetID <= 0 {
+			return errors.New("missing filter to continue with")
+		}
+
+		if filterID > 0 && targetID == filterID {
+			return errors.New("filter can not continue with itself")
+		}
+
+		return nil
+	}
+
+	return errors.New("invalid mode: %s", mode)
+}
+
+// NextFilterIndex returns the index in filters of the filter to check after the filter at current, or -1 to stop.
+// Filters in checked are skipped so a chain can not loop. When the filter to continue with is not in filters,
+// eg. because it is disabled or not enabled for the indexer, the next filter by priority is used and found is false.
+func NextFilterIndex(filters []Filter, current int, mode FilterChainMode, targetID int, checked map[int]struct{}) (next int, found bool) {
+	switch mode {
+	case FilterChainStop:
+		return -1, true
+
+	case FilterChainFilter:
+		for i, f := range filters {
+			if f.ID != targetID {
+				continue
+			}
+
+			if _, ok := checked[f.ID]; ok {
+				return -1, true
+			}
+
+			return i, true
+		}
+
+		found = false
+
+	default:
+		found = true
+	}
+
+	for i := current + 1; i < len(filters); i++ {
+		if _, ok := checked[filters[i].ID]; !ok {
+			return i, found
+		}
+	}
+
+	return -1, found
+}
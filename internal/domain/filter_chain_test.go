@@ -0,0 +1,71 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_ValidateChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{name: "default", filter: Filter{ID: 1}},
+		{name: "stop", filter: Filter{ID: 1, OnReject: FilterChainStop, OnActionFailure: FilterChainNext}},
+		{name: "continue with filter", filter: Filter{ID: 1, OnReject: FilterChainFilter, OnRejectFilterID: 2, OnActionFailure: FilterChainFilter, OnActionFailureFilterID: 3}},
+		{name: "missing filter", filter: Filter{ID: 1, OnReject: FilterChainFilter}, wantErr: true},
+		{name: "itself", filter: Filter{ID: 1, OnActionFailure: FilterChainFilter, OnActionFailureFilterID: 1}, wantErr: true},
+		{name: "invalid mode", filter: Filter{ID: 1, OnReject: "RETRY"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.ValidateChain()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNextFilterIndex(t *testing.T) {
+	filters := []Filter{{ID: 10}, {ID: 20}, {ID: 30}, {ID: 40}}
+
+	tests := []struct {
+		name      string
+		current   int
+		mode      FilterChainMode
+		targetID  int
+		checked   []int
+		wantNext  int
+		wantFound bool
+	}{
+		{name: "next by priority", current: 0, mode: "", checked: []int{10}, wantNext: 1, wantFound: true},
+		{name: "next explicit", current: 1, mode: FilterChainNext, checked: []int{10, 20}, wantNext: 2, wantFound: true},
+		{name: "last filter", current: 3, mode: FilterChainNext, checked: []int{10, 20, 30, 40}, wantNext: -1, wantFound: true},
+		{name: "stop", current: 0, mode: FilterChainStop, checked: []int{10}, wantNext: -1, wantFound: true},
+		{name: "skip to filter", current: 0, mode: FilterChainFilter, targetID: 40, checked: []int{10}, wantNext: 3, wantFound: true},
+		{name: "back to higher priority filter", current: 3, mode: FilterChainFilter, targetID: 20, checked: []int{10, 40}, wantNext: 1, wantFound: true},
+		{name: "next skips checked", current: 1, mode: FilterChainNext, checked: []int{10, 20, 30}, wantNext: 3, wantFound: true},
+		{name: "loop stops", current: 2, mode: FilterChainFilter, targetID: 10, checked: []int{10, 30}, wantNext: -1, wantFound: true},
+		{name: "missing filter falls back to next", current: 0, mode: FilterChainFilter, targetID: 99, checked: []int{10}, wantNext: 1, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked := map[int]struct{}{}
+			for _, id := range tt.checked {
+				checked[id] = struct{}{}
+			}
+
+			next, found := NextFilterIndex(filters, tt.current, tt.mode, tt.targetID, checked)
+			assert.Equal(t, tt.wantNext, next)
+			assert.Equal(t, tt.wantFound, found)
+		})
+	}
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterExternalExecMode decides how the result of an external exec filter is read
+type FilterExternalExecMode string
+
+const (
+	// ExternalFilterExecModeExitCode compares the exit code of the program with ExecExpectStatus
+	ExternalFilterExecModeExitCode FilterExternalExecMode = "EXIT_CODE"
+
+	// ExternalFilterExecModeJSON writes an ExternalFilterInput as json to stdin of the program
+	// and reads an ExternalFilterOutput as json from its stdout
+	ExternalFilterExecModeJSON FilterExternalExecMode = "JSON"
+)
+
+const (
+	// ExternalFilterExecTimeoutDefault is the timeout in seconds used when ExecTimeout is not set
+	ExternalFilterExecTimeoutDefault = 60
+
+	// ExternalFilterExecTimeoutMax is the longest timeout in seconds an external exec filter can use
+	ExternalFilterExecTimeoutMax = 3600
+)
+
+const (
+	// ExternalFilterWebhookRetryAttemptsMax is the most times a webhook filter request is retried
+	ExternalFilterWebhookRetryAttemptsMax = 10
+
+	// ExternalFilterWebhookRetryDelayMax is the longest base delay in seconds between webhook filter retries
+	ExternalFilterWebhookRetryDelayMax = 60
+)
+
+// ExternalFilterContractVersion is sent as version in the input of JSON exec filters
+const ExternalFilterContractVersion = 1
+
+// Timeout returns the configured timeout of the exec filter or the default
+func (f FilterExternal) Timeout() time.Duration {
+	if f.ExecTimeout <= 0 {
+		return ExternalFilterExecTimeoutDefault * time.Second
+	}
+
+	return time.Duration(f.ExecTimeout) * time.Second
+}
+
+// IsJSONMode returns true if the exec filter uses the json contract
+func (f FilterExternal) IsJSONMode() bool {
+	return f.ExecMode == ExternalFilterExecModeJSON
+}
+
+// Validate checks the exec or webhook settings of the external filter
+func (f FilterExternal) Validate() error {
+	switch f.Type {
+	case ExternalFilterTypeExec:
+		return f.validateExec()
+	case ExternalFilterTypeWebhook:
+		return f.validateWebhook()
+	}
+
+	return nil
+}
+
+func (f FilterExternal) validateExec() error {
+	switch f.ExecMode {
+	case "", ExternalFilterExecModeExitCode, ExternalFilterExecModeJSON:
+	default:
+		return errors.New("external filter %s: invalid exec mode: %s", f.Name, f.ExecMode)
+	}
+
+	if f.ExecTimeout < 0 || f.ExecTimeout > ExternalFilterExecTimeoutMax {
+		return errors.New("external filter %s: exec timeout must be between 0 and %d seconds", f.Name, ExternalFilterExecTimeoutMax)
+	}
+
+	if _, err := ParseExternalFilterEnv(f.ExecEnv); err != nil {
+		return errors.Wrap(err, "external filter %s", f.Name)
+	}
+
+	return nil
+}
+
+func (f FilterExternal) validateWebhook() error {
+	if f.WebhookRetryAttempts < 0 || f.WebhookRetryAttempts > ExternalFilterWebhookRetryAttemptsMax {
+		return errors.New("external filter %s: webhook retry attempts must be between 0 and %d", f.Name, ExternalFilterWebhookRetryAttemptsMax)
+	}
+
+	if f.WebhookRetryDelay < 0 || f.WebhookRetryDelay > ExternalFilterWebhookRetryDelayMax {
+		return errors.New("external filter %s: webhook retry delay must be between 0 and %d seconds", f.Name, ExternalFilterWebhookRetryDelayMax)
+	}
+
+	if _, err := ParseWebhookRetryStatus(f.WebhookRetryStatus); err != nil {
+		return errors.Wrap(err, "external filter %s", f.Name)
+	}
+
+	if f.WebhookJSONPath != "" {
+		if _, err := splitJSONPath(f.WebhookJSONPath); err != nil {
+			return errors.Wrap(err, "external filter %s", f.Name)
+		}
+	}
+
+	return nil
+}
+
+// ParseExternalFilterEnv parses one KEY=VALUE pair per line into a list usable as exec.Cmd Env.
+// Empty lines and lines starting with # are skipped.
+func ParseExternalFilterEnv(text string) ([]string, error) {
+	var env []string
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, errors.New("invalid env line, want KEY=VALUE: %s", line)
+		}
+
+		env = append(env, key+"="+value)
+	}
+
+	return env, nil
+}
+
+// ExternalFilterInput is written as json to stdin of exec filters in JSON mode
+type ExternalFilterInput struct {
+	Version int                   `json:"version"`
+	Release ExternalFilterRelease `json:"release"`
+	Filter  ExternalFilterContext `json:"filter"`
+}
+
+// ExternalFilterRelease is the release as seen by an external filter
+type ExternalFilterRelease struct {
+	Indexer          string    `json:"indexer"`
+	Protocol         string    `json:"protocol"`
+	Implementation   string    `json:"implementation"`
+	Timestamp        time.Time `json:"timestamp"`
+	InfoURL          string    `json:"info_url"`
+	DownloadURL      string    `json:"download_url"`
+	GroupID          string    `json:"group_id"`
+	TorrentID        string    `json:"torrent_id"`
+	TorrentName      string    `json:"torrent_name"`
+	TorrentTmpFile   string    `json:"torrent_tmp_file"`
+	TorrentHash      string    `json:"torrent_hash"`
+	Size             uint64    `json:"size"`
+	Title            string    `json:"title"`
+	Category         string    `json:"category"`
+	Categories       []string  `json:"categories"`
+	Season           int       `json:"season"`
+	Episode          int       `json:"episode"`
+	Year             int       `json:"year"`
+	Resolution       string    `json:"resolution"`
+	Source           string    `json:"source"`
+	Codec            []string  `json:"codec"`
+	Container        string    `json:"container"`
+	HDR              []string  `json:"hdr"`
+	Audio            []string  `json:"audio"`
+	AudioChannels    string    `json:"audio_channels"`
+	Group            string    `json:"group"`
+	Region           string    `json:"region"`
+	Language         []string  `json:"language"`
+	Proper           bool      `json:"proper"`
+	Repack           bool      `json:"repack"`
+	Website          string    `json:"website"`
+	Artists          string    `json:"artists"`
+	Type             string    `json:"type"`
+	LogScore         int       `json:"log_score"`
+	Origin           string    `json:"origin"`
+	Tags             []string  `json:"tags"`
+	Freeleech        bool      `json:"freeleech"`
+	FreeleechPercent int       `json:"freeleech_percent"`
+	Bonus            []string  `json:"bonus"`
+	Uploader         string    `json:"uploader"`
+	PreTime          string    `json:"pre_time"`
+	Other            []string  `json:"other"`
+}
+
+// ExternalFilterContext is the filter and external filter a release is checked against
+type ExternalFilterContext struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Priority     int32  `json:"priority"`
+	ExternalName string `json:"external_name"`
+}
+
+// NewExternalFilterInput builds the input of an exec filter in JSON mode
+func NewExternalFilterInput(external FilterExternal, release *Release) ExternalFilterInput {
+	input := ExternalFilterInput{
+		Version: ExternalFilterContractVersion,
+		Release: ExternalFilterRelease{
+			Indexer:          release.Indexer,
+			Protocol:         string(release.Protocol),
+			Implementation:   string(release.Implementation),
+			Timestamp:        release.Timestamp,
+			InfoURL:          release.InfoURL,
+			DownloadURL:      release.DownloadURL,
+			GroupID:          release.GroupID,
+			TorrentID:        release.TorrentID,
+			TorrentName:      release.TorrentName,
+			TorrentTmpFile:   release.TorrentTmpFile,
+			TorrentHash:      release.TorrentHash,
+			Size:             release.Size,
+			Title:            release.Title,
+			Category:         release.Category,
+			Categories:       release.Categories,
+			Season:           release.Season,
+			Episode:          release.Episode,
+			Year:             release.Year,
+			Resolution:       release.Resolution,
+			Source:           release.Source,
+			Codec:            release.Codec,
+			Container:        release.Container,
+			HDR:              release.HDR,
+			Audio:            release.Audio,
+			AudioChannels:    release.AudioChannels,
+			Group:            release.Group,
+			Region:           release.Region,
+			Language:         release.Language,
+			Proper:           release.Proper,
+			Repack:           release.Repack,
+			Website:          release.Website,
+			Artists:          release.Artists,
+			Type:             release.Type,
+			LogScore:         release.LogScore,
+			Origin:           release.Origin,
+			Tags:             release.Tags,
+			Freeleech:        release.Freeleech,
+			FreeleechPercent: release.FreeleechPercent,
+			Bonus:            release.Bonus,
+			Uploader:         release.Uploader,
+			PreTime:          release.PreTime,
+			Other:            release.Other,
+		},
+		Filter: ExternalFilterContext{
+			ID:           release.FilterID,
+			Name:         release.FilterName,
+			ExternalName: external.Name,
+		},
+	}
+
+	if release.Filter != nil {
+		input.Filter.ID = release.Filter.ID
+		input.Filter.Name = release.Filter.Name
+		input.Filter.Priority = release.Filter.Priority
+	}
+
+	return input
+}
+
+// ExternalFilterOutput is read as json from stdout of exec filters in JSON mode.
+// The release is rejected with Reason unless Accept is true.
+type ExternalFilterOutput struct {
+	Accept    bool                     `json:"accept"`
+	Reason    string                   `json:"reason"`
+	Overrides *ExternalFilterOverrides `json:"overrides,omitempty"`
+}
+
+// ExternalFilterOverrides are release fields an accepting external filter can change
+type ExternalFilterOverrides struct {
+	Title      *string  `json:"title,omitempty"`
+	Category   *string  `json:"category,omitempty"`
+	Season     *int     `json:"season,omitempty"`
+	Episode    *int     `json:"episode,omitempty"`
+	Year       *int     `json:"year,omitempty"`
+	Resolution *string  `json:"resolution,omitempty"`
+	Source     *string  `json:"source,omitempty"`
+	Group      *string  `json:"group,omitempty"`
+	Uploader   *string  `json:"uploader,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// ParseExternalFilterOutput reads the output of an exec filter in JSON mode
+func ParseExternalFilterOutput(data []byte) (*ExternalFilterOutput, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty output")
+	}
+
+	var output ExternalFilterOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, errors.Wrap(err, "invalid json output")
+	}
+
+	return &output, nil
+}
+
+// Apply sets the overridden fields on the release
+func (o *ExternalFilterOverrides) Apply(release *Release) {
+	if o == nil {
+		return
+	}
+
+	if o.Title != nil {
+		release.Title = *o.Title
+	}
+	if o.Category != nil {
+		release.Category = *o.Category
+	}
+	if o.Season != nil {
+		release.Season = *o.Season
+	}
+	if o.Episode != nil {
+		release.Episode = *o.Episode
+	}
+	if o.Year != nil {
+		release.Year = *o.Year
+	}
+	if o.Resolution != nil {
+		release.Resolution = *o.Resolution
+	}
+	if o.Source != nil {
+		release.Source = *o.Source
+	}
+	if o.Group != nil {
+		release.Group = *o.Group
+	}
+	if o.Uploader != nil {
+		release.Uploader = *o.Uploader
+	}
+	if o.Tags != nil {
+		release.Tags = o.Tags
+	}
+}
+
+// ParseWebhookRetryStatus parses a comma separated list of http status codes a webhook filter request is retried on
+func ParseWebhookRetryStatus(text string) ([]int, error) {
+	var codes []int
+
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			return nil, errors.New("invalid retry status code: %s", part)
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// MatchJSONPath looks up the dot separated path in the json body and compares the value with expected.
+// Array elements are selected by index, eg. results.0.allowed. When expected is empty any value
+// except null, false, 0 and an empty string matches. The found value is returned for logging.
+func MatchJSONPath(body []byte, path string, expected string) (bool, string, error) {
+	keys, err := splitJSONPath(path)
+	if err != nil {
+		return false, "", err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return false, "", errors.Wrap(err, "invalid json response")
+	}
+
+	for _, key := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return false, "", nil
+			}
+			value = next
+
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return false, "", nil
+			}
+			value = v[idx]
+
+		default:
+			return false, "", nil
+		}
+	}
+
+	got := formatJSONValue(value)
+
+	if expected == "" {
+		switch v := value.(type) {
+		case nil:
+			return false, got, nil
+		case bool:
+			return v, got, nil
+		case float64:
+			return v != 0, got, nil
+		case string:
+			return v != "", got, nil
+		}
+
+		return true, got, nil
+	}
+
+	return got == expected, got, nil
+}
+
+func splitJSONPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$.")
+
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, errors.New("invalid json path: %s", path)
+		}
+	}
+
+	return keys, nil
+}
+
+func formatJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(b)
+}
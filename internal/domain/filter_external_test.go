@@ -0,0 +1,178 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExternalFilterEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "pairs", text: "API_KEY=secret\n\n# comment\nRELEASE=Some.Show.S01E01=x", want: []string{"API_KEY=secret", "RELEASE=Some.Show.S01E01=x"}},
+		{name: "windows line endings", text: "A=1\r\nB=2\r\n", want: []string{"A=1", "B=2"}},
+		{name: "missing separator", text: "API_KEY", wantErr: true},
+		{name: "empty key", text: "=value", wantErr: true},
+		{name: "space in key", text: "API KEY=value", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExternalFilterEnv(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterExternal_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		external FilterExternal
+		wantErr  bool
+	}{
+		{name: "legacy", external: FilterExternal{Type: ExternalFilterTypeExec}},
+		{name: "json", external: FilterExternal{Type: ExternalFilterTypeExec, ExecMode: ExternalFilterExecModeJSON, ExecTimeout: 30, ExecEnv: "A=1"}},
+		{name: "invalid mode", external: FilterExternal{Type: ExternalFilterTypeExec, ExecMode: "XML"}, wantErr: true},
+		{name: "negative timeout", external: FilterExternal{Type: ExternalFilterTypeExec, ExecTimeout: -1}, wantErr: true},
+		{name: "timeout too long", external: FilterExternal{Type: ExternalFilterTypeExec, ExecTimeout: ExternalFilterExecTimeoutMax + 1}, wantErr: true},
+		{name: "invalid env", external: FilterExternal{Type: ExternalFilterTypeExec, ExecEnv: "A"}, wantErr: true},
+		{name: "webhook ignores exec", external: FilterExternal{Type: ExternalFilterTypeWebhook, ExecMode: "XML"}},
+		{name: "webhook retries", external: FilterExternal{Type: ExternalFilterTypeWebhook, WebhookRetryStatus: "503", WebhookRetryAttempts: 3, WebhookRetryDelay: 2, WebhookJSONPath: "result.allowed"}},
+		{name: "webhook too many retries", external: FilterExternal{Type: ExternalFilterTypeWebhook, WebhookRetryAttempts: ExternalFilterWebhookRetryAttemptsMax + 1}, wantErr: true},
+		{name: "webhook invalid retry status", external: FilterExternal{Type: ExternalFilterTypeWebhook, WebhookRetryStatus: "5xx"}, wantErr: true},
+		{name: "webhook invalid json path", external: FilterExternal{Type: ExternalFilterTypeWebhook, WebhookJSONPath: "a..b"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.external.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilterExternal_Timeout(t *testing.T) {
+	assert.Equal(t, ExternalFilterExecTimeoutDefault*time.Second, FilterExternal{}.Timeout())
+	assert.Equal(t, 5*time.Second, FilterExternal{ExecTimeout: 5}.Timeout())
+}
+
+func TestParseExternalFilterOutput(t *testing.T) {
+	t.Run("reject", func(t *testing.T) {
+		output, err := ParseExternalFilterOutput([]byte(`{"accept": false, "reason": "already in library"}` + "\n"))
+		assert.NoError(t, err)
+		assert.False(t, output.Accept)
+		assert.Equal(t, "already in library", output.Reason)
+		assert.Nil(t, output.Overrides)
+	})
+
+	t.Run("accept with overrides", func(t *testing.T) {
+		output, err := ParseExternalFilterOutput([]byte(`{"accept": true, "overrides": {"title": "Other Show", "season": 2, "category": "tv-uhd"}}`))
+		assert.NoError(t, err)
+		assert.True(t, output.Accept)
+
+		r := NewRelease("ptp")
+		r.ParseString("Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX")
+
+		output.Overrides.Apply(r)
+
+		assert.Equal(t, "Other Show", r.Title)
+		assert.Equal(t, 2, r.Season)
+		assert.Equal(t, 1, r.Episode)
+		assert.Equal(t, "tv-uhd", r.Category)
+		assert.Equal(t, "FLUX", r.Group)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := ParseExternalFilterOutput([]byte("  \n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := ParseExternalFilterOutput([]byte("accept"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewExternalFilterInput(t *testing.T) {
+	r := NewRelease("ptp")
+	r.ParseString("Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX")
+	r.Filter = &Filter{ID: 3, Name: "tv", Priority: 10}
+
+	input := NewExternalFilterInput(FilterExternal{Name: "lookup"}, r)
+
+	assert.Equal(t, ExternalFilterContractVersion, input.Version)
+	assert.Equal(t, "ptp", input.Release.Indexer)
+	assert.Equal(t, r.TorrentName, input.Release.TorrentName)
+	assert.Equal(t, 1, input.Release.Season)
+	assert.Equal(t, ExternalFilterContext{ID: 3, Name: "tv", Priority: 10, ExternalName: "lookup"}, input.Filter)
+}
+
+func TestParseWebhookRetryStatus(t *testing.T) {
+	got, err := ParseWebhookRetryStatus("500, 502,503,")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{500, 502, 503}, got)
+
+	got, err = ParseWebhookRetryStatus("")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = ParseWebhookRetryStatus("500,abc")
+	assert.Error(t, err)
+
+	_, err = ParseWebhookRetryStatus("999")
+	assert.Error(t, err)
+}
+
+func TestMatchJSONPath(t *testing.T) {
+	body := []byte(`{"allowed": true, "score": 7.5, "reason": "", "results": [{"id": 1, "status": "missing"}], "nothing": null}`)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+		want     bool
+		got      string
+	}{
+		{name: "bool truthy", path: "allowed", want: true, got: "true"},
+		{name: "bool equal", path: "allowed", expected: "true", want: true, got: "true"},
+		{name: "bool not equal", path: "allowed", expected: "false", want: false, got: "true"},
+		{name: "number", path: "score", expected: "7.5", want: true, got: "7.5"},
+		{name: "empty string not truthy", path: "reason", want: false, got: ""},
+		{name: "array index", path: "results.0.status", expected: "missing", want: true, got: "missing"},
+		{name: "jsonpath prefix", path: "$.results.0.id", expected: "1", want: true, got: "1"},
+		{name: "null", path: "nothing", want: false, got: "null"},
+		{name: "missing key", path: "results.0.other", want: false},
+		{name: "index out of range", path: "results.1.status", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, got, err := MatchJSONPath(body, tt.path, tt.expected)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, match)
+			assert.Equal(t, tt.got, got)
+		})
+	}
+
+	_, _, err := MatchJSONPath([]byte("not json"), "allowed", "")
+	assert.Error(t, err)
+
+	_, _, err = MatchJSONPath(body, "results..id", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// FilterFlag is a named flag set by an irc trigger, like a sitewide freeleech, that filters can require or reject
+type FilterFlag struct {
+	Name string `json:"name"`
+	// Until is when the flag expires, zero when it stays until it is cleared
+	Until time.Time `json:"until,omitempty"`
+}
+
+// checkFlags reports whether all flags in the comma separated list are active
+func checkFlags(active []string, flags string) bool {
+	for _, flag := range strings.Split(flags, ",") {
+		flag = strings.TrimSpace(flag)
+		if flag == "" {
+			continue
+		}
+
+		if !containsFlag(active, flag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkAnyFlag reports whether any flag in the comma separated list is active
+func checkAnyFlag(active []string, flags string) bool {
+	for _, flag := range strings.Split(flags, ",") {
+		flag = strings.TrimSpace(flag)
+		if flag != "" && containsFlag(active, flag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFlag(active []string, flag string) bool {
+	for _, a := range active {
+		if strings.EqualFold(a, flag) {
+			return true
+		}
+	}
+
+	return false
+}
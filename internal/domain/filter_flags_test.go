@@ -0,0 +1,33 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_CheckFilter_Flags(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		flags  []string
+		want   bool
+	}{
+		{name: "no_flags", filter: Filter{}, flags: nil, want: true},
+		{name: "required_active", filter: Filter{Flags: "sitewide-fl"}, flags: []string{"SITEWIDE-FL"}, want: true},
+		{name: "required_inactive", filter: Filter{Flags: "sitewide-fl"}, flags: nil, want: false},
+		{name: "all_required", filter: Filter{Flags: "sitewide-fl, double-upload"}, flags: []string{"sitewide-fl"}, want: false},
+		{name: "unwanted_active", filter: Filter{ExceptFlags: "maintenance"}, flags: []string{"maintenance"}, want: false},
+		{name: "unwanted_inactive", filter: Filter{ExceptFlags: "maintenance"}, flags: []string{"sitewide-fl"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Release{Flags: tt.flags}
+			_, got := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.want, got, r.Rejections)
+		})
+	}
+}
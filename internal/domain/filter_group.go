@@ -0,0 +1,45 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type FilterGroupRepo interface {
+	List(ctx context.Context) ([]FilterGroup, error)
+	FindByID(ctx context.Context, groupID int) (*FilterGroup, error)
+	Store(ctx context.Context, group *FilterGroup) error
+	Update(ctx context.Context, group *FilterGroup) error
+	Delete(ctx context.Context, groupID int) error
+	FindIndexers(ctx context.Context, groupID int) ([]Indexer, error)
+	StoreIndexerConnections(ctx context.Context, groupID int, indexers []Indexer) error
+}
+
+// FilterGroup holds the settings shared by its member filters.
+// A filter inherits each setting it leaves empty itself.
+type FilterGroup struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	MinSize      string    `json:"min_size,omitempty"`
+	MaxSize      string    `json:"max_size,omitempty"`
+	FiltersCount int       `json:"filters_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Indexers     []Indexer `json:"indexers"`
+	Actions      []*Action `json:"actions"`
+}
+
+// ApplyTo sets the size limits of the group on a member filter that does not set them itself.
+// Indexers and actions are inherited when the filter is looked up by indexer and when it runs its actions.
+func (g *FilterGroup) ApplyTo(f *Filter) {
+	if f.MinSize == "" {
+		f.MinSize = g.MinSize
+	}
+
+	if f.MaxSize == "" {
+		f.MaxSize = g.MaxSize
+	}
+}
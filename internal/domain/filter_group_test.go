@@ -0,0 +1,45 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterGroup_ApplyTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		group  FilterGroup
+		filter Filter
+		want   Filter
+	}{
+		{
+			name:   "inherit_all",
+			group:  FilterGroup{MinSize: "1GB", MaxSize: "40GB"},
+			filter: Filter{Name: "a"},
+			want:   Filter{Name: "a", MinSize: "1GB", MaxSize: "40GB"},
+		},
+		{
+			name:   "keep_own",
+			group:  FilterGroup{MinSize: "1GB", MaxSize: "40GB"},
+			filter: Filter{Name: "a", MaxSize: "10GB"},
+			want:   Filter{Name: "a", MinSize: "1GB", MaxSize: "10GB"},
+		},
+		{
+			name:   "empty_group",
+			group:  FilterGroup{},
+			filter: Filter{Name: "a", MinSize: "2GB"},
+			want:   Filter{Name: "a", MinSize: "2GB"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.group.ApplyTo(&tt.filter)
+			assert.Equal(t, tt.want, tt.filter)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type FilterListFormat string
+
+const (
+	FilterListFormatText FilterListFormat = "TEXT"
+	FilterListFormatJSON FilterListFormat = "JSON"
+)
+
+// FilterListSource populates a list field of a filter from a remote url.
+// Text lists have one item per line or comma separated items, lines starting with # are ignored.
+// JSON lists are an array of strings, or an object with the array at JSONKey, eg. "data.groups".
+type FilterListSource struct {
+	ID              int              `json:"id"`
+	FilterID        int              `json:"filter_id"`
+	Field           string           `json:"field"`
+	URL             string           `json:"url"`
+	Format          FilterListFormat `json:"format"`
+	JSONKey         string           `json:"json_key,omitempty"`
+	RefreshInterval int              `json:"refresh_interval"` // minutes
+	LastRefreshedAt *time.Time       `json:"last_refreshed_at,omitempty"`
+	LastError       string           `json:"last_error,omitempty"`
+}
+
+// FilterListSourceMinInterval is the shortest refresh interval in minutes
+const FilterListSourceMinInterval = 5
+
+// filterListFields are the filter fields that can be populated from a list source
+var filterListFields = map[string]func(u *FilterUpdate, value string){
+	"match_releases":        func(u *FilterUpdate, v string) { u.MatchReleases = &v },
+	"except_releases":       func(u *FilterUpdate, v string) { u.ExceptReleases = &v },
+	"match_release_groups":  func(u *FilterUpdate, v string) { u.MatchReleaseGroups = &v },
+	"except_release_groups": func(u *FilterUpdate, v string) { u.ExceptReleaseGroups = &v },
+	"match_release_tags":    func(u *FilterUpdate, v string) { u.MatchReleaseTags = &v },
+	"except_release_tags":   func(u *FilterUpdate, v string) { u.ExceptReleaseTags = &v },
+	"match_uploaders":       func(u *FilterUpdate, v string) { u.MatchUploaders = &v },
+	"except_uploaders":      func(u *FilterUpdate, v string) { u.ExceptUploaders = &v },
+	"match_categories":      func(u *FilterUpdate, v string) { u.MatchCategories = &v },
+	"except_categories":     func(u *FilterUpdate, v string) { u.ExceptCategories = &v },
+	"shows":                 func(u *FilterUpdate, v string) { u.Shows = &v },
+	"years":                 func(u *FilterUpdate, v string) { u.Years = &v },
+	"artists":               func(u *FilterUpdate, v string) { u.Artists = &v },
+	"albums":                func(u *FilterUpdate, v string) { u.Albums = &v },
+	"tags":                  func(u *FilterUpdate, v string) { u.Tags = &v },
+	"except_tags":           func(u *FilterUpdate, v string) { u.ExceptTags = &v },
+}
+
+func (s FilterListSource) Validate() error {
+	if _, ok := filterListFields[s.Field]; !ok {
+		return errors.New("field %q can not be populated from a list", s.Field)
+	}
+
+	if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+		return errors.New("url must start with http:// or https://")
+	}
+
+	switch s.Format {
+	case FilterListFormatText, FilterListFormatJSON:
+	default:
+		return errors.New("invalid format %q, expected TEXT or JSON", s.Format)
+	}
+
+	if s.RefreshInterval < FilterListSourceMinInterval {
+		return errors.New("refresh interval must be at least %d minutes", FilterListSourceMinInterval)
+	}
+
+	return nil
+}
+
+// Due reports whether the source should be refreshed at t
+func (s FilterListSource) Due(t time.Time) bool {
+	if s.LastRefreshedAt == nil {
+		return true
+	}
+
+	return !t.Before(s.LastRefreshedAt.Add(time.Duration(s.RefreshInterval) * time.Minute))
+}
+
+// SetField sets the list field of the source on the filter update
+func (s FilterListSource) SetField(u *FilterUpdate, items []string) {
+	if set, ok := filterListFields[s.Field]; ok {
+		set(u, strings.Join(items, ","))
+	}
+}
+
+// ParseList parses the body of the list url into items
+func (s FilterListSource) ParseList(data []byte) ([]string, error) {
+	switch s.Format {
+	case FilterListFormatJSON:
+		return parseJSONList(data, s.JSONKey)
+	default:
+		return parseTextList(data)
+	}
+}
+
+func parseTextList(data []byte) ([]string, error) {
+	items := make([]string, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read list")
+	}
+
+	return items, nil
+}
+
+func parseJSONList(data []byte, key string) ([]string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, "could not decode json")
+	}
+
+	if key != "" {
+		for _, k := range strings.Split(key, ".") {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, errors.New("json key %q: %q is not an object", key, k)
+			}
+
+			if v, ok = obj[k]; !ok {
+				return nil, errors.New("json key %q: %q not found", key, k)
+			}
+		}
+	}
+
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("json is not a list of strings")
+	}
+
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, errors.New("json is not a list of strings")
+		}
+
+		if str = strings.TrimSpace(str); str != "" {
+			items = append(items, str)
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterListSource_ParseList(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  FilterListSource
+		data    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "text_lines",
+			source: FilterListSource{Format: FilterListFormatText},
+			data:   "# trusted groups\nFLUX\n\n  NTb \r\nGROUP1, GROUP2\n",
+			want:   []string{"FLUX", "NTb", "GROUP1", "GROUP2"},
+		},
+		{
+			name:   "json_array",
+			source: FilterListSource{Format: FilterListFormatJSON},
+			data:   `["FLUX", " NTb ", ""]`,
+			want:   []string{"FLUX", "NTb"},
+		},
+		{
+			name:   "json_key",
+			source: FilterListSource{Format: FilterListFormatJSON, JSONKey: "data.groups"},
+			data:   `{"data": {"groups": ["FLUX"]}}`,
+			want:   []string{"FLUX"},
+		},
+		{
+			name:    "json_key_missing",
+			source:  FilterListSource{Format: FilterListFormatJSON, JSONKey: "data.groups"},
+			data:    `{"data": {}}`,
+			wantErr: `json key "data.groups": "groups" not found`,
+		},
+		{
+			name:    "json_not_strings",
+			source:  FilterListSource{Format: FilterListFormatJSON},
+			data:    `[1, 2]`,
+			wantErr: "json is not a list of strings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.source.ParseList([]byte(tt.data))
+			if tt.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterListSource_Validate(t *testing.T) {
+	valid := FilterListSource{Field: "match_release_groups", URL: "https://example.com/groups.txt", Format: FilterListFormatText, RefreshInterval: 60}
+	assert.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.Field = "name"
+	assert.EqualError(t, invalid.Validate(), `field "name" can not be populated from a list`)
+
+	invalid = valid
+	invalid.URL = "file:///etc/passwd"
+	assert.Error(t, invalid.Validate())
+
+	invalid = valid
+	invalid.RefreshInterval = 1
+	assert.Error(t, invalid.Validate())
+}
+
+func TestFilterListSource_Due(t *testing.T) {
+	now := time.Now()
+	refreshed := now.Add(-30 * time.Minute)
+
+	assert.True(t, FilterListSource{RefreshInterval: 60}.Due(now))
+	assert.False(t, FilterListSource{RefreshInterval: 60, LastRefreshedAt: &refreshed}.Due(now))
+	assert.True(t, FilterListSource{RefreshInterval: 30, LastRefreshedAt: &refreshed}.Due(now))
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import "strings"
+
+// FilterMatchPolicy sets how a release matched by more than one filter is handled
+type FilterMatchPolicy string
+
+const (
+	// FilterMatchFirst runs the actions of the highest priority matching filter only
+	FilterMatchFirst FilterMatchPolicy = "first-match"
+
+	// FilterMatchAll runs the actions of every matching filter
+	FilterMatchAll FilterMatchPolicy = "all-match"
+
+	// FilterMatchPerActionDedupe runs the actions of every matching filter,
+	// but a release is sent to the same action target at most once
+	FilterMatchPerActionDedupe FilterMatchPolicy = "per-action-dedupe"
+)
+
+// ParseFilterMatchPolicy returns the policy for a config value, unknown values are first-match
+func ParseFilterMatchPolicy(value string) FilterMatchPolicy {
+	switch policy := FilterMatchPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case FilterMatchAll, FilterMatchPerActionDedupe:
+		return policy
+	default:
+		return FilterMatchFirst
+	}
+}
+
+// ValidFilterMatchPolicy reports whether the config value is a known policy, empty is the default first-match
+func ValidFilterMatchPolicy(value string) bool {
+	switch FilterMatchPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FilterMatchFirst, FilterMatchAll, FilterMatchPerActionDedupe:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContinueAfterMatch reports whether filters after a matched filter are checked as well
+func (p FilterMatchPolicy) ContinueAfterMatch() bool {
+	return p == FilterMatchAll || p == FilterMatchPerActionDedupe
+}
+
+// Dedupe reports whether an action target that accepted a release, like a download client, exec command, webhook or
+// watch folder, is skipped by the actions of other filters
+func (p FilterMatchPolicy) Dedupe() bool {
+	return p != FilterMatchAll
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterMatchPolicy(t *testing.T) {
+	assert.Equal(t, FilterMatchFirst, ParseFilterMatchPolicy(""))
+	assert.Equal(t, FilterMatchFirst, ParseFilterMatchPolicy("unknown"))
+	assert.Equal(t, FilterMatchAll, ParseFilterMatchPolicy(" All-Match "))
+	assert.Equal(t, FilterMatchPerActionDedupe, ParseFilterMatchPolicy("per-action-dedupe"))
+
+	assert.True(t, ValidFilterMatchPolicy(""))
+	assert.True(t, ValidFilterMatchPolicy(" Per-Action-Dedupe"))
+	assert.False(t, ValidFilterMatchPolicy("per-action-dedup"))
+
+	assert.False(t, FilterMatchFirst.ContinueAfterMatch())
+	assert.True(t, FilterMatchFirst.Dedupe())
+	assert.True(t, FilterMatchAll.ContinueAfterMatch())
+	assert.False(t, FilterMatchAll.Dedupe())
+	assert.True(t, FilterMatchPerActionDedupe.ContinueAfterMatch())
+	assert.True(t, FilterMatchPerActionDedupe.Dedupe())
+}
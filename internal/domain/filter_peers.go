@@ -0,0 +1,40 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// RequiresPeers returns true if the filter wants a minimum of seeders or leechers
+func (f Filter) RequiresPeers() bool {
+	return f.MinSeeders > 0 || f.MinLeechers > 0
+}
+
+// CheckPeers checks the seeders and leechers of the release against the minimums of the filter.
+// The release must have its peers set, either from the feed or the indexer api.
+func (f Filter) CheckPeers(r *Release) bool {
+	ok := true
+
+	if f.MinSeeders > 0 && r.Seeders < f.MinSeeders {
+		r.addRejectionF("seeders too low. got: %d want min: %d", r.Seeders, f.MinSeeders)
+		ok = false
+	}
+
+	if f.MinLeechers > 0 && r.Leechers < f.MinLeechers {
+		r.addRejectionF("leechers too low. got: %d want min: %d", r.Leechers, f.MinLeechers)
+		ok = false
+	}
+
+	return ok
+}
+
+// ValidatePeers checks the seeder and leecher minimums of a filter, zero means no minimum
+func ValidatePeers(minSeeders, minLeechers int) error {
+	if minSeeders < 0 || minLeechers < 0 {
+		return errors.New("min seeders and leechers can't be negative")
+	}
+
+	return nil
+}
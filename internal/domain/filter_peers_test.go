@@ -0,0 +1,60 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_CheckPeers(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     Filter
+		release    *Release
+		want       bool
+		rejections []string
+	}{
+		{
+			name:    "no_minimum",
+			filter:  Filter{},
+			release: &Release{},
+			want:    true,
+		},
+		{
+			name:    "enough_peers",
+			filter:  Filter{MinSeeders: 5, MinLeechers: 1},
+			release: &Release{Seeders: 5, Leechers: 3},
+			want:    true,
+		},
+		{
+			name:       "too_few_seeders",
+			filter:     Filter{MinSeeders: 10},
+			release:    &Release{Seeders: 4, Leechers: 20},
+			want:       false,
+			rejections: []string{"seeders too low. got: 4 want min: 10"},
+		},
+		{
+			name:       "too_few_seeders_and_leechers",
+			filter:     Filter{MinSeeders: 10, MinLeechers: 2},
+			release:    &Release{Seeders: 4},
+			want:       false,
+			rejections: []string{"seeders too low. got: 4 want min: 10", "leechers too low. got: 0 want min: 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.CheckPeers(tt.release))
+			assert.Equal(t, tt.rejections, tt.release.Rejections)
+		})
+	}
+}
+
+func TestValidatePeers(t *testing.T) {
+	assert.NoError(t, ValidatePeers(0, 0))
+	assert.NoError(t, ValidatePeers(10, 2))
+	assert.Error(t, ValidatePeers(-1, 0))
+	assert.Error(t, ValidatePeers(0, -1))
+}
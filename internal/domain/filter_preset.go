@@ -0,0 +1,407 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type FilterPresetFormat string
+
+const (
+	FilterPresetFormatAutobrr FilterPresetFormat = "AUTOBRR"
+	FilterPresetFormatTrash   FilterPresetFormat = "TRASH"
+)
+
+// FilterPresetApp is the arr a TRaSH custom format is written for, they use different source values
+type FilterPresetApp string
+
+const (
+	FilterPresetAppRadarr FilterPresetApp = "radarr"
+	FilterPresetAppSonarr FilterPresetApp = "sonarr"
+)
+
+// FilterPreset is a filter definition from a file or url that creates or updates a filter.
+// A filter created from a preset keeps the preset id, and a managed preset is synced again when its source changes.
+type FilterPreset struct {
+	ID       string             `json:"preset_id"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	Filter   FilterUpdate       `json:"filter"`
+	Format   FilterPresetFormat `json:"-"`
+	Hash     string             `json:"-"`
+	Warnings []string           `json:"-"`
+}
+
+type FilterPresetImportOptions struct {
+	// Source is the url the preset is fetched from, required to sync a managed preset
+	Source  string          `json:"source,omitempty"`
+	Managed bool            `json:"managed"`
+	App     FilterPresetApp `json:"app,omitempty"`
+}
+
+type FilterPresetImportResult struct {
+	FilterID int      `json:"filter_id"`
+	Name     string   `json:"name"`
+	PresetID string   `json:"preset_id"`
+	Created  bool     `json:"created"`
+	Changed  bool     `json:"changed"`
+	Warnings []string `json:"warnings"`
+}
+
+// TrashCustomFormat is a custom format in the TRaSH-Guides json format, which is also what radarr and sonarr export
+type TrashCustomFormat struct {
+	TrashID        string                     `json:"trash_id"`
+	Name           string                     `json:"name"`
+	Specifications []TrashFormatSpecification `json:"specifications"`
+}
+
+type TrashFormatSpecification struct {
+	Name           string          `json:"name"`
+	Implementation string          `json:"implementation"`
+	Negate         bool            `json:"negate"`
+	Required       bool            `json:"required"`
+	Fields         json.RawMessage `json:"fields"`
+}
+
+// fields returns the fields of a specification, TRaSH-Guides uses an object and the arr export an array of name and value
+func (s TrashFormatSpecification) fields() (map[string]any, error) {
+	fields := map[string]any{}
+	if len(s.Fields) == 0 {
+		return fields, nil
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(s.Fields), []byte("[")) {
+		var list []struct {
+			Name  string `json:"name"`
+			Value any    `json:"value"`
+		}
+		if err := json.Unmarshal(s.Fields, &list); err != nil {
+			return nil, errors.Wrap(err, "could not parse fields")
+		}
+
+		for _, field := range list {
+			fields[strings.ToLower(field.Name)] = field.Value
+		}
+
+		return fields, nil
+	}
+
+	if err := json.Unmarshal(s.Fields, &fields); err != nil {
+		return nil, errors.Wrap(err, "could not parse fields")
+	}
+
+	return fields, nil
+}
+
+// trashResolutions are the resolution values of radarr and sonarr
+var trashResolutions = map[int]string{360: "360p", 480: "480p", 540: "540p", 576: "576p", 720: "720p", 1080: "1080p", 2160: "2160p"}
+
+// trashSources map the source values of radarr and sonarr to a regex for the release source
+var trashSources = map[FilterPresetApp]map[int]string{
+	FilterPresetAppRadarr: {1: "cam", 2: "telesync|ts", 3: "telecine|tc", 4: "workprint", 5: "dvd", 6: "hdtv|tv", 7: "web-?dl|web", 8: "webrip", 9: "blu-?ray"},
+	FilterPresetAppSonarr: {1: "hdtv|tv", 2: "hdtv|tv", 3: "web-?dl|web", 4: "webrip", 5: "dvd", 6: "blu-?ray", 7: "blu-?ray"},
+}
+
+// PresetAppFromSource guesses the arr of a TRaSH custom format from its url, eg. .../docs/json/sonarr/cf/x.json
+func PresetAppFromSource(source string) FilterPresetApp {
+	if strings.Contains(strings.ToLower(source), "/sonarr/") {
+		return FilterPresetAppSonarr
+	}
+
+	return FilterPresetAppRadarr
+}
+
+// ParseFilterPreset parses an autobrr preset or a TRaSH custom format
+func ParseFilterPreset(data []byte, app FilterPresetApp) (*FilterPreset, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, errors.Wrap(err, "could not parse preset")
+	}
+
+	var preset *FilterPreset
+	var err error
+
+	switch {
+	case probe["specifications"] != nil:
+		var cf TrashCustomFormat
+		if err := json.Unmarshal(data, &cf); err != nil {
+			return nil, errors.Wrap(err, "could not parse custom format")
+		}
+
+		preset, err = cf.ToFilterPreset(app)
+
+	case probe["filter"] != nil:
+		preset = &FilterPreset{}
+		if err := json.Unmarshal(data, preset); err != nil {
+			return nil, errors.Wrap(err, "could not parse preset")
+		}
+
+		preset.Format = FilterPresetFormatAutobrr
+		err = preset.validate()
+
+	default:
+		return nil, errors.New("unknown preset format")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	preset.Hash = hex.EncodeToString(hash[:])
+
+	return preset, nil
+}
+
+// validate checks an autobrr preset and drops the fields that belong to the user and not to the preset
+func (p *FilterPreset) validate() error {
+	if p.ID == "" {
+		return errors.New("preset_id is required")
+	}
+
+	if p.Name == "" {
+		if p.Filter.Name == nil || *p.Filter.Name == "" {
+			return errors.New("name is required")
+		}
+
+		p.Name = *p.Filter.Name
+	}
+
+	p.Filter.ID = 0
+	p.Filter.Name = nil
+	p.Filter.Enabled = nil
+	p.Filter.GroupID = nil
+	p.Filter.Actions = nil
+	p.Filter.Indexers = nil
+	p.Filter.External = nil
+	p.Filter.ListSources = nil
+	p.Filter.PresetID = nil
+	p.Filter.PresetSource = nil
+	p.Filter.PresetManaged = nil
+	p.Filter.PresetHash = nil
+
+	return nil
+}
+
+// ToFilterPreset converts the custom format into a preset that sets the filter expression.
+// Like radarr and sonarr, specifications of the same implementation match if any of them matches,
+// or if all of them match when some are required. All implementations have to match.
+// Specifications that can not be expressed are skipped with a warning.
+func (cf TrashCustomFormat) ToFilterPreset(app FilterPresetApp) (*FilterPreset, error) {
+	if cf.Name == "" {
+		return nil, errors.New("custom format name is required")
+	}
+
+	if app == "" {
+		app = FilterPresetAppRadarr
+	}
+
+	preset := &FilterPreset{
+		ID:     "trash:" + cf.TrashID,
+		Name:   cf.Name,
+		Format: FilterPresetFormatTrash,
+	}
+
+	if cf.TrashID == "" {
+		preset.ID = "trash:" + strings.ToLower(cf.Name)
+	}
+
+	type group struct {
+		all      []string
+		required []string
+	}
+
+	var order []string
+	groups := map[string]*group{}
+
+	for _, spec := range cf.Specifications {
+		condition, err := spec.expression(app)
+		if err != nil {
+			preset.Warnings = append(preset.Warnings, fmt.Sprintf("skipped specification %q: %v", spec.Name, err))
+			continue
+		}
+
+		if spec.Negate {
+			condition = "!(" + condition + ")"
+		}
+
+		g, ok := groups[spec.Implementation]
+		if !ok {
+			g = &group{}
+			groups[spec.Implementation] = g
+			order = append(order, spec.Implementation)
+		}
+
+		g.all = append(g.all, condition)
+		if spec.Required {
+			g.required = append(g.required, condition)
+		}
+	}
+
+	if len(order) == 0 {
+		return nil, errors.New("custom format %s has no supported specifications", cf.Name)
+	}
+
+	conditions := make([]string, 0, len(order))
+	for _, implementation := range order {
+		g := groups[implementation]
+		if len(g.required) > 0 {
+			conditions = append(conditions, joinConditions(g.required, "&&"))
+		} else {
+			conditions = append(conditions, joinConditions(g.all, "||"))
+		}
+	}
+
+	expression := conditions[0]
+	if len(conditions) > 1 {
+		expression = strings.Join(conditions, " && ")
+	}
+
+	if _, err := compileExpression(expression); err != nil {
+		return nil, errors.Wrap(err, "could not build expression")
+	}
+
+	preset.Filter.Expression = &expression
+
+	return preset, nil
+}
+
+func joinConditions(conditions []string, op string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+
+	return "(" + strings.Join(conditions, " "+op+" ") + ")"
+}
+
+// expression returns the filter expression of a single specification
+func (s TrashFormatSpecification) expression(app FilterPresetApp) (string, error) {
+	fields, err := s.fields()
+	if err != nil {
+		return "", err
+	}
+
+	switch s.Implementation {
+	case "ReleaseTitleSpecification":
+		return regexCondition("TorrentName", fields["value"])
+
+	case "ReleaseGroupSpecification":
+		return regexCondition("Group", fields["value"])
+
+	case "ResolutionSpecification":
+		value, err := intField(fields["value"])
+		if err != nil {
+			return "", err
+		}
+
+		resolution, ok := trashResolutions[value]
+		if !ok {
+			return "", errors.New("unknown resolution: %d", value)
+		}
+
+		return fmt.Sprintf("Resolution == %s", quoteExpression(resolution)), nil
+
+	case "SourceSpecification":
+		value, err := intField(fields["value"])
+		if err != nil {
+			return "", err
+		}
+
+		source, ok := trashSources[app][value]
+		if !ok {
+			return "", errors.New("unknown %s source: %d", app, value)
+		}
+
+		return fmt.Sprintf("Source matches %s", quoteExpression("(?i)^(?:"+source+")$")), nil
+
+	case "SizeSpecification":
+		min, err := floatField(fields["min"])
+		if err != nil {
+			return "", err
+		}
+
+		max, err := floatField(fields["max"])
+		if err != nil {
+			return "", err
+		}
+
+		var conditions []string
+		if min > 0 {
+			conditions = append(conditions, "Size >= "+formatPresetSize(min))
+		}
+		if max > 0 {
+			conditions = append(conditions, "Size <= "+formatPresetSize(max))
+		}
+
+		if len(conditions) == 0 {
+			return "", errors.New("size without min or max")
+		}
+
+		return joinConditions(conditions, "&&"), nil
+	}
+
+	return "", errors.New("unsupported implementation %s", s.Implementation)
+}
+
+func regexCondition(field string, value any) (string, error) {
+	pattern, ok := value.(string)
+	if !ok || pattern == "" {
+		return "", errors.New("missing regex")
+	}
+
+	// the arrs use .NET regexes, lookarounds and backreferences are not supported by go
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", errors.New("unsupported regex %q", pattern)
+	}
+
+	// the arrs match case-insensitive
+	return fmt.Sprintf("%s matches %s", field, quoteExpression("(?i)"+pattern)), nil
+}
+
+// quoteExpression quotes a string literal for a filter expression
+func quoteExpression(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+
+	return `"` + s + `"`
+}
+
+// formatPresetSize formats a size in GB for an expression, the arrs count GB in multiples of 1024
+func formatPresetSize(gb float64) string {
+	return strconv.FormatFloat(gb, 'f', -1, 64) + "GiB"
+}
+
+func intField(value any) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	}
+
+	return 0, errors.New("invalid value: %v", value)
+}
+
+func floatField(value any) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return 0, errors.New("invalid value: %v", value)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterPreset_Trash(t *testing.T) {
+	data := `{
+  "trash_id": "e6258996055b9fbab7e9cb2f75819294",
+  "name": "WEB Tier 01",
+  "specifications": [
+    {"name": "WEB", "implementation": "SourceSpecification", "negate": false, "required": false, "fields": {"value": 7}},
+    {"name": "WEBRip", "implementation": "SourceSpecification", "negate": false, "required": false, "fields": {"value": 8}},
+    {"name": "FLUX", "implementation": "ReleaseGroupSpecification", "negate": false, "required": false, "fields": {"value": "^(FLUX)$"}},
+    {"name": "NTb", "implementation": "ReleaseGroupSpecification", "negate": false, "required": false, "fields": {"value": "^(NTb)$"}},
+    {"name": "Not Scene", "implementation": "ReleaseTitleSpecification", "negate": true, "required": true, "fields": [{"name": "value", "value": "\\b(scene)\\b"}]},
+    {"name": "Not DV", "implementation": "ReleaseTitleSpecification", "negate": true, "required": false, "fields": {"value": "^(?!.*HDR).*\\bDV\\b"}},
+    {"name": "Language", "implementation": "LanguageSpecification", "negate": false, "required": false, "fields": {"value": 1}}
+  ]
+}`
+
+	preset, err := ParseFilterPreset([]byte(data), FilterPresetAppRadarr)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "trash:e6258996055b9fbab7e9cb2f75819294", preset.ID)
+	assert.Equal(t, "WEB Tier 01", preset.Name)
+	assert.Equal(t, FilterPresetFormatTrash, preset.Format)
+	assert.Len(t, preset.Hash, 64)
+	assert.Equal(t, []string{
+		`skipped specification "Not DV": unsupported regex "^(?!.*HDR).*\\bDV\\b"`,
+		`skipped specification "Language": unsupported implementation LanguageSpecification`,
+	}, preset.Warnings)
+	assert.Equal(t, `(Source matches "(?i)^(?:web-?dl|web)$" || Source matches "(?i)^(?:webrip)$") && (Group matches "(?i)^(FLUX)$" || Group matches "(?i)^(NTb)$") && !(TorrentName matches "(?i)\\b(scene)\\b")`, *preset.Filter.Expression)
+
+	f := Filter{Expression: *preset.Filter.Expression}
+
+	r := NewRelease("mock")
+	r.ParseString("That.Movie.2023.2160p.WEB-DL.DDP5.1.H.265-FLUX")
+	match, err := f.CheckExpression(r)
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	r = NewRelease("mock")
+	r.ParseString("That.Movie.2023.2160p.BluRay.DDP5.1.H.265-FLUX")
+	match, err = f.CheckExpression(r)
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
+
+func TestTrashCustomFormat_ToFilterPreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		cf      string
+		app     FilterPresetApp
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "resolution and size",
+			cf:   `{"name": "Big 4K", "specifications": [{"name": "2160p", "implementation": "ResolutionSpecification", "required": true, "fields": {"value": 2160}}, {"name": "Size", "implementation": "SizeSpecification", "required": true, "fields": {"min": 20, "max": 80.5}}]}`,
+			want: `Resolution == "2160p" && (Size >= 20GiB && Size <= 80.5GiB)`,
+		},
+		{
+			name: "sonarr source",
+			cf:   `{"name": "Bluray", "specifications": [{"name": "Bluray", "implementation": "SourceSpecification", "fields": {"value": 6}}]}`,
+			app:  FilterPresetAppSonarr,
+			want: `Source matches "(?i)^(?:blu-?ray)$"`,
+		},
+		{
+			name: "quotes",
+			cf:   `{"name": "Quoted", "specifications": [{"name": "q", "implementation": "ReleaseTitleSpecification", "fields": {"value": "\"x\""}}]}`,
+			want: `TorrentName matches "(?i)\"x\""`,
+		},
+		{
+			name:    "nothing supported",
+			cf:      `{"name": "Language", "specifications": [{"name": "Language", "implementation": "LanguageSpecification", "fields": {"value": 1}}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset, err := ParseFilterPreset([]byte(tt.cf), tt.app)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, *preset.Filter.Expression)
+		})
+	}
+}
+
+func TestParseFilterPreset_Autobrr(t *testing.T) {
+	data := `{"preset_id": "hd-movies", "name": "HD Movies", "version": "2", "filter": {"id": 5, "enabled": true, "resolutions": ["1080p"], "match_release_groups": "FLUX", "actions": [{"name": "qbit"}]}}`
+
+	preset, err := ParseFilterPreset([]byte(data), "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "hd-movies", preset.ID)
+	assert.Equal(t, FilterPresetFormatAutobrr, preset.Format)
+	assert.Equal(t, []string{"1080p"}, *preset.Filter.Resolutions)
+	assert.Equal(t, "FLUX", *preset.Filter.MatchReleaseGroups)
+	assert.Equal(t, 0, preset.Filter.ID)
+	assert.Nil(t, preset.Filter.Enabled)
+	assert.Nil(t, preset.Filter.Actions)
+
+	_, err = ParseFilterPreset([]byte(`{"name": "No id", "filter": {}}`), "")
+	assert.Error(t, err)
+
+	_, err = ParseFilterPreset([]byte(`{"name": "Unknown"}`), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterRegexResult is the outcome of testing a comma separated list of patterns against a sample
+type FilterRegexResult struct {
+	Valid    bool                 `json:"valid"`
+	Match    bool                 `json:"match"`
+	Patterns []FilterRegexPattern `json:"patterns"`
+}
+
+type FilterRegexPattern struct {
+	Pattern string `json:"pattern"`
+	Valid   bool   `json:"valid"`
+	Error   string `json:"error,omitempty"`
+	Match   bool   `json:"match"`
+}
+
+// TestRegexList compiles the patterns the same way filters do, case insensitive and split on commas,
+// and matches them against the sample. The list matches when any pattern matches.
+func TestRegexList(filterList string, sample string) FilterRegexResult {
+	result := FilterRegexResult{Valid: true, Patterns: []FilterRegexPattern{}}
+
+	for _, filter := range strings.Split(filterList, ",") {
+		if filter == "" {
+			continue
+		}
+
+		pattern := FilterRegexPattern{Pattern: filter, Valid: true}
+
+		re, err := regexp.Compile(`(?i)(?:` + filter + `)`)
+		if err != nil {
+			pattern.Valid = false
+			pattern.Error = err.Error()
+			result.Valid = false
+		} else if sample != "" && re.MatchString(sample) {
+			pattern.Match = true
+			result.Match = true
+		}
+
+		result.Patterns = append(result.Patterns, pattern)
+	}
+
+	return result
+}
+
+// ValidateRegexFields checks the patterns of all fields that have regex enabled
+func (f *Filter) ValidateRegexFields() error {
+	fields := []struct {
+		enabled bool
+		name    string
+		value   string
+	}{
+		{f.UseRegex, "match_releases", f.MatchReleases},
+		{f.UseRegex, "except_releases", f.ExceptReleases},
+		{f.UseRegexShows, "shows", f.Shows},
+		{f.UseRegexReleaseGroups, "match_release_groups", f.MatchReleaseGroups},
+		{f.UseRegexReleaseGroups, "except_release_groups", f.ExceptReleaseGroups},
+		{f.UseRegexTags, "tags", f.Tags},
+		{f.UseRegexTags, "except_tags", f.ExceptTags},
+		{f.UseRegexUploaders, "match_uploaders", f.MatchUploaders},
+		{f.UseRegexUploaders, "except_uploaders", f.ExceptUploaders},
+		{f.UseRegexReleaseTags, "match_release_tags", f.MatchReleaseTags},
+		{f.UseRegexReleaseTags, "except_release_tags", f.ExceptReleaseTags},
+		{f.UseRegexDescription, "match_description", f.MatchDescription},
+		{f.UseRegexDescription, "except_description", f.ExceptDescription},
+	}
+
+	for _, field := range fields {
+		if !field.enabled || field.value == "" {
+			continue
+		}
+
+		for _, pattern := range TestRegexList(field.value, "").Patterns {
+			if !pattern.Valid {
+				return errors.New("%s: invalid regex %q: %s", field.name, pattern.Pattern, pattern.Error)
+			}
+		}
+	}
+
+	return nil
+}
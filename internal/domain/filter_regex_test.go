@@ -0,0 +1,66 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestRegexList(t *testing.T) {
+	result := TestRegexList(`^(FLUX|NTb)$,^GRP-\d+$`, "ntb")
+	assert.True(t, result.Valid)
+	assert.True(t, result.Match)
+	assert.Equal(t, []FilterRegexPattern{
+		{Pattern: `^(FLUX|NTb)$`, Valid: true, Match: true},
+		{Pattern: `^GRP-\d+$`, Valid: true},
+	}, result.Patterns)
+
+	result = TestRegexList(`^(FLUX`, "FLUX")
+	assert.False(t, result.Valid)
+	assert.False(t, result.Match)
+	assert.Contains(t, result.Patterns[0].Error, "missing closing )")
+}
+
+func TestFilter_ValidateRegexFields(t *testing.T) {
+	f := Filter{MatchReleaseGroups: "^(FLUX"}
+	assert.NoError(t, f.ValidateRegexFields())
+
+	f.UseRegexReleaseGroups = true
+	assert.EqualError(t, f.ValidateRegexFields(), "match_release_groups: invalid regex \"^(FLUX\": error parsing regexp: missing closing ): `(?i)(?:^(FLUX)`")
+}
+
+func TestFilter_CheckFilter_RegexFields(t *testing.T) {
+	release := func() *Release {
+		r := NewRelease("mock")
+		r.ParseString("That Show S01E01 2160p WEB-DL DDP5.1 HDR H.265-FLUX")
+		r.Uploader = "uploader1"
+		r.Tags = []string{"tv", "hdr10"}
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{name: "shows_anchored", filter: Filter{UseRegexShows: true, Shows: "^that show$"}, match: true},
+		{name: "shows_anchored_no_match", filter: Filter{UseRegexShows: true, Shows: "^show"}, match: false},
+		{name: "groups_alternation", filter: Filter{UseRegexReleaseGroups: true, MatchReleaseGroups: "^(FLUX|NTb)$"}, match: true},
+		{name: "groups_except", filter: Filter{UseRegexReleaseGroups: true, ExceptReleaseGroups: "^FL"}, match: false},
+		{name: "uploaders", filter: Filter{UseRegexUploaders: true, MatchUploaders: `^uploader\d$`}, match: true},
+		{name: "tags_any", filter: Filter{UseRegexTags: true, Tags: `^hdr\d+$`}, match: true},
+		{name: "tags_all", filter: Filter{UseRegexTags: true, Tags: `^hdr\d+$,^movie$`, TagsMatchLogic: "ALL"}, match: false},
+		{name: "tags_except", filter: Filter{UseRegexTags: true, ExceptTags: `^t.$`}, match: false},
+		{name: "without_regex_toggle", filter: Filter{MatchReleaseGroups: "^(FLUX|NTb)$"}, match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejections, match := tt.filter.CheckFilter(release())
+			assert.Equal(t, tt.match, match, rejections)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type FilterRevisionRepo interface {
+	List(ctx context.Context, filterID int) ([]FilterRevision, error)
+	FindByRevision(ctx context.Context, filterID int, revision int) (*FilterRevision, error)
+	FindLatest(ctx context.Context, filterID int) (*FilterRevision, error)
+	Store(ctx context.Context, revision *FilterRevision) error
+	DeleteOld(ctx context.Context, filterID int, keep int) error
+	DeleteByFilterID(ctx context.Context, filterID int) error
+}
+
+// FilterRevision is a snapshot of a filter with its indexers, actions and external filters after a change
+type FilterRevision struct {
+	ID        int                 `json:"id"`
+	FilterID  int                 `json:"filter_id"`
+	Revision  int                 `json:"revision"`
+	Username  string              `json:"username,omitempty"`
+	Note      string              `json:"note,omitempty"`
+	Changes   []FilterFieldChange `json:"changes"`
+	Snapshot  *Filter             `json:"snapshot,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type FilterFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// DiffFilters returns the fields that differ between two filters.
+// Ids and timestamps are ignored, indexers are compared by identifier and clients by id.
+func DiffFilters(old, new *Filter) ([]FilterFieldChange, error) {
+	oldFields, err := filterDiffFields(old)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := filterDiffFields(new)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{}, len(newFields))
+	for k := range oldFields {
+		keys[k] = struct{}{}
+	}
+	for k := range newFields {
+		keys[k] = struct{}{}
+	}
+
+	changes := make([]FilterFieldChange, 0)
+	for k := range keys {
+		if reflect.DeepEqual(oldFields[k], newFields[k]) {
+			continue
+		}
+
+		changes = append(changes, FilterFieldChange{Field: k, Old: oldFields[k], New: newFields[k]})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes, nil
+}
+
+// filterDiffFields returns the json fields of a filter without the ones that change without an edit
+func filterDiffFields(f *Filter) (map[string]any, error) {
+	c := *f
+
+	indexers := make([]Indexer, 0, len(f.Indexers))
+	for _, indexer := range f.Indexers {
+		indexers = append(indexers, Indexer{Identifier: indexer.Identifier})
+	}
+	c.Indexers = indexers
+
+	actions := make([]*Action, 0, len(f.Actions))
+	for _, action := range f.Actions {
+		a := *action
+		a.ID = 0
+		a.FilterID = 0
+		a.Client = nil
+		actions = append(actions, &a)
+	}
+	c.Actions = actions
+
+	external := make([]FilterExternal, 0, len(f.External))
+	for _, e := range f.External {
+		e.ID = 0
+		e.FilterId = 0
+		external = append(external, e)
+	}
+	c.External = external
+
+	sources := make([]FilterListSource, 0, len(f.ListSources))
+	for _, s := range f.ListSources {
+		s.ID = 0
+		s.FilterID = 0
+		s.LastRefreshedAt = nil
+		s.LastError = ""
+		sources = append(sources, s)
+	}
+	c.ListSources = sources
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "id")
+	delete(fields, "created_at")
+	delete(fields, "updated_at")
+	delete(fields, "actions_count")
+
+	return fields, nil
+}
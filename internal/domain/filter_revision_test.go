@@ -0,0 +1,44 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFilters(t *testing.T) {
+	old := &Filter{
+		ID:        1,
+		Name:      "test",
+		MaxSize:   "10GB",
+		CreatedAt: time.Now(),
+		Indexers:  []Indexer{{ID: 1, Identifier: "mock", Name: "Mock"}},
+		Actions:   []*Action{{ID: 1, FilterID: 1, Name: "watch", Type: ActionTypeWatchFolder, WatchFolder: "/watch"}},
+	}
+
+	new := &Filter{
+		ID:        1,
+		Name:      "test",
+		MaxSize:   "20GB",
+		CreatedAt: time.Now().Add(time.Hour),
+		UpdatedAt: time.Now().Add(time.Hour),
+		Indexers:  []Indexer{{ID: 2, Identifier: "mock", Name: "Mock renamed"}},
+		Actions:   []*Action{{ID: 5, FilterID: 1, Name: "watch", Type: ActionTypeWatchFolder, WatchFolder: "/watch"}},
+		Shows:     "Show",
+	}
+
+	changes, err := DiffFilters(old, new)
+	assert.NoError(t, err)
+	assert.Equal(t, []FilterFieldChange{
+		{Field: "max_size", Old: "10GB", New: "20GB"},
+		{Field: "shows", Old: nil, New: "Show"},
+	}, changes)
+
+	changes, err = DiffFilters(old, old)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
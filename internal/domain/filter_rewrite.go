@@ -0,0 +1,47 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"regexp"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterRewriteRule replaces every match of Pattern in the release name with Replacement.
+// Replacement can reference capture groups like $1 or ${name}.
+type FilterRewriteRule struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// RewriteReleaseName applies the rewrite rules of the filter in order to the release name.
+// Rules with invalid patterns are skipped, they are rejected when the filter is saved.
+func (f Filter) RewriteReleaseName(name string) string {
+	for _, rule := range f.RewriteRules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+
+		name = re.ReplaceAllString(name, rule.Replacement)
+	}
+
+	return name
+}
+
+// ValidateRewriteRules checks that all rules have a valid pattern
+func ValidateRewriteRules(rules []FilterRewriteRule) error {
+	for i, rule := range rules {
+		if rule.Pattern == "" {
+			return errors.New("rewrite rule %d: pattern can't be empty", i+1)
+		}
+
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return errors.Wrap(err, "rewrite rule %d: invalid pattern: %s", i+1, rule.Pattern)
+		}
+	}
+
+	return nil
+}
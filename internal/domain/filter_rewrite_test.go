@@ -0,0 +1,59 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_RewriteReleaseName(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []FilterRewriteRule
+		input string
+		want  string
+	}{
+		{
+			name:  "no_rules",
+			input: "That.Show.S01E01.1080p.WEB.h264-GROUP",
+			want:  "That.Show.S01E01.1080p.WEB.h264-GROUP",
+		},
+		{
+			name:  "strip_tracker_tag",
+			rules: []FilterRewriteRule{{Pattern: `\s*\[[^\]]+\]$`, Replacement: ""}},
+			input: "That.Show.S01E01.1080p.WEB.h264-GROUP [TrackerTag]",
+			want:  "That.Show.S01E01.1080p.WEB.h264-GROUP",
+		},
+		{
+			name: "rules_in_order",
+			rules: []FilterRewriteRule{
+				{Pattern: `(?i)-group$`, Replacement: "-GRP"},
+				{Pattern: `-(\w+)$`, Replacement: "-${1}x"},
+			},
+			input: "That.Show.S01E01.1080p.WEB.h264-group",
+			want:  "That.Show.S01E01.1080p.WEB.h264-GRPx",
+		},
+		{
+			name:  "invalid_pattern_skipped",
+			rules: []FilterRewriteRule{{Pattern: `(`, Replacement: ""}},
+			input: "That.Show.S01E01",
+			want:  "That.Show.S01E01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{RewriteRules: tt.rules}
+			assert.Equal(t, tt.want, f.RewriteReleaseName(tt.input))
+		})
+	}
+}
+
+func TestValidateRewriteRules(t *testing.T) {
+	assert.NoError(t, ValidateRewriteRules(nil))
+	assert.NoError(t, ValidateRewriteRules([]FilterRewriteRule{{Pattern: `\[.*\]`}}))
+	assert.Error(t, ValidateRewriteRules([]FilterRewriteRule{{Pattern: ""}}))
+	assert.Error(t, ValidateRewriteRules([]FilterRewriteRule{{Pattern: "("}}))
+}
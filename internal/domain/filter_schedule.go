@@ -0,0 +1,190 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// FilterSchedule holds the windows in which a filter is active.
+//
+// Windows are separated by ";" and consist of optional days and an optional time range in server local time, eg.
+// "mon-fri 22:00-06:00; sat,sun". A time range that ends before it starts runs past midnight into the next day.
+type FilterSchedule struct {
+	windows []scheduleWindow
+}
+
+type scheduleWindow struct {
+	days  [7]bool
+	start int
+	end   int
+}
+
+// ParseFilterSchedule parses a schedule like "mon-fri 22:00-06:00; sat,sun"
+func ParseFilterSchedule(schedule string) (*FilterSchedule, error) {
+	s := &FilterSchedule{}
+
+	for _, part := range strings.Split(schedule, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		w, err := parseScheduleWindow(part)
+		if err != nil {
+			return nil, err
+		}
+
+		s.windows = append(s.windows, w)
+	}
+
+	if len(s.windows) == 0 {
+		return nil, errors.New("schedule is empty")
+	}
+
+	return s, nil
+}
+
+func parseScheduleWindow(window string) (scheduleWindow, error) {
+	w := scheduleWindow{start: 0, end: 24 * 60}
+
+	var hasDays, hasTime bool
+
+	for _, field := range strings.Fields(window) {
+		if strings.Contains(field, ":") {
+			if hasTime {
+				return w, errors.New("window %q: more than one time range", window)
+			}
+
+			start, end, err := parseScheduleTimeRange(field)
+			if err != nil {
+				return w, errors.Wrap(err, "window %q", window)
+			}
+
+			w.start, w.end = start, end
+			hasTime = true
+			continue
+		}
+
+		if hasDays {
+			return w, errors.New("window %q: more than one list of days", window)
+		}
+
+		days, err := parseScheduleDays(field)
+		if err != nil {
+			return w, errors.Wrap(err, "window %q", window)
+		}
+
+		w.days = days
+		hasDays = true
+	}
+
+	if !hasDays {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	}
+
+	return w, nil
+}
+
+// parseScheduleDays parses days like "mon,wed,fri" or ranges like "mon-fri" and "fri-mon"
+func parseScheduleDays(field string) ([7]bool, error) {
+	var days [7]bool
+
+	for _, item := range strings.Split(strings.ToLower(field), ",") {
+		from, to, isRange := strings.Cut(item, "-")
+
+		start, ok := scheduleDays[from]
+		if !ok {
+			return days, errors.New("invalid day: %q", from)
+		}
+
+		end := start
+		if isRange {
+			if end, ok = scheduleDays[to]; !ok {
+				return days, errors.New("invalid day: %q", to)
+			}
+		}
+
+		for d := start; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// parseScheduleTimeRange parses "22:00-06:00" to minutes since midnight, "24:00" is allowed as end
+func parseScheduleTimeRange(field string) (int, int, error) {
+	from, to, ok := strings.Cut(field, "-")
+	if !ok {
+		return 0, 0, errors.New("invalid time range: %q, expected eg. 22:00-06:00", field)
+	}
+
+	start, err := parseScheduleTime(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end := 24 * 60
+	if to != "24:00" {
+		if end, err = parseScheduleTime(to); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if start == end {
+		return 0, 0, errors.New("invalid time range: %q, start and end are equal", field)
+	}
+
+	return start, end, nil
+}
+
+func parseScheduleTime(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("invalid time: %q, expected HH:MM", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Active reports whether t falls within one of the schedule windows
+func (s *FilterSchedule) Active(t time.Time) bool {
+	day := t.Weekday()
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, w := range s.windows {
+		if w.start < w.end {
+			if w.days[day] && minute >= w.start && minute < w.end {
+				return true
+			}
+			continue
+		}
+
+		// the window runs past midnight, so the early hours belong to the previous day
+		if (w.days[day] && minute >= w.start) || (w.days[(day+6)%7] && minute < w.end) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSchedule_Active(t *testing.T) {
+	// 2023-05-01 is a monday
+	at := func(day int, clock string) time.Time {
+		c, _ := time.Parse("15:04", clock)
+		return time.Date(2023, 5, day, c.Hour(), c.Minute(), 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		t        time.Time
+		want     bool
+	}{
+		{name: "time_inside", schedule: "09:00-17:00", t: at(1, "12:00"), want: true},
+		{name: "time_end_excluded", schedule: "09:00-17:00", t: at(1, "17:00"), want: false},
+		{name: "days_inside", schedule: "mon-fri", t: at(5, "23:59"), want: true},
+		{name: "days_outside", schedule: "mon-fri", t: at(6, "12:00"), want: false},
+		{name: "days_list", schedule: "Sat,Sunday", t: at(7, "08:00"), want: true},
+		{name: "days_wrap", schedule: "fri-mon", t: at(7, "08:00"), want: true},
+		{name: "overnight_evening", schedule: "mon-fri 22:00-06:00", t: at(5, "23:00"), want: true},
+		{name: "overnight_morning_after", schedule: "mon-fri 22:00-06:00", t: at(6, "05:59"), want: true},
+		{name: "overnight_morning_before", schedule: "mon-fri 22:00-06:00", t: at(1, "05:00"), want: false},
+		{name: "until_midnight", schedule: "sun 20:00-24:00", t: at(7, "23:59"), want: true},
+		{name: "multiple_windows", schedule: "mon-fri 00:00-08:00; sat,sun", t: at(6, "14:00"), want: true},
+		{name: "multiple_windows_outside", schedule: "mon-fri 00:00-08:00; sat,sun", t: at(3, "14:00"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseFilterSchedule(tt.schedule)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.want, s.Active(tt.t))
+		})
+	}
+}
+
+func TestParseFilterSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		errMsg   string
+	}{
+		{name: "empty", schedule: " ; ", errMsg: "schedule is empty"},
+		{name: "invalid_day", schedule: "mon-fry", errMsg: `invalid day: "fry"`},
+		{name: "invalid_time", schedule: "25:00-06:00", errMsg: `invalid time: "25:00"`},
+		{name: "missing_end", schedule: "22:00", errMsg: `invalid time range: "22:00"`},
+		{name: "equal_times", schedule: "06:00-06:00", errMsg: "start and end are equal"},
+		{name: "two_ranges", schedule: "06:00-07:00 08:00-09:00", errMsg: "more than one time range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilterSchedule(tt.schedule)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestFilter_CheckFilter_ScheduleUsesReleaseTimestamp(t *testing.T) {
+	f := Filter{Schedule: "mon-fri 09:00-17:00"}
+
+	// 2023-05-06 is a saturday
+	r := &Release{TorrentName: "That Show S01E01 1080p WEB-DL H.264-GROUP", Timestamp: time.Date(2023, 5, 6, 12, 0, 0, 0, time.Local)}
+	r.ParseString(r.TorrentName)
+
+	rejections, match := f.CheckFilter(r)
+	assert.False(t, match)
+	assert.Equal(t, []string{"outside schedule. got: Sat 12:00 want: mon-fri 09:00-17:00"}, rejections)
+
+	r.Timestamp = time.Date(2023, 5, 5, 12, 0, 0, 0, time.Local)
+	_, match = f.CheckFilter(r)
+	assert.True(t, match)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type FilterStatsRepo interface {
+	Store(ctx context.Context, entries []FilterStatsEntry) error
+	Find(ctx context.Context, filterID int, since time.Time) ([]FilterStatsEntry, error)
+	FindActionStatuses(ctx context.Context, filterID int, since time.Time) ([]ReleaseActionStatus, error)
+	DeleteOld(ctx context.Context, before time.Time) error
+	DeleteByFilterID(ctx context.Context, filterID int) error
+}
+
+// FilterStatsEntry holds the counters of a filter for one hour
+type FilterStatsEntry struct {
+	FilterID   int
+	Bucket     time.Time
+	Evaluated  int
+	Matched    int
+	Rejected   int
+	Rejections map[string]int
+}
+
+type FilterStatsBucketSize string
+
+const (
+	FilterStatsBucketHour FilterStatsBucketSize = "hour"
+	FilterStatsBucketDay  FilterStatsBucketSize = "day"
+)
+
+// FilterStats are the counters of a filter over a period, in total and per bucket
+type FilterStats struct {
+	FilterID        int                 `json:"filter_id"`
+	Since           time.Time           `json:"since"`
+	BucketSize      string              `json:"bucket_size"`
+	LastEvaluatedAt *time.Time          `json:"last_evaluated_at"`
+	LastMatchedAt   *time.Time          `json:"last_matched_at"`
+	Totals          FilterStatsCounts   `json:"totals"`
+	Buckets         []FilterStatsBucket `json:"buckets"`
+}
+
+type FilterStatsBucket struct {
+	Start time.Time `json:"start"`
+	FilterStatsCounts
+}
+
+type FilterStatsCounts struct {
+	Evaluated       int                         `json:"evaluated"`
+	Matched         int                         `json:"matched"`
+	Rejected        int                         `json:"rejected"`
+	ActionsPushed   int                         `json:"actions_pushed"`
+	ActionsRejected int                         `json:"actions_rejected"`
+	ActionErrors    int                         `json:"action_errors"`
+	Rejections      []FilterStatsRejectionCount `json:"rejections"`
+}
+
+type FilterStatsRejectionCount struct {
+	Condition string `json:"condition"`
+	Count     int    `json:"count"`
+}
+
+// RejectionCondition returns the condition of a filter rejection without the values,
+// e.g. "resolution" for "resolution not matching. got: 1080p want: [2160p]"
+func RejectionCondition(rejection string) string {
+	condition, _, _ := strings.Cut(rejection, ". got:")
+
+	// "wanted: freeleech" and the like are conditions on their own
+	if before, _, ok := strings.Cut(condition, ":"); ok && before != "wanted" {
+		condition = before
+	}
+
+	condition, _, _ = strings.Cut(condition, " (")
+
+	return strings.TrimSpace(strings.TrimSuffix(condition, " not matching"))
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectionCondition(t *testing.T) {
+	tests := []struct {
+		rejection string
+		want      string
+	}{
+		{rejection: "resolution not matching. got: 1080p want: [2160p]", want: "resolution"},
+		{rejection: "size: larger than max size", want: "size"},
+		{rejection: "size not matching. got: 100 want min: 1GB max: ", want: "size"},
+		{rejection: "max downloads (5) this (DAY) reached", want: "max downloads"},
+		{rejection: "except releases regex: unwanted release. got: a want: b", want: "except releases regex"},
+		{rejection: "unwanted release group. got: GRP unwanted: GRP", want: "unwanted release group"},
+		{rejection: "wanted: freeleech", want: "wanted: freeleech"},
+		{rejection: "wanted: perfect flac. got: [Log]", want: "wanted: perfect flac"},
+		{rejection: "smart episode check: not new: (Show) season: 1 ep: 2", want: "smart episode check"},
+		{rejection: "outside schedule. got: Sat 12:00 want: mon-fri", want: "outside schedule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, RejectionCondition(tt.rejection))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// FilterBulkResult lists the filters changed by a bulk operation on a filter tag
+type FilterBulkResult struct {
+	Tag     string `json:"tag"`
+	Filters []int  `json:"filters"`
+}
+
+// FilterExport is a filter as exported by the web ui, without ids, indexers and actions
+type FilterExport struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Data    Filter `json:"data"`
+}
+
+// NewFilterExport strips the ids and relations that only exist in this instance from a filter
+func NewFilterExport(f Filter) FilterExport {
+	name := f.Name
+
+	f.ID = 0
+	f.Name = ""
+	f.GroupID = 0
+	f.ActionsCount = 0
+	f.Actions = nil
+	f.Indexers = nil
+	f.External = nil
+	f.ListSources = nil
+	f.Downloads = nil
+
+	return FilterExport{Name: name, Version: "1.0", Data: f}
+}
+
+// HasFilterTag returns true if the filter has any of the tags, tags are compared case-insensitive
+func (f Filter) HasFilterTag(tags ...string) bool {
+	for _, tag := range tags {
+		for _, t := range f.FilterTags {
+			if strings.EqualFold(t, strings.TrimSpace(tag)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NormalizeFilterTags trims the tags and removes empty and duplicate ones
+func NormalizeFilterTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		result = append(result, tag)
+	}
+
+	return result
+}
+
+// FilterTagsOf returns the sorted unique tags of the filters
+func FilterTagsOf(filters []Filter) []string {
+	var tags []string
+	for _, f := range filters {
+		tags = append(tags, f.FilterTags...)
+	}
+
+	tags = NormalizeFilterTags(tags)
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+
+	return tags
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeFilterTags(t *testing.T) {
+	assert.Equal(t, []string{"sports", "F1 racing"}, NormalizeFilterTags([]string{" sports", "", "F1 racing", "Sports "}))
+	assert.Equal(t, []string{}, NormalizeFilterTags(nil))
+}
+
+func TestFilter_HasFilterTag(t *testing.T) {
+	f := Filter{FilterTags: []string{"sports", "F1 racing"}}
+
+	assert.True(t, f.HasFilterTag("Sports"))
+	assert.True(t, f.HasFilterTag("tv", "f1 racing"))
+	assert.False(t, f.HasFilterTag("tv"))
+	assert.False(t, Filter{}.HasFilterTag("sports"))
+}
+
+func TestFilterTagsOf(t *testing.T) {
+	filters := []Filter{
+		{FilterTags: []string{"sports", "racing"}},
+		{FilterTags: []string{"Anime"}},
+		{FilterTags: []string{"Racing"}},
+		{},
+	}
+
+	assert.Equal(t, []string{"Anime", "racing", "sports"}, FilterTagsOf(filters))
+}
+
+func TestNewFilterExport(t *testing.T) {
+	export := NewFilterExport(Filter{ID: 4, Name: "F1", GroupID: 2, FilterTags: []string{"racing"}, Actions: []*Action{{Name: "qbit"}}, Indexers: []Indexer{{ID: 1}}})
+
+	assert.Equal(t, "F1", export.Name)
+	assert.Equal(t, "1.0", export.Version)
+	assert.Equal(t, Filter{FilterTags: []string{"racing"}}, export.Data)
+}
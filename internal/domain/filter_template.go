@@ -0,0 +1,159 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type FilterTemplateRepo interface {
+	List(ctx context.Context) ([]FilterTemplate, error)
+	FindByID(ctx context.Context, templateID int) (*FilterTemplate, error)
+	Store(ctx context.Context, template *FilterTemplate) error
+	Update(ctx context.Context, template *FilterTemplate) error
+	Delete(ctx context.Context, templateID int) error
+}
+
+// FilterTemplate is a filter with placeholders like {{ .ShowName }} in its text fields.
+// A concrete filter is created from the template and values for its variables.
+type FilterTemplate struct {
+	ID          int                      `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Variables   []FilterTemplateVariable `json:"variables"`
+	Filter      Filter                   `json:"filter"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+type FilterTemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// FilterTemplateInstance is the input to create a filter from a template
+type FilterTemplateInstance struct {
+	// Name overrides the rendered name of the template filter
+	Name      string            `json:"name,omitempty"`
+	Variables map[string]string `json:"variables"`
+}
+
+var (
+	templatePlaceholderRegexp  = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	templateVariableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validate checks the name and the variables of the template
+func (t *FilterTemplate) Validate() error {
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if t.Filter.Name == "" {
+		return errors.New("filter name is required")
+	}
+
+	seen := make(map[string]struct{}, len(t.Variables))
+	for _, v := range t.Variables {
+		if !templateVariableNameRegexp.MatchString(v.Name) {
+			return errors.New("invalid variable name: %q", v.Name)
+		}
+
+		if _, ok := seen[v.Name]; ok {
+			return errors.New("duplicate variable: %s", v.Name)
+		}
+
+		seen[v.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// Render returns the filter of the template with the placeholders of its variables replaced in all text fields.
+// Placeholders that are not template variables, like the release macros of actions, are kept as they are.
+func (t *FilterTemplate) Render(instance FilterTemplateInstance) (*Filter, error) {
+	values := make(map[string]string, len(t.Variables))
+	for _, v := range t.Variables {
+		value := instance.Variables[v.Name]
+		if value == "" {
+			value = v.Default
+		}
+
+		if value == "" && v.Required {
+			return nil, errors.New("variable %s is required", v.Name)
+		}
+
+		values[v.Name] = value
+	}
+
+	for name := range instance.Variables {
+		if _, ok := values[name]; !ok {
+			return nil, errors.New("unknown variable: %s", name)
+		}
+	}
+
+	data, err := json.Marshal(t.Filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal template filter")
+	}
+
+	// numbers are kept as json.Number so large integers survive the round trip
+	var tree any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, errors.Wrap(err, "could not decode template filter")
+	}
+
+	data, err = json.Marshal(renderTemplateValue(tree, values))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal rendered filter")
+	}
+
+	var filter Filter
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return nil, errors.Wrap(err, "could not decode rendered filter")
+	}
+
+	filter.ID = 0
+	if instance.Name != "" {
+		filter.Name = instance.Name
+	}
+
+	return &filter, nil
+}
+
+func renderTemplateValue(value any, values map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		return templatePlaceholderRegexp.ReplaceAllStringFunc(v, func(placeholder string) string {
+			name := templatePlaceholderRegexp.FindStringSubmatch(placeholder)[1]
+			if value, ok := values[name]; ok {
+				return value
+			}
+
+			return placeholder
+		})
+
+	case []any:
+		for i := range v {
+			v[i] = renderTemplateValue(v[i], values)
+		}
+
+	case map[string]any:
+		for k := range v {
+			v[k] = renderTemplateValue(v[k], values)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterTemplate_Render(t *testing.T) {
+	template := FilterTemplate{
+		Name: "TV show",
+		Variables: []FilterTemplateVariable{
+			{Name: "ShowName", Required: true},
+			{Name: "SavePath", Default: "/data/tv"},
+		},
+		Filter: Filter{
+			ID:           3,
+			Name:         "TV - {{ .ShowName }}",
+			Enabled:      true,
+			Shows:        "{{.ShowName}}",
+			Resolutions:  []string{"1080p"},
+			MaxDownloads: 2500000000,
+			Actions: []*Action{
+				{Name: "qbit", Type: ActionTypeQbittorrent, SavePath: "{{ .SavePath }}/{{ .ShowName }}/{{ .Indexer }}"},
+			},
+		},
+	}
+
+	filter, err := template.Render(FilterTemplateInstance{Variables: map[string]string{"ShowName": "Servant"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, filter.ID)
+	assert.Equal(t, "TV - Servant", filter.Name)
+	assert.True(t, filter.Enabled)
+	assert.Equal(t, "Servant", filter.Shows)
+	assert.Equal(t, []string{"1080p"}, filter.Resolutions)
+	assert.Equal(t, 2500000000, filter.MaxDownloads)
+	assert.Equal(t, "/data/tv/Servant/{{ .Indexer }}", filter.Actions[0].SavePath)
+
+	// the template is not changed
+	assert.Equal(t, "{{.ShowName}}", template.Filter.Shows)
+
+	filter, err = template.Render(FilterTemplateInstance{Name: "Servant", Variables: map[string]string{"ShowName": "Servant", "SavePath": "/tv"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Servant", filter.Name)
+	assert.Equal(t, "/tv/Servant/{{ .Indexer }}", filter.Actions[0].SavePath)
+
+	_, err = template.Render(FilterTemplateInstance{})
+	assert.EqualError(t, err, "variable ShowName is required")
+
+	_, err = template.Render(FilterTemplateInstance{Variables: map[string]string{"ShowName": "Servant", "Season": "1"}})
+	assert.EqualError(t, err, "unknown variable: Season")
+}
+
+func TestFilterTemplate_Validate(t *testing.T) {
+	assert.NoError(t, (&FilterTemplate{Name: "t", Filter: Filter{Name: "f"}, Variables: []FilterTemplateVariable{{Name: "ShowName"}}}).Validate())
+	assert.Error(t, (&FilterTemplate{Filter: Filter{Name: "f"}}).Validate())
+	assert.Error(t, (&FilterTemplate{Name: "t"}).Validate())
+	assert.Error(t, (&FilterTemplate{Name: "t", Filter: Filter{Name: "f"}, Variables: []FilterTemplateVariable{{Name: "Show Name"}}}).Validate())
+	assert.Error(t, (&FilterTemplate{Name: "t", Filter: Filter{Name: "f"}, Variables: []FilterTemplateVariable{{Name: "A"}, {Name: "A"}}}).Validate())
+}
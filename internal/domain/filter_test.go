@@ -5,6 +5,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -1727,7 +1728,7 @@ func TestFilter_CheckFilter1(t *testing.T) {
 				},
 			},
 			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
-			wantRejections: []string{"max downloads (10) this (MONTH) reached"},
+			wantRejections: []string{"max downloads (10) per (MONTH) reached"},
 			wantMatch:      false,
 		},
 		{
@@ -1740,7 +1741,7 @@ func TestFilter_CheckFilter1(t *testing.T) {
 				},
 			},
 			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
-			wantRejections: []string{"max downloads (10) this (MONTH) reached"},
+			wantRejections: []string{"max downloads (10) per (MONTH) reached"},
 			wantMatch:      false,
 		},
 		{
@@ -1754,7 +1755,7 @@ func TestFilter_CheckFilter1(t *testing.T) {
 				},
 			},
 			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
-			wantRejections: []string{"max downloads (15) this (HOUR) reached"},
+			wantRejections: []string{"max downloads (15) per (HOUR) reached"},
 			wantMatch:      false,
 		},
 		{
@@ -1771,6 +1772,34 @@ func TestFilter_CheckFilter1(t *testing.T) {
 			wantRejections: nil,
 			wantMatch:      true,
 		},
+		{
+			name: "test_36_interval",
+			fields: fields{
+				MaxDownloads:         5,
+				MaxDownloadsUnit:     FilterMaxDownloadsHour,
+				MaxDownloadsInterval: 6,
+				Downloads: &FilterDownloads{
+					HourCount: 5,
+				},
+			},
+			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
+			wantRejections: []string{"max downloads (5) per (6 HOUR) reached"},
+			wantMatch:      false,
+		},
+		{
+			name: "test_36_per_indexer",
+			fields: fields{
+				MaxDownloads:           5,
+				MaxDownloadsUnit:       FilterMaxDownloadsDay,
+				MaxDownloadsPerIndexer: true,
+				Downloads: &FilterDownloads{
+					DayCount: 5,
+				},
+			},
+			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2", Indexer: "mock"}},
+			wantRejections: []string{"max downloads (5) per (DAY) reached for indexer: mock"},
+			wantMatch:      false,
+		},
 		{
 			name: "test_37",
 			fields: fields{
@@ -1828,68 +1857,105 @@ func TestFilter_CheckFilter1(t *testing.T) {
 			wantRejections: []string{"match release tags regex not matching. got:  want: foreign - 17"},
 			wantMatch:      false,
 		},
+		{
+			name: "test_43",
+			fields: fields{
+				Expression: `Resolution == "2160p" && (Group in ["OTHER", "NOSiViD"] || Source == "BluRay")`,
+			},
+			args:      args{&Release{TorrentName: "WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD"}},
+			wantMatch: true,
+		},
+		{
+			name: "test_44",
+			fields: fields{
+				Expression: `Resolution == "1080p" || "x264" in Codec`,
+			},
+			args:           args{&Release{TorrentName: "WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD"}},
+			wantRejections: []string{"expression not matching. got: WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD want: Resolution == \"1080p\" || \"x264\" in Codec"},
+			wantMatch:      false,
+		},
+		{
+			name: "test_45",
+			fields: fields{
+				Expression: `Size < 40GB`,
+			},
+			args:           args{&Release{TorrentName: "WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD", Size: 50_000_000_000}},
+			wantRejections: []string{"expression not matching. got: WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD want: Size < 40GB"},
+			wantMatch:      false,
+		},
+		{
+			name: "test_46",
+			fields: fields{
+				Expression: `Size < 40GB`,
+			},
+			args:      args{&Release{TorrentName: "WeCrashed.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-NOSiViD"}},
+			wantMatch: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := Filter{
-				ID:                   tt.fields.ID,
-				Name:                 tt.fields.Name,
-				Enabled:              tt.fields.Enabled,
-				CreatedAt:            tt.fields.CreatedAt,
-				UpdatedAt:            tt.fields.UpdatedAt,
-				MinSize:              tt.fields.MinSize,
-				MaxSize:              tt.fields.MaxSize,
-				Delay:                tt.fields.Delay,
-				Priority:             tt.fields.Priority,
-				MaxDownloads:         tt.fields.MaxDownloads,
-				MaxDownloadsUnit:     tt.fields.MaxDownloadsUnit,
-				MatchReleases:        tt.fields.MatchReleases,
-				ExceptReleases:       tt.fields.ExceptReleases,
-				UseRegex:             tt.fields.UseRegex,
-				MatchReleaseGroups:   tt.fields.MatchReleaseGroups,
-				ExceptReleaseGroups:  tt.fields.ExceptReleaseGroups,
-				MatchReleaseTags:     tt.fields.MatchReleaseTags,
-				ExceptReleaseTags:    tt.fields.ExceptReleaseTags,
-				UseRegexReleaseTags:  tt.fields.UseRegexReleaseTags,
-				Scene:                tt.fields.Scene,
-				Origins:              tt.fields.Origins,
-				ExceptOrigins:        tt.fields.ExceptOrigins,
-				Freeleech:            tt.fields.Freeleech,
-				FreeleechPercent:     tt.fields.FreeleechPercent,
-				Shows:                tt.fields.Shows,
-				Seasons:              tt.fields.Seasons,
-				Episodes:             tt.fields.Episodes,
-				Resolutions:          tt.fields.Resolutions,
-				Codecs:               tt.fields.Codecs,
-				Sources:              tt.fields.Sources,
-				Containers:           tt.fields.Containers,
-				MatchHDR:             tt.fields.MatchHDR,
-				ExceptHDR:            tt.fields.ExceptHDR,
-				Years:                tt.fields.Years,
-				Artists:              tt.fields.Artists,
-				Albums:               tt.fields.Albums,
-				MatchReleaseTypes:    tt.fields.MatchReleaseTypes,
-				ExceptReleaseTypes:   tt.fields.ExceptReleaseTypes,
-				Formats:              tt.fields.Formats,
-				Quality:              tt.fields.Quality,
-				Media:                tt.fields.Media,
-				PerfectFlac:          tt.fields.PerfectFlac,
-				Cue:                  tt.fields.Cue,
-				Log:                  tt.fields.Log,
-				LogScore:             tt.fields.LogScore,
-				MatchOther:           tt.fields.MatchOther,
-				ExceptOther:          tt.fields.ExceptOther,
-				MatchCategories:      tt.fields.MatchCategories,
-				ExceptCategories:     tt.fields.ExceptCategories,
-				MatchUploaders:       tt.fields.MatchUploaders,
-				ExceptUploaders:      tt.fields.ExceptUploaders,
-				Tags:                 tt.fields.Tags,
-				ExceptTags:           tt.fields.ExceptTags,
-				TagsMatchLogic:       tt.fields.TagsMatchLogic,
-				ExceptTagsMatchLogic: tt.fields.ExceptTagsMatchLogic,
-				Actions:              tt.fields.Actions,
-				Indexers:             tt.fields.Indexers,
-				Downloads:            tt.fields.Downloads,
+				ID:                     tt.fields.ID,
+				Name:                   tt.fields.Name,
+				Enabled:                tt.fields.Enabled,
+				CreatedAt:              tt.fields.CreatedAt,
+				UpdatedAt:              tt.fields.UpdatedAt,
+				MinSize:                tt.fields.MinSize,
+				MaxSize:                tt.fields.MaxSize,
+				Delay:                  tt.fields.Delay,
+				Priority:               tt.fields.Priority,
+				MaxDownloads:           tt.fields.MaxDownloads,
+				MaxDownloadsUnit:       tt.fields.MaxDownloadsUnit,
+				MaxDownloadsInterval:   tt.fields.MaxDownloadsInterval,
+				MaxDownloadsPerIndexer: tt.fields.MaxDownloadsPerIndexer,
+				MatchReleases:          tt.fields.MatchReleases,
+				ExceptReleases:         tt.fields.ExceptReleases,
+				UseRegex:               tt.fields.UseRegex,
+				MatchReleaseGroups:     tt.fields.MatchReleaseGroups,
+				ExceptReleaseGroups:    tt.fields.ExceptReleaseGroups,
+				MatchReleaseTags:       tt.fields.MatchReleaseTags,
+				ExceptReleaseTags:      tt.fields.ExceptReleaseTags,
+				UseRegexReleaseTags:    tt.fields.UseRegexReleaseTags,
+				Scene:                  tt.fields.Scene,
+				Origins:                tt.fields.Origins,
+				ExceptOrigins:          tt.fields.ExceptOrigins,
+				Freeleech:              tt.fields.Freeleech,
+				FreeleechPercent:       tt.fields.FreeleechPercent,
+				Shows:                  tt.fields.Shows,
+				Seasons:                tt.fields.Seasons,
+				Episodes:               tt.fields.Episodes,
+				Resolutions:            tt.fields.Resolutions,
+				Codecs:                 tt.fields.Codecs,
+				Sources:                tt.fields.Sources,
+				Containers:             tt.fields.Containers,
+				MatchHDR:               tt.fields.MatchHDR,
+				ExceptHDR:              tt.fields.ExceptHDR,
+				Years:                  tt.fields.Years,
+				Artists:                tt.fields.Artists,
+				Albums:                 tt.fields.Albums,
+				MatchReleaseTypes:      tt.fields.MatchReleaseTypes,
+				ExceptReleaseTypes:     tt.fields.ExceptReleaseTypes,
+				Formats:                tt.fields.Formats,
+				Quality:                tt.fields.Quality,
+				Media:                  tt.fields.Media,
+				PerfectFlac:            tt.fields.PerfectFlac,
+				Cue:                    tt.fields.Cue,
+				Log:                    tt.fields.Log,
+				LogScore:               tt.fields.LogScore,
+				MatchOther:             tt.fields.MatchOther,
+				ExceptOther:            tt.fields.ExceptOther,
+				MatchCategories:        tt.fields.MatchCategories,
+				ExceptCategories:       tt.fields.ExceptCategories,
+				MatchUploaders:         tt.fields.MatchUploaders,
+				ExceptUploaders:        tt.fields.ExceptUploaders,
+				Tags:                   tt.fields.Tags,
+				ExceptTags:             tt.fields.ExceptTags,
+				TagsMatchLogic:         tt.fields.TagsMatchLogic,
+				ExceptTagsMatchLogic:   tt.fields.ExceptTagsMatchLogic,
+				Actions:                tt.fields.Actions,
+				Indexers:               tt.fields.Indexers,
+				Downloads:              tt.fields.Downloads,
+				Expression:             tt.fields.Expression,
 			}
 			tt.args.r.ParseString(tt.args.r.TorrentName)
 			rejections, match := f.CheckFilter(tt.args.r)
@@ -2114,3 +2180,115 @@ func Test_matchRegex(t *testing.T) {
 		})
 	}
 }
+
+func Test_expandExpressionSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "size", input: `Size < 40GB`, want: `Size < 40000000000`},
+		{name: "binary_size", input: `Size >= 1.5GiB && Size <= 2GiB`, want: `Size >= 1610612736 && Size <= 2147483648`},
+		{name: "plain_number", input: `Season > 1 && Year == 2023`, want: `Season > 1 && Year == 2023`},
+		{name: "string_literal", input: `TorrentName contains "40GB" && Group == 'x2'`, want: `TorrentName contains "40GB" && Group == 'x2'`},
+		{name: "escaped_quote", input: `Title == "a\"1GB" || Size < 1MB`, want: `Title == "a\"1GB" || Size < 1000000`},
+		{name: "identifier_digits", input: `"x265" in Codec && Size < 10GB`, want: `"x265" in Codec && Size < 10000000000`},
+		{name: "exponent", input: `Size < 4e10`, want: `Size < 4e10`},
+		{name: "invalid_size", input: `Size < 40XB`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandExpressionSizes(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateFilterExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid", input: `Resolution == "2160p" && Size < 40GB && (Group in ["A", "B"] || Source == "WEB-DL")`},
+		{name: "lists", input: `"HEVC" in Codec || any(HDR, # startsWith "DV")`},
+		{name: "case_insensitive", input: `lower(Group) == "flux" || Title matches "(?i)^that\\s"`},
+		{name: "unknown_field", input: `group == "flux"`, wantErr: true},
+		{name: "not_bool", input: `Resolution`, wantErr: true},
+		{name: "mismatched_types", input: `Group < 1`, wantErr: true},
+		{name: "list_compared_to_string", input: `Codec == "x265"`, wantErr: true},
+		{name: "invalid_size", input: `Size < 40XB`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFilterExpression(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_checkFreeleechPercent(t *testing.T) {
+	type args struct {
+		announcePercent int
+		filterPercent   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "test_1", args: args{announcePercent: 50, filterPercent: "50"}, want: true},
+		{name: "test_2", args: args{announcePercent: 50, filterPercent: "25,100"}, want: false},
+		{name: "test_3", args: args{announcePercent: 50, filterPercent: "25-75"}, want: true},
+		{name: "test_4", args: args{announcePercent: 100, filterPercent: "25-75,100%"}, want: true},
+		{name: "test_5", args: args{announcePercent: 50, filterPercent: ">=50%"}, want: true},
+		{name: "test_6", args: args{announcePercent: 50, filterPercent: ">50"}, want: false},
+		{name: "test_7", args: args{announcePercent: 25, filterPercent: "<= 25"}, want: true},
+		{name: "test_8", args: args{announcePercent: 25, filterPercent: "<25"}, want: false},
+		{name: "test_9", args: args{announcePercent: 75, filterPercent: "abc,>=75"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, checkFreeleechPercent(tt.args.announcePercent, tt.args.filterPercent), "checkFreeleechPercent(%v, %v)", tt.args.announcePercent, tt.args.filterPercent)
+		})
+	}
+}
+
+func TestValidateFreeleechPercent(t *testing.T) {
+	assert.NoError(t, ValidateFreeleechPercent(""))
+	assert.NoError(t, ValidateFreeleechPercent("25,50-75,>=90%,"))
+	assert.Error(t, ValidateFreeleechPercent("75-25"))
+	assert.Error(t, ValidateFreeleechPercent(">=150"))
+	assert.Error(t, ValidateFreeleechPercent("half"))
+}
+
+func TestFilter_DownloadsParams(t *testing.T) {
+	f := Filter{ID: 3, MaxDownloads: 5, MaxDownloadsUnit: FilterMaxDownloadsHour, MaxDownloadsInterval: 6}
+	r := &Release{Indexer: "mock"}
+
+	assert.Equal(t, FilterDownloadsParams{FilterID: 3, Interval: 6}, f.DownloadsParams(r))
+
+	f.MaxDownloadsPerIndexer = true
+	assert.Equal(t, FilterDownloadsParams{FilterID: 3, Indexer: "mock", Interval: 6}, f.DownloadsParams(r))
+}
+
+func TestFilterDownloadsParams_Window(t *testing.T) {
+	assert.Equal(t, time.Hour, FilterDownloadsParams{}.Window(FilterMaxDownloadsHour))
+	assert.Equal(t, 6*time.Hour, FilterDownloadsParams{Interval: 6}.Window(FilterMaxDownloadsHour))
+	assert.Equal(t, 48*time.Hour, FilterDownloadsParams{Interval: 2}.Window(FilterMaxDownloadsDay))
+	assert.Equal(t, 7*24*time.Hour, FilterDownloadsParams{}.Window(FilterMaxDownloadsWeek))
+	assert.Equal(t, 30*24*time.Hour, FilterDownloadsParams{}.Window(FilterMaxDownloadsMonth))
+	assert.Equal(t, time.Duration(0), FilterDownloadsParams{Interval: 6}.Window(FilterMaxDownloadsEver))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"path"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// CheckTorrentFiles checks the size, number of files and file extensions of the downloaded torrent of the release.
+// MatchFileExtensions wants at least one file with one of the extensions, ExceptFileExtensions rejects the torrent if
+// any file has one of them.
+func (f Filter) CheckTorrentFiles(r *Release) bool {
+	ok := true
+
+	if (f.MinSize != "" || f.MaxSize != "") && !f.checkSizeFilter(r, f.MinSize, f.MaxSize) {
+		r.addRejectionF("torrent size not matching. got: %v want min: %v max: %v", r.Size, f.MinSize, f.MaxSize)
+		ok = false
+	}
+
+	if f.MinFileCount > 0 && len(r.TorrentFiles) < f.MinFileCount {
+		r.addRejectionF("torrent file count too low. got: %d want min: %d", len(r.TorrentFiles), f.MinFileCount)
+		ok = false
+	}
+
+	if f.MaxFileCount > 0 && len(r.TorrentFiles) > f.MaxFileCount {
+		r.addRejectionF("torrent file count too high. got: %d want max: %d", len(r.TorrentFiles), f.MaxFileCount)
+		ok = false
+	}
+
+	if f.MatchFileExtensions != "" {
+		extensions := parseFileExtensions(f.MatchFileExtensions)
+
+		matched := false
+		for _, file := range r.TorrentFiles {
+			if _, found := extensions[fileExtension(file.Path)]; found {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			r.addRejectionF("torrent file extensions not matching. want: %v", f.MatchFileExtensions)
+			ok = false
+		}
+	}
+
+	if f.ExceptFileExtensions != "" {
+		extensions := parseFileExtensions(f.ExceptFileExtensions)
+
+		for _, file := range r.TorrentFiles {
+			if _, found := extensions[fileExtension(file.Path)]; found {
+				r.addRejectionF("torrent file extension unwanted. got: %v unwanted: %v", file.Path, f.ExceptFileExtensions)
+				ok = false
+				break
+			}
+		}
+	}
+
+	return ok
+}
+
+// ValidateFileCount checks the file count limits of a filter, zero means no limit
+func ValidateFileCount(minFileCount, maxFileCount int) error {
+	if minFileCount < 0 || maxFileCount < 0 {
+		return errors.New("file count can't be negative")
+	}
+
+	if maxFileCount > 0 && minFileCount > maxFileCount {
+		return errors.New("min file count %d is larger than max file count %d", minFileCount, maxFileCount)
+	}
+
+	return nil
+}
+
+// parseFileExtensions parses a comma separated list of extensions like "mkv, .mp4" into a lowercase set without dots
+func parseFileExtensions(list string) map[string]struct{} {
+	extensions := make(map[string]struct{})
+	for _, ext := range strings.Split(list, ",") {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext != "" {
+			extensions[ext] = struct{}{}
+		}
+	}
+
+	return extensions
+}
+
+func fileExtension(name string) string {
+	return strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+}
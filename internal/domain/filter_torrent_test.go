@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_CheckTorrentFiles(t *testing.T) {
+	files := []ReleaseTorrentFile{
+		{Path: "Show.S01.1080p.WEB-DL-GRP/Show.S01E01.1080p.WEB-DL-GRP.mkv", Size: 2000000000},
+		{Path: "Show.S01.1080p.WEB-DL-GRP/Show.S01E02.1080p.WEB-DL-GRP.mkv", Size: 2000000000},
+		{Path: "Show.S01.1080p.WEB-DL-GRP/Show.S01.1080p.WEB-DL-GRP.NFO", Size: 2000},
+	}
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		want       bool
+		rejections []string
+	}{
+		{
+			name:   "no limits",
+			filter: Filter{VerifyTorrent: true},
+			want:   true,
+		},
+		{
+			name:   "size and file count",
+			filter: Filter{VerifyTorrent: true, MinSize: "1GB", MaxSize: "10GB", MinFileCount: 2, MaxFileCount: 3},
+			want:   true,
+		},
+		{
+			name:       "too large",
+			filter:     Filter{VerifyTorrent: true, MaxSize: "2GB"},
+			rejections: []string{"size: larger than max size", "torrent size not matching. got: 4000002000 want min:  max: 2GB"},
+		},
+		{
+			name:       "file count",
+			filter:     Filter{VerifyTorrent: true, MinFileCount: 4},
+			rejections: []string{"torrent file count too low. got: 3 want min: 4"},
+		},
+		{
+			name:       "max file count",
+			filter:     Filter{VerifyTorrent: true, MaxFileCount: 1},
+			rejections: []string{"torrent file count too high. got: 3 want max: 1"},
+		},
+		{
+			name:   "match extension",
+			filter: Filter{VerifyTorrent: true, MatchFileExtensions: ".MKV, mp4"},
+			want:   true,
+		},
+		{
+			name:       "match extension missing",
+			filter:     Filter{VerifyTorrent: true, MatchFileExtensions: "mp4,avi"},
+			rejections: []string{"torrent file extensions not matching. want: mp4,avi"},
+		},
+		{
+			name:       "except extension",
+			filter:     Filter{VerifyTorrent: true, ExceptFileExtensions: "exe, nfo"},
+			rejections: []string{"torrent file extension unwanted. got: Show.S01.1080p.WEB-DL-GRP/Show.S01.1080p.WEB-DL-GRP.NFO unwanted: exe, nfo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Release{Size: 4000002000, TorrentFiles: files}
+			assert.Equal(t, tt.want, tt.filter.CheckTorrentFiles(r))
+			assert.Equal(t, tt.rejections, r.Rejections)
+		})
+	}
+}
+
+func TestFilter_CheckFilter_VerifyTorrentSize(t *testing.T) {
+	f := Filter{Enabled: true, MaxSize: "2GB"}
+
+	r := &Release{TorrentName: "Show.S01E01.1080p.WEB-DL-GRP", Size: 4000000000}
+	_, match := f.CheckFilter(r)
+	assert.False(t, match)
+
+	// the announced size is not used when the torrent is verified
+	f.VerifyTorrent = true
+	r = &Release{TorrentName: "Show.S01E01.1080p.WEB-DL-GRP", Size: 4000000000}
+	_, match = f.CheckFilter(r)
+	assert.True(t, match)
+}
+
+func TestValidateFileCount(t *testing.T) {
+	assert.NoError(t, ValidateFileCount(0, 0))
+	assert.NoError(t, ValidateFileCount(2, 0))
+	assert.NoError(t, ValidateFileCount(2, 2))
+	assert.Error(t, ValidateFileCount(3, 2))
+	assert.Error(t, ValidateFileCount(-1, 0))
+}
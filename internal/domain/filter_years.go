@@ -0,0 +1,133 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// yearRange is an inclusive range of years, zero means unbounded
+type yearRange struct {
+	min int
+	max int
+}
+
+func (y yearRange) contains(year int) bool {
+	return (y.min == 0 || year >= y.min) && (y.max == 0 || year <= y.max)
+}
+
+// parseYears parses a comma separated list of years, ranges like 1990-1999, comparisons like >=2020 and decades
+// like 1990s
+func parseYears(value string) ([]yearRange, error) {
+	var ranges []yearRange
+
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		r, err := parseYearRange(s)
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+func parseYearRange(s string) (yearRange, error) {
+	switch {
+	case strings.HasPrefix(s, ">="):
+		year, err := parseYear(s[2:])
+		return yearRange{min: year}, err
+
+	case strings.HasPrefix(s, "<="):
+		year, err := parseYear(s[2:])
+		return yearRange{max: year}, err
+
+	case strings.HasPrefix(s, ">"):
+		year, err := parseYear(s[1:])
+		return yearRange{min: year + 1}, err
+
+	case strings.HasPrefix(s, "<"):
+		year, err := parseYear(s[1:])
+		return yearRange{max: year - 1}, err
+
+	case strings.HasSuffix(strings.ToLower(s), "s"):
+		year, err := parseYear(s[:len(s)-1])
+		if err != nil {
+			return yearRange{}, err
+		}
+
+		if year%10 != 0 {
+			return yearRange{}, errors.New("invalid decade: %s", s)
+		}
+
+		return yearRange{min: year, max: year + 9}, nil
+
+	case strings.Contains(s, "-"):
+		minMax := strings.SplitN(s, "-", 2)
+
+		min, err := parseYear(minMax[0])
+		if err != nil {
+			return yearRange{}, err
+		}
+
+		max, err := parseYear(minMax[1])
+		if err != nil {
+			return yearRange{}, err
+		}
+
+		if min > max {
+			return yearRange{}, errors.New("invalid year range: %s", s)
+		}
+
+		return yearRange{min: min, max: max}, nil
+	}
+
+	year, err := parseYear(s)
+	return yearRange{min: year, max: year}, err
+}
+
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || year < 1 || year > 9999 {
+		return 0, errors.New("invalid year: %s", strings.TrimSpace(s))
+	}
+
+	return year, nil
+}
+
+// matchYears reports whether the year is in any of the years, ranges and decades of the filter.
+// Releases without a year never match.
+func matchYears(year int, filterYears string) bool {
+	if year == 0 {
+		return false
+	}
+
+	ranges, err := parseYears(filterYears)
+	if err != nil {
+		return false
+	}
+
+	for _, r := range ranges {
+		if r.contains(year) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateYears checks the years field of a filter
+func ValidateYears(years string) error {
+	_, err := parseYears(years)
+	return err
+}
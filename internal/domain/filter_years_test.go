@@ -0,0 +1,51 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchYears(t *testing.T) {
+	tests := []struct {
+		years string
+		year  int
+		want  bool
+	}{
+		{years: "2020", year: 2020, want: true},
+		{years: "2020", year: 2021, want: false},
+		{years: "2018, 2020", year: 2020, want: true},
+		{years: "1990-1999", year: 1995, want: true},
+		{years: "1990-1999", year: 2000, want: false},
+		{years: "1970-1979,1990-1999", year: 1995, want: true},
+		{years: "1970-1979, 2005", year: 2005, want: true},
+		{years: ">=2020", year: 2020, want: true},
+		{years: ">=2020", year: 2019, want: false},
+		{years: ">2020", year: 2020, want: false},
+		{years: "<=1999", year: 1999, want: true},
+		{years: "<1999", year: 1999, want: false},
+		{years: "<1980, >=2020", year: 2023, want: true},
+		{years: "1980s", year: 1989, want: true},
+		{years: "1980s", year: 1990, want: false},
+		{years: "1960s,1970s", year: 1975, want: true},
+		{years: ">=2020", year: 0, want: false},
+		{years: "20x0", year: 2020, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.years, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchYears(tt.year, tt.years))
+		})
+	}
+}
+
+func TestValidateYears(t *testing.T) {
+	assert.NoError(t, ValidateYears(""))
+	assert.NoError(t, ValidateYears("2018, 1990-1999, >=2020, 1980s"))
+	assert.Error(t, ValidateYears("1999-1990"))
+	assert.Error(t, ValidateYears("1985s"))
+	assert.Error(t, ValidateYears("=>2020"))
+	assert.Error(t, ValidateYears("nineties"))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// HDRFormat is the combined HDR format of a release, eg. Dolby Vision with an HDR10 fallback layer
+type HDRFormat string
+
+const (
+	HDRFormatDVHDR10Plus HDRFormat = "DV HDR10+"
+	HDRFormatDVHDR10     HDRFormat = "DV HDR10"
+	HDRFormatDVHDR       HDRFormat = "DV HDR"
+
+	// HDRFormatDV is Dolby Vision without a fallback layer, which displays with wrong colors without DV support
+	HDRFormatDV HDRFormat = "DV"
+
+	HDRFormatHDR10Plus HDRFormat = "HDR10+"
+	HDRFormatHDR10     HDRFormat = "HDR10"
+	HDRFormatHDR       HDRFormat = "HDR"
+	HDRFormatHLG       HDRFormat = "HLG"
+	HDRFormatSDR       HDRFormat = "SDR"
+)
+
+var hdrFormats = []HDRFormat{
+	HDRFormatDVHDR10Plus,
+	HDRFormatDVHDR10,
+	HDRFormatDVHDR,
+	HDRFormatDV,
+	HDRFormatHDR10Plus,
+	HDRFormatHDR10,
+	HDRFormatHDR,
+	HDRFormatHLG,
+	HDRFormatSDR,
+}
+
+// NewHDRFormat returns the format for the HDR tags parsed from a release name.
+// Releases without HDR tags are SDR.
+func NewHDRFormat(tags []string) HDRFormat {
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[strings.ToUpper(strings.TrimSpace(tag))] = true
+	}
+
+	fallback := HDRFormatSDR
+
+	switch {
+	case has["HDR10+"]:
+		fallback = HDRFormatHDR10Plus
+	case has["HDR10"]:
+		fallback = HDRFormatHDR10
+	case has["HDR"] || has["HDR+"]:
+		fallback = HDRFormatHDR
+	case has["HLG"]:
+		fallback = HDRFormatHLG
+	}
+
+	if !has["DV"] {
+		return fallback
+	}
+
+	switch fallback {
+	case HDRFormatHDR10Plus:
+		return HDRFormatDVHDR10Plus
+	case HDRFormatHDR10:
+		return HDRFormatDVHDR10
+	case HDRFormatHDR:
+		return HDRFormatDVHDR
+	}
+
+	return HDRFormatDV
+}
+
+// IsDVWithoutFallback reports if the format is Dolby Vision without an HDR fallback layer
+func (h HDRFormat) IsDVWithoutFallback() bool {
+	return h == HDRFormatDV
+}
+
+// HDRPreferenceRank returns the rank of the format in the preference, ordered from most to least preferred.
+// The most preferred format has the highest rank and formats not in the preference rank 0.
+func HDRPreferenceRank(preference []string, format HDRFormat) int {
+	for i, p := range preference {
+		if strings.EqualFold(strings.TrimSpace(p), string(format)) {
+			return len(preference) - i
+		}
+	}
+
+	return 0
+}
+
+// ValidateHDRPreference checks that the preference only holds known formats and no duplicates
+func ValidateHDRPreference(preference []string) error {
+	seen := make(map[HDRFormat]struct{}, len(preference))
+
+	for _, p := range preference {
+		format, ok := parseHDRFormat(p)
+		if !ok {
+			return errors.New("unknown hdr format: %s", p)
+		}
+
+		if _, ok := seen[format]; ok {
+			return errors.New("duplicate hdr format: %s", p)
+		}
+
+		seen[format] = struct{}{}
+	}
+
+	return nil
+}
+
+func parseHDRFormat(value string) (HDRFormat, bool) {
+	for _, format := range hdrFormats {
+		if strings.EqualFold(strings.TrimSpace(value), string(format)) {
+			return format, true
+		}
+	}
+
+	return "", false
+}
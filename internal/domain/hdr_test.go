@@ -0,0 +1,103 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHDRFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want HDRFormat
+	}{
+		{name: "sdr", tags: nil, want: HDRFormatSDR},
+		{name: "explicit sdr", tags: []string{"SDR"}, want: HDRFormatSDR},
+		{name: "hdr10", tags: []string{"HDR10"}, want: HDRFormatHDR10},
+		{name: "hdr10+", tags: []string{"HDR10+"}, want: HDRFormatHDR10Plus},
+		{name: "hdr", tags: []string{"HDR"}, want: HDRFormatHDR},
+		{name: "hlg", tags: []string{"HLG"}, want: HDRFormatHLG},
+		{name: "dv hdr10", tags: []string{"DV", "HDR10"}, want: HDRFormatDVHDR10},
+		{name: "dv hdr10+", tags: []string{"HDR10+", "DV"}, want: HDRFormatDVHDR10Plus},
+		{name: "dv hdr", tags: []string{"DV", "HDR"}, want: HDRFormatDVHDR},
+		{name: "dv without fallback", tags: []string{"DV"}, want: HDRFormatDV},
+		{name: "dv hlg has no hdr10 fallback", tags: []string{"DV", "HLG"}, want: HDRFormatDV},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewHDRFormat(tt.tags))
+		})
+	}
+}
+
+func TestHDRPreferenceRank(t *testing.T) {
+	preference := []string{"DV HDR10", "HDR10+", "hdr10", "SDR"}
+
+	assert.Equal(t, 4, HDRPreferenceRank(preference, HDRFormatDVHDR10))
+	assert.Equal(t, 3, HDRPreferenceRank(preference, HDRFormatHDR10Plus))
+	assert.Equal(t, 2, HDRPreferenceRank(preference, HDRFormatHDR10))
+	assert.Equal(t, 1, HDRPreferenceRank(preference, HDRFormatSDR))
+	assert.Equal(t, 0, HDRPreferenceRank(preference, HDRFormatDV))
+}
+
+func TestValidateHDRPreference(t *testing.T) {
+	assert.NoError(t, ValidateHDRPreference(nil))
+	assert.NoError(t, ValidateHDRPreference([]string{"DV HDR10", "HDR10+", "HDR10", "SDR"}))
+	assert.Error(t, ValidateHDRPreference([]string{"HDR12"}))
+	assert.Error(t, ValidateHDRPreference([]string{"HDR10", "hdr10"}))
+}
+
+func TestFilter_CheckFilter_HDRPreference(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         Filter
+		release        string
+		wantRejections []string
+		wantMatch      bool
+	}{
+		{
+			name:      "preferred",
+			filter:    Filter{HDRPreference: []string{"DV HDR10", "HDR10+", "HDR10", "SDR"}},
+			release:   "Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HDR10.HEVC-FLUX",
+			wantMatch: true,
+		},
+		{
+			name:      "sdr preferred",
+			filter:    Filter{HDRPreference: []string{"DV HDR10", "HDR10+", "HDR10", "SDR"}},
+			release:   "Servant.S01E01.1080p.ATVP.WEB-DL.DDP.5.1.H.264-FLUX",
+			wantMatch: true,
+		},
+		{
+			name:           "not preferred",
+			filter:         Filter{HDRPreference: []string{"DV HDR10", "HDR10+", "HDR10", "SDR"}},
+			release:        "Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX",
+			wantRejections: []string{"hdr format not preferred. got: DV want: [DV HDR10 HDR10+ HDR10 SDR]"},
+		},
+		{
+			name:           "dv without fallback",
+			filter:         Filter{RejectDVWithoutFallback: true},
+			release:        "Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX",
+			wantRejections: []string{"hdr unwanted. got: DV without fallback"},
+		},
+		{
+			name:      "dv with fallback",
+			filter:    Filter{RejectDVWithoutFallback: true},
+			release:   "Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HDR10.HEVC-FLUX",
+			wantMatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.release)
+
+			rejections, match := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.wantRejections, rejections)
+			assert.Equal(t, tt.wantMatch, match)
+		})
+	}
+}
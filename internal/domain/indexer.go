@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"net/url"
+	"strings"
 	"text/template"
 
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -48,12 +49,15 @@ type IndexerDefinition struct {
 	Protocol       string            `json:"protocol"`
 	URLS           []string          `json:"urls"`
 	Supports       []string          `json:"supports"`
+	Anime          bool              `json:"anime,omitempty"` // anime tracker, releases are parsed with ParseAnime
 	Settings       []IndexerSetting  `json:"settings,omitempty"`
 	SettingsMap    map[string]string `json:"-"`
 	IRC            *IndexerIRC       `json:"irc,omitempty"`
 	Torznab        *Torznab          `json:"torznab,omitempty"`
 	Newznab        *Newznab          `json:"newznab,omitempty"`
 	RSS            *FeedSettings     `json:"rss,omitempty"`
+	JSON           *FeedSettings     `json:"json,omitempty"`
+	Push           *FeedSettings     `json:"push,omitempty"`
 }
 
 type IndexerImplementation string
@@ -63,6 +67,8 @@ const (
 	IndexerImplementationTorznab IndexerImplementation = "torznab"
 	IndexerImplementationNewznab IndexerImplementation = "newznab"
 	IndexerImplementationRSS     IndexerImplementation = "rss"
+	IndexerImplementationJSON    IndexerImplementation = "json"
+	IndexerImplementationPush    IndexerImplementation = "push"
 	IndexerImplementationLegacy  IndexerImplementation = ""
 )
 
@@ -76,6 +82,10 @@ func (i IndexerImplementation) String() string {
 		return "newznab"
 	case IndexerImplementationRSS:
 		return "rss"
+	case IndexerImplementationJSON:
+		return "json"
+	case IndexerImplementationPush:
+		return "push"
 	case IndexerImplementationLegacy:
 		return ""
 	}
@@ -105,12 +115,15 @@ type IndexerDefinitionCustom struct {
 	Protocol       string            `json:"protocol"`
 	URLS           []string          `json:"urls"`
 	Supports       []string          `json:"supports"`
+	Anime          bool              `json:"anime,omitempty"` // anime tracker, releases are parsed with ParseAnime
 	Settings       []IndexerSetting  `json:"settings,omitempty"`
 	SettingsMap    map[string]string `json:"-"`
 	IRC            *IndexerIRC       `json:"irc,omitempty"`
 	Torznab        *Torznab          `json:"torznab,omitempty"`
 	Newznab        *Newznab          `json:"newznab,omitempty"`
 	RSS            *FeedSettings     `json:"rss,omitempty"`
+	JSON           *FeedSettings     `json:"json,omitempty"`
+	Push           *FeedSettings     `json:"push,omitempty"`
 	Parse          *IndexerIRCParse  `json:"parse,omitempty"`
 }
 
@@ -128,12 +141,15 @@ func (i *IndexerDefinitionCustom) ToIndexerDefinition() *IndexerDefinition {
 		Protocol:       i.Protocol,
 		URLS:           i.URLS,
 		Supports:       i.Supports,
+		Anime:          i.Anime,
 		Settings:       i.Settings,
 		SettingsMap:    i.SettingsMap,
 		IRC:            i.IRC,
 		Torznab:        i.Torznab,
 		Newznab:        i.Newznab,
 		RSS:            i.RSS,
+		JSON:           i.JSON,
+		Push:           i.Push,
 	}
 
 	if i.IRC != nil && i.Parse != nil {
@@ -201,10 +217,26 @@ func (i IndexerIRC) ValidChannel(channel string) bool {
 }
 
 type IndexerIRCParse struct {
-	Type          string                `json:"type"`
-	ForceSizeUnit string                `json:"forcesizeunit"`
-	Lines         []IndexerIRCParseLine `json:"lines"`
-	Match         IndexerIRCParseMatch  `json:"match"`
+	Type          string                       `json:"type"`
+	ForceSizeUnit string                       `json:"forcesizeunit"`
+	Lines         []IndexerIRCParseLine        `json:"lines"`
+	Assemble      *IndexerIRCParseAssemble     `json:"assemble,omitempty"`
+	Match         IndexerIRCParseMatch         `json:"match"`
+	Mappings      map[string]map[string]string `json:"mappings"`
+}
+
+// IndexerIRCParseAssemble joins announces that span a varying number of lines into one line before parsing.
+// An announce starts with a line matching start and collects the following lines matching continue, or any line
+// when continue is empty. It is complete on a line matching end, after maxlines lines or, without an end pattern,
+// on the first line that does not continue it. Every parse line is then matched against the assembled announce.
+type IndexerIRCParseAssemble struct {
+	Start     string `json:"start"`
+	Continue  string `json:"continue"`
+	End       string `json:"end"`
+	Separator string `json:"separator"`
+	MaxLines  int    `json:"maxlines"`
+	// Timeout is how many seconds an announce waits for its next line
+	Timeout int `json:"timeout"`
 }
 
 type IndexerIRCParseLine struct {
@@ -227,6 +259,24 @@ type IndexerIRCParseMatched struct {
 	TorrentName string
 }
 
+// MapValues replaces announced values with the values of the mappings for their var, eg. a scene flag of "Ja" with "yes".
+// Values are matched case-insensitive and values without a mapping are kept.
+func (p *IndexerIRCParse) MapValues(vars map[string]string) {
+	for name, mapping := range p.Mappings {
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+
+		for from, to := range mapping {
+			if strings.EqualFold(strings.TrimSpace(value), from) {
+				vars[name] = to
+				break
+			}
+		}
+	}
+}
+
 func (p *IndexerIRCParse) ParseMatch(baseURL string, vars map[string]string) (*IndexerIRCParseMatched, error) {
 	matched := &IndexerIRCParseMatched{}
 
@@ -341,6 +391,8 @@ type TorrentBasic struct {
 	InfoHash  string `json:"InfoHash"`
 	Size      string `json:"Size"`
 	Uploader  string `json:"Uploader"`
+	Seeders   string `json:"Seeders,omitempty"`
+	Leechers  string `json:"Leechers,omitempty"`
 }
 
 func (t TorrentBasic) ReleaseSizeBytes() uint64 {
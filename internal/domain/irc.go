@@ -16,14 +16,16 @@ type IrcChannel struct {
 	Password   string `json:"password"`
 	Detached   bool   `json:"detached"`
 	Monitoring bool   `json:"monitoring"`
+	Announcers string `json:"announcers"`
 }
 
 type IRCAuthMechanism string
 
 const (
-	IRCAuthMechanismNone      IRCAuthMechanism = "NONE"
-	IRCAuthMechanismSASLPlain IRCAuthMechanism = "SASL_PLAIN"
-	IRCAuthMechanismNickServ  IRCAuthMechanism = "NICKSERV"
+	IRCAuthMechanismNone         IRCAuthMechanism = "NONE"
+	IRCAuthMechanismSASLPlain    IRCAuthMechanism = "SASL_PLAIN"
+	IRCAuthMechanismNickServ     IRCAuthMechanism = "NICKSERV"
+	IRCAuthMechanismSASLExternal IRCAuthMechanism = "SASL_EXTERNAL"
 )
 
 type IRCAuth struct {
@@ -33,43 +35,89 @@ type IRCAuth struct {
 }
 
 type IrcNetwork struct {
-	ID             int64        `json:"id"`
-	Name           string       `json:"name"`
-	Enabled        bool         `json:"enabled"`
-	Server         string       `json:"server"`
-	Port           int          `json:"port"`
-	TLS            bool         `json:"tls"`
-	Pass           string       `json:"pass"`
-	Nick           string       `json:"nick"`
-	Auth           IRCAuth      `json:"auth,omitempty"`
-	InviteCommand  string       `json:"invite_command"`
-	UseBouncer     bool         `json:"use_bouncer"`
-	BouncerAddr    string       `json:"bouncer_addr"`
-	Channels       []IrcChannel `json:"channels"`
-	Connected      bool         `json:"connected"`
-	ConnectedSince *time.Time   `json:"connected_since"`
+	ID                     int64        `json:"id"`
+	Name                   string       `json:"name"`
+	Enabled                bool         `json:"enabled"`
+	Server                 string       `json:"server"`
+	Port                   int          `json:"port"`
+	TLS                    bool         `json:"tls"`
+	Pass                   string       `json:"pass"`
+	Nick                   string       `json:"nick"`
+	Auth                   IRCAuth      `json:"auth,omitempty"`
+	InviteCommand          string       `json:"invite_command"`
+	UseBouncer             bool         `json:"use_bouncer"`
+	BouncerAddr            string       `json:"bouncer_addr"`
+	TLSCert                string       `json:"tls_cert"`
+	TLSKey                 string       `json:"tls_key"`
+	TLSKeySet              bool         `json:"tls_key_set"`
+	Proxy                  string       `json:"proxy"`
+	ReconnectDelay         int          `json:"reconnect_delay"`
+	ReconnectMaxDelay      int          `json:"reconnect_max_delay"`
+	ReconnectJitter        int          `json:"reconnect_jitter"`
+	ReconnectAlertAttempts int          `json:"reconnect_alert_attempts"`
+	AltServers             string       `json:"alt_servers"`
+	SendBurst              int          `json:"send_burst"`
+	SendDelay              int          `json:"send_delay"`
+	TLSMinVersion          string       `json:"tls_min_version"`
+	TLSCA                  string       `json:"tls_ca"`
+	TLSSkipVerify          bool         `json:"tls_skip_verify"`
+	ShareConnection        bool         `json:"share_connection"`
+	OfflineSchedule        string       `json:"offline_schedule"`
+	Ident                  string       `json:"ident"`
+	RealName               string       `json:"realname"`
+	PerformCommands        string       `json:"perform_commands"`
+	SplitQuarantine        int          `json:"split_quarantine"`
+	SplitAction            string       `json:"split_action"`
+	Encoding               string       `json:"encoding"`
+	Channels               []IrcChannel `json:"channels"`
+	Connected              bool         `json:"connected"`
+	ConnectedSince         *time.Time   `json:"connected_since"`
 }
 
 type IrcNetworkWithHealth struct {
-	ID               int64               `json:"id"`
-	Name             string              `json:"name"`
-	Enabled          bool                `json:"enabled"`
-	Server           string              `json:"server"`
-	Port             int                 `json:"port"`
-	TLS              bool                `json:"tls"`
-	Pass             string              `json:"pass"`
-	Nick             string              `json:"nick"`
-	Auth             IRCAuth             `json:"auth,omitempty"`
-	InviteCommand    string              `json:"invite_command"`
-	UseBouncer       bool                `json:"use_bouncer"`
-	BouncerAddr      string              `json:"bouncer_addr"`
-	CurrentNick      string              `json:"current_nick"`
-	PreferredNick    string              `json:"preferred_nick"`
-	Channels         []ChannelWithHealth `json:"channels"`
-	Connected        bool                `json:"connected"`
-	ConnectedSince   time.Time           `json:"connected_since"`
-	ConnectionErrors []string            `json:"connection_errors"`
-	Healthy          bool                `json:"healthy"`
+	ID                     int64               `json:"id"`
+	Name                   string              `json:"name"`
+	Enabled                bool                `json:"enabled"`
+	Server                 string              `json:"server"`
+	Port                   int                 `json:"port"`
+	TLS                    bool                `json:"tls"`
+	Pass                   string              `json:"pass"`
+	Nick                   string              `json:"nick"`
+	Auth                   IRCAuth             `json:"auth,omitempty"`
+	InviteCommand          string              `json:"invite_command"`
+	UseBouncer             bool                `json:"use_bouncer"`
+	BouncerAddr            string              `json:"bouncer_addr"`
+	TLSCert                string              `json:"tls_cert"`
+	TLSKeySet              bool                `json:"tls_key_set"`
+	Proxy                  string              `json:"proxy"`
+	ReconnectDelay         int                 `json:"reconnect_delay"`
+	ReconnectMaxDelay      int                 `json:"reconnect_max_delay"`
+	ReconnectJitter        int                 `json:"reconnect_jitter"`
+	ReconnectAlertAttempts int                 `json:"reconnect_alert_attempts"`
+	AltServers             string              `json:"alt_servers"`
+	SendBurst              int                 `json:"send_burst"`
+	SendDelay              int                 `json:"send_delay"`
+	TLSMinVersion          string              `json:"tls_min_version"`
+	TLSCA                  string              `json:"tls_ca"`
+	TLSSkipVerify          bool                `json:"tls_skip_verify"`
+	ShareConnection        bool                `json:"share_connection"`
+	OfflineSchedule        string              `json:"offline_schedule"`
+	Ident                  string              `json:"ident"`
+	RealName               string              `json:"realname"`
+	PerformCommands        string              `json:"perform_commands"`
+	SplitQuarantine        int                 `json:"split_quarantine"`
+	SplitAction            string              `json:"split_action"`
+	Encoding               string              `json:"encoding"`
+	CurrentNick            string              `json:"current_nick"`
+	PreferredNick          string              `json:"preferred_nick"`
+	Channels               []ChannelWithHealth `json:"channels"`
+	Connected              bool                `json:"connected"`
+	ConnectedSince         time.Time           `json:"connected_since"`
+	ConnectionErrors       []string            `json:"connection_errors"`
+	Healthy                bool                `json:"healthy"`
+	LagMs                  int64               `json:"lag_ms"`
+	ScheduledOffline       bool                `json:"scheduled_offline"`
+	QuarantinedAnnounces   int                 `json:"quarantined_announces"`
 }
 
 type ChannelWithHealth struct {
@@ -78,9 +126,13 @@ type ChannelWithHealth struct {
 	Name            string    `json:"name"`
 	Password        string    `json:"password"`
 	Detached        bool      `json:"detached"`
+	Announcers      string    `json:"announcers"`
 	Monitoring      bool      `json:"monitoring"`
 	MonitoringSince time.Time `json:"monitoring_since"`
 	LastAnnounce    time.Time `json:"last_announce"`
+	// AnnouncesLastHour and AnnouncesTotal tell a quiet tracker apart from missed announces
+	AnnouncesLastHour int    `json:"announces_last_hour"`
+	AnnouncesTotal    uint64 `json:"announces_total"`
 }
 
 type ChannelHealth struct {
@@ -98,6 +150,36 @@ type SendIrcCmdRequest struct {
 	Message   string `json:"msg"`
 }
 
+// SendIrcRawCmdRequest holds a raw command like "PRIVMSG NickServ :HELP" to send to a network
+type SendIrcRawCmdRequest struct {
+	NetworkId int64  `json:"network_id"`
+	Command   string `json:"command"`
+}
+
+// AnnounceTestRequest holds raw announce lines of an indexer to parse without processing the release
+type AnnounceTestRequest struct {
+	Indexer string   `json:"indexer"`
+	Lines   []string `json:"lines"`
+}
+
+// AnnounceTestResult is the outcome of parsing announce lines with the indexer definition and checking the
+// parsed release against the filters of the indexer in dry-run mode
+type AnnounceTestResult struct {
+	Indexer string               `json:"indexer"`
+	Matched bool                 `json:"matched"`
+	Lines   []AnnounceTestLine   `json:"lines"`
+	Vars    map[string]string    `json:"vars"`
+	Release *Release             `json:"release,omitempty"`
+	Results []FilterDryRunResult `json:"results"`
+}
+
+type AnnounceTestLine struct {
+	Line    string `json:"line"`
+	Pattern string `json:"pattern"`
+	Matched bool   `json:"matched"`
+	Error   string `json:"error,omitempty"`
+}
+
 type IrcMessage struct {
 	Channel string    `json:"channel"`
 	Nick    string    `json:"nick"`
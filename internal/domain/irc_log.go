@@ -0,0 +1,107 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type IrcLogRepo interface {
+	Store(ctx context.Context, lines []IrcLogLine) error
+	Find(ctx context.Context, params IrcLogQueryParams) ([]IrcLogLine, int64, error)
+	DeleteOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+// IrcLogLine is a line sent to a channel of a network, stored so announces can be looked up after a restart
+type IrcLogLine struct {
+	ID        int64     `json:"id"`
+	NetworkID int64     `json:"network_id"`
+	Channel   string    `json:"channel"`
+	Nick      string    `json:"nick"`
+	Message   string    `json:"msg"`
+	Timestamp time.Time `json:"time"`
+}
+
+// IrcLogQueryParams selects the logged lines of a network, newest first
+type IrcLogQueryParams struct {
+	NetworkID int64
+	Channel   string
+	Nick      string
+	Search    string
+	// Before and After limit the lines to a time range, ignored when zero
+	Before time.Time
+	After  time.Time
+	Limit  uint64
+	Offset uint64
+}
+
+type IrcLogResponse struct {
+	Data  []IrcLogLine `json:"data"`
+	Count int64        `json:"count"`
+}
+
+// IrcReplayRequest parses the last logged lines of a channel again, to recover announces missed while disconnected
+type IrcReplayRequest struct {
+	NetworkID int64  `json:"network_id"`
+	Channel   string `json:"channel"`
+	Lines     int    `json:"lines"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+type IrcReplayResult struct {
+	Channel   string              `json:"channel"`
+	Indexer   string              `json:"indexer"`
+	DryRun    bool                `json:"dry_run"`
+	Lines     int                 `json:"lines"`
+	Announces []IrcReplayAnnounce `json:"announces"`
+}
+
+type IrcReplayAnnounce struct {
+	Time      time.Time            `json:"time"`
+	Lines     []string             `json:"lines"`
+	Release   *Release             `json:"release"`
+	Results   []FilterDryRunResult `json:"results,omitempty"`
+	Processed bool                 `json:"processed"`
+}
+
+// IrcInjectRequest holds fabricated announce lines to handle in a channel of a running network as if the announcer
+// sent them, to test a setup end to end
+type IrcInjectRequest struct {
+	NetworkID int64    `json:"network_id"`
+	Channel   string   `json:"channel"`
+	Nick      string   `json:"nick"`
+	Lines     []string `json:"lines"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+func (r IrcInjectRequest) Validate() error {
+	if r.Channel == "" {
+		return errors.New("channel required")
+	}
+
+	if len(r.Lines) == 0 {
+		return errors.New("lines required")
+	}
+
+	for _, line := range r.Lines {
+		if strings.ContainsAny(line, "\r\n") {
+			return errors.New("lines can not contain line breaks")
+		}
+	}
+
+	return nil
+}
+
+type IrcInjectResult struct {
+	Channel   string              `json:"channel"`
+	Indexer   string              `json:"indexer"`
+	Nick      string              `json:"nick"`
+	DryRun    bool                `json:"dry_run"`
+	Queued    int                 `json:"queued"`
+	Announces []IrcReplayAnnounce `json:"announces"`
+}
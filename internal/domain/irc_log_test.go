@@ -0,0 +1,29 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIrcInjectRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     IrcInjectRequest
+		wantErr bool
+	}{
+		{name: "valid", req: IrcInjectRequest{NetworkID: 1, Channel: "#announce", Lines: []string{"New Torrent: That.Show.S01E01.1080p.WEB.h264-GRP"}}, wantErr: false},
+		{name: "missing_channel", req: IrcInjectRequest{NetworkID: 1, Lines: []string{"New Torrent: That.Show.S01E01.1080p.WEB.h264-GRP"}}, wantErr: true},
+		{name: "missing_lines", req: IrcInjectRequest{NetworkID: 1, Channel: "#announce"}, wantErr: true},
+		{name: "line_break", req: IrcInjectRequest{NetworkID: 1, Channel: "#announce", Lines: []string{"New Torrent: x\r\nQUIT"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
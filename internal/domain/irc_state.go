@@ -0,0 +1,89 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type IrcState string
+
+const (
+	IrcStateConnected    IrcState = "CONNECTED"
+	IrcStateReconnected  IrcState = "RECONNECTED"
+	IrcStateDisconnected IrcState = "DISCONNECTED"
+	IrcStateAuthFailed   IrcState = "AUTH_FAILED"
+	IrcStateKicked       IrcState = "KICKED"
+	IrcStateBanned       IrcState = "BANNED"
+)
+
+// IrcStateEvent is published on the event bus as "irc:state" when the connection of a network or the membership of
+// one of its channels changes. Channel is only set for kicks and channel bans.
+type IrcStateEvent struct {
+	NetworkID int64     `json:"network_id"`
+	Network   string    `json:"network"`
+	Server    string    `json:"server"`
+	State     IrcState  `json:"state"`
+	Channel   string    `json:"channel,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NotificationEvent returns the notification event users subscribe to for the state
+func (e IrcStateEvent) NotificationEvent() NotificationEvent {
+	switch e.State {
+	case IrcStateConnected:
+		return NotificationEventIRCConnected
+	case IrcStateReconnected:
+		return NotificationEventIRCReconnected
+	case IrcStateDisconnected:
+		return NotificationEventIRCDisconnected
+	case IrcStateAuthFailed:
+		return NotificationEventIRCAuthFailed
+	case IrcStateKicked:
+		return NotificationEventIRCKicked
+	case IrcStateBanned:
+		return NotificationEventIRCBanned
+	}
+
+	return ""
+}
+
+// NotificationPayload returns the notification of the state change
+func (e IrcStateEvent) NotificationPayload() NotificationPayload {
+	subject := ""
+	switch e.State {
+	case IrcStateConnected:
+		subject = "IRC Connected"
+	case IrcStateReconnected:
+		subject = "IRC Reconnected"
+	case IrcStateDisconnected:
+		subject = "IRC Disconnected unexpectedly"
+	case IrcStateAuthFailed:
+		subject = "IRC Authentication failed"
+	case IrcStateKicked:
+		subject = "IRC Kicked from channel"
+	case IrcStateBanned:
+		subject = "IRC Banned"
+	}
+
+	message := fmt.Sprintf("Network: %s", e.Network)
+	if e.Channel != "" {
+		message += fmt.Sprintf("\nChannel: %s", e.Channel)
+	}
+	if e.Reason != "" {
+		message += fmt.Sprintf("\nReason: %s", e.Reason)
+	}
+
+	return NotificationPayload{
+		Subject:   subject,
+		Message:   message,
+		Event:     e.NotificationEvent(),
+		Network:   e.Network,
+		Channel:   e.Channel,
+		Reason:    e.Reason,
+		Timestamp: e.Timestamp,
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIrcStateEvent_NotificationPayload(t *testing.T) {
+	now := time.Now()
+
+	event := IrcStateEvent{
+		NetworkID: 1,
+		Network:   "P2P-Network",
+		Server:    "irc.p2p-network.net",
+		State:     IrcStateKicked,
+		Channel:   "#announce",
+		Reason:    "idle",
+		Timestamp: now,
+	}
+
+	payload := event.NotificationPayload()
+
+	assert.Equal(t, NotificationEventIRCKicked, payload.Event)
+	assert.Equal(t, "IRC Kicked from channel", payload.Subject)
+	assert.Equal(t, "Network: P2P-Network\nChannel: #announce\nReason: idle", payload.Message)
+	assert.Equal(t, "P2P-Network", payload.Network)
+	assert.Equal(t, "#announce", payload.Channel)
+	assert.Equal(t, "idle", payload.Reason)
+	assert.Equal(t, now, payload.Timestamp)
+
+	disconnected := IrcStateEvent{Network: "P2P-Network", State: IrcStateDisconnected}
+	assert.Equal(t, NotificationEventIRCDisconnected, disconnected.NotificationPayload().Event)
+	assert.Equal(t, "Network: P2P-Network", disconnected.NotificationPayload().Message)
+}
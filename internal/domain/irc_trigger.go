@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type IrcTriggerRepo interface {
+	List(ctx context.Context, networkID int64) ([]IrcTrigger, error)
+	FindByID(ctx context.Context, id int64) (*IrcTrigger, error)
+	Store(ctx context.Context, trigger *IrcTrigger) error
+	Update(ctx context.Context, trigger *IrcTrigger) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type IrcTriggerAction string
+
+const (
+	// IrcTriggerActionNotify sends an IRC_TRIGGER notification with the matched line
+	IrcTriggerActionNotify IrcTriggerAction = "NOTIFY"
+	// IrcTriggerActionSetFlag sets a flag that filters can require or reject, like a sitewide freeleech
+	IrcTriggerActionSetFlag IrcTriggerAction = "SET_FLAG"
+	// IrcTriggerActionClearFlag clears a flag, like when the sitewide freeleech has ended
+	IrcTriggerActionClearFlag IrcTriggerAction = "CLEAR_FLAG"
+)
+
+// IrcTrigger reacts to lines matching Pattern in a channel that does not need to be an announce channel,
+// like a staff channel announcing a sitewide freeleech
+type IrcTrigger struct {
+	ID        int64            `json:"id"`
+	NetworkID int64            `json:"network_id"`
+	Name      string           `json:"name"`
+	Enabled   bool             `json:"enabled"`
+	Channel   string           `json:"channel"`
+	Pattern   string           `json:"pattern"`
+	Action    IrcTriggerAction `json:"action"`
+	Flag      string           `json:"flag"`
+	// FlagDuration is how many minutes a set flag stays active, 0 keeps it until it is cleared
+	FlagDuration int       `json:"flag_duration"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	re *regexp.Regexp
+}
+
+// Validate checks the trigger and compiles its pattern
+func (t *IrcTrigger) Validate() error {
+	if t.Name == "" {
+		return errors.New("name required")
+	}
+
+	if t.Channel == "" {
+		return errors.New("channel required")
+	}
+
+	re, err := regexp.Compile("(?i)" + t.Pattern)
+	if err != nil || t.Pattern == "" {
+		return errors.New("invalid pattern: %s", t.Pattern)
+	}
+
+	switch t.Action {
+	case IrcTriggerActionNotify:
+	case IrcTriggerActionSetFlag, IrcTriggerActionClearFlag:
+		if t.Flag == "" {
+			return errors.New("flag required for action %s", t.Action)
+		}
+	default:
+		return errors.New("unsupported action: %s", t.Action)
+	}
+
+	if t.FlagDuration < 0 {
+		return errors.New("flag duration can not be negative")
+	}
+
+	t.re = re
+
+	return nil
+}
+
+// Match reports whether the line matches the pattern of the trigger, case-insensitive
+func (t *IrcTrigger) Match(line string) bool {
+	if t.re == nil {
+		if err := t.Validate(); err != nil {
+			return false
+		}
+	}
+
+	return t.re.MatchString(line)
+}
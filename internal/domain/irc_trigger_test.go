@@ -0,0 +1,38 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIrcTrigger_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger IrcTrigger
+		wantErr bool
+	}{
+		{name: "notify", trigger: IrcTrigger{Name: "fl", Channel: "#staff", Pattern: "freeleech", Action: IrcTriggerActionNotify}, wantErr: false},
+		{name: "set_flag", trigger: IrcTrigger{Name: "fl", Channel: "#staff", Pattern: "freeleech", Action: IrcTriggerActionSetFlag, Flag: "sitewide-fl", FlagDuration: 60}, wantErr: false},
+		{name: "missing_flag", trigger: IrcTrigger{Name: "fl", Channel: "#staff", Pattern: "freeleech", Action: IrcTriggerActionSetFlag}, wantErr: true},
+		{name: "invalid_pattern", trigger: IrcTrigger{Name: "fl", Channel: "#staff", Pattern: "free(leech", Action: IrcTriggerActionNotify}, wantErr: true},
+		{name: "invalid_action", trigger: IrcTrigger{Name: "fl", Channel: "#staff", Pattern: "freeleech", Action: "GRAB"}, wantErr: true},
+		{name: "missing_channel", trigger: IrcTrigger{Name: "fl", Pattern: "freeleech", Action: IrcTriggerActionNotify}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.trigger.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestIrcTrigger_Match(t *testing.T) {
+	trigger := IrcTrigger{Name: "fl", Channel: "#staff", Pattern: `sitewide freeleech (is )?(now )?active`, Action: IrcTriggerActionNotify}
+
+	assert.True(t, trigger.Match("SITEWIDE FREELEECH IS NOW ACTIVE for 24 hours"))
+	assert.False(t, trigger.Match("sitewide freeleech has ended"))
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/moistari/rls"
+)
+
+// LanguageUntagged is the audio language of releases without a language tag.
+// Scene and most P2P releases are only tagged when they are not in English.
+const LanguageUntagged = "ENGLiSH"
+
+// SubtitleNone is the subtitle of releases without a subtitle tag, so they can be ranked in a subtitle preference
+const SubtitleNone = "NONE"
+
+// subtitleTags are the language tags that describe subtitles instead of the audio language, in upper case
+var subtitleTags = map[string]struct{}{
+	"DKSUBS":    {},
+	"HARDCODED": {},
+	"HARDSUB":   {},
+	"HC":        {},
+	"HEBSUB":    {},
+	"MULTISUB":  {},
+	"MULTISUBS": {},
+	"SUBBED":    {},
+	"SUBFORCED": {},
+	"SUBPACK":   {},
+	"UNSUBBED":  {},
+}
+
+// ReleaseLanguages returns the audio languages of the release, see LanguageUntagged for releases without one
+func ReleaseLanguages(r *Release) []string {
+	languages := make([]string, 0, len(r.Language))
+	for _, tag := range r.Language {
+		if _, ok := subtitleTags[strings.ToUpper(tag)]; !ok && tag != "" {
+			languages = append(languages, tag)
+		}
+	}
+
+	if len(languages) == 0 {
+		return []string{LanguageUntagged}
+	}
+
+	return languages
+}
+
+// ReleaseSubtitles returns the subtitle tags of the release, or SubtitleNone
+func ReleaseSubtitles(r *Release) []string {
+	subtitles := make([]string, 0)
+	for _, tag := range r.Language {
+		if _, ok := subtitleTags[strings.ToUpper(tag)]; ok {
+			subtitles = append(subtitles, tag)
+		}
+	}
+
+	if len(subtitles) == 0 {
+		return []string{SubtitleNone}
+	}
+
+	return subtitles
+}
+
+// ParseReleaseLanguage returns the language tags of a release name
+func ParseReleaseLanguage(name string) []string {
+	return rls.ParseString(name).Language
+}
+
+// PreferenceRank returns the rank of the best of the values in the preference, ordered from most to least preferred.
+// The most preferred value has the highest rank and values not in the preference rank 0.
+func PreferenceRank(preference []string, values []string) int {
+	rank := 0
+	for _, value := range values {
+		for i, p := range preference {
+			if strings.EqualFold(strings.TrimSpace(p), value) && len(preference)-i > rank {
+				rank = len(preference) - i
+			}
+		}
+	}
+
+	return rank
+}
+
+// onlyLanguages reports if all the languages are in the list
+func onlyLanguages(languages []string, list []string) bool {
+	for _, language := range languages {
+		found := false
+		for _, l := range list {
+			if strings.EqualFold(strings.TrimSpace(l), language) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return len(languages) > 0
+}
+
+// ValidatePreference checks that a preference has no empty values or duplicates
+func ValidatePreference(name string, preference []string) error {
+	seen := make(map[string]struct{}, len(preference))
+
+	for _, p := range preference {
+		value := strings.ToUpper(strings.TrimSpace(p))
+		if value == "" {
+			return errors.New("%s preference can't have empty values", name)
+		}
+
+		if _, ok := seen[value]; ok {
+			return errors.New("duplicate %s preference: %s", name, p)
+		}
+
+		seen[value] = struct{}{}
+	}
+
+	return nil
+}
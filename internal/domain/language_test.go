@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseLanguages(t *testing.T) {
+	assert.Equal(t, []string{"ENGLiSH"}, ReleaseLanguages(&Release{}))
+	assert.Equal(t, []string{"ENGLiSH"}, ReleaseLanguages(&Release{Language: []string{"SUBBED"}}))
+	assert.Equal(t, []string{"FRENCH"}, ReleaseLanguages(&Release{Language: []string{"FRENCH", "SUBFORCED"}}))
+	assert.Equal(t, []string{"MULTi", "FRENCH"}, ReleaseLanguages(&Release{Language: []string{"MULTi", "FRENCH"}}))
+}
+
+func TestReleaseSubtitles(t *testing.T) {
+	assert.Equal(t, []string{"NONE"}, ReleaseSubtitles(&Release{Language: []string{"GERMAN"}}))
+	assert.Equal(t, []string{"HARDSUB"}, ReleaseSubtitles(&Release{Language: []string{"KOREAN", "HARDSUB"}}))
+}
+
+func TestPreferenceRank(t *testing.T) {
+	preference := []string{"MULTi", "ENGLiSH", "GERMAN"}
+
+	assert.Equal(t, 3, PreferenceRank(preference, []string{"MULTi", "FRENCH"}))
+	assert.Equal(t, 2, PreferenceRank(preference, []string{"english"}))
+	assert.Equal(t, 2, PreferenceRank(preference, []string{"GERMAN", "ENGLiSH"}))
+	assert.Equal(t, 0, PreferenceRank(preference, []string{"FRENCH"}))
+	assert.Equal(t, 0, PreferenceRank(nil, []string{"FRENCH"}))
+}
+
+func TestFilter_CheckFilter_LanguagePreference(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		release string
+		want    bool
+	}{
+		{
+			name:    "preferred_multi",
+			filter:  Filter{LanguagePreference: []string{"MULTi", "ENGLiSH"}},
+			release: "Movie.2023.MULTi.1080p.BluRay.x264-GROUP",
+			want:    true,
+		},
+		{
+			name:    "untagged_is_english",
+			filter:  Filter{LanguagePreference: []string{"MULTi", "ENGLiSH"}},
+			release: "Movie.2023.1080p.BluRay.x264-GROUP",
+			want:    true,
+		},
+		{
+			name:    "not_preferred",
+			filter:  Filter{LanguagePreference: []string{"MULTi", "ENGLiSH"}},
+			release: "Movie.2023.GERMAN.1080p.BluRay.x264-GROUP",
+			want:    false,
+		},
+		{
+			name:    "reject_french_only",
+			filter:  Filter{ExceptLanguageOnly: []string{"FRENCH"}},
+			release: "Movie.2023.FRENCH.1080p.BluRay.x264-GROUP",
+			want:    false,
+		},
+		{
+			name:    "accept_french_with_multi",
+			filter:  Filter{ExceptLanguageOnly: []string{"FRENCH"}},
+			release: "Movie.2023.MULTi.FRENCH.1080p.BluRay.x264-GROUP",
+			want:    true,
+		},
+		{
+			name:    "subtitle_preference_none",
+			filter:  Filter{SubtitlePreference: []string{"NONE"}},
+			release: "Movie.2023.KOREAN.SUBBED.1080p.WEB.h264-GROUP",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.release)
+
+			_, got := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.want, got, r.Rejections)
+		})
+	}
+}
+
+func TestValidatePreference(t *testing.T) {
+	assert.NoError(t, ValidatePreference("language", nil))
+	assert.NoError(t, ValidatePreference("language", []string{"MULTi", "ENGLiSH"}))
+	assert.Error(t, ValidatePreference("language", []string{"MULTi", "multi"}))
+	assert.Error(t, ValidatePreference("language", []string{" "}))
+}
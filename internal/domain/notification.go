@@ -62,6 +62,9 @@ type NotificationPayload struct {
 	Rejections     []string
 	Protocol       ReleaseProtocol       // torrent, usenet
 	Implementation ReleaseImplementation // irc, rss, api
+	Network        string
+	Channel        string
+	Reason         string
 	Timestamp      time.Time
 }
 
@@ -80,6 +83,7 @@ const (
 	NotificationTypeSlack      NotificationType = "SLACK"
 	NotificationTypeTelegram   NotificationType = "TELEGRAM"
 	NotificationTypeGotify     NotificationType = "GOTIFY"
+	NotificationTypeWebhook    NotificationType = "WEBHOOK"
 )
 
 type NotificationEvent string
@@ -89,8 +93,16 @@ const (
 	NotificationEventPushApproved       NotificationEvent = "PUSH_APPROVED"
 	NotificationEventPushRejected       NotificationEvent = "PUSH_REJECTED"
 	NotificationEventPushError          NotificationEvent = "PUSH_ERROR"
+	NotificationEventIRCConnected       NotificationEvent = "IRC_CONNECTED"
 	NotificationEventIRCDisconnected    NotificationEvent = "IRC_DISCONNECTED"
 	NotificationEventIRCReconnected     NotificationEvent = "IRC_RECONNECTED"
+	NotificationEventIRCAuthFailed      NotificationEvent = "IRC_AUTH_FAILED"
+	NotificationEventIRCKicked          NotificationEvent = "IRC_KICKED"
+	NotificationEventIRCBanned          NotificationEvent = "IRC_BANNED"
+	NotificationEventIRCTrigger         NotificationEvent = "IRC_TRIGGER"
+	NotificationEventFeedFailing        NotificationEvent = "FEED_FAILING"
+	NotificationEventFeedRecovered      NotificationEvent = "FEED_RECOVERED"
+	NotificationEventFeedAuthFailed     NotificationEvent = "FEED_AUTH_FAILED"
 	NotificationEventTest               NotificationEvent = "TEST"
 )
 
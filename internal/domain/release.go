@@ -35,8 +35,10 @@ type ReleaseRepo interface {
 	Get(ctx context.Context, req *GetReleaseRequest) (*Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*ReleaseStats, error)
-	Delete(ctx context.Context, req *DeleteReleaseRequest) error
+	StatsBreakdown(ctx context.Context) (*ReleaseStatsBreakdown, error)
+	Delete(ctx context.Context, req *DeleteReleaseRequest) (int64, error)
 	CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error)
+	FindGrabbed(ctx context.Context, params FindGrabbedParams) ([]*Release, error)
 
 	GetActionStatus(ctx context.Context, req *GetReleaseActionStatusRequest) (*ReleaseActionStatus, error)
 	StoreReleaseActionStatus(ctx context.Context, status *ReleaseActionStatus) error
@@ -59,6 +61,7 @@ type Release struct {
 	TorrentTmpFile              string                `json:"-"`
 	TorrentDataRawBytes         []byte                `json:"-"`
 	TorrentHash                 string                `json:"-"`
+	TorrentFiles                []ReleaseTorrentFile  `json:"-"`            // files of the downloaded torrent
 	TorrentName                 string                `json:"torrent_name"` // full release name
 	Size                        uint64                `json:"size"`
 	Title                       string                `json:"title"` // Parsed title
@@ -68,6 +71,8 @@ type Release struct {
 	Season                      int                   `json:"season"`
 	Episode                     int                   `json:"episode"`
 	Year                        int                   `json:"year"`
+	Month                       int                   `json:"-"`
+	Day                         int                   `json:"-"`
 	Resolution                  string                `json:"resolution"`
 	Source                      string                `json:"source"`
 	Codec                       []string              `json:"codec"`
@@ -75,6 +80,12 @@ type Release struct {
 	HDR                         []string              `json:"hdr"`
 	Audio                       []string              `json:"-"`
 	AudioChannels               string                `json:"-"`
+	BitDepth                    int                   `json:"-"` // music bit depth, eg. 16 or 24
+	SampleRate                  int                   `json:"-"` // music sample rate in Hz, eg. 44100 or 96000
+	Anime                       bool                  `json:"-"` // parsed as anime, see ParseAnime
+	AbsoluteEpisode             int                   `json:"-"`
+	Batch                       bool                  `json:"-"`
+	AnimeSubType                AnimeSubType          `json:"-"`
 	Group                       string                `json:"group"`
 	Region                      string                `json:"-"`
 	Language                    []string              `json:"-"`
@@ -89,12 +100,17 @@ type Release struct {
 	ReleaseTags                 string                `json:"-"`
 	Freeleech                   bool                  `json:"-"`
 	FreeleechPercent            int                   `json:"-"`
+	Seeders                     int                   `json:"-"`
+	Leechers                    int                   `json:"-"`
+	PeersKnown                  bool                  `json:"-"` // seeders and leechers are set, from the feed or the indexer api
 	Bonus                       []string              `json:"-"`
 	Uploader                    string                `json:"uploader"`
 	PreTime                     string                `json:"pre_time"`
 	Other                       []string              `json:"-"`
 	RawCookie                   string                `json:"-"`
 	AdditionalSizeCheckRequired bool                  `json:"-"`
+	UpgradeOf                   string                `json:"-"` // name of the grabbed release a PROPER or REPACK replaces
+	Flags                       []string              `json:"-"` // flags set by irc triggers when the release was checked
 	FilterID                    int                   `json:"-"`
 	Filter                      *Filter               `json:"-"`
 	ActionStatus                []ReleaseActionStatus `json:"action_status"`
@@ -115,7 +131,9 @@ type ReleaseActionStatus struct {
 }
 
 type DeleteReleaseRequest struct {
-	OlderThan int
+	OlderThan       int
+	Indexers        []string
+	ReleaseStatuses []ReleasePushStatus
 }
 
 func NewReleaseActionStatus(action *Action, release *Release) *ReleaseActionStatus {
@@ -139,6 +157,12 @@ func NewReleaseActionStatus(action *Action, release *Release) *ReleaseActionStat
 	return s
 }
 
+// ReleaseTorrentFile is a file in the torrent of a release
+type ReleaseTorrentFile struct {
+	Path string `json:"path"`
+	Size uint64 `json:"size"`
+}
+
 type DownloadTorrentFileResponse struct {
 	MetaInfo    *metainfo.MetaInfo
 	TmpFileName string
@@ -152,6 +176,22 @@ type ReleaseStats struct {
 	PushRejectedCount   int64 `json:"push_rejected_count"`
 }
 
+// ReleaseStatsBreakdown groups action push results by filter, indexer and time window
+type ReleaseStatsBreakdown struct {
+	Filters  []ReleaseStatsGroup `json:"filters"`
+	Indexers []ReleaseStatsGroup `json:"indexers"`
+	Windows  []ReleaseStatsGroup `json:"windows"`
+}
+
+// ReleaseStatsGroup counts action pushes, a snatch is an approved push
+type ReleaseStatsGroup struct {
+	Name      string  `json:"name"`
+	Snatched  int64   `json:"snatched"`
+	Rejected  int64   `json:"rejected"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
 type ReleasePushStatus string
 
 const (
@@ -225,6 +265,7 @@ const (
 	ReleaseImplementationTorznab ReleaseImplementation = "TORZNAB"
 	ReleaseImplementationNewznab ReleaseImplementation = "NEWZNAB"
 	ReleaseImplementationRSS     ReleaseImplementation = "RSS"
+	ReleaseImplementationJSON    ReleaseImplementation = "JSON"
 )
 
 func (r ReleaseImplementation) String() string {
@@ -237,6 +278,8 @@ func (r ReleaseImplementation) String() string {
 		return "NEWZNAB"
 	case ReleaseImplementationRSS:
 		return "RSS"
+	case ReleaseImplementationJSON:
+		return "JSON"
 	default:
 		return "IRC"
 	}
@@ -264,6 +307,17 @@ type GetReleaseRequest struct {
 	Id int
 }
 
+// FindGrabbedParams selects the grabbed releases of a title. Movies are matched on year,
+// episodes and season packs on season and episode. A FilterID limits them to grabs of that filter.
+type FindGrabbedParams struct {
+	Title     string
+	Year      int
+	Season    int
+	Episode   int
+	FilterID  int
+	ExcludeID int64
+}
+
 type GetReleaseActionStatusRequest struct {
 	Id int
 }
@@ -296,6 +350,8 @@ func (r *Release) ParseString(title string) {
 	r.Container = rel.Container
 	r.HDR = rel.HDR
 	r.Other = rel.Other
+	r.Proper = otherContains(rel.Other, "PROPER")
+	r.Repack = otherContains(rel.Other, "REPACK")
 	r.Artists = rel.Artist
 	r.Language = rel.Language
 
@@ -314,13 +370,31 @@ func (r *Release) ParseString(title string) {
 		r.Year = rel.Year
 	}
 
+	if r.Month == 0 && r.Day == 0 {
+		r.Month = rel.Month
+		r.Day = rel.Day
+	}
+
 	if r.Group == "" {
 		r.Group = rel.Group
 	}
 
+	r.parseAudioQuality(title)
+
 	r.ParseReleaseTagsString(r.ReleaseTags)
 }
 
+// otherContains checks the parsed other tags like REAL.PROPER or REREPACK for a tag
+func otherContains(other []string, tag string) bool {
+	for _, o := range other {
+		if strings.Contains(strings.ToUpper(o), tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
 var ErrUnrecoverableError = errors.New("unrecoverable error")
 
 func (r *Release) ParseReleaseTagsString(tags string) {
@@ -363,6 +437,8 @@ func (r *Release) ParseReleaseTagsString(tags string) {
 	if r.AudioChannels == "" && t.Channels != "" {
 		r.AudioChannels = t.Channels
 	}
+
+	r.parseAudioQuality(tags)
 }
 
 // ParseSizeBytesString If there are parsing errors, then it keeps the original (or default size 0)
@@ -514,6 +590,7 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 		r.TorrentTmpFile = tmpFile.Name()
 		r.TorrentHash = meta.HashInfoBytes().String()
 		r.Size = uint64(torrentMetaInfo.TotalLength())
+		r.TorrentFiles = torrentFiles(&torrentMetaInfo)
 
 		return nil
 	},
@@ -525,6 +602,21 @@ func (r *Release) downloadTorrentFile(ctx context.Context) error {
 	return errFunc
 }
 
+// torrentFiles lists the files of a torrent without BEP 47 padding files
+func torrentFiles(info *metainfo.Info) []ReleaseTorrentFile {
+	files := make([]ReleaseTorrentFile, 0, len(info.UpvertedFiles()))
+	for _, file := range info.UpvertedFiles() {
+		path := file.DisplayPath(info)
+		if strings.HasPrefix(path, ".pad/") {
+			continue
+		}
+
+		files = append(files, ReleaseTorrentFile{Path: path, Size: uint64(file.Length)})
+	}
+
+	return files
+}
+
 func (r *Release) CleanupTemporaryFiles() {
 	if len(r.TorrentTmpFile) == 0 {
 		return
@@ -534,6 +626,23 @@ func (r *Release) CleanupTemporaryFiles() {
 	r.TorrentTmpFile = ""
 }
 
+// NormalizeOrigin returns the announced origin of a release in the form used by filters: P2P, SCENE, O-SCENE or INTERNAL.
+// Unknown origins are upper-cased.
+func NormalizeOrigin(origin string) string {
+	origin = strings.ToUpper(strings.TrimSpace(origin))
+
+	switch origin {
+	case "OSCENE", "O SCENE", "OLDSCENE", "OLD SCENE":
+		return "O-SCENE"
+	case "INT", "INTERNAL!":
+		return "INTERNAL"
+	case "NON-SCENE", "NONSCENE":
+		return "P2P"
+	}
+
+	return origin
+}
+
 // HasMagnetUri check uf MagnetURI is set or empty
 func (r *Release) HasMagnetUri() bool {
 	return r.MagnetURI != ""
@@ -640,6 +749,9 @@ func (r *Release) RejectionsString(trim bool) string {
 
 // MapVars map vars from regex captures to fields on release
 func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) error {
+	if def.IRC != nil && def.IRC.Parse != nil {
+		def.IRC.Parse.MapValues(varMap)
+	}
 
 	if torrentName, err := getStringMapValue(varMap, "torrentName"); err != nil {
 		return errors.Wrap(err, "failed parsing required field")
@@ -717,9 +829,9 @@ func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) erro
 		}
 	}
 
-	// set origin. P2P, SCENE, O-SCENE and Internal
-	if origin, err := getStringMapValue(varMap, "origin"); err == nil {
-		r.Origin = origin
+	// set origin. P2P, SCENE, O-SCENE and INTERNAL
+	if origin, err := getStringMapValue(varMap, "origin"); err == nil && strings.TrimSpace(origin) != "" {
+		r.Origin = NormalizeOrigin(origin)
 	}
 
 	if internal, err := getStringMapValue(varMap, "internal"); err == nil {
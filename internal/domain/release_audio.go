@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var (
+	bitDepthRegexp   = regexp.MustCompile(`(?i)\b(16|24|32)[\s\-]?bit\b`)
+	sampleRateRegexp = regexp.MustCompile(`(?i)\b(44[.,]1|48|88[.,]2|96|176[.,]4|192|352[.,]8|384)[\s\-]?khz\b`)
+	channelsRegexp   = regexp.MustCompile(`(?i)\b(mono|stereo)\b`)
+)
+
+// parseAudioQuality sets the bit depth, sample rate and channels of music releases from a release name or
+// announce tags like "FLAC / 24bit Lossless / 96kHz / Stereo". Values that are already set are kept.
+func (r *Release) parseAudioQuality(s string) {
+	if r.BitDepth == 0 {
+		if m := bitDepthRegexp.FindStringSubmatch(s); m != nil {
+			r.BitDepth, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if r.SampleRate == 0 {
+		if m := sampleRateRegexp.FindStringSubmatch(s); m != nil {
+			r.SampleRate, _ = parseSampleRate(m[1])
+		}
+	}
+
+	if r.AudioChannels == "" {
+		if m := channelsRegexp.FindStringSubmatch(s); m != nil {
+			r.AudioChannels = "2.0"
+			if strings.EqualFold(m[1], "mono") {
+				r.AudioChannels = "1.0"
+			}
+		}
+	}
+}
+
+// parseSampleRate parses a sample rate in kHz like 44.1, 96 or 96kHz into Hz
+func parseSampleRate(value string) (int, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	value = strings.TrimSuffix(value, "khz")
+	value = strings.Replace(strings.TrimSpace(value), ",", ".", 1)
+
+	khz, err := strconv.ParseFloat(value, 64)
+	if err != nil || khz <= 0 {
+		return 0, errors.New("invalid sample rate: %s", value)
+	}
+
+	return int(math.Round(khz * 1000)), nil
+}
+
+// parseBitDepth parses a bit depth like 24 or 24bit
+func parseBitDepth(value string) (int, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(value, "bit"), "-"))
+
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth <= 0 {
+		return 0, errors.New("invalid bit depth: %s", value)
+	}
+
+	return depth, nil
+}
+
+// formatSampleRate formats a sample rate in Hz as kHz, eg. 44.1kHz
+func formatSampleRate(hz int) string {
+	if hz == 0 {
+		return "unknown"
+	}
+
+	return strconv.FormatFloat(float64(hz)/1000, 'f', -1, 64) + "kHz"
+}
+
+func matchBitDepth(depth int, filterValues []string) bool {
+	for _, value := range filterValues {
+		if d, err := parseBitDepth(value); err == nil && d == depth {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSampleRate(hz int, filterValues []string) bool {
+	for _, value := range filterValues {
+		if rate, err := parseSampleRate(value); err == nil && rate == hz {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAudioQuality checks the bit depths and sample rates of a music filter
+func ValidateAudioQuality(bitDepths []string, sampleRates []string) error {
+	for _, value := range bitDepths {
+		if _, err := parseBitDepth(value); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range sampleRates {
+		if _, err := parseSampleRate(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
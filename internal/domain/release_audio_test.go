@@ -0,0 +1,92 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease_parseAudioQuality(t *testing.T) {
+	tests := []struct {
+		name           string
+		releaseName    string
+		releaseTags    string
+		wantBitDepth   int
+		wantSampleRate int
+		wantChannels   string
+	}{
+		{
+			name:           "gazelle tags",
+			releaseName:    "Artist - Album [2023] [Album] - FLAC / 24bit Lossless / WEB",
+			releaseTags:    "FLAC / 24bit Lossless / 96kHz / Stereo / WEB",
+			wantBitDepth:   24,
+			wantSampleRate: 96000,
+			wantChannels:   "2.0",
+		},
+		{
+			name:           "scene name",
+			releaseName:    "Artist-Album-24BIT-192KHZ-WEB-FLAC-2023-GRP",
+			wantBitDepth:   24,
+			wantSampleRate: 192000,
+		},
+		{
+			name:           "decimal sample rate",
+			releaseName:    "Artist - Album [2023] [Album] - FLAC / 16-bit / 44.1 kHz / Mono / CD",
+			wantBitDepth:   16,
+			wantSampleRate: 44100,
+			wantChannels:   "1.0",
+		},
+		{
+			name:        "lossy",
+			releaseName: "Artist - Album [2023] [Album] - MP3 / 320 / WEB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("red")
+			r.ReleaseTags = tt.releaseTags
+			r.ParseString(tt.releaseName)
+
+			assert.Equal(t, tt.wantBitDepth, r.BitDepth)
+			assert.Equal(t, tt.wantSampleRate, r.SampleRate)
+			if tt.wantChannels != "" {
+				assert.Equal(t, tt.wantChannels, r.AudioChannels)
+			}
+		})
+	}
+}
+
+func TestFilter_CheckFilter_AudioQuality(t *testing.T) {
+	r := &Release{TorrentName: "Artist - Album [2023] [Album] - FLAC / 24bit Lossless / 96kHz / WEB", BitDepth: 24, SampleRate: 96000, AudioChannels: "2.0"}
+
+	tests := []struct {
+		name           string
+		filter         Filter
+		wantRejections []string
+		wantMatch      bool
+	}{
+		{name: "match", filter: Filter{BitDepths: []string{"24"}, SampleRates: []string{"96", "192"}, AudioChannels: []string{"2.0"}}, wantMatch: true},
+		{name: "units", filter: Filter{BitDepths: []string{"24bit"}, SampleRates: []string{"96kHz"}}, wantMatch: true},
+		{name: "bit depth", filter: Filter{BitDepths: []string{"16"}}, wantRejections: []string{"bit depth not matching. got: 24 want: [16]"}},
+		{name: "sample rate", filter: Filter{SampleRates: []string{"44.1", "48"}}, wantRejections: []string{"sample rate not matching. got: 96kHz want: [44.1 48]"}},
+		{name: "channels", filter: Filter{AudioChannels: []string{"5.1"}}, wantRejections: []string{"audio channels not matching. got: 2.0 want: [5.1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.resetRejections()
+
+			rejections, match := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.wantRejections, rejections)
+			assert.Equal(t, tt.wantMatch, match)
+		})
+	}
+}
+
+func TestValidateAudioQuality(t *testing.T) {
+	assert.NoError(t, ValidateAudioQuality([]string{"16", "24bit"}, []string{"44.1", "96kHz", "192"}))
+	assert.Error(t, ValidateAudioQuality([]string{"high"}, nil))
+	assert.Error(t, ValidateAudioQuality(nil, []string{"fast"}))
+}
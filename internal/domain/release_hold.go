@@ -0,0 +1,54 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SeasonPackHoldMax is the longest time in hours the actions of an episode can be held for a season pack
+const SeasonPackHoldMax = 168
+
+type ReleaseHoldRepo interface {
+	Store(ctx context.Context, hold *ReleaseHold) error
+	FindDue(ctx context.Context, now time.Time) ([]ReleaseHold, error)
+	FindByType(ctx context.Context, holdType ReleaseHoldType) ([]ReleaseHold, error)
+	FindBySeason(ctx context.Context, title string, season int) ([]ReleaseHold, error)
+	Claim(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ReleaseHoldType string
+
+const (
+	// ReleaseHoldTypeSeasonPack holds a single episode until a season pack is grabbed or the hold expires
+	ReleaseHoldTypeSeasonPack ReleaseHoldType = "SEASON_PACK"
+
+	// ReleaseHoldTypeDelay holds a release for the action delay of its filter
+	ReleaseHoldTypeDelay ReleaseHoldType = "DELAY"
+)
+
+// ReleaseHold is a matched release whose actions wait until HoldUntil
+type ReleaseHold struct {
+	ID        int             `json:"id"`
+	Type      ReleaseHoldType `json:"type"`
+	ReleaseID int64           `json:"release_id"`
+	FilterID  int             `json:"filter_id"`
+	Title     string          `json:"title"`
+	Season    int             `json:"season"`
+	Episode   int             `json:"episode"`
+	HoldUntil time.Time       `json:"hold_until"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// IsSingleEpisode returns true for releases of one episode of a season
+func (r *Release) IsSingleEpisode() bool {
+	return r.Season > 0 && r.Episode > 0
+}
+
+// IsSeasonPack returns true for releases of a whole season
+func (r *Release) IsSeasonPack() bool {
+	return r.Season > 0 && r.Episode == 0
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease_IsSeasonPack(t *testing.T) {
+	tests := []struct {
+		name          string
+		torrentName   string
+		singleEpisode bool
+		seasonPack    bool
+	}{
+		{name: "episode", torrentName: "Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX", singleEpisode: true},
+		{name: "season pack", torrentName: "Servant.S01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX", seasonPack: true},
+		{name: "movie", torrentName: "That Movie 2020 2160p BluRay DD5.1 x264-GROUP1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("ptp")
+			r.ParseString(tt.torrentName)
+
+			assert.Equal(t, tt.singleEpisode, r.IsSingleEpisode())
+			assert.Equal(t, tt.seasonPack, r.IsSeasonPack())
+		})
+	}
+}
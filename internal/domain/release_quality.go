@@ -0,0 +1,137 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"strings"
+)
+
+var qualityResolutionRank = map[string]int{
+	"480i":  1,
+	"480p":  1,
+	"576i":  2,
+	"576p":  2,
+	"720p":  3,
+	"1080i": 4,
+	"1080p": 5,
+	"2160p": 6,
+}
+
+var qualitySourceRank = map[string]int{
+	"dvdrip":     1,
+	"dvd":        1,
+	"hdtv":       2,
+	"webrip":     3,
+	"web":        4,
+	"web-dl":     5,
+	"bluray":     6,
+	"uhd.bluray": 7,
+}
+
+// ReleaseQuality is the quality of a release used to compare duplicates of the same episode.
+// Resolution is compared first, then source, HDR, language and subtitles. Unknown values rank lowest.
+type ReleaseQuality struct {
+	Resolution string
+	Source     string
+	HDR        []string
+	Language   []string
+
+	// HDRPreference ranks HDR formats from most to least preferred, see HDRPreferenceRank.
+	// Without a preference any HDR ranks above SDR.
+	HDRPreference []string
+
+	// LanguagePreference and SubtitlePreference rank the language tags, see PreferenceRank.
+	// Without a preference languages and subtitles are not compared.
+	LanguagePreference []string
+	SubtitlePreference []string
+}
+
+func NewReleaseQuality(r *Release) ReleaseQuality {
+	return ReleaseQuality{Resolution: r.Resolution, Source: r.Source, HDR: r.HDR, Language: r.Language}
+}
+
+// WithPreferences returns the quality ranked by the HDR, language and subtitle preferences of the filter
+func (q ReleaseQuality) WithPreferences(f *Filter) ReleaseQuality {
+	if f == nil {
+		return q
+	}
+
+	q.HDRPreference = f.HDRPreference
+	q.LanguagePreference = f.LanguagePreference
+	q.SubtitlePreference = f.SubtitlePreference
+
+	return q
+}
+
+// Compare returns -1 if q is worse than other, 0 if they are equal and 1 if q is better
+func (q ReleaseQuality) Compare(other ReleaseQuality) int {
+	a := [5]int{q.resolutionRank(), q.sourceRank(), q.hdrRank(), q.languageRank(), q.subtitleRank()}
+	b := [5]int{other.resolutionRank(), other.sourceRank(), other.hdrRank(), other.languageRank(), other.subtitleRank()}
+
+	for i := range a {
+		if a[i] > b[i] {
+			return 1
+		}
+		if a[i] < b[i] {
+			return -1
+		}
+	}
+
+	return 0
+}
+
+func (q ReleaseQuality) String() string {
+	parts := make([]string, 0, 3)
+	if q.Resolution != "" {
+		parts = append(parts, q.Resolution)
+	}
+	if q.Source != "" {
+		parts = append(parts, q.Source)
+	}
+	parts = append(parts, q.HDR...)
+
+	if len(parts) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (q ReleaseQuality) resolutionRank() int {
+	return qualityResolutionRank[strings.ToLower(q.Resolution)]
+}
+
+func (q ReleaseQuality) sourceRank() int {
+	return qualitySourceRank[strings.ToLower(q.Source)]
+}
+
+func (q ReleaseQuality) hdrRank() int {
+	if len(q.HDRPreference) > 0 {
+		return HDRPreferenceRank(q.HDRPreference, NewHDRFormat(q.HDR))
+	}
+
+	for _, hdr := range q.HDR {
+		if hdr != "" {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (q ReleaseQuality) languageRank() int {
+	if len(q.LanguagePreference) == 0 {
+		return 0
+	}
+
+	return PreferenceRank(q.LanguagePreference, ReleaseLanguages(&Release{Language: q.Language}))
+}
+
+func (q ReleaseQuality) subtitleRank() int {
+	if len(q.SubtitlePreference) == 0 {
+		return 0
+	}
+
+	return PreferenceRank(q.SubtitlePreference, ReleaseSubtitles(&Release{Language: q.Language}))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseQuality_Compare(t *testing.T) {
+	tests := []struct {
+		name string
+		a    ReleaseQuality
+		b    ReleaseQuality
+		want int
+	}{
+		{name: "equal", a: ReleaseQuality{Resolution: "1080p", Source: "WEB-DL"}, b: ReleaseQuality{Resolution: "1080p", Source: "WEB-DL"}, want: 0},
+		{name: "higher resolution", a: ReleaseQuality{Resolution: "2160p", Source: "WEB"}, b: ReleaseQuality{Resolution: "1080p", Source: "BluRay"}, want: 1},
+		{name: "lower resolution", a: ReleaseQuality{Resolution: "720p", Source: "BluRay"}, b: ReleaseQuality{Resolution: "1080p", Source: "HDTV"}, want: -1},
+		{name: "better source", a: ReleaseQuality{Resolution: "1080p", Source: "WEB-DL"}, b: ReleaseQuality{Resolution: "1080p", Source: "HDTV"}, want: 1},
+		{name: "source case insensitive", a: ReleaseQuality{Resolution: "1080p", Source: "WEBRiP"}, b: ReleaseQuality{Resolution: "1080p", Source: "webrip"}, want: 0},
+		{name: "hdr upgrade", a: ReleaseQuality{Resolution: "2160p", Source: "WEB-DL", HDR: []string{"DV", "HDR10"}}, b: ReleaseQuality{Resolution: "2160p", Source: "WEB-DL"}, want: 1},
+		{name: "hdr preference", a: ReleaseQuality{Resolution: "2160p", HDR: []string{"HDR10+"}, HDRPreference: []string{"DV HDR10", "HDR10+", "HDR10"}}, b: ReleaseQuality{Resolution: "2160p", HDR: []string{"DV"}, HDRPreference: []string{"DV HDR10", "HDR10+", "HDR10"}}, want: 1},
+		{name: "hdr preference downgrade", a: ReleaseQuality{Resolution: "2160p", HDR: []string{"HDR10"}, HDRPreference: []string{"DV HDR10", "HDR10"}}, b: ReleaseQuality{Resolution: "2160p", HDR: []string{"DV", "HDR10"}, HDRPreference: []string{"DV HDR10", "HDR10"}}, want: -1},
+		{name: "language preference", a: ReleaseQuality{Resolution: "1080p", Language: []string{"MULTi"}, LanguagePreference: []string{"MULTi", "ENGLiSH"}}, b: ReleaseQuality{Resolution: "1080p", LanguagePreference: []string{"MULTi", "ENGLiSH"}}, want: 1},
+		{name: "language without preference", a: ReleaseQuality{Resolution: "1080p", Language: []string{"MULTi"}}, b: ReleaseQuality{Resolution: "1080p"}, want: 0},
+		{name: "subtitle preference", a: ReleaseQuality{Resolution: "1080p", SubtitlePreference: []string{"SUBBED", "NONE"}}, b: ReleaseQuality{Resolution: "1080p", Language: []string{"SUBBED"}, SubtitlePreference: []string{"SUBBED", "NONE"}}, want: -1},
+		{name: "unknown ranks lowest", a: ReleaseQuality{}, b: ReleaseQuality{Resolution: "480p"}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.a.Compare(tt.b))
+		})
+	}
+}
+
+func TestReleaseQuality_String(t *testing.T) {
+	assert.Equal(t, "2160p WEB-DL DV HDR10", ReleaseQuality{Resolution: "2160p", Source: "WEB-DL", HDR: []string{"DV", "HDR10"}}.String())
+	assert.Equal(t, "unknown", ReleaseQuality{}.String())
+}
@@ -224,6 +224,7 @@ func TestRelease_Parse(t *testing.T) {
 				Title:       "Artist",
 				Group:       "Albumname",
 				Audio:       []string{"24BIT Lossless", "Cue", "FLAC", "Log100", "Log"},
+				BitDepth:    24,
 				Source:      "CD",
 			},
 		},
@@ -521,6 +522,39 @@ func TestRelease_MapVars(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "origin",
+			fields: &Release{},
+			want:   &Release{TorrentName: "That.Movie.2017.1080p.BluRay.x264-GROUP", Origin: "INTERNAL"},
+			args: args{varMap: map[string]string{
+				"torrentName": "That.Movie.2017.1080p.BluRay.x264-GROUP",
+				"origin":      "Internal",
+			}},
+		},
+		{
+			name:   "empty origin",
+			fields: &Release{},
+			want:   &Release{TorrentName: "That.Movie.2017.1080p.BluRay.x264-GROUP", Origin: "SCENE"},
+			args: args{varMap: map[string]string{
+				"torrentName": "That.Movie.2017.1080p.BluRay.x264-GROUP",
+				"scene":       "1",
+				"origin":      "",
+			}},
+		},
+		{
+			name:   "mappings",
+			fields: &Release{},
+			want:   &Release{TorrentName: "That.Movie.2017.1080p.BluRay.x264-GROUP", Origin: "SCENE"},
+			args: args{
+				varMap: map[string]string{
+					"torrentName": "That.Movie.2017.1080p.BluRay.x264-GROUP",
+					"scene":       "ja",
+				},
+				definition: IndexerDefinition{IRC: &IndexerIRC{Parse: &IndexerIRCParse{
+					Mappings: map[string]map[string]string{"scene": {"Ja": "yes", "Nei": "no"}},
+				}}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -663,6 +697,29 @@ func TestRelease_ParseString(t *testing.T) {
 	}
 }
 
+func TestRelease_ParseString_ProperRepack(t *testing.T) {
+	tests := []struct {
+		title  string
+		proper bool
+		repack bool
+	}{
+		{title: "Show.S01E02.PROPER.1080p.WEB.h264-GRP", proper: true},
+		{title: "Show.S01E02.REPACK.1080p.WEB.h264-GRP", repack: true},
+		{title: "Show.S01E02.REPACK2.1080p.WEB.h264-GRP", repack: true},
+		{title: "Movie.2020.REAL.PROPER.1080p.BluRay.x264-GRP", proper: true},
+		{title: "Show.S01E02.1080p.WEB.h264-GRP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			r := NewRelease("ptp")
+			r.ParseString(tt.title)
+
+			assert.Equal(t, tt.proper, r.Proper)
+			assert.Equal(t, tt.repack, r.Repack)
+		})
+	}
+}
+
 var trackerLessTestTorrent = `d7:comment19:This is just a test10:created by12:Johnny Bravo13:creation datei1430648794e8:encoding5:UTF-84:infod6:lengthi1128e4:name12:testfile.bin12:piece lengthi32768e6:pieces20:Õˆë	=‘UŒäiÎ^æ °Eâ?ÇÒe5:nodesl35:udp://tracker.openbittorrent.com:8035:udp://tracker.openbittorrent.com:80ee`
 
 func TestRelease_DownloadTorrentFile(t *testing.T) {
@@ -917,6 +974,10 @@ func TestRelease_DownloadTorrentFile(t *testing.T) {
 				fmt.Println("error")
 			}
 
+			if err == nil {
+				assert.Equal(t, []ReleaseTorrentFile{{Path: "archlinux-2011.08.19-netinstall-i686.iso", Size: r.Size}}, r.TorrentFiles)
+			}
+
 		})
 	}
 }
@@ -970,3 +1031,13 @@ func Test_getUniqueTags(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeOrigin(t *testing.T) {
+	assert.Equal(t, "INTERNAL", NormalizeOrigin("Internal"))
+	assert.Equal(t, "INTERNAL", NormalizeOrigin(" internal! "))
+	assert.Equal(t, "O-SCENE", NormalizeOrigin("o-scene"))
+	assert.Equal(t, "O-SCENE", NormalizeOrigin("Old Scene"))
+	assert.Equal(t, "P2P", NormalizeOrigin("p2p"))
+	assert.Equal(t, "SCENE", NormalizeOrigin("Scene"))
+	assert.Equal(t, "USER", NormalizeOrigin("User"))
+}
@@ -3,13 +3,18 @@
 
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type UserRepo interface {
 	GetUserCount(ctx context.Context) (int, error)
 	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindAll(ctx context.Context) ([]*User, error)
 	Store(ctx context.Context, req CreateUserRequest) error
 	Update(ctx context.Context, user User) error
+	Delete(ctx context.Context, username string) error
 }
 
 type User struct {
@@ -22,3 +27,29 @@ type CreateUserRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
+
+type AuthRecoveryRepo interface {
+	Store(ctx context.Context, token AuthRecoveryToken) error
+	Consume(ctx context.Context, tokenHash string) error
+	DeleteUnused(ctx context.Context) (int64, error)
+}
+
+// AuthRecoveryToken allows a single login without credentials until it expires.
+// Only the hash of the token is stored.
+type AuthRecoveryToken struct {
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+type userContextKey struct{}
+
+// ContextWithUser returns a context that carries the name of the user making a request
+func ContextWithUser(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, username)
+}
+
+// UserFromContext returns the name of the user making a request, or an empty string for internal calls
+func UserFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(userContextKey{}).(string)
+	return username
+}
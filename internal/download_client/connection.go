@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/aria2"
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/lidarr"
+	"github.com/autobrr/autobrr/pkg/nzbget"
 	"github.com/autobrr/autobrr/pkg/porla"
 	"github.com/autobrr/autobrr/pkg/radarr"
 	"github.com/autobrr/autobrr/pkg/readarr"
@@ -60,6 +62,12 @@ func (s *service) testConnection(ctx context.Context, client domain.DownloadClie
 	case domain.DownloadClientTypeSabnzbd:
 		return s.testSabnzbdConnection(ctx, client)
 
+	case domain.DownloadClientTypeNzbget:
+		return s.testNzbgetConnection(ctx, client)
+
+	case domain.DownloadClientTypeAria2:
+		return s.testAria2Connection(ctx, client)
+
 	default:
 		return errors.New("unsupported client: %s", client.Type)
 	}
@@ -336,3 +344,37 @@ func (s *service) testSabnzbdConnection(ctx context.Context, client domain.Downl
 
 	return nil
 }
+
+func (s *service) testNzbgetConnection(ctx context.Context, client domain.DownloadClient) error {
+	nzb := nzbget.New(nzbget.Options{
+		Addr:     client.Host,
+		Username: client.Username,
+		Password: client.Password,
+	})
+
+	version, err := nzb.Version(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error getting version from nzbget")
+	}
+
+	s.log.Debug().Msgf("test client connection for nzbget: success got version: %s", version)
+
+	return nil
+}
+
+func (s *service) testAria2Connection(ctx context.Context, client domain.DownloadClient) error {
+	a := aria2.New(aria2.Options{
+		Addr:          client.Host,
+		Secret:        client.Settings.APIKey,
+		TLSSkipVerify: client.TLSSkipVerify,
+	})
+
+	version, err := a.GetVersion(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error getting version from aria2")
+	}
+
+	s.log.Debug().Msgf("test client connection for aria2: success got version: %s", version.Version)
+
+	return nil
+}
@@ -39,6 +39,7 @@ func (s Subscriber) Register() {
 	s.eventbus.Subscribe("release:store-action-status", s.releaseActionStatus)
 	s.eventbus.Subscribe("release:push", s.releasePushStatus)
 	s.eventbus.Subscribe("events:notification", s.sendNotification)
+	s.eventbus.Subscribe("irc:state", s.ircState)
 }
 
 func (s Subscriber) releaseActionStatus(actionStatus *domain.ReleaseActionStatus) {
@@ -63,3 +64,11 @@ func (s Subscriber) sendNotification(event *domain.NotificationEvent, payload *d
 
 	s.notificationSvc.Send(*event, *payload)
 }
+
+func (s Subscriber) ircState(event *domain.IrcStateEvent) {
+	s.log.Trace().Msgf("events: 'irc:state' '%+v'", event)
+
+	payload := event.NotificationPayload()
+
+	s.notificationSvc.Send(payload.Event, payload)
+}
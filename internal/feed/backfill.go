@@ -0,0 +1,303 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+const (
+	backfillPageSize = 100
+
+	// backfillMaxPages stops a backfill of an indexer that ignores the offset and returns the same page
+	backfillMaxPages = 50
+)
+
+// backfiller is implemented by the feed jobs that can page back through the older items of their feed
+type backfiller interface {
+	// backfillPage returns the items of the page, newest first. Offset is the number of newer items of the earlier pages.
+	backfillPage(ctx context.Context, page int, offset int) ([]backfillItem, error)
+}
+
+// backfillItem is an item of an older page, release is nil when the item can not be processed
+type backfillItem struct {
+	key       string
+	title     string
+	published time.Time
+	release   *domain.Release
+}
+
+// runBackfill pages back through the older items of the feed and processes the ones not in its cache yet, one page at a time
+// with the delay of the settings in between. It ends at the count of the settings, at the first item older than its max age,
+// or at the last page, and returns the number of processed items.
+func runBackfill(ctx context.Context, log zerolog.Logger, feed *domain.Feed, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, settings domain.FeedBackfillSettings, b backfiller) (int, error) {
+	var cutoff time.Time
+	if settings.MaxAge > 0 {
+		cutoff = time.Now().Add(-time.Duration(settings.MaxAge) * time.Second)
+	}
+
+	// set ttl to 1 month
+	ttl := time.Now().AddDate(0, 1, 0)
+
+	processed := 0
+	offset := 0
+
+	for page := 0; page < backfillMaxPages && processed < settings.Count; page++ {
+		if page > 0 && settings.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return processed, ctx.Err()
+			case <-time.After(time.Duration(settings.Delay) * time.Second):
+			}
+		}
+
+		items, err := b.backfillPage(ctx, page, offset)
+		if err != nil {
+			return processed, errors.Wrap(err, "could not get page %d", page)
+		}
+
+		if len(items) == 0 {
+			break
+		}
+
+		offset += len(items)
+
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].published.After(items[j].published)
+		})
+
+		tooOld := false
+		releases := make([]*domain.Release, 0)
+
+		for _, item := range items {
+			if !cutoff.IsZero() && !item.published.IsZero() && item.published.Before(cutoff) {
+				tooOld = true
+				break
+			}
+
+			if processed >= settings.Count {
+				break
+			}
+
+			if item.key == "" || item.release == nil {
+				continue
+			}
+
+			exists, err := cacheRepo.Exists(feed.ID, item.key)
+			if err != nil {
+				log.Error().Err(err).Msg("could not check if item exists")
+				continue
+			}
+			if exists {
+				log.Trace().Msgf("cache item exists, skipping release: %s", item.title)
+				continue
+			}
+
+			if err := cacheRepo.Put(feed.ID, item.key, []byte(item.title), ttl); err != nil {
+				log.Error().Err(err).Str("entry", item.key).Msg("cache.Put: error storing item in cache")
+				continue
+			}
+
+			if !feed.Settings.TitleAllowed(item.title) {
+				log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", item.title)
+				continue
+			}
+
+			releases = append(releases, item.release)
+			processed++
+		}
+
+		log.Debug().Msgf("backfill feed: %s page %d, found (%d) items to process", feed.Name, page, len(releases))
+
+		// process the page before the next one so a large backfill does not flood the filters and clients
+		if len(releases) > 0 {
+			releaseSvc.ProcessMultiple(releases)
+		}
+
+		if tooOld {
+			break
+		}
+	}
+
+	return processed, nil
+}
+
+func (j *TorznabJob) backfillPage(ctx context.Context, page int, offset int) ([]backfillItem, error) {
+	feed, err := j.Client.FetchFeedPage(ctx, offset, backfillPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		items = append(items, backfillItem{
+			key:       item.GUID,
+			title:     item.Title,
+			published: item.PubDate.Time,
+			release:   j.mapRelease(*item),
+		})
+	}
+
+	return items, nil
+}
+
+func (j *NewznabJob) backfillPage(ctx context.Context, page int, offset int) ([]backfillItem, error) {
+	feed, err := j.Client.GetFeedPage(ctx, offset, backfillPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		items = append(items, backfillItem{
+			key:       item.GUID,
+			title:     item.Title,
+			published: item.PubDate.Time,
+			release:   j.mapRelease(*item),
+		})
+	}
+
+	return items, nil
+}
+
+func (j *JSONJob) backfillPage(ctx context.Context, page int, offset int) ([]backfillItem, error) {
+	settings := j.settings()
+	if settings.PageParam == "" {
+		return nil, errors.New("json feed without page parameter can not be backfilled")
+	}
+
+	pageItems, _, _, err := j.fetchPage(ctx, settings.PageStart+page, feedValidators{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(pageItems))
+	for _, item := range pageItems {
+		published, _ := parseJSONTime(jsonValue(item, settings.Fields.Published))
+
+		items = append(items, backfillItem{
+			key:       j.itemKey(item),
+			title:     jsonValue(item, settings.Fields.Title),
+			published: published,
+			release:   j.mapItem(item),
+		})
+	}
+
+	return items, nil
+}
+
+// Backfill starts a backfill of the feed with the settings, or else the backfill settings of the feed. It runs in the background,
+// ErrFeedBackfillRunning is returned when the feed is already backfilling.
+func (s *service) Backfill(ctx context.Context, id int, settings *domain.FeedBackfillSettings) error {
+	f, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msg("error finding feed")
+		return err
+	}
+
+	if !f.CanBackfill() {
+		return errors.New("%s feed can not be backfilled", f.Type)
+	}
+
+	opts := domain.FeedBackfillSettings{}
+	if settings != nil {
+		opts = *settings
+	} else if f.Settings != nil && f.Settings.Backfill != nil {
+		opts = *f.Settings.Backfill
+	}
+
+	if err := opts.Validate(); err != nil {
+		return errors.Wrap(err, "invalid backfill settings")
+	}
+
+	job, err := s.newFeedJob(f)
+	if err != nil {
+		return err
+	}
+
+	return s.startBackfill(f, job, opts.WithDefaults())
+}
+
+// backfillFirstRun backfills the feed when it never ran before
+func (s *service) backfillFirstRun(f *domain.Feed, job cron.Job) {
+	// the feed may come from an update request without its last run
+	stored, err := s.repo.FindByID(context.Background(), f.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("error finding feed: %s", f.Name)
+		return
+	}
+
+	if !stored.LastRun.IsZero() {
+		return
+	}
+
+	// mark the feed as run so the backfill does not start again on the next restart
+	if err := s.repo.UpdateLastRun(context.Background(), f.ID); err != nil {
+		s.log.Error().Err(err).Msgf("error updating last run for feed id: %v", f.ID)
+	}
+
+	if err := s.startBackfill(f, job, f.Settings.Backfill.WithDefaults()); err != nil {
+		s.log.Error().Err(err).Msgf("could not start backfill of feed: %s", f.Name)
+	}
+}
+
+func (s *service) startBackfill(f *domain.Feed, job cron.Job, settings domain.FeedBackfillSettings) error {
+	b, ok := job.(backfiller)
+	if !ok {
+		return errors.New("%s feed can not be backfilled", f.Type)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, running := s.backfills[f.ID]; running {
+		return domain.ErrFeedBackfillRunning
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.backfills[f.ID] = cancel
+
+	l := s.log.With().Str("feed", f.Name).Logger()
+
+	go func() {
+		defer func() {
+			cancel()
+
+			s.m.Lock()
+			delete(s.backfills, f.ID)
+			s.m.Unlock()
+		}()
+
+		l.Info().Msgf("starting backfill of up to %d items", settings.Count)
+
+		processed, err := runBackfill(ctx, l, f, s.feedCache(f), s.releaseSvc, settings, b)
+		if err != nil {
+			l.Error().Err(err).Msgf("backfill stopped after (%d) items", processed)
+			return
+		}
+
+		l.Info().Msgf("backfill done, processed (%d) items", processed)
+	}()
+
+	return nil
+}
+
+// cancelBackfill stops a running backfill of the feed
+func (s *service) cancelBackfill(id int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if cancel, ok := s.backfills[id]; ok {
+		cancel()
+	}
+}
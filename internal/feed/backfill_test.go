@@ -0,0 +1,113 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCacheRepo struct {
+	domain.FeedCacheRepo
+	items map[string]bool
+}
+
+func (r *testCacheRepo) Exists(feedId int, key string) (bool, error) {
+	return r.items[key], nil
+}
+
+func (r *testCacheRepo) Put(feedId int, key string, val []byte, ttl time.Time) error {
+	r.items[key] = true
+	return nil
+}
+
+type testReleaseSvc struct {
+	release.Service
+	processed []*domain.Release
+}
+
+func (s *testReleaseSvc) ProcessMultiple(releases []*domain.Release) {
+	s.processed = append(s.processed, releases...)
+}
+
+// testBackfiller returns pages of items published an hour apart, newest first
+type testBackfiller struct {
+	pages    int
+	pageSize int
+	requests int
+}
+
+func (b *testBackfiller) backfillPage(ctx context.Context, page int, offset int) ([]backfillItem, error) {
+	b.requests++
+
+	if page >= b.pages {
+		return nil, nil
+	}
+
+	items := make([]backfillItem, 0, b.pageSize)
+	for i := 0; i < b.pageSize; i++ {
+		n := offset + i
+		items = append(items, backfillItem{
+			key:       fmt.Sprintf("guid-%d", n),
+			title:     fmt.Sprintf("Release.%d", n),
+			published: time.Now().Add(-time.Duration(n) * time.Hour),
+			release:   domain.NewRelease("mock-feed"),
+		})
+	}
+
+	return items, nil
+}
+
+func Test_runBackfill(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     domain.FeedBackfillSettings
+		cached       []string
+		want         int
+		wantRequests int
+	}{
+		{name: "all_pages", settings: domain.FeedBackfillSettings{Count: 100}, want: 30, wantRequests: 4},
+		{name: "count", settings: domain.FeedBackfillSettings{Count: 15}, want: 15, wantRequests: 2},
+		{name: "max_age", settings: domain.FeedBackfillSettings{Count: 100, MaxAge: 12*3600 - 60}, want: 12, wantRequests: 2},
+		{name: "skip_cached", settings: domain.FeedBackfillSettings{Count: 100}, cached: []string{"guid-0", "guid-11"}, want: 28, wantRequests: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheRepo := &testCacheRepo{items: map[string]bool{}}
+			for _, key := range tt.cached {
+				cacheRepo.items[key] = true
+			}
+
+			releaseSvc := &testReleaseSvc{}
+			b := &testBackfiller{pages: 3, pageSize: 10}
+
+			got, err := runBackfill(context.Background(), zerolog.Nop(), &domain.Feed{ID: 1, Name: "test feed"}, cacheRepo, releaseSvc, tt.settings, b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, releaseSvc.processed, tt.want)
+			assert.Equal(t, tt.wantRequests, b.requests)
+		})
+	}
+}
+
+func Test_runBackfill_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	releaseSvc := &testReleaseSvc{}
+	b := &testBackfiller{pages: 3, pageSize: 10}
+
+	got, err := runBackfill(ctx, zerolog.Nop(), &domain.Feed{ID: 1}, &testCacheRepo{items: map[string]bool{}}, releaseSvc, domain.FeedBackfillSettings{Count: 100, Delay: 60}, b)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 10, got)
+	assert.Equal(t, 1, b.requests)
+}
@@ -14,22 +14,39 @@ import (
 
 type CleanupJob struct {
 	log       zerolog.Logger
+	config    *domain.Config
 	cacheRepo domain.FeedCacheRepo
 
 	CronSchedule time.Duration
 }
 
-func NewCleanupJob(log zerolog.Logger, cacheRepo domain.FeedCacheRepo) *CleanupJob {
+func NewCleanupJob(log zerolog.Logger, config *domain.Config, cacheRepo domain.FeedCacheRepo) *CleanupJob {
 	return &CleanupJob{
 		log:       log,
+		config:    config,
 		cacheRepo: cacheRepo,
 	}
 }
 
 func (j *CleanupJob) Run() {
-	if err := j.cacheRepo.DeleteStale(context.Background()); err != nil {
+	ctx := context.Background()
+
+	if err := j.cacheRepo.DeleteStale(ctx); err != nil {
 		j.log.Error().Err(err).Msg("error when running feed cache cleanup job")
 	}
 
+	// apply the retention of the config
+	if j.config != nil && j.config.FeedCacheMaxAgeDays > 0 {
+		if _, err := j.cacheRepo.DeleteOlderThan(ctx, j.config.FeedCacheMaxAgeDays); err != nil {
+			j.log.Error().Err(err).Msg("error deleting feed cache items older than max age")
+		}
+	}
+
+	if j.config != nil && j.config.FeedCacheMaxEntries > 0 {
+		if _, err := j.cacheRepo.DeleteOverLimit(ctx, j.config.FeedCacheMaxEntries); err != nil {
+			j.log.Error().Err(err).Msg("error deleting feed cache items over max entries")
+		}
+	}
+
 	j.log.Info().Msg("successfully ran feed-cache-cleanup job")
 }
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type cleanupCacheRepo struct {
+	domain.FeedCacheRepo
+	stale      int
+	maxAge     int
+	maxEntries int
+}
+
+func (r *cleanupCacheRepo) DeleteStale(ctx context.Context) error {
+	r.stale++
+	return nil
+}
+
+func (r *cleanupCacheRepo) DeleteOlderThan(ctx context.Context, days int) (int64, error) {
+	r.maxAge = days
+	return 0, nil
+}
+
+func (r *cleanupCacheRepo) DeleteOverLimit(ctx context.Context, maxEntries int) (int64, error) {
+	r.maxEntries = maxEntries
+	return 0, nil
+}
+
+func TestCleanupJob_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *domain.Config
+		wantMaxAge     int
+		wantMaxEntries int
+	}{
+		{name: "retention", config: &domain.Config{FeedCacheMaxAgeDays: 14, FeedCacheMaxEntries: 2000}, wantMaxAge: 14, wantMaxEntries: 2000},
+		{name: "unlimited", config: &domain.Config{}},
+		{name: "no_config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &cleanupCacheRepo{}
+
+			NewCleanupJob(zerolog.Nop(), tt.config, repo).Run()
+
+			assert.Equal(t, 1, repo.stale)
+			assert.Equal(t, tt.wantMaxAge, repo.maxAge)
+			assert.Equal(t, tt.wantMaxEntries, repo.maxEntries)
+		})
+	}
+}
@@ -5,15 +5,42 @@ package feed
 
 import (
 	"context"
-	"crypto/tls"
 	"net/http"
 	"net/http/cookiejar"
 	"time"
 
+	"github.com/autobrr/autobrr/pkg/errors"
+
 	"github.com/mmcdole/gofeed"
 	"golang.org/x/net/publicsuffix"
 )
 
+// errNotModified is returned by conditional requests of feeds that did not change since the last run
+var errNotModified = errors.Sentinel("feed not modified")
+
+// feedValidators are the ETag and Last-Modified of the last response of a feed
+type feedValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// setHeaders makes the request conditional, so unchanged feeds answer 304 without a body
+func (v feedValidators) setHeaders(req *http.Request) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+func responseValidators(resp *http.Response) feedValidators {
+	return feedValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
 type RSSParser struct {
 	parser *gofeed.Parser
 	http   *http.Client
@@ -26,11 +53,9 @@ func NewFeedParser(timeout time.Duration, cookie string) *RSSParser {
 	jarOptions := &cookiejar.Options{PublicSuffixList: publicsuffix.List}
 	jar, _ := cookiejar.New(jarOptions)
 
-	customTransport := http.DefaultTransport.(*http.Transport).Clone()
-	customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	httpClient := &http.Client{
 		Timeout:   time.Second * 60,
-		Transport: customTransport,
+		Transport: sharedFeedTransport(),
 		Jar:       jar,
 	}
 
@@ -45,13 +70,30 @@ func NewFeedParser(timeout time.Duration, cookie string) *RSSParser {
 	return c
 }
 
+// WithTransport requests the feed with the transport, nil keeps the default
+func (c *RSSParser) WithTransport(transport http.RoundTripper) *RSSParser {
+	if transport != nil {
+		c.http.Transport = transport
+	}
+
+	return c
+}
+
 func (c *RSSParser) ParseURLWithContext(ctx context.Context, feedURL string) (feed *gofeed.Feed, err error) {
+	feed, _, err = c.ParseURLIfModified(ctx, feedURL, feedValidators{})
+	return feed, err
+}
+
+// ParseURLIfModified requests the feed conditionally with the validators of the last response and returns the feed
+// with the validators of this response, or errNotModified when the feed did not change
+func (c *RSSParser) ParseURLIfModified(ctx context.Context, feedURL string, validators feedValidators) (feed *gofeed.Feed, next feedValidators, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, next, err
 	}
 
 	req.Header.Set("User-Agent", "Gofeed/1.0")
+	validators.setHeaders(req)
 
 	if c.cookie != "" {
 		// set raw cookie as header
@@ -60,7 +102,7 @@ func (c *RSSParser) ParseURLWithContext(ctx context.Context, feedURL string) (fe
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, next, err
 	}
 
 	if resp != nil {
@@ -72,12 +114,21 @@ func (c *RSSParser) ParseURLWithContext(ctx context.Context, feedURL string) (fe
 		}()
 	}
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, validators, errNotModified
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, gofeed.HTTPError{
+		return nil, next, gofeed.HTTPError{
 			StatusCode: resp.StatusCode,
 			Status:     resp.Status,
 		}
 	}
 
-	return c.parser.Parse(resp.Body)
+	feed, err = c.parser.Parse(resp.Body)
+	if err != nil {
+		return nil, next, err
+	}
+
+	return feed, responseValidators(resp), nil
 }
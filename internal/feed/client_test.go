@@ -0,0 +1,45 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSSParser_ParseURLIfModified(t *testing.T) {
+	const etag = `"v1"`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>test</title><item><title>Some.Release-GROUP</title><guid>1</guid></item></channel></rss>`))
+	}))
+	defer srv.Close()
+
+	parser := NewFeedParser(time.Minute, "")
+
+	feed, validators, err := parser.ParseURLIfModified(context.Background(), srv.URL, feedValidators{})
+	assert.NoError(t, err)
+	assert.Len(t, feed.Items, 1)
+	assert.Equal(t, feedValidators{ETag: etag, LastModified: "Wed, 21 Oct 2015 07:28:00 GMT"}, validators)
+
+	feed, next, err := parser.ParseURLIfModified(context.Background(), srv.URL, validators)
+	assert.True(t, errors.Is(err, errNotModified))
+	assert.Nil(t, feed)
+	assert.Equal(t, validators, next)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// lastValidators returns the validators of the last response of the feed
+func lastValidators(feed *domain.Feed) feedValidators {
+	return feedValidators{ETag: feed.ETag, LastModified: feed.LastModified}
+}
+
+// storeValidators remembers the validators of the response for the next conditional request of the feed
+func storeValidators(ctx context.Context, repo domain.FeedRepo, feed *domain.Feed, log zerolog.Logger, validators feedValidators) {
+	if validators == lastValidators(feed) {
+		return
+	}
+
+	feed.ETag = validators.ETag
+	feed.LastModified = validators.LastModified
+
+	if err := repo.UpdateValidators(ctx, feed.ID, validators.ETag, validators.LastModified); err != nil {
+		log.Error().Err(err).Msgf("error updating validators for feed id: %v", feed.ID)
+	}
+}
+
+// feedNotModified records the run of a feed that did not change since the last run
+func feedNotModified(ctx context.Context, repo domain.FeedRepo, feed *domain.Feed, log zerolog.Logger) {
+	log.Debug().Msgf("feed not modified since last run: %s", feed.Name)
+
+	if err := repo.UpdateLastRun(ctx, feed.ID); err != nil {
+		log.Error().Err(err).Msgf("error updating last run for feed id: %v", feed.ID)
+	}
+}
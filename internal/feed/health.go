@@ -0,0 +1,169 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// feedProcessor is implemented by the feed jobs, process refreshes the feed once
+type feedProcessor interface {
+	process(ctx context.Context) error
+}
+
+// healthJob refreshes the feed and records its health. After errors in a row it skips the refreshes in its backoff,
+// the first refresh that succeeds again restores the schedule of the feed.
+type healthJob struct {
+	svc      *service
+	feed     *domain.Feed
+	job      feedProcessor
+	interval time.Duration
+	log      zerolog.Logger
+
+	now func() time.Time
+}
+
+func (j *healthJob) Run() {
+	now := j.now()
+
+	if health := j.svc.feedHealth(j.feed.ID); health != nil && health.InBackoff(now) {
+		j.log.Debug().Msgf("feed refresh skipped, backing off after %d errors until %s", health.Errors, health.BackoffUntil.Format(time.RFC3339))
+		return
+	}
+
+	if gate := j.svc.indexerGate(j.feed.Indexer); gate != nil {
+		if err := gate.acquire(context.Background(), j.feed.Priority); err != nil {
+			return
+		}
+		defer gate.release()
+	}
+
+	done, err := j.svc.fetches.acquire(context.Background(), j.svc.feedConcurrency())
+	if err != nil {
+		return
+	}
+	defer done()
+
+	// the refresh starts once the feed got its turn
+	now = j.now()
+
+	run := j.svc.startRun(j.feed.ID, now)
+
+	err = j.job.process(withFeedRun(context.Background(), run))
+	if err != nil {
+		j.log.Error().Err(err).Msg("feed process error")
+	}
+
+	j.svc.finishRun(run, err, j.now())
+	j.svc.recordRun(j.feed, err, j.interval, now)
+}
+
+// feedHealth returns a copy of the health of the feed, nil when it did not run yet
+func (s *service) feedHealth(id int) *domain.FeedHealth {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	health, ok := s.health[id]
+	if !ok {
+		return nil
+	}
+
+	h := *health
+	return &h
+}
+
+// recordRun records the result of a refresh of the feed and notifies when it has been failing for too long or recovered
+func (s *service) recordRun(f *domain.Feed, err error, interval time.Duration, now time.Time) {
+	s.m.Lock()
+
+	health, ok := s.health[f.ID]
+	if !ok {
+		health = domain.NewFeedHealth()
+		s.health[f.ID] = health
+	}
+
+	notify := false
+	recovered := false
+
+	if err != nil {
+		health.RecordError(err, now, interval)
+
+		if health.ShouldNotify(now, f.Settings.FailingAlertDuration()) {
+			health.Notified = true
+			notify = true
+		}
+	} else {
+		// only feeds notified as failing are notified as recovered
+		recovered = health.Notified
+		notify = recovered
+
+		if health.RecordSuccess() {
+			s.log.Info().Msgf("feed recovered: %s", f.Name)
+		}
+
+		health.Notified = false
+	}
+
+	h := *health
+	s.m.Unlock()
+
+	if h.Status == domain.FeedHealthFailing {
+		s.log.Warn().Msgf("feed failing: %s, %d errors in a row, next refresh after %s", f.Name, h.Errors, h.BackoffUntil.Format(time.RFC3339))
+	}
+
+	if notify && s.notificationSvc != nil {
+		event, payload := domain.FeedHealthNotification(f, &h, recovered)
+		s.notificationSvc.Send(event, payload)
+	}
+}
+
+// recordAuth records whether the feed was authorized, and notifies once when it is refused even after logging in
+// again or its login fails, until it is authorized again
+func (s *service) recordAuth(f *domain.Feed, err error) {
+	s.m.Lock()
+
+	if s.authFailed == nil {
+		s.authFailed = map[int]bool{}
+	}
+
+	failed := s.authFailed[f.ID]
+
+	if err == nil {
+		delete(s.authFailed, f.ID)
+		s.m.Unlock()
+
+		if failed {
+			s.log.Info().Msgf("feed authorized again: %s", f.Name)
+		}
+		return
+	}
+
+	s.authFailed[f.ID] = true
+	s.m.Unlock()
+
+	if failed {
+		return
+	}
+
+	s.log.Error().Err(err).Msgf("feed authentication failed: %s", f.Name)
+
+	if s.notificationSvc != nil {
+		event, payload := domain.FeedAuthFailedNotification(f, err)
+		s.notificationSvc.Send(event, payload)
+	}
+}
+
+// resetHealth forgets the health of the feed when its job is stopped
+func (s *service) resetHealth(id int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.health, id)
+	delete(s.authFailed, id)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/notification"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProcessor struct {
+	err   error
+	calls int
+}
+
+func (p *testProcessor) process(ctx context.Context) error {
+	p.calls++
+	return p.err
+}
+
+type testNotificationSvc struct {
+	notification.Service
+	events []domain.NotificationEvent
+}
+
+func (s *testNotificationSvc) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+	s.events = append(s.events, event)
+}
+
+func TestHealthJob_Run(t *testing.T) {
+	notifications := &testNotificationSvc{}
+	svc := &service{
+		log:             zerolog.Nop(),
+		notificationSvc: notifications,
+		health:          map[int]*domain.FeedHealth{},
+	}
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	processor := &testProcessor{err: errors.New("connection refused")}
+	feed := &domain.Feed{ID: 1, Name: "test feed", Settings: &domain.FeedSettingsJSON{FailingAlertAfter: 30}}
+
+	job := &healthJob{
+		svc:      svc,
+		feed:     feed,
+		job:      processor,
+		interval: 10 * time.Minute,
+		log:      zerolog.Nop(),
+		now:      func() time.Time { return now },
+	}
+
+	// run every 10 minutes, skipping the runs in the backoff
+	for i := 0; i < 6; i++ {
+		job.Run()
+		now = now.Add(10 * time.Minute)
+	}
+
+	// errors at 0, 10 and 30 minutes, backing off until 70 minutes
+	assert.Equal(t, 3, processor.calls)
+	assert.Equal(t, domain.FeedHealthFailing, svc.feedHealth(1).Status)
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventFeedFailing}, notifications.events)
+
+	now = now.Add(10 * time.Minute)
+	processor.err = nil
+	job.Run()
+
+	assert.Equal(t, 4, processor.calls)
+	assert.Equal(t, domain.FeedHealthOK, svc.feedHealth(1).Status)
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventFeedFailing, domain.NotificationEventFeedRecovered}, notifications.events)
+}
+
+func Test_service_recordAuth(t *testing.T) {
+	notifications := &testNotificationSvc{}
+	svc := &service{log: zerolog.Nop(), notificationSvc: notifications}
+	feed := &domain.Feed{ID: 1, Name: "test feed"}
+
+	// notified once while it keeps failing
+	svc.recordAuth(feed, errors.New("feed answered with status: 401"))
+	svc.recordAuth(feed, errors.New("feed answered with status: 401"))
+	assert.Equal(t, []domain.NotificationEvent{domain.NotificationEventFeedAuthFailed}, notifications.events)
+
+	// and again after it was authorized in between
+	svc.recordAuth(feed, nil)
+	svc.recordAuth(feed, errors.New("feed answered with status: 401"))
+	assert.Len(t, notifications.events, 2)
+}
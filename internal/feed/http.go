@@ -0,0 +1,213 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	// defaultFeedTimeout is the timeout of a refresh of feeds without one
+	defaultFeedTimeout = 60 * time.Second
+
+	// feedIdleConnsPerHost are the connections kept open per host, feeds of an indexer share them
+	feedIdleConnsPerHost = 8
+
+	// feedAcceptEncoding are the encodings decoded by decodeTransport. Brotli is not advertised, there is no
+	// decoder for it in the standard library.
+	feedAcceptEncoding = "gzip, deflate"
+)
+
+var (
+	sharedTransportOnce sync.Once
+	sharedTransport     *http.Transport
+)
+
+// sharedFeedTransport returns the transport of the feeds without a proxy. It is shared by all feeds so connections
+// are kept alive across refreshes and feeds of the same host, and HTTP/2 is used where the server supports it.
+func sharedFeedTransport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		// a custom tls config turns off http/2 unless forced
+		t.ForceAttemptHTTP2 = true
+		t.MaxIdleConnsPerHost = feedIdleConnsPerHost
+
+		sharedTransport = t
+	})
+
+	return sharedTransport
+}
+
+// feedTimeout returns the timeout of a refresh of the feed
+func feedTimeout(f *domain.Feed) time.Duration {
+	if f.Timeout <= 0 {
+		return defaultFeedTimeout
+	}
+
+	return time.Duration(f.Timeout) * time.Second
+}
+
+// decodeTransport asks for compressed responses and decodes gzip and deflate bodies. The standard transport only
+// decodes gzip, and not when the request sets its own Accept-Encoding like feeds with extra headers can.
+type decodeTransport struct {
+	base http.RoundTripper
+}
+
+func (t *decodeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", feedAcceptEncoding)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Uncompressed || req.Method == http.MethodHead {
+		return resp, nil
+	}
+
+	var body io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		body = &lazyReader{body: resp.Body, open: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }}
+
+	case "deflate":
+		body = &lazyReader{body: resp.Body, open: func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) }}
+
+	default:
+		return resp, nil
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// lazyReader opens the decoder on the first read, so reading the header of the stream does not block the response
+type lazyReader struct {
+	body io.ReadCloser
+	open func(r io.Reader) (io.ReadCloser, error)
+
+	decoder io.ReadCloser
+	err     error
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.decoder == nil && r.err == nil {
+		decoder, err := r.open(r.body)
+		switch {
+		case err == io.EOF:
+			r.err = err
+		case err != nil:
+			r.err = errors.Wrap(err, "could not decode response")
+		default:
+			r.decoder = decoder
+		}
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return r.decoder.Read(p)
+}
+
+func (r *lazyReader) Close() error {
+	if r.decoder != nil {
+		r.decoder.Close()
+	}
+
+	return r.body.Close()
+}
+
+// fetchLimiter bounds the feeds refreshing at once, so feeds sharing an interval do not all fetch in the same second.
+// Waiting feeds go in the order they started waiting.
+type fetchLimiter struct {
+	m       sync.Mutex
+	limit   int
+	running int
+	waiting []chan struct{}
+}
+
+// acquire waits for a free slot of the limit and returns the func that frees it, the limit is not applied when 0.
+// It returns the error of the context when it is done first.
+func (l *fetchLimiter) acquire(ctx context.Context, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	l.m.Lock()
+	l.limit = limit
+	if l.running < limit {
+		l.running++
+		l.m.Unlock()
+		return l.release, nil
+	}
+
+	ready := make(chan struct{})
+	l.waiting = append(l.waiting, ready)
+	l.m.Unlock()
+
+	select {
+	case <-ready:
+		return l.release, nil
+
+	case <-ctx.Done():
+		l.m.Lock()
+		for i, w := range l.waiting {
+			if w == ready {
+				l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+				l.m.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+		l.m.Unlock()
+
+		// the slot was handed over while giving up, pass it on
+		l.release()
+		return nil, ctx.Err()
+	}
+}
+
+// release hands the slot to the first waiting feed, or frees it when the limit was lowered or nobody waits
+func (l *fetchLimiter) release() {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if len(l.waiting) > 0 && l.running <= l.limit {
+		ready := l.waiting[0]
+		l.waiting = l.waiting[1:]
+		close(ready)
+		return
+	}
+
+	l.running--
+}
+
+// feedConcurrency returns the number of feeds refreshing at once, 0 is not limited
+func (s *service) feedConcurrency() int {
+	if s.config == nil {
+		return 0
+	}
+
+	return s.config.FeedConcurrency
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept := r.Header.Get("Accept-Encoding")
+
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte("gzip " + accept))
+			zw.Close()
+
+		case "/deflate":
+			w.Header().Set("Content-Encoding", "deflate")
+			zw := zlib.NewWriter(w)
+			zw.Write([]byte("deflate " + accept))
+			zw.Close()
+
+		case "/empty":
+			w.Header().Set("Content-Encoding", "gzip")
+
+		default:
+			w.Write([]byte("plain " + accept))
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &decodeTransport{base: http.DefaultTransport}}
+
+	get := func(path string, header string) string {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		assert.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Accept-Encoding", header)
+		}
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+
+		return string(body)
+	}
+
+	assert.Equal(t, "gzip gzip, deflate", get("/gzip", ""))
+	assert.Equal(t, "deflate gzip, deflate", get("/deflate", ""))
+	assert.Equal(t, "plain gzip, deflate", get("/plain", ""))
+	assert.Equal(t, "", get("/empty", ""))
+
+	// the Accept-Encoding of feeds with extra headers is kept and the response still decoded
+	assert.Equal(t, "gzip gzip", get("/gzip", "gzip"))
+}
+
+func Test_feedTimeout(t *testing.T) {
+	assert.Equal(t, defaultFeedTimeout, feedTimeout(&domain.Feed{}))
+	assert.Equal(t, 30*time.Second, feedTimeout(&domain.Feed{Timeout: 30}))
+}
+
+func Test_sharedFeedTransport(t *testing.T) {
+	transport := sharedFeedTransport()
+
+	assert.Same(t, transport, sharedFeedTransport())
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func Test_fetchLimiter(t *testing.T) {
+	var l fetchLimiter
+
+	first, err := l.acquire(context.Background(), 2)
+	assert.NoError(t, err)
+	second, err := l.acquire(context.Background(), 2)
+	assert.NoError(t, err)
+
+	// the third waits for a slot
+	acquired := make(chan func())
+	go func() {
+		done, err := l.acquire(context.Background(), 2)
+		assert.NoError(t, err)
+		acquired <- done
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired over the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first()
+
+	var third func()
+	select {
+	case third = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("slot not handed over")
+	}
+
+	// a canceled wait gives up
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx, 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	second()
+	third()
+	assert.Equal(t, 0, l.running)
+	assert.Empty(t, l.waiting)
+
+	// no limit
+	for i := 0; i < 10; i++ {
+		done, err := l.acquire(context.Background(), 0)
+		assert.NoError(t, err)
+		defer done()
+	}
+	assert.Equal(t, 0, l.running)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// indexerGate lets the feeds of an indexer refresh one at a time, so two feeds never both find an item missing
+// from their shared cache. The waiting feed with the highest priority goes next.
+type indexerGate struct {
+	m       sync.Mutex
+	busy    bool
+	waiting []*gateWaiter
+}
+
+type gateWaiter struct {
+	priority int
+	ready    chan struct{}
+}
+
+// acquire waits for the gate, it returns the error of the context when it is done first
+func (g *indexerGate) acquire(ctx context.Context, priority int) error {
+	g.m.Lock()
+	if !g.busy {
+		g.busy = true
+		g.m.Unlock()
+		return nil
+	}
+
+	w := &gateWaiter{priority: priority, ready: make(chan struct{})}
+	g.waiting = append(g.waiting, w)
+	g.m.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+
+	case <-ctx.Done():
+		g.m.Lock()
+		for i, other := range g.waiting {
+			if other == w {
+				g.waiting = append(g.waiting[:i], g.waiting[i+1:]...)
+				g.m.Unlock()
+				return ctx.Err()
+			}
+		}
+		g.m.Unlock()
+
+		// the gate was handed over while giving up, pass it on
+		g.release()
+		return ctx.Err()
+	}
+}
+
+// release hands the gate to the waiting feed with the highest priority, the first of them to wait
+func (g *indexerGate) release() {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if len(g.waiting) == 0 {
+		g.busy = false
+		return
+	}
+
+	next := 0
+	for i, w := range g.waiting {
+		if w.priority > g.waiting[next].priority {
+			next = i
+		}
+	}
+
+	w := g.waiting[next]
+	g.waiting = append(g.waiting[:next], g.waiting[next+1:]...)
+	close(w.ready)
+}
+
+// indexerGate returns the gate of the feeds of the indexer, nil for a feed without indexer
+func (s *service) indexerGate(indexer string) *indexerGate {
+	if indexer == "" {
+		return nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.gates == nil {
+		s.gates = map[string]*indexerGate{}
+	}
+
+	g, ok := s.gates[indexer]
+	if !ok {
+		g = &indexerGate{}
+		s.gates[indexer] = g
+	}
+
+	return g
+}
+
+// indexerFeedIDs returns the ids of the feeds of the indexer, loaded once and again after feeds are changed
+func (s *service) indexerFeedIDs(indexer string) []int {
+	s.m.Lock()
+	loaded := s.indexerFeeds != nil
+	ids := s.indexerFeeds[indexer]
+	s.m.Unlock()
+
+	if loaded {
+		return ids
+	}
+
+	feeds, err := s.repo.Find(context.Background())
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not find the feeds of the indexers")
+		return nil
+	}
+
+	indexerFeeds := map[string][]int{}
+	for _, f := range feeds {
+		indexerFeeds[f.Indexer] = append(indexerFeeds[f.Indexer], f.ID)
+	}
+
+	s.m.Lock()
+	s.indexerFeeds = indexerFeeds
+	s.m.Unlock()
+
+	return indexerFeeds[indexer]
+}
+
+// resetIndexerFeeds reloads the feeds of the indexers on the next lookup
+func (s *service) resetIndexerFeeds() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.indexerFeeds = nil
+}
+
+// feedCache returns the cache of the feed, shared with the other feeds of its indexer
+func (s *service) feedCache(f *domain.Feed) domain.FeedCacheRepo {
+	if f.Indexer == "" {
+		return s.cacheRepo
+	}
+
+	return &sharedFeedCache{
+		FeedCacheRepo: s.cacheRepo,
+		feedIDs: func() []int {
+			return s.indexerFeedIDs(f.Indexer)
+		},
+	}
+}
+
+// sharedFeedCache is the cache of a feed that also finds the items cached by the other feeds of its indexer,
+// so an item is processed by one of them only. Items are stored in the cache of the feed itself.
+type sharedFeedCache struct {
+	domain.FeedCacheRepo
+
+	feedIDs func() []int
+}
+
+func (c *sharedFeedCache) Exists(feedId int, key string) (bool, error) {
+	exists, err := c.FeedCacheRepo.Exists(feedId, key)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	for _, id := range c.feedIDs() {
+		if id == feedId {
+			continue
+		}
+
+		exists, err := c.FeedCacheRepo.Exists(id, key)
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type feedIDCacheRepo struct {
+	domain.FeedCacheRepo
+	items map[int]map[string]bool
+}
+
+func (r *feedIDCacheRepo) Exists(feedId int, key string) (bool, error) {
+	return r.items[feedId][key], nil
+}
+
+func Test_sharedFeedCache_Exists(t *testing.T) {
+	repo := &feedIDCacheRepo{items: map[int]map[string]bool{
+		1: {"a": true},
+		2: {"b": true},
+		3: {"c": true},
+	}}
+
+	cache := &sharedFeedCache{FeedCacheRepo: repo, feedIDs: func() []int { return []int{1, 2} }}
+
+	for key, want := range map[string]bool{"a": true, "b": true, "c": false, "d": false} {
+		exists, err := cache.Exists(1, key)
+		assert.NoError(t, err)
+		assert.Equal(t, want, exists, key)
+	}
+}
+
+func Test_indexerGate_priority(t *testing.T) {
+	g := &indexerGate{}
+	ctx := context.Background()
+
+	assert.NoError(t, g.acquire(ctx, 0))
+
+	order := make(chan int, 3)
+	for _, priority := range []int{1, 5, 3} {
+		priority := priority
+		go func() {
+			if err := g.acquire(ctx, priority); err == nil {
+				order <- priority
+				g.release()
+			}
+		}()
+
+		// wait until it is queued
+		assert.Eventually(t, func() bool {
+			g.m.Lock()
+			defer g.m.Unlock()
+			return len(g.waiting) > 0 && g.waiting[len(g.waiting)-1].priority == priority
+		}, time.Second, time.Millisecond)
+	}
+
+	g.release()
+
+	assert.Equal(t, 5, <-order)
+	assert.Equal(t, 3, <-order)
+	assert.Equal(t, 1, <-order)
+}
+
+func Test_indexerGate_cancel(t *testing.T) {
+	g := &indexerGate{}
+	assert.NoError(t, g.acquire(context.Background(), 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, g.acquire(ctx, 1), context.DeadlineExceeded)
+	assert.Empty(t, g.waiting)
+
+	g.release()
+	assert.False(t, g.busy)
+}
@@ -0,0 +1,478 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jsonpath"
+
+	"github.com/rs/zerolog"
+)
+
+type JSONJob struct {
+	Feed              *domain.Feed
+	Name              string
+	IndexerIdentifier string
+	Log               zerolog.Logger
+	URL               string
+	Repo              domain.FeedRepo
+	CacheRepo         domain.FeedCacheRepo
+	ReleaseSvc        release.Service
+	Timeout           time.Duration
+
+	http *http.Client
+
+	attempts int
+	errors   []error
+
+	JobID int
+}
+
+func NewJSONJob(feed *domain.Feed, name string, indexerIdentifier string, log zerolog.Logger, url string, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, timeout time.Duration) *JSONJob {
+	return &JSONJob{
+		Feed:              feed,
+		Name:              name,
+		IndexerIdentifier: indexerIdentifier,
+		Log:               log,
+		URL:               url,
+		Repo:              repo,
+		CacheRepo:         cacheRepo,
+		ReleaseSvc:        releaseSvc,
+		Timeout:           timeout,
+		http: &http.Client{
+			Timeout:   timeout,
+			Transport: sharedFeedTransport(),
+		},
+	}
+}
+
+// withTransport requests the feed with the transport, nil keeps the default
+func (j *JSONJob) withTransport(transport http.RoundTripper) {
+	if transport != nil {
+		j.http.Transport = transport
+	}
+}
+
+func (j *JSONJob) Run() {
+	ctx := context.Background()
+
+	if err := j.process(ctx); err != nil {
+		j.Log.Error().Err(err).Int("attempts", j.attempts).Msg("json feed process error")
+
+		j.errors = append(j.errors, err)
+		return
+	}
+
+	j.attempts = 0
+	j.errors = []error{}
+}
+
+func (j *JSONJob) process(ctx context.Context) error {
+	items, err := j.getFeed(ctx)
+	if err != nil {
+		j.Log.Error().Err(err).Msgf("error fetching json feed items")
+		return errors.Wrap(err, "error getting json feed items")
+	}
+
+	j.Log.Debug().Msgf("found (%d) new items to process", len(items))
+
+	feedRunFromContext(ctx).setNewItems(len(items))
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	releases := make([]*domain.Release, 0)
+
+	for _, item := range items {
+		rls := j.processItem(item)
+		if rls != nil {
+			releases = append(releases, rls)
+		}
+	}
+
+	// process all new releases
+	go processReleases(ctx, j.ReleaseSvc, releases)
+
+	return nil
+}
+
+func (j *JSONJob) settings() *domain.FeedJSONSettings {
+	if j.Feed.Settings == nil || j.Feed.Settings.JSON == nil {
+		return &domain.FeedJSONSettings{}
+	}
+
+	return j.Feed.Settings.JSON
+}
+
+// processItem maps the values of the json item to a release, nil when it is older than the max age of the feed
+func (j *JSONJob) processItem(item any) *domain.Release {
+	if j.Feed.MaxAge > 0 {
+		if published, ok := parseJSONTime(jsonValue(item, j.settings().Fields.Published)); ok {
+			if !isNewerThanMaxAge(j.Feed.MaxAge, published, time.Now()) {
+				return nil
+			}
+		}
+	}
+
+	return j.mapItem(item)
+}
+
+// mapItem maps the values of the json item to a release, nil when it has no title
+func (j *JSONJob) mapItem(item any) *domain.Release {
+	fields := j.settings().Fields
+
+	title := jsonValue(item, fields.Title)
+	if title == "" {
+		j.Log.Warn().Msgf("json item without title, check the title path %s", fields.Title)
+		return nil
+	}
+
+	rls := domain.NewRelease(j.IndexerIdentifier)
+	rls.Implementation = domain.ReleaseImplementationJSON
+
+	rls.ParseString(title)
+
+	if v := jsonValue(item, fields.DownloadURL); v != "" {
+		rls.DownloadURL = j.absoluteURL(v)
+	}
+
+	if v := jsonValue(item, fields.MagnetURI); v != "" {
+		rls.MagnetURI = v
+	}
+
+	if v := jsonValue(item, fields.InfoURL); v != "" {
+		rls.InfoURL = j.absoluteURL(v)
+	}
+
+	if v := jsonValue(item, fields.Size); v != "" {
+		rls.ParseSizeBytesString(v)
+	}
+
+	if categories := jsonValues(item, fields.Category); len(categories) > 0 {
+		rls.Categories = categories
+		rls.Category = strings.Join(categories, ", ")
+	}
+
+	if v := jsonValue(item, fields.InfoHash); v != "" {
+		rls.TorrentHash = v
+	}
+
+	if uploaders := jsonValues(item, fields.Uploader); len(uploaders) > 0 {
+		rls.Uploader = strings.Join(uploaders, ", ")
+	}
+
+	if fields.Freeleech != "" && isTruthy(jsonValue(item, fields.Freeleech)) {
+		rls.Freeleech = true
+		rls.Bonus = []string{"Freeleech"}
+	}
+
+	// add cookie to release for download if needed
+	if j.Feed.Cookie != "" {
+		rls.RawCookie = j.Feed.Cookie
+	}
+
+	return rls
+}
+
+// absoluteURL makes urls relative to the api absolute
+func (j *JSONJob) absoluteURL(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.IsAbs() {
+		return link
+	}
+
+	base, err := url.Parse(j.URL)
+	if err != nil {
+		return link
+	}
+
+	return base.ResolveReference(parsed).String()
+}
+
+// itemKey returns the key of the item in the feed cache
+func (j *JSONJob) itemKey(item any) string {
+	fields := j.settings().Fields
+
+	for _, path := range []string{fields.GUID, fields.DownloadURL, fields.MagnetURI, fields.Title} {
+		if v := jsonValue(item, path); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func (j *JSONJob) getFeed(ctx context.Context) ([]any, error) {
+	settings := j.settings()
+
+	pages := 1
+	if settings.PageParam != "" && settings.MaxPages > 1 {
+		pages = settings.MaxPages
+	}
+
+	// set ttl to 1 month
+	ttl := time.Now().AddDate(0, 1, 0)
+
+	items := make([]any, 0)
+
+	for page := settings.PageStart; page < settings.PageStart+pages; page++ {
+		// only the first page is requested conditionally, the next pages are only fetched when it changed
+		conditional := feedValidators{}
+		if page == settings.PageStart {
+			conditional = lastValidators(j.Feed)
+		}
+
+		pageItems, raw, validators, err := j.fetchPage(ctx, page, conditional)
+		if err != nil {
+			if errors.Is(err, errNotModified) {
+				feedNotModified(ctx, j.Repo, j.Feed, j.Log)
+				return items, nil
+			}
+
+			return nil, err
+		}
+
+		if page == settings.PageStart {
+			if err := j.Repo.UpdateLastRunWithData(ctx, j.Feed.ID, string(raw)); err != nil {
+				j.Log.Error().Err(err).Msgf("error updating last run for feed id: %v", j.Feed.ID)
+			}
+
+			storeValidators(ctx, j.Repo, j.Feed, j.Log, validators)
+		}
+
+		j.Log.Debug().Msgf("refreshing json feed: %v page %d, found (%d) items", j.Name, page, len(pageItems))
+
+		feedRunFromContext(ctx).addItems(len(pageItems))
+
+		newItems := 0
+		for _, item := range pageItems {
+			key := j.itemKey(item)
+			if key == "" {
+				continue
+			}
+
+			exists, err := j.CacheRepo.Exists(j.Feed.ID, key)
+			if err != nil {
+				j.Log.Error().Err(err).Msg("could not check if item exists")
+				continue
+			}
+			if exists {
+				j.Log.Trace().Msgf("cache item exists, skipping release: %s", key)
+				continue
+			}
+
+			title := jsonValue(item, settings.Fields.Title)
+
+			j.Log.Debug().Msgf("found new release: %s", title)
+
+			if err := j.CacheRepo.Put(j.Feed.ID, key, []byte(title), ttl); err != nil {
+				j.Log.Error().Err(err).Str("entry", key).Msg("cache.Put: error storing item in cache")
+				continue
+			}
+
+			newItems++
+
+			// excluded items are cached too, so they are not matched again and count as seen for the paging
+			if !j.Feed.Settings.TitleAllowed(title) {
+				j.Log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", title)
+				continue
+			}
+
+			// only append if we successfully added to cache
+			items = append(items, item)
+		}
+
+		// older pages were seen on earlier runs
+		if newItems == 0 {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// fetchPage requests the page of the api and returns its items, the raw response and its validators.
+// With validators of the last response the request is conditional and errNotModified is returned when it did not change.
+func (j *JSONJob) fetchPage(ctx context.Context, page int, validators feedValidators) ([]any, []byte, feedValidators, error) {
+	settings := j.settings()
+
+	itemsPath, err := jsonpath.Parse(settings.ItemsPath)
+	if err != nil {
+		return nil, nil, feedValidators{}, err
+	}
+
+	reqURL, err := url.Parse(j.URL)
+	if err != nil {
+		return nil, nil, feedValidators{}, errors.Wrap(err, "invalid url: %s", j.URL)
+	}
+
+	if settings.PageParam != "" {
+		q := reqURL.Query()
+		q.Set(settings.PageParam, strconv.Itoa(page))
+		if settings.PageSizeParam != "" && settings.PageSize > 0 {
+			q.Set(settings.PageSizeParam, strconv.Itoa(settings.PageSize))
+		}
+		reqURL.RawQuery = q.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, nil, feedValidators{}, errors.Wrap(err, "could not build request")
+	}
+
+	validators.setHeaders(req)
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	for name, value := range settings.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if j.Feed.Cookie != "" {
+		// set raw cookie as header
+		req.Header.Set("Cookie", j.Feed.Cookie)
+	}
+
+	resp, err := j.http.Do(req)
+	if err != nil {
+		return nil, nil, feedValidators{}, errors.Wrap(err, "error fetching json feed")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil, validators, errNotModified
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, feedValidators{}, errors.Wrap(err, "could not read response")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, feedValidators{}, errors.New("unexpected status: %d", resp.StatusCode)
+	}
+
+	items, err := parseJSONItems(raw, itemsPath)
+	if err != nil {
+		return nil, nil, feedValidators{}, err
+	}
+
+	return items, raw, responseValidators(resp), nil
+}
+
+// parseJSONItems decodes the response and returns the items the path selects, a selected array is flattened
+func parseJSONItems(raw []byte, itemsPath jsonpath.Path) ([]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "could not decode json response")
+	}
+
+	items := make([]any, 0)
+	for _, v := range itemsPath.Get(data) {
+		if arr, ok := v.([]any); ok {
+			items = append(items, arr...)
+			continue
+		}
+
+		items = append(items, v)
+	}
+
+	return items, nil
+}
+
+// jsonValues returns the values the path selects in the item as strings, empty when the path is invalid
+func jsonValues(item any, path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	p, err := jsonpath.Parse(path)
+	if err != nil {
+		return nil
+	}
+
+	var values []string
+	for _, v := range p.Get(item) {
+		// lists of values like tags or categories
+		if arr, ok := v.([]any); ok {
+			for _, e := range arr {
+				values = append(values, jsonString(e))
+			}
+			continue
+		}
+
+		values = append(values, jsonString(v))
+	}
+
+	return nonEmpty(values)
+}
+
+func jsonValue(item any, path string) string {
+	if values := jsonValues(item, path); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// jsonString formats a scalar json value, objects and null are empty
+func jsonString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+
+	return ""
+}
+
+// parseJSONTime parses the common date formats of json apis, and unix timestamps
+func parseJSONTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// timestamps in milliseconds
+		if ts > 1e12 {
+			return time.UnixMilli(ts), true
+		}
+
+		return time.Unix(ts, 0), true
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/jsonpath"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonFeedResponse = `{
+	"data": [
+		{
+			"id": 1001,
+			"name": "Some.Release.Title.2022.09.22.720p.WEB.h264-GROUP",
+			"download": "/api/torrents/1001/download",
+			"size": 1490000000,
+			"categories": ["TV", "HD"],
+			"freeleech": true,
+			"created_at": "2022-09-29T16:06:08Z"
+		},
+		{
+			"id": 1002,
+			"name": "Other.Release.2022.1080p.BluRay.x264-GROUP",
+			"download": "https://tracker.test/api/torrents/1002/download",
+			"size": "4.2 GB",
+			"categories": "Movies",
+			"freeleech": false
+		}
+	]
+}`
+
+func TestJSONJob_processItem(t *testing.T) {
+	itemsPath, err := jsonpath.Parse("$.data")
+	assert.NoError(t, err)
+
+	items, err := parseJSONItems([]byte(jsonFeedResponse), itemsPath)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	feed := &domain.Feed{
+		Type: string(domain.FeedTypeJSON),
+		Settings: &domain.FeedSettingsJSON{
+			JSON: &domain.FeedJSONSettings{
+				ItemsPath: "$.data",
+				Fields: domain.FeedJSONFieldMapping{
+					GUID:        "$.id",
+					Title:       "$.name",
+					DownloadURL: "$.download",
+					Size:        "$.size",
+					Category:    "$.categories",
+					Freeleech:   "$.freeleech",
+					Published:   "$.created_at",
+				},
+			},
+		},
+	}
+	assert.NoError(t, feed.Validate())
+
+	j := NewJSONJob(feed, "test feed", "mock-feed", zerolog.Logger{}, "https://tracker.test/api/releases?limit=50", nil, nil, nil, time.Minute)
+
+	rls := j.processItem(items[0])
+	assert.NotNil(t, rls)
+	assert.Equal(t, "Some.Release.Title.2022.09.22.720p.WEB.h264-GROUP", rls.TorrentName)
+	assert.Equal(t, domain.ReleaseImplementationJSON, rls.Implementation)
+	assert.Equal(t, "https://tracker.test/api/torrents/1001/download", rls.DownloadURL)
+	assert.Equal(t, uint64(1490000000), rls.Size)
+	assert.Equal(t, []string{"TV", "HD"}, rls.Categories)
+	assert.True(t, rls.Freeleech)
+	assert.Equal(t, "1001", j.itemKey(items[0]))
+
+	rls = j.processItem(items[1])
+	assert.NotNil(t, rls)
+	assert.Equal(t, "https://tracker.test/api/torrents/1002/download", rls.DownloadURL)
+	assert.Equal(t, uint64(4200000000), rls.Size)
+	assert.Equal(t, "Movies", rls.Category)
+	assert.False(t, rls.Freeleech)
+
+	// older than max age
+	feed.MaxAge = 3600
+	assert.Nil(t, j.processItem(items[0]))
+}
+
+func Test_parseJSONTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{name: "rfc3339", value: "2022-09-29T16:06:08Z", want: time.Date(2022, 9, 29, 16, 6, 8, 0, time.UTC), ok: true},
+		{name: "datetime", value: "2022-09-29 16:06:08", want: time.Date(2022, 9, 29, 16, 6, 8, 0, time.UTC), ok: true},
+		{name: "unix", value: "1664467568", want: time.Unix(1664467568, 0), ok: true},
+		{name: "unix_milli", value: "1664467568000", want: time.UnixMilli(1664467568000), ok: true},
+		{name: "invalid", value: "yesterday", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseJSONTime(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.True(t, tt.want.Equal(got))
+			}
+		})
+	}
+}
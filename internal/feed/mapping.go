@@ -0,0 +1,154 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// itemValues returns the values of the element path in the item, empty when the path is invalid or not found
+func itemValues(item *gofeed.Item, path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	p, err := domain.ParseFeedElementPath(path)
+	if err != nil {
+		return nil
+	}
+
+	if p.Prefix != "" {
+		return extensionValues(item, p)
+	}
+
+	var values []string
+
+	switch strings.ToLower(p.Name) {
+	case "title":
+		values = append(values, item.Title)
+	case "link":
+		values = append(values, item.Link)
+	case "description":
+		values = append(values, item.Description)
+	case "content":
+		values = append(values, item.Content)
+	case "guid":
+		values = append(values, item.GUID)
+	case "published":
+		values = append(values, item.Published)
+	case "updated":
+		values = append(values, item.Updated)
+	case "category":
+		values = append(values, item.Categories...)
+	case "author":
+		for _, a := range item.Authors {
+			values = append(values, a.Name)
+		}
+	case "enclosure":
+		for _, e := range item.Enclosures {
+			switch strings.ToLower(p.Attr) {
+			case "", "url":
+				values = append(values, e.URL)
+			case "length":
+				values = append(values, e.Length)
+			case "type":
+				values = append(values, e.Type)
+			}
+		}
+	default:
+		if v, ok := item.Custom[p.Name]; ok {
+			values = append(values, v)
+		}
+	}
+
+	return nonEmpty(values)
+}
+
+// extensionValues returns the values of namespaced elements like <torznab:attr name="size" value="1234"/>
+func extensionValues(item *gofeed.Item, p domain.FeedElementPath) []string {
+	var values []string
+
+	for _, e := range item.Extensions[p.Prefix][p.Name] {
+		if p.MatchAttr != "" && !strings.EqualFold(e.Attrs[p.MatchAttr], p.MatchValue) {
+			continue
+		}
+
+		if p.Attr != "" {
+			values = append(values, e.Attrs[p.Attr])
+		} else {
+			values = append(values, e.Value)
+		}
+	}
+
+	return nonEmpty(values)
+}
+
+func itemValue(item *gofeed.Item, path string) string {
+	if values := itemValues(item, path); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func nonEmpty(values []string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// isTruthy reports whether a mapped flag like freeleech is set
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "freeleech":
+		return true
+	}
+
+	return false
+}
+
+// mapFields sets the release fields of the field mapping of the feed, overriding the default parsing
+func (j *RSSJob) mapFields(item *gofeed.Item, rls *domain.Release, m *domain.FeedFieldMapping) {
+	if v := itemValue(item, m.DownloadURL); v != "" {
+		rls.DownloadURL = j.absoluteURL(v)
+	}
+
+	if v := itemValue(item, m.MagnetURI); v != "" {
+		rls.MagnetURI = v
+	}
+
+	if v := itemValue(item, m.Size); v != "" {
+		rls.Size = 0
+		rls.ParseSizeBytesString(v)
+	}
+
+	if categories := itemValues(item, m.Category); len(categories) > 0 {
+		rls.Categories = categories
+		rls.Category = strings.Join(categories, ", ")
+	}
+
+	if v := itemValue(item, m.InfoHash); v != "" {
+		rls.TorrentHash = v
+	}
+
+	if uploaders := itemValues(item, m.Uploader); len(uploaders) > 0 {
+		rls.Uploader = strings.Join(uploaders, ", ")
+	}
+
+	if m.Freeleech != "" {
+		if isTruthy(itemValue(item, m.Freeleech)) {
+			rls.Freeleech = true
+			rls.Bonus = []string{"Freeleech"}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const mappingTestFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed" xmlns:tr="https://tracker.example/rss">
+  <channel>
+    <title>tracker</title>
+    <item>
+      <title>Some Show S01E01</title>
+      <link>/details/1234</link>
+      <releasename>Some.Show.S01E01.1080p.WEB.h264-GROUP</releasename>
+      <tr:download>/download/1234?passkey=abc</tr:download>
+      <tr:tag>tv</tr:tag>
+      <tr:tag>hd</tr:tag>
+      <tr:uploader>anon</tr:uploader>
+      <tr:freeleech>true</tr:freeleech>
+      <enclosure url="https://tracker.example/download/1234" length="39399" type="application/x-bittorrent"/>
+      <torznab:attr name="size" value="2147483648"/>
+      <torznab:attr name="infohash" value="0123456789abcdef0123456789abcdef01234567"/>
+    </item>
+  </channel>
+</rss>`
+
+func TestRSSJob_processItem_FieldMapping(t *testing.T) {
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(mappingTestFeed))
+	assert.NoError(t, err)
+	assert.Len(t, feed.Items, 1)
+
+	j := &RSSJob{
+		Feed: &domain.Feed{
+			Settings: &domain.FeedSettingsJSON{
+				FieldMapping: &domain.FeedFieldMapping{
+					Title:       "releasename",
+					DownloadURL: "tr:download",
+					Size:        "torznab:attr[name=size]@value",
+					Category:    "tr:tag",
+					InfoHash:    "torznab:attr[name=infohash]@value",
+					Uploader:    "tr:uploader",
+					Freeleech:   "tr:freeleech",
+				},
+			},
+		},
+		IndexerIdentifier: "mock-feed",
+		Log:               zerolog.Logger{},
+		URL:               "https://tracker.example/rss",
+	}
+
+	rls := j.processItem(feed.Items[0])
+
+	assert.Equal(t, "Some.Show.S01E01.1080p.WEB.h264-GROUP", rls.TorrentName)
+	assert.Equal(t, "https://tracker.example/download/1234?passkey=abc", rls.DownloadURL)
+	assert.Equal(t, uint64(2147483648), rls.Size)
+	assert.Equal(t, []string{"tv", "hd"}, rls.Categories)
+	assert.Equal(t, "tv, hd", rls.Category)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", rls.TorrentHash)
+	assert.Equal(t, "anon", rls.Uploader)
+	assert.True(t, rls.Freeleech)
+}
+
+func TestItemValues(t *testing.T) {
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(mappingTestFeed))
+	assert.NoError(t, err)
+	item := feed.Items[0]
+
+	assert.Equal(t, []string{"Some Show S01E01"}, itemValues(item, "title"))
+	assert.Equal(t, []string{"39399"}, itemValues(item, "enclosure@length"))
+	assert.Equal(t, []string{"application/x-bittorrent"}, itemValues(item, "enclosure@type"))
+	assert.Equal(t, []string{"2147483648", "0123456789abcdef0123456789abcdef01234567"}, itemValues(item, "torznab:attr@value"))
+	assert.Empty(t, itemValues(item, "torznab:attr[name=seeders]@value"))
+	assert.Empty(t, itemValues(item, "missing"))
+	assert.Empty(t, itemValues(item, "not a path"))
+}
@@ -73,6 +73,8 @@ func (j *NewznabJob) process(ctx context.Context) error {
 
 	j.Log.Debug().Msgf("found (%d) new items to process", len(items))
 
+	feedRunFromContext(ctx).setNewItems(len(items))
+
 	if len(items) == 0 {
 		return nil
 	}
@@ -88,43 +90,78 @@ func (j *NewznabJob) process(ctx context.Context) error {
 			}
 		}
 
-		rls := domain.NewRelease(j.IndexerIdentifier)
+		releases = append(releases, j.mapRelease(item))
+	}
 
-		rls.TorrentName = item.Title
-		rls.InfoURL = item.GUID
-		rls.Implementation = domain.ReleaseImplementationNewznab
-		rls.Protocol = domain.ReleaseProtocolNzb
+	// process all new releases
+	go processReleases(ctx, j.ReleaseSvc, releases)
 
-		// parse size bytes string
-		rls.ParseSizeBytesString(item.Size)
+	return nil
+}
 
-		rls.ParseString(item.Title)
+// mapRelease creates the usenet release of the item, with the nzb as download url
+func (j *NewznabJob) mapRelease(item newznab.FeedItem) *domain.Release {
+	rls := domain.NewRelease(j.IndexerIdentifier)
 
-		if item.Enclosure != nil {
-			if item.Enclosure.Type == "application/x-nzb" {
-				rls.DownloadURL = item.Enclosure.Url
-			}
-		}
+	rls.TorrentName = item.Title
+	rls.InfoURL = item.GUID
+	rls.Implementation = domain.ReleaseImplementationNewznab
+	rls.Protocol = domain.ReleaseProtocolNzb
 
-		// map newznab categories ID and Name into rls.Categories
-		// so we can filter on both ID and Name
-		for _, category := range item.Categories {
-			rls.Categories = append(rls.Categories, []string{category.Name, strconv.Itoa(category.ID)}...)
-		}
+	// comments links to the details page, the guid is not always an url
+	if item.Comments != "" {
+		rls.InfoURL = item.Comments
+	}
 
-		releases = append(releases, rls)
+	// parse size bytes string, most indexers only set it as attribute
+	size := item.Size
+	if size == "" {
+		size = newznabAttr(item, "size")
 	}
+	rls.ParseSizeBytesString(size)
 
-	// process all new releases
-	go j.ReleaseSvc.ProcessMultiple(releases)
+	rls.ParseString(item.Title)
 
-	return nil
+	// some indexers don't set the type of the enclosure, the link is the nzb as well
+	if item.Enclosure != nil && item.Enclosure.Url != "" && (item.Enclosure.Type == "" || item.Enclosure.Type == "application/x-nzb") {
+		rls.DownloadURL = item.Enclosure.Url
+	} else if item.Link != "" {
+		rls.DownloadURL = item.Link
+	}
+
+	// map newznab categories ID and Name into rls.Categories
+	// so we can filter on both ID and Name
+	for _, category := range item.Categories {
+		rls.Categories = append(rls.Categories, []string{category.Name, strconv.Itoa(category.ID)}...)
+	}
+
+	if poster := newznabAttr(item, "poster"); poster != "" {
+		rls.Uploader = poster
+	}
+
+	return rls
+}
+
+// newznabAttr returns the value of the newznab:attr of the item
+func newznabAttr(item newznab.FeedItem, name string) string {
+	for _, attr := range item.Attributes {
+		if attr.Name == name {
+			return attr.Value
+		}
+	}
+
+	return ""
 }
 
 func (j *NewznabJob) getFeed(ctx context.Context) ([]newznab.FeedItem, error) {
 	// get feed
-	feed, err := j.Client.GetFeed(ctx)
+	feed, err := j.Client.GetFeedIfModified(ctx, j.Feed.ETag, j.Feed.LastModified)
 	if err != nil {
+		if errors.Is(err, newznab.ErrNotModified) {
+			feedNotModified(ctx, j.Repo, j.Feed, j.Log)
+			return nil, nil
+		}
+
 		j.Log.Error().Err(err).Msgf("error fetching feed items")
 		return nil, errors.Wrap(err, "error fetching feed items")
 	}
@@ -133,8 +170,12 @@ func (j *NewznabJob) getFeed(ctx context.Context) ([]newznab.FeedItem, error) {
 		j.Log.Error().Err(err).Msgf("error updating last run for feed id: %v", j.Feed.ID)
 	}
 
+	storeValidators(ctx, j.Repo, j.Feed, j.Log, feedValidators{ETag: feed.ETag, LastModified: feed.LastModified})
+
 	j.Log.Debug().Msgf("refreshing feed: %s, found (%d) items", j.Name, len(feed.Channel.Items))
 
+	feedRunFromContext(ctx).addItems(len(feed.Channel.Items))
+
 	items := make([]newznab.FeedItem, 0)
 	if len(feed.Channel.Items) == 0 {
 		return items, nil
@@ -171,6 +212,12 @@ func (j *NewznabJob) getFeed(ctx context.Context) ([]newznab.FeedItem, error) {
 			continue
 		}
 
+		// excluded items are cached too, so they are not matched again
+		if !j.Feed.Settings.TitleAllowed(i.Title) {
+			j.Log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", i.Title)
+			continue
+		}
+
 		// only append if we successfully added to cache
 		items = append(items, *i)
 	}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/newznab"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewznabJob_mapRelease(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        newznab.FeedItem
+		downloadURL string
+		infoURL     string
+		size        uint64
+		uploader    string
+	}{
+		{
+			name: "enclosure",
+			item: newznab.FeedItem{
+				Title:     "Some.Show.S01E01.1080p.WEB.h264-GROUP",
+				GUID:      "abc123",
+				Comments:  "https://indexer.test/details/abc123",
+				Link:      "https://indexer.test/getnzb/abc123",
+				Enclosure: &newznab.Enclosure{Url: "https://indexer.test/getnzb/abc123.nzb", Type: "application/x-nzb"},
+				Attributes: []newznab.ItemAttr{
+					{Name: "size", Value: "1073741824"},
+					{Name: "poster", Value: "poster@example.com"},
+				},
+			},
+			downloadURL: "https://indexer.test/getnzb/abc123.nzb",
+			infoURL:     "https://indexer.test/details/abc123",
+			size:        1073741824,
+			uploader:    "poster@example.com",
+		},
+		{
+			name: "link_fallback",
+			item: newznab.FeedItem{
+				Title: "Some.Show.S01E01.1080p.WEB.h264-GROUP",
+				GUID:  "https://indexer.test/details/abc123",
+				Size:  "2048",
+				Link:  "https://indexer.test/getnzb/abc123",
+			},
+			downloadURL: "https://indexer.test/getnzb/abc123",
+			infoURL:     "https://indexer.test/details/abc123",
+			size:        2048,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &NewznabJob{IndexerIdentifier: "newznab-test"}
+
+			rls := j.mapRelease(tt.item)
+			assert.Equal(t, domain.ReleaseProtocolNzb, rls.Protocol)
+			assert.Equal(t, tt.downloadURL, rls.DownloadURL)
+			assert.Equal(t, tt.infoURL, rls.InfoURL)
+			assert.Equal(t, tt.size, rls.Size)
+			assert.Equal(t, tt.uploader, rls.Uploader)
+		})
+	}
+}
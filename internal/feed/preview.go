@@ -0,0 +1,201 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// previewer is implemented by the feed jobs, previewItems fetches the feed once without its cache and last run
+type previewer interface {
+	previewItems(ctx context.Context) ([]backfillItem, error)
+}
+
+func (j *TorznabJob) previewItems(ctx context.Context) ([]backfillItem, error) {
+	feed, err := j.Client.FetchFeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		items = append(items, backfillItem{
+			key:       item.GUID,
+			title:     item.Title,
+			published: item.PubDate.Time,
+			release:   j.mapRelease(*item),
+		})
+	}
+
+	return items, nil
+}
+
+func (j *NewznabJob) previewItems(ctx context.Context) ([]backfillItem, error) {
+	feed, err := j.Client.GetFeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		items = append(items, backfillItem{
+			key:       item.GUID,
+			title:     item.Title,
+			published: item.PubDate.Time,
+			release:   j.mapRelease(*item),
+		})
+	}
+
+	return items, nil
+}
+
+func (j *RSSJob) previewItems(ctx context.Context) ([]backfillItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+
+	feed, err := NewFeedParser(j.Timeout, j.Feed.Cookie).WithTransport(j.Transport).ParseURLWithContext(ctx, j.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching rss feed items")
+	}
+
+	items := make([]backfillItem, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		key := item.GUID
+		if key == "" {
+			key = item.Title
+		}
+
+		var published time.Time
+		if item.PublishedParsed != nil {
+			published = *item.PublishedParsed
+		}
+
+		items = append(items, backfillItem{
+			key:       key,
+			title:     item.Title,
+			published: published,
+			release:   j.mapItem(item),
+		})
+	}
+
+	return items, nil
+}
+
+func (j *JSONJob) previewItems(ctx context.Context) ([]backfillItem, error) {
+	settings := j.settings()
+
+	pageItems, _, _, err := j.fetchPage(ctx, settings.PageStart, feedValidators{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]backfillItem, 0, len(pageItems))
+	for _, item := range pageItems {
+		published, _ := parseJSONTime(jsonValue(item, settings.Fields.Published))
+
+		items = append(items, backfillItem{
+			key:       j.itemKey(item),
+			title:     jsonValue(item, settings.Fields.Title),
+			published: published,
+			release:   j.mapItem(item),
+		})
+	}
+
+	return items, nil
+}
+
+// previewWarnings returns what stops the item from being processed, or from being grabbed by filters on these fields
+func previewWarnings(f *domain.Feed, item backfillItem, now time.Time) []string {
+	warnings := make([]string, 0)
+
+	if item.title == "" {
+		warnings = append(warnings, "missing title")
+	}
+
+	if item.key == "" {
+		warnings = append(warnings, "missing guid, the item is skipped")
+	}
+
+	if !f.Settings.TitleAllowed(item.title) {
+		warnings = append(warnings, "excluded by the title include or exclude patterns, the item is skipped")
+	}
+
+	if item.published.IsZero() {
+		warnings = append(warnings, "missing publish date, max age is not checked")
+	} else if f.MaxAge > 0 && !isNewerThanMaxAge(f.MaxAge, item.published, now) {
+		warnings = append(warnings, "older than max age, the item is skipped")
+	}
+
+	if item.release == nil {
+		return append(warnings, "could not parse the item into a release")
+	}
+
+	if item.release.DownloadURL == "" && item.release.MagnetURI == "" {
+		warnings = append(warnings, "missing download url and magnet uri")
+	}
+
+	if item.release.Size == 0 {
+		warnings = append(warnings, "missing size")
+	}
+
+	if item.release.Category == "" && len(item.release.Categories) == 0 {
+		warnings = append(warnings, "missing categories")
+	}
+
+	return warnings
+}
+
+// Preview fetches the feed once and parses its items into releases. The items are not cached and not sent to the
+// filters, and the feed is not stored.
+func (s *service) Preview(ctx context.Context, feed *domain.Feed) (*domain.FeedPreview, error) {
+	if err := feed.Validate(); err != nil {
+		return nil, err
+	}
+
+	f := *feed
+	if f.Timeout <= 0 {
+		f.Timeout = 60
+	}
+
+	// the preview feed is not stored, a refreshed cookie is only used for the preview
+	transport, err := newFeedTransport(&f, nil, s.log)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.createFeedJob(&f, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := job.(previewer)
+	if !ok {
+		return nil, errors.New("%s feed can not be previewed", f.Type)
+	}
+
+	items, err := p.previewItems(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("error previewing feed: %s", f.Name)
+		return nil, errors.Wrap(err, "error fetching feed")
+	}
+
+	now := time.Now()
+
+	preview := &domain.FeedPreview{Items: make([]domain.FeedPreviewItem, 0, len(items))}
+	for _, item := range items {
+		preview.Items = append(preview.Items, domain.FeedPreviewItem{
+			Key:       item.key,
+			Title:     item.title,
+			Published: item.published,
+			Release:   item.release,
+			Warnings:  previewWarnings(&f, item, now),
+		})
+	}
+
+	return preview, nil
+}
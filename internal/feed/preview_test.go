@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const previewRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Preview</title>
+    <item>
+      <title>Some.Show.S01E01.1080p.WEB.h264-GROUP</title>
+      <guid>https://tracker.local/details.php?id=1</guid>
+      <pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+      <category>TV</category>
+      <enclosure url="https://tracker.local/download.php?id=1" length="1500000000" type="application/x-bittorrent" />
+    </item>
+    <item>
+      <title>Some.Movie.2023.720p.BluRay.x264-GROUP</title>
+      <link>https://tracker.local/download.php?id=2</link>
+    </item>
+  </channel>
+</rss>`
+
+func Test_service_Preview(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(previewRSS))
+	}))
+	defer ts.Close()
+
+	s := &service{log: zerolog.Nop()}
+
+	got, err := s.Preview(context.Background(), &domain.Feed{
+		Name:    "preview",
+		Indexer: "mock-feed",
+		Type:    string(domain.FeedTypeRSS),
+		URL:     ts.URL,
+		MaxAge:  3600,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, got.Items, 2)
+
+	first := got.Items[0]
+	assert.Equal(t, "https://tracker.local/details.php?id=1", first.Key)
+	assert.Equal(t, time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC), first.Published.UTC())
+	assert.Equal(t, "https://tracker.local/download.php?id=1", first.Release.DownloadURL)
+	assert.Equal(t, uint64(1500000000), first.Release.Size)
+	assert.Equal(t, "1080p", first.Release.Resolution)
+	assert.Equal(t, []string{"older than max age, the item is skipped"}, first.Warnings)
+
+	second := got.Items[1]
+	assert.Equal(t, "Some.Movie.2023.720p.BluRay.x264-GROUP", second.Key)
+	assert.Equal(t, []string{"missing publish date, max age is not checked", "missing size", "missing categories"}, second.Warnings)
+}
+
+func Test_previewWarnings_unparsed(t *testing.T) {
+	got := previewWarnings(&domain.Feed{}, backfillItem{published: time.Now()}, time.Now())
+	assert.Equal(t, []string{"missing title", "missing guid, the item is skipped", "could not parse the item into a release"}, got)
+}
+
+func Test_previewWarnings_titleExcluded(t *testing.T) {
+	f := &domain.Feed{Settings: &domain.FeedSettingsJSON{TitleExclude: "720p"}}
+	item := backfillItem{key: "1", title: "Movie.2023.720p.BluRay-GRP", published: time.Now()}
+
+	got := previewWarnings(f, item, time.Now())
+	assert.Contains(t, got, "excluded by the title include or exclude patterns, the item is skipped")
+}
@@ -0,0 +1,704 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jsonpath"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/proxy"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// pushReconnectMin is the wait before the first reconnect, it doubles with each failed attempt up to pushReconnectMax
+	pushReconnectMin = time.Second
+	pushReconnectMax = 5 * time.Minute
+
+	// pushIdleTimeout drops connections that did not send anything, not even a keep alive, for this long
+	pushIdleTimeout = 10 * time.Minute
+
+	// pushMaxMessageSize is the largest message or server-sent event line that is read
+	pushMaxMessageSize = 4 << 20
+)
+
+// errStopStream stops reading the stream without an error
+var errStopStream = errors.New("stop stream")
+
+// PushJob keeps a connection to a feed that streams new uploads over server-sent events or a websocket, and reconnects
+// with a backoff when the connection drops. The items of each message are mapped with the json settings of the feed.
+type PushJob struct {
+	Feed              *domain.Feed
+	Name              string
+	IndexerIdentifier string
+	Log               zerolog.Logger
+	URL               string
+	Repo              domain.FeedRepo
+	CacheRepo         domain.FeedCacheRepo
+	ReleaseSvc        release.Service
+	Timeout           time.Duration
+
+	// json maps the items of the messages like the items of a json feed
+	json *JSONJob
+	http *http.Client
+
+	// transport has the proxy and credentials of the feed, websockets are dialed with them. Nil when the feed has none.
+	transport *feedTransport
+
+	// svc records the health of the connection and the messages as runs, nil does not record them
+	svc *service
+
+	m           sync.Mutex
+	lastEventID string
+}
+
+func NewPushJob(feed *domain.Feed, name string, indexerIdentifier string, log zerolog.Logger, url string, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, timeout time.Duration) *PushJob {
+	return &PushJob{
+		Feed:              feed,
+		Name:              name,
+		IndexerIdentifier: indexerIdentifier,
+		Log:               log,
+		URL:               url,
+		Repo:              repo,
+		CacheRepo:         cacheRepo,
+		ReleaseSvc:        releaseSvc,
+		Timeout:           timeout,
+		json:              NewJSONJob(feed, name, indexerIdentifier, log, pushBaseURL(url), repo, cacheRepo, releaseSvc, timeout),
+		// no client timeout, the stream stays open
+		http: &http.Client{
+			Transport: sharedFeedTransport(),
+		},
+	}
+}
+
+// withTransport requests the stream with the transport, nil keeps the default. Websockets are dialed through the
+// proxy of a feed transport and send its credentials.
+func (j *PushJob) withTransport(transport http.RoundTripper) {
+	if transport != nil {
+		j.http.Transport = transport
+	}
+
+	if t, ok := transport.(*feedTransport); ok {
+		j.transport = t
+	}
+}
+
+// Run keeps the connection until the process stops, push feeds are started by the service and not scheduled
+func (j *PushJob) Run() {
+	j.run(context.Background())
+}
+
+// run connects to the feed and reconnects when the connection drops, until the context is canceled
+func (j *PushJob) run(ctx context.Context) {
+	failures := 0
+
+	for {
+		err := j.stream(ctx, func() error {
+			failures = 0
+
+			j.Log.Info().Msgf("push feed connected: %s", j.Name)
+			j.recordHealth(nil)
+
+			return nil
+		}, func(data []byte) error {
+			j.processMessage(ctx, data)
+			return nil
+		})
+
+		if ctx.Err() != nil {
+			j.Log.Debug().Msgf("push feed stopped: %s", j.Name)
+			return
+		}
+
+		if err == nil {
+			err = errors.New("connection closed by the server")
+		}
+
+		failures++
+		wait := pushReconnectBackoff(failures)
+
+		j.Log.Error().Err(err).Msgf("push feed disconnected, reconnecting in %s", wait)
+		j.recordHealth(err)
+
+		select {
+		case <-ctx.Done():
+			j.Log.Debug().Msgf("push feed stopped: %s", j.Name)
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// pushReconnectBackoff returns the wait before reconnecting after the failed attempts in a row
+func pushReconnectBackoff(failures int) time.Duration {
+	wait := pushReconnectMin
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= pushReconnectMax {
+			return pushReconnectMax
+		}
+	}
+
+	return wait
+}
+
+func (j *PushJob) recordHealth(err error) {
+	if j.svc == nil {
+		return
+	}
+
+	j.svc.recordRun(j.Feed, err, 0, time.Now())
+}
+
+func (j *PushJob) settings() *domain.FeedPushSettings {
+	if j.Feed.Settings == nil || j.Feed.Settings.Push == nil {
+		return &domain.FeedPushSettings{}
+	}
+
+	return j.Feed.Settings.Push
+}
+
+// stream connects to the feed, calls onConnect once connected and handle with each message. It returns when the
+// connection drops or a callback fails, errStopStream stops reading without an error.
+func (j *PushJob) stream(ctx context.Context, onConnect func() error, handle func(data []byte) error) error {
+	var err error
+
+	switch j.settings().Protocol {
+	case domain.FeedPushProtocolWebSocket:
+		err = j.streamWebSocket(ctx, onConnect, handle)
+	default:
+		err = j.streamSSE(ctx, onConnect, handle)
+	}
+
+	if errors.Is(err, errStopStream) {
+		return nil
+	}
+
+	return err
+}
+
+// streamSSE reads the server-sent events of the feed. Events are dispatched on an empty line, their data lines are
+// joined. Reconnects send the id of the last event, so the feed can replay what was missed.
+func (j *PushJob) streamSSE(ctx context.Context, onConnect func() error, handle func(data []byte) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "autobrr")
+
+	for name, value := range j.json.settings().Headers {
+		req.Header.Set(name, value)
+	}
+
+	if j.Feed.Cookie != "" {
+		req.Header.Set("Cookie", j.Feed.Cookie)
+	}
+
+	if id := j.eventID(); id != "" {
+		req.Header.Set("Last-Event-ID", id)
+	}
+
+	// the connect timeout, the stream itself is only dropped when idle
+	var timedOut atomic.Bool
+	connectTimer := time.AfterFunc(j.connectTimeout(), func() {
+		timedOut.Store(true)
+		cancel()
+	})
+
+	resp, err := j.http.Do(req)
+	connectTimer.Stop()
+	if err != nil {
+		if timedOut.Load() {
+			return errors.New("no response within %s", j.connectTimeout())
+		}
+		return errors.Wrap(err, "error connecting to push feed")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %d", resp.StatusCode)
+	}
+
+	if err := onConnect(); err != nil {
+		return err
+	}
+
+	idle := time.AfterFunc(pushIdleTimeout, func() {
+		timedOut.Store(true)
+		cancel()
+	})
+	defer idle.Stop()
+
+	wantEvent := j.settings().Event
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), pushMaxMessageSize)
+
+	var event string
+	var data []string
+
+	for scanner.Scan() {
+		idle.Reset(pushIdleTimeout)
+
+		line := scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 && (wantEvent == "" || strings.EqualFold(eventOrDefault(event), wantEvent)) {
+				if err := handle([]byte(strings.Join(data, "\n"))); err != nil {
+					return err
+				}
+			}
+
+			event = ""
+			data = data[:0]
+			continue
+		}
+
+		// comments are keep alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			j.setEventID(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if timedOut.Load() {
+			return errors.New("nothing received for %s", pushIdleTimeout)
+		}
+		return errors.Wrap(err, "error reading push feed")
+	}
+
+	return nil
+}
+
+// eventOrDefault returns the type of the event, events without a type are message events
+func eventOrDefault(event string) string {
+	if event == "" {
+		return "message"
+	}
+
+	return event
+}
+
+func (j *PushJob) eventID() string {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	return j.lastEventID
+}
+
+func (j *PushJob) setEventID(id string) {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	j.lastEventID = id
+}
+
+// streamWebSocket reads the text messages of the websocket of the feed, after sending the subscribe message if set
+func (j *PushJob) streamWebSocket(ctx context.Context, onConnect func() error, handle func(data []byte) error) error {
+	config, err := websocket.NewConfig(j.URL, websocketOrigin(j.URL))
+	if err != nil {
+		return errors.Wrap(err, "invalid websocket url: %s", j.URL)
+	}
+
+	config.TlsConfig = &tls.Config{InsecureSkipVerify: true}
+	config.Header = http.Header{}
+	config.Header.Set("User-Agent", "autobrr")
+
+	for name, value := range j.json.settings().Headers {
+		config.Header.Set(name, value)
+	}
+
+	if j.Feed.Cookie != "" {
+		config.Header.Set("Cookie", j.Feed.Cookie)
+	}
+
+	var proxyURL *url.URL
+
+	if j.transport != nil {
+		req, err := j.transport.websocketRequest(ctx, j.URL, config.Header)
+		if err != nil {
+			return err
+		}
+
+		config.Location = req.URL
+		config.Header = req.Header
+		proxyURL = j.transport.proxy
+	}
+
+	conn, err := j.dialWebSocket(ctx, config, proxyURL)
+	if err != nil {
+		// the handshake of an expired session or api key is refused, log in again for the reconnect
+		if errors.Is(err, websocket.ErrBadStatus) && j.transport != nil && j.transport.login != nil {
+			j.transport.refreshLogin(ctx)
+		}
+
+		return errors.Wrap(err, "error connecting to push feed")
+	}
+
+	// the websocket can not be read with a context, closing it stops the read
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	if subscribe := j.settings().Subscribe; subscribe != "" {
+		if err := websocket.Message.Send(conn, subscribe); err != nil {
+			return errors.Wrap(err, "could not send subscribe message")
+		}
+	}
+
+	if err := onConnect(); err != nil {
+		return err
+	}
+
+	conn.MaxPayloadBytes = pushMaxMessageSize
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(pushIdleTimeout)); err != nil {
+			return err
+		}
+
+		var msg []byte
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "error reading push feed")
+		}
+
+		if len(msg) == 0 {
+			continue
+		}
+
+		if err := handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// dialWebSocket connects to the websocket through the proxy, if set, and sends the handshake
+func (j *PushJob) dialWebSocket(ctx context.Context, config *websocket.Config, proxyURL *url.URL) (*websocket.Conn, error) {
+	dialer, err := websocketDialer(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, j.connectTimeout())
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", websocketAddr(config.Location))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if config.Location.Scheme == "wss" {
+		tlsConfig := config.TlsConfig.Clone()
+		tlsConfig.ServerName = config.Location.Hostname()
+
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		conn = tlsConn
+	}
+
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+
+	return ws, nil
+}
+
+// websocketDialer returns the dialer of the proxy of a websocket. Websockets are not sent through the http transport,
+// so only socks5 proxies can be used for them.
+func websocketDialer(proxyURL *url.URL) (proxy.ContextDialer, error) {
+	direct := &net.Dialer{}
+
+	if proxyURL == nil {
+		return direct, nil
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		dialer, err := proxy.FromURL(proxyURL, direct)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create socks5 proxy dialer")
+		}
+
+		contextDialer, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return nil, errors.New("socks5 proxy dialer does not support context")
+		}
+
+		return contextDialer, nil
+	}
+
+	return nil, errors.New("websocket push feeds only support socks5 proxies, not: %s", proxyURL.Scheme)
+}
+
+// websocketAddr returns the host and port of a websocket url, with the default port of its scheme
+func websocketAddr(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return u.Host
+	}
+
+	if u.Scheme == "wss" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+// pushBaseURL returns the http url of a websocket url, relative urls in the messages are resolved against it
+func pushBaseURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+
+	return u.String()
+}
+
+// websocketOrigin returns the http origin of the websocket url, which some servers check
+func websocketOrigin(rawURL string) string {
+	u, err := url.Parse(pushBaseURL(rawURL))
+	if err != nil {
+		return rawURL
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+func (j *PushJob) connectTimeout() time.Duration {
+	if j.Timeout <= 0 {
+		return 60 * time.Second
+	}
+
+	return j.Timeout
+}
+
+// messageItems returns the items of the message, selected by the items path of the json settings
+func (j *PushJob) messageItems(data []byte) ([]any, error) {
+	itemsPath, err := jsonpath.Parse(j.json.settings().ItemsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJSONItems(data, itemsPath)
+}
+
+// processMessage sends the new items of the message to the filters, each message is recorded as a run of the feed
+func (j *PushJob) processMessage(ctx context.Context, data []byte) {
+	now := time.Now()
+
+	var run *feedRun
+	if j.svc != nil {
+		if gate := j.svc.indexerGate(j.Feed.Indexer); gate != nil {
+			if err := gate.acquire(ctx, j.Feed.Priority); err != nil {
+				return
+			}
+			defer gate.release()
+		}
+
+		run = j.svc.startRun(j.Feed.ID, now)
+		ctx = withFeedRun(ctx, run)
+	}
+
+	err := j.process(ctx, data)
+	if err != nil {
+		j.Log.Error().Err(err).Msg("push feed message error")
+	}
+
+	if run != nil {
+		j.svc.finishRun(run, err, time.Now())
+	}
+}
+
+func (j *PushJob) process(ctx context.Context, data []byte) error {
+	items, err := j.messageItems(data)
+	if err != nil {
+		return err
+	}
+
+	if err := j.Repo.UpdateLastRunWithData(ctx, j.Feed.ID, string(data)); err != nil {
+		j.Log.Error().Err(err).Msgf("error updating last run for feed id: %v", j.Feed.ID)
+	}
+
+	feedRunFromContext(ctx).addItems(len(items))
+
+	// set ttl to 1 month
+	ttl := time.Now().AddDate(0, 1, 0)
+
+	releases := make([]*domain.Release, 0)
+
+	for _, item := range items {
+		key := j.json.itemKey(item)
+		if key == "" {
+			continue
+		}
+
+		exists, err := j.CacheRepo.Exists(j.Feed.ID, key)
+		if err != nil {
+			j.Log.Error().Err(err).Msg("could not check if item exists")
+			continue
+		}
+		if exists {
+			j.Log.Trace().Msgf("cache item exists, skipping release: %s", key)
+			continue
+		}
+
+		title := jsonValue(item, j.json.settings().Fields.Title)
+
+		j.Log.Debug().Msgf("found new release: %s", title)
+
+		if err := j.CacheRepo.Put(j.Feed.ID, key, []byte(title), ttl); err != nil {
+			j.Log.Error().Err(err).Str("entry", key).Msg("cache.Put: error storing item in cache")
+			continue
+		}
+
+		// excluded items are cached too, so they are not matched again
+		if !j.Feed.Settings.TitleAllowed(title) {
+			j.Log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", title)
+			continue
+		}
+
+		if rls := j.json.processItem(item); rls != nil {
+			releases = append(releases, rls)
+		}
+	}
+
+	feedRunFromContext(ctx).setNewItems(len(releases))
+
+	if len(releases) == 0 {
+		return nil
+	}
+
+	// process all new releases
+	go processReleases(ctx, j.ReleaseSvc, releases)
+
+	return nil
+}
+
+// previewItems connects to the feed and returns the items of the first message with items, or none when no
+// message arrived within the timeout
+func (j *PushJob) previewItems(ctx context.Context) ([]backfillItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, j.connectTimeout())
+	defer cancel()
+
+	items := make([]backfillItem, 0)
+
+	err := j.stream(ctx, func() error { return nil }, func(data []byte) error {
+		messageItems, err := j.messageItems(data)
+		if err != nil {
+			return err
+		}
+
+		settings := j.json.settings()
+
+		for _, item := range messageItems {
+			published, _ := parseJSONTime(jsonValue(item, settings.Fields.Published))
+
+			items = append(items, backfillItem{
+				key:       j.json.itemKey(item),
+				title:     jsonValue(item, settings.Fields.Title),
+				published: published,
+				release:   j.json.mapItem(item),
+			})
+		}
+
+		if len(items) > 0 {
+			return errStopStream
+		}
+
+		return nil
+	})
+
+	// no message within the timeout is not an error of the feed
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// startPushJob connects the push feed in the background, replacing its running connection
+func (s *service) startPushJob(f *domain.Feed, job *PushJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.m.Lock()
+	if stop, ok := s.pushes[f.ID]; ok {
+		stop()
+	}
+	s.pushes[f.ID] = cancel
+	s.m.Unlock()
+
+	go job.run(ctx)
+}
+
+// stopPushJob closes the connection of the push feed
+func (s *service) stopPushJob(id int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if stop, ok := s.pushes[id]; ok {
+		stop()
+		delete(s.pushes, id)
+	}
+}
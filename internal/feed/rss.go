@@ -6,6 +6,7 @@ package feed
 import (
 	"context"
 	"encoding/xml"
+	"net/http"
 	"net/url"
 	"regexp"
 	"time"
@@ -34,6 +35,9 @@ type RSSJob struct {
 	ReleaseSvc        release.Service
 	Timeout           time.Duration
 
+	// Transport of the feed with a proxy, headers or login step, nil uses the default
+	Transport http.RoundTripper
+
 	attempts int
 	errors   []error
 
@@ -77,6 +81,8 @@ func (j *RSSJob) process(ctx context.Context) error {
 
 	j.Log.Debug().Msgf("found (%d) new items to process", len(items))
 
+	feedRunFromContext(ctx).setNewItems(len(items))
+
 	if len(items) == 0 {
 		return nil
 	}
@@ -94,7 +100,7 @@ func (j *RSSJob) process(ctx context.Context) error {
 	}
 
 	// process all new releases
-	go j.ReleaseSvc.ProcessMultiple(releases)
+	go processReleases(ctx, j.ReleaseSvc, releases)
 
 	return nil
 }
@@ -110,10 +116,24 @@ func (j *RSSJob) processItem(item *gofeed.Item) *domain.Release {
 		}
 	}
 
+	return j.mapItem(item)
+}
+
+// mapItem maps the item to a release with the field mapping of the feed
+func (j *RSSJob) mapItem(item *gofeed.Item) *domain.Release {
+	mapping := j.fieldMapping()
+
+	title := item.Title
+	if mapping != nil {
+		if v := itemValue(item, mapping.Title); v != "" {
+			title = v
+		}
+	}
+
 	rls := domain.NewRelease(j.IndexerIdentifier)
 	rls.Implementation = domain.ReleaseImplementationRSS
 
-	rls.ParseString(item.Title)
+	rls.ParseString(title)
 
 	if j.Feed.Settings != nil && j.Feed.Settings.DownloadType == domain.FeedDownloadTypeMagnet {
 		rls.MagnetURI = item.Link
@@ -135,17 +155,7 @@ func (j *RSSJob) processItem(item *gofeed.Item) *domain.Release {
 	}
 
 	if rls.DownloadURL != "" {
-		// handle no baseurl with only relative url
-		// grab url from feed url and create full url
-		if parsedURL, _ := url.Parse(rls.DownloadURL); parsedURL != nil && len(parsedURL.Hostname()) == 0 {
-			if parentURL, _ := url.Parse(j.URL); parentURL != nil {
-				parentURL.Path, parentURL.RawPath = "", ""
-
-				// unescape the query params for max compatibility
-				escapedUrl, _ := url.QueryUnescape(parentURL.JoinPath(rls.DownloadURL).String())
-				rls.DownloadURL = escapedUrl
-			}
-		}
+		rls.DownloadURL = j.absoluteURL(rls.DownloadURL)
 	}
 
 	for _, v := range item.Categories {
@@ -208,6 +218,10 @@ func (j *RSSJob) processItem(item *gofeed.Item) *domain.Release {
 		}
 	}
 
+	if mapping != nil {
+		j.mapFields(item, rls, mapping)
+	}
+
 	// add cookie to release for download if needed
 	if j.Feed.Cookie != "" {
 		rls.RawCookie = j.Feed.Cookie
@@ -216,12 +230,41 @@ func (j *RSSJob) processItem(item *gofeed.Item) *domain.Release {
 	return rls
 }
 
+// absoluteURL handles no baseurl with only relative url, it grabs the url from the feed url and creates the full url
+func (j *RSSJob) absoluteURL(downloadURL string) string {
+	if parsedURL, _ := url.Parse(downloadURL); parsedURL != nil && len(parsedURL.Hostname()) == 0 {
+		if parentURL, _ := url.Parse(j.URL); parentURL != nil {
+			parentURL.Path, parentURL.RawPath = "", ""
+
+			// unescape the query params for max compatibility
+			escapedUrl, _ := url.QueryUnescape(parentURL.JoinPath(downloadURL).String())
+			return escapedUrl
+		}
+	}
+
+	return downloadURL
+}
+
+// fieldMapping returns the field mapping of the feed or nil to only use the default parsing
+func (j *RSSJob) fieldMapping() *domain.FeedFieldMapping {
+	if j.Feed == nil || j.Feed.Settings == nil {
+		return nil
+	}
+
+	return j.Feed.Settings.FieldMapping
+}
+
 func (j *RSSJob) getFeed(ctx context.Context) (items []*gofeed.Item, err error) {
 	ctx, cancel := context.WithTimeout(ctx, j.Timeout)
 	defer cancel()
 
-	feed, err := NewFeedParser(j.Timeout, j.Feed.Cookie).ParseURLWithContext(ctx, j.URL)
+	feed, validators, err := NewFeedParser(j.Timeout, j.Feed.Cookie).WithTransport(j.Transport).ParseURLIfModified(ctx, j.URL, lastValidators(j.Feed))
 	if err != nil {
+		if errors.Is(err, errNotModified) {
+			feedNotModified(ctx, j.Repo, j.Feed, j.Log)
+			return nil, nil
+		}
+
 		return nil, errors.Wrap(err, "error fetching rss feed items")
 	}
 
@@ -232,8 +275,12 @@ func (j *RSSJob) getFeed(ctx context.Context) (items []*gofeed.Item, err error)
 		j.Log.Error().Err(err).Msgf("error updating last run for feed id: %v", j.Feed.ID)
 	}
 
+	storeValidators(ctx, j.Repo, j.Feed, j.Log, validators)
+
 	j.Log.Debug().Msgf("refreshing rss feed: %v, found (%d) items", j.Name, len(feed.Items))
 
+	feedRunFromContext(ctx).addItems(len(feed.Items))
+
 	if len(feed.Items) == 0 {
 		return
 	}
@@ -271,6 +318,12 @@ func (j *RSSJob) getFeed(ctx context.Context) (items []*gofeed.Item, err error)
 			continue
 		}
 
+		// excluded items are cached too, so they are not matched again
+		if !j.Feed.Settings.TitleAllowed(item.Title) {
+			j.Log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", item.Title)
+			continue
+		}
+
 		// only append if we successfully added to cache
 		items = append(items, item)
 	}
@@ -0,0 +1,267 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// feedRunHistory keeps the last runs of a feed and the totals of its runs since start
+type feedRunHistory struct {
+	m      sync.Mutex
+	runs   []*feedRun
+	totals domain.FeedRunStats
+}
+
+// feedRun records the diagnostics of a refresh. The jobs find it in the context of the refresh,
+// all its methods do nothing when the refresh is not recorded.
+type feedRun struct {
+	history *feedRunHistory
+	run     domain.FeedRun
+}
+
+type feedRunKey struct{}
+
+func withFeedRun(ctx context.Context, run *feedRun) context.Context {
+	return context.WithValue(ctx, feedRunKey{}, run)
+}
+
+// feedRunFromContext returns the run of the refresh, nil when it is not recorded
+func feedRunFromContext(ctx context.Context) *feedRun {
+	run, _ := ctx.Value(feedRunKey{}).(*feedRun)
+	return run
+}
+
+// addItems records the items in the feed, or in a page of the feed
+func (r *feedRun) addItems(items int) {
+	if r == nil {
+		return
+	}
+
+	r.history.m.Lock()
+	defer r.history.m.Unlock()
+
+	r.run.Items += items
+}
+
+// setNewItems records the items not in the cache, sent to the filters
+func (r *feedRun) setNewItems(items int) {
+	if r == nil {
+		return
+	}
+
+	r.history.m.Lock()
+	defer r.history.m.Unlock()
+
+	r.history.totals.NewItems += items - r.run.NewItems
+	r.run.NewItems = items
+}
+
+// setHTTPStatus records the status of the last response of the feed
+func (r *feedRun) setHTTPStatus(status int) {
+	if r == nil {
+		return
+	}
+
+	r.history.m.Lock()
+	defer r.history.m.Unlock()
+
+	r.run.HTTPStatus = status
+}
+
+// addMatched records the releases of the run approved by a filter
+func (r *feedRun) addMatched(matched int) {
+	if r == nil || matched == 0 {
+		return
+	}
+
+	r.history.m.Lock()
+	defer r.history.m.Unlock()
+
+	r.run.Matched += matched
+	r.history.totals.Matched += matched
+}
+
+// startRun returns the run of a refresh of the feed, it is kept once finished
+func (s *service) startRun(id int, now time.Time) *feedRun {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.runs == nil {
+		s.runs = map[int]*feedRunHistory{}
+	}
+
+	history, ok := s.runs[id]
+	if !ok {
+		history = &feedRunHistory{}
+		s.runs[id] = history
+	}
+
+	return &feedRun{
+		history: history,
+		run: domain.FeedRun{
+			FeedID:    id,
+			StartedAt: now,
+		},
+	}
+}
+
+// finishRun keeps the run with its duration and error, dropping the oldest runs over domain.FeedRunsKept
+func (s *service) finishRun(run *feedRun, err error, now time.Time) {
+	h := run.history
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	run.run.DurationMs = now.Sub(run.run.StartedAt).Milliseconds()
+
+	h.totals.Runs++
+	if err != nil {
+		run.run.Error = err.Error()
+		h.totals.Errors++
+	}
+
+	h.runs = append(h.runs, run)
+	if len(h.runs) > domain.FeedRunsKept {
+		h.runs = h.runs[len(h.runs)-domain.FeedRunsKept:]
+	}
+}
+
+// GetRuns returns the kept runs of the feed, newest first
+func (s *service) GetRuns(ctx context.Context, id int) ([]domain.FeedRun, error) {
+	s.m.Lock()
+	h, ok := s.runs[id]
+	s.m.Unlock()
+
+	runs := make([]domain.FeedRun, 0)
+	if !ok {
+		return runs, nil
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for i := len(h.runs) - 1; i >= 0; i-- {
+		runs = append(runs, h.runs[i].run)
+	}
+
+	return runs, nil
+}
+
+// runStats returns the last run of the feed and the totals of its runs, nil when it did not run yet
+func (s *service) runStats(id int) *domain.FeedRunStats {
+	s.m.Lock()
+	h, ok := s.runs[id]
+	s.m.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if len(h.runs) == 0 {
+		return nil
+	}
+
+	stats := h.totals
+	last := h.runs[len(h.runs)-1].run
+	stats.LastRun = &last
+
+	return &stats
+}
+
+// resetRuns forgets the runs of the deleted feed
+func (s *service) resetRuns(id int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.runs, id)
+}
+
+// processReleases sends the new releases to the filters and records the releases they approved on the run
+func processReleases(ctx context.Context, releaseSvc release.Service, releases []*domain.Release) {
+	releaseSvc.ProcessMultiple(releases)
+
+	run := feedRunFromContext(ctx)
+	if run == nil {
+		return
+	}
+
+	matched := 0
+	for _, rls := range releases {
+		if rls != nil && rls.FilterStatus == domain.ReleaseStatusFilterApproved {
+			matched++
+		}
+	}
+
+	run.addMatched(matched)
+}
+
+// runTransport records the status of the feed responses on the run of the request context
+type runTransport struct {
+	base http.RoundTripper
+
+	// onAuth is called with the error of a response refused with 401 or 403 or of a failed login,
+	// and with nil for an accepted response
+	onAuth func(err error)
+}
+
+// newRunTransport wraps the transport of the feed, nil wraps the default transport of the feed type. Responses of
+// polled feeds are decoded, the stream of push feeds is read as it is.
+func newRunTransport(base http.RoundTripper, feedType string, onAuth func(err error)) http.RoundTripper {
+	if base == nil {
+		switch feedType {
+		case string(domain.FeedTypeRSS), string(domain.FeedTypeJSON), string(domain.FeedTypePush):
+			base = sharedFeedTransport()
+
+		default:
+			base = http.DefaultTransport
+		}
+	}
+
+	if feedType != string(domain.FeedTypePush) {
+		base = &decodeTransport{base: base}
+	}
+
+	return &runTransport{base: base, onAuth: onAuth}
+}
+
+func (t *runTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		var loginErr *feedLoginError
+		if errors.As(err, &loginErr) {
+			t.auth(err)
+		}
+
+		return nil, err
+	}
+
+	feedRunFromContext(req.Context()).setHTTPStatus(resp.StatusCode)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		t.auth(errors.New("feed answered with status: %d", resp.StatusCode))
+
+	case resp.StatusCode < http.StatusBadRequest:
+		t.auth(nil)
+	}
+
+	return resp, nil
+}
+
+func (t *runTransport) auth(err error) {
+	if t.onAuth != nil {
+		t.onAuth(err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRunProcessor struct {
+	transport http.RoundTripper
+	url       string
+	err       error
+}
+
+func (p *testRunProcessor) process(ctx context.Context) error {
+	run := feedRunFromContext(ctx)
+	run.addItems(10)
+	run.setNewItems(3)
+
+	if p.transport != nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := p.transport.RoundTrip(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+
+	run.addMatched(2)
+
+	return p.err
+}
+
+func TestHealthJob_Run_records_runs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	svc := &service{
+		log:    zerolog.Nop(),
+		health: map[int]*domain.FeedHealth{},
+		runs:   map[int]*feedRunHistory{},
+	}
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	processor := &testRunProcessor{transport: newRunTransport(nil, string(domain.FeedTypeTorznab), nil), url: ts.URL}
+
+	job := &healthJob{
+		svc:      svc,
+		feed:     &domain.Feed{ID: 1, Name: "test feed", Settings: &domain.FeedSettingsJSON{}},
+		job:      processor,
+		interval: 10 * time.Minute,
+		log:      zerolog.Nop(),
+		now:      func() time.Time { return now },
+	}
+
+	job.Run()
+
+	now = now.Add(10 * time.Minute)
+	processor.err = errors.New("unexpected status: 429")
+	job.Run()
+
+	runs, err := svc.GetRuns(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+
+	// newest first
+	assert.Equal(t, now, runs[0].StartedAt)
+	assert.Equal(t, "unexpected status: 429", runs[0].Error)
+	assert.Equal(t, domain.FeedRun{FeedID: 1, StartedAt: now.Add(-10 * time.Minute), Items: 10, NewItems: 3, Matched: 2, HTTPStatus: http.StatusTooManyRequests}, runs[1])
+
+	stats := svc.runStats(1)
+	assert.Equal(t, 2, stats.Runs)
+	assert.Equal(t, 1, stats.Errors)
+	assert.Equal(t, 6, stats.NewItems)
+	assert.Equal(t, 4, stats.Matched)
+	assert.Equal(t, runs[0], *stats.LastRun)
+
+	svc.resetRuns(1)
+	assert.Nil(t, svc.runStats(1))
+}
+
+func Test_service_finishRun_keeps_last_runs(t *testing.T) {
+	svc := &service{runs: map[int]*feedRunHistory{}}
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < domain.FeedRunsKept+5; i++ {
+		started := start.Add(time.Duration(i) * time.Minute)
+		svc.finishRun(svc.startRun(1, started), nil, started.Add(1500*time.Millisecond))
+	}
+
+	runs, err := svc.GetRuns(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, runs, domain.FeedRunsKept)
+	assert.Equal(t, start.Add(time.Duration(domain.FeedRunsKept+4)*time.Minute), runs[0].StartedAt)
+	assert.Equal(t, start.Add(5*time.Minute), runs[len(runs)-1].StartedAt)
+	assert.Equal(t, int64(1500), runs[0].DurationMs)
+
+	assert.Equal(t, domain.FeedRunsKept+5, svc.runStats(1).Runs)
+}
+
+func Test_feedRun_without_run(t *testing.T) {
+	run := feedRunFromContext(context.Background())
+	assert.Nil(t, run)
+
+	// the jobs record on the run without checking whether the refresh is recorded
+	run.addItems(1)
+	run.setNewItems(1)
+	run.setHTTPStatus(http.StatusOK)
+	run.addMatched(1)
+}
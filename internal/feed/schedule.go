@@ -0,0 +1,197 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+// quietSearchMinutes is how far ahead the end of the quiet windows is searched, windows repeat weekly
+const quietSearchMinutes = 8 * 24 * 60
+
+// notBetweenJob skips the runs of the feed job in the not between windows of the feed and the global
+// feed quiet hours, and catches up with one run when the windows end if the feed asks for it
+type notBetweenJob struct {
+	job      cron.Job
+	schedule *domain.FilterSchedule
+
+	// quietHours returns the global feed quiet hours, read on every run to follow config reloads
+	quietHours func() string
+
+	// catchUp schedules run at until, nil to not catch up
+	catchUp func(until time.Time, run func())
+
+	log zerolog.Logger
+	now func() time.Time
+}
+
+func (j *notBetweenJob) Run() {
+	now := j.now()
+
+	schedules := j.schedules()
+	if !quietActive(schedules, now) {
+		j.job.Run()
+		return
+	}
+
+	j.log.Debug().Msg("feed refresh skipped, in not between window or quiet hours")
+
+	if j.catchUp == nil {
+		return
+	}
+
+	if until := quietUntil(schedules, now); !until.IsZero() {
+		j.catchUp(until, j.Run)
+	}
+}
+
+// schedules returns the not between windows of the feed and the global quiet hours
+func (j *notBetweenJob) schedules() []*domain.FilterSchedule {
+	var schedules []*domain.FilterSchedule
+	if j.schedule != nil {
+		schedules = append(schedules, j.schedule)
+	}
+
+	if j.quietHours == nil {
+		return schedules
+	}
+
+	value := j.quietHours()
+	if value == "" {
+		return schedules
+	}
+
+	schedule, err := domain.ParseFilterSchedule(value)
+	if err != nil {
+		j.log.Error().Err(err).Msgf("invalid feed quiet hours: %q", value)
+		return schedules
+	}
+
+	return append(schedules, schedule)
+}
+
+func quietActive(schedules []*domain.FilterSchedule, t time.Time) bool {
+	for _, schedule := range schedules {
+		if schedule.Active(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quietUntil returns the first minute after t outside all the windows, zero when they never end
+func quietUntil(schedules []*domain.FilterSchedule, t time.Time) time.Time {
+	next := t.Truncate(time.Minute)
+
+	for i := 0; i < quietSearchMinutes; i++ {
+		next = next.Add(time.Minute)
+		if !quietActive(schedules, next) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// scheduleCatchUp runs the feed job once at until, the runs skipped until then share the catch-up
+func (s *service) scheduleCatchUp(id int, until time.Time, run func()) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.catchUps == nil {
+		s.catchUps = map[int]*time.Timer{}
+	}
+
+	if _, ok := s.catchUps[id]; ok {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(until), func() {
+		s.m.Lock()
+		if s.catchUps[id] != timer {
+			s.m.Unlock()
+			return
+		}
+		delete(s.catchUps, id)
+		s.m.Unlock()
+
+		s.log.Debug().Msgf("feed %d catching up after quiet window", id)
+
+		run()
+	})
+
+	s.catchUps[id] = timer
+}
+
+// cancelCatchUp stops the pending catch-up of the stopped feed
+func (s *service) cancelCatchUp(id int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if timer, ok := s.catchUps[id]; ok {
+		timer.Stop()
+		delete(s.catchUps, id)
+	}
+}
+
+// scheduleFeedJob schedules the job on the cron expression of the feed or else its interval,
+// and wraps it to record its health and skip the not between windows and quiet hours
+func (s *service) scheduleFeedJob(f *domain.Feed, job cron.Job, interval time.Duration, identifierKey string) (int, error) {
+	if p, ok := job.(feedProcessor); ok {
+		job = &healthJob{
+			svc:      s,
+			feed:     f,
+			job:      p,
+			interval: interval,
+			log:      s.log.With().Str("feed", f.Name).Logger(),
+			now:      time.Now,
+		}
+	}
+
+	quiet := &notBetweenJob{
+		job:        job,
+		quietHours: s.feedQuietHours,
+		log:        s.log.With().Str("feed", f.Name).Logger(),
+		now:        time.Now,
+	}
+
+	if f.Settings != nil && f.Settings.NotBetween != "" {
+		schedule, err := domain.ParseFilterSchedule(f.Settings.NotBetween)
+		if err != nil {
+			return 0, err
+		}
+
+		quiet.schedule = schedule
+	}
+
+	if f.Settings != nil && f.Settings.CatchUp {
+		quiet.catchUp = func(until time.Time, run func()) {
+			s.scheduleCatchUp(f.ID, until, run)
+		}
+	}
+
+	job = quiet
+
+	if f.Settings != nil && f.Settings.Cron != "" {
+		return s.scheduler.AddJob(job, f.Settings.Cron, identifierKey)
+	}
+
+	return s.scheduler.ScheduleJob(job, interval, identifierKey)
+}
+
+// feedQuietHours returns the global quiet hours of the feeds from the config
+func (s *service) feedQuietHours() string {
+	if s.config == nil {
+		return ""
+	}
+
+	return s.config.FeedQuietHours
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingJob struct {
+	runs int
+}
+
+func (j *countingJob) Run() {
+	j.runs++
+}
+
+func Test_notBetweenJob_Run(t *testing.T) {
+	schedule, err := domain.ParseFilterSchedule("02:00-07:00")
+	assert.NoError(t, err)
+
+	counter := &countingJob{}
+	now := time.Date(2023, 6, 1, 3, 0, 0, 0, time.Local)
+
+	job := &notBetweenJob{
+		job:      counter,
+		schedule: schedule,
+		log:      zerolog.Nop(),
+		now:      func() time.Time { return now },
+	}
+
+	job.Run()
+	assert.Equal(t, 0, counter.runs)
+
+	now = time.Date(2023, 6, 1, 8, 0, 0, 0, time.Local)
+	job.Run()
+	assert.Equal(t, 1, counter.runs)
+}
+
+func Test_notBetweenJob_Run_quietHours(t *testing.T) {
+	counter := &countingJob{}
+	now := time.Date(2023, 6, 1, 3, 0, 0, 0, time.Local)
+	quietHours := "01:00-04:00"
+
+	job := &notBetweenJob{
+		job:        counter,
+		quietHours: func() string { return quietHours },
+		log:        zerolog.Nop(),
+		now:        func() time.Time { return now },
+	}
+
+	job.Run()
+	assert.Equal(t, 0, counter.runs)
+
+	// reloaded config
+	quietHours = ""
+	job.Run()
+	assert.Equal(t, 1, counter.runs)
+
+	// invalid quiet hours do not stop the feed
+	quietHours = "25:00-26:00"
+	job.Run()
+	assert.Equal(t, 2, counter.runs)
+}
+
+func Test_notBetweenJob_Run_catchUp(t *testing.T) {
+	schedule, err := domain.ParseFilterSchedule("02:00-07:00")
+	assert.NoError(t, err)
+
+	counter := &countingJob{}
+	now := time.Date(2023, 6, 1, 3, 30, 20, 0, time.Local)
+
+	var until time.Time
+	var run func()
+
+	job := &notBetweenJob{
+		job:        counter,
+		schedule:   schedule,
+		quietHours: func() string { return "06:00-08:00" },
+		catchUp: func(u time.Time, r func()) {
+			until = u
+			run = r
+		},
+		log: zerolog.Nop(),
+		now: func() time.Time { return now },
+	}
+
+	job.Run()
+	assert.Equal(t, 0, counter.runs)
+	assert.Equal(t, time.Date(2023, 6, 1, 8, 0, 0, 0, time.Local), until)
+
+	now = until
+	run()
+	assert.Equal(t, 1, counter.runs)
+}
+
+func Test_quietUntil(t *testing.T) {
+	schedule, err := domain.ParseFilterSchedule("22:00-02:00")
+	assert.NoError(t, err)
+
+	now := time.Date(2023, 6, 1, 23, 15, 0, 0, time.Local)
+	assert.Equal(t, time.Date(2023, 6, 2, 2, 0, 0, 0, time.Local), quietUntil([]*domain.FilterSchedule{schedule}, now))
+
+	always, err := domain.ParseFilterSchedule("sun,mon,tue,wed,thu,fri,sat")
+	assert.NoError(t, err)
+	assert.True(t, quietUntil([]*domain.FilterSchedule{always}, now).IsZero())
+}
+
+func Test_service_scheduleCatchUp(t *testing.T) {
+	s := &service{log: zerolog.Nop()}
+
+	done := make(chan struct{}, 2)
+	s.scheduleCatchUp(1, time.Now().Add(10*time.Millisecond), func() { done <- struct{}{} })
+	// a pending catch-up is not scheduled twice
+	s.scheduleCatchUp(1, time.Now(), func() { done <- struct{}{} })
+
+	<-done
+	select {
+	case <-done:
+		t.Fatal("catch-up ran twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.scheduleCatchUp(2, time.Now().Add(time.Hour), func() { done <- struct{}{} })
+	s.cancelCatchUp(2)
+	assert.Empty(t, s.catchUps)
+}
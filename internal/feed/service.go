@@ -7,10 +7,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -18,7 +21,6 @@ import (
 	"github.com/autobrr/autobrr/pkg/torznab"
 
 	"github.com/dcarbone/zadapters/zstdlog"
-	"github.com/mmcdole/gofeed"
 	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
 )
@@ -28,14 +30,19 @@ type Service interface {
 	FindByIndexerIdentifier(ctx context.Context, indexer string) (*domain.Feed, error)
 	Find(ctx context.Context) ([]domain.Feed, error)
 	GetCacheByID(ctx context.Context, feedId int) ([]domain.FeedCacheItem, error)
+	GetCache(ctx context.Context, feedId int, limit int) (*domain.FeedCache, error)
 	Store(ctx context.Context, feed *domain.Feed) error
 	Update(ctx context.Context, feed *domain.Feed) error
 	Test(ctx context.Context, feed *domain.Feed) error
+	Preview(ctx context.Context, feed *domain.Feed) (*domain.FeedPreview, error)
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Delete(ctx context.Context, id int) error
 	DeleteFeedCache(ctx context.Context, id int) error
 	GetLastRunData(ctx context.Context, id int) (string, error)
 	DeleteFeedCacheStale(ctx context.Context) error
+	Backfill(ctx context.Context, id int, settings *domain.FeedBackfillSettings) error
+	RefreshCaps(ctx context.Context, id int) (*domain.FeedCaps, error)
+	GetRuns(ctx context.Context, id int) ([]domain.FeedRun, error)
 
 	Start() error
 }
@@ -49,6 +56,12 @@ type feedInstance struct {
 	Implementation    string
 	CronSchedule      time.Duration
 	Timeout           time.Duration
+
+	// Transport of the feed with a proxy, headers or login step, nil uses the default
+	Transport http.RoundTripper
+
+	// CacheRepo is shared with the other feeds of the indexer
+	CacheRepo domain.FeedCacheRepo
 }
 
 // feedKey creates a unique identifier to be used for controlling jobs in the scheduler
@@ -62,23 +75,49 @@ func (k feedKey) ToString() string {
 }
 
 type service struct {
-	log  zerolog.Logger
-	jobs map[string]int
+	log    zerolog.Logger
+	config *domain.Config
+	jobs   map[string]int
+
+	repo            domain.FeedRepo
+	cacheRepo       domain.FeedCacheRepo
+	releaseSvc      release.Service
+	scheduler       scheduler.Service
+	notificationSvc notification.Service
+
+	m         sync.Mutex
+	backfills map[int]context.CancelFunc
+	health    map[int]*domain.FeedHealth
+	runs      map[int]*feedRunHistory
+	pushes    map[int]context.CancelFunc
+	catchUps  map[int]*time.Timer
+
+	// gates and feeds of the indexers with more than one feed
+	gates        map[string]*indexerGate
+	indexerFeeds map[string][]int
+
+	// feeds notified as failing to authenticate, until they are authorized again
+	authFailed map[int]bool
 
-	repo       domain.FeedRepo
-	cacheRepo  domain.FeedCacheRepo
-	releaseSvc release.Service
-	scheduler  scheduler.Service
+	// refreshes running at once across all feeds
+	fetches fetchLimiter
 }
 
-func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service, notificationSvc notification.Service) Service {
 	return &service{
-		log:        log.With().Str("module", "feed").Logger(),
-		jobs:       map[string]int{},
-		repo:       repo,
-		cacheRepo:  cacheRepo,
-		releaseSvc: releaseSvc,
-		scheduler:  scheduler,
+		log:             log.With().Str("module", "feed").Logger(),
+		config:          config,
+		jobs:            map[string]int{},
+		repo:            repo,
+		cacheRepo:       cacheRepo,
+		releaseSvc:      releaseSvc,
+		scheduler:       scheduler,
+		notificationSvc: notificationSvc,
+		backfills:       map[int]context.CancelFunc{},
+		health:          map[int]*domain.FeedHealth{},
+		runs:            map[int]*feedRunHistory{},
+		pushes:          map[int]context.CancelFunc{},
+		catchUps:        map[int]*time.Timer{},
 	}
 }
 
@@ -97,11 +136,14 @@ func (s *service) Find(ctx context.Context) ([]domain.Feed, error) {
 	}
 
 	for i, feed := range feeds {
+		feed.Health = s.feedHealth(feed.ID)
+		feed.Runs = s.runStats(feed.ID)
+
 		t, err := s.scheduler.GetNextRun(feedKey{id: feed.ID}.ToString())
-		if err != nil {
-			continue
+		if err == nil {
+			feed.NextRun = t
 		}
-		feed.NextRun = t
+
 		feeds[i] = feed
 	}
 
@@ -109,11 +151,67 @@ func (s *service) Find(ctx context.Context) ([]domain.Feed, error) {
 }
 
 func (s *service) GetCacheByID(ctx context.Context, feedId int) ([]domain.FeedCacheItem, error) {
-	return s.cacheRepo.GetByFeed(ctx, feedId)
+	return s.cacheRepo.GetByFeed(ctx, feedId, 0)
+}
+
+// GetCache returns the stats of the cache of the feed with its newest items
+func (s *service) GetCache(ctx context.Context, feedId int, limit int) (*domain.FeedCache, error) {
+	stats, err := s.cacheRepo.GetStatsByFeed(ctx, feedId)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.cacheRepo.GetByFeed(ctx, feedId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FeedCache{FeedCacheStats: *stats, Items: items}, nil
+}
+
+// RefreshCaps fetches and stores the caps of the torznab feed now
+func (s *service) RefreshCaps(ctx context.Context, id int) (*domain.FeedCaps, error) {
+	f, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msg("error finding feed")
+		return nil, err
+	}
+
+	if f.Type != string(domain.FeedTypeTorznab) {
+		return nil, errors.New("%s feed has no caps", f.Type)
+	}
+
+	job, err := s.newFeedJob(f)
+	if err != nil {
+		return nil, err
+	}
+
+	torznabJob, ok := job.(*TorznabJob)
+	if !ok {
+		return nil, errors.New("%s feed has no caps", f.Type)
+	}
+
+	caps, err := torznabJob.updateCaps(ctx, time.Now())
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not refresh caps of feed: %s", f.Name)
+		return nil, errors.Wrap(err, "could not refresh caps")
+	}
+
+	return caps, nil
 }
 
 func (s *service) Store(ctx context.Context, feed *domain.Feed) error {
-	return s.repo.Store(ctx, feed)
+	if err := feed.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Store(ctx, feed); err != nil {
+		return err
+	}
+
+	s.resetIndexerFeeds()
+
+	return nil
 }
 
 func (s *service) Update(ctx context.Context, feed *domain.Feed) error {
@@ -145,11 +243,17 @@ func (s *service) Start() error {
 }
 
 func (s *service) update(ctx context.Context, feed *domain.Feed) error {
+	if err := feed.Validate(); err != nil {
+		return err
+	}
+
 	if err := s.repo.Update(ctx, feed); err != nil {
 		s.log.Error().Err(err).Msg("error updating feed")
 		return err
 	}
 
+	s.resetIndexerFeeds()
+
 	if err := s.restartJob(feed); err != nil {
 		s.log.Error().Err(err).Msg("error restarting feed")
 		return err
@@ -178,6 +282,9 @@ func (s *service) delete(ctx context.Context, id int) error {
 		return err
 	}
 
+	s.resetRuns(f.ID)
+	s.resetIndexerFeeds()
+
 	return nil
 }
 
@@ -227,20 +334,36 @@ func (s *service) test(ctx context.Context, feed *domain.Feed) error {
 	// create sub logger
 	subLogger := zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.DebugLevel)
 
+	// the test feed is not stored, a refreshed cookie is only used for the test
+	transport, err := newFeedTransport(feed, nil, s.log)
+	if err != nil {
+		return err
+	}
+
 	// test feeds
 	switch feed.Type {
 	case string(domain.FeedTypeTorznab):
-		if err := s.testTorznab(ctx, feed, subLogger); err != nil {
+		if err := s.testTorznab(ctx, feed, subLogger, transport); err != nil {
 			return err
 		}
 
 	case string(domain.FeedTypeNewznab):
-		if err := s.testNewznab(ctx, feed, subLogger); err != nil {
+		if err := s.testNewznab(ctx, feed, subLogger, transport); err != nil {
 			return err
 		}
 
 	case string(domain.FeedTypeRSS):
-		if err := s.testRSS(ctx, feed); err != nil {
+		if err := s.testRSS(ctx, feed, transport); err != nil {
+			return err
+		}
+
+	case string(domain.FeedTypeJSON):
+		if err := s.testJSON(ctx, feed, transport); err != nil {
+			return err
+		}
+
+	case string(domain.FeedTypePush):
+		if err := s.testPush(ctx, feed, transport); err != nil {
 			return err
 		}
 
@@ -253,8 +376,8 @@ func (s *service) test(ctx context.Context, feed *domain.Feed) error {
 	return nil
 }
 
-func (s *service) testRSS(ctx context.Context, feed *domain.Feed) error {
-	f, err := gofeed.NewParser().ParseURLWithContext(feed.URL, ctx)
+func (s *service) testRSS(ctx context.Context, feed *domain.Feed, transport http.RoundTripper) error {
+	f, err := NewFeedParser(feedTimeout(feed), feed.Cookie).WithTransport(transport).ParseURLWithContext(ctx, feed.URL)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("error fetching rss feed items")
 		return errors.Wrap(err, "error fetching rss feed items")
@@ -265,9 +388,47 @@ func (s *service) testRSS(ctx context.Context, feed *domain.Feed) error {
 	return nil
 }
 
-func (s *service) testTorznab(ctx context.Context, feed *domain.Feed, subLogger *log.Logger) error {
+func (s *service) testJSON(ctx context.Context, feed *domain.Feed, transport http.RoundTripper) error {
+	if err := feed.Validate(); err != nil {
+		return err
+	}
+
+	job := NewJSONJob(feed, feed.Name, feed.Indexer, s.log, feed.URL, s.repo, s.cacheRepo, s.releaseSvc, feedTimeout(feed))
+	job.withTransport(transport)
+
+	items, _, _, err := job.fetchPage(ctx, feed.Settings.JSON.PageStart, feedValidators{})
+	if err != nil {
+		s.log.Error().Err(err).Msgf("error fetching json feed items")
+		return errors.Wrap(err, "error fetching json feed items")
+	}
+
+	s.log.Info().Msgf("refreshing json feed: %s, found (%d) items", feed.Name, len(items))
+
+	return nil
+}
+
+// testPush connects to the push feed, it does not wait for a message
+func (s *service) testPush(ctx context.Context, feed *domain.Feed, transport http.RoundTripper) error {
+	if err := feed.Validate(); err != nil {
+		return err
+	}
+
+	job := NewPushJob(feed, feed.Name, feed.Indexer, s.log, feed.URL, s.repo, s.cacheRepo, s.releaseSvc, feedTimeout(feed))
+	job.withTransport(transport)
+
+	if err := job.stream(ctx, func() error { return errStopStream }, func(data []byte) error { return nil }); err != nil {
+		s.log.Error().Err(err).Msgf("error connecting to push feed")
+		return errors.Wrap(err, "error connecting to push feed")
+	}
+
+	s.log.Info().Msgf("connected to push feed: %s", feed.Name)
+
+	return nil
+}
+
+func (s *service) testTorznab(ctx context.Context, feed *domain.Feed, subLogger *log.Logger, transport http.RoundTripper) error {
 	// setup torznab Client
-	c := torznab.NewClient(torznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Log: subLogger})
+	c := torznab.NewClient(torznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Timeout: feedTimeout(feed), Log: subLogger, Transport: transport})
 
 	items, err := c.FetchFeed(ctx)
 	if err != nil {
@@ -280,9 +441,9 @@ func (s *service) testTorznab(ctx context.Context, feed *domain.Feed, subLogger
 	return nil
 }
 
-func (s *service) testNewznab(ctx context.Context, feed *domain.Feed, subLogger *log.Logger) error {
+func (s *service) testNewznab(ctx context.Context, feed *domain.Feed, subLogger *log.Logger, transport http.RoundTripper) error {
 	// setup newznab Client
-	c := newznab.NewClient(newznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Log: subLogger})
+	c := newznab.NewClient(newznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Timeout: feedTimeout(feed), Log: subLogger, Transport: transport})
 
 	items, err := c.GetFeed(ctx)
 	if err != nil {
@@ -346,9 +507,57 @@ func (s *service) startJob(f *domain.Feed) error {
 		return nil
 	}
 
+	job, err := s.newFeedJob(f)
+	if err != nil {
+		return err
+	}
+
+	// push feeds keep a connection instead of being refreshed
+	if push, ok := job.(*PushJob); ok {
+		s.startPushJob(f, push)
+
+		s.log.Debug().Msgf("successfully started push feed: %s", f.Name)
+
+		return nil
+	}
+
+	identifierKey := feedKey{f.ID}.ToString()
+
+	// schedule job
+	id, err := s.scheduleFeedJob(f, job, time.Duration(f.Interval)*time.Minute, identifierKey)
+	if err != nil {
+		return errors.Wrap(err, "add job %s failed", identifierKey)
+	}
+
+	// add to job map
+	s.jobs[identifierKey] = id
+
+	s.log.Debug().Msgf("successfully started feed: %s", f.Name)
+
+	if f.Settings != nil && f.Settings.Backfill != nil && f.Settings.Backfill.OnFirstRun {
+		s.backfillFirstRun(f, job)
+	}
+
+	return nil
+}
+
+// newFeedJob creates the job that refreshes the feed
+func (s *service) newFeedJob(f *domain.Feed) (cron.Job, error) {
+	transport, err := newFeedTransport(f, s.repo, s.log)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not setup transport for feed: %s", f.Name)
+	}
+
+	return s.createFeedJob(f, newRunTransport(transport, f.Type, func(err error) {
+		s.recordAuth(f, err)
+	}))
+}
+
+// createFeedJob creates the job of the feed type, requesting the feed with the transport
+func (s *service) createFeedJob(f *domain.Feed, transport http.RoundTripper) (cron.Job, error) {
 	// get torznab_url from settings
 	if f.URL == "" {
-		return errors.New("no URL provided for feed: %s", f.Name)
+		return nil, errors.New("no URL provided for feed: %s", f.Name)
 	}
 
 	// cron schedule to run every X minutes
@@ -360,7 +569,9 @@ func (s *service) startJob(f *domain.Feed) error {
 		URL:               f.URL,
 		ApiKey:            f.ApiKey,
 		CronSchedule:      time.Duration(f.Interval) * time.Minute,
-		Timeout:           time.Duration(f.Timeout) * time.Second,
+		Timeout:           feedTimeout(f),
+		Transport:         transport,
+		CacheRepo:         s.feedCache(f),
 	}
 
 	var err error
@@ -376,29 +587,22 @@ func (s *service) startJob(f *domain.Feed) error {
 	case string(domain.FeedTypeRSS):
 		job, err = s.createRSSJob(fi)
 
+	case string(domain.FeedTypeJSON):
+		job, err = s.createJSONJob(fi)
+
+	case string(domain.FeedTypePush):
+		job, err = s.createPushJob(fi)
+
 	default:
-		return errors.New("unsupported feed type: %s", fi.Implementation)
+		return nil, errors.New("unsupported feed type: %s", fi.Implementation)
 	}
 
 	if err != nil {
 		s.log.Error().Err(err).Msgf("failed to initialize %s feed", fi.Implementation)
-		return err
-	}
-
-	identifierKey := feedKey{f.ID}.ToString()
-
-	// schedule job
-	id, err := s.scheduler.ScheduleJob(job, fi.CronSchedule, identifierKey)
-	if err != nil {
-		return errors.Wrap(err, "add job %s failed", identifierKey)
+		return nil, err
 	}
 
-	// add to job map
-	s.jobs[identifierKey] = id
-
-	s.log.Debug().Msgf("successfully started feed: %s", f.Name)
-
-	return nil
+	return job, nil
 }
 
 func (s *service) createTorznabJob(f feedInstance) (cron.Job, error) {
@@ -416,10 +620,10 @@ func (s *service) createTorznabJob(f feedInstance) (cron.Job, error) {
 	l := s.log.With().Str("feed", f.Name).Logger()
 
 	// setup torznab Client
-	client := torznab.NewClient(torznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout})
+	client := torznab.NewClient(torznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout, Transport: f.Transport})
 
 	// create job
-	job := NewTorznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc)
+	job := NewTorznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, client, s.repo, f.CacheRepo, s.releaseSvc)
 
 	return job, nil
 }
@@ -435,10 +639,10 @@ func (s *service) createNewznabJob(f feedInstance) (cron.Job, error) {
 	l := s.log.With().Str("feed", f.Name).Logger()
 
 	// setup newznab Client
-	client := newznab.NewClient(newznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout})
+	client := newznab.NewClient(newznab.Config{Host: f.URL, ApiKey: f.ApiKey, Timeout: f.Timeout, Transport: f.Transport})
 
 	// create job
-	job := NewNewznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, client, s.repo, s.cacheRepo, s.releaseSvc)
+	job := NewNewznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, client, s.repo, f.CacheRepo, s.releaseSvc)
 
 	return job, nil
 }
@@ -458,7 +662,51 @@ func (s *service) createRSSJob(f feedInstance) (cron.Job, error) {
 	l := s.log.With().Str("feed", f.Name).Logger()
 
 	// create job
-	job := NewRSSJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, s.repo, s.cacheRepo, s.releaseSvc, f.Timeout)
+	job := NewRSSJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, s.repo, f.CacheRepo, s.releaseSvc, f.Timeout)
+	job.Transport = f.Transport
+
+	return job, nil
+}
+
+func (s *service) createJSONJob(f feedInstance) (cron.Job, error) {
+	s.log.Debug().Msgf("add json job: %s", f.Name)
+
+	if f.URL == "" {
+		return nil, errors.New("json feed requires URL")
+	}
+
+	if err := f.Feed.Validate(); err != nil {
+		return nil, err
+	}
+
+	// setup logger
+	l := s.log.With().Str("feed", f.Name).Logger()
+
+	// create job
+	job := NewJSONJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, s.repo, f.CacheRepo, s.releaseSvc, f.Timeout)
+	job.withTransport(f.Transport)
+
+	return job, nil
+}
+
+func (s *service) createPushJob(f feedInstance) (cron.Job, error) {
+	s.log.Debug().Msgf("add push job: %s", f.Name)
+
+	if f.URL == "" {
+		return nil, errors.New("push feed requires URL")
+	}
+
+	if err := f.Feed.Validate(); err != nil {
+		return nil, err
+	}
+
+	// setup logger
+	l := s.log.With().Str("feed", f.Name).Logger()
+
+	// create job
+	job := NewPushJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, s.repo, f.CacheRepo, s.releaseSvc, f.Timeout)
+	job.withTransport(f.Transport)
+	job.svc = s
 
 	return job, nil
 }
@@ -468,12 +716,12 @@ func (s *service) createCleanupJob() error {
 	l := s.log.With().Str("job", "feed-cache-cleanup").Logger()
 
 	// create job
-	job := NewCleanupJob(l, s.cacheRepo)
+	job := NewCleanupJob(l, s.config, s.cacheRepo)
 
 	identifierKey := "feed-cache-cleanup"
 
-	// schedule job for every day at 03:05
-	id, err := s.scheduler.AddJob(job, "5 3 * * *", identifierKey)
+	// schedule job for every hour at minute 5, busy feeds reach the max entries fast
+	id, err := s.scheduler.AddJob(job, "5 * * * *", identifierKey)
 	if err != nil {
 		return errors.Wrap(err, "add job %s failed", identifierKey)
 	}
@@ -485,6 +733,11 @@ func (s *service) createCleanupJob() error {
 }
 
 func (s *service) stopFeedJob(id int) error {
+	s.cancelBackfill(id)
+	s.stopPushJob(id)
+	s.cancelCatchUp(id)
+	s.resetHealth(id)
+
 	// remove job from scheduler
 	if err := s.scheduler.RemoveJobByIdentifier(feedKey{id}.ToString()); err != nil {
 		return errors.Wrap(err, "stop job failed")
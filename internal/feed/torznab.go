@@ -65,6 +65,8 @@ func (j *TorznabJob) Run() {
 }
 
 func (j *TorznabJob) process(ctx context.Context) error {
+	j.refreshCaps(ctx)
+
 	// get feed
 	items, err := j.getFeed(ctx)
 	if err != nil {
@@ -74,6 +76,8 @@ func (j *TorznabJob) process(ctx context.Context) error {
 
 	j.Log.Debug().Msgf("found (%d) new items to process", len(items))
 
+	feedRunFromContext(ctx).setNewItems(len(items))
+
 	if len(items) == 0 {
 		return nil
 	}
@@ -89,53 +93,168 @@ func (j *TorznabJob) process(ctx context.Context) error {
 			}
 		}
 
-		rls := domain.NewRelease(j.IndexerIdentifier)
+		releases = append(releases, j.mapRelease(item))
+	}
 
-		rls.TorrentName = item.Title
-		rls.DownloadURL = item.Link
-		rls.Implementation = domain.ReleaseImplementationTorznab
+	// process all new releases
+	go processReleases(ctx, j.ReleaseSvc, releases)
 
-		// parse size bytes string
-		rls.ParseSizeBytesString(item.Size)
+	return nil
+}
 
-		rls.ParseString(item.Title)
+// mapRelease creates the release of the item, with categories and freeleech from its torznab attributes
+func (j *TorznabJob) mapRelease(item torznab.FeedItem) *domain.Release {
+	rls := domain.NewRelease(j.IndexerIdentifier)
+
+	rls.TorrentName = item.Title
+	rls.DownloadURL = item.Link
+	rls.Implementation = domain.ReleaseImplementationTorznab
+
+	// parse size bytes string
+	rls.ParseSizeBytesString(item.Size)
+
+	rls.ParseString(item.Title)
+
+	if j.Feed.Settings != nil && j.Feed.Settings.DownloadType == domain.FeedDownloadTypeMagnet {
+		rls.MagnetURI = item.Link
+		rls.DownloadURL = ""
+	}
 
-		if j.Feed.Settings != nil && j.Feed.Settings.DownloadType == domain.FeedDownloadTypeMagnet {
-			rls.MagnetURI = item.Link
-			rls.DownloadURL = ""
+	// Get freeleech percentage between 0 - 100. The value is ignored if
+	// an error occurrs
+	freeleechPercentage, err := parseFreeleechTorznab(item)
+	if err != nil {
+		j.Log.Debug().Err(err).Msgf("error parsing torznab freeleech")
+	} else {
+		if freeleechPercentage == 100 {
+			// Release is 100% freeleech
+			rls.Freeleech = true
+			rls.Bonus = []string{"Freeleech"}
 		}
 
-		// Get freeleech percentage between 0 - 100. The value is ignored if
-		// an error occurrs
-		freeleechPercentage, err := parseFreeleechTorznab(item)
-		if err != nil {
-			j.Log.Debug().Err(err).Msgf("error parsing torznab freeleech")
-		} else {
-			if freeleechPercentage == 100 {
-				// Release is 100% freeleech
-				rls.Freeleech = true
-				rls.Bonus = []string{"Freeleech"}
+		rls.FreeleechPercent = freeleechPercentage
+		if bonus := mapFreeleechToBonus(freeleechPercentage); bonus != "" {
+			rls.Bonus = append(rls.Bonus, bonus)
+		}
+	}
+
+	if seeders, leechers, ok := parsePeersTorznab(item); ok {
+		rls.Seeders = seeders
+		rls.Leechers = leechers
+		rls.PeersKnown = true
+	}
+
+	// map torznab categories ID and Name into rls.Categories
+	// so we can filter on both ID and Name
+	for _, category := range item.Categories {
+		rls.Categories = append(rls.Categories, []string{category.Name, strconv.Itoa(category.ID)}...)
+	}
+
+	if j.Feed.Settings != nil {
+		rls.Categories = appendMappedCategories(rls.Categories, item.Category, j.Feed.Settings.CategoryMapping)
+	}
+
+	return rls
+}
+
+// appendMappedCategories appends the categories the torznab categories of the item are mapped to
+func appendMappedCategories(categories []string, ids []int, mappings []domain.FeedCategoryMapping) []string {
+	for _, m := range mappings {
+		for _, id := range ids {
+			if !m.Matches(id) {
+				continue
 			}
 
-			rls.FreeleechPercent = freeleechPercentage
-			if bonus := mapFreeleechToBonus(freeleechPercentage); bonus != "" {
-				rls.Bonus = append(rls.Bonus, bonus)
+			if !containsCategory(categories, m.Category) {
+				categories = append(categories, m.Category)
 			}
+			break
+		}
+	}
+
+	return categories
+}
+
+func containsCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
 		}
+	}
+
+	return false
+}
+
+// refreshCaps fetches the caps of the feed when they are missing or stale and stores its category tree. Fresh stored
+// caps are given to the client, so it does not request them again after a restart.
+func (j *TorznabJob) refreshCaps(ctx context.Context) {
+	now := time.Now()
 
-		// map torznab categories ID and Name into rls.Categories
-		// so we can filter on both ID and Name
-		for _, category := range item.Categories {
-			rls.Categories = append(rls.Categories, []string{category.Name, strconv.Itoa(category.ID)}...)
+	if !j.Feed.Caps.Stale(now) {
+		if j.Client.GetCaps() == nil {
+			j.Client.SetCaps(&torznab.Caps{Categories: torznab.CapCategories{Categories: torznabCategories(j.Feed.Caps.Categories)}})
 		}
+		return
+	}
+
+	if _, err := j.updateCaps(ctx, now); err != nil {
+		j.Log.Warn().Err(err).Msg("could not refresh torznab caps")
+	}
+}
 
-		releases = append(releases, rls)
+// updateCaps fetches and stores the caps of the feed, and warns about category mappings of categories it does not have
+func (j *TorznabJob) updateCaps(ctx context.Context, now time.Time) (*domain.FeedCaps, error) {
+	caps, err := j.Client.FetchCaps(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// process all new releases
-	go j.ReleaseSvc.ProcessMultiple(releases)
+	j.Client.SetCaps(caps)
 
-	return nil
+	feedCaps := &domain.FeedCaps{
+		Categories: feedCategories(caps.Categories.Categories),
+		UpdatedAt:  now,
+	}
+
+	if err := j.Repo.UpdateCaps(ctx, j.Feed.ID, feedCaps); err != nil {
+		j.Log.Error().Err(err).Msgf("error updating caps for feed id: %v", j.Feed.ID)
+	}
+
+	j.Feed.Caps = feedCaps
+
+	if j.Feed.Settings != nil {
+		for _, m := range feedCaps.StaleCategoryMappings(j.Feed.Settings.CategoryMapping) {
+			j.Log.Warn().Msgf("torznab category %d mapped to %s is not in the caps of the indexer anymore", m.ID, m.Category)
+		}
+	}
+
+	return feedCaps, nil
+}
+
+func feedCategories(categories []torznab.Category) []domain.FeedCategory {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	result := make([]domain.FeedCategory, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, domain.FeedCategory{ID: c.ID, Name: c.Name, SubCategories: feedCategories(c.SubCategories)})
+	}
+
+	return result
+}
+
+func torznabCategories(categories []domain.FeedCategory) []torznab.Category {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	result := make([]torznab.Category, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, torznab.Category{ID: c.ID, Name: c.Name, SubCategories: torznabCategories(c.SubCategories)})
+	}
+
+	return result
 }
 
 // Parse the downloadvolumefactor attribute. The returned value is the percentage
@@ -169,6 +288,35 @@ func parseFreeleechTorznab(item torznab.FeedItem) (int, error) {
 	return 0, nil
 }
 
+// Parse the seeders and peers attributes. Peers include the seeders, so the
+// leechers are the difference. Returns false if the item has no seeders.
+func parsePeersTorznab(item torznab.FeedItem) (int, int, bool) {
+	seeders, peers := -1, -1
+	for _, attr := range item.Attributes {
+		switch attr.Name {
+		case "seeders":
+			if v, err := strconv.Atoi(attr.Value); err == nil {
+				seeders = v
+			}
+		case "peers":
+			if v, err := strconv.Atoi(attr.Value); err == nil {
+				peers = v
+			}
+		}
+	}
+
+	if seeders < 0 {
+		return 0, 0, false
+	}
+
+	leechers := 0
+	if peers > seeders {
+		leechers = peers - seeders
+	}
+
+	return seeders, leechers, true
+}
+
 // Maps a freeleech percentage of 25, 50, 75 or 100 to a bonus.
 func mapFreeleechToBonus(percentage int) string {
 	switch percentage {
@@ -187,8 +335,13 @@ func mapFreeleechToBonus(percentage int) string {
 
 func (j *TorznabJob) getFeed(ctx context.Context) ([]torznab.FeedItem, error) {
 	// get feed
-	feed, err := j.Client.FetchFeed(ctx)
+	feed, err := j.Client.FetchFeedIfModified(ctx, j.Feed.ETag, j.Feed.LastModified)
 	if err != nil {
+		if errors.Is(err, torznab.ErrNotModified) {
+			feedNotModified(ctx, j.Repo, j.Feed, j.Log)
+			return nil, nil
+		}
+
 		j.Log.Error().Err(err).Msgf("error fetching feed items")
 		return nil, errors.Wrap(err, "error fetching feed items")
 	}
@@ -197,8 +350,12 @@ func (j *TorznabJob) getFeed(ctx context.Context) ([]torznab.FeedItem, error) {
 		j.Log.Error().Err(err).Msgf("error updating last run for feed id: %v", j.Feed.ID)
 	}
 
+	storeValidators(ctx, j.Repo, j.Feed, j.Log, feedValidators{ETag: feed.ETag, LastModified: feed.LastModified})
+
 	j.Log.Debug().Msgf("refreshing feed: %v, found (%d) items", j.Name, len(feed.Channel.Items))
 
+	feedRunFromContext(ctx).addItems(len(feed.Channel.Items))
+
 	items := make([]torznab.FeedItem, 0)
 	if len(feed.Channel.Items) == 0 {
 		return items, nil
@@ -234,6 +391,12 @@ func (j *TorznabJob) getFeed(ctx context.Context) ([]torznab.FeedItem, error) {
 			continue
 		}
 
+		// excluded items are cached too, so they are not matched again
+		if !j.Feed.Settings.TitleAllowed(i.Title) {
+			j.Log.Trace().Msgf("title excluded by the feed title patterns, skipping release: %s", i.Title)
+			continue
+		}
+
 		// only append if we successfully added to cache
 		items = append(items, *i)
 	}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/torznab"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePeersTorznab(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    []torznab.ItemAttr
+		seeders  int
+		leechers int
+		ok       bool
+	}{
+		{
+			name:  "no_attributes",
+			attrs: nil,
+			ok:    false,
+		},
+		{
+			name:     "seeders_and_peers",
+			attrs:    []torznab.ItemAttr{{Name: "seeders", Value: "12"}, {Name: "peers", Value: "15"}},
+			seeders:  12,
+			leechers: 3,
+			ok:       true,
+		},
+		{
+			name:    "seeders_only",
+			attrs:   []torznab.ItemAttr{{Name: "seeders", Value: "4"}},
+			seeders: 4,
+			ok:      true,
+		},
+		{
+			name:  "invalid_seeders",
+			attrs: []torznab.ItemAttr{{Name: "seeders", Value: "many"}, {Name: "peers", Value: "15"}},
+			ok:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seeders, leechers, ok := parsePeersTorznab(torznab.FeedItem{Attributes: tt.attrs})
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.seeders, seeders)
+			assert.Equal(t, tt.leechers, leechers)
+		})
+	}
+}
+
+func Test_appendMappedCategories(t *testing.T) {
+	mappings := []domain.FeedCategoryMapping{{ID: 5000, Category: "TV"}, {ID: 5040, Category: "TV/HD"}, {ID: 100001, Category: "Anime"}}
+
+	assert.Equal(t, []string{"TV/HD", "5040", "TV"}, appendMappedCategories([]string{"TV/HD", "5040"}, []int{5040}, mappings))
+	assert.Equal(t, []string{"Anime"}, appendMappedCategories(nil, []int{100001, 2000}, mappings))
+	assert.Nil(t, appendMappedCategories(nil, []int{2040}, mappings))
+}
+
+type testTorznabClient struct {
+	torznab.Client
+	caps  *torznab.Caps
+	fetch int
+}
+
+func (c *testTorznabClient) FetchCaps(ctx context.Context) (*torznab.Caps, error) {
+	c.fetch++
+	return &torznab.Caps{Categories: torznab.CapCategories{Categories: []torznab.Category{{ID: 5000, Name: "TV", SubCategories: []torznab.Category{{ID: 5040, Name: "TV/HD"}}}}}}, nil
+}
+
+func (c *testTorznabClient) GetCaps() *torznab.Caps {
+	return c.caps
+}
+
+func (c *testTorznabClient) SetCaps(caps *torznab.Caps) {
+	c.caps = caps
+}
+
+type testCapsRepo struct {
+	domain.FeedRepo
+	caps *domain.FeedCaps
+}
+
+func (r *testCapsRepo) UpdateCaps(ctx context.Context, feedID int, caps *domain.FeedCaps) error {
+	r.caps = caps
+	return nil
+}
+
+func TestTorznabJob_refreshCaps(t *testing.T) {
+	client := &testTorznabClient{}
+	repo := &testCapsRepo{}
+	feed := &domain.Feed{ID: 1}
+
+	job := NewTorznabJob(feed, "test", "mock-feed", zerolog.Nop(), "", client, repo, nil, nil)
+
+	// missing caps are fetched and stored
+	job.refreshCaps(context.Background())
+	assert.Equal(t, 1, client.fetch)
+	assert.NotNil(t, repo.caps)
+	assert.Equal(t, []domain.FeedCategory{{ID: 5000, Name: "TV", SubCategories: []domain.FeedCategory{{ID: 5040, Name: "TV/HD"}}}}, feed.Caps.Categories)
+
+	// fresh stored caps are given to a new client without fetching them
+	client = &testTorznabClient{}
+	job = NewTorznabJob(feed, "test", "mock-feed", zerolog.Nop(), "", client, repo, nil, nil)
+
+	job.refreshCaps(context.Background())
+	assert.Equal(t, 0, client.fetch)
+	assert.Equal(t, 5040, client.caps.Categories.Categories[0].SubCategories[0].ID)
+
+	// stale caps are fetched again
+	feed.Caps.UpdatedAt = time.Now().Add(-domain.FeedCapsRefreshInterval)
+
+	job.refreshCaps(context.Background())
+	assert.Equal(t, 1, client.fetch)
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/publicsuffix"
+)
+
+// loginResponseMaxBytes is the size of the login response read for a renewed api key
+const loginResponseMaxBytes = 1 << 20
+
+// feedTransport requests a feed through its proxy, with its extra headers and its cookie. Feeds with a login step
+// log in when they have no cookie, and log in again and retry once when the feed answers 401 or 403. A login can
+// renew the api key of the feed, which then replaces the key the requests were built with.
+type feedTransport struct {
+	base    *http.Transport
+	proxy   *url.URL
+	feed    *domain.Feed
+	headers map[string]string
+	login   *domain.FeedLoginSettings
+	repo    domain.FeedRepo
+	log     zerolog.Logger
+
+	// jobKey is the api key of the feed when its job was created
+	jobKey string
+
+	m      sync.Mutex
+	cookie string
+	apiKey string
+}
+
+// feedLoginError is returned by the transport when the login step of the feed fails
+type feedLoginError struct {
+	err error
+}
+
+func (e *feedLoginError) Error() string {
+	return "feed login failed: " + e.err.Error()
+}
+
+func (e *feedLoginError) Unwrap() error {
+	return e.err
+}
+
+// newFeedTransport returns the transport of the feed, nil when the feed has no proxy, headers or login step
+func newFeedTransport(f *domain.Feed, repo domain.FeedRepo, log zerolog.Logger) (http.RoundTripper, error) {
+	if f.Settings == nil || (f.Settings.Proxy == "" && f.Settings.Headers == "" && !f.Settings.HasLogin()) {
+		return nil, nil
+	}
+
+	headers, err := domain.ParseFeedHeaders(f.Settings.Headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid headers")
+	}
+
+	base := sharedFeedTransport()
+
+	var proxyURL *url.URL
+	if f.Settings.Proxy != "" {
+		if err := domain.ValidateFeedProxy(f.Settings.Proxy); err != nil {
+			return nil, err
+		}
+
+		if proxyURL, err = url.Parse(f.Settings.Proxy); err != nil {
+			return nil, errors.Wrap(err, "invalid proxy url")
+		}
+
+		base = base.Clone()
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	t := &feedTransport{
+		base:    base,
+		proxy:   proxyURL,
+		feed:    f,
+		headers: headers,
+		repo:    repo,
+		log:     log.With().Str("feed", f.Name).Logger(),
+		jobKey:  f.ApiKey,
+		cookie:  f.Cookie,
+		apiKey:  f.ApiKey,
+	}
+
+	if f.Settings.HasLogin() {
+		t.login = f.Settings.Login
+	}
+
+	return t, nil
+}
+
+func (t *feedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cookie, apiKey, err := t.currentCredentials(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(t.prepare(req, cookie, apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// the session or api key expired, only requests without a body can be sent again
+	if t.login != nil && req.Body == nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		t.log.Debug().Msgf("feed answered %d, logging in again", resp.StatusCode)
+
+		cookie, apiKey, err := t.refreshLogin(req.Context())
+		if err != nil {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		return t.base.RoundTrip(t.prepare(req, cookie, apiKey))
+	}
+
+	return resp, nil
+}
+
+// prepare clones the request with the headers and cookie of the feed, and its renewed api key in place of the key
+// the request was built with
+func (t *feedTransport) prepare(req *http.Request, cookie string, apiKey string) *http.Request {
+	r := req.Clone(req.Context())
+
+	for name, value := range t.headers {
+		r.Header.Set(name, value)
+	}
+
+	if cookie != "" {
+		r.Header.Set("Cookie", cookie)
+	}
+
+	if t.jobKey != "" && apiKey != "" && apiKey != t.jobKey {
+		r.URL.RawQuery = strings.ReplaceAll(r.URL.RawQuery, url.QueryEscape(t.jobKey), url.QueryEscape(apiKey))
+
+		for name, values := range r.Header {
+			for i, value := range values {
+				values[i] = strings.ReplaceAll(value, t.jobKey, apiKey)
+			}
+			r.Header[name] = values
+		}
+	}
+
+	return r
+}
+
+// currentCredentials returns the cookie and api key of the feed, after logging in when the feed has no cookie.
+// Feeds that renew their api key do not need a cookie.
+func (t *feedTransport) currentCredentials(ctx context.Context) (string, string, error) {
+	cookie, apiKey := t.credentials()
+
+	if t.login != nil && cookie == "" && (t.login.APIKeyPath == "" || apiKey == "") {
+		return t.refreshLogin(ctx)
+	}
+
+	return cookie, apiKey, nil
+}
+
+// websocketRequest returns the handshake request of a websocket of the feed, with its headers, cookie and renewed
+// api key. Websockets are not dialed through the transport, so they take the credentials from here.
+func (t *feedTransport) websocketRequest(ctx context.Context, rawURL string, header http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+	req.Header = header
+
+	cookie, apiKey, err := t.currentCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.prepare(req, cookie, apiKey), nil
+}
+
+func (t *feedTransport) credentials() (string, string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.cookie, t.apiKey
+}
+
+// refreshLogin sends the login request and stores the cookies it sets as the cookie of the feed,
+// and the api key it returns as the api key of the feed
+func (t *feedTransport) refreshLogin(ctx context.Context) (string, string, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	cookie, apiKey, err := t.doLogin(ctx)
+	if err != nil {
+		t.log.Error().Err(err).Msg("feed login failed")
+		return "", "", &feedLoginError{err: err}
+	}
+
+	if cookie != "" {
+		t.cookie = cookie
+		t.feed.Cookie = cookie
+
+		if t.repo != nil && t.feed.ID != 0 {
+			if err := t.repo.UpdateCookie(ctx, t.feed.ID, cookie); err != nil {
+				t.log.Error().Err(err).Msg("could not store feed cookie")
+			}
+		}
+	}
+
+	if apiKey != "" {
+		t.apiKey = apiKey
+		t.feed.ApiKey = apiKey
+
+		if t.repo != nil && t.feed.ID != 0 {
+			if err := t.repo.UpdateApiKey(ctx, t.feed.ID, apiKey); err != nil {
+				t.log.Error().Err(err).Msg("could not store feed api key")
+			}
+		}
+	}
+
+	t.log.Debug().Msg("feed login successful, credentials refreshed")
+
+	return t.cookie, t.apiKey, nil
+}
+
+func (t *feedTransport) doLogin(ctx context.Context) (string, string, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return "", "", err
+	}
+
+	client := &http.Client{
+		Transport: t.base,
+		Jar:       jar,
+	}
+
+	method := strings.ToUpper(t.login.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	if t.login.Body != "" {
+		body = strings.NewReader(t.login.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.login.URL, body)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not build login request")
+	}
+
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	if body != nil {
+		contentType := t.login.ContentType
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", errors.Wrap(err, "login request failed")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", "", errors.New("login answered with status: %d", resp.StatusCode)
+	}
+
+	var apiKey string
+	if t.login.APIKeyPath != "" {
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, loginResponseMaxBytes))
+		if err != nil {
+			return "", "", errors.Wrap(err, "could not read login response")
+		}
+
+		var data any
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return "", "", errors.Wrap(err, "could not decode login response")
+		}
+
+		if apiKey = jsonValue(data, t.login.APIKeyPath); apiKey == "" {
+			return "", "", errors.New("login response has no api key at: %s", t.login.APIKeyPath)
+		}
+	}
+
+	// the cookies of the feed, or else the cookies of the login page when the feed is on another domain
+	var cookies []*http.Cookie
+	if feedURL, err := url.Parse(t.feed.URL); err == nil {
+		cookies = jar.Cookies(feedURL)
+	}
+
+	if len(cookies) == 0 {
+		cookies = jar.Cookies(req.URL)
+	}
+
+	if len(cookies) == 0 {
+		if apiKey != "" {
+			return "", apiKey, nil
+		}
+
+		return "", "", errors.New("login did not set any cookies")
+	}
+
+	parts := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+
+	return strings.Join(parts, "; "), apiKey, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCookieRepo struct {
+	domain.FeedRepo
+	cookies []string
+	apiKeys []string
+}
+
+func (r *testCookieRepo) UpdateCookie(ctx context.Context, feedID int, cookie string) error {
+	r.cookies = append(r.cookies, cookie)
+	return nil
+}
+
+func (r *testCookieRepo) UpdateApiKey(ctx context.Context, feedID int, apiKey string) error {
+	r.apiKeys = append(r.apiKeys, apiKey)
+	return nil
+}
+
+func Test_newFeedTransport_default(t *testing.T) {
+	transport, err := newFeedTransport(&domain.Feed{Settings: &domain.FeedSettingsJSON{}}, nil, zerolog.Nop())
+	assert.NoError(t, err)
+	assert.Nil(t, transport)
+
+	_, err = newFeedTransport(&domain.Feed{Settings: &domain.FeedSettingsJSON{Proxy: "ftp://proxy.local"}}, nil, zerolog.Nop())
+	assert.Error(t, err)
+}
+
+func Test_feedTransport_headers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" || r.Header.Get("Cookie") != "uid=1; pass=abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	f := &domain.Feed{URL: ts.URL, Cookie: "uid=1; pass=abc", Settings: &domain.FeedSettingsJSON{Headers: "X-Api-Key: secret"}}
+
+	transport, err := newFeedTransport(f, nil, zerolog.Nop())
+	assert.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: transport}).Get(ts.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_feedTransport_login(t *testing.T) {
+	logins := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || string(body) != "username=user&password=pass" || r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "fresh", Path: "/"})
+			w.WriteHeader(http.StatusOK)
+
+		case "/rss":
+			if c, err := r.Cookie("session"); err != nil || c.Value != "fresh" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer ts.Close()
+
+	repo := &testCookieRepo{}
+	f := &domain.Feed{
+		ID:     1,
+		URL:    ts.URL + "/rss",
+		Cookie: "session=expired",
+		Settings: &domain.FeedSettingsJSON{
+			Login: &domain.FeedLoginSettings{URL: ts.URL + "/login", Body: "username=user&password=pass"},
+		},
+	}
+
+	transport, err := newFeedTransport(f, repo, zerolog.Nop())
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: transport}
+
+	// the expired session answers 401, the transport logs in again and retries
+	resp, err := client.Get(f.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, "session=fresh", f.Cookie)
+	assert.Equal(t, []string{"session=fresh"}, repo.cookies)
+
+	// the refreshed cookie is used for the next refresh
+	resp, err = client.Get(f.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, logins)
+}
+
+func Test_feedTransport_login_apiKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"api_key":"fresh key"}}`))
+
+		case "/api":
+			if r.URL.Query().Get("apikey") != "fresh key" || r.Header.Get("X-Api-Key") != "fresh key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer ts.Close()
+
+	repo := &testCookieRepo{}
+	f := &domain.Feed{
+		ID:     1,
+		URL:    ts.URL + "/api",
+		ApiKey: "expired",
+		Settings: &domain.FeedSettingsJSON{
+			Headers: "X-Api-Key: expired",
+			Login:   &domain.FeedLoginSettings{URL: ts.URL + "/auth", APIKeyPath: "$.data.api_key"},
+		},
+	}
+
+	transport, err := newFeedTransport(f, repo, zerolog.Nop())
+	assert.NoError(t, err)
+
+	// the request is built with the api key of the job, the transport swaps in the renewed key
+	resp, err := (&http.Client{Transport: transport}).Get(f.URL + "?t=search&apikey=expired")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "fresh key", f.ApiKey)
+	assert.Equal(t, []string{"fresh key"}, repo.apiKeys)
+	assert.Empty(t, repo.cookies)
+}
+
+func Test_runTransport_auth(t *testing.T) {
+	status := http.StatusForbidden
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	var errs []error
+	client := &http.Client{Transport: newRunTransport(nil, string(domain.FeedTypeTorznab), func(err error) {
+		errs = append(errs, err)
+	})}
+
+	resp, err := client.Get(ts.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	status = http.StatusOK
+	resp, err = client.Get(ts.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "feed answered with status: 403")
+	assert.NoError(t, errs[1])
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// DryRun checks the release against the enabled filters in the order the release processor would.
+// If release.Indexer is empty all enabled filters are checked regardless of indexer.
+// External filters and additional size checks are not run since they call out to other services,
+// they are reported as notes on the result instead.
+func (s *service) DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterDryRunResult, error) {
+	filters, err := s.dryRunFilters(ctx, release.Indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.FilterDryRunResult, 0, len(filters))
+
+	for _, f := range filters {
+		result, err := s.dryRunFilter(ctx, f, release)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// dryRunFilter checks the release against a single filter without running external filters or additional size checks
+func (s *service) dryRunFilter(ctx context.Context, f domain.Filter, release *domain.Release) (domain.FilterDryRunResult, error) {
+	result := domain.FilterDryRunResult{
+		FilterID:   f.ID,
+		Name:       f.Name,
+		Priority:   f.Priority,
+		Rejections: []string{},
+		Notes:      []string{},
+	}
+
+	if f.ProperUpgrades && (release.Proper || release.Repack) {
+		upgradeOf, err := s.properUpgradeOf(ctx, &f, release)
+		if err != nil {
+			return result, errors.Wrap(err, "could not check grab history for filter: %s", f.Name)
+		}
+
+		if upgradeOf != "" {
+			result.Notes = append(result.Notes, fmt.Sprintf("upgrade of %s, max downloads and smart episode are not checked", upgradeOf))
+			f.MaxDownloads = 0
+			f.SmartEpisode = false
+		}
+	}
+
+	if f.MaxDownloads > 0 {
+		var err error
+		if f.Downloads, err = s.repo.GetDownloadsByFilterId(ctx, f.DownloadsParams(release)); err != nil {
+			return result, errors.Wrap(err, "could not get downloads for filter: %s", f.Name)
+		}
+	}
+
+	release.AdditionalSizeCheckRequired = false
+
+	rejections, ok := f.CheckFilter(release)
+	if !ok {
+		result.Rejections = append(result.Rejections, rejections...)
+		return result, nil
+	}
+
+	if f.SmartEpisode {
+		rejection, err := s.smartEpisodeRejection(ctx, release, &f)
+		if err != nil {
+			return result, errors.Wrap(err, "could not run smart episode check for filter: %s", f.Name)
+		}
+
+		if rejection != "" {
+			result.Rejections = append(result.Rejections, rejection)
+			return result, nil
+		}
+	}
+
+	if f.PreferSeasonPacks {
+		rejection, err := s.seasonPackRejection(ctx, release)
+		if err != nil {
+			return result, errors.Wrap(err, "could not run season pack check for filter: %s", f.Name)
+		}
+
+		if rejection != "" {
+			result.Rejections = append(result.Rejections, rejection)
+			return result, nil
+		}
+	}
+
+	result.Match = true
+
+	if f.FreeleechTokens && release.FreeleechPercent != 100 {
+		result.Notes = append(result.Notes, "freeleech tokens are checked against the indexer api")
+	}
+
+	if len(f.RewriteRules) > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("actions get the rewritten release name: %s", f.RewriteReleaseName(release.TorrentName)))
+	}
+
+	if f.RequiresPeers() {
+		result.Notes = append(result.Notes, fmt.Sprintf("seeders and leechers (min: %d / %d) are checked against the feed or indexer api", f.MinSeeders, f.MinLeechers))
+	}
+
+	if f.PreferSeasonPacks && f.SeasonPackHold > 0 && release.IsSingleEpisode() {
+		result.Notes = append(result.Notes, fmt.Sprintf("actions are held for %d hours and cancelled if a season pack is grabbed", f.SeasonPackHold))
+	}
+
+	if release.AdditionalSizeCheckRequired {
+		if f.MinSize != "" || f.MaxSize != "" {
+			result.Notes = append(result.Notes, fmt.Sprintf("size is unknown, the size limits (min: %q max: %q) are checked against the indexer or torrent file", f.MinSize, f.MaxSize))
+		}
+		if f.Expression != "" {
+			result.Notes = append(result.Notes, "size is unknown, the expression is checked after getting the size from the indexer or torrent file")
+		}
+	}
+
+	for _, external := range f.External {
+		if external.Enabled {
+			result.Notes = append(result.Notes, fmt.Sprintf("external filter %q (%s) is not run", external.Name, external.Type))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *service) dryRunFilters(ctx context.Context, indexer string) ([]domain.Filter, error) {
+	if indexer != "" {
+		filters, err := s.FindByIndexerIdentifier(ctx, indexer)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filters for indexer: %s", indexer)
+		}
+
+		return filters, nil
+	}
+
+	list, err := s.repo.ListFilters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filters")
+	}
+
+	filters := make([]domain.Filter, 0, len(list))
+	for _, f := range list {
+		if !f.Enabled {
+			continue
+		}
+
+		full, err := s.repo.FindByID(ctx, f.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %d", f.ID)
+		}
+
+		filters = append(filters, *full)
+	}
+
+	if err := s.applyGroups(ctx, filters); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(filters, func(i, j int) bool {
+		return filters[i].Priority > filters[j].Priority
+	})
+
+	return filters, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// flagStore keeps the flags set by irc triggers, like a sitewide freeleech. Flags are not persisted,
+// after a restart they are set again by the next matching line.
+type flagStore struct {
+	mu    sync.Mutex
+	flags map[string]time.Time
+}
+
+func newFlagStore() *flagStore {
+	return &flagStore{flags: make(map[string]time.Time)}
+}
+
+// set activates the flag until the time, a zero time keeps it until it is cleared
+func (s *flagStore) set(name string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags[strings.ToLower(name)] = until
+}
+
+func (s *flagStore) clear(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.flags, strings.ToLower(name))
+}
+
+// active returns the flags that have not expired
+func (s *flagStore) active(now time.Time) []domain.FilterFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := make([]domain.FilterFlag, 0, len(s.flags))
+	for name, until := range s.flags {
+		if !until.IsZero() && now.After(until) {
+			delete(s.flags, name)
+			continue
+		}
+
+		flags = append(flags, domain.FilterFlag{Name: name, Until: until})
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	return flags
+}
+
+func (s *flagStore) activeNames(now time.Time) []string {
+	flags := s.active(now)
+
+	names := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		names = append(names, flag.Name)
+	}
+
+	return names
+}
+
+// SetFlag activates a flag for filters for the duration, 0 keeps it until it is cleared
+func (s *service) SetFlag(name string, duration time.Duration) {
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+
+	s.flags.set(name, until)
+
+	s.log.Info().Msgf("flag %s set", name)
+}
+
+// ClearFlag deactivates a flag for filters
+func (s *service) ClearFlag(name string) {
+	s.flags.clear(name)
+
+	s.log.Info().Msgf("flag %s cleared", name)
+}
+
+// ListFlags returns the active flags
+func (s *service) ListFlags() []domain.FilterFlag {
+	return s.flags.active(time.Now())
+}
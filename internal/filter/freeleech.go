@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// freeleechTokenTTL is how long the token count of an indexer is reused before asking the api again
+const freeleechTokenTTL = 5 * time.Minute
+
+type freeleechTokenEntry struct {
+	tokens    int
+	fetchedAt time.Time
+}
+
+// freeleechTokenCache keeps the freeleech token counts per indexer to not query the api for every announce
+type freeleechTokenCache struct {
+	mu      sync.Mutex
+	entries map[string]freeleechTokenEntry
+}
+
+func newFreeleechTokenCache() *freeleechTokenCache {
+	return &freeleechTokenCache{entries: make(map[string]freeleechTokenEntry)}
+}
+
+func (c *freeleechTokenCache) get(indexer string, now time.Time) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[indexer]
+	if !ok || now.Sub(entry.fetchedAt) > freeleechTokenTTL {
+		return 0, false
+	}
+
+	return entry.tokens, true
+}
+
+func (c *freeleechTokenCache) set(indexer string, tokens int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[indexer] = freeleechTokenEntry{tokens: tokens, fetchedAt: now}
+}
+
+// freeleechTokensRejection returns why the release is rejected when the filter requires freeleech tokens, or an empty string.
+// Releases that are already fully freeleech do not need a token.
+func (s *service) freeleechTokensRejection(ctx context.Context, release *domain.Release) (string, error) {
+	if release.FreeleechPercent == 100 {
+		return "", nil
+	}
+
+	now := time.Now()
+
+	tokens, ok := s.freeleechTokens.get(release.Indexer, now)
+	if !ok {
+		var err error
+		tokens, err = s.apiService.GetFreeleechTokens(ctx, release.Indexer)
+		if err != nil {
+			if errors.Is(err, indexer.ErrFreeleechTokensNotSupported) {
+				return fmt.Sprintf("freeleech tokens not supported by indexer api: %s", release.Indexer), nil
+			}
+			return "", err
+		}
+
+		s.freeleechTokens.set(release.Indexer, tokens, now)
+	}
+
+	if tokens < 1 {
+		return fmt.Sprintf("freeleech tokens not available. got: %d", tokens), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_freeleechTokenCache(t *testing.T) {
+	c := newFreeleechTokenCache()
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok := c.get("redacted", now)
+	assert.False(t, ok)
+
+	c.set("redacted", 3, now)
+
+	tokens, ok := c.get("redacted", now.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 3, tokens)
+
+	_, ok = c.get("ops", now)
+	assert.False(t, ok)
+
+	_, ok = c.get("redacted", now.Add(freeleechTokenTTL+time.Second))
+	assert.False(t, ok)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+func (s *service) ListGroups(ctx context.Context) ([]domain.FilterGroup, error) {
+	return s.groupRepo.List(ctx)
+}
+
+func (s *service) FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error) {
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.Indexers, err = s.groupRepo.FindIndexers(ctx, groupID); err != nil {
+		s.log.Error().Err(err).Msgf("could not find indexers for filter group: %v", group.Name)
+		return nil, err
+	}
+
+	if group.Actions, err = s.actionRepo.FindByFilterGroupID(ctx, groupID); err != nil {
+		s.log.Error().Err(err).Msgf("could not find actions for filter group: %v", group.Name)
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *service) StoreGroup(ctx context.Context, group *domain.FilterGroup) error {
+	if err := validateGroup(group); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Store(ctx, group); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter group: %v", group.Name)
+		return err
+	}
+
+	return s.storeGroupRelations(ctx, group)
+}
+
+func (s *service) UpdateGroup(ctx context.Context, group *domain.FilterGroup) error {
+	if err := validateGroup(group); err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		s.log.Error().Err(err).Msgf("could not update filter group: %v", group.Name)
+		return err
+	}
+
+	return s.storeGroupRelations(ctx, group)
+}
+
+func (s *service) storeGroupRelations(ctx context.Context, group *domain.FilterGroup) error {
+	if err := s.groupRepo.StoreIndexerConnections(ctx, group.ID, group.Indexers); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter group indexer connections: %v", group.Name)
+		return err
+	}
+
+	actions, err := s.actionRepo.StoreFilterGroupActions(ctx, int64(group.ID), group.Actions)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter group actions: %v", group.Name)
+		return err
+	}
+
+	group.Actions = actions
+
+	return nil
+}
+
+func (s *service) DeleteGroup(ctx context.Context, groupID int) error {
+	if err := s.groupRepo.Delete(ctx, groupID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter group: %v", groupID)
+		return err
+	}
+
+	return nil
+}
+
+func validateGroup(group *domain.FilterGroup) error {
+	if group.Name == "" {
+		return errors.New("validation: name can't be empty")
+	}
+
+	for _, size := range []string{group.MinSize, group.MaxSize} {
+		if size == "" {
+			continue
+		}
+
+		if _, err := humanize.ParseBytes(size); err != nil {
+			return errors.Wrap(err, "validation: invalid size: %s", size)
+		}
+	}
+
+	return nil
+}
+
+// validateGroupID checks that the group a filter is assigned to exists, 0 means no group
+func (s *service) validateGroupID(ctx context.Context, groupID int) error {
+	if groupID == 0 {
+		return nil
+	}
+
+	if _, err := s.groupRepo.FindByID(ctx, groupID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return errors.New("validation: filter group not found: %d", groupID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// applyGroups sets the inherited settings on filters that belong to a group
+func (s *service) applyGroups(ctx context.Context, filters []domain.Filter) error {
+	groups := make(map[int]*domain.FilterGroup)
+
+	for i := range filters {
+		groupID := filters[i].GroupID
+		if groupID == 0 {
+			continue
+		}
+
+		group, ok := groups[groupID]
+		if !ok {
+			var err error
+			group, err = s.groupRepo.FindByID(ctx, groupID)
+			if err != nil {
+				if errors.Is(err, domain.ErrRecordNotFound) {
+					s.log.Warn().Msgf("filter %s references missing filter group: %d", filters[i].Name, groupID)
+					continue
+				}
+				return errors.Wrap(err, "could not find filter group: %d", groupID)
+			}
+
+			groups[groupID] = group
+		}
+
+		group.ApplyTo(&filters[i])
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	listSourceJobIdentifier = "filter-list-sources"
+	listSourceMaxBodySize   = 5 << 20
+)
+
+var listSourceClient = &http.Client{Timeout: 30 * time.Second}
+
+// ListSourceJob refreshes the list sources that are due
+type ListSourceJob struct {
+	log zerolog.Logger
+	svc Service
+}
+
+func (j *ListSourceJob) Run() {
+	if err := j.svc.RefreshListSources(context.Background(), 0); err != nil {
+		j.log.Error().Err(err).Msg("error when refreshing filter list sources")
+	}
+}
+
+func (s *service) Start() error {
+	job := &ListSourceJob{
+		log: s.log.With().Str("job", listSourceJobIdentifier).Logger(),
+		svc: s,
+	}
+
+	// check every minute, each source has its own refresh interval
+	if _, err := s.scheduler.ScheduleJob(job, time.Minute, listSourceJobIdentifier); err != nil {
+		return errors.Wrap(err, "add job %s failed", listSourceJobIdentifier)
+	}
+
+	presetJob := &PresetSyncJob{
+		log: s.log.With().Str("job", presetSyncJobIdentifier).Logger(),
+		svc: s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(presetJob, presetSyncInterval, presetSyncJobIdentifier); err != nil {
+		return errors.Wrap(err, "add job %s failed", presetSyncJobIdentifier)
+	}
+
+	statsJob := &StatsJob{
+		log: s.log.With().Str("job", statsJobIdentifier).Logger(),
+		svc: s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(statsJob, time.Minute, statsJobIdentifier); err != nil {
+		return errors.Wrap(err, "add job %s failed", statsJobIdentifier)
+	}
+
+	return nil
+}
+
+// Stop writes the filter stats that are not stored yet
+func (s *service) Stop() {
+	s.flushStats(context.Background())
+}
+
+type listSourceKey struct {
+	filterID int
+	field    string
+}
+
+// RefreshListSources fetches the list sources and updates the filter fields.
+// With a filterID all sources of that filter are refreshed, otherwise the sources of all filters that are due.
+// Sources for the same field of a filter are merged, and the field is only updated when all of them could be fetched.
+func (s *service) RefreshListSources(ctx context.Context, filterID int) error {
+	sources, err := s.repo.FindListSources(ctx, filterID)
+	if err != nil {
+		return errors.Wrap(err, "could not find list sources")
+	}
+
+	now := time.Now()
+
+	fields := make(map[listSourceKey][]domain.FilterListSource)
+	var order []listSourceKey
+
+	for _, source := range sources {
+		key := listSourceKey{filterID: source.FilterID, field: source.Field}
+		if _, ok := fields[key]; !ok {
+			order = append(order, key)
+		}
+
+		fields[key] = append(fields[key], source)
+	}
+
+	for _, key := range order {
+		group := fields[key]
+
+		due := filterID > 0
+		for _, source := range group {
+			if source.Due(now) {
+				due = true
+			}
+		}
+
+		if !due {
+			continue
+		}
+
+		s.refreshListField(ctx, key, group, now)
+	}
+
+	return nil
+}
+
+func (s *service) refreshListField(ctx context.Context, key listSourceKey, sources []domain.FilterListSource, now time.Time) {
+	seen := make(map[string]struct{})
+	items := make([]string, 0)
+	failed := false
+
+	for _, source := range sources {
+		list, err := s.fetchListSource(ctx, source)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("could not refresh list source for filter %d field %s: %s", key.filterID, key.field, source.URL)
+			failed = true
+
+			if err := s.repo.UpdateListSourceRefresh(ctx, source.ID, now, err.Error()); err != nil {
+				s.log.Error().Err(err).Msgf("could not update list source: %d", source.ID)
+			}
+			continue
+		}
+
+		for _, item := range list {
+			if _, ok := seen[item]; ok {
+				continue
+			}
+
+			seen[item] = struct{}{}
+			items = append(items, item)
+		}
+	}
+
+	// keep the current value rather than applying a partial list
+	if failed {
+		return
+	}
+
+	update := domain.FilterUpdate{ID: key.filterID}
+	sources[0].SetField(&update, items)
+
+	s.ensureBaselineRevisionByID(ctx, key.filterID)
+
+	if err := s.repo.UpdatePartial(ctx, update); err != nil {
+		s.log.Error().Err(err).Msgf("could not update filter %d field %s from list sources", key.filterID, key.field)
+		return
+	}
+
+	s.recordRevision(ctx, key.filterID, "list source refresh")
+
+	for _, source := range sources {
+		if err := s.repo.UpdateListSourceRefresh(ctx, source.ID, now, ""); err != nil {
+			s.log.Error().Err(err).Msgf("could not update list source: %d", source.ID)
+		}
+	}
+
+	s.log.Debug().Msgf("updated filter %d field %s with %d items from list sources", key.filterID, key.field, len(items))
+}
+
+func (s *service) fetchListSource(ctx context.Context, source domain.FilterListSource) ([]string, error) {
+	body, err := fetchRemote(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.ParseList(body)
+}
+
+// fetchRemote downloads a list or preset of at most listSourceMaxBodySize bytes
+func fetchRemote(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	res, err := listSourceClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch url")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, listSourceMaxBodySize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read body")
+	}
+
+	if len(body) > listSourceMaxBodySize {
+		return nil, errors.New("body is larger than %d bytes", listSourceMaxBodySize)
+	}
+
+	return body, nil
+}
+
+// validateListSources sets the default format and refresh interval and validates the sources
+func validateListSources(sources []domain.FilterListSource) error {
+	for i := range sources {
+		if sources[i].Format == "" {
+			sources[i].Format = domain.FilterListFormatText
+		}
+
+		if sources[i].RefreshInterval == 0 {
+			sources[i].RefreshInterval = 60
+		}
+
+		if err := sources[i].Validate(); err != nil {
+			return errors.Wrap(err, "validation: invalid list source")
+		}
+	}
+
+	return nil
+}
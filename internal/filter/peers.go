@@ -0,0 +1,38 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// checkPeers checks the seeders and leechers of the release against the filter minimums.
+// Feeds like torznab report the peers of their items, for everything else they are looked up via the indexer api.
+func (s *service) checkPeers(ctx context.Context, f domain.Filter, release *domain.Release) (bool, error) {
+	if !release.PeersKnown {
+		if release.TorrentID == "" {
+			release.AddRejectionF("seeders and leechers unknown: missing torrent id for indexer api: %s", release.Indexer)
+			return false, nil
+		}
+
+		seeders, leechers, err := s.apiService.GetTorrentPeers(ctx, release.Indexer, release.TorrentID)
+		if err != nil {
+			if errors.Is(err, indexer.ErrPeersNotSupported) {
+				release.AddRejectionF("seeders and leechers not supported by indexer api: %s", release.Indexer)
+				return false, nil
+			}
+			return false, err
+		}
+
+		release.Seeders = seeders
+		release.Leechers = leechers
+		release.PeersKnown = true
+	}
+
+	return f.CheckPeers(release), nil
+}
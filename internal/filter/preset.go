@@ -0,0 +1,185 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	presetSyncJobIdentifier = "filter-preset-sync"
+	presetSyncInterval      = 6 * time.Hour
+)
+
+// PresetSyncJob updates the filters of managed presets whose source changed
+type PresetSyncJob struct {
+	log zerolog.Logger
+	svc Service
+}
+
+func (j *PresetSyncJob) Run() {
+	if _, err := j.svc.SyncPresets(context.Background()); err != nil {
+		j.log.Error().Err(err).Msg("error when syncing filter presets")
+	}
+}
+
+// ImportPreset creates a filter from an autobrr preset or a TRaSH custom format, or updates the filter
+// that was imported from the same preset before. Without data the preset is fetched from the source.
+// New filters are disabled so indexers and actions can be added first.
+func (s *service) ImportPreset(ctx context.Context, data []byte, opts domain.FilterPresetImportOptions) (*domain.FilterPresetImportResult, error) {
+	if len(data) == 0 {
+		if opts.Source == "" {
+			return nil, errors.New("preset or source is required")
+		}
+
+		var err error
+		data, err = fetchRemote(ctx, opts.Source)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch preset: %s", opts.Source)
+		}
+	}
+
+	if opts.App == "" {
+		opts.App = domain.PresetAppFromSource(opts.Source)
+	}
+
+	preset, err := domain.ParseFilterPreset(data, opts.App)
+	if err != nil {
+		return nil, errors.Wrap(err, "validation")
+	}
+
+	return s.applyPreset(ctx, preset, opts, "preset import")
+}
+
+// SyncPresets fetches the sources of managed presets and updates the filters of the presets that changed
+func (s *service) SyncPresets(ctx context.Context) ([]domain.FilterPresetImportResult, error) {
+	filters, err := s.repo.FindPresets(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find presets")
+	}
+
+	results := make([]domain.FilterPresetImportResult, 0)
+
+	for _, f := range filters {
+		if !f.PresetManaged || f.PresetSource == "" {
+			continue
+		}
+
+		data, err := fetchRemote(ctx, f.PresetSource)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("could not fetch preset for filter %d: %s", f.ID, f.PresetSource)
+			continue
+		}
+
+		preset, err := domain.ParseFilterPreset(data, domain.PresetAppFromSource(f.PresetSource))
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("could not parse preset for filter %d: %s", f.ID, f.PresetSource)
+			continue
+		}
+
+		if preset.ID != f.PresetID {
+			s.log.Warn().Msgf("preset for filter %d changed id from %s to %s, skipping", f.ID, f.PresetID, preset.ID)
+			continue
+		}
+
+		if preset.Hash == f.PresetHash {
+			continue
+		}
+
+		result, err := s.applyPreset(ctx, preset, domain.FilterPresetImportOptions{Source: f.PresetSource, Managed: true}, "preset sync")
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not sync preset for filter %d", f.ID)
+			continue
+		}
+
+		s.log.Info().Msgf("synced preset %s for filter %d: %s", preset.ID, f.ID, f.Name)
+
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+func (s *service) applyPreset(ctx context.Context, preset *domain.FilterPreset, opts domain.FilterPresetImportOptions, note string) (*domain.FilterPresetImportResult, error) {
+	existing, err := s.findPreset(ctx, preset.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Source == "" && existing != nil {
+		opts.Source = existing.PresetSource
+	}
+
+	if opts.Managed && opts.Source == "" {
+		return nil, errors.New("validation: a managed preset needs a source")
+	}
+
+	result := &domain.FilterPresetImportResult{
+		Name:     preset.Name,
+		PresetID: preset.ID,
+		Warnings: preset.Warnings,
+	}
+
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+
+	if existing == nil {
+		filter := &domain.Filter{
+			Name:     preset.Name,
+			PresetID: preset.ID,
+		}
+
+		if err := s.Store(ctx, filter); err != nil {
+			return nil, errors.Wrap(err, "could not store filter for preset: %s", preset.ID)
+		}
+
+		result.FilterID = filter.ID
+		result.Created = true
+		result.Changed = true
+	} else {
+		result.FilterID = existing.ID
+		result.Name = existing.Name
+		result.Changed = existing.PresetHash != preset.Hash
+
+		s.ensureBaselineRevisionByID(ctx, existing.ID)
+	}
+
+	update := preset.Filter
+	update.ID = result.FilterID
+	update.PresetID = &preset.ID
+	update.PresetSource = &opts.Source
+	update.PresetManaged = &opts.Managed
+	update.PresetHash = &preset.Hash
+
+	if err := s.updatePartial(ctx, update); err != nil {
+		return nil, errors.Wrap(err, "could not apply preset: %s", preset.ID)
+	}
+
+	s.recordRevision(ctx, result.FilterID, note)
+
+	return result, nil
+}
+
+// findPreset returns the filter imported from the preset or nil
+func (s *service) findPreset(ctx context.Context, presetID string) (*domain.Filter, error) {
+	filters, err := s.repo.FindPresets(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find presets")
+	}
+
+	for i := range filters {
+		if filters[i].PresetID == presetID {
+			return &filters[i], nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// maxFilterRevisions is the number of revisions kept per filter
+const maxFilterRevisions = 100
+
+func (s *service) ListRevisions(ctx context.Context, filterID int) ([]domain.FilterRevision, error) {
+	return s.revisionRepo.List(ctx, filterID)
+}
+
+func (s *service) FindRevision(ctx context.Context, filterID int, revision int) (*domain.FilterRevision, error) {
+	return s.revisionRepo.FindByRevision(ctx, filterID, revision)
+}
+
+// Rollback restores a filter with its indexers, actions and external filters to a previous revision.
+// The rollback is recorded as a new revision.
+func (s *service) Rollback(ctx context.Context, filterID int, revision int) (*domain.Filter, error) {
+	rev, err := s.revisionRepo.FindByRevision(ctx, filterID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.FindByID(ctx, filterID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := *rev.Snapshot
+	filter.ID = filterID
+
+	// actions deleted since the revision are created again
+	existing := make(map[int]struct{}, len(current.Actions))
+	for _, action := range current.Actions {
+		existing[action.ID] = struct{}{}
+	}
+
+	for _, action := range filter.Actions {
+		if _, ok := existing[action.ID]; !ok {
+			action.ID = 0
+		}
+		action.FilterID = filterID
+	}
+
+	// the group may have been deleted since
+	if filter.GroupID > 0 {
+		if _, err := s.groupRepo.FindByID(ctx, filter.GroupID); errors.Is(err, domain.ErrRecordNotFound) {
+			filter.GroupID = 0
+		}
+	}
+
+	s.ensureBaselineRevision(ctx, current)
+
+	if err := s.update(ctx, &filter); err != nil {
+		return nil, err
+	}
+
+	s.recordRevision(ctx, filterID, fmt.Sprintf("rollback to revision %d", revision))
+
+	return s.FindByID(ctx, filterID)
+}
+
+// ensureBaselineRevision records the current state of a filter that has no revisions yet,
+// so the first change made after upgrading still has something to diff and roll back to.
+func (s *service) ensureBaselineRevision(ctx context.Context, filter *domain.Filter) {
+	if _, err := s.revisionRepo.FindLatest(ctx, filter.ID); !errors.Is(err, domain.ErrRecordNotFound) {
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not find latest revision for filter: %d", filter.ID)
+		}
+		return
+	}
+
+	rev := &domain.FilterRevision{
+		FilterID: filter.ID,
+		Revision: 1,
+		Note:     "initial revision",
+		Changes:  []domain.FilterFieldChange{},
+		Snapshot: filter,
+	}
+
+	if err := s.revisionRepo.Store(ctx, rev); err != nil {
+		s.log.Error().Err(err).Msgf("could not store initial revision for filter: %d", filter.ID)
+	}
+}
+
+// ensureBaselineRevisionByID is ensureBaselineRevision for callers that only have the filter id
+func (s *service) ensureBaselineRevisionByID(ctx context.Context, filterID int) {
+	filter, err := s.FindByID(ctx, filterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filter for revision: %d", filterID)
+		return
+	}
+
+	s.ensureBaselineRevision(ctx, filter)
+}
+
+// recordRevision stores the current state of a filter as a new revision if it changed since the latest one.
+// Failing to record a revision is logged but does not fail the change itself.
+func (s *service) recordRevision(ctx context.Context, filterID int, note string) {
+	filter, err := s.FindByID(ctx, filterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filter for revision: %d", filterID)
+		return
+	}
+
+	rev := &domain.FilterRevision{
+		FilterID: filterID,
+		Revision: 1,
+		Username: domain.UserFromContext(ctx),
+		Note:     note,
+		Changes:  []domain.FilterFieldChange{},
+		Snapshot: filter,
+	}
+
+	latest, err := s.revisionRepo.FindLatest(ctx, filterID)
+	if err != nil && !errors.Is(err, domain.ErrRecordNotFound) {
+		s.log.Error().Err(err).Msgf("could not find latest revision for filter: %d", filterID)
+		return
+	}
+
+	if latest != nil {
+		if rev.Changes, err = domain.DiffFilters(latest.Snapshot, filter); err != nil {
+			s.log.Error().Err(err).Msgf("could not diff revision for filter: %d", filterID)
+			return
+		}
+
+		if len(rev.Changes) == 0 {
+			return
+		}
+
+		rev.Revision = latest.Revision + 1
+	}
+
+	if err := s.revisionRepo.Store(ctx, rev); err != nil {
+		s.log.Error().Err(err).Msgf("could not store revision for filter: %d", filterID)
+		return
+	}
+
+	if err := s.revisionRepo.DeleteOld(ctx, filterID, maxFilterRevisions); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete old revisions for filter: %d", filterID)
+	}
+}
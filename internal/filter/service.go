@@ -6,10 +6,8 @@ package filter
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"sort"
@@ -19,6 +17,7 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/dustin/go-humanize"
@@ -27,6 +26,8 @@ import (
 )
 
 type Service interface {
+	Start() error
+	Stop()
 	FindByID(ctx context.Context, filterID int) (*domain.Filter, error)
 	FindByIndexerIdentifier(ctx context.Context, indexer string) ([]domain.Filter, error)
 	Find(ctx context.Context, params domain.FilterQueryParams) ([]domain.Filter, error)
@@ -37,29 +38,75 @@ type Service interface {
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	ListFilterTags(ctx context.Context) ([]string, error)
+	ToggleEnabledByTag(ctx context.Context, tag string, enabled bool) (*domain.FilterBulkResult, error)
+	DeleteByTag(ctx context.Context, tag string) (*domain.FilterBulkResult, error)
+	ExportByTag(ctx context.Context, tag string) ([]domain.FilterExport, error)
 	Delete(ctx context.Context, filterID int) error
 	AdditionalSizeCheck(ctx context.Context, f domain.Filter, release *domain.Release) (bool, error)
 	CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error)
-	GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error)
+	GetDownloadsByFilterId(ctx context.Context, params domain.FilterDownloadsParams) (*domain.FilterDownloads, error)
+	DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterDryRunResult, error)
+	Simulate(ctx context.Context, filter *domain.Filter, limit int) (*domain.FilterSimulation, error)
+	ListGroups(ctx context.Context) ([]domain.FilterGroup, error)
+	FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error)
+	StoreGroup(ctx context.Context, group *domain.FilterGroup) error
+	UpdateGroup(ctx context.Context, group *domain.FilterGroup) error
+	DeleteGroup(ctx context.Context, groupID int) error
+	RefreshListSources(ctx context.Context, filterID int) error
+	ImportPreset(ctx context.Context, data []byte, opts domain.FilterPresetImportOptions) (*domain.FilterPresetImportResult, error)
+	SyncPresets(ctx context.Context) ([]domain.FilterPresetImportResult, error)
+	ListRevisions(ctx context.Context, filterID int) ([]domain.FilterRevision, error)
+	FindRevision(ctx context.Context, filterID int, revision int) (*domain.FilterRevision, error)
+	Rollback(ctx context.Context, filterID int, revision int) (*domain.Filter, error)
+	GetStats(ctx context.Context, filterID int, bucketSize domain.FilterStatsBucketSize, days int) (*domain.FilterStats, error)
+	ListTemplates(ctx context.Context) ([]domain.FilterTemplate, error)
+	FindTemplateByID(ctx context.Context, templateID int) (*domain.FilterTemplate, error)
+	StoreTemplate(ctx context.Context, template *domain.FilterTemplate) error
+	UpdateTemplate(ctx context.Context, template *domain.FilterTemplate) error
+	DeleteTemplate(ctx context.Context, templateID int) error
+	CreateFromTemplate(ctx context.Context, templateID int, instance domain.FilterTemplateInstance) (*domain.Filter, error)
+	SetFlag(name string, duration time.Duration)
+	ClearFlag(name string)
+	ListFlags() []domain.FilterFlag
 }
 
 type service struct {
-	log         zerolog.Logger
-	repo        domain.FilterRepo
-	actionRepo  domain.ActionRepo
-	releaseRepo domain.ReleaseRepo
-	indexerSvc  indexer.Service
-	apiService  indexer.APIService
+	log          zerolog.Logger
+	repo         domain.FilterRepo
+	groupRepo    domain.FilterGroupRepo
+	revisionRepo domain.FilterRevisionRepo
+	statsRepo    domain.FilterStatsRepo
+	templateRepo domain.FilterTemplateRepo
+	actionRepo   domain.ActionRepo
+	releaseRepo  domain.ReleaseRepo
+	indexerSvc   indexer.Service
+	scheduler    scheduler.Service
+	apiService   indexer.APIService
+
+	stats           *statsCollector
+	freeleechTokens *freeleechTokenCache
+	webhookCache    *webhookResponseCache
+	flags           *flagStore
 }
 
-func NewService(log logger.Logger, repo domain.FilterRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service) Service {
+func NewService(log logger.Logger, repo domain.FilterRepo, groupRepo domain.FilterGroupRepo, revisionRepo domain.FilterRevisionRepo, statsRepo domain.FilterStatsRepo, templateRepo domain.FilterTemplateRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service, scheduler scheduler.Service) Service {
 	return &service{
-		log:         log.With().Str("module", "filter").Logger(),
-		repo:        repo,
-		actionRepo:  actionRepo,
-		releaseRepo: releaseRepo,
-		apiService:  apiService,
-		indexerSvc:  indexerSvc,
+		log:             log.With().Str("module", "filter").Logger(),
+		repo:            repo,
+		groupRepo:       groupRepo,
+		revisionRepo:    revisionRepo,
+		statsRepo:       statsRepo,
+		templateRepo:    templateRepo,
+		actionRepo:      actionRepo,
+		releaseRepo:     releaseRepo,
+		apiService:      apiService,
+		indexerSvc:      indexerSvc,
+		scheduler:       scheduler,
+		stats:           newStatsCollector(),
+		freeleechTokens: newFreeleechTokenCache(),
+		webhookCache:    newWebhookResponseCache(),
+		flags:           newFlagStore(),
 	}
 }
 
@@ -131,6 +178,14 @@ func (s *service) FindByID(ctx context.Context, filterID int) (*domain.Filter, e
 	}
 	filter.Indexers = indexers
 
+	// find list sources and attach
+	sources, err := s.repo.FindListSources(ctx, filter.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find list sources for filter: %v", filter.Name)
+		return nil, err
+	}
+	filter.ListSources = sources
+
 	return filter, nil
 }
 
@@ -138,15 +193,116 @@ func (s *service) FindByIndexerIdentifier(ctx context.Context, indexer string) (
 	// get filters for indexer
 	// we do not load actions here since we do not need it at this stage
 	// only load those after filter has matched
-	return s.repo.FindByIndexerIdentifier(ctx, indexer)
+	filters, err := s.repo.FindByIndexerIdentifier(ctx, indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyGroups(ctx, filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
 }
 
-func (s *service) GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error) {
-	return s.GetDownloadsByFilterId(ctx, filterID)
+func (s *service) GetDownloadsByFilterId(ctx context.Context, params domain.FilterDownloadsParams) (*domain.FilterDownloads, error) {
+	return s.repo.GetDownloadsByFilterId(ctx, params)
 }
 
 func (s *service) Store(ctx context.Context, filter *domain.Filter) error {
+	if err := s.store(ctx, filter); err != nil {
+		return err
+	}
+
+	s.recordRevision(ctx, filter.ID, "created")
+
+	return nil
+}
+
+func (s *service) store(ctx context.Context, filter *domain.Filter) error {
 	// validate data
+	setRequiredLists(filter)
+	filter.FilterTags = domain.NormalizeFilterTags(filter.FilterTags)
+
+	if err := validateExpression(filter.Expression); err != nil {
+		return err
+	}
+
+	if err := validateSchedule(filter.Schedule); err != nil {
+		return err
+	}
+
+	if err := validateSeasonPackHold(filter.SeasonPackHold); err != nil {
+		return err
+	}
+
+	if err := validateActionDelay(filter.Delay, filter.DelayJitter); err != nil {
+		return err
+	}
+
+	if err := domain.ValidateFreeleechPercent(filter.FreeleechPercent); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateHDRPreference(filter.HDRPreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePreference("language", filter.LanguagePreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePreference("subtitle", filter.SubtitlePreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateRewriteRules(filter.RewriteRules); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateAudioQuality(filter.BitDepths, filter.SampleRates); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateFileCount(filter.MinFileCount, filter.MaxFileCount); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePeers(filter.MinSeeders, filter.MinLeechers); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateYears(filter.Years); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateAirDates(filter.AirDates, filter.MaxAirDateAge); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := filter.ValidateAnime(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := validateExternalFilters(filter.External); err != nil {
+		return err
+	}
+
+	if err := filter.ValidateChain(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := filter.ValidateRegexFields(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := validateListSources(filter.ListSources); err != nil {
+		return err
+	}
+
+	if err := s.validateGroupID(ctx, filter.GroupID); err != nil {
+		return err
+	}
 
 	// store
 	err := s.repo.Store(ctx, filter)
@@ -155,15 +311,117 @@ func (s *service) Store(ctx context.Context, filter *domain.Filter) error {
 		return err
 	}
 
+	if len(filter.ListSources) > 0 {
+		if err := s.repo.StoreListSources(ctx, filter.ID, filter.ListSources); err != nil {
+			s.log.Error().Err(err).Msgf("could not store list sources: %s", filter.Name)
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
+	s.ensureBaselineRevisionByID(ctx, filter.ID)
+
+	if err := s.update(ctx, filter); err != nil {
+		return err
+	}
+
+	s.recordRevision(ctx, filter.ID, "")
+
+	return nil
+}
+
+func (s *service) update(ctx context.Context, filter *domain.Filter) error {
 	// validate data
 	if filter.Name == "" {
 		return errors.New("validation: name can't be empty")
 	}
 
+	setRequiredLists(filter)
+	filter.FilterTags = domain.NormalizeFilterTags(filter.FilterTags)
+
+	if err := validateExpression(filter.Expression); err != nil {
+		return err
+	}
+
+	if err := validateSchedule(filter.Schedule); err != nil {
+		return err
+	}
+
+	if err := validateSeasonPackHold(filter.SeasonPackHold); err != nil {
+		return err
+	}
+
+	if err := validateActionDelay(filter.Delay, filter.DelayJitter); err != nil {
+		return err
+	}
+
+	if err := domain.ValidateFreeleechPercent(filter.FreeleechPercent); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateHDRPreference(filter.HDRPreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePreference("language", filter.LanguagePreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePreference("subtitle", filter.SubtitlePreference); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateRewriteRules(filter.RewriteRules); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateAudioQuality(filter.BitDepths, filter.SampleRates); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateFileCount(filter.MinFileCount, filter.MaxFileCount); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidatePeers(filter.MinSeeders, filter.MinLeechers); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateYears(filter.Years); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := domain.ValidateAirDates(filter.AirDates, filter.MaxAirDateAge); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := filter.ValidateAnime(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := validateExternalFilters(filter.External); err != nil {
+		return err
+	}
+
+	if err := filter.ValidateChain(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := filter.ValidateRegexFields(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	if err := validateListSources(filter.ListSources); err != nil {
+		return err
+	}
+
+	if err := s.validateGroupID(ctx, filter.GroupID); err != nil {
+		return err
+	}
+
 	// replace newline with comma
 	filter.Shows = strings.ReplaceAll(filter.Shows, "\n", ",")
 	filter.Shows = strings.ReplaceAll(filter.Shows, ",,", ",")
@@ -186,6 +444,12 @@ func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
 		return err
 	}
 
+	// take care of list sources
+	if err := s.repo.StoreListSources(ctx, filter.ID, filter.ListSources); err != nil {
+		s.log.Error().Err(err).Msgf("could not store list sources: %s", filter.Name)
+		return err
+	}
+
 	// take care of filter actions
 	actions, err := s.actionRepo.StoreFilterActions(ctx, int64(filter.ID), filter.Actions)
 	if err != nil {
@@ -198,7 +462,109 @@ func (s *service) Update(ctx context.Context, filter *domain.Filter) error {
 	return nil
 }
 
+func validateExpression(expression string) error {
+	if expression == "" {
+		return nil
+	}
+
+	if err := domain.ValidateFilterExpression(expression); err != nil {
+		return errors.Wrap(err, "validation: invalid expression")
+	}
+
+	return nil
+}
+
+func validateSchedule(schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+
+	if _, err := domain.ParseFilterSchedule(schedule); err != nil {
+		return errors.Wrap(err, "validation: invalid schedule")
+	}
+
+	return nil
+}
+
+func validateSeasonPackHold(hold int) error {
+	if hold < 0 || hold > domain.SeasonPackHoldMax {
+		return errors.New("validation: season pack hold must be between 0 and %d hours", domain.SeasonPackHoldMax)
+	}
+
+	return nil
+}
+
+// validatePartialChain validates the chain settings of a partial update together with the stored settings
+func (s *service) validatePartialChain(ctx context.Context, update domain.FilterUpdate) error {
+	f, err := s.repo.FindByID(ctx, update.ID)
+	if err != nil {
+		return err
+	}
+
+	if update.OnReject != nil {
+		f.OnReject = *update.OnReject
+	}
+	if update.OnRejectFilterID != nil {
+		f.OnRejectFilterID = *update.OnRejectFilterID
+	}
+	if update.OnActionFailure != nil {
+		f.OnActionFailure = *update.OnActionFailure
+	}
+	if update.OnActionFailureFilterID != nil {
+		f.OnActionFailureFilterID = *update.OnActionFailureFilterID
+	}
+
+	if err := f.ValidateChain(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	return nil
+}
+
+func validateExternalFilters(externals []domain.FilterExternal) error {
+	for _, external := range externals {
+		if err := external.Validate(); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	return nil
+}
+
+func validateActionDelay(delay, jitter int) error {
+	if delay < 0 {
+		return errors.New("validation: delay can not be negative")
+	}
+
+	if jitter < 0 {
+		return errors.New("validation: delay jitter can not be negative")
+	}
+
+	return nil
+}
+
+// setRequiredLists sets nil lists to empty lists since empty lists are omitted from json but the columns are not nullable
+func setRequiredLists(filter *domain.Filter) {
+	for _, list := range []*[]string{&filter.Resolutions, &filter.Codecs, &filter.Sources, &filter.Containers} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+}
+
 func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error {
+	s.ensureBaselineRevisionByID(ctx, filter.ID)
+
+	if err := s.updatePartial(ctx, filter); err != nil {
+		return err
+	}
+
+	s.recordRevision(ctx, filter.ID, "")
+
+	return nil
+}
+
+func (s *service) updatePartial(ctx context.Context, filter domain.FilterUpdate) error {
 	// cleanup
 	if filter.Shows != nil {
 		// replace newline with comma
@@ -208,6 +574,169 @@ func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate)
 		filter.Shows = &clean
 	}
 
+	if filter.FilterTags != nil {
+		tags := domain.NormalizeFilterTags(*filter.FilterTags)
+		filter.FilterTags = &tags
+	}
+
+	if filter.Expression != nil {
+		if err := validateExpression(*filter.Expression); err != nil {
+			return err
+		}
+	}
+
+	if filter.Schedule != nil {
+		if err := validateSchedule(*filter.Schedule); err != nil {
+			return err
+		}
+	}
+
+	if filter.SeasonPackHold != nil {
+		if err := validateSeasonPackHold(*filter.SeasonPackHold); err != nil {
+			return err
+		}
+	}
+
+	if filter.Delay != nil {
+		if err := validateActionDelay(*filter.Delay, 0); err != nil {
+			return err
+		}
+	}
+
+	if filter.DelayJitter != nil {
+		if err := validateActionDelay(0, *filter.DelayJitter); err != nil {
+			return err
+		}
+	}
+
+	if filter.FreeleechPercent != nil {
+		if err := domain.ValidateFreeleechPercent(*filter.FreeleechPercent); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.HDRPreference != nil {
+		if err := domain.ValidateHDRPreference(*filter.HDRPreference); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.LanguagePreference != nil {
+		if err := domain.ValidatePreference("language", *filter.LanguagePreference); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.SubtitlePreference != nil {
+		if err := domain.ValidatePreference("subtitle", *filter.SubtitlePreference); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.RewriteRules != nil {
+		if err := domain.ValidateRewriteRules(*filter.RewriteRules); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.BitDepths != nil {
+		if err := domain.ValidateAudioQuality(*filter.BitDepths, nil); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.SampleRates != nil {
+		if err := domain.ValidateAudioQuality(nil, *filter.SampleRates); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.Years != nil {
+		if err := domain.ValidateYears(*filter.Years); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.AirDates != nil || filter.MaxAirDateAge != nil {
+		var airDates string
+		var maxAirDateAge int
+		if filter.AirDates != nil {
+			airDates = *filter.AirDates
+		}
+		if filter.MaxAirDateAge != nil {
+			maxAirDateAge = *filter.MaxAirDateAge
+		}
+
+		if err := domain.ValidateAirDates(airDates, maxAirDateAge); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.MinFileCount != nil || filter.MaxFileCount != nil {
+		var minFileCount, maxFileCount int
+		if filter.MinFileCount != nil {
+			minFileCount = *filter.MinFileCount
+		}
+		if filter.MaxFileCount != nil {
+			maxFileCount = *filter.MaxFileCount
+		}
+
+		if err := domain.ValidateFileCount(minFileCount, maxFileCount); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.MinSeeders != nil || filter.MinLeechers != nil {
+		var minSeeders, minLeechers int
+		if filter.MinSeeders != nil {
+			minSeeders = *filter.MinSeeders
+		}
+		if filter.MinLeechers != nil {
+			minLeechers = *filter.MinLeechers
+		}
+
+		if err := domain.ValidatePeers(minSeeders, minLeechers); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if filter.AnimeBatch != nil || filter.AnimeSubType != nil || filter.AnimeMaxGroupTier != nil {
+		f := domain.Filter{}
+		if filter.AnimeBatch != nil {
+			f.AnimeBatch = *filter.AnimeBatch
+		}
+		if filter.AnimeSubType != nil {
+			f.AnimeSubType = *filter.AnimeSubType
+		}
+		if filter.AnimeMaxGroupTier != nil {
+			f.AnimeMaxGroupTier = *filter.AnimeMaxGroupTier
+		}
+
+		if err := f.ValidateAnime(); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	if err := validateExternalFilters(filter.External); err != nil {
+		return err
+	}
+
+	if filter.OnReject != nil || filter.OnRejectFilterID != nil || filter.OnActionFailure != nil || filter.OnActionFailureFilterID != nil {
+		if err := s.validatePartialChain(ctx, filter); err != nil {
+			return err
+		}
+	}
+
+	if err := validateListSources(filter.ListSources); err != nil {
+		return err
+	}
+
+	if filter.GroupID != nil {
+		if err := s.validateGroupID(ctx, *filter.GroupID); err != nil {
+			return err
+		}
+	}
+
 	// update
 	if err := s.repo.UpdatePartial(ctx, filter); err != nil {
 		s.log.Error().Err(err).Msgf("could not update partial filter: %v", filter.ID)
@@ -230,6 +759,14 @@ func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate)
 		}
 	}
 
+	if filter.ListSources != nil {
+		// take care of list sources
+		if err := s.repo.StoreListSources(ctx, filter.ID, filter.ListSources); err != nil {
+			s.log.Error().Err(err).Msgf("could not store list sources: %v", filter.Name)
+			return err
+		}
+	}
+
 	if filter.Actions != nil {
 		// take care of filter actions
 		if _, err := s.actionRepo.StoreFilterActions(ctx, int64(filter.ID), filter.Actions); err != nil {
@@ -285,10 +822,20 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 		return nil, err
 	}
 
+	// take care of list sources
+	if err := s.repo.StoreListSources(ctx, filter.ID, filter.ListSources); err != nil {
+		s.log.Error().Err(err).Msgf("could not store list sources: %s", filter.Name)
+		return nil, err
+	}
+
+	s.recordRevision(ctx, filter.ID, fmt.Sprintf("duplicated from filter %d", filterID))
+
 	return filter, nil
 }
 
 func (s *service) ToggleEnabled(ctx context.Context, filterID int, enabled bool) error {
+	s.ensureBaselineRevisionByID(ctx, filterID)
+
 	if err := s.repo.ToggleEnabled(ctx, filterID, enabled); err != nil {
 		s.log.Error().Err(err).Msg("could not update filter enabled")
 		return err
@@ -296,6 +843,8 @@ func (s *service) ToggleEnabled(ctx context.Context, filterID int, enabled bool)
 
 	s.log.Debug().Msgf("filter.toggle_enabled: update filter '%v' to '%v'", filterID, enabled)
 
+	s.recordRevision(ctx, filterID, "")
+
 	return nil
 }
 
@@ -322,6 +871,24 @@ func (s *service) Delete(ctx context.Context, filterID int) error {
 		return err
 	}
 
+	// delete list sources
+	if err := s.repo.DeleteListSources(ctx, filterID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter list sources: %v", filterID)
+		return err
+	}
+
+	// delete revisions
+	if err := s.revisionRepo.DeleteByFilterID(ctx, filterID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter revisions: %v", filterID)
+		return err
+	}
+
+	// delete stats
+	if err := s.statsRepo.DeleteByFilterID(ctx, filterID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter stats: %v", filterID)
+		return err
+	}
+
 	// delete filter
 	if err := s.repo.Delete(ctx, filterID); err != nil {
 		s.log.Error().Err(err).Msgf("could not delete filter: %v", filterID)
@@ -331,14 +898,51 @@ func (s *service) Delete(ctx context.Context, filterID int) error {
 	return nil
 }
 
+// CheckFilter checks the release against the filter and counts the result in the filter stats
 func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *domain.Release) (bool, error) {
+	match, err := s.checkFilter(ctx, f, release)
+	if err != nil {
+		return false, err
+	}
+
+	rejections := release.Rejections
+	if match {
+		rejections = nil
+	}
+
+	s.stats.record(f.ID, time.Now(), match, rejections)
+
+	return match, nil
+}
+
+func (s *service) checkFilter(ctx context.Context, f domain.Filter, release *domain.Release) (bool, error) {
 
 	s.log.Trace().Msgf("filter.Service.CheckFilter: checking filter: %s %+v", f.Name, f)
 	s.log.Trace().Msgf("filter.Service.CheckFilter: checking filter: %s for release: %+v", f.Name, release)
 
+	release.UpgradeOf = ""
+	release.Flags = s.flags.activeNames(time.Now())
+
+	// a PROPER or REPACK of a release grabbed by this filter is an upgrade and not limited by max downloads or smart episode
+	if f.ProperUpgrades && (release.Proper || release.Repack) {
+		upgradeOf, err := s.properUpgradeOf(ctx, &f, release)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%s) error checking grab history for proper upgrade", f.Name)
+			return false, nil
+		}
+
+		if upgradeOf != "" {
+			s.log.Debug().Msgf("filter.Service.CheckFilter: (%s) release %s is an upgrade of %s", f.Name, release.TorrentName, upgradeOf)
+
+			release.UpgradeOf = upgradeOf
+			f.MaxDownloads = 0
+			f.SmartEpisode = false
+		}
+	}
+
 	// do additional fetch to get download counts for filter
 	if f.MaxDownloads > 0 {
-		downloadCounts, err := s.repo.GetDownloadsByFilterId(ctx, f.ID)
+		downloadCounts, err := s.repo.GetDownloadsByFilterId(ctx, f.DownloadsParams(release))
 		if err != nil {
 			s.log.Error().Err(err).Msg("filter.Service.CheckFilter: error getting download counters for filter")
 			return false, nil
@@ -355,15 +959,57 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 	if matchedFilter {
 		// smartEpisode check
 		if f.SmartEpisode {
-			canDownloadShow, err := s.CanDownloadShow(ctx, release)
+			rejection, err := s.smartEpisodeRejection(ctx, release, &f)
 			if err != nil {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: failed smart episode check: %s", f.Name)
 				return false, nil
 			}
 
-			if !canDownloadShow {
+			if rejection != "" {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: failed smart episode check: %s", f.Name)
-				release.AddRejectionF("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
+				release.AddRejectionF("%s", rejection)
+				return false, nil
+			}
+		}
+
+		if f.FreeleechTokens {
+			rejection, err := s.freeleechTokensRejection(ctx, release)
+			if err != nil {
+				s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%s) freeleech tokens check error", f.Name)
+				return false, nil
+			}
+
+			if rejection != "" {
+				s.log.Trace().Msgf("filter.Service.CheckFilter: failed freeleech tokens check: %s", f.Name)
+				release.AddRejectionF("%s", rejection)
+				return false, nil
+			}
+		}
+
+		if f.PreferSeasonPacks {
+			rejection, err := s.seasonPackRejection(ctx, release)
+			if err != nil {
+				s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%s) season pack check error", f.Name)
+				return false, nil
+			}
+
+			if rejection != "" {
+				s.log.Trace().Msgf("filter.Service.CheckFilter: failed season pack check: %s", f.Name)
+				release.AddRejectionF("%s", rejection)
+				return false, nil
+			}
+		}
+
+		// check the current seeders and leechers, only established torrents are wanted
+		if f.RequiresPeers() {
+			ok, err := s.checkPeers(ctx, f, release)
+			if err != nil {
+				s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%s) seeders and leechers check error", f.Name)
+				return false, nil
+			}
+
+			if !ok {
+				s.log.Trace().Msgf("filter.Service.CheckFilter: failed seeders and leechers check: %s", f.Name)
 				return false, nil
 			}
 		}
@@ -392,6 +1038,12 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 			}
 		}
 
+		// verify the downloaded torrent before any actions run, announced sizes are often missing or wrong
+		if f.VerifyTorrent && !s.verifyTorrent(ctx, f, release) {
+			s.log.Debug().Msgf("filter.Service.CheckFilter: (%s) torrent verification failed for release: %s rejections: (%s)", f.Name, release.TorrentName, release.RejectionsString(true))
+			return false, nil
+		}
+
 		// run external filters
 		if f.External != nil {
 			externalOk, err := s.RunExternalFilters(ctx, f.External, release)
@@ -465,9 +1117,40 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f domain.Filter, rele
 		return false, nil
 	}
 
+	// expressions using Size are skipped until the size is known
+	if f.Expression != "" {
+		match, err := f.CheckExpression(release)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("filter.Service.AdditionalSizeCheck: (%s) error checking expression", f.Name)
+			return false, err
+		}
+
+		if !match {
+			s.log.Debug().Msgf("filter.Service.AdditionalSizeCheck: (%s) expression did not match after additional size check, trying next", f.Name)
+			return false, nil
+		}
+	}
+
 	return true, nil
 }
 
+// verifyTorrent downloads the torrent file of the release and checks its size, file count and file extensions
+// against the filter. Releases that are not torrents can not be verified and are passed.
+func (s *service) verifyTorrent(ctx context.Context, f domain.Filter, release *domain.Release) bool {
+	if release.Protocol != domain.ReleaseProtocolTorrent {
+		s.log.Debug().Msgf("filter.Service.verifyTorrent: (%s) skip verification of %s release: %s", f.Name, release.Protocol, release.TorrentName)
+		return true
+	}
+
+	if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		s.log.Error().Err(err).Msgf("filter.Service.verifyTorrent: (%s) could not download torrent file with id: '%s' from: %s", f.Name, release.TorrentID, release.Indexer)
+		release.AddRejectionF("torrent verification: could not download torrent file: %v", err)
+		return false
+	}
+
+	return f.CheckTorrentFiles(release)
+}
+
 func checkSizeFilter(minSize string, maxSize string, releaseSize uint64) (bool, error) {
 	// handle both min and max
 	if minSize != "" {
@@ -501,7 +1184,106 @@ func checkSizeFilter(minSize string, maxSize string, releaseSize uint64) (bool,
 }
 
 func (s *service) CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error) {
-	return s.releaseRepo.CanDownloadShow(ctx, release.Title, release.Season, release.Episode)
+	rejection, err := s.smartEpisodeRejection(ctx, release, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return rejection == "", nil
+}
+
+// properUpgradeOf returns the name of the release grabbed by the filter that a PROPER or REPACK replaces, or an empty string.
+// Nothing is replaced when the release or another PROPER or REPACK was already grabbed.
+func (s *service) properUpgradeOf(ctx context.Context, f *domain.Filter, release *domain.Release) (string, error) {
+	grabbed, err := s.releaseRepo.FindGrabbed(ctx, domain.FindGrabbedParams{
+		Title:     release.Title,
+		Year:      release.Year,
+		Season:    release.Season,
+		Episode:   release.Episode,
+		FilterID:  f.ID,
+		ExcludeID: release.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(grabbed) == 0 {
+		return "", nil
+	}
+
+	for _, rls := range grabbed {
+		if strings.EqualFold(rls.TorrentName, release.TorrentName) {
+			return "", nil
+		}
+
+		previous := domain.NewRelease(release.Indexer)
+		previous.ParseString(rls.TorrentName)
+
+		if previous.Proper || previous.Repack {
+			return "", nil
+		}
+	}
+
+	return grabbed[0].TorrentName, nil
+}
+
+// seasonPackRejection returns why a single episode is rejected because a season pack of the same season was already grabbed,
+// or an empty string.
+func (s *service) seasonPackRejection(ctx context.Context, release *domain.Release) (string, error) {
+	if !release.IsSingleEpisode() {
+		return "", nil
+	}
+
+	packs, err := s.releaseRepo.FindGrabbed(ctx, domain.FindGrabbedParams{Title: release.Title, Season: release.Season, ExcludeID: release.ID})
+	if err != nil {
+		return "", err
+	}
+
+	if len(packs) > 0 {
+		return fmt.Sprintf("season pack already grabbed: (%s) season: %d got: %s", release.Title, release.Season, packs[0].TorrentName), nil
+	}
+
+	return "", nil
+}
+
+// smartEpisodeRejection returns why the release is rejected by the smart episode check, or an empty string.
+// Releases are rejected when a later episode was seen, or when the same episode was already grabbed
+// in equal or better quality. Better quality is allowed through as an upgrade, ranked by the preferences of the filter when set.
+func (s *service) smartEpisodeRejection(ctx context.Context, release *domain.Release, f *domain.Filter) (string, error) {
+	canDownload, err := s.releaseRepo.CanDownloadShow(ctx, release.Title, release.Season, release.Episode)
+	if err != nil {
+		return "", err
+	}
+
+	if !canDownload {
+		return fmt.Sprintf("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode), nil
+	}
+
+	if release.Season == 0 {
+		return "", nil
+	}
+
+	grabbed, err := s.releaseRepo.FindGrabbed(ctx, domain.FindGrabbedParams{Title: release.Title, Season: release.Season, Episode: release.Episode, ExcludeID: release.ID})
+	if err != nil {
+		return "", err
+	}
+
+	quality := domain.NewReleaseQuality(release).WithPreferences(f)
+
+	for _, rls := range grabbed {
+		// language tags are not stored with the release, parse them from the name when they are ranked
+		if f != nil && (len(f.LanguagePreference) > 0 || len(f.SubtitlePreference) > 0) {
+			rls.Language = domain.ParseReleaseLanguage(rls.TorrentName)
+		}
+
+		existing := domain.NewReleaseQuality(rls).WithPreferences(f)
+
+		if quality.Compare(existing) <= 0 {
+			return fmt.Sprintf("smart episode check: already grabbed in equal or better quality: (%s) season: %d ep: %d got: %s grabbed: %s", release.Title, release.Season, release.Episode, quality, existing), nil
+		}
+	}
+
+	return "", nil
 }
 
 func (s *service) RunExternalFilters(ctx context.Context, externalFilters []domain.FilterExternal, release *domain.Release) (bool, error) {
@@ -526,9 +1308,26 @@ func (s *service) RunExternalFilters(ctx context.Context, externalFilters []doma
 
 		switch external.Type {
 		case domain.ExternalFilterTypeExec:
+			if external.IsJSONMode() {
+				ok, err := s.execJSON(ctx, external, release)
+				if err != nil {
+					return false, errors.Wrap(err, "error executing external command")
+				}
+
+				if !ok {
+					return false, nil
+				}
+
+				continue
+			}
+
 			// run external script
-			exitCode, err := s.execCmd(ctx, external, release)
+			exitCode, _, err := s.execCmd(ctx, external, release, nil)
 			if err != nil {
+				if errors.Is(err, errExecTimeout) {
+					release.AddRejectionF("external script %s timed out after %s", external.Name, external.Timeout())
+					return false, nil
+				}
 				return false, errors.Wrap(err, "error executing external command")
 			}
 
@@ -540,28 +1339,52 @@ func (s *service) RunExternalFilters(ctx context.Context, externalFilters []doma
 
 		case domain.ExternalFilterTypeWebhook:
 			// run external webhook
-			statusCode, err := s.webhook(ctx, external, release)
+			res, err := s.webhook(ctx, external, release)
 			if err != nil {
 				return false, errors.Wrap(err, "error executing external webhook")
 			}
 
-			if statusCode != external.WebhookExpectStatus {
-				s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected status code. got: %d want: %d", statusCode, external.WebhookExpectStatus)
-				release.AddRejectionF("external webhook unexpected status code. got: %d want: %d", statusCode, external.WebhookExpectStatus)
+			if res.statusCode != external.WebhookExpectStatus {
+				s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected status code. got: %d want: %d", res.statusCode, external.WebhookExpectStatus)
+				release.AddRejectionF("external webhook unexpected status code. got: %d want: %d", res.statusCode, external.WebhookExpectStatus)
 				return false, nil
 			}
+
+			if external.WebhookJSONPath != "" {
+				match, got, err := domain.MatchJSONPath(res.body, external.WebhookJSONPath, external.WebhookJSONValue)
+				if err != nil {
+					release.AddRejectionF("external webhook %s: %v", external.Name, err)
+					return false, nil
+				}
+
+				if !match {
+					want := external.WebhookJSONValue
+					if want == "" {
+						want = "any value"
+					}
+
+					s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected value at %s. got: %s want: %s", external.WebhookJSONPath, got, want)
+					release.AddRejectionF("external webhook unexpected value at %s. got: %s want: %s", external.WebhookJSONPath, got, want)
+					return false, nil
+				}
+			}
 		}
 	}
 
 	return true, nil
 }
 
-func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, release *domain.Release) (int, error) {
+// errExecTimeout is returned by execCmd when the external script did not finish within its timeout
+var errExecTimeout = errors.New("external script timed out")
+
+// execCmd runs the external script and returns its exit code and output.
+// When stdin is set it is written to the standard input of the script.
+func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, release *domain.Release, stdin []byte) (int, []byte, error) {
 	s.log.Trace().Msgf("filter exec release: %s", release.TorrentName)
 
 	if release.TorrentTmpFile == "" && strings.Contains(external.ExecArgs, "TorrentPathName") {
 		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
-			return 0, errors.Wrap(err, "error downloading torrent file for release: %s", release.TorrentName)
+			return 0, nil, errors.Wrap(err, "error downloading torrent file for release: %s", release.TorrentName)
 		}
 	}
 
@@ -569,7 +1392,7 @@ func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, r
 	if len(release.TorrentDataRawBytes) == 0 && release.TorrentTmpFile != "" {
 		t, err := os.ReadFile(release.TorrentTmpFile)
 		if err != nil {
-			return 0, errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
+			return 0, nil, errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
 		}
 
 		release.TorrentDataRawBytes = t
@@ -578,7 +1401,7 @@ func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, r
 	// check if program exists
 	cmd, err := exec.LookPath(external.ExecCmd)
 	if err != nil {
-		return 0, errors.Wrap(err, "exec failed, could not find program: %s", cmd)
+		return 0, nil, errors.Wrap(err, "exec failed, could not find program: %s", cmd)
 	}
 
 	// handle args and replace vars
@@ -587,7 +1410,7 @@ func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, r
 	// parse and replace values in argument string before continuing
 	parsedArgs, err := m.Parse(external.ExecArgs)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse macro")
+		return 0, nil, errors.Wrap(err, "could not parse macro")
 	}
 
 	// we need to split on space into a string slice, so we can spread the args into exec
@@ -595,160 +1418,118 @@ func (s *service) execCmd(ctx context.Context, external domain.FilterExternal, r
 	p.ParseBacktick = true
 	commandArgs, err := p.Parse(parsedArgs)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse into shell-words")
+		return 0, nil, errors.Wrap(err, "could not parse into shell-words")
 	}
 
-	start := time.Now()
-
-	// setup command and args
-	command := exec.Command(cmd, commandArgs...)
-
-	s.log.Debug().Msgf("script: %s args: %s", cmd, strings.Join(commandArgs, " "))
-
-	// Create a pipe to capture the standard output of the command
-	cmdOutput, err := command.StdoutPipe()
+	// env vars can use macros as well
+	parsedEnv, err := m.Parse(external.ExecEnv)
 	if err != nil {
-		s.log.Error().Err(err).Msg("could not create stdout pipe")
-		return 0, err
+		return 0, nil, errors.Wrap(err, "could not parse env macro")
 	}
 
-	duration := time.Since(start)
-
-	// Start the command
-	if err := command.Start(); err != nil {
-		s.log.Error().Err(err).Msg("error starting command")
-		return 0, err
+	env, err := domain.ParseExternalFilterEnv(parsedEnv)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "could not parse env")
 	}
 
-	// Create a buffer to store the output
-	outputBuffer := make([]byte, 4096)
+	timeout := external.Timeout()
 
-	execLogger := s.log.With().Str("release", release.TorrentName).Str("filter", release.FilterName).Logger()
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	for {
-		// Read the output into the buffer
-		n, err := cmdOutput.Read(outputBuffer)
-		if err != nil {
-			break
-		}
+	start := time.Now()
 
-		// Write the output to the logger
-		execLogger.Trace().Msg(string(outputBuffer[:n]))
-	}
+	// setup command and args
+	command := exec.CommandContext(cmdCtx, cmd, commandArgs...)
+	command.Env = append(os.Environ(), env...)
 
-	// Wait for the command to finish and check for any errors
-	if err := command.Wait(); err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			s.log.Debug().Msgf("filter script command exited with non zero code: %v", exitErr.ExitCode())
-			return exitErr.ExitCode(), nil
-		}
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
 
-		s.log.Error().Err(err).Msg("error waiting for command")
-		return 0, err
+	if stdin != nil {
+		command.Stdin = bytes.NewReader(stdin)
 	}
 
-	s.log.Debug().Msgf("executed external script: (%s), args: (%s) for release: (%s) indexer: (%s) total time (%s)", cmd, parsedArgs, release.TorrentName, release.Indexer, duration)
+	s.log.Debug().Msgf("script: %s args: %s", cmd, strings.Join(commandArgs, " "))
 
-	return 0, nil
-}
+	err = command.Run()
 
-func (s *service) webhook(ctx context.Context, external domain.FilterExternal, release *domain.Release) (int, error) {
-	s.log.Trace().Msgf("preparing to run external webhook filter to: (%s) payload: (%s)", external.WebhookHost, external.WebhookData)
+	duration := time.Since(start)
 
-	if external.WebhookHost == "" {
-		return 0, errors.New("external filter: missing host for webhook")
-	}
+	execLogger := s.log.With().Str("release", release.TorrentName).Str("filter", release.FilterName).Str("external", external.Name).Logger()
 
-	// if webhook data contains TorrentPathName or TorrentDataRawBytes, lets download the torrent file
-	if release.TorrentTmpFile == "" && (strings.Contains(external.WebhookData, "TorrentPathName") || strings.Contains(external.WebhookData, "TorrentDataRawBytes")) {
-		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
-			return 0, errors.Wrap(err, "webhook: could not download torrent file for release: %s", release.TorrentName)
-		}
+	if stdout.Len() > 0 {
+		execLogger.Trace().Msg(stdout.String())
 	}
 
-	// if webhook data contains TorrentDataRawBytes, lets read the file into bytes we can then use in the macro
-	if len(release.TorrentDataRawBytes) == 0 && strings.Contains(external.WebhookData, "TorrentDataRawBytes") {
-		t, err := os.ReadFile(release.TorrentTmpFile)
-		if err != nil {
-			return 0, errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
-		}
-
-		release.TorrentDataRawBytes = t
+	if stderr.Len() > 0 {
+		execLogger.Debug().Msgf("external script stderr: %s", strings.TrimSpace(stderr.String()))
 	}
 
-	m := domain.NewMacro(*release)
-
-	// parse and replace values in argument string before continuing
-	dataArgs, err := m.Parse(external.WebhookData)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse webhook data macro: %s", external.WebhookData)
-	}
+		if errors.Is(cmdCtx.Err(), context.DeadlineExceeded) {
+			execLogger.Warn().Msgf("external script (%s) killed after timeout of %s", cmd, timeout)
+			return 0, nil, errExecTimeout
+		}
 
-	s.log.Trace().Msgf("sending %s to external webhook filter: (%s) payload: (%s)", external.WebhookMethod, external.WebhookHost, external.WebhookData)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			s.log.Debug().Msgf("filter script command exited with non zero code: %v", exitErr.ExitCode())
+			return exitErr.ExitCode(), stdout.Bytes(), nil
+		}
 
-	t := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
+		s.log.Error().Err(err).Msg("error running command")
+		return 0, nil, err
 	}
 
-	client := http.Client{Transport: t, Timeout: 120 * time.Second}
+	s.log.Debug().Msgf("executed external script: (%s), args: (%s) for release: (%s) indexer: (%s) total time (%s)", cmd, parsedArgs, release.TorrentName, release.Indexer, duration)
 
-	method := http.MethodPost
-	if external.WebhookMethod != "" {
-		method = external.WebhookMethod
-	}
+	return 0, stdout.Bytes(), nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, external.WebhookHost, nil)
+// execJSON runs an external script using the json contract.
+// The script gets a domain.ExternalFilterInput on stdin and must print a domain.ExternalFilterOutput to stdout.
+// A non zero exit code or invalid output rejects the release.
+func (s *service) execJSON(ctx context.Context, external domain.FilterExternal, release *domain.Release) (bool, error) {
+	input, err := json.Marshal(domain.NewExternalFilterInput(external, release))
 	if err != nil {
-		return 0, errors.Wrap(err, "could not build request for webhook")
+		return false, errors.Wrap(err, "could not marshal external filter input")
 	}
 
-	if external.WebhookData != "" && dataArgs != "" {
-		req, err = http.NewRequestWithContext(ctx, method, external.WebhookHost, bytes.NewBufferString(dataArgs))
-		if err != nil {
-			return 0, errors.Wrap(err, "could not build request for webhook")
+	exitCode, stdout, err := s.execCmd(ctx, external, release, input)
+	if err != nil {
+		if errors.Is(err, errExecTimeout) {
+			release.AddRejectionF("external script %s timed out after %s", external.Name, external.Timeout())
+			return false, nil
 		}
+		return false, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "autobrr")
-
-	if external.WebhookHeaders != "" {
-		headers := strings.Split(external.WebhookHeaders, ";")
-
-		for _, header := range headers {
-			h := strings.Split(header, "=")
-
-			if len(h) != 2 {
-				continue
-			}
-
-			// add header to req
-			req.Header.Add(http.CanonicalHeaderKey(h[0]), h[1])
-		}
+	if exitCode != 0 {
+		release.AddRejectionF("external script %s exited with code: %d", external.Name, exitCode)
+		return false, nil
 	}
 
-	start := time.Now()
-
-	res, err := client.Do(req)
+	output, err := domain.ParseExternalFilterOutput(stdout)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not make request for webhook")
+		s.log.Debug().Err(err).Msgf("external script %s returned invalid output: %s", external.Name, string(stdout))
+		release.AddRejectionF("external script %s returned invalid output: %v", external.Name, err)
+		return false, nil
 	}
 
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return 0, errors.Wrap(err, "could not read request body")
-	}
+	if !output.Accept {
+		reason := output.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
 
-	if len(body) > 0 {
-		s.log.Debug().Msgf("filter external webhook response status: %d body: %s", res.StatusCode, body)
+		s.log.Trace().Msgf("filter.Service.CheckFilter: external script %s rejected release: %s", external.Name, reason)
+		release.AddRejectionF("external script %s rejected: %s", external.Name, reason)
+		return false, nil
 	}
 
-	s.log.Debug().Msgf("successfully ran external webhook filter to: (%s) payload: (%s) finished in %s", external.WebhookHost, dataArgs, time.Since(start))
+	output.Overrides.Apply(release)
 
-	return res.StatusCode, nil
+	return true, nil
 }
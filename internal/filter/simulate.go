@@ -0,0 +1,113 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	simulateDefaultLimit = 100
+	simulateMaxLimit     = 1000
+)
+
+// Simulate replays the last limit stored releases through a draft filter without storing it or running any actions.
+// Releases are parsed again from their name, so fields that are only announced (category, uploader, freeleech)
+// are unknown and checks on them reject the release. Schedules are checked against the time the release was seen.
+func (s *service) Simulate(ctx context.Context, filter *domain.Filter, limit int) (*domain.FilterSimulation, error) {
+	if limit <= 0 {
+		limit = simulateDefaultLimit
+	}
+
+	if limit > simulateMaxLimit {
+		return nil, errors.New("validation: limit can't be more than %d", simulateMaxLimit)
+	}
+
+	setRequiredLists(filter)
+
+	if err := validateExpression(filter.Expression); err != nil {
+		return nil, err
+	}
+
+	if err := validateSchedule(filter.Schedule); err != nil {
+		return nil, err
+	}
+
+	if err := filter.ValidateRegexFields(); err != nil {
+		return nil, errors.Wrap(err, "validation")
+	}
+
+	if err := s.validateGroupID(ctx, filter.GroupID); err != nil {
+		return nil, err
+	}
+
+	filters := []domain.Filter{*filter}
+	if err := s.applyGroups(ctx, filters); err != nil {
+		return nil, err
+	}
+	f := filters[0]
+
+	indexers := make(map[string]struct{}, len(f.Indexers))
+	for _, indexer := range f.Indexers {
+		indexers[indexer.Identifier] = struct{}{}
+	}
+
+	releases, _, _, err := s.releaseRepo.Find(ctx, domain.ReleaseQueryParams{Limit: uint64(limit)})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find releases")
+	}
+
+	simulation := &domain.FilterSimulation{
+		Total:   len(releases),
+		Results: make([]domain.FilterSimulationResult, 0, len(releases)),
+	}
+
+	for _, stored := range releases {
+		release := domain.NewRelease(stored.Indexer)
+		release.ID = stored.ID
+		release.Protocol = stored.Protocol
+		release.Timestamp = stored.Timestamp
+		release.ParseString(stored.TorrentName)
+		release.Size = stored.Size
+
+		result := domain.FilterSimulationResult{
+			ReleaseID:   stored.ID,
+			TorrentName: stored.TorrentName,
+			Indexer:     stored.Indexer,
+			Timestamp:   stored.Timestamp,
+			Rejections:  []string{},
+			Notes:       []string{},
+		}
+
+		// filters only receive releases from their indexers
+		if len(indexers) > 0 {
+			if _, ok := indexers[stored.Indexer]; !ok {
+				result.Rejections = append(result.Rejections, fmt.Sprintf("indexer not enabled for filter: %s", stored.Indexer))
+				simulation.Results = append(simulation.Results, result)
+				continue
+			}
+		}
+
+		dryRun, err := s.dryRunFilter(ctx, f, release)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Match = dryRun.Match
+		result.Rejections = dryRun.Rejections
+		result.Notes = dryRun.Notes
+
+		if result.Match {
+			simulation.Matched++
+		}
+
+		simulation.Results = append(simulation.Results, result)
+	}
+
+	return simulation, nil
+}
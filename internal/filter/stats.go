@@ -0,0 +1,303 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	statsJobIdentifier = "filter-stats"
+
+	// statsMaxDays is how long the counters are kept
+	statsMaxDays = 90
+)
+
+type statsKey struct {
+	filterID int
+	bucket   time.Time
+}
+
+// statsCollector counts filter checks in memory, they are written to the database by the stats job
+type statsCollector struct {
+	mu      sync.Mutex
+	entries map[statsKey]*domain.FilterStatsEntry
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{entries: make(map[statsKey]*domain.FilterStatsEntry)}
+}
+
+func (c *statsCollector) record(filterID int, now time.Time, match bool, rejections []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := statsKey{filterID: filterID, bucket: now.UTC().Truncate(time.Hour)}
+
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &domain.FilterStatsEntry{FilterID: filterID, Bucket: key.bucket, Rejections: map[string]int{}}
+		c.entries[key] = entry
+	}
+
+	entry.Evaluated++
+
+	if match {
+		entry.Matched++
+		return
+	}
+
+	entry.Rejected++
+
+	if len(rejections) == 0 {
+		entry.Rejections["unknown"]++
+		return
+	}
+
+	// a release can fail several conditions, each is counted once
+	seen := make(map[string]struct{}, len(rejections))
+	for _, rejection := range rejections {
+		condition := domain.RejectionCondition(rejection)
+		if _, ok := seen[condition]; ok {
+			continue
+		}
+
+		seen[condition] = struct{}{}
+		entry.Rejections[condition]++
+	}
+}
+
+// take returns the collected entries and resets the collector
+func (c *statsCollector) take() []domain.FilterStatsEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]domain.FilterStatsEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, *entry)
+	}
+
+	c.entries = make(map[statsKey]*domain.FilterStatsEntry)
+
+	return entries
+}
+
+// restore adds entries that could not be stored back to the collector
+func (c *statsCollector) restore(entries []domain.FilterStatsEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range entries {
+		key := statsKey{filterID: e.FilterID, bucket: e.Bucket}
+
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &domain.FilterStatsEntry{FilterID: e.FilterID, Bucket: e.Bucket, Rejections: map[string]int{}}
+			c.entries[key] = entry
+		}
+
+		entry.Evaluated += e.Evaluated
+		entry.Matched += e.Matched
+		entry.Rejected += e.Rejected
+
+		for condition, count := range e.Rejections {
+			entry.Rejections[condition] += count
+		}
+	}
+}
+
+// StatsJob writes the collected filter counters and removes old ones
+type StatsJob struct {
+	log zerolog.Logger
+	svc *service
+
+	lastCleanup time.Time
+}
+
+func (j *StatsJob) Run() {
+	ctx := context.Background()
+
+	j.svc.flushStats(ctx)
+
+	if time.Since(j.lastCleanup) < time.Hour {
+		return
+	}
+
+	if err := j.svc.statsRepo.DeleteOld(ctx, time.Now().AddDate(0, 0, -statsMaxDays)); err != nil {
+		j.log.Error().Err(err).Msg("error when deleting old filter stats")
+		return
+	}
+
+	j.lastCleanup = time.Now()
+}
+
+func (s *service) flushStats(ctx context.Context) {
+	entries := s.stats.take()
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := s.statsRepo.Store(ctx, entries); err != nil {
+		s.log.Error().Err(err).Msg("could not store filter stats")
+		s.stats.restore(entries)
+	}
+}
+
+// GetStats returns the counters of a filter for the last days, in hourly or daily buckets in local time.
+// Actions are counted from the stored action statuses.
+func (s *service) GetStats(ctx context.Context, filterID int, bucketSize domain.FilterStatsBucketSize, days int) (*domain.FilterStats, error) {
+	if days <= 0 {
+		days = 7
+	}
+
+	if days > statsMaxDays {
+		return nil, errors.New("validation: days can't be more than %d", statsMaxDays)
+	}
+
+	if bucketSize == "" {
+		bucketSize = domain.FilterStatsBucketDay
+	}
+
+	if bucketSize != domain.FilterStatsBucketHour && bucketSize != domain.FilterStatsBucketDay {
+		return nil, errors.New("validation: invalid bucket size: %s", bucketSize)
+	}
+
+	if _, err := s.repo.FindByID(ctx, filterID); err != nil {
+		return nil, err
+	}
+
+	// include the counters that are not written yet
+	s.flushStats(ctx)
+
+	now := time.Now()
+	since := bucketStart(now, bucketSize).AddDate(0, 0, -days+1)
+	if bucketSize == domain.FilterStatsBucketHour {
+		since = bucketStart(now, bucketSize).Add(-time.Duration(days*24-1) * time.Hour)
+	}
+
+	entries, err := s.statsRepo.Find(ctx, filterID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find filter stats")
+	}
+
+	statuses, err := s.statsRepo.FindActionStatuses(ctx, filterID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find action statuses")
+	}
+
+	stats := &domain.FilterStats{
+		FilterID:   filterID,
+		Since:      since,
+		BucketSize: string(bucketSize),
+		Buckets:    make([]domain.FilterStatsBucket, 0),
+	}
+
+	buckets := make(map[time.Time]*domain.FilterStatsBucket)
+	totalRejections := make(map[string]int)
+	bucketRejections := make(map[time.Time]map[string]int)
+
+	bucket := func(t time.Time) *domain.FilterStatsBucket {
+		start := bucketStart(t, bucketSize)
+
+		b, ok := buckets[start]
+		if !ok {
+			b = &domain.FilterStatsBucket{Start: start}
+			buckets[start] = b
+			bucketRejections[start] = make(map[string]int)
+		}
+
+		return b
+	}
+
+	for _, entry := range entries {
+		b := bucket(entry.Bucket)
+		addStatsCounts(&b.FilterStatsCounts, entry)
+		addStatsCounts(&stats.Totals, entry)
+
+		for condition, count := range entry.Rejections {
+			bucketRejections[b.Start][condition] += count
+			totalRejections[condition] += count
+		}
+
+		last := entry.Bucket.Local()
+		if entry.Evaluated > 0 && (stats.LastEvaluatedAt == nil || last.After(*stats.LastEvaluatedAt)) {
+			stats.LastEvaluatedAt = &last
+		}
+
+		if entry.Matched > 0 && (stats.LastMatchedAt == nil || last.After(*stats.LastMatchedAt)) {
+			stats.LastMatchedAt = &last
+		}
+	}
+
+	for _, status := range statuses {
+		b := bucket(status.Timestamp)
+		addActionCounts(&b.FilterStatsCounts, status.Status)
+		addActionCounts(&stats.Totals, status.Status)
+	}
+
+	stats.Totals.Rejections = sortedRejectionCounts(totalRejections)
+
+	for start, b := range buckets {
+		b.Rejections = sortedRejectionCounts(bucketRejections[start])
+		stats.Buckets = append(stats.Buckets, *b)
+	}
+
+	sort.Slice(stats.Buckets, func(i, j int) bool {
+		return stats.Buckets[i].Start.Before(stats.Buckets[j].Start)
+	})
+
+	return stats, nil
+}
+
+func bucketStart(t time.Time, bucketSize domain.FilterStatsBucketSize) time.Time {
+	t = t.Local()
+
+	if bucketSize == domain.FilterStatsBucketHour {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func addStatsCounts(counts *domain.FilterStatsCounts, entry domain.FilterStatsEntry) {
+	counts.Evaluated += entry.Evaluated
+	counts.Matched += entry.Matched
+	counts.Rejected += entry.Rejected
+}
+
+func addActionCounts(counts *domain.FilterStatsCounts, status domain.ReleasePushStatus) {
+	switch status {
+	case domain.ReleasePushStatusApproved:
+		counts.ActionsPushed++
+	case domain.ReleasePushStatusRejected:
+		counts.ActionsRejected++
+	case domain.ReleasePushStatusErr:
+		counts.ActionErrors++
+	}
+}
+
+// sortedRejectionCounts returns the conditions that rejected the most releases first
+func sortedRejectionCounts(rejections map[string]int) []domain.FilterStatsRejectionCount {
+	counts := make([]domain.FilterStatsRejectionCount, 0, len(rejections))
+	for condition, count := range rejections {
+		counts = append(counts, domain.FilterStatsRejectionCount{Condition: condition, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Condition < counts[j].Condition
+	})
+
+	return counts
+}
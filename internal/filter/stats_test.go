@@ -0,0 +1,41 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_statsCollector(t *testing.T) {
+	c := newStatsCollector()
+	now := time.Date(2023, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	c.record(1, now, true, nil)
+	c.record(1, now, false, []string{"size: larger than max size", "size not matching. got: 1 want min:  max: 1GB", "resolution not matching. got: 720p want: [1080p]"})
+	c.record(1, now.Add(time.Minute), false, nil)
+
+	entries := c.take()
+	assert.Equal(t, []domain.FilterStatsEntry{{
+		FilterID:   1,
+		Bucket:     time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		Evaluated:  3,
+		Matched:    1,
+		Rejected:   2,
+		Rejections: map[string]int{"size": 1, "resolution": 1, "unknown": 1},
+	}}, entries)
+	assert.Empty(t, c.take())
+
+	c.restore(entries)
+	c.record(1, now, true, nil)
+
+	entries = c.take()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 4, entries[0].Evaluated)
+	assert.Equal(t, 2, entries[0].Matched)
+}
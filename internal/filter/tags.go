@@ -0,0 +1,107 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// ListFilterTags returns the tags used by any filter
+func (s *service) ListFilterTags(ctx context.Context) ([]string, error) {
+	filters, err := s.repo.ListFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.FilterTagsOf(filters), nil
+}
+
+func (s *service) findByFilterTag(ctx context.Context, tag string) ([]domain.Filter, error) {
+	if tag == "" {
+		return nil, errors.New("validation: tag can't be empty")
+	}
+
+	filters, err := s.repo.ListFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := make([]domain.Filter, 0)
+	for _, f := range filters {
+		if f.HasFilterTag(tag) {
+			tagged = append(tagged, f)
+		}
+	}
+
+	return tagged, nil
+}
+
+// ToggleEnabledByTag enables or disables all filters with the tag
+func (s *service) ToggleEnabledByTag(ctx context.Context, tag string, enabled bool) (*domain.FilterBulkResult, error) {
+	filters, err := s.findByFilterTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.FilterBulkResult{Tag: tag, Filters: []int{}}
+	for _, f := range filters {
+		if f.Enabled == enabled {
+			continue
+		}
+
+		if err := s.ToggleEnabled(ctx, f.ID, enabled); err != nil {
+			return nil, errors.Wrap(err, "could not toggle filter: %s", f.Name)
+		}
+
+		result.Filters = append(result.Filters, f.ID)
+	}
+
+	s.log.Info().Msgf("filter.toggle_enabled_by_tag: set enabled %v on %d filters with tag %s", enabled, len(result.Filters), tag)
+
+	return result, nil
+}
+
+// DeleteByTag deletes all filters with the tag
+func (s *service) DeleteByTag(ctx context.Context, tag string) (*domain.FilterBulkResult, error) {
+	filters, err := s.findByFilterTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.FilterBulkResult{Tag: tag, Filters: []int{}}
+	for _, f := range filters {
+		if err := s.Delete(ctx, f.ID); err != nil {
+			return nil, errors.Wrap(err, "could not delete filter: %s", f.Name)
+		}
+
+		result.Filters = append(result.Filters, f.ID)
+	}
+
+	s.log.Info().Msgf("filter.delete_by_tag: deleted %d filters with tag %s", len(result.Filters), tag)
+
+	return result, nil
+}
+
+// ExportByTag exports all filters with the tag in the format of the web ui export
+func (s *service) ExportByTag(ctx context.Context, tag string) ([]domain.FilterExport, error) {
+	filters, err := s.findByFilterTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]domain.FilterExport, 0, len(filters))
+	for _, f := range filters {
+		filter, err := s.repo.FindByID(ctx, f.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %s", f.Name)
+		}
+
+		exports = append(exports, domain.NewFilterExport(*filter))
+	}
+
+	return exports, nil
+}
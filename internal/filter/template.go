@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func (s *service) ListTemplates(ctx context.Context) ([]domain.FilterTemplate, error) {
+	return s.templateRepo.List(ctx)
+}
+
+func (s *service) FindTemplateByID(ctx context.Context, templateID int) (*domain.FilterTemplate, error) {
+	return s.templateRepo.FindByID(ctx, templateID)
+}
+
+func (s *service) StoreTemplate(ctx context.Context, template *domain.FilterTemplate) error {
+	if err := template.Validate(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	resetTemplateIDs(template)
+
+	if err := s.templateRepo.Store(ctx, template); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter template: %v", template.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, template *domain.FilterTemplate) error {
+	if err := template.Validate(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	resetTemplateIDs(template)
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		s.log.Error().Err(err).Msgf("could not update filter template: %v", template.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteTemplate(ctx context.Context, templateID int) error {
+	if err := s.templateRepo.Delete(ctx, templateID); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete filter template: %v", templateID)
+		return err
+	}
+
+	return nil
+}
+
+// resetTemplateIDs clears the ids of the template filter and its actions, they are set when a filter is created
+func resetTemplateIDs(template *domain.FilterTemplate) {
+	template.Filter.ID = 0
+	template.Filter.ActionsCount = 0
+
+	for _, action := range template.Filter.Actions {
+		action.ID = 0
+		action.FilterID = 0
+	}
+
+	for i := range template.Filter.External {
+		template.Filter.External[i].ID = 0
+		template.Filter.External[i].FilterId = 0
+	}
+
+	for i := range template.Filter.ListSources {
+		template.Filter.ListSources[i].ID = 0
+		template.Filter.ListSources[i].FilterID = 0
+	}
+}
+
+// CreateFromTemplate creates a filter with its indexers, actions and external filters from a template and the
+// values of its variables
+func (s *service) CreateFromTemplate(ctx context.Context, templateID int, instance domain.FilterTemplateInstance) (*domain.Filter, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := template.Render(instance)
+	if err != nil {
+		return nil, errors.Wrap(err, "validation")
+	}
+
+	if err := s.store(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.StoreIndexerConnections(ctx, filter.ID, filter.Indexers); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter indexer connections: %s", filter.Name)
+		return nil, err
+	}
+
+	if _, err := s.actionRepo.StoreFilterActions(ctx, int64(filter.ID), filter.Actions); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter actions: %s", filter.Name)
+		return nil, err
+	}
+
+	if err := s.repo.StoreFilterExternal(ctx, filter.ID, filter.External); err != nil {
+		s.log.Error().Err(err).Msgf("could not store external filters: %s", filter.Name)
+		return nil, err
+	}
+
+	s.recordRevision(ctx, filter.ID, fmt.Sprintf("created from template %s", template.Name))
+
+	return s.FindByID(ctx, filter.ID)
+}
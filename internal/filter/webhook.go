@@ -0,0 +1,233 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/avast/retry-go"
+)
+
+// webhookCacheTTL is how long a webhook filter response is reused for the same release and request
+const webhookCacheTTL = time.Minute
+
+type webhookResponse struct {
+	statusCode int
+	body       []byte
+}
+
+type webhookCacheEntry struct {
+	response  webhookResponse
+	expiresAt time.Time
+}
+
+// webhookResponseCache keeps webhook filter responses so a release that is checked by multiple filters
+// with the same webhook does not send the same request again
+type webhookResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]webhookCacheEntry
+}
+
+func newWebhookResponseCache() *webhookResponseCache {
+	return &webhookResponseCache{entries: make(map[string]webhookCacheEntry)}
+}
+
+func (c *webhookResponseCache) get(key string, now time.Time) (webhookResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		return webhookResponse{}, false
+	}
+
+	return entry.response, true
+}
+
+func (c *webhookResponseCache) set(key string, response webhookResponse, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// drop expired entries so the cache does not grow with every release
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = webhookCacheEntry{response: response, expiresAt: now.Add(webhookCacheTTL)}
+}
+
+// webhookCacheKey identifies a webhook request for a release
+func webhookCacheKey(release *domain.Release, method, host, headers, data string) string {
+	h := sha256.New()
+
+	for _, part := range []string{release.Indexer, release.TorrentID, release.TorrentName, release.DownloadURL, method, host, headers, data} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// errWebhookRetryStatus is returned for responses with a status the webhook filter retries on
+var errWebhookRetryStatus = errors.New("webhook retry status")
+
+func (s *service) webhook(ctx context.Context, external domain.FilterExternal, release *domain.Release) (webhookResponse, error) {
+	s.log.Trace().Msgf("preparing to run external webhook filter to: (%s) payload: (%s)", external.WebhookHost, external.WebhookData)
+
+	if external.WebhookHost == "" {
+		return webhookResponse{}, errors.New("external filter: missing host for webhook")
+	}
+
+	// if webhook data contains TorrentPathName or TorrentDataRawBytes, lets download the torrent file
+	if release.TorrentTmpFile == "" && (strings.Contains(external.WebhookData, "TorrentPathName") || strings.Contains(external.WebhookData, "TorrentDataRawBytes")) {
+		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			return webhookResponse{}, errors.Wrap(err, "webhook: could not download torrent file for release: %s", release.TorrentName)
+		}
+	}
+
+	// if webhook data contains TorrentDataRawBytes, lets read the file into bytes we can then use in the macro
+	if len(release.TorrentDataRawBytes) == 0 && strings.Contains(external.WebhookData, "TorrentDataRawBytes") {
+		t, err := os.ReadFile(release.TorrentTmpFile)
+		if err != nil {
+			return webhookResponse{}, errors.Wrap(err, "could not read torrent file: %s", release.TorrentTmpFile)
+		}
+
+		release.TorrentDataRawBytes = t
+	}
+
+	m := domain.NewMacro(*release)
+
+	// parse and replace values in argument string before continuing
+	dataArgs, err := m.Parse(external.WebhookData)
+	if err != nil {
+		return webhookResponse{}, errors.Wrap(err, "could not parse webhook data macro: %s", external.WebhookData)
+	}
+
+	retryStatus, err := domain.ParseWebhookRetryStatus(external.WebhookRetryStatus)
+	if err != nil {
+		return webhookResponse{}, err
+	}
+
+	method := http.MethodPost
+	if external.WebhookMethod != "" {
+		method = external.WebhookMethod
+	}
+
+	cacheKey := webhookCacheKey(release, method, external.WebhookHost, external.WebhookHeaders, dataArgs)
+
+	if cached, ok := s.webhookCache.get(cacheKey, time.Now()); ok {
+		s.log.Debug().Msgf("using cached external webhook filter response from: (%s) status: %d", external.WebhookHost, cached.statusCode)
+		return cached, nil
+	}
+
+	s.log.Trace().Msgf("sending %s to external webhook filter: (%s) payload: (%s)", method, external.WebhookHost, external.WebhookData)
+
+	t := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+
+	client := http.Client{Transport: t, Timeout: 120 * time.Second}
+
+	retryDelay := time.Second
+	if external.WebhookRetryDelay > 0 {
+		retryDelay = time.Duration(external.WebhookRetryDelay) * time.Second
+	}
+
+	start := time.Now()
+
+	var res webhookResponse
+
+	err = retry.Do(
+		func() error {
+			var body io.Reader
+			if external.WebhookData != "" && dataArgs != "" {
+				body = bytes.NewBufferString(dataArgs)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, method, external.WebhookHost, body)
+			if err != nil {
+				return retry.Unrecoverable(errors.Wrap(err, "could not build request for webhook"))
+			}
+
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "autobrr")
+
+			if external.WebhookHeaders != "" {
+				headers := strings.Split(external.WebhookHeaders, ";")
+
+				for _, header := range headers {
+					h := strings.Split(header, "=")
+
+					if len(h) != 2 {
+						continue
+					}
+
+					// add header to req
+					req.Header.Add(http.CanonicalHeaderKey(h[0]), h[1])
+				}
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return errors.Wrap(err, "could not make request for webhook")
+			}
+
+			defer resp.Body.Close()
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return errors.Wrap(err, "could not read request body")
+			}
+
+			res = webhookResponse{statusCode: resp.StatusCode, body: respBody}
+
+			if len(respBody) > 0 {
+				s.log.Debug().Msgf("filter external webhook response status: %d body: %s", resp.StatusCode, respBody)
+			}
+
+			for _, code := range retryStatus {
+				if resp.StatusCode == code {
+					return errWebhookRetryStatus
+				}
+			}
+
+			return nil
+		},
+		retry.Context(ctx),
+		retry.Attempts(uint(external.WebhookRetryAttempts)+1),
+		retry.Delay(retryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.MaxDelay(domain.ExternalFilterWebhookRetryDelayMax*time.Second),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(n uint, err error) {
+			s.log.Debug().Msgf("external webhook filter to: (%s) attempt %d failed: %v", external.WebhookHost, n+1, err)
+		}),
+	)
+	if err != nil && !errors.Is(err, errWebhookRetryStatus) {
+		return webhookResponse{}, err
+	}
+
+	s.webhookCache.set(cacheKey, res, time.Now())
+
+	s.log.Debug().Msgf("successfully ran external webhook filter to: (%s) payload: (%s) finished in %s", external.WebhookHost, dataArgs, time.Since(start))
+
+	return res, nil
+}
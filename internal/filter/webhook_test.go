@@ -0,0 +1,97 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webhookResponseCache(t *testing.T) {
+	c := newWebhookResponseCache()
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok := c.get("key", now)
+	assert.False(t, ok)
+
+	c.set("key", webhookResponse{statusCode: 200, body: []byte("ok")}, now)
+
+	res, ok := c.get("key", now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 200, res.statusCode)
+
+	_, ok = c.get("key", now.Add(webhookCacheTTL+time.Second))
+	assert.False(t, ok)
+
+	// expired entries are dropped on set
+	c.set("other", webhookResponse{statusCode: 204}, now.Add(webhookCacheTTL+time.Second))
+	assert.Len(t, c.entries, 1)
+}
+
+func Test_service_webhook(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// fail the first request to test retries
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result": {"allowed": true}}`))
+	}))
+	defer srv.Close()
+
+	s := &service{
+		log:          zerolog.Nop(),
+		webhookCache: newWebhookResponseCache(),
+	}
+
+	external := domain.FilterExternal{
+		Name:                 "webhook",
+		Type:                 domain.ExternalFilterTypeWebhook,
+		Enabled:              true,
+		WebhookHost:          srv.URL,
+		WebhookData:          `{"name": "{{ .TorrentName }}"}`,
+		WebhookExpectStatus:  http.StatusOK,
+		WebhookRetryStatus:   "500, 503",
+		WebhookRetryAttempts: 2,
+		WebhookRetryDelay:    1,
+		WebhookJSONPath:      "result.allowed",
+		WebhookJSONValue:     "true",
+	}
+
+	release := domain.NewRelease("mock")
+	release.ParseString("Servant.S01E01.2160p.ATVP.WEB-DL.DDP.5.1.Atmos.DV.HEVC-FLUX")
+
+	ok, err := s.RunExternalFilters(context.Background(), []domain.FilterExternal{external}, release)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// the same release and request is served from the cache
+	ok, err = s.RunExternalFilters(context.Background(), []domain.FilterExternal{external}, release)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// the cached response is checked against the json condition of another filter
+	external.WebhookJSONValue = "false"
+
+	ok, err = s.RunExternalFilters(context.Background(), []domain.FilterExternal{external}, release)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"external webhook unexpected value at result.allowed. got: true want: false"}, release.Rejections)
+	assert.Equal(t, int32(2), requests.Load())
+}
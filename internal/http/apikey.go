@@ -20,6 +20,7 @@ type apikeyService interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	FindAPIKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type apikeyHandler struct {
@@ -20,6 +20,10 @@ type authService interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	ListUsers(ctx context.Context) ([]string, error)
+	UpdatePassword(ctx context.Context, username, password string) error
+	DeleteUser(ctx context.Context, username string) error
+	RecoveryLogin(ctx context.Context, token string) error
 }
 
 type authHandler struct {
@@ -47,6 +51,7 @@ func (h authHandler) Routes(r chi.Router) {
 	r.Post("/onboard", h.onboard)
 	r.Get("/onboard", h.canOnboard)
 	r.Get("/validate", h.validate)
+	r.Get("/recover", h.recover)
 }
 
 func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
@@ -60,6 +65,40 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := h.service.Login(ctx, data.Username, data.Password); err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed login attempt username: [%s] ip: %s", data.Username, ReadUserIP(r))
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: bad credentials"))
+		return
+	}
+
+	if err := h.startSession(w, r, data.Username); err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
+}
+
+// recover logs in with a one time token created by autobrrctl auth:recovery-enable and redirects to the web ui
+func (h authHandler) recover(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RecoveryLogin(r.Context(), r.URL.Query().Get("token")); err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed recovery login attempt ip: %s", ReadUserIP(r))
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: invalid or expired recovery token"))
+		return
+	}
+
+	if err := h.startSession(w, r, "recovery"); err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log.Warn().Msgf("Auth: Recovery login ip: %s", ReadUserIP(r))
+
+	http.Redirect(w, r, h.config.BaseURL, http.StatusFound)
+}
+
+// startSession sets the session cookie for an authenticated user
+func (h authHandler) startSession(w http.ResponseWriter, r *http.Request, username string) error {
 	h.cookieStore.Options.HttpOnly = true
 	h.cookieStore.Options.SameSite = http.SameSiteLaxMode
 	h.cookieStore.Options.Path = h.config.BaseURL
@@ -74,23 +113,17 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 		h.cookieStore.Options.SameSite = http.SameSiteStrictMode
 	}
 
-	if _, err := h.service.Login(ctx, data.Username, data.Password); err != nil {
-		h.log.Error().Err(err).Msgf("Auth: Failed login attempt username: [%s] ip: %s", data.Username, ReadUserIP(r))
-		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: bad credentials"))
-		return
-	}
-
 	// create new session
 	session, _ := h.cookieStore.Get(r, "user_session")
 
 	// Set user as authenticated
 	session.Values["authenticated"] = true
+	session.Values["username"] = username
 	if err := session.Save(r, w); err != nil {
-		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
-		return
+		return errors.Wrap(err, "could not save session")
 	}
 
-	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
+	return nil
 }
 
 func (h authHandler) logout(w http.ResponseWriter, r *http.Request) {
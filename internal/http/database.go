@@ -0,0 +1,76 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type databaseHandler struct {
+	encoder encoder
+	db      *database.DB
+}
+
+func newDatabaseHandler(encoder encoder, db *database.DB) *databaseHandler {
+	return &databaseHandler{
+		encoder: encoder,
+		db:      db,
+	}
+}
+
+func (h databaseHandler) Routes(r chi.Router) {
+	r.Get("/check", h.check)
+	r.With(RequireAdmin).Post("/vacuum", h.vacuum)
+}
+
+func (h databaseHandler) check(w http.ResponseWriter, r *http.Request) {
+	tables, err := h.db.TableStats(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	problems, err := h.db.Check(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if problems == nil {
+		problems = []string{}
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]any{
+		"tables":   tables,
+		"problems": problems,
+	})
+}
+
+func (h databaseHandler) vacuum(w http.ResponseWriter, r *http.Request) {
+	before, err := h.db.TableStats(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.db.Vacuum(r.Context()); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	after, err := h.db.TableStats(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]any{
+		"before": before,
+		"after":  after,
+	})
+}
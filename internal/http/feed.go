@@ -6,10 +6,12 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -20,9 +22,14 @@ type feedService interface {
 	Update(ctx context.Context, feed *domain.Feed) error
 	Delete(ctx context.Context, id int) error
 	DeleteFeedCache(ctx context.Context, id int) error
+	GetCache(ctx context.Context, feedId int, limit int) (*domain.FeedCache, error)
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Test(ctx context.Context, feed *domain.Feed) error
+	Preview(ctx context.Context, feed *domain.Feed) (*domain.FeedPreview, error)
 	GetLastRunData(ctx context.Context, id int) (string, error)
+	Backfill(ctx context.Context, id int, settings *domain.FeedBackfillSettings) error
+	RefreshCaps(ctx context.Context, id int) (*domain.FeedCaps, error)
+	GetRuns(ctx context.Context, id int) ([]domain.FeedRun, error)
 }
 
 type feedHandler struct {
@@ -41,13 +48,18 @@ func (h feedHandler) Routes(r chi.Router) {
 	r.Get("/", h.find)
 	r.Post("/", h.store)
 	r.Post("/test", h.test)
+	r.Post("/preview", h.preview)
 
 	r.Route("/{feedID}", func(r chi.Router) {
 		r.Put("/", h.update)
 		r.Delete("/", h.delete)
+		r.Get("/cache", h.getCache)
 		r.Delete("/cache", h.deleteCache)
 		r.Patch("/enabled", h.toggleEnabled)
 		r.Get("/latest", h.latestRun)
+		r.Post("/backfill", h.backfill)
+		r.Post("/caps", h.refreshCaps)
+		r.Get("/runs", h.getRuns)
 	})
 }
 
@@ -102,6 +114,26 @@ func (h feedHandler) test(w http.ResponseWriter, r *http.Request) {
 	h.encoder.NoContent(w)
 }
 
+func (h feedHandler) preview(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data *domain.Feed
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	preview, err := h.service.Preview(ctx, data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, preview)
+}
+
 func (h feedHandler) update(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
@@ -217,3 +249,106 @@ func (h feedHandler) latestRun(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(feed))
 }
+
+func (h feedHandler) backfill(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+		data   *domain.FeedBackfillSettings
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// the body is optional, without it the backfill settings of the feed are used
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.Backfill(ctx, id, data); err != nil {
+		if errors.Is(err, domain.ErrFeedBackfillRunning) {
+			h.encoder.StatusError(w, http.StatusConflict, err)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusAccepted, nil)
+}
+
+func (h feedHandler) getCache(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+		limit  = 100
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid limit"))
+			return
+		}
+	}
+
+	cache, err := h.service.GetCache(ctx, id, limit)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, cache)
+}
+
+func (h feedHandler) refreshCaps(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	caps, err := h.service.RefreshCaps(ctx, id)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, caps)
+}
+
+func (h feedHandler) getRuns(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	runs, err := h.service.GetRuns(ctx, id)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, runs)
+}
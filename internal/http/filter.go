@@ -27,23 +27,64 @@ type filterService interface {
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	ListFilterTags(ctx context.Context) ([]string, error)
+	ToggleEnabledByTag(ctx context.Context, tag string, enabled bool) (*domain.FilterBulkResult, error)
+	DeleteByTag(ctx context.Context, tag string) (*domain.FilterBulkResult, error)
+	ExportByTag(ctx context.Context, tag string) ([]domain.FilterExport, error)
+	DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterDryRunResult, error)
+	Simulate(ctx context.Context, filter *domain.Filter, limit int) (*domain.FilterSimulation, error)
+	RefreshListSources(ctx context.Context, filterID int) error
+	ImportPreset(ctx context.Context, data []byte, opts domain.FilterPresetImportOptions) (*domain.FilterPresetImportResult, error)
+	SyncPresets(ctx context.Context) ([]domain.FilterPresetImportResult, error)
+	ListRevisions(ctx context.Context, filterID int) ([]domain.FilterRevision, error)
+	FindRevision(ctx context.Context, filterID int, revision int) (*domain.FilterRevision, error)
+	Rollback(ctx context.Context, filterID int, revision int) (*domain.Filter, error)
+	GetStats(ctx context.Context, filterID int, bucketSize domain.FilterStatsBucketSize, days int) (*domain.FilterStats, error)
+	ListFlags() []domain.FilterFlag
+	ClearFlag(name string)
+	filterGroupService
+	filterTemplateService
+}
+
+type announceService interface {
+	TestAnnounce(ctx context.Context, req domain.AnnounceTestRequest) (*domain.AnnounceTestResult, error)
 }
 
 type filterHandler struct {
-	encoder encoder
-	service filterService
+	encoder  encoder
+	service  filterService
+	announce announceService
 }
 
-func newFilterHandler(encoder encoder, service filterService) *filterHandler {
+func newFilterHandler(encoder encoder, service filterService, announce announceService) *filterHandler {
 	return &filterHandler{
-		encoder: encoder,
-		service: service,
+		encoder:  encoder,
+		service:  service,
+		announce: announce,
 	}
 }
 
 func (h filterHandler) Routes(r chi.Router) {
 	r.Get("/", h.getFilters)
 	r.Post("/", h.store)
+	r.Post("/dry-run", h.dryRun)
+	r.Post("/dry-run/announce", h.dryRunAnnounce)
+	r.Post("/simulate", h.simulate)
+	r.Post("/regex", h.testRegex)
+	r.Post("/presets/import", h.importPreset)
+	r.Post("/presets/sync", h.syncPresets)
+
+	r.Route("/flags", func(r chi.Router) {
+		r.Get("/", h.listFlags)
+		r.Delete("/{flag}", h.clearFlag)
+	})
+
+	r.Route("/tags", func(r chi.Router) {
+		r.Get("/", h.listTags)
+		r.Put("/{tag}/enabled", h.toggleEnabledByTag)
+		r.Delete("/{tag}", h.deleteByTag)
+		r.Get("/{tag}/export", h.exportByTag)
+	})
 
 	r.Route("/{filterID}", func(r chi.Router) {
 		r.Get("/", h.getByID)
@@ -53,6 +94,13 @@ func (h filterHandler) Routes(r chi.Router) {
 
 		r.Get("/duplicate", h.duplicate)
 		r.Put("/enabled", h.toggleEnabled)
+		r.Post("/list_sources/refresh", h.refreshListSources)
+
+		r.Get("/stats", h.getStats)
+
+		r.Get("/revisions", h.listRevisions)
+		r.Get("/revisions/{revision}", h.getRevision)
+		r.Post("/revisions/{revision}/rollback", h.rollback)
 	})
 }
 
@@ -63,6 +111,7 @@ func (h filterHandler) getFilters(w http.ResponseWriter, r *http.Request) {
 		Sort: map[string]string{},
 		Filters: struct {
 			Indexers []string
+			Tags     []string
 		}{},
 		Search: "",
 	}
@@ -94,6 +143,7 @@ func (h filterHandler) getFilters(w http.ResponseWriter, r *http.Request) {
 	}
 	vals := u.Query()
 	params.Filters.Indexers = vals["indexer"]
+	params.Filters.Tags = vals["tag"]
 
 	trackers, err := h.service.Find(ctx, params)
 	if err != nil {
@@ -151,6 +201,180 @@ func (h filterHandler) duplicate(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(w, http.StatusOK, filter)
 }
 
+func (h filterHandler) refreshListSources(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.RefreshListSources(r.Context(), id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	filter, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, filter)
+}
+
+func (h filterHandler) getStats(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	days := 0
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid days: %s", v))
+			return
+		}
+	}
+
+	stats, err := h.service.GetStats(r.Context(), id, domain.FilterStatsBucketSize(r.URL.Query().Get("bucket")), days)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, stats)
+}
+
+func (h filterHandler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, revisions)
+}
+
+func (h filterHandler) getRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rev, err := h.service.FindRevision(r.Context(), id, revision)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, rev)
+}
+
+func (h filterHandler) rollback(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "filterID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := h.service.Rollback(r.Context(), id, revision)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, filter)
+}
+
+func (h filterHandler) importPreset(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		domain.FilterPresetImportOptions
+		// Preset is the preset json, either as object or as string
+		Preset json.RawMessage `json:"preset"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	preset := []byte(data.Preset)
+	if len(preset) > 0 && preset[0] == '"' {
+		var text string
+		if err := json.Unmarshal(preset, &text); err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		preset = []byte(text)
+	} else if string(preset) == "null" {
+		preset = nil
+	}
+
+	result, err := h.service.ImportPreset(r.Context(), preset, data.FilterPresetImportOptions)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+
+	h.encoder.StatusResponse(w, status, result)
+}
+
+func (h filterHandler) syncPresets(w http.ResponseWriter, r *http.Request) {
+	results, err := h.service.SyncPresets(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, results)
+}
+
 func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
@@ -170,6 +394,123 @@ func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusCreatedData(w, data)
 }
 
+func (h filterHandler) dryRun(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		Title   string `json:"title"`
+		Indexer string `json:"indexer"`
+		Size    string `json:"size"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.Title == "" {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("title required"))
+		return
+	}
+
+	release := domain.NewRelease(data.Indexer)
+	release.ParseString(data.Title)
+
+	if data.Size != "" {
+		release.ParseSizeBytesString(data.Size)
+	}
+
+	results, err := h.service.DryRun(r.Context(), release)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]any{
+		"release": release,
+		"results": results,
+	})
+}
+
+func (h filterHandler) dryRunAnnounce(w http.ResponseWriter, r *http.Request) {
+	var data domain.AnnounceTestRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.Indexer == "" || len(data.Lines) == 0 {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("indexer and lines required"))
+		return
+	}
+
+	result, err := h.announce.TestAnnounce(r.Context(), data)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusError(w, http.StatusNotFound, errors.New("indexer %s not found", data.Indexer))
+			return
+		}
+
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if result.Release != nil {
+		results, err := h.service.DryRun(r.Context(), result.Release)
+		if err != nil {
+			h.encoder.Error(w, err)
+			return
+		}
+
+		result.Results = results
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+func (h filterHandler) testRegex(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		Pattern string `json:"pattern"`
+		Sample  string `json:"sample"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.Pattern == "" {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("pattern required"))
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, domain.TestRegexList(data.Pattern, data.Sample))
+}
+
+func (h filterHandler) simulate(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid limit: %s", v))
+			return
+		}
+	}
+
+	var data *domain.Filter
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	simulation, err := h.service.Simulate(r.Context(), data, limit)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, simulation)
+}
+
 func (h filterHandler) update(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
@@ -245,6 +586,76 @@ func (h filterHandler) toggleEnabled(w http.ResponseWriter, r *http.Request) {
 	h.encoder.NoContent(w)
 }
 
+func (h filterHandler) listTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.ListFilterTags(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, tags)
+}
+
+// tagParam returns the unescaped tag of the url, tags may contain spaces
+func tagParam(r *http.Request) string {
+	tag := chi.URLParam(r, "tag")
+	if unescaped, err := url.PathUnescape(tag); err == nil {
+		return unescaped
+	}
+
+	return tag
+}
+
+func (h filterHandler) toggleEnabledByTag(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.ToggleEnabledByTag(r.Context(), tagParam(r), data.Enabled)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+func (h filterHandler) deleteByTag(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.DeleteByTag(r.Context(), tagParam(r))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+// listFlags returns the flags set by irc triggers that filters can require or reject
+func (h filterHandler) listFlags(w http.ResponseWriter, _ *http.Request) {
+	h.encoder.StatusResponse(w, http.StatusOK, h.service.ListFlags())
+}
+
+func (h filterHandler) clearFlag(w http.ResponseWriter, r *http.Request) {
+	h.service.ClearFlag(chi.URLParam(r, "flag"))
+
+	h.encoder.NoContent(w)
+}
+
+func (h filterHandler) exportByTag(w http.ResponseWriter, r *http.Request) {
+	exports, err := h.service.ExportByTag(r.Context(), tagParam(r))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, exports)
+}
+
 func (h filterHandler) delete(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx      = r.Context()
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type filterGroupService interface {
+	ListGroups(ctx context.Context) ([]domain.FilterGroup, error)
+	FindGroupByID(ctx context.Context, groupID int) (*domain.FilterGroup, error)
+	StoreGroup(ctx context.Context, group *domain.FilterGroup) error
+	UpdateGroup(ctx context.Context, group *domain.FilterGroup) error
+	DeleteGroup(ctx context.Context, groupID int) error
+}
+
+type filterGroupHandler struct {
+	encoder encoder
+	service filterGroupService
+}
+
+func newFilterGroupHandler(encoder encoder, service filterGroupService) *filterGroupHandler {
+	return &filterGroupHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h filterGroupHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+
+	r.Route("/{groupID}", func(r chi.Router) {
+		r.Get("/", h.getByID)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+}
+
+func (h filterGroupHandler) list(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.ListGroups(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, groups)
+}
+
+func (h filterGroupHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "groupID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	group, err := h.service.FindGroupByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, group)
+}
+
+func (h filterGroupHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data *domain.FilterGroup
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.StoreGroup(r.Context(), data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, data)
+}
+
+func (h filterGroupHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "groupID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var data *domain.FilterGroup
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.ID = id
+
+	if err := h.service.UpdateGroup(r.Context(), data); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, data)
+}
+
+func (h filterGroupHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "groupID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.DeleteGroup(r.Context(), id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
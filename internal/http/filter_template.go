@@ -0,0 +1,174 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type filterTemplateService interface {
+	ListTemplates(ctx context.Context) ([]domain.FilterTemplate, error)
+	FindTemplateByID(ctx context.Context, templateID int) (*domain.FilterTemplate, error)
+	StoreTemplate(ctx context.Context, template *domain.FilterTemplate) error
+	UpdateTemplate(ctx context.Context, template *domain.FilterTemplate) error
+	DeleteTemplate(ctx context.Context, templateID int) error
+	CreateFromTemplate(ctx context.Context, templateID int, instance domain.FilterTemplateInstance) (*domain.Filter, error)
+}
+
+type filterTemplateHandler struct {
+	encoder encoder
+	service filterTemplateService
+}
+
+func newFilterTemplateHandler(encoder encoder, service filterTemplateService) *filterTemplateHandler {
+	return &filterTemplateHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h filterTemplateHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+
+	r.Route("/{templateID}", func(r chi.Router) {
+		r.Get("/", h.getByID)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+
+		r.Post("/create", h.createFilter)
+	})
+}
+
+func (h filterTemplateHandler) list(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.ListTemplates(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, templates)
+}
+
+func (h filterTemplateHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	template, err := h.service.FindTemplateByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, template)
+}
+
+func (h filterTemplateHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data *domain.FilterTemplate
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.StoreTemplate(r.Context(), data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, data)
+}
+
+func (h filterTemplateHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var data *domain.FilterTemplate
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.ID = id
+
+	if err := h.service.UpdateTemplate(r.Context(), data); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, data)
+}
+
+func (h filterTemplateHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h filterTemplateHandler) createFilter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "templateID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var data domain.FilterTemplateInstance
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := h.service.CreateFromTemplate(r.Context(), id, data)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, filter)
+}
@@ -23,6 +23,7 @@ type indexerService interface {
 	Delete(ctx context.Context, id int) error
 	TestApi(ctx context.Context, req domain.IndexerTestApiRequest) error
 	ToggleEnabled(ctx context.Context, indexerID int, enabled bool) error
+	ReloadCustomDefinitions(ctx context.Context) ([]string, error)
 }
 
 type indexerHandler struct {
@@ -44,6 +45,7 @@ func (h indexerHandler) Routes(r chi.Router) {
 	r.Post("/", h.store)
 	r.Get("/", h.getAll)
 	r.Get("/options", h.list)
+	r.Post("/definitions/reload", h.reloadDefinitions)
 
 	r.Route("/{indexerID}", func(r chi.Router) {
 		r.Put("/", h.update)
@@ -54,6 +56,19 @@ func (h indexerHandler) Routes(r chi.Router) {
 	})
 }
 
+// reloadDefinitions reads the custom definitions again and rebinds the announce parsers of the running networks
+func (h indexerHandler) reloadDefinitions(w http.ResponseWriter, r *http.Request) {
+	reloaded, err := h.service.ReloadCustomDefinitions(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.ircSvc.ReloadDefinitions()
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string][]string{"reloaded": reloaded})
+}
+
 func (h indexerHandler) getSchema(w http.ResponseWriter, r *http.Request) {
 	indexers, err := h.service.GetTemplates()
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 
@@ -25,6 +26,19 @@ type ircService interface {
 	StoreChannel(ctx context.Context, networkID int64, channel *domain.IrcChannel) error
 	RestartNetwork(ctx context.Context, id int64) error
 	SendCmd(ctx context.Context, req *domain.SendIrcCmdRequest) error
+	SendRawCmd(ctx context.Context, req *domain.SendIrcRawCmdRequest) error
+	TestAnnounce(ctx context.Context, req domain.AnnounceTestRequest) (*domain.AnnounceTestResult, error)
+	FindLogs(ctx context.Context, params domain.IrcLogQueryParams) (*domain.IrcLogResponse, error)
+	ReplayAnnounces(ctx context.Context, req domain.IrcReplayRequest) (*domain.IrcReplayResult, error)
+	InjectAnnounce(ctx context.Context, req domain.IrcInjectRequest) (*domain.IrcInjectResult, error)
+	ListTriggers(ctx context.Context, networkID int64) ([]domain.IrcTrigger, error)
+	StoreTrigger(ctx context.Context, trigger *domain.IrcTrigger) error
+	UpdateTrigger(ctx context.Context, trigger *domain.IrcTrigger) error
+	DeleteTrigger(ctx context.Context, id int64) error
+	ListQuarantined(ctx context.Context, networkID int64) ([]domain.IrcQuarantinedAnnounce, error)
+	ReleaseQuarantined(ctx context.Context, networkID int64) (int, error)
+	DropQuarantined(ctx context.Context, networkID int64) (int, error)
+	ReloadDefinitions()
 }
 
 type ircHandler struct {
@@ -52,8 +66,22 @@ func (h ircHandler) Routes(r chi.Router) {
 		r.Delete("/", h.deleteNetwork)
 
 		r.Post("/cmd", h.sendCmd)
+		r.Post("/raw", h.sendRawCmd)
 		r.Post("/channel", h.storeChannel)
 		r.Get("/restart", h.restartNetwork)
+		r.Get("/logs", h.findLogs)
+		r.Post("/replay", h.replayAnnounces)
+		r.Post("/inject", h.injectAnnounce)
+		r.Get("/triggers", h.listTriggers)
+		r.Post("/triggers", h.storeTrigger)
+		r.Get("/quarantine", h.listQuarantined)
+		r.Post("/quarantine/release", h.releaseQuarantined)
+		r.Delete("/quarantine", h.dropQuarantined)
+	})
+
+	r.Route("/trigger/{triggerID}", func(r chi.Router) {
+		r.Put("/", h.updateTrigger)
+		r.Delete("/", h.deleteTrigger)
 	})
 
 	r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
@@ -99,6 +127,10 @@ func (h ircHandler) getNetworkByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the private key of the client certificate is never sent back
+	network.TLSKeySet = network.TLSKey != ""
+	network.TLSKey = ""
+
 	h.encoder.StatusResponse(w, http.StatusOK, network)
 }
 
@@ -185,6 +217,296 @@ func (h ircHandler) sendCmd(w http.ResponseWriter, r *http.Request) {
 	h.encoder.NoContent(w)
 }
 
+func (h ircHandler) sendRawCmd(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		networkID = chi.URLParam(r, "networkID")
+		data      domain.SendIrcRawCmdRequest
+	)
+
+	id, err := strconv.Atoi(networkID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	data.NetworkId = int64(id)
+
+	if err := h.service.SendRawCmd(ctx, &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h ircHandler) findLogs(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		networkID = chi.URLParam(r, "networkID")
+		query     = r.URL.Query()
+	)
+
+	id, err := strconv.Atoi(networkID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	params := domain.IrcLogQueryParams{
+		NetworkID: int64(id),
+		Channel:   query.Get("channel"),
+		Nick:      query.Get("nick"),
+		Search:    query.Get("q"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "limit parameter is invalid",
+			})
+			return
+		}
+		params.Limit = limit
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "offset parameter is invalid",
+			})
+			return
+		}
+		params.Offset = offset
+	}
+
+	if v := query.Get("before"); v != "" {
+		before, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "before parameter is invalid",
+			})
+			return
+		}
+		params.Before = before
+	}
+
+	if v := query.Get("after"); v != "" {
+		after, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "after parameter is invalid",
+			})
+			return
+		}
+		params.After = after
+	}
+
+	logs, err := h.service.FindLogs(ctx, params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, logs)
+}
+
+func (h ircHandler) replayAnnounces(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		networkID = chi.URLParam(r, "networkID")
+		data      domain.IrcReplayRequest
+	)
+
+	id, err := strconv.Atoi(networkID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.NetworkID = int64(id)
+
+	result, err := h.service.ReplayAnnounces(ctx, data)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+func (h ircHandler) injectAnnounce(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		networkID = chi.URLParam(r, "networkID")
+		data      domain.IrcInjectRequest
+	)
+
+	id, err := strconv.Atoi(networkID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.NetworkID = int64(id)
+
+	result, err := h.service.InjectAnnounce(ctx, data)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+func (h ircHandler) listQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "networkID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	announces, err := h.service.ListQuarantined(r.Context(), int64(id))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusNotFound, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, announces)
+}
+
+func (h ircHandler) releaseQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "networkID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	released, err := h.service.ReleaseQuarantined(r.Context(), int64(id))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusNotFound, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]int{"released": released})
+}
+
+func (h ircHandler) dropQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "networkID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	dropped, err := h.service.DropQuarantined(r.Context(), int64(id))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusNotFound, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]int{"dropped": dropped})
+}
+
+func (h ircHandler) listTriggers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "networkID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	triggers, err := h.service.ListTriggers(r.Context(), int64(id))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, triggers)
+}
+
+func (h ircHandler) storeTrigger(w http.ResponseWriter, r *http.Request) {
+	var data domain.IrcTrigger
+
+	id, err := strconv.Atoi(chi.URLParam(r, "networkID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.NetworkID = int64(id)
+
+	if err := h.service.StoreTrigger(r.Context(), &data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, data)
+}
+
+func (h ircHandler) updateTrigger(w http.ResponseWriter, r *http.Request) {
+	var data domain.IrcTrigger
+
+	id, err := strconv.Atoi(chi.URLParam(r, "triggerID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data.ID = int64(id)
+
+	if err := h.service.UpdateTrigger(r.Context(), &data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, data)
+}
+
+func (h ircHandler) deleteTrigger(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "triggerID"))
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteTrigger(r.Context(), int64(id)); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
 func (h ircHandler) storeChannel(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx       = r.Context()
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type metricsIrcService interface {
+	GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error)
+}
+
+type metricsFeedService interface {
+	Find(ctx context.Context) ([]domain.Feed, error)
+}
+
+// metricsHandler exposes the health of the irc networks and the runs of the feeds in the Prometheus text format
+type metricsHandler struct {
+	ircService  metricsIrcService
+	feedService metricsFeedService
+}
+
+func newMetricsHandler(ircService metricsIrcService, feedService metricsFeedService) *metricsHandler {
+	return &metricsHandler{
+		ircService:  ircService,
+		feedService: feedService,
+	}
+}
+
+func (h metricsHandler) Routes(r chi.Router) {
+	r.Get("/", h.metrics)
+}
+
+func (h metricsHandler) metrics(w http.ResponseWriter, r *http.Request) {
+	networks, err := h.ircService.GetNetworksWithHealth(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	feeds, err := h.feedService.Find(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	writeIrcMetrics(w, networks, time.Now())
+	writeFeedMetrics(w, feeds)
+}
+
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	values []metricValue
+}
+
+type metricValue struct {
+	labels [][2]string
+	value  float64
+}
+
+// writeIrcMetrics writes the lag, uptime and announce rate of the networks and their channels
+func writeIrcMetrics(w io.Writer, networks []domain.IrcNetworkWithHealth, now time.Time) {
+	connected := metric{name: "autobrr_irc_network_connected", help: "Whether the irc network is connected.", kind: "gauge"}
+	healthy := metric{name: "autobrr_irc_network_healthy", help: "Whether the irc network is connected and monitors its channels.", kind: "gauge"}
+	uptime := metric{name: "autobrr_irc_network_uptime_seconds", help: "Seconds since the irc network connected.", kind: "gauge"}
+	lag := metric{name: "autobrr_irc_network_lag_seconds", help: "Last measured round trip to the irc server.", kind: "gauge"}
+	monitoring := metric{name: "autobrr_irc_channel_monitoring", help: "Whether the channel is joined and monitored.", kind: "gauge"}
+	lastHour := metric{name: "autobrr_irc_channel_announces_last_hour", help: "Announces in the channel during the last hour.", kind: "gauge"}
+	total := metric{name: "autobrr_irc_channel_announces_total", help: "Announces in the channel since start.", kind: "counter"}
+	lastAnnounce := metric{name: "autobrr_irc_channel_last_announce_timestamp_seconds", help: "Unix time of the last announce in the channel.", kind: "gauge"}
+
+	for _, n := range networks {
+		if !n.Enabled {
+			continue
+		}
+
+		labels := [][2]string{{"network", n.Name}, {"server", n.Server}}
+
+		connected.add(labels, boolMetric(n.Connected))
+		healthy.add(labels, boolMetric(n.Healthy))
+		lag.add(labels, float64(n.LagMs)/1000)
+
+		if n.Connected {
+			uptime.add(labels, now.Sub(n.ConnectedSince).Seconds())
+		} else {
+			uptime.add(labels, 0)
+		}
+
+		for _, ch := range n.Channels {
+			channelLabels := append(labels[:len(labels):len(labels)], [2]string{"channel", ch.Name})
+
+			monitoring.add(channelLabels, boolMetric(ch.Monitoring))
+			lastHour.add(channelLabels, float64(ch.AnnouncesLastHour))
+			total.add(channelLabels, float64(ch.AnnouncesTotal))
+
+			if !ch.LastAnnounce.IsZero() {
+				lastAnnounce.add(channelLabels, float64(ch.LastAnnounce.Unix()))
+			}
+		}
+	}
+
+	for _, m := range []metric{connected, healthy, uptime, lag, monitoring, lastHour, total, lastAnnounce} {
+		m.write(w)
+	}
+}
+
+// writeFeedMetrics writes the last run of the feeds and the totals of their runs since start
+func writeFeedMetrics(w io.Writer, feeds []domain.Feed) {
+	duration := metric{name: "autobrr_feed_last_run_duration_seconds", help: "Duration of the last refresh of the feed.", kind: "gauge"}
+	items := metric{name: "autobrr_feed_last_run_items", help: "Items in the feed on the last refresh.", kind: "gauge"}
+	newItems := metric{name: "autobrr_feed_last_run_new_items", help: "Items not seen before on the last refresh.", kind: "gauge"}
+	matched := metric{name: "autobrr_feed_last_run_matched", help: "Releases of the last refresh approved by a filter.", kind: "gauge"}
+	status := metric{name: "autobrr_feed_last_run_http_status", help: "HTTP status of the last response of the feed, 0 when it did not answer.", kind: "gauge"}
+	lastRun := metric{name: "autobrr_feed_last_run_timestamp_seconds", help: "Unix time of the last refresh of the feed.", kind: "gauge"}
+	runs := metric{name: "autobrr_feed_runs_total", help: "Refreshes of the feed since start.", kind: "counter"}
+	runErrors := metric{name: "autobrr_feed_run_errors_total", help: "Failed refreshes of the feed since start.", kind: "counter"}
+	newTotal := metric{name: "autobrr_feed_new_items_total", help: "Items not seen before since start.", kind: "counter"}
+	matchedTotal := metric{name: "autobrr_feed_matched_total", help: "Releases approved by a filter since start.", kind: "counter"}
+
+	for _, f := range feeds {
+		if !f.Enabled || f.Runs == nil || f.Runs.LastRun == nil {
+			continue
+		}
+
+		labels := [][2]string{{"feed", f.Name}, {"indexer", f.Indexer}, {"type", f.Type}}
+		last := f.Runs.LastRun
+
+		duration.add(labels, float64(last.DurationMs)/1000)
+		items.add(labels, float64(last.Items))
+		newItems.add(labels, float64(last.NewItems))
+		matched.add(labels, float64(last.Matched))
+		status.add(labels, float64(last.HTTPStatus))
+		lastRun.add(labels, float64(last.StartedAt.Unix()))
+		runs.add(labels, float64(f.Runs.Runs))
+		runErrors.add(labels, float64(f.Runs.Errors))
+		newTotal.add(labels, float64(f.Runs.NewItems))
+		matchedTotal.add(labels, float64(f.Runs.Matched))
+	}
+
+	for _, m := range []metric{duration, items, newItems, matched, status, lastRun, runs, runErrors, newTotal, matchedTotal} {
+		m.write(w)
+	}
+}
+
+func (m *metric) add(labels [][2]string, value float64) {
+	m.values = append(m.values, metricValue{labels: labels, value: value})
+}
+
+func (m *metric) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+
+	for _, v := range m.values {
+		pairs := make([]string, 0, len(v.labels))
+		for _, l := range v.labels {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", l[0], escapeLabelValue(l[1])))
+		}
+
+		fmt.Fprintf(w, "%s{%s} %g\n", m.name, strings.Join(pairs, ","), v.value)
+	}
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
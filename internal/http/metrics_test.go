@@ -0,0 +1,109 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+func TestWriteIrcMetrics(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	networks := []domain.IrcNetworkWithHealth{
+		{
+			Name:           "Tracker \"One\"",
+			Enabled:        true,
+			Server:         "irc.tracker.org",
+			Connected:      true,
+			ConnectedSince: now.Add(-90 * time.Second),
+			Healthy:        true,
+			LagMs:          250,
+			Channels: []domain.ChannelWithHealth{
+				{Name: "#announce", Monitoring: true, AnnouncesLastHour: 12, AnnouncesTotal: 40, LastAnnounce: now.Add(-time.Minute)},
+			},
+		},
+		{Name: "disabled", Enabled: false, Server: "irc.other.org"},
+	}
+
+	var buf bytes.Buffer
+	writeIrcMetrics(&buf, networks, now)
+	out := buf.String()
+
+	expected := []string{
+		"# TYPE autobrr_irc_network_connected gauge",
+		`autobrr_irc_network_connected{network="Tracker \"One\"",server="irc.tracker.org"} 1`,
+		`autobrr_irc_network_uptime_seconds{network="Tracker \"One\"",server="irc.tracker.org"} 90`,
+		`autobrr_irc_network_lag_seconds{network="Tracker \"One\"",server="irc.tracker.org"} 0.25`,
+		`autobrr_irc_channel_announces_last_hour{network="Tracker \"One\"",server="irc.tracker.org",channel="#announce"} 12`,
+		"# TYPE autobrr_irc_channel_announces_total counter",
+		`autobrr_irc_channel_announces_total{network="Tracker \"One\"",server="irc.tracker.org",channel="#announce"} 40`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, out)
+		}
+	}
+
+	if strings.Contains(out, "irc.other.org") {
+		t.Errorf("disabled network should not be exported:\n%s", out)
+	}
+}
+
+func TestWriteFeedMetrics(t *testing.T) {
+	started := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	feeds := []domain.Feed{
+		{
+			Name:    "Tracker RSS",
+			Indexer: "tracker",
+			Type:    string(domain.FeedTypeRSS),
+			Enabled: true,
+			Runs: &domain.FeedRunStats{
+				LastRun:  &domain.FeedRun{FeedID: 1, StartedAt: started, DurationMs: 1250, Items: 50, NewItems: 4, Matched: 1, HTTPStatus: 200},
+				Runs:     12,
+				Errors:   2,
+				NewItems: 40,
+				Matched:  5,
+			},
+		},
+		{Name: "not run yet", Indexer: "other", Type: string(domain.FeedTypeTorznab), Enabled: true},
+		{Name: "disabled", Indexer: "disabled", Type: string(domain.FeedTypeRSS), Runs: &domain.FeedRunStats{LastRun: &domain.FeedRun{}}},
+	}
+
+	var buf bytes.Buffer
+	writeFeedMetrics(&buf, feeds)
+	out := buf.String()
+
+	labels := `{feed="Tracker RSS",indexer="tracker",type="RSS"}`
+	expected := []string{
+		"# TYPE autobrr_feed_last_run_duration_seconds gauge",
+		"autobrr_feed_last_run_duration_seconds" + labels + " 1.25",
+		"autobrr_feed_last_run_items" + labels + " 50",
+		"autobrr_feed_last_run_new_items" + labels + " 4",
+		"autobrr_feed_last_run_matched" + labels + " 1",
+		"autobrr_feed_last_run_http_status" + labels + " 200",
+		"autobrr_feed_last_run_timestamp_seconds" + labels + " 1.6856208e+09",
+		"# TYPE autobrr_feed_runs_total counter",
+		"autobrr_feed_runs_total" + labels + " 12",
+		"autobrr_feed_run_errors_total" + labels + " 2",
+		"autobrr_feed_new_items_total" + labels + " 40",
+		"autobrr_feed_matched_total" + labels + " 5",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, out)
+		}
+	}
+
+	for _, name := range []string{"not run yet", "disabled"} {
+		if strings.Contains(out, name) {
+			t.Errorf("feed %q should not be exported:\n%s", name, out)
+		}
+	}
+}
@@ -4,30 +4,45 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/rs/zerolog"
 )
 
 func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// username is recorded with changes like filter revisions
+		username := "api"
+
+		// logged in users are admins, api keys only with the admin scope
+		admin := true
+
 		if token := r.Header.Get("X-API-Token"); token != "" {
 			// check header
-			if !s.apiService.ValidateAPIKey(r.Context(), token) {
+			key, err := s.apiService.FindAPIKey(r.Context(), token)
+			if err != nil {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
 
-		} else if key := r.URL.Query().Get("apikey"); key != "" {
+			admin = key.HasScope(domain.APIKeyScopeAdmin)
+
+		} else if token := r.URL.Query().Get("apikey"); token != "" {
 			// check query param lke ?apikey=TOKEN
-			if !s.apiService.ValidateAPIKey(r.Context(), key) {
+			key, err := s.apiService.FindAPIKey(r.Context(), token)
+			if err != nil {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
+
+			admin = key.HasScope(domain.APIKeyScopeAdmin)
 		} else {
 			// check session
 			session, _ := s.cookieStore.Get(r, "user_session")
@@ -37,6 +52,26 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
+
+			// sessions created before the username was stored do not have it
+			username, _ = session.Values["username"].(string)
+		}
+
+		ctx := domain.ContextWithUser(r.Context(), username)
+		ctx = context.WithValue(ctx, adminContextKey{}, admin)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type adminContextKey struct{}
+
+// RequireAdmin only lets requests through that IsAuthenticated marked as admin
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if admin, _ := r.Context().Value(adminContextKey{}).(bool); !admin {
+			http.Error(w, "api key requires the admin scope", http.StatusForbidden)
+			return
 		}
 
 		next.ServeHTTP(w, r)
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAPIKeyService struct {
+	keys []domain.APIKey
+}
+
+func (m *mockAPIKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	return m.keys, nil
+}
+
+func (m *mockAPIKeyService) Store(ctx context.Context, key *domain.APIKey) error {
+	return nil
+}
+
+func (m *mockAPIKeyService) Update(ctx context.Context, key *domain.APIKey) error {
+	return nil
+}
+
+func (m *mockAPIKeyService) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (m *mockAPIKeyService) ValidateAPIKey(ctx context.Context, token string) bool {
+	_, err := m.FindAPIKey(ctx, token)
+	return err == nil
+}
+
+func (m *mockAPIKeyService) FindAPIKey(ctx context.Context, token string) (*domain.APIKey, error) {
+	for _, k := range m.keys {
+		if k.Key == token {
+			return &k, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func TestRequireAdmin(t *testing.T) {
+	s := Server{
+		cookieStore: sessions.NewCookieStore([]byte("secret")),
+		apiService: &mockAPIKeyService{keys: []domain.APIKey{
+			{Name: "sonarr", Key: "plain", Scopes: []string{}},
+			{Name: "ops", Key: "admin", Scopes: []string{domain.APIKeyScopeAdmin}},
+		}},
+	}
+
+	handler := s.IsAuthenticated(RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		want   int
+	}{
+		{name: "admin_header", header: "admin", want: http.StatusNoContent},
+		{name: "admin_query", query: "admin", want: http.StatusNoContent},
+		{name: "plain_header", header: "plain", want: http.StatusForbidden},
+		{name: "plain_query", query: "plain", want: http.StatusForbidden},
+		{name: "unknown_key", header: "unknown", want: http.StatusUnauthorized},
+		{name: "no_session", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/api/users/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("X-API-Token", tt.header)
+			}
+			if tt.query != "" {
+				req.URL.RawQuery = "apikey=" + tt.query
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
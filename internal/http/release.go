@@ -20,7 +20,8 @@ type releaseService interface {
 	FindRecent(ctx context.Context) (res []*domain.Release, err error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
-	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error
+	StatsBreakdown(ctx context.Context) (*domain.ReleaseStatsBreakdown, error)
+	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error)
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
 }
 
@@ -40,6 +41,7 @@ func (h releaseHandler) Routes(r chi.Router) {
 	r.Get("/", h.findReleases)
 	r.Get("/recent", h.findRecentReleases)
 	r.Get("/stats", h.getStats)
+	r.Get("/stats/breakdown", h.getStatsBreakdown)
 	r.Get("/indexers", h.getIndexerOptions)
 	r.Delete("/", h.deleteReleases)
 
@@ -191,6 +193,16 @@ func (h releaseHandler) getStats(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(w, http.StatusOK, stats)
 }
 
+func (h releaseHandler) getStatsBreakdown(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.StatsBreakdown(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, stats)
+}
+
 func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 	req := domain.DeleteReleaseRequest{}
 
@@ -207,12 +219,27 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 		req.OlderThan = duration
 	}
 
-	if err := h.service.Delete(r.Context(), &req); err != nil {
+	req.Indexers = r.URL.Query()["indexer"]
+
+	for _, status := range r.URL.Query()["push_status"] {
+		if !domain.ValidReleasePushStatus(status) {
+			h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": fmt.Sprintf("push_status parameter is of invalid type: %v", status),
+			})
+			return
+		}
+
+		req.ReleaseStatuses = append(req.ReleaseStatuses, domain.ReleasePushStatus(status))
+	}
+
+	deleted, err := h.service.Delete(r.Context(), &req)
+	if err != nil {
 		h.encoder.Error(w, err)
 		return
 	}
 
-	h.encoder.NoContent(w)
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]int64{"deleted": deleted})
 }
 
 func (h releaseHandler) retryAction(w http.ResponseWriter, r *http.Request) {
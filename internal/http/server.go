@@ -120,16 +120,21 @@ func (s Server) Handler() http.Handler {
 
 			r.Route("/actions", newActionHandler(encoder, s.actionService).Routes)
 			r.Route("/config", newConfigHandler(encoder, s, s.config).Routes)
+			r.Route("/database", newDatabaseHandler(encoder, s.db).Routes)
 			r.Route("/download_clients", newDownloadClientHandler(encoder, s.downloadClientService).Routes)
-			r.Route("/filters", newFilterHandler(encoder, s.filterService).Routes)
+			r.Route("/filters", newFilterHandler(encoder, s.filterService, s.ircService).Routes)
+			r.Route("/filter_groups", newFilterGroupHandler(encoder, s.filterService).Routes)
+			r.Route("/filter_templates", newFilterTemplateHandler(encoder, s.filterService).Routes)
 			r.Route("/feeds", newFeedHandler(encoder, s.feedService).Routes)
 			r.Route("/irc", newIrcHandler(encoder, s.sse, s.ircService).Routes)
 			r.Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService).Routes)
-			r.Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
+			r.With(RequireAdmin).Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
 			r.Route("/logs", newLogsHandler(s.config).Routes)
+			r.Route("/metrics", newMetricsHandler(s.ircService, s.feedService).Routes)
 			r.Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
 			r.Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
 			r.Route("/updates", newUpdateHandler(encoder, s.updateService).Routes)
+			r.Route("/users", newUserHandler(encoder, s.authService).Routes)
 
 			r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
 
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type userHandler struct {
+	encoder encoder
+	service authService
+}
+
+func newUserHandler(encoder encoder, service authService) *userHandler {
+	return &userHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h userHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+
+	r.Group(func(r chi.Router) {
+		r.Use(RequireAdmin)
+
+		r.Post("/", h.store)
+
+		r.Route("/{username}", func(r chi.Router) {
+			r.Put("/password", h.updatePassword)
+			r.Delete("/", h.delete)
+		})
+	})
+}
+
+func (h userHandler) list(w http.ResponseWriter, r *http.Request) {
+	usernames, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, usernames)
+}
+
+func (h userHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data domain.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.CreateUser(r.Context(), data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusCreated(w)
+}
+
+func (h userHandler) updatePassword(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.UpdatePassword(r.Context(), chi.URLParam(r, "username"), data.Password); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h userHandler) delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteUser(r.Context(), chi.URLParam(r, "username")); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		if errors.Is(err, domain.ErrLastUser) {
+			h.encoder.StatusError(w, http.StatusConflict, err)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
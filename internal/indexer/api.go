@@ -5,6 +5,7 @@ package indexer
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -22,6 +23,8 @@ import (
 type APIService interface {
 	TestConnection(ctx context.Context, req domain.IndexerTestApiRequest) (bool, error)
 	GetTorrentByID(ctx context.Context, indexer string, torrentID string) (*domain.TorrentBasic, error)
+	GetFreeleechTokens(ctx context.Context, indexer string) (int, error)
+	GetTorrentPeers(ctx context.Context, indexer string, torrentID string) (int, int, error)
 	AddClient(indexer string, settings map[string]string) error
 	RemoveClient(indexer string) error
 }
@@ -31,6 +34,15 @@ type apiClient interface {
 	TestAPI(ctx context.Context) (bool, error)
 }
 
+// freeleechTokenClient is implemented by the api clients of indexers that report the freeleech tokens of the user
+type freeleechTokenClient interface {
+	GetFreeleechTokens(ctx context.Context) (int, error)
+}
+
+var ErrFreeleechTokensNotSupported = errors.New("freeleech tokens not supported by indexer api")
+
+var ErrPeersNotSupported = errors.New("seeders and leechers not supported by indexer api")
+
 type apiService struct {
 	log        zerolog.Logger
 	apiClients map[string]apiClient
@@ -67,6 +79,61 @@ func (s *apiService) GetTorrentByID(ctx context.Context, indexer string, torrent
 	return torrent, nil
 }
 
+// GetFreeleechTokens returns the number of freeleech tokens the user has left on the indexer
+func (s *apiService) GetFreeleechTokens(ctx context.Context, indexer string) (int, error) {
+	// indexers without an api client can not report tokens either
+	client, err := s.getApiClient(indexer)
+	if err != nil {
+		return 0, ErrFreeleechTokensNotSupported
+	}
+
+	tokenClient, ok := client.(freeleechTokenClient)
+	if !ok {
+		return 0, ErrFreeleechTokensNotSupported
+	}
+
+	tokens, err := tokenClient.GetFreeleechTokens(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get freeleech tokens from: %s", indexer)
+	}
+
+	s.log.Trace().Str("method", "GetFreeleechTokens").Msgf("%s api freeleech tokens: %d", indexer, tokens)
+
+	return tokens, nil
+}
+
+// GetTorrentPeers returns the current seeders and leechers of the torrent on the indexer
+func (s *apiService) GetTorrentPeers(ctx context.Context, indexer string, torrentID string) (int, int, error) {
+	// indexers without an api client can not report peers either
+	client, err := s.getApiClient(indexer)
+	if err != nil {
+		return 0, 0, ErrPeersNotSupported
+	}
+
+	torrent, err := client.GetTorrentByID(ctx, torrentID)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "could not get torrent: %s from: %s", torrentID, indexer)
+	}
+
+	if torrent == nil || torrent.Seeders == "" {
+		return 0, 0, ErrPeersNotSupported
+	}
+
+	seeders, err := strconv.Atoi(torrent.Seeders)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "could not parse seeders: %q from: %s", torrent.Seeders, indexer)
+	}
+
+	leechers, err := strconv.Atoi(torrent.Leechers)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "could not parse leechers: %q from: %s", torrent.Leechers, indexer)
+	}
+
+	s.log.Trace().Str("method", "GetTorrentPeers").Msgf("%s api torrent: %s seeders: %d leechers: %d", indexer, torrentID, seeders, leechers)
+
+	return seeders, leechers, nil
+}
+
 func (s *apiService) TestConnection(ctx context.Context, req domain.IndexerTestApiRequest) (bool, error) {
 	client, err := s.getClientForTest(req)
 	if err != nil {
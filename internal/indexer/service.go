@@ -32,6 +32,7 @@ type Service interface {
 	GetAll() ([]*domain.IndexerDefinition, error)
 	GetTemplates() ([]domain.IndexerDefinition, error)
 	LoadIndexerDefinitions() error
+	ReloadCustomDefinitions(ctx context.Context) ([]string, error)
 	GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition
 	GetTorznabIndexers() []domain.IndexerDefinition
 	Start() error
@@ -342,7 +343,7 @@ func (s *service) Start() error {
 			}
 
 		// handle feeds
-		case string(domain.IndexerImplementationRSS):
+		case string(domain.IndexerImplementationRSS), string(domain.IndexerImplementationJSON), string(domain.IndexerImplementationPush):
 			s.rssIndexers[indexer.Identifier] = indexer
 
 		case string(domain.IndexerImplementationTorznab):
@@ -361,7 +362,7 @@ func (s *service) Start() error {
 func (s *service) removeIndexer(indexer domain.Indexer) {
 	// handle feeds
 	switch indexer.Implementation {
-	case string(domain.IndexerImplementationRSS):
+	case string(domain.IndexerImplementationRSS), string(domain.IndexerImplementationJSON), string(domain.IndexerImplementationPush):
 		delete(s.rssIndexers, indexer.Identifier)
 
 	case string(domain.IndexerImplementationTorznab):
@@ -398,7 +399,7 @@ func (s *service) addIndexer(indexer domain.Indexer) error {
 		}
 
 	// handle feeds
-	case string(domain.IndexerImplementationRSS):
+	case string(domain.IndexerImplementationRSS), string(domain.IndexerImplementationJSON), string(domain.IndexerImplementationPush):
 		s.rssIndexers[indexer.Identifier] = indexerDefinition
 
 	case string(domain.IndexerImplementationTorznab):
@@ -436,7 +437,7 @@ func (s *service) updateIndexer(indexer domain.Indexer) error {
 		}
 
 	// handle feeds
-	case string(domain.IndexerImplementationRSS):
+	case string(domain.IndexerImplementationRSS), string(domain.IndexerImplementationJSON), string(domain.IndexerImplementationPush):
 		s.rssIndexers[indexer.Identifier] = indexerDefinition
 
 	case string(domain.IndexerImplementationTorznab):
@@ -514,25 +515,31 @@ func (s *service) LoadIndexerDefinitions() error {
 
 // LoadCustomIndexerDefinitions load definitions from custom path
 func (s *service) LoadCustomIndexerDefinitions() error {
+	_, err := s.loadCustomDefinitions()
+	return err
+}
+
+// loadCustomDefinitions loads the definitions from the custom path and returns their identifiers
+func (s *service) loadCustomDefinitions() ([]string, error) {
 	if s.config.CustomDefinitions == "" {
-		return nil
+		return nil, nil
 	}
 
 	outputDirRead, err := os.Open(s.config.CustomDefinitions)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("failed opening custom definitions directory %s", s.config.CustomDefinitions)
-		return nil
+		return nil, nil
 	}
 
 	defer outputDirRead.Close()
 
 	entries, err := outputDirRead.ReadDir(0)
 	if err != nil {
-		s.log.Fatal().Err(err).Stack().Msg("failed reading directory")
-		return errors.Wrap(err, "could not read directory")
+		s.log.Error().Err(err).Stack().Msg("failed reading directory")
+		return nil, errors.Wrap(err, "could not read directory")
 	}
 
-	customCount := 0
+	var identifiers []string
 
 	for _, f := range entries {
 		fileExtension := filepath.Ext(f.Name())
@@ -548,13 +555,13 @@ func (s *service) LoadCustomIndexerDefinitions() error {
 		data, err := os.ReadFile(file)
 		if err != nil {
 			s.log.Error().Stack().Err(err).Msgf("failed reading file: %s", file)
-			return errors.Wrap(err, "could not read file: %s", file)
+			return nil, errors.Wrap(err, "could not read file: %s", file)
 		}
 
 		var d *domain.IndexerDefinitionCustom
 		if err = yaml.Unmarshal(data, &d); err != nil {
 			s.log.Error().Stack().Err(err).Msgf("failed unmarshal file: %s", file)
-			return errors.Wrap(err, "could not unmarshal file: %s", file)
+			return nil, errors.Wrap(err, "could not unmarshal file: %s", file)
 		}
 
 		if d == nil {
@@ -573,12 +580,57 @@ func (s *service) LoadCustomIndexerDefinitions() error {
 
 		s.definitions[d.Identifier] = *d.ToIndexerDefinition()
 
-		customCount++
+		identifiers = append(identifiers, d.Identifier)
 	}
 
-	s.log.Debug().Msgf("Loaded %d custom indexer definitions", customCount)
+	s.log.Debug().Msgf("Loaded %d custom indexer definitions", len(identifiers))
 
-	return nil
+	return identifiers, nil
+}
+
+// ReloadCustomDefinitions reads the custom definitions again and remaps the indexers using them,
+// so changed announce patterns can be applied without a restart. It returns the reloaded identifiers.
+func (s *service) ReloadCustomDefinitions(ctx context.Context) ([]string, error) {
+	if s.config.CustomDefinitions == "" {
+		return nil, errors.New("no custom definitions directory configured")
+	}
+
+	identifiers, err := s.loadCustomDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identifier := range identifiers {
+		mapped, ok := s.mappedDefinitions[identifier]
+		if !ok {
+			continue
+		}
+
+		indexer, err := s.repo.FindByID(ctx, mapped.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find indexer: %s", identifier)
+		}
+
+		definition, err := s.mapIndexer(*indexer)
+		if err != nil || definition == nil {
+			return nil, errors.New("could not map indexer: %s", identifier)
+		}
+
+		// the server of the definition can change, so drop it from the old one
+		for _, definitions := range s.lookupIRCServerDefinition {
+			delete(definitions, identifier)
+		}
+
+		if definition.Implementation == string(domain.IndexerImplementationIRC) {
+			s.mapIRCServerDefinitionLookup(definition.IRC.Server, definition)
+		}
+
+		s.mappedDefinitions[identifier] = definition
+	}
+
+	s.log.Info().Msgf("reloaded %d custom indexer definitions", len(identifiers))
+
+	return identifiers, nil
 }
 
 func (s *service) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition {
@@ -709,7 +761,7 @@ func (s *service) ToggleEnabled(ctx context.Context, indexerID int, enabled bool
 
 func isImplFeed(implementation string) bool {
 	switch implementation {
-	case "torznab", "newznab", "rss":
+	case "torznab", "newznab", "rss", "json", "push":
 		return true
 	default:
 		return false
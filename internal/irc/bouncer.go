@@ -0,0 +1,82 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// bouncerCaps are requested when connecting through a bouncer like ZNC so replayed buffer lines
+// carry the time they were originally sent and the buffer can be requested with *playback
+var bouncerCaps = []string{"server-time", "znc.in/server-time-iso", "batch", "znc.in/playback"}
+
+// bouncerReplayMaxAge is how old a replayed announce can be and still be processed
+const bouncerReplayMaxAge = 30 * time.Minute
+
+// messageTime returns the time of the server-time tag of the message
+func messageTime(msg ircmsg.Message) (time.Time, bool) {
+	ok, value := msg.GetTag("time")
+	if !ok || value == "" {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// skipReplayed reports whether an announce with a server-time tag was already processed or is too old.
+// Bouncers replay their buffer on reconnect, so announces at or before the last seen one of the channel
+// are duplicates.
+func (h *Handler) skipReplayed(channel string, msgTime time.Time) bool {
+	channel = strings.ToLower(channel)
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if lastSeen, ok := h.lastMessageTime[channel]; ok && !msgTime.After(lastSeen) {
+		return true
+	}
+
+	if time.Since(msgTime) > bouncerReplayMaxAge {
+		return true
+	}
+
+	h.lastMessageTime[channel] = msgTime
+
+	return false
+}
+
+// requestPlayback asks the ZNC playback module for the buffer of the channel since the last seen announce.
+// Without the znc.in/playback cap ZNC replays the whole buffer by itself which skipReplayed deduplicates.
+func (h *Handler) requestPlayback(channel string) {
+	if !h.network.UseBouncer {
+		return
+	}
+
+	if _, ok := h.client.AcknowledgedCaps()["znc.in/playback"]; !ok {
+		return
+	}
+
+	h.m.RLock()
+	since, ok := h.lastMessageTime[strings.ToLower(channel)]
+	h.m.RUnlock()
+
+	if !ok {
+		since = time.Now().Add(-bouncerReplayMaxAge)
+	}
+
+	h.log.Debug().Msgf("requesting playback for %s since %s", channel, since.Format(time.RFC3339))
+
+	if err := h.client.Privmsg("*playback", fmt.Sprintf("PLAY %s %d", channel, since.Unix())); err != nil {
+		h.log.Error().Err(err).Msgf("error requesting playback for %s", channel)
+	}
+}
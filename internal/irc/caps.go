@@ -0,0 +1,39 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// ircv3Caps are requested from every server. With server-time announces carry the time the server received them,
+// which is used as release timestamp instead of the time autobrr read the line.
+var ircv3Caps = []string{"server-time", "message-tags"}
+
+// requestedCaps returns the capabilities to request for the network
+func requestedCaps(network *domain.IrcNetwork) []string {
+	caps := append([]string{}, ircv3Caps...)
+
+	if !network.UseBouncer {
+		return caps
+	}
+
+	for _, c := range bouncerCaps {
+		if !containsCap(caps, c) {
+			caps = append(caps, c)
+		}
+	}
+
+	return caps
+}
+
+func containsCap(caps []string, c string) bool {
+	for _, v := range caps {
+		if v == c {
+			return true
+		}
+	}
+
+	return false
+}
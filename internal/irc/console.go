@@ -0,0 +1,95 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// consoleChannel is the SSE stream of the raw command console of a network.
+// Nicks and channels can not start with * so it never collides with a channel stream.
+const consoleChannel = "*console"
+
+// consoleReplies are the numerics shown in the console, mostly errors of commands sent from it
+var consoleReplies = []string{
+	"401", // ERR_NOSUCHNICK
+	"403", // ERR_NOSUCHCHANNEL
+	"404", // ERR_CANNOTSENDTOCHAN
+	"421", // ERR_UNKNOWNCOMMAND
+	"442", // ERR_NOTONCHANNEL
+	"461", // ERR_NEEDMOREPARAMS
+	"471", // ERR_CHANNELISFULL
+	"473", // ERR_INVITEONLYCHAN
+	"474", // ERR_BANNEDFROMCHAN
+	"475", // ERR_BADCHANNELKEY
+	"477", // ERR_NEEDREGGEDNICK
+	"482", // ERR_CHANOPRIVSNEEDED
+}
+
+// SendRaw sends a raw command like "PRIVMSG NickServ :HELP" to the server. Replies are published to the console stream.
+func (h *Handler) SendRaw(cmd string) error {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return errors.New("empty command")
+	}
+
+	if strings.ContainsAny(cmd, "\r\n") {
+		return errors.New("command can not contain line breaks")
+	}
+
+	if !h.connected() {
+		return errors.New("network %s is not connected", h.network.Name)
+	}
+
+	h.log.Debug().Msgf("sending raw command: %s", cmd)
+
+	h.publishSSEMsg(domain.IrcMessage{Channel: consoleChannel, Nick: h.CurrentNick(), Message: cmd, Time: time.Now()})
+
+	if err := h.client.SendRaw(cmd); err != nil {
+		h.log.Error().Stack().Err(err).Msgf("error sending raw command: %s", cmd)
+		return err
+	}
+
+	return nil
+}
+
+// onConsoleReply publishes a reply to the console stream, like a private message, notice or error numeric
+func (h *Handler) onConsoleReply(msg ircmsg.Message) {
+	if len(msg.Params) < 2 {
+		return
+	}
+
+	nick := msg.Nick()
+	if nick == "" {
+		nick = msg.Source
+	}
+
+	// numerics and private messages start with our nick
+	message := strings.Join(msg.Params[1:], " ")
+	if msg.Command != "PRIVMSG" && msg.Command != "NOTICE" {
+		message = msg.Command + " " + message
+	}
+
+	msgTime, ok := messageTime(msg)
+	if !ok {
+		msgTime = time.Now()
+	}
+
+	h.publishSSEMsg(domain.IrcMessage{Channel: consoleChannel, Nick: nick, Message: h.cleanMessage(message), Time: msgTime})
+}
+
+// isConsoleTarget reports whether a PRIVMSG or NOTICE is sent to us instead of a channel
+func (h *Handler) isConsoleTarget(msg ircmsg.Message) bool {
+	if len(msg.Params) < 2 {
+		return false
+	}
+
+	return strings.EqualFold(msg.Params[0], h.CurrentNick())
+}
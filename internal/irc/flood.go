@@ -0,0 +1,78 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+)
+
+// floodConn limits the lines written to the server so NickServ auth, invite commands and console commands
+// can't trip the flood limit of the server and get the connection killed.
+// ircevent writes one line per Write, also as a single record with tls, and lines that have to wait stay in its send queue.
+type floodConn struct {
+	net.Conn
+	log     zerolog.Logger
+	limiter *rate.Limiter
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (c *floodConn) Write(b []byte) (int, error) {
+	reservation := c.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		c.log.Trace().Msgf("flood protection: delaying line by %s", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-c.ctx.Done():
+			timer.Stop()
+			reservation.Cancel()
+			return 0, net.ErrClosed
+		}
+	}
+
+	return c.Conn.Write(b)
+}
+
+func (c *floodConn) Close() error {
+	c.cancel()
+	return c.Conn.Close()
+}
+
+// floodDialer wraps the connections of dial with the flood protection of the network.
+// A send delay of 0 disables it.
+func (h *Handler) floodDialer(dial dialFunc) dialFunc {
+	network := h.network
+	if network.SendDelay <= 0 {
+		return dial
+	}
+
+	burst := network.SendBurst
+	if burst < 1 {
+		burst = 1
+	}
+
+	return func(ctx context.Context, netw, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, netw, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		connCtx, cancel := context.WithCancel(context.Background())
+
+		return &floodConn{
+			Conn:    conn,
+			log:     h.log,
+			limiter: rate.NewLimiter(rate.Every(time.Duration(network.SendDelay)*time.Millisecond), burst),
+			ctx:     connCtx,
+			cancel:  cancel,
+		}, nil
+	}
+}
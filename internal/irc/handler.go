@@ -4,17 +4,19 @@
 package irc
 
 import (
-	"crypto/tls"
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/announce"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/avast/retry-go"
 	"github.com/dcarbone/zadapters/zstdlog"
 	"github.com/ergochat/irc-go/ircevent"
@@ -32,6 +34,8 @@ type channelHealth struct {
 	monitoring      bool
 	monitoringSince time.Time
 	lastAnnounce    time.Time
+	announces       []time.Time
+	announcesTotal  uint64
 }
 
 // SetLastAnnounce set last announce to now
@@ -39,6 +43,8 @@ func (ch *channelHealth) SetLastAnnounce() {
 	ch.m.Lock()
 	ch.lastAnnounce = time.Now()
 	ch.m.Unlock()
+
+	ch.recordAnnounce(time.Now())
 }
 
 // SetMonitoring set monitoring and time
@@ -63,8 +69,11 @@ type Handler struct {
 	sse                 *sse.Server
 	network             *domain.IrcNetwork
 	releaseSvc          release.Service
+	filterService       filter.Service
 	notificationService notification.Service
-	announceProcessors  map[string]announce.Processor
+	bus                 EventBus.Bus
+	history             *historyWriter
+	announceProcessors  map[string][]indexerProcessor
 	definitions         map[string]*domain.IndexerDefinition
 
 	client *ircevent.Connection
@@ -73,31 +82,66 @@ type Handler struct {
 	connectedSince       time.Time
 	haveDisconnected     bool
 	manuallyDisconnected bool
+	scheduledOffline     bool
+
+	// lastServerError is the reason of the last ERROR from the server, like a k-line, given with the disconnect event
+	lastServerError string
+
+	// netsplit detection and the announces quarantined after it
+	recentQuits     []time.Time
+	quarantineUntil time.Time
+	quarantineTimer *time.Timer
+	quarantined     []domain.IrcQuarantinedAnnounce
 
-	validAnnouncers map[string]struct{}
+	validAnnouncers map[string][]string
 	validChannels   map[string]struct{}
 	channelHealth   map[string]*channelHealth
 
+	// connectAttempts counts the connect attempts since the last successful connection
+	connectAttempts int
+
+	// lastMessageTime holds the server-time of the last announce per channel to skip bouncer replays
+	lastMessageTime map[string]time.Time
+
 	connectionErrors       []string
 	failedNickServAttempts int
 
+	// triggers react to lines in any channel, like a sitewide freeleech in a staff channel
+	triggers []domain.IrcTrigger
+
+	// lag is the last measured round trip to the server, lagStop stops measuring it on disconnect
+	lag     time.Duration
+	lagStop chan struct{}
+
+	// inviteAttempts counts the invite commands per channel after failed joins
+	inviteAttempts map[string]*inviteAttempt
+
+	// regainingNick is set while NickServ is asked to free the preferred nick
+	regainingNick bool
+	regainMethod  string
+
 	authenticated bool
 	saslauthed    bool
 }
 
-func NewHandler(log zerolog.Logger, sse *sse.Server, network domain.IrcNetwork, definitions []*domain.IndexerDefinition, releaseSvc release.Service, notificationSvc notification.Service) *Handler {
+func NewHandler(log zerolog.Logger, sse *sse.Server, network domain.IrcNetwork, definitions []*domain.IndexerDefinition, releaseSvc release.Service, filterSvc filter.Service, notificationSvc notification.Service, bus EventBus.Bus, history *historyWriter) *Handler {
 	h := &Handler{
 		log:                 log.With().Str("network", network.Server).Logger(),
 		sse:                 sse,
 		client:              nil,
 		network:             &network,
 		releaseSvc:          releaseSvc,
+		filterService:       filterSvc,
 		notificationService: notificationSvc,
+		bus:                 bus,
+		history:             history,
 		definitions:         map[string]*domain.IndexerDefinition{},
-		announceProcessors:  map[string]announce.Processor{},
-		validAnnouncers:     map[string]struct{}{},
+		announceProcessors:  map[string][]indexerProcessor{},
+		validAnnouncers:     map[string][]string{},
 		validChannels:       map[string]struct{}{},
 		channelHealth:       map[string]*channelHealth{},
+		lastMessageTime:     map[string]time.Time{},
+		inviteAttempts:      map[string]*inviteAttempt{},
 		authenticated:       false,
 		saslauthed:          false,
 		connectionErrors:    []string{},
@@ -109,6 +153,14 @@ func NewHandler(log zerolog.Logger, sse *sse.Server, network domain.IrcNetwork,
 	return h
 }
 
+// indexerProcessor is the announce processor of an indexer in a channel
+type indexerProcessor struct {
+	identifier string
+	announcers []string
+	multiLine  bool
+	processor  announce.Processor
+}
+
 func (h *Handler) InitIndexers(definitions []*domain.IndexerDefinition) {
 	// Networks can be shared by multiple indexers but channels are unique
 	// so let's add a new AnnounceProcessor per channel
@@ -124,22 +176,52 @@ func (h *Handler) InitIndexers(definitions []*domain.IndexerDefinition) {
 			// some channels are defined in mixed case
 			channel = strings.ToLower(channel)
 
-			h.announceProcessors[channel] = announce.NewAnnounceProcessor(h.log, h.releaseSvc, definition)
+			// indexers sharing a connection can also share a channel, their announces are routed by announcer
+			h.announceProcessors[channel] = append(h.announceProcessors[channel], indexerProcessor{
+				identifier: definition.Identifier,
+				announcers: definition.IRC.Announcers,
+				multiLine:  definition.IRC.Parse != nil && (len(definition.IRC.Parse.Lines) > 1 || definition.IRC.Parse.Assemble != nil),
+				processor:  announce.NewAnnounceProcessor(h.log, h.releaseSvc, definition),
+			})
 
-			h.channelHealth[channel] = &channelHealth{
-				name:       channel,
-				monitoring: false,
+			// keep the health of channels that are monitored already, like when the definitions are reloaded
+			if _, ok := h.channelHealth[channel]; !ok {
+				h.channelHealth[channel] = &channelHealth{
+					name:       channel,
+					monitoring: false,
+				}
 			}
 
 			// create map of valid channels
 			h.validChannels[channel] = struct{}{}
+
+			// trackers can rotate between multiple announce bots in a channel
+			h.validAnnouncers[channel] = append(h.validAnnouncers[channel], definition.IRC.Announcers...)
 		}
+	}
+}
+
+// ReloadIndexers replaces the definitions and announce processors of the network without reconnecting,
+// so changed announce patterns apply from the next announce
+func (h *Handler) ReloadIndexers(definitions []*domain.IndexerDefinition) {
+	h.m.Lock()
+	previous := h.announceProcessors
 
-		// create map of valid announcers
-		for _, announcer := range definition.IRC.Announcers {
-			h.validAnnouncers[strings.ToLower(announcer)] = struct{}{}
+	h.definitions = map[string]*domain.IndexerDefinition{}
+	h.announceProcessors = map[string][]indexerProcessor{}
+	h.validAnnouncers = map[string][]string{}
+	h.validChannels = map[string]struct{}{}
+
+	h.InitIndexers(definitions)
+	h.m.Unlock()
+
+	for _, processors := range previous {
+		for _, p := range processors {
+			p.processor.Stop()
 		}
 	}
+
+	h.log.Info().Msgf("reloaded %d indexer definitions", len(definitions))
 }
 
 func (h *Handler) removeIndexer() {
@@ -166,8 +248,8 @@ func (h *Handler) Run() error {
 
 	h.client = &ircevent.Connection{
 		Nick:          h.network.Nick,
-		User:          h.network.Auth.Account,
-		RealName:      h.network.Auth.Account,
+		User:          ircIdent(h.network),
+		RealName:      ircRealName(h.network),
 		Password:      h.network.Pass,
 		Server:        addr,
 		KeepAlive:     4 * time.Minute,
@@ -179,6 +261,14 @@ func (h *Handler) Run() error {
 		Log:           subLogger,
 	}
 
+	dialer, err := networkDialer(h.network.Proxy)
+	if err != nil {
+		h.addConnectError(err.Error())
+		return err
+	}
+
+	h.client.DialContext = dialer.DialContext
+
 	if h.network.Auth.Mechanism == domain.IRCAuthMechanismSASLPlain {
 		if h.network.Auth.Account != "" && h.network.Auth.Password != "" {
 			h.client.SASLLogin = h.network.Auth.Account
@@ -188,11 +278,45 @@ func (h *Handler) Run() error {
 		}
 	}
 
+	h.client.RequestCaps = requestedCaps(h.network)
+
 	if h.network.TLS {
+		config, err := tlsConfig(h.network)
+		if err != nil {
+			h.addConnectError(err.Error())
+			return err
+		}
+
 		h.client.UseTLS = true
-		h.client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+		h.client.TLSConfig = config
+
+		if h.network.Auth.Mechanism == domain.IRCAuthMechanismSASLExternal {
+			if len(config.Certificates) == 0 {
+				h.addConnectError("sasl external requires a client certificate")
+				return errors.New("sasl external requires a client certificate")
+			}
+
+			// the account comes from the certificate, the login is only needed to start SASL
+			h.client.SASLLogin = h.network.Nick
+			h.client.SASLPassword = "EXTERNAL"
+			h.client.SASLOptional = true
+			h.client.UseSASL = true
+
+			h.client.UseTLS = false
+			h.client.DialContext = dialExternalSASL(dialer, h.client.TLSConfig)
+		}
+	} else if h.network.Auth.Mechanism == domain.IRCAuthMechanismSASLExternal {
+		h.addConnectError("sasl external requires tls")
+		return errors.New("sasl external requires tls")
+	}
+
+	addrs := []string{addr}
+	if !h.network.UseBouncer {
+		addrs = h.network.ServerAddresses()
 	}
 
+	h.client.DialContext = h.reconnectDialer(h.floodDialer(h.client.DialContext), addrs)
+
 	h.client.AddConnectCallback(h.onConnect)
 	h.client.AddDisconnectCallback(h.onDisconnect)
 
@@ -204,6 +328,18 @@ func (h *Handler) Run() error {
 	h.client.AddCallback("NOTICE", h.onNotice)
 	h.client.AddCallback("NICK", h.onNick)
 	h.client.AddCallback("903", h.handleSASLSuccess)
+	h.client.AddCallback("PONG", h.onPong)
+	h.client.AddCallback("QUIT", h.onQuit)
+	h.client.AddCallback("473", h.handleJoinFailed)
+	h.client.AddCallback("474", h.handleJoinFailed)
+	h.client.AddCallback("KICK", h.onKick)
+	h.client.AddCallback("ERROR", h.onError)
+	h.client.AddCallback("904", h.onSASLFailed)
+	h.client.AddCallback("465", h.onServerBanned)
+
+	for _, numeric := range consoleReplies {
+		h.client.AddCallback(numeric, h.onConsoleReply)
+	}
 
 	//h.setConnectionStatus()
 	h.saslauthed = false
@@ -214,7 +350,7 @@ func (h *Handler) Run() error {
 		disconnectTime := time.Now()
 
 		// retry initial connect if network is down
-		// using exponential backoff of 15 seconds
+		// using the reconnect backoff of the network
 		return retry.Do(
 			func() error {
 				h.log.Debug().Msgf("connect attempt %d", connectAttempts)
@@ -236,10 +372,9 @@ func (h *Handler) Run() error {
 					h.log.Debug().Msgf("%s connect attempt %d", h.network.Name, n)
 				}
 			}),
-			retry.Delay(time.Second*15),
 			retry.Attempts(25),
 			retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
-				return retry.BackOffDelay(n, err, config)
+				return h.network.ReconnectBackoff(int(n)+1, rand.Int63n)
 			}),
 		)
 	}(); err != nil {
@@ -323,14 +458,22 @@ func (h *Handler) Stop() {
 	h.connectedSince = time.Time{}
 	h.manuallyDisconnected = true
 
-	if h.client.Connected() {
+	if h.connected() {
 		h.log.Debug().Msg("Disconnecting...")
 	}
 	h.m.Unlock()
 
 	h.resetChannelHealth()
 
-	h.client.Quit()
+	// networks that are offline by schedule at startup never ran
+	if h.client != nil {
+		h.client.Quit()
+	}
+}
+
+// connected reports whether the network has a connection, the client is only created when the network runs
+func (h *Handler) connected() bool {
+	return h.client != nil && h.client.Connected()
 }
 
 // Restart stops the network and then runs it
@@ -350,25 +493,33 @@ func (h *Handler) onConnect(m ircmsg.Message) {
 	// 4. invite command - join
 
 	h.setConnectionStatus()
+	h.resetConnectAttempts()
+	h.startLagCheck()
 
 	func() {
 		h.m.Lock()
+		state := domain.IrcStateConnected
 		if h.haveDisconnected {
-			h.notificationService.Send(domain.NotificationEventIRCReconnected, domain.NotificationPayload{
-				Subject: "IRC Reconnected",
-				Message: fmt.Sprintf("Network: %s", h.network.Name),
-			})
+			state = domain.IrcStateReconnected
 
 			// reset haveDisconnected
 			h.haveDisconnected = false
 		}
+		h.lastServerError = ""
 		h.m.Unlock()
 
 		h.log.Debug().Msgf("connected to: %s", h.network.Name)
+
+		h.emitState(state, "", "")
 	}()
 
 	time.Sleep(1 * time.Second)
 
+	// authenticate and join once the preferred nick is back
+	if h.regainNick() {
+		return
+	}
+
 	h.authenticate()
 
 }
@@ -382,6 +533,8 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 	// reset connectedSince
 	h.connectedSince = time.Time{}
 
+	h.stopLagCheck()
+
 	// reset channelHealth
 	for _, ch := range h.channelHealth {
 		ch.resetMonitoring()
@@ -389,25 +542,31 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 
 	// reset authenticated
 	h.authenticated = false
+	h.regainingNick = false
 
 	h.haveDisconnected = true
 
 	// check if we are responsible for disconnect
-	if !h.manuallyDisconnected {
-		// only send notification if we did not initiate disconnect/restart/stop
-		h.notificationService.Send(domain.NotificationEventIRCDisconnected, domain.NotificationPayload{
-			Subject: "IRC Disconnected unexpectedly",
-			Message: fmt.Sprintf("Network: %s", h.network.Name),
-		})
-	} else {
-		// reset
-		h.manuallyDisconnected = false
-	}
+	unexpected := !h.manuallyDisconnected
+	reason := h.lastServerError
+
+	// reset
+	h.manuallyDisconnected = false
+	h.lastServerError = ""
 	h.m.Unlock()
+
+	// only send notification if we did not initiate disconnect/restart/stop
+	if unexpected {
+		h.emitState(domain.IrcStateDisconnected, "", reason)
+	}
 }
 
 // onNotice handles NOTICE events
 func (h *Handler) onNotice(msg ircmsg.Message) {
+	if h.isConsoleTarget(msg) {
+		h.onConsoleReply(msg)
+	}
+
 	switch msg.Nick() {
 	case "NickServ":
 		h.handleNickServ(msg)
@@ -418,6 +577,10 @@ func (h *Handler) onNotice(msg ircmsg.Message) {
 func (h *Handler) handleNickServ(msg ircmsg.Message) {
 	h.log.Trace().Msgf("NOTICE from nickserv: %v", msg.Params)
 
+	if h.handleNickRegainNotice(msg.Params[1]) {
+		return
+	}
+
 	if contains(msg.Params[1],
 		"Invalid account credentials",
 		"Authentication failed: Invalid account credentials",
@@ -425,6 +588,7 @@ func (h *Handler) handleNickServ(msg ircmsg.Message) {
 	) {
 		h.addConnectError("authentication failed: Bad account credentials")
 		h.log.Error().Msg("NickServ: authentication failed - bad account credentials")
+		h.emitState(domain.IrcStateAuthFailed, "", "NickServ: bad account credentials")
 
 		// stop network and notify user
 		h.Stop()
@@ -438,6 +602,7 @@ func (h *Handler) handleNickServ(msg ircmsg.Message) {
 	) {
 		if h.CurrentNick() == h.PreferredNick() {
 			h.addConnectError("authentication failed: account does not exist")
+			h.emitState(domain.IrcStateAuthFailed, "", "NickServ: account does not exist")
 
 			// stop network and notify user
 			h.Stop()
@@ -517,6 +682,7 @@ func (h *Handler) setAuthenticated() {
 	h.connectionErrors = []string{}
 	h.failedNickServAttempts = 0
 
+	h.perform()
 	h.inviteCommand()
 	h.JoinChannels()
 }
@@ -556,6 +722,8 @@ func (h *Handler) onNick(msg ircmsg.Message) {
 		return
 	}
 
+	h.onNickRegained()
+
 	if !h.authenticated {
 		h.authenticate()
 	}
@@ -575,6 +743,16 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 	if len(msg.Params) < 2 {
 		return
 	}
+
+	// lines that are not valid UTF-8 are transcoded from the fallback encoding of the network
+	msg.Params[1] = h.GetNetwork().DecodeLine(msg.Params[1])
+
+	// private messages are replies to the console, not announces
+	if h.isConsoleTarget(msg) {
+		h.onConsoleReply(msg)
+		return
+	}
+
 	// parse announce
 	nick := msg.Nick()
 	channel := msg.Params[0]
@@ -583,8 +761,19 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 	// clean message
 	cleanedMsg := h.cleanMessage(message)
 
+	// with server-time lines carry the time they were sent, also when replayed by a bouncer
+	msgTime, hasTime := messageTime(msg)
+	if !hasTime {
+		msgTime = time.Now()
+	}
+
 	// publish to SSE stream
-	h.publishSSEMsg(domain.IrcMessage{Channel: channel, Nick: nick, Message: cleanedMsg, Time: time.Now()})
+	h.publishSSEMsg(domain.IrcMessage{Channel: channel, Nick: nick, Message: cleanedMsg, Time: msgTime})
+
+	// store in the history so it can be searched after a restart
+	h.history.Write(domain.IrcLogLine{NetworkID: h.network.ID, Channel: channel, Nick: nick, Message: cleanedMsg, Timestamp: msgTime})
+
+	h.runTriggers(channel, nick, cleanedMsg)
 
 	// check if message is from a valid channel, if not return
 	if validChannel := h.isValidChannel(channel); !validChannel {
@@ -592,13 +781,23 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 	}
 
 	// check if message is from announce bot, if not return
-	if validAnnouncer := h.isValidAnnouncer(nick); !validAnnouncer {
+	if validAnnouncer := h.isValidAnnouncer(channel, msg.Source); !validAnnouncer {
+		h.log.Trace().Str("channel", channel).Str("nick", nick).Msgf("ignoring line from unknown announcer %s", msg.Source)
+		return
+	}
+
+	if h.network.UseBouncer && hasTime && h.skipReplayed(channel, msgTime) {
+		h.log.Debug().Str("channel", channel).Str("nick", nick).Msgf("skipping replayed announce from %s: %s", msgTime.Format(time.RFC3339), cleanedMsg)
 		return
 	}
 
 	h.log.Debug().Str("channel", channel).Str("nick", nick).Msg(cleanedMsg)
 
-	if err := h.sendToAnnounceProcessor(channel, cleanedMsg); err != nil {
+	if h.quarantine(channel, msg.Source, cleanedMsg, msgTime) {
+		return
+	}
+
+	if err := h.sendToAnnounceProcessor(channel, msg.Source, cleanedMsg, msgTime); err != nil {
 		h.log.Error().Stack().Err(err).Msgf("could not queue line: %s", cleanedMsg)
 		return
 	}
@@ -607,17 +806,37 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 }
 
 // send the msg to announce processor
-func (h *Handler) sendToAnnounceProcessor(channel string, msg string) error {
+func (h *Handler) sendToAnnounceProcessor(channel string, source string, msg string, msgTime time.Time) error {
 	channel = strings.ToLower(channel)
 
 	// check if queue exists
-	queue, ok := h.announceProcessors[channel]
-	if !ok {
+	h.m.RLock()
+	processors, ok := h.announceProcessors[channel]
+	h.m.RUnlock()
+	if !ok || len(processors) == 0 {
 		return errors.New("queue '%s' not found", channel)
 	}
 
+	queue := processors[0].processor
+	if len(processors) > 1 {
+		queue = nil
+		for _, p := range processors {
+			if domain.MatchAnnouncer(p.announcers, source) {
+				h.log.Trace().Msgf("routing announce from %s in %s to %s", source, channel, p.identifier)
+				queue = p.processor
+				break
+			}
+		}
+
+		// a line of a shared channel that no indexer announces can't be parsed by the right definition
+		if queue == nil {
+			h.log.Debug().Msgf("dropping line from %s in %s, not an announcer of any indexer in the channel: %s", source, channel, msg)
+			return nil
+		}
+	}
+
 	// if it exists, add msg
-	if err := queue.AddLineToQueue(channel, msg); err != nil {
+	if err := queue.AddLineToQueue(channel, msg, msgTime); err != nil {
 		h.log.Error().Stack().Err(err).Msgf("could not queue line: %s", msg)
 		return err
 	}
@@ -745,6 +964,10 @@ func (h *Handler) handleJoined(msg ircmsg.Message) {
 
 	h.log.Info().Msgf("Monitoring channel %s", channel)
 
+	h.resetInviteAttempts(channel)
+
+	h.requestPlayback(msg.Params[1])
+
 	// reset log level to Trace now that we are monitoring a channel
 	h.client.Log = zstdlog.NewStdLoggerWithLevel(h.log.With().Logger(), zerolog.TraceLevel)
 }
@@ -861,6 +1084,10 @@ func (h *Handler) handleMode(msg ircmsg.Message) {
 func (h *Handler) SendMsg(channel, msg string) error {
 	h.log.Debug().Msgf("sending msg command: %s", msg)
 
+	if !h.connected() {
+		return errors.New("network %s is not connected", h.network.Name)
+	}
+
 	if err := h.client.Privmsg(channel, msg); err != nil {
 		h.log.Error().Stack().Err(err).Msgf("error sending msg: %s", msg)
 		return err
@@ -869,13 +1096,22 @@ func (h *Handler) SendMsg(channel, msg string) error {
 	return nil
 }
 
-// check if announcer is one from the list in the definition
-func (h *Handler) isValidAnnouncer(nick string) bool {
+// check if announcer is one from the list of the channel in the network settings or else the definition.
+// source is the nick!user@host of the sender so announcers can also be host masks.
+func (h *Handler) isValidAnnouncer(channel string, source string) bool {
 	h.m.RLock()
 	defer h.m.RUnlock()
 
-	_, ok := h.validAnnouncers[strings.ToLower(nick)]
-	return ok
+	for _, ircChannel := range h.network.Channels {
+		if strings.EqualFold(ircChannel.Name, channel) {
+			if announcers := ircChannel.AnnouncerList(); len(announcers) > 0 {
+				return domain.MatchAnnouncer(announcers, source)
+			}
+			break
+		}
+	}
+
+	return domain.MatchAnnouncer(h.validAnnouncers[strings.ToLower(channel)], source)
 }
 
 // check if channel is one from the list in the definition
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircevent"
+	"github.com/ergochat/irc-go/ircmsg"
+	"github.com/r3labs/sse/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockProcessor records the lines queued for an indexer
+type mockProcessor struct {
+	lines []string
+}
+
+func (p *mockProcessor) AddLineToQueue(channel string, line string, timestamp time.Time) error {
+	p.lines = append(p.lines, line)
+	return nil
+}
+
+func (p *mockProcessor) Stop() {}
+
+// newTestHandler creates a handler for network with the announce processors of the definitions replaced by mocks
+func newTestHandler(network domain.IrcNetwork, definitions []*domain.IndexerDefinition) (*Handler, map[string]*mockProcessor) {
+	h := NewHandler(zerolog.Nop(), sse.New(), network, definitions, nil, nil, nil, nil, nil)
+	h.client = &ircevent.Connection{}
+
+	mocks := map[string]*mockProcessor{}
+	for _, definition := range definitions {
+		mocks[definition.Identifier] = &mockProcessor{}
+	}
+
+	for channel, processors := range h.announceProcessors {
+		for i, p := range processors {
+			p.processor.Stop()
+			h.announceProcessors[channel][i].processor = mocks[p.identifier]
+		}
+	}
+
+	return h, mocks
+}
+
+func newTestDefinition(identifier string, channels []string, announcers []string) *domain.IndexerDefinition {
+	return &domain.IndexerDefinition{
+		Identifier: identifier,
+		IRC: &domain.IndexerIRC{
+			Channels:   channels,
+			Announcers: announcers,
+		},
+	}
+}
+
+func TestHandler_InitIndexers(t *testing.T) {
+	definitions := []*domain.IndexerDefinition{
+		newTestDefinition("tracker-a", []string{"#Announce"}, []string{"BotA"}),
+		newTestDefinition("tracker-b", []string{"#announce"}, []string{"BotB", "BotB2"}),
+		newTestDefinition("tracker-c", []string{"#other"}, []string{"BotC"}),
+	}
+
+	h, _ := newTestHandler(domain.IrcNetwork{Server: "irc.shared.net", ShareConnection: true}, definitions)
+
+	// adding the same definitions again does not add processors twice
+	h.InitIndexers(definitions)
+
+	assert.Len(t, h.announceProcessors["#announce"], 2)
+	assert.Equal(t, "tracker-a", h.announceProcessors["#announce"][0].identifier)
+	assert.Equal(t, "tracker-b", h.announceProcessors["#announce"][1].identifier)
+	assert.Len(t, h.announceProcessors["#other"], 1)
+
+	assert.Equal(t, []string{"BotA", "BotB", "BotB2"}, h.validAnnouncers["#announce"])
+	assert.Equal(t, []string{"BotC"}, h.validAnnouncers["#other"])
+
+	assert.True(t, h.isValidChannel("#ANNOUNCE"))
+	assert.False(t, h.isValidChannel("#chat"))
+}
+
+func TestHandler_onMessage(t *testing.T) {
+	definitions := []*domain.IndexerDefinition{
+		newTestDefinition("tracker-a", []string{"#announce"}, []string{"BotA"}),
+		newTestDefinition("tracker-b", []string{"#announce"}, []string{"BotB!*@bots.tracker-b.org"}),
+		newTestDefinition("tracker-c", []string{"#other"}, []string{"BotC"}),
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		channel string
+		want    map[string][]string
+	}{
+		{
+			name:    "first_indexer",
+			source:  "BotA!bot@tracker-a.org",
+			channel: "#announce",
+			want:    map[string][]string{"tracker-a": {"line"}},
+		},
+		{
+			name:    "second_indexer",
+			source:  "BotB!bot@bots.tracker-b.org",
+			channel: "#Announce",
+			want:    map[string][]string{"tracker-b": {"line"}},
+		},
+		{
+			name:    "wrong_host",
+			source:  "BotB!bot@elsewhere.org",
+			channel: "#announce",
+			want:    map[string][]string{},
+		},
+		{
+			name:    "announcer_of_other_channel",
+			source:  "BotC!bot@tracker-c.org",
+			channel: "#announce",
+			want:    map[string][]string{},
+		},
+		{
+			name:    "own_channel",
+			source:  "BotC!bot@tracker-c.org",
+			channel: "#other",
+			want:    map[string][]string{"tracker-c": {"line"}},
+		},
+		{
+			name:    "unknown_channel",
+			source:  "BotA!bot@tracker-a.org",
+			channel: "#chat",
+			want:    map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mocks := newTestHandler(domain.IrcNetwork{Server: "irc.shared.net", ShareConnection: true}, definitions)
+
+			h.onMessage(ircmsg.Message{Source: tt.source, Command: "PRIVMSG", Params: []string{tt.channel, "line"}})
+
+			for identifier, mock := range mocks {
+				assert.Equal(t, tt.want[identifier], mock.lines, identifier)
+			}
+		})
+	}
+}
+
+func TestHandler_onMessage_channelAnnouncers(t *testing.T) {
+	definitions := []*domain.IndexerDefinition{
+		newTestDefinition("tracker-a", []string{"#announce"}, []string{"BotA"}),
+		newTestDefinition("tracker-b", []string{"#announce"}, []string{"BotB"}),
+		newTestDefinition("tracker-c", []string{"#other"}, []string{"BotC"}),
+	}
+
+	network := domain.IrcNetwork{
+		Server:          "irc.shared.net",
+		ShareConnection: true,
+		Channels: []domain.IrcChannel{
+			{Name: "#announce", Announcers: "CustomBot, BotB"},
+			{Name: "#other", Announcers: "CustomBot"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		channel string
+		want    map[string][]string
+	}{
+		{
+			name:    "shared_channel_indexer_announcer",
+			source:  "BotB!bot@tracker-b.org",
+			channel: "#announce",
+			want:    map[string][]string{"tracker-b": {"line"}},
+		},
+		{
+			name:    "shared_channel_no_indexer_announcer",
+			source:  "CustomBot!bot@example.org",
+			channel: "#announce",
+			want:    map[string][]string{},
+		},
+		{
+			name:    "single_indexer_channel",
+			source:  "CustomBot!bot@example.org",
+			channel: "#other",
+			want:    map[string][]string{"tracker-c": {"line"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mocks := newTestHandler(network, definitions)
+
+			h.onMessage(ircmsg.Message{Source: tt.source, Command: "PRIVMSG", Params: []string{tt.channel, "line"}})
+
+			for identifier, mock := range mocks {
+				assert.Equal(t, tt.want[identifier], mock.lines, identifier)
+			}
+		})
+	}
+}
+
+func TestHandler_onMessage_fallbackEncoding(t *testing.T) {
+	definitions := []*domain.IndexerDefinition{
+		newTestDefinition("tracker-a", []string{"#announce"}, []string{"BotA"}),
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		line     string
+		want     []string
+	}{
+		{name: "latin1", encoding: "latin-1", line: "New: Am\xe9lie.2001.1080p.BluRay.x264-GRP", want: []string{"New: Amélie.2001.1080p.BluRay.x264-GRP"}},
+		{name: "utf8", encoding: "latin-1", line: "New: Amélie.2001.1080p.BluRay.x264-GRP", want: []string{"New: Amélie.2001.1080p.BluRay.x264-GRP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mocks := newTestHandler(domain.IrcNetwork{Server: "irc.tracker.org", Encoding: tt.encoding}, definitions)
+
+			h.onMessage(ircmsg.Message{Source: "BotA!bot@tracker-a.org", Command: "PRIVMSG", Params: []string{"#announce", tt.line}})
+
+			assert.Equal(t, tt.want, mocks["tracker-a"].lines)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// historyBatchSize is the max amount of lines stored in one query
+	historyBatchSize = 100
+	// historyFlushInterval is how long lines are buffered before they are stored
+	historyFlushInterval = 2 * time.Second
+	// historyCleanupInterval is how often lines past the retention are removed
+	historyCleanupInterval = time.Hour
+	// defaultHistoryRetentionDays is used when the config has no retention set
+	defaultHistoryRetentionDays = 7
+)
+
+// historyWriter stores channel lines in the database in batches, so announces can be searched after a restart.
+// Lines are dropped instead of blocking the irc handler when the database can not keep up.
+type historyWriter struct {
+	log    zerolog.Logger
+	repo   domain.IrcLogRepo
+	config *domain.Config
+
+	lines chan domain.IrcLogLine
+}
+
+func newHistoryWriter(log zerolog.Logger, repo domain.IrcLogRepo, config *domain.Config) *historyWriter {
+	return &historyWriter{
+		log:    log.With().Str("module", "irc_history").Logger(),
+		repo:   repo,
+		config: config,
+		lines:  make(chan domain.IrcLogLine, 1024),
+	}
+}
+
+// retentionDays returns how many days lines are kept, 0 disables the history
+func (w *historyWriter) retentionDays() int {
+	if w.config == nil || w.config.IrcLogRetentionDays < 0 {
+		return defaultHistoryRetentionDays
+	}
+
+	return w.config.IrcLogRetentionDays
+}
+
+func (w *historyWriter) Write(line domain.IrcLogLine) {
+	if w == nil || w.retentionDays() == 0 {
+		return
+	}
+
+	select {
+	case w.lines <- line:
+	default:
+		w.log.Warn().Msgf("history buffer full, dropped line from %s", line.Channel)
+	}
+}
+
+// run stores the buffered lines and removes lines past the retention until the context is done
+func (w *historyWriter) run(ctx context.Context) {
+	flush := time.NewTicker(historyFlushInterval)
+	defer flush.Stop()
+
+	cleanup := time.NewTicker(historyCleanupInterval)
+	defer cleanup.Stop()
+
+	batch := make([]domain.IrcLogLine, 0, historyBatchSize)
+
+	store := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := w.repo.Store(context.Background(), batch); err != nil {
+			w.log.Error().Err(err).Msgf("could not store %d lines", len(batch))
+		}
+
+		batch = batch[:0]
+	}
+
+	w.deleteExpired()
+
+	for {
+		select {
+		case <-ctx.Done():
+			store()
+			return
+
+		case line := <-w.lines:
+			batch = append(batch, line)
+			if len(batch) >= historyBatchSize {
+				store()
+			}
+
+		case <-flush.C:
+			store()
+
+		case <-cleanup.C:
+			w.deleteExpired()
+		}
+	}
+}
+
+func (w *historyWriter) deleteExpired() {
+	days := w.retentionDays()
+	if days == 0 {
+		return
+	}
+
+	deleted, err := w.repo.DeleteOlderThan(context.Background(), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		w.log.Error().Err(err).Msg("could not delete expired lines")
+		return
+	}
+
+	if deleted > 0 {
+		w.log.Debug().Msgf("deleted %d lines older than %d days", deleted, days)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// injectLines queues fabricated lines of the announcer like they were sent to the channel
+func (h *Handler) injectLines(channel string, source string, lines []string) (int, error) {
+	if !h.isValidChannel(channel) {
+		return 0, errors.New("channel %s has no announce processor", channel)
+	}
+
+	if !h.isValidAnnouncer(channel, source) {
+		return 0, errors.New("%s is not an announcer of %s", source, channel)
+	}
+
+	queued := 0
+	for _, line := range lines {
+		cleanedMsg := h.cleanMessage(line)
+
+		h.log.Info().Str("channel", channel).Str("nick", source).Msgf("injected announce: %s", cleanedMsg)
+
+		if err := h.sendToAnnounceProcessor(channel, source, cleanedMsg, time.Now()); err != nil {
+			return queued, errors.Wrap(err, "could not queue line: %s", cleanedMsg)
+		}
+
+		queued++
+	}
+
+	return queued, nil
+}
+
+// InjectAnnounce handles fabricated announce lines in a channel of a running network as if the announcer sent them.
+// The lines go through the announce processor of the channel and the releases are processed like live announces,
+// with dry run they are only parsed and checked against the filters.
+func (s *service) InjectAnnounce(ctx context.Context, req domain.IrcInjectRequest) (*domain.IrcInjectResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	handler, err := s.handlerByID(req.NetworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	network := handler.GetNetwork()
+
+	definitions := s.channelDefinitions(network.Server, req.Channel)
+	if len(definitions) == 0 {
+		return nil, errors.New("no indexer announces in channel %s on %s", req.Channel, network.Server)
+	}
+
+	nick := req.Nick
+	if nick == "" {
+		if len(definitions) > 1 {
+			return nil, errors.New("nick required, channel %s is shared by %d indexers", req.Channel, len(definitions))
+		}
+
+		announcers := channelAnnouncers(network, definitions[0], req.Channel)
+		if len(announcers) == 0 {
+			return nil, errors.New("nick required, channel %s has no announcers", req.Channel)
+		}
+
+		nick, _, _ = strings.Cut(announcers[0], "!")
+		if strings.ContainsAny(nick, "*?") {
+			return nil, errors.New("nick required, announcer %s of channel %s is a mask", announcers[0], req.Channel)
+		}
+	}
+
+	// the lines go to the announce processor of the indexer the nick announces for
+	def := announcerDefinition(definitions, nick)
+	if def == nil {
+		return nil, errors.New("%s is not an announcer of any indexer in %s", nick, req.Channel)
+	}
+
+	announcers := channelAnnouncers(network, def, req.Channel)
+
+	result := &domain.IrcInjectResult{
+		Channel:   req.Channel,
+		Indexer:   def.Identifier,
+		Nick:      nick,
+		DryRun:    req.DryRun,
+		Announces: []domain.IrcReplayAnnounce{},
+	}
+
+	if !req.DryRun {
+		queued, err := handler.injectLines(req.Channel, nick, req.Lines)
+		if err != nil {
+			return nil, err
+		}
+		result.Queued = queued
+
+		return result, nil
+	}
+
+	if !domain.MatchAnnouncer(announcers, nick) {
+		return nil, errors.New("%s is not an announcer of %s", nick, req.Channel)
+	}
+
+	now := time.Now()
+
+	lines := make([]domain.IrcLogLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, domain.IrcLogLine{
+			NetworkID: network.ID,
+			Channel:   req.Channel,
+			Nick:      nick,
+			Message:   handler.cleanMessage(line),
+			Timestamp: now,
+		})
+	}
+
+	announces, err := s.parseLoggedAnnounces(ctx, def, lines, true)
+	if err != nil {
+		return nil, err
+	}
+	result.Announces = announces
+
+	s.log.Info().Msgf("dry run of %d injected lines in %s parsed %d announces", len(lines), req.Channel, len(announces))
+
+	return result, nil
+}
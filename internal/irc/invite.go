@@ -0,0 +1,150 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+const (
+	// inviteRetryInterval is the min time between invite commands for a channel
+	inviteRetryInterval = 2 * time.Minute
+	// inviteMaxAttempts is how often the invite command is sent for a channel before giving up
+	inviteMaxAttempts = 3
+	// inviteRejoinDelay is how long to wait for the invite before joining again,
+	// some bots only grant access and don't send an INVITE
+	inviteRejoinDelay = 10 * time.Second
+)
+
+type inviteAttempt struct {
+	count int
+	last  time.Time
+}
+
+// handleJoinFailed runs the invite command of the network when joining a channel fails with
+// 473 ERR_INVITEONLYCHAN or 474 ERR_BANNEDFROMCHAN
+func (h *Handler) handleJoinFailed(msg ircmsg.Message) {
+	if len(msg.Params) < 2 {
+		return
+	}
+
+	channel := msg.Params[1]
+	reason := "invite only"
+	if msg.Command == "474" {
+		reason = "banned"
+	}
+
+	if !h.isValidHandlerChannel(channel) {
+		return
+	}
+
+	if h.network.InviteCommand == "" {
+		h.log.Warn().Msgf("could not join %s (%s) and no invite command is set", channel, reason)
+		h.addConnectError(fmt.Sprintf("could not join %s: %s", channel, reason))
+		h.joinFailedState(msg.Command, channel, reason)
+		return
+	}
+
+	attempt, ok := h.nextInviteAttempt(channel)
+	if !ok {
+		return
+	}
+
+	if attempt > inviteMaxAttempts {
+		h.log.Error().Msgf("could not join %s (%s) after %d invite commands", channel, reason, inviteMaxAttempts)
+		h.addConnectError(fmt.Sprintf("could not join %s: %s, invite command did not work", channel, reason))
+		h.joinFailedState(msg.Command, channel, reason)
+		return
+	}
+
+	h.log.Info().Msgf("could not join %s (%s), sending invite command (attempt %d/%d)", channel, reason, attempt, inviteMaxAttempts)
+
+	go func() {
+		if err := h.sendConnectCommands(h.network.InviteCommand); err != nil {
+			h.log.Error().Err(err).Msgf("could not send invite command for %s", channel)
+			return
+		}
+
+		time.Sleep(inviteRejoinDelay)
+
+		if h.isMonitoring(channel) {
+			return
+		}
+
+		if err := h.JoinChannel(channel, h.channelPassword(channel)); err != nil {
+			h.log.Error().Err(err).Msgf("could not join %s after invite command", channel)
+		}
+	}()
+}
+
+// joinFailedState publishes a channel ban once joining the channel is given up
+func (h *Handler) joinFailedState(command string, channel string, reason string) {
+	if command == "474" {
+		h.emitState(domain.IrcStateBanned, channel, reason)
+	}
+}
+
+// nextInviteAttempt counts the invite commands of the channel. Returns false while the last one is too recent.
+func (h *Handler) nextInviteAttempt(channel string) (int, bool) {
+	channel = strings.ToLower(channel)
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	attempt, ok := h.inviteAttempts[channel]
+	if !ok {
+		attempt = &inviteAttempt{}
+		h.inviteAttempts[channel] = attempt
+	}
+
+	if time.Since(attempt.last) < inviteRetryInterval {
+		h.log.Debug().Msgf("invite command for %s sent %s ago, waiting", channel, time.Since(attempt.last).Round(time.Second))
+		return 0, false
+	}
+
+	attempt.count++
+	attempt.last = time.Now()
+
+	return attempt.count, true
+}
+
+// resetInviteAttempts is called when the channel is joined
+func (h *Handler) resetInviteAttempts(channel string) {
+	channel = strings.ToLower(channel)
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if attempt, ok := h.inviteAttempts[channel]; ok {
+		h.log.Info().Msgf("joined %s after %d invite commands", channel, attempt.count)
+		delete(h.inviteAttempts, channel)
+	}
+}
+
+func (h *Handler) isMonitoring(channel string) bool {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	ch, ok := h.channelHealth[strings.ToLower(channel)]
+	return ok && ch != nil && ch.monitoring
+}
+
+func (h *Handler) channelPassword(channel string) string {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	for _, c := range h.network.Channels {
+		if strings.EqualFold(c.Name, channel) {
+			return c.Password
+		}
+	}
+
+	return ""
+}
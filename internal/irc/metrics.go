@@ -0,0 +1,126 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+const (
+	// lagCheckInterval is how often the lag to the server is measured
+	lagCheckInterval = 1 * time.Minute
+	// lagPingPrefix marks our own PINGs so the PONG can be told apart from the keepalive of ircevent
+	lagPingPrefix = "autobrr-lag-"
+	// announceRateWindow is the window of the announce rate of a channel
+	announceRateWindow = 1 * time.Hour
+)
+
+// recordAnnounce counts an announce of the channel for the announce rate
+func (ch *channelHealth) recordAnnounce(t time.Time) {
+	ch.m.Lock()
+	defer ch.m.Unlock()
+
+	ch.announcesTotal++
+	ch.announces = append(pruneAnnounces(ch.announces, t), t)
+}
+
+// announceRate returns the announces of the last hour and the total since start
+func (ch *channelHealth) announceRate() (int, uint64) {
+	ch.m.Lock()
+	defer ch.m.Unlock()
+
+	ch.announces = pruneAnnounces(ch.announces, time.Now())
+
+	return len(ch.announces), ch.announcesTotal
+}
+
+// pruneAnnounces drops the announce times that are older than the announce rate window
+func pruneAnnounces(announces []time.Time, now time.Time) []time.Time {
+	i := 0
+	for i < len(announces) && now.Sub(announces[i]) > announceRateWindow {
+		i++
+	}
+
+	return announces[i:]
+}
+
+// measureLag pings the server every lagCheckInterval until stop is closed
+func (h *Handler) measureLag(stop chan struct{}) {
+	ticker := time.NewTicker(lagCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		h.sendLagPing()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Handler) sendLagPing() {
+	if !h.client.Connected() {
+		return
+	}
+
+	token := lagPingPrefix + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := h.client.Send("PING", token); err != nil {
+		h.log.Trace().Err(err).Msg("could not send lag ping")
+	}
+}
+
+// onPong sets the lag from the time in the token of our own PINGs
+func (h *Handler) onPong(msg ircmsg.Message) {
+	if len(msg.Params) == 0 {
+		return
+	}
+
+	token := msg.Params[len(msg.Params)-1]
+	if !strings.HasPrefix(token, lagPingPrefix) {
+		return
+	}
+
+	sent, err := strconv.ParseInt(strings.TrimPrefix(token, lagPingPrefix), 10, 64)
+	if err != nil {
+		return
+	}
+
+	lag := time.Since(time.Unix(0, sent))
+
+	h.m.Lock()
+	h.lag = lag
+	h.m.Unlock()
+
+	h.log.Trace().Msgf("lag: %s", lag)
+}
+
+// startLagCheck starts measuring the lag of the connection, it stops on disconnect
+func (h *Handler) startLagCheck() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.lagStop != nil {
+		close(h.lagStop)
+	}
+
+	h.lagStop = make(chan struct{})
+
+	go h.measureLag(h.lagStop)
+}
+
+// stopLagCheck stops measuring the lag and resets it. Must be called with h.m locked.
+func (h *Handler) stopLagCheck() {
+	if h.lagStop != nil {
+		close(h.lagStop)
+		h.lagStop = nil
+	}
+
+	h.lag = 0
+}
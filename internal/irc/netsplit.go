@@ -0,0 +1,245 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// netsplitReason matches the quit reason of users lost in a netsplit, which is the name of both servers
+var netsplitReason = regexp.MustCompile(`^[\w-]+(\.[\w-]+)+ [\w-]+(\.[\w-]+)+$`)
+
+const (
+	// massQuitCount quits within massQuitWindow are treated as a netsplit even without a split reason
+	massQuitCount  = 5
+	massQuitWindow = 10 * time.Second
+)
+
+// onQuit watches the quits of other users for netsplits
+func (h *Handler) onQuit(msg ircmsg.Message) {
+	if h.isOurCurrentNick(msg.Nick()) {
+		return
+	}
+
+	reason := ""
+	if len(msg.Params) > 0 {
+		reason = msg.Params[len(msg.Params)-1]
+	}
+
+	if !h.detectSplit(reason, time.Now()) {
+		return
+	}
+
+	h.startQuarantine(time.Now())
+}
+
+// detectSplit records the quit and reports whether it is part of a netsplit or a mass quit
+func (h *Handler) detectSplit(reason string, now time.Time) bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if netsplitReason.MatchString(strings.TrimSpace(reason)) {
+		return true
+	}
+
+	quits := h.recentQuits[:0]
+	for _, t := range h.recentQuits {
+		if now.Sub(t) < massQuitWindow {
+			quits = append(quits, t)
+		}
+	}
+	h.recentQuits = append(quits, now)
+
+	return len(h.recentQuits) >= massQuitCount
+}
+
+// startQuarantine holds back the announces of the next split quarantine seconds, since announces are often
+// replayed or duplicated when the servers rejoin
+func (h *Handler) startQuarantine(now time.Time) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.network.SplitQuarantine <= 0 {
+		return
+	}
+
+	duration := time.Duration(h.network.SplitQuarantine) * time.Second
+
+	if now.Before(h.quarantineUntil) {
+		// extend the running quarantine while the split goes on
+		h.quarantineUntil = now.Add(duration)
+		if h.quarantineTimer != nil {
+			h.quarantineTimer.Reset(duration)
+		}
+		return
+	}
+
+	h.log.Warn().Msgf("netsplit detected, quarantining announces for %s", duration)
+
+	h.quarantineUntil = now.Add(duration)
+
+	if h.network.SplitAction != domain.IrcSplitActionHold {
+		h.quarantineTimer = time.AfterFunc(duration, func() {
+			h.ReleaseQuarantine()
+		})
+	}
+}
+
+// quarantine holds the announce when a quarantine is running and reports whether it did
+func (h *Handler) quarantine(channel, source, msg string, msgTime time.Time) bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	active := time.Now().Before(h.quarantineUntil)
+
+	// with hold the quarantine lasts until the held announces are released or dropped
+	if !active && (h.network.SplitAction != domain.IrcSplitActionHold || len(h.quarantined) == 0) {
+		return false
+	}
+
+	h.log.Debug().Str("channel", channel).Msgf("quarantined announce: %s", msg)
+
+	h.quarantined = append(h.quarantined, domain.IrcQuarantinedAnnounce{
+		Channel:   channel,
+		Source:    source,
+		Message:   msg,
+		Timestamp: msgTime,
+	})
+
+	return true
+}
+
+// Quarantined returns the announces held back after a netsplit
+func (h *Handler) Quarantined() []domain.IrcQuarantinedAnnounce {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	quarantined := make([]domain.IrcQuarantinedAnnounce, len(h.quarantined))
+	copy(quarantined, h.quarantined)
+
+	return quarantined
+}
+
+// ReleaseQuarantine ends the quarantine and processes the held announces, duplicates are processed once
+func (h *Handler) ReleaseQuarantine() int {
+	h.m.Lock()
+	if h.quarantineTimer != nil {
+		h.quarantineTimer.Stop()
+		h.quarantineTimer = nil
+	}
+
+	held := h.quarantined
+	h.quarantined = nil
+	h.quarantineUntil = time.Time{}
+	h.m.Unlock()
+
+	seen := make(map[string]struct{}, len(held))
+	released := 0
+
+	for _, announce := range held {
+		// lines of multi line announces repeat, like a header line, so only single line announces are deduplicated
+		key := strings.ToLower(announce.Channel) + " " + announce.Message
+		if _, ok := seen[key]; ok && !h.multiLineChannel(announce.Channel) {
+			h.log.Debug().Str("channel", announce.Channel).Msgf("dropping duplicate quarantined announce: %s", announce.Message)
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if err := h.sendToAnnounceProcessor(announce.Channel, announce.Source, announce.Message, announce.Timestamp); err != nil {
+			h.log.Error().Err(err).Msgf("could not queue quarantined line: %s", announce.Message)
+			continue
+		}
+
+		released++
+	}
+
+	if len(held) > 0 {
+		h.log.Info().Msgf("released %d of %d quarantined announces", released, len(held))
+	}
+
+	return released
+}
+
+// DropQuarantine ends the quarantine and discards the held announces
+func (h *Handler) DropQuarantine() int {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.quarantineTimer != nil {
+		h.quarantineTimer.Stop()
+		h.quarantineTimer = nil
+	}
+
+	dropped := len(h.quarantined)
+	h.quarantined = nil
+	h.quarantineUntil = time.Time{}
+
+	h.log.Info().Msgf("dropped %d quarantined announces", dropped)
+
+	return dropped
+}
+
+// multiLineChannel reports whether an indexer of the channel announces in more than one line
+func (h *Handler) multiLineChannel(channel string) bool {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	for _, p := range h.announceProcessors[strings.ToLower(channel)] {
+		if p.multiLine {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *service) handlerByID(networkID int64) (*Handler, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	handler, found := s.handlers[networkID]
+	if !found {
+		return nil, errors.New("network %d is not running", networkID)
+	}
+
+	return handler, nil
+}
+
+// ListQuarantined returns the announces of the network held back after a netsplit
+func (s *service) ListQuarantined(ctx context.Context, networkID int64) ([]domain.IrcQuarantinedAnnounce, error) {
+	handler, err := s.handlerByID(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.Quarantined(), nil
+}
+
+// ReleaseQuarantined processes the quarantined announces of the network
+func (s *service) ReleaseQuarantined(ctx context.Context, networkID int64) (int, error) {
+	handler, err := s.handlerByID(networkID)
+	if err != nil {
+		return 0, err
+	}
+
+	return handler.ReleaseQuarantine(), nil
+}
+
+// DropQuarantined discards the quarantined announces of the network
+func (s *service) DropQuarantined(ctx context.Context, networkID int64) (int, error) {
+	handler, err := s.handlerByID(networkID)
+	if err != nil {
+		return 0, err
+	}
+
+	return handler.DropQuarantine(), nil
+}
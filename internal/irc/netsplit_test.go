@@ -0,0 +1,176 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_detectSplit(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type quit struct {
+		reason string
+		after  time.Duration
+	}
+
+	tests := []struct {
+		name  string
+		quits []quit
+		want  bool
+	}{
+		{
+			name:  "split_reason",
+			quits: []quit{{reason: "irc.hub.net leaf-1.tracker.org"}},
+			want:  true,
+		},
+		{
+			name:  "quit_message",
+			quits: []quit{{reason: "Quit: leaving"}},
+			want:  false,
+		},
+		{
+			name:  "single_server_name",
+			quits: []quit{{reason: "irc.hub.net"}},
+			want:  false,
+		},
+		{
+			name: "mass_quit",
+			quits: []quit{
+				{reason: "Ping timeout", after: 0},
+				{reason: "Ping timeout", after: 2 * time.Second},
+				{reason: "Ping timeout", after: 4 * time.Second},
+				{reason: "Ping timeout", after: 6 * time.Second},
+				{reason: "Ping timeout", after: 8 * time.Second},
+			},
+			want: true,
+		},
+		{
+			name: "quits_spread_out",
+			quits: []quit{
+				{reason: "Ping timeout", after: 0},
+				{reason: "Ping timeout", after: 5 * time.Second},
+				{reason: "Ping timeout", after: 11 * time.Second},
+				{reason: "Ping timeout", after: 16 * time.Second},
+				{reason: "Ping timeout", after: 22 * time.Second},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestHandler(domain.IrcNetwork{Server: "irc.tracker.org"}, nil)
+
+			var got bool
+			for _, q := range tt.quits {
+				got = h.detectSplit(q.reason, now.Add(q.after))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHandler_quarantine(t *testing.T) {
+	multiLine := newTestDefinition("multi", []string{"#multi"}, []string{"Bot"})
+	multiLine.IRC.Parse = &domain.IndexerIRCParse{
+		Lines: []domain.IndexerIRCParseLine{{}, {}},
+	}
+
+	definitions := []*domain.IndexerDefinition{
+		newTestDefinition("single", []string{"#single"}, []string{"Bot"}),
+		multiLine,
+	}
+
+	lines := []domain.IrcQuarantinedAnnounce{
+		{Channel: "#single", Source: "Bot", Message: "New: Show.S01E01-GROUP"},
+		{Channel: "#single", Source: "Bot", Message: "New: Show.S01E01-GROUP"},
+		{Channel: "#single", Source: "Bot", Message: "New: Show.S01E02-GROUP"},
+		{Channel: "#multi", Source: "Bot", Message: "Name: Album"},
+		{Channel: "#multi", Source: "Bot", Message: "Name: Album"},
+	}
+
+	tests := []struct {
+		name            string
+		network         domain.IrcNetwork
+		quarantined     bool
+		drop            bool
+		wantReleased    int
+		wantDropped     int
+		wantProcessed   map[string][]string
+		wantQuarantined int
+	}{
+		{
+			name:          "disabled",
+			network:       domain.IrcNetwork{SplitQuarantine: 0},
+			quarantined:   false,
+			wantProcessed: map[string][]string{},
+		},
+		{
+			name:            "delay_release",
+			network:         domain.IrcNetwork{SplitQuarantine: 60, SplitAction: domain.IrcSplitActionDelay},
+			quarantined:     true,
+			wantReleased:    4,
+			wantQuarantined: 5,
+			wantProcessed: map[string][]string{
+				"single": {"New: Show.S01E01-GROUP", "New: Show.S01E02-GROUP"},
+				"multi":  {"Name: Album", "Name: Album"},
+			},
+		},
+		{
+			name:            "hold_drop",
+			network:         domain.IrcNetwork{SplitQuarantine: 60, SplitAction: domain.IrcSplitActionHold},
+			quarantined:     true,
+			drop:            true,
+			wantDropped:     5,
+			wantQuarantined: 5,
+			wantProcessed:   map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mocks := newTestHandler(tt.network, definitions)
+
+			h.startQuarantine(time.Now())
+
+			for _, l := range lines {
+				assert.Equal(t, tt.quarantined, h.quarantine(l.Channel, l.Source, l.Message, time.Now()))
+			}
+
+			assert.Len(t, h.Quarantined(), tt.wantQuarantined)
+
+			if tt.drop {
+				assert.Equal(t, tt.wantDropped, h.DropQuarantine())
+			} else {
+				assert.Equal(t, tt.wantReleased, h.ReleaseQuarantine())
+			}
+
+			assert.Empty(t, h.Quarantined())
+
+			for identifier, mock := range mocks {
+				assert.Equal(t, tt.wantProcessed[identifier], mock.lines, identifier)
+			}
+		})
+	}
+}
+
+func TestHandler_quarantine_holdOutlastsSplit(t *testing.T) {
+	h, _ := newTestHandler(domain.IrcNetwork{SplitQuarantine: 60, SplitAction: domain.IrcSplitActionHold}, nil)
+
+	h.startQuarantine(time.Now())
+	assert.True(t, h.quarantine("#announce", "Bot", "first", time.Now()))
+
+	// the split is over, but held announces keep later ones quarantined until they are released or dropped
+	h.quarantineUntil = time.Now().Add(-time.Second)
+	assert.True(t, h.quarantine("#announce", "Bot", "second", time.Now()))
+	assert.Len(t, h.Quarantined(), 2)
+
+	assert.Equal(t, 2, h.DropQuarantine())
+	assert.False(t, h.quarantine("#announce", "Bot", "third", time.Now()))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// ircIdent returns the ident sent with USER, networks without one keep using the NickServ account
+func ircIdent(network *domain.IrcNetwork) string {
+	if network.Ident != "" {
+		return network.Ident
+	}
+
+	return network.Auth.Account
+}
+
+// ircRealName returns the realname (gecos) sent with USER
+func ircRealName(network *domain.IrcNetwork) string {
+	if network.RealName != "" {
+		return network.RealName
+	}
+
+	return network.Auth.Account
+}
+
+// performCommands returns the raw commands of the network to send once authenticated, one per line.
+// $nick is replaced with the current nick, eg. "MODE $nick +x" or "HS ON" to enable a vhost.
+func performCommands(network *domain.IrcNetwork, nick string) []string {
+	var commands []string
+
+	for _, line := range strings.Split(network.PerformCommands, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		commands = append(commands, strings.ReplaceAll(line, "$nick", nick))
+	}
+
+	return commands
+}
+
+// perform sends the perform commands of the network, before the invite command and joins so a vhost
+// or user modes are set before entering the channels
+func (h *Handler) perform() {
+	for _, cmd := range performCommands(h.network, h.client.CurrentNick()) {
+		h.log.Debug().Msgf("sending perform command: %s", cmd)
+
+		if err := h.client.SendRaw(cmd); err != nil {
+			h.log.Error().Err(err).Msgf("error sending perform command: %s", cmd)
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// reconnectDialer wraps the dialer of the connection to apply the reconnect policy of the network.
+// ircevent reconnects with a fixed delay, so every attempt sets the delay before the next one from the backoff
+// and picks the next server address when alternative servers are set.
+func (h *Handler) reconnectDialer(dial dialFunc, addrs []string) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		attempt := h.nextConnectAttempt()
+
+		if len(addrs) > 1 {
+			addr = addrs[(attempt-1)%len(addrs)]
+		}
+
+		// the dialer runs on the goroutine of the connect loop which reads the delay
+		h.client.ReconnectFreq = h.network.ReconnectBackoff(attempt, rand.Int63n)
+
+		if attempt > 1 {
+			h.log.Debug().Msgf("connect attempt %d to %s, next attempt in %s", attempt, addr, h.client.ReconnectFreq)
+		}
+
+		if h.network.ReconnectAlertAttempts > 0 && attempt == h.network.ReconnectAlertAttempts+1 {
+			h.notificationService.Send(domain.NotificationEventIRCDisconnected, domain.NotificationPayload{
+				Subject: "IRC Reconnect failing",
+				Message: fmt.Sprintf("Network: %s could not connect after %d attempts, still trying", h.network.Name, h.network.ReconnectAlertAttempts),
+			})
+		}
+
+		return dial(ctx, network, addr)
+	}
+}
+
+// nextConnectAttempt counts the connect attempts since the last successful connection, starting at 1
+func (h *Handler) nextConnectAttempt() int {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.connectAttempts++
+
+	return h.connectAttempts
+}
+
+func (h *Handler) resetConnectAttempts() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.connectAttempts = 0
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"time"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// nickRegainTimeout is how long to wait for the preferred nick before joining with the altered nick
+const nickRegainTimeout = 20 * time.Second
+
+const (
+	nickRegainRegain = "REGAIN"
+	nickRegainGhost  = "GHOST"
+)
+
+// regainNick asks NickServ to free the preferred nick when the server gave us an altered one, usually because
+// the connection from before a crash is still around. Joining is held until the nick is back, so invites and
+// channel access bound to the nick keep working. Returns false when there is nothing to regain.
+func (h *Handler) regainNick() bool {
+	preferred := h.PreferredNick()
+	if h.CurrentNick() == preferred || h.network.Auth.Password == "" {
+		return false
+	}
+
+	h.m.Lock()
+	h.regainingNick = true
+	h.m.Unlock()
+
+	h.log.Info().Msgf("nick %s is in use, trying to regain it with NickServ", preferred)
+
+	h.sendNickRegain(nickRegainRegain)
+
+	time.AfterFunc(nickRegainTimeout, func() {
+		if !h.stopRegainingNick() {
+			return
+		}
+
+		h.log.Warn().Msgf("could not regain nick %s, continuing as %s", preferred, h.CurrentNick())
+		h.authenticate()
+	})
+
+	return true
+}
+
+// sendNickRegain sends REGAIN, or GHOST for services without it, for the preferred nick
+func (h *Handler) sendNickRegain(method string) {
+	h.m.Lock()
+	h.regainMethod = method
+	h.m.Unlock()
+
+	m := ircmsg.Message{
+		Command: "PRIVMSG",
+		Params:  []string{"NickServ", method + " " + h.PreferredNick() + " " + h.network.Auth.Password},
+	}
+
+	h.log.Debug().Msgf("NickServ: %s %s", method, h.PreferredNick())
+
+	if err := h.client.SendIRCMessage(m); err != nil {
+		h.log.Error().Stack().Err(err).Msgf("error sending nickserv %s", method)
+	}
+}
+
+// handleNickRegainNotice handles the NickServ replies to REGAIN and GHOST. Returns true if the notice was handled.
+func (h *Handler) handleNickRegainNotice(notice string) bool {
+	h.m.RLock()
+	regaining, method := h.regainingNick, h.regainMethod
+	h.m.RUnlock()
+
+	if !regaining {
+		return false
+	}
+
+	// REGAIN is not supported by all services, fall back to GHOST and change the nick ourselves
+	if method == nickRegainRegain && contains(notice, "unknown command", "is not a valid command", "no such command") {
+		h.sendNickRegain(nickRegainGhost)
+		return true
+	}
+
+	if method == nickRegainGhost && contains(notice, "has been ghosted", "has been killed", "ghost with your nick") {
+		if err := h.NickChange(h.PreferredNick()); err != nil {
+			h.log.Error().Err(err).Msg("could not change nick after ghost")
+		}
+		return true
+	}
+
+	return false
+}
+
+// onNickRegained continues the connection once the server gave us the preferred nick back
+func (h *Handler) onNickRegained() {
+	if h.CurrentNick() != h.PreferredNick() || !h.stopRegainingNick() {
+		return
+	}
+
+	h.log.Info().Msgf("regained nick %s", h.PreferredNick())
+}
+
+// stopRegainingNick clears the regain state and returns true if a regain was in progress
+func (h *Handler) stopRegainingNick() bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if !h.regainingNick {
+		return false
+	}
+
+	h.regainingNick = false
+	h.regainMethod = ""
+
+	return true
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/announce"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// maxReplayLines is the max amount of logged lines a replay reads
+const maxReplayLines = 1000
+
+// ReplayAnnounces parses the last logged lines of a channel like live announces. With dry run the releases are only
+// checked against the filters, otherwise they are processed to recover announces missed while disconnected.
+func (s *service) ReplayAnnounces(ctx context.Context, req domain.IrcReplayRequest) (*domain.IrcReplayResult, error) {
+	if req.Channel == "" {
+		return nil, errors.New("channel required")
+	}
+
+	if req.Lines <= 0 || req.Lines > maxReplayLines {
+		return nil, errors.New("lines must be between 1 and %d", maxReplayLines)
+	}
+
+	network, err := s.repo.GetNetworkByID(ctx, req.NetworkID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find network: %d", req.NetworkID)
+	}
+
+	definitions := s.channelDefinitions(network.Server, req.Channel)
+	if len(definitions) == 0 {
+		return nil, errors.New("no indexer announces in channel %s on %s", req.Channel, network.Server)
+	}
+
+	logged, _, err := s.logRepo.Find(ctx, domain.IrcLogQueryParams{
+		NetworkID: req.NetworkID,
+		Channel:   req.Channel,
+		Limit:     uint64(req.Lines),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find logged lines")
+	}
+
+	channels, err := s.repo.ListChannels(network.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find channels")
+	}
+	network.Channels = channels
+
+	// the log is newest first, announces are parsed in the order they were sent by the indexer of the announcer
+	lines := map[string][]domain.IrcLogLine{}
+	total := 0
+	for i := len(logged) - 1; i >= 0; i-- {
+		def := announcerDefinition(definitions, logged[i].Nick)
+		if def == nil || !domain.MatchAnnouncer(channelAnnouncers(network, def, req.Channel), logged[i].Nick) {
+			continue
+		}
+
+		lines[def.Identifier] = append(lines[def.Identifier], logged[i])
+		total++
+	}
+
+	result := &domain.IrcReplayResult{
+		Channel:   req.Channel,
+		DryRun:    req.DryRun,
+		Lines:     total,
+		Announces: []domain.IrcReplayAnnounce{},
+	}
+
+	identifiers := make([]string, 0, len(definitions))
+	for _, def := range definitions {
+		identifiers = append(identifiers, def.Identifier)
+
+		announces, err := s.parseLoggedAnnounces(ctx, def, lines[def.Identifier], req.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Announces = append(result.Announces, announces...)
+	}
+
+	result.Indexer = strings.Join(identifiers, ", ")
+
+	// announces of indexers sharing the channel are listed in the order they were sent
+	sort.SliceStable(result.Announces, func(i, j int) bool {
+		return result.Announces[i].Time.Before(result.Announces[j].Time)
+	})
+
+	s.log.Info().Msgf("replayed %d announces from %d lines of %s (dry-run: %t)", len(result.Announces), total, req.Channel, req.DryRun)
+
+	return result, nil
+}
+
+// parseLoggedAnnounces parses announce lines with the definition like the announce processor, assembling or
+// windowing them, and dry runs or processes the parsed releases
+func (s *service) parseLoggedAnnounces(ctx context.Context, def *domain.IndexerDefinition, lines []domain.IrcLogLine, dryRun bool) ([]domain.IrcReplayAnnounce, error) {
+	announces := []domain.IrcReplayAnnounce{}
+
+	if def.IRC.Parse.Assemble != nil {
+		assembled, err := announce.AssembleLines(def, lines)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not assemble announces")
+		}
+
+		for _, a := range assembled {
+			parsed, err := announce.ParseAssembled(s.log, def, a.Line)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not parse announce")
+			}
+
+			if !parsed.Matched || parsed.Release == nil {
+				continue
+			}
+
+			replayed, err := s.replayRelease(ctx, dryRun, parsed.Release, a.Timestamp, a.Lines)
+			if err != nil {
+				return nil, err
+			}
+
+			announces = append(announces, replayed)
+		}
+	} else {
+		size := len(def.IRC.Parse.Lines)
+
+		for i := 0; i+size <= len(lines); {
+			window := make([]string, 0, size)
+			for _, line := range lines[i : i+size] {
+				window = append(window, line.Message)
+			}
+
+			parsed, err := announce.ParseLines(s.log, def, window)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not parse announce")
+			}
+
+			// multi line announces can be interrupted, so look for the next announce from the following line
+			if !parsed.Matched || parsed.Release == nil {
+				i++
+				continue
+			}
+
+			replayed, err := s.replayRelease(ctx, dryRun, parsed.Release, lines[i].Timestamp, window)
+			if err != nil {
+				return nil, err
+			}
+
+			announces = append(announces, replayed)
+
+			i += size
+		}
+	}
+
+	return announces, nil
+}
+
+// channelDefinitions returns the indexer definitions that announce in the channel of the server
+func (s *service) channelDefinitions(server string, channel string) []*domain.IndexerDefinition {
+	var definitions []*domain.IndexerDefinition
+	for _, def := range s.indexerService.GetIndexersByIRCNetwork(server) {
+		if def.IRC == nil || def.IRC.Parse == nil {
+			continue
+		}
+
+		for _, c := range def.IRC.Channels {
+			if strings.EqualFold(c, channel) {
+				definitions = append(definitions, def)
+				break
+			}
+		}
+	}
+
+	return definitions
+}
+
+// announcerDefinition returns the definition of the indexer the nick announces for, the same way the announce
+// processors route lines in channels shared by indexers. The only indexer of a channel takes any announcer.
+func announcerDefinition(definitions []*domain.IndexerDefinition, nick string) *domain.IndexerDefinition {
+	if len(definitions) == 1 {
+		return definitions[0]
+	}
+
+	for _, def := range definitions {
+		if domain.MatchAnnouncer(def.IRC.Announcers, nick) {
+			return def
+		}
+	}
+
+	return nil
+}
+
+// channelAnnouncers returns the announcers of the channel in the network settings or else the ones of the definition
+func channelAnnouncers(network *domain.IrcNetwork, def *domain.IndexerDefinition, channel string) []string {
+	for _, c := range network.Channels {
+		if strings.EqualFold(c.Name, channel) {
+			if announcers := c.AnnouncerList(); len(announcers) > 0 {
+				return announcers
+			}
+			break
+		}
+	}
+
+	return def.IRC.Announcers
+}
+
+// replayRelease dry runs or processes a release parsed from logged lines
+func (s *service) replayRelease(ctx context.Context, dryRun bool, rls *domain.Release, timestamp time.Time, lines []string) (domain.IrcReplayAnnounce, error) {
+	rls.Timestamp = timestamp
+
+	replayed := domain.IrcReplayAnnounce{
+		Time:    timestamp,
+		Lines:   lines,
+		Release: rls,
+	}
+
+	if dryRun {
+		results, err := s.filterService.DryRun(ctx, rls)
+		if err != nil {
+			return replayed, errors.Wrap(err, "could not dry run release: %s", rls.TorrentName)
+		}
+
+		replayed.Results = results
+	} else {
+		go s.releaseService.Process(rls)
+		replayed.Processed = true
+	}
+
+	return replayed, nil
+}
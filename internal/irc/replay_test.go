@@ -0,0 +1,34 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_announcerDefinition(t *testing.T) {
+	trackerA := newTestDefinition("tracker-a", []string{"#announce"}, []string{"BotA"})
+	trackerB := newTestDefinition("tracker-b", []string{"#announce"}, []string{"BotB!*@bots.tracker-b.org"})
+
+	tests := []struct {
+		name        string
+		definitions []*domain.IndexerDefinition
+		nick        string
+		want        *domain.IndexerDefinition
+	}{
+		{name: "only_indexer", definitions: []*domain.IndexerDefinition{trackerA}, nick: "CustomBot", want: trackerA},
+		{name: "first_indexer", definitions: []*domain.IndexerDefinition{trackerA, trackerB}, nick: "bota", want: trackerA},
+		{name: "second_indexer", definitions: []*domain.IndexerDefinition{trackerA, trackerB}, nick: "BotB", want: trackerB},
+		{name: "no_indexer", definitions: []*domain.IndexerDefinition{trackerA, trackerB}, nick: "BotC", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, announcerDefinition(tt.definitions, tt.nick))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/net/proxy"
+)
+
+// clientCertificate returns the client certificate of the network used for CertFP
+func clientCertificate(network *domain.IrcNetwork) (*tls.Certificate, error) {
+	if network.TLSCert == "" && network.TLSKey == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(network.TLSCert), []byte(network.TLSKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load client certificate")
+	}
+
+	return &cert, nil
+}
+
+// externalSASLConn rewrites the SASL PLAIN exchange of ircevent into SASL EXTERNAL.
+// ircevent only implements PLAIN, with EXTERNAL the server takes the account from the client certificate
+// so the credentials are replaced with an empty response.
+type externalSASLConn struct {
+	net.Conn
+}
+
+func (c *externalSASLConn) Write(b []byte) (int, error) {
+	line := bytes.TrimRight(b, "\r\n")
+
+	switch {
+	case bytes.Equal(line, []byte("AUTHENTICATE PLAIN")):
+		if _, err := c.Conn.Write([]byte("AUTHENTICATE EXTERNAL\r\n")); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+
+	case bytes.HasPrefix(line, []byte("AUTHENTICATE ")):
+		if _, err := c.Conn.Write([]byte("AUTHENTICATE +\r\n")); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	return c.Conn.Write(b)
+}
+
+// dialExternalSASL dials the server with TLS itself so the SASL exchange can be rewritten before it is encrypted
+func dialExternalSASL(dialer proxy.ContextDialer, tlsConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return &externalSASLConn{Conn: tlsConn}, nil
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// offlineScheduleInterval is how often the offline schedules of the networks are checked
+const offlineScheduleInterval = time.Minute
+
+// inOfflineWindow reports whether the offline schedule of the network disconnects it at t.
+// The schedule is validated when the network is stored, so an invalid one keeps the network online.
+func inOfflineWindow(network *domain.IrcNetwork, t time.Time) bool {
+	if network.OfflineSchedule == "" {
+		return false
+	}
+
+	schedule, err := domain.ParseFilterSchedule(network.OfflineSchedule)
+	if err != nil {
+		return false
+	}
+
+	return schedule.Active(t)
+}
+
+// ScheduledOffline reports whether the network is disconnected by its offline schedule
+func (h *Handler) ScheduledOffline() bool {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	return h.scheduledOffline
+}
+
+func (h *Handler) setScheduledOffline(offline bool) {
+	h.m.Lock()
+	h.scheduledOffline = offline
+	h.m.Unlock()
+}
+
+// goOffline parts the channels and quits when an offline window of the schedule starts
+func (h *Handler) goOffline() {
+	h.setScheduledOffline(true)
+
+	h.log.Info().Msgf("disconnecting from %s for its offline schedule", h.network.Name)
+
+	if h.connected() {
+		for _, channel := range h.GetNetwork().Channels {
+			if err := h.PartChannel(channel.Name); err != nil {
+				h.log.Error().Err(err).Msgf("could not part channel %s", channel.Name)
+			}
+		}
+	}
+
+	// also stops reconnect attempts of a network that lost its connection
+	h.Stop()
+}
+
+// goOnline connects again when the offline window of the schedule ends
+func (h *Handler) goOnline() error {
+	h.setScheduledOffline(false)
+
+	h.log.Info().Msgf("connecting to %s after its offline schedule", h.network.Name)
+
+	return h.Run()
+}
+
+// runOfflineSchedules disconnects and reconnects the networks on their offline schedules until ctx is done
+func (s *service) runOfflineSchedules(ctx context.Context) {
+	ticker := time.NewTicker(offlineScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkOfflineSchedules(now)
+		}
+	}
+}
+
+func (s *service) checkOfflineSchedules(now time.Time) {
+	s.lock.RLock()
+	handlers := make([]*Handler, 0, len(s.handlers))
+	for _, handler := range s.handlers {
+		handlers = append(handlers, handler)
+	}
+	s.lock.RUnlock()
+
+	for _, handler := range handlers {
+		network := handler.GetNetwork()
+		if !network.Enabled {
+			continue
+		}
+
+		offline := inOfflineWindow(network, now)
+
+		switch {
+		case offline && !handler.ScheduledOffline():
+			handler.goOffline()
+
+		case !offline && handler.ScheduledOffline():
+			go func(handler *Handler) {
+				if err := handler.goOnline(); err != nil {
+					s.log.Error().Err(err).Msgf("failed to start handler for network: %s", handler.network.Name)
+				}
+			}(handler)
+		}
+	}
+}
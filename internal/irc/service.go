@@ -11,13 +11,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/announce"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/r3labs/sse/v2"
 	"github.com/rs/zerolog"
 )
@@ -37,6 +40,19 @@ type Service interface {
 	UpdateNetwork(ctx context.Context, network *domain.IrcNetwork) error
 	StoreChannel(ctx context.Context, networkID int64, channel *domain.IrcChannel) error
 	SendCmd(ctx context.Context, req *domain.SendIrcCmdRequest) error
+	SendRawCmd(ctx context.Context, req *domain.SendIrcRawCmdRequest) error
+	TestAnnounce(ctx context.Context, req domain.AnnounceTestRequest) (*domain.AnnounceTestResult, error)
+	FindLogs(ctx context.Context, params domain.IrcLogQueryParams) (*domain.IrcLogResponse, error)
+	ReplayAnnounces(ctx context.Context, req domain.IrcReplayRequest) (*domain.IrcReplayResult, error)
+	InjectAnnounce(ctx context.Context, req domain.IrcInjectRequest) (*domain.IrcInjectResult, error)
+	ListTriggers(ctx context.Context, networkID int64) ([]domain.IrcTrigger, error)
+	StoreTrigger(ctx context.Context, trigger *domain.IrcTrigger) error
+	UpdateTrigger(ctx context.Context, trigger *domain.IrcTrigger) error
+	DeleteTrigger(ctx context.Context, id int64) error
+	ListQuarantined(ctx context.Context, networkID int64) ([]domain.IrcQuarantinedAnnounce, error)
+	ReleaseQuarantined(ctx context.Context, networkID int64) (int, error)
+	DropQuarantined(ctx context.Context, networkID int64) (int, error)
+	ReloadDefinitions()
 }
 
 type service struct {
@@ -44,11 +60,17 @@ type service struct {
 	sse *sse.Server
 
 	repo                domain.IrcRepo
+	logRepo             domain.IrcLogRepo
+	triggerRepo         domain.IrcTriggerRepo
 	releaseService      release.Service
+	filterService       filter.Service
 	indexerService      indexer.Service
 	notificationService notification.Service
+	bus                 EventBus.Bus
 	indexerMap          map[string]string
 	handlers            map[int64]*Handler
+	history             *historyWriter
+	stopHistory         context.CancelFunc
 
 	stopWG sync.WaitGroup
 	lock   sync.RWMutex
@@ -56,19 +78,32 @@ type service struct {
 
 const sseMaxEntries = 1000
 
-func NewService(log logger.Logger, sse *sse.Server, repo domain.IrcRepo, releaseSvc release.Service, indexerSvc indexer.Service, notificationSvc notification.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, sse *sse.Server, repo domain.IrcRepo, logRepo domain.IrcLogRepo, triggerRepo domain.IrcTriggerRepo, releaseSvc release.Service, filterSvc filter.Service, indexerSvc indexer.Service, notificationSvc notification.Service, bus EventBus.Bus) Service {
+	l := log.With().Str("module", "irc").Logger()
+
 	return &service{
-		log:                 log.With().Str("module", "irc").Logger(),
+		log:                 l,
 		sse:                 sse,
 		repo:                repo,
+		logRepo:             logRepo,
+		triggerRepo:         triggerRepo,
+		history:             newHistoryWriter(l, logRepo, config),
 		releaseService:      releaseSvc,
+		filterService:       filterSvc,
 		indexerService:      indexerSvc,
 		notificationService: notificationSvc,
+		bus:                 bus,
 		handlers:            make(map[int64]*Handler),
 	}
 }
 
 func (s *service) StartHandlers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopHistory = cancel
+
+	go s.history.run(ctx)
+	go s.runOfflineSchedules(ctx)
+
 	networks, err := s.repo.FindActiveNetworks(context.Background())
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to list networks")
@@ -88,6 +123,7 @@ func (s *service) StartHandlers() {
 			// setup SSE stream per channel
 			s.createSSEStream(network.ID, channel.Name)
 		}
+		s.createSSEStream(network.ID, consoleChannel)
 
 		// find indexer definitions for network and add
 		definitions := s.indexerService.GetIndexersByIRCNetwork(network.Server)
@@ -96,13 +132,20 @@ func (s *service) StartHandlers() {
 		network.Channels = channels
 
 		// init new irc handler
-		handler := NewHandler(s.log, s.sse, network, definitions, s.releaseService, s.notificationService)
+		handler := NewHandler(s.log, s.sse, network, definitions, s.releaseService, s.filterService, s.notificationService, s.bus, s.history)
+		s.loadTriggers(context.Background(), handler, network.ID)
 
 		// use network.Server + nick to use multiple indexers with different nick per network
 		// this allows for multiple handlers to one network
 		s.handlers[network.ID] = handler
 		s.lock.Unlock()
 
+		if inOfflineWindow(&network, time.Now()) {
+			s.log.Info().Msgf("network %s is offline by schedule", network.Name)
+			handler.setScheduledOffline(true)
+			continue
+		}
+
 		s.log.Debug().Msgf("starting network: %s", network.Name)
 
 		go func(network domain.IrcNetwork) {
@@ -119,15 +162,26 @@ func (s *service) StopHandlers() {
 		handler.Stop()
 	}
 
+	if s.stopHistory != nil {
+		s.stopHistory()
+	}
+
 	s.log.Info().Msg("stopped all irc handlers")
 }
 
 func (s *service) startNetwork(network domain.IrcNetwork) error {
 	// look if we have the network in handlers already, if so start it
 	if existingHandler, found := s.handlers[network.ID]; found {
+		if inOfflineWindow(&network, time.Now()) {
+			s.log.Info().Msgf("network %s is offline by schedule", network.Name)
+			existingHandler.SetNetwork(&network)
+			existingHandler.setScheduledOffline(true)
+			return nil
+		}
+
 		s.log.Debug().Msgf("starting network: %s", network.Name)
 
-		if !existingHandler.client.Connected() {
+		if !existingHandler.connected() {
 			go func(handler *Handler) {
 				if err := handler.Run(); err != nil {
 					s.log.Error().Err(err).Msgf("failed to start existing handler for network: %s", handler.network.Name)
@@ -145,6 +199,7 @@ func (s *service) startNetwork(network domain.IrcNetwork) error {
 			// setup SSE stream per channel
 			s.createSSEStream(network.ID, channel.Name)
 		}
+		s.createSSEStream(network.ID, consoleChannel)
 
 		// find indexer definitions for network and add
 		definitions := s.indexerService.GetIndexersByIRCNetwork(network.Server)
@@ -153,11 +208,18 @@ func (s *service) startNetwork(network domain.IrcNetwork) error {
 		network.Channels = channels
 
 		// init new irc handler
-		handler := NewHandler(s.log, s.sse, network, definitions, s.releaseService, s.notificationService)
+		handler := NewHandler(s.log, s.sse, network, definitions, s.releaseService, s.filterService, s.notificationService, s.bus, s.history)
+		s.loadTriggers(context.Background(), handler, network.ID)
 
 		s.handlers[network.ID] = handler
 		s.lock.Unlock()
 
+		if inOfflineWindow(&network, time.Now()) {
+			s.log.Info().Msgf("network %s is offline by schedule", network.Name)
+			handler.setScheduledOffline(true)
+			return nil
+		}
+
 		s.log.Debug().Msgf("starting network: %s", network.Name)
 
 		go func(network domain.IrcNetwork) {
@@ -178,7 +240,7 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 		// if server, tls, invite command, port : changed - restart
 		// if nickserv account, nickserv password : changed - stay connected, and change those
 		// if channels len : changes - join or leave
-		if existingHandler.client.Connected() {
+		if existingHandler.connected() {
 			handler := existingHandler.GetNetwork()
 			restartNeeded := false
 			var fieldsChanged []string
@@ -211,6 +273,66 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 				restartNeeded = true
 				fieldsChanged = append(fieldsChanged, "bouncer addr")
 			}
+			if handler.TLSCert != network.TLSCert {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "tls cert")
+			}
+			if handler.TLSKey != network.TLSKey {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "tls key")
+			}
+			if handler.Proxy != network.Proxy {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "proxy")
+			}
+			if handler.ReconnectDelay != network.ReconnectDelay {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "reconnect delay")
+			}
+			if handler.ReconnectMaxDelay != network.ReconnectMaxDelay {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "reconnect max delay")
+			}
+			if handler.ReconnectJitter != network.ReconnectJitter {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "reconnect jitter")
+			}
+			if handler.ReconnectAlertAttempts != network.ReconnectAlertAttempts {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "reconnect alert attempts")
+			}
+			if handler.AltServers != network.AltServers {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "alt servers")
+			}
+			if handler.SendBurst != network.SendBurst {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "send_burst")
+			}
+			if handler.SendDelay != network.SendDelay {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "send_delay")
+			}
+			if handler.TLSMinVersion != network.TLSMinVersion {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "tls_min_version")
+			}
+			if handler.TLSCA != network.TLSCA {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "tls_ca")
+			}
+			if handler.TLSSkipVerify != network.TLSSkipVerify {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "tls_skip_verify")
+			}
+			if handler.Ident != network.Ident {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "ident")
+			}
+			if handler.RealName != network.RealName {
+				restartNeeded = true
+				fieldsChanged = append(fieldsChanged, "realname")
+			}
 			if handler.Auth.Mechanism != network.Auth.Mechanism {
 				restartNeeded = true
 				fieldsChanged = append(fieldsChanged, "auth mechanism")
@@ -321,6 +443,9 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 			definitions := s.indexerService.GetIndexersByIRCNetwork(network.Server)
 
 			existingHandler.InitIndexers(definitions)
+		} else if existingHandler.ScheduledOffline() {
+			// the schedule is checked against the network of the handler, so keep it up to date while offline
+			existingHandler.SetNetwork(network)
 		}
 	} else {
 		if err := s.startNetwork(*network); err != nil {
@@ -364,6 +489,7 @@ func (s *service) StopAndRemoveNetwork(id int64) error {
 		for _, channel := range handler.network.Channels {
 			s.removeSSEStream(handler.network.ID, channel.Name)
 		}
+		s.removeSSEStream(handler.network.ID, consoleChannel)
 
 		handler.Stop()
 
@@ -435,21 +561,42 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 
 	for _, n := range networks {
 		netw := domain.IrcNetworkWithHealth{
-			ID:               n.ID,
-			Name:             n.Name,
-			Enabled:          n.Enabled,
-			Server:           n.Server,
-			Port:             n.Port,
-			TLS:              n.TLS,
-			Pass:             n.Pass,
-			Nick:             n.Nick,
-			Auth:             n.Auth,
-			InviteCommand:    n.InviteCommand,
-			BouncerAddr:      n.BouncerAddr,
-			UseBouncer:       n.UseBouncer,
-			Connected:        false,
-			Channels:         []domain.ChannelWithHealth{},
-			ConnectionErrors: []string{},
+			ID:                     n.ID,
+			Name:                   n.Name,
+			Enabled:                n.Enabled,
+			Server:                 n.Server,
+			Port:                   n.Port,
+			TLS:                    n.TLS,
+			Pass:                   n.Pass,
+			Nick:                   n.Nick,
+			Auth:                   n.Auth,
+			InviteCommand:          n.InviteCommand,
+			BouncerAddr:            n.BouncerAddr,
+			UseBouncer:             n.UseBouncer,
+			TLSCert:                n.TLSCert,
+			TLSKeySet:              n.TLSKey != "",
+			Proxy:                  n.Proxy,
+			ReconnectDelay:         n.ReconnectDelay,
+			ReconnectMaxDelay:      n.ReconnectMaxDelay,
+			ReconnectJitter:        n.ReconnectJitter,
+			ReconnectAlertAttempts: n.ReconnectAlertAttempts,
+			AltServers:             n.AltServers,
+			SendBurst:              n.SendBurst,
+			SendDelay:              n.SendDelay,
+			TLSMinVersion:          n.TLSMinVersion,
+			TLSCA:                  n.TLSCA,
+			TLSSkipVerify:          n.TLSSkipVerify,
+			ShareConnection:        n.ShareConnection,
+			OfflineSchedule:        n.OfflineSchedule,
+			Ident:                  n.Ident,
+			RealName:               n.RealName,
+			PerformCommands:        n.PerformCommands,
+			SplitQuarantine:        n.SplitQuarantine,
+			SplitAction:            n.SplitAction,
+			Encoding:               n.Encoding,
+			Connected:              false,
+			Channels:               []domain.ChannelWithHealth{},
+			ConnectionErrors:       []string{},
 		}
 
 		handler, ok := s.handlers[n.ID]
@@ -457,7 +604,7 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 			handler.m.RLock()
 
 			// only set connected and connected since if we have an active handler and connection
-			if handler.client.Connected() {
+			if handler.connected() {
 
 				netw.Connected = handler.connectedSince != time.Time{}
 				netw.ConnectedSince = handler.connectedSince
@@ -465,8 +612,11 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 				// current and preferred nick is only available if the network is connected
 				netw.CurrentNick = handler.CurrentNick()
 				netw.PreferredNick = handler.PreferredNick()
+				netw.LagMs = handler.lag.Milliseconds()
 			}
 			netw.Healthy = handler.Healthy()
+			netw.ScheduledOffline = handler.scheduledOffline
+			netw.QuarantinedAnnounces = len(handler.quarantined)
 
 			// if we have any connection errors like bad nickserv auth add them here
 			if len(handler.connectionErrors) > 0 {
@@ -485,11 +635,12 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 		// combine from repo and handler
 		for _, channel := range channels {
 			ch := domain.ChannelWithHealth{
-				ID:       channel.ID,
-				Enabled:  channel.Enabled,
-				Name:     channel.Name,
-				Password: channel.Password,
-				Detached: channel.Detached,
+				ID:         channel.ID,
+				Enabled:    channel.Enabled,
+				Name:       channel.Name,
+				Password:   channel.Password,
+				Detached:   channel.Detached,
+				Announcers: channel.Announcers,
 				//Monitoring:      false,
 				//MonitoringSince: time.Time{},
 				//LastAnnounce:    time.Time{},
@@ -508,6 +659,8 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 					ch.LastAnnounce = chan1.lastAnnounce
 
 					chan1.m.RUnlock()
+
+					ch.AnnouncesLastHour, ch.AnnouncesTotal = chan1.announceRate()
 				}
 				handler.m.RUnlock()
 			}
@@ -545,6 +698,19 @@ func (s *service) DeleteNetwork(ctx context.Context, id int64) error {
 }
 
 func (s *service) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork) error {
+	// the api does not return the client key, an empty key with a certificate keeps the stored one
+	if network.TLSCert != "" && network.TLSKey == "" {
+		existing, err := s.repo.GetNetworkByID(ctx, network.ID)
+		if err != nil {
+			return err
+		}
+		network.TLSKey = existing.TLSKey
+	}
+
+	if err := network.Validate(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
 	if network.Channels != nil {
 		if err := s.repo.StoreNetworkChannels(ctx, network.ID, network.Channels); err != nil {
 			return err
@@ -581,6 +747,10 @@ func (s *service) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 }
 
 func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork) error {
+	if err := network.Validate(); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
 	existingNetwork, err := s.repo.CheckExistingNetwork(ctx, network)
 	if err != nil {
 		s.log.Error().Err(err).Msg("could not check for existing network")
@@ -605,6 +775,13 @@ func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 		return nil
 	}
 
+	// channels of a shared connection are joined with its nick, so invites and announcer checks tied to another nick
+	// would fail on the tracker
+	if existingNetwork.Nick != network.Nick {
+		s.log.Warn().Msgf("refusing to share connection of network %s with nick %s for nick %s", existingNetwork.Name, existingNetwork.Nick, network.Nick)
+		return errors.New("network %s on %s shares its connection with nick %s, use that nick or turn off share connection to connect as %s", existingNetwork.Name, existingNetwork.Server, existingNetwork.Nick, network.Nick)
+	}
+
 	// get channels for existing network
 	existingChannels, err := s.repo.ListChannels(existingNetwork.ID)
 	if err != nil {
@@ -664,6 +841,82 @@ func (s *service) SendCmd(ctx context.Context, req *domain.SendIrcCmdRequest) er
 	return nil
 }
 
+// SendRawCmd sends a raw command to a running network, replies are published to its console SSE stream
+func (s *service) SendRawCmd(ctx context.Context, req *domain.SendIrcRawCmdRequest) error {
+	s.lock.RLock()
+	handler, found := s.handlers[req.NetworkId]
+	s.lock.RUnlock()
+
+	if !found {
+		return errors.New("network %d is not running", req.NetworkId)
+	}
+
+	if err := handler.SendRaw(req.Command); err != nil {
+		return errors.Wrap(err, "could not send raw command")
+	}
+
+	return nil
+}
+
+// FindLogs searches the logged channel lines of a network
+func (s *service) FindLogs(ctx context.Context, params domain.IrcLogQueryParams) (*domain.IrcLogResponse, error) {
+	lines, count, err := s.logRepo.Find(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find irc logs")
+	}
+
+	return &domain.IrcLogResponse{Data: lines, Count: count}, nil
+}
+
+// TestAnnounce parses the announce lines of an indexer without processing the release. The definition of a configured
+// indexer is preferred so the download url is built with its settings.
+func (s *service) TestAnnounce(ctx context.Context, req domain.AnnounceTestRequest) (*domain.AnnounceTestResult, error) {
+	def, err := s.findDefinition(req.Indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	return announce.ParseLines(s.log, def, req.Lines)
+}
+
+// ReloadDefinitions rebinds the announce parsers of the running networks to the current indexer definitions
+func (s *service) ReloadDefinitions() {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, handler := range s.handlers {
+		network := handler.GetNetwork()
+
+		handler.ReloadIndexers(s.indexerService.GetIndexersByIRCNetwork(network.Server))
+	}
+}
+
+func (s *service) findDefinition(identifier string) (*domain.IndexerDefinition, error) {
+	definitions, err := s.indexerService.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, def := range definitions {
+		if def.Identifier == identifier {
+			return def, nil
+		}
+	}
+
+	templates, err := s.indexerService.GetTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if templates[i].Identifier == identifier {
+			return &templates[i], nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
 func (s *service) createSSEStream(networkId int64, channel string) {
 	key := genSSEKey(networkId, channel)
 
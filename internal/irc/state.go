@@ -0,0 +1,93 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// emitState publishes a change of the connection or of a channel on the event bus, which notifies the subscribers
+func (h *Handler) emitState(state domain.IrcState, channel string, reason string) {
+	network := h.GetNetwork()
+
+	h.log.Debug().Str("channel", channel).Msgf("state %s: %s", state, reason)
+
+	if h.bus == nil {
+		return
+	}
+
+	h.bus.Publish("irc:state", &domain.IrcStateEvent{
+		NetworkID: network.ID,
+		Network:   network.Name,
+		Server:    network.Server,
+		State:     state,
+		Channel:   channel,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	})
+}
+
+// onError keeps the reason the server gives before it closes the link, like a k-line, for the disconnect event
+func (h *Handler) onError(msg ircmsg.Message) {
+	if len(msg.Params) == 0 {
+		return
+	}
+
+	h.m.Lock()
+	h.lastServerError = msg.Params[len(msg.Params)-1]
+	h.m.Unlock()
+}
+
+// onKick handles KICK events, a kick from an announce channel stops the announces until it is joined again
+func (h *Handler) onKick(msg ircmsg.Message) {
+	if len(msg.Params) < 2 || !h.isOurCurrentNick(msg.Params[1]) {
+		return
+	}
+
+	channel := msg.Params[0]
+
+	reason := ""
+	if len(msg.Params) > 2 {
+		reason = msg.Params[2]
+	}
+
+	h.log.Warn().Msgf("kicked from %s by %s: %s", channel, msg.Nick(), reason)
+
+	h.m.RLock()
+	if v, ok := h.channelHealth[strings.ToLower(channel)]; ok {
+		v.resetMonitoring()
+	}
+	h.m.RUnlock()
+
+	h.emitState(domain.IrcStateKicked, channel, reason)
+}
+
+// onSASLFailed handles 904 ERR_SASLFAIL
+func (h *Handler) onSASLFailed(msg ircmsg.Message) {
+	reason := "sasl authentication failed"
+	if len(msg.Params) > 1 {
+		reason = msg.Params[len(msg.Params)-1]
+	}
+
+	h.addConnectError("authentication failed: " + reason)
+
+	h.emitState(domain.IrcStateAuthFailed, "", reason)
+}
+
+// onServerBanned handles 465 ERR_YOUREBANNEDCREEP, sent when the server refuses the connection because of a ban
+func (h *Handler) onServerBanned(msg ircmsg.Message) {
+	reason := "banned from server"
+	if len(msg.Params) > 1 {
+		reason = msg.Params[len(msg.Params)-1]
+	}
+
+	h.addConnectError(reason)
+
+	h.emitState(domain.IrcStateBanned, "", reason)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsConfig returns the tls config of the network with its min version, custom CA and client certificate.
+// Verification is only skipped when the network explicitly allows it, like for servers with self-signed certificates.
+func tlsConfig(network *domain.IrcNetwork) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: network.TLSSkipVerify,
+	}
+
+	if !network.TLSSkipVerify {
+		// set it ourselves, with sasl external or alt servers the dial does not go through ircevent
+		config.ServerName = network.Server
+	}
+
+	if network.TLSMinVersion != "" {
+		version, ok := tlsVersions[network.TLSMinVersion]
+		if !ok {
+			return nil, errors.New("unsupported tls min version: %s", network.TLSMinVersion)
+		}
+
+		config.MinVersion = version
+	}
+
+	if network.TLSCA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(network.TLSCA)) {
+			return nil, errors.New("could not load tls ca bundle")
+		}
+
+		config.RootCAs = pool
+	}
+
+	cert, err := clientCertificate(network)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+
+	return config, nil
+}
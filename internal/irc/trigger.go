@@ -0,0 +1,145 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package irc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// SetTriggers replaces the keyword triggers of the handler, invalid triggers are skipped
+func (h *Handler) SetTriggers(triggers []domain.IrcTrigger) {
+	valid := make([]domain.IrcTrigger, 0, len(triggers))
+
+	for _, trigger := range triggers {
+		if !trigger.Enabled {
+			continue
+		}
+
+		if err := trigger.Validate(); err != nil {
+			h.log.Error().Err(err).Msgf("skipping invalid trigger: %s", trigger.Name)
+			continue
+		}
+
+		valid = append(valid, trigger)
+	}
+
+	h.m.Lock()
+	h.triggers = valid
+	h.m.Unlock()
+}
+
+// runTriggers runs the triggers matching a line of a channel, which does not need to be an announce channel
+func (h *Handler) runTriggers(channel, nick, line string) {
+	h.m.RLock()
+	triggers := h.triggers
+	h.m.RUnlock()
+
+	for i := range triggers {
+		trigger := &triggers[i]
+
+		if !strings.EqualFold(trigger.Channel, channel) || !trigger.Match(line) {
+			continue
+		}
+
+		h.log.Info().Msgf("trigger %s matched in %s: %s", trigger.Name, channel, line)
+
+		switch trigger.Action {
+		case domain.IrcTriggerActionNotify:
+			h.notificationService.Send(domain.NotificationEventIRCTrigger, domain.NotificationPayload{
+				Subject: fmt.Sprintf("IRC Trigger: %s", trigger.Name),
+				Message: fmt.Sprintf("Network: %s\n%s <%s> %s", h.network.Name, channel, nick, line),
+			})
+
+		case domain.IrcTriggerActionSetFlag:
+			h.filterService.SetFlag(trigger.Flag, time.Duration(trigger.FlagDuration)*time.Minute)
+
+		case domain.IrcTriggerActionClearFlag:
+			h.filterService.ClearFlag(trigger.Flag)
+		}
+	}
+}
+
+func (s *service) ListTriggers(ctx context.Context, networkID int64) ([]domain.IrcTrigger, error) {
+	return s.triggerRepo.List(ctx, networkID)
+}
+
+func (s *service) StoreTrigger(ctx context.Context, trigger *domain.IrcTrigger) error {
+	if err := trigger.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.triggerRepo.Store(ctx, trigger); err != nil {
+		return errors.Wrap(err, "could not store trigger")
+	}
+
+	s.reloadTriggers(ctx, trigger.NetworkID)
+
+	return nil
+}
+
+func (s *service) UpdateTrigger(ctx context.Context, trigger *domain.IrcTrigger) error {
+	if err := trigger.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.triggerRepo.FindByID(ctx, trigger.ID)
+	if err != nil {
+		return err
+	}
+
+	// triggers can not be moved to another network
+	trigger.NetworkID = existing.NetworkID
+
+	if err := s.triggerRepo.Update(ctx, trigger); err != nil {
+		return errors.Wrap(err, "could not update trigger")
+	}
+
+	s.reloadTriggers(ctx, trigger.NetworkID)
+
+	return nil
+}
+
+func (s *service) DeleteTrigger(ctx context.Context, id int64) error {
+	trigger, err := s.triggerRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.triggerRepo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "could not delete trigger")
+	}
+
+	s.reloadTriggers(ctx, trigger.NetworkID)
+
+	return nil
+}
+
+// reloadTriggers sets the triggers of the network on its running handler
+func (s *service) reloadTriggers(ctx context.Context, networkID int64) {
+	s.lock.RLock()
+	handler, ok := s.handlers[networkID]
+	s.lock.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	s.loadTriggers(ctx, handler, networkID)
+}
+
+func (s *service) loadTriggers(ctx context.Context, handler *Handler, networkID int64) {
+	triggers, err := s.triggerRepo.List(ctx, networkID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("failed to list triggers for network: %d", networkID)
+		return
+	}
+
+	handler.SetTriggers(triggers)
+}
@@ -38,12 +38,19 @@ func (c *IndexerClient) GetTorrentByID(ctx context.Context, torrentID string) (*
 		Id:       torrentID,
 		InfoHash: "",
 		Size:     "10GB",
+		Seeders:  "10",
+		Leechers: "2",
 	}
 
 	return r, nil
 
 }
 
+// GetFreeleechTokens returns a fixed number of tokens
+func (c *IndexerClient) GetFreeleechTokens(ctx context.Context) (int, error) {
+	return 1, nil
+}
+
 // TestAPI try api access against torrents page
 func (c *IndexerClient) TestAPI(ctx context.Context) (bool, error) {
 	return true, nil
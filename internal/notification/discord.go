@@ -150,7 +150,15 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		color = RED
 	case domain.NotificationEventIRCDisconnected:
 		color = RED
-	case domain.NotificationEventIRCReconnected:
+	case domain.NotificationEventIRCReconnected, domain.NotificationEventIRCConnected:
+		color = GREEN
+	case domain.NotificationEventIRCAuthFailed, domain.NotificationEventIRCKicked, domain.NotificationEventIRCBanned:
+		color = RED
+	case domain.NotificationEventIRCTrigger:
+		color = LIGHT_BLUE
+	case domain.NotificationEventFeedFailing, domain.NotificationEventFeedAuthFailed:
+		color = RED
+	case domain.NotificationEventFeedRecovered:
 		color = GREEN
 	case domain.NotificationEventTest:
 		color = LIGHT_BLUE
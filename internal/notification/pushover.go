@@ -191,6 +191,22 @@ func (s *pushoverSender) buildTitle(event domain.NotificationEvent) string {
 		title = "IRC Disconnected"
 	case domain.NotificationEventIRCReconnected:
 		title = "IRC Reconnected"
+	case domain.NotificationEventIRCConnected:
+		title = "IRC Connected"
+	case domain.NotificationEventIRCAuthFailed:
+		title = "IRC Authentication Failed"
+	case domain.NotificationEventIRCKicked:
+		title = "IRC Kicked"
+	case domain.NotificationEventIRCBanned:
+		title = "IRC Banned"
+	case domain.NotificationEventIRCTrigger:
+		title = "IRC Trigger"
+	case domain.NotificationEventFeedFailing:
+		title = "Feed Failing"
+	case domain.NotificationEventFeedRecovered:
+		title = "Feed Recovered"
+	case domain.NotificationEventFeedAuthFailed:
+		title = "Feed Authentication Failed"
 	case domain.NotificationEventTest:
 		title = "Test"
 	}
@@ -132,6 +132,8 @@ func (s *service) registerSenders() {
 				s.senders = append(s.senders, NewPushoverSender(s.log, n))
 			case domain.NotificationTypeGotify:
 				s.senders = append(s.senders, NewGotifySender(s.log, n))
+			case domain.NotificationTypeWebhook:
+				s.senders = append(s.senders, NewWebhookSender(s.log, n))
 			}
 		}
 	}
@@ -228,6 +230,15 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Event:     domain.NotificationEventIRCReconnected,
 			Timestamp: time.Now(),
 		},
+		{
+			Subject:   "IRC Kicked from channel",
+			Message:   "Network: P2P-Network\nChannel: #announce\nReason: idle",
+			Event:     domain.NotificationEventIRCKicked,
+			Network:   "P2P-Network",
+			Channel:   "#announce",
+			Reason:    "idle",
+			Timestamp: time.Now(),
+		},
 		{
 			Subject:   "New update available!",
 			Message:   "v1.6.0",
@@ -247,6 +258,8 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 		agent = NewPushoverSender(s.log, notification)
 	case domain.NotificationTypeGotify:
 		agent = NewGotifySender(s.log, notification)
+	case domain.NotificationTypeWebhook:
+		agent = NewWebhookSender(s.log, notification)
 	default:
 		s.log.Error().Msgf("unsupported notification type: %v", notification.Type)
 		return errors.New("unsupported notification type")
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// webhookMessage is the JSON body posted to generic webhooks
+type webhookMessage struct {
+	Event          domain.NotificationEvent     `json:"event"`
+	Subject        string                       `json:"subject"`
+	Message        string                       `json:"message"`
+	ReleaseName    string                       `json:"release_name,omitempty"`
+	Filter         string                       `json:"filter,omitempty"`
+	Indexer        string                       `json:"indexer,omitempty"`
+	InfoHash       string                       `json:"info_hash,omitempty"`
+	Size           uint64                       `json:"size,omitempty"`
+	Status         domain.ReleasePushStatus     `json:"status,omitempty"`
+	Action         string                       `json:"action,omitempty"`
+	ActionType     domain.ActionType            `json:"action_type,omitempty"`
+	ActionClient   string                       `json:"action_client,omitempty"`
+	Rejections     []string                     `json:"rejections,omitempty"`
+	Protocol       domain.ReleaseProtocol       `json:"protocol,omitempty"`
+	Implementation domain.ReleaseImplementation `json:"implementation,omitempty"`
+	Network        string                       `json:"network,omitempty"`
+	Channel        string                       `json:"channel,omitempty"`
+	Reason         string                       `json:"reason,omitempty"`
+	Timestamp      time.Time                    `json:"timestamp"`
+}
+
+type webhookSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewWebhookSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &webhookSender{
+		log:      log.With().Str("sender", "webhook").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *webhookSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := webhookMessage{
+		Event:          event,
+		Subject:        payload.Subject,
+		Message:        payload.Message,
+		ReleaseName:    payload.ReleaseName,
+		Filter:         payload.Filter,
+		Indexer:        payload.Indexer,
+		InfoHash:       payload.InfoHash,
+		Size:           payload.Size,
+		Status:         payload.Status,
+		Action:         payload.Action,
+		ActionType:     payload.ActionType,
+		ActionClient:   payload.ActionClient,
+		Rejections:     payload.Rejections,
+		Protocol:       payload.Protocol,
+		Implementation: payload.Implementation,
+		Network:        payload.Network,
+		Channel:        payload.Channel,
+		Reason:         payload.Reason,
+		Timestamp:      payload.Timestamp,
+	}
+
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("webhook client could not marshal data: %v", m)
+		return errors.Wrap(err, "could not marshal data: %+v", m)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Settings.Webhook, bytes.NewBuffer(jsonData))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("webhook client request error: %v", event)
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	if s.Settings.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Settings.Token)
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("webhook client request error: %v", event)
+		return errors.Wrap(err, "could not make request: %+v", req)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("webhook client request error: %v", event)
+		return errors.Wrap(err, "could not read data")
+	}
+
+	defer res.Body.Close()
+
+	s.log.Trace().Msgf("webhook status: %v response: %v", res.StatusCode, string(body))
+
+	if res.StatusCode >= 300 {
+		s.log.Error().Err(err).Msgf("webhook client request error: %v", string(body))
+		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to webhook")
+
+	return nil
+}
+
+func (s *webhookSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *webhookSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *webhookSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// dedupSizeTolerance is the difference in size, relative to the larger one, up to which releases are the same
+const dedupSizeTolerance = 0.05
+
+// releaseDedup remembers the releases of the last window per indexer, to not process a release announced on IRC
+// again when it shows up in a feed of the same indexer, and the other way around.
+type releaseDedup struct {
+	m    sync.Mutex
+	seen map[dedupKey][]dedupEntry
+
+	lastPrune time.Time
+}
+
+type dedupKey struct {
+	indexer string
+	title   string
+}
+
+type dedupEntry struct {
+	irc  bool
+	size uint64
+	at   time.Time
+}
+
+func newReleaseDedup() *releaseDedup {
+	return &releaseDedup{
+		seen: map[dedupKey][]dedupEntry{},
+	}
+}
+
+// check remembers the release and returns the source it was already seen from within the window, irc or feed,
+// or false when it is new
+func (d *releaseDedup) check(release *domain.Release, window time.Duration, now time.Time) (string, bool) {
+	title := normalizeDedupTitle(release.TorrentName)
+	if window <= 0 || release.Indexer == "" || title == "" {
+		return "", false
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if now.Sub(d.lastPrune) >= window {
+		d.prune(window, now)
+	}
+
+	key := dedupKey{indexer: release.Indexer, title: title}
+	irc := release.Implementation == domain.ReleaseImplementationIRC
+
+	for _, e := range d.seen[key] {
+		// duplicates from the same source are left to the feed cache and the announce parsing
+		if e.irc == irc || now.Sub(e.at) > window || !dedupSizeMatches(e.size, release.Size) {
+			continue
+		}
+
+		if e.irc {
+			return "irc", true
+		}
+		return "feed", true
+	}
+
+	d.seen[key] = append(d.seen[key], dedupEntry{irc: irc, size: release.Size, at: now})
+
+	return "", false
+}
+
+// prune forgets the releases older than the window
+func (d *releaseDedup) prune(window time.Duration, now time.Time) {
+	for key, entries := range d.seen {
+		kept := entries[:0]
+		for _, e := range entries {
+			if now.Sub(e.at) <= window {
+				kept = append(kept, e)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(d.seen, key)
+			continue
+		}
+
+		d.seen[key] = kept
+	}
+
+	d.lastPrune = now
+}
+
+// dedupSizeMatches reports whether the sizes are within dedupSizeTolerance, unknown sizes match any size
+func dedupSizeMatches(a, b uint64) bool {
+	if a == 0 || b == 0 {
+		return true
+	}
+
+	larger, smaller := a, b
+	if b > a {
+		larger, smaller = b, a
+	}
+
+	return float64(larger-smaller) <= float64(larger)*dedupSizeTolerance
+}
+
+// normalizeDedupTitle lowercases the title and drops everything but letters and digits,
+// so "Show S01E01 1080p WEB-GRP" and "Show.S01E01.1080p.WEB-GRP" are the same title
+func normalizeDedupTitle(title string) string {
+	var b strings.Builder
+	b.Grow(len(title))
+
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_releaseDedup_check(t *testing.T) {
+	now := time.Now()
+	window := time.Hour
+
+	irc := func(title string, size uint64) *domain.Release {
+		return &domain.Release{Indexer: "mock", TorrentName: title, Size: size, Implementation: domain.ReleaseImplementationIRC}
+	}
+	feed := func(title string, size uint64) *domain.Release {
+		return &domain.Release{Indexer: "mock", TorrentName: title, Size: size, Implementation: domain.ReleaseImplementationTorznab}
+	}
+
+	tests := []struct {
+		name     string
+		first    *domain.Release
+		second   *domain.Release
+		after    time.Duration
+		want     bool
+		wantFrom string
+	}{
+		{name: "irc_then_feed", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 1500000000), second: feed("Show S01E01 1080p WEB h264-GRP", 1490000000), want: true, wantFrom: "irc"},
+		{name: "feed_then_irc", first: feed("Show.S01E01.1080p.WEB.h264-GRP", 1500000000), second: irc("show.s01e01.1080p.web.h264-grp", 0), want: true, wantFrom: "feed"},
+		{name: "same_source", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 0), second: irc("Show.S01E01.1080p.WEB.h264-GRP", 0), want: false},
+		{name: "other_size", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 1500000000), second: feed("Show.S01E01.1080p.WEB.h264-GRP", 1000000000), want: false},
+		{name: "other_indexer", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 0), second: &domain.Release{Indexer: "other", TorrentName: "Show.S01E01.1080p.WEB.h264-GRP", Implementation: domain.ReleaseImplementationRSS}, want: false},
+		{name: "other_title", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 0), second: feed("Show.S01E02.1080p.WEB.h264-GRP", 0), want: false},
+		{name: "after_window", first: irc("Show.S01E01.1080p.WEB.h264-GRP", 0), second: feed("Show.S01E01.1080p.WEB.h264-GRP", 0), after: window + time.Minute, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newReleaseDedup()
+
+			_, dupe := d.check(tt.first, window, now)
+			assert.False(t, dupe)
+
+			from, dupe := d.check(tt.second, window, now.Add(tt.after))
+			assert.Equal(t, tt.want, dupe)
+			assert.Equal(t, tt.wantFrom, from)
+		})
+	}
+}
+
+func Test_releaseDedup_prune(t *testing.T) {
+	now := time.Now()
+	d := newReleaseDedup()
+
+	d.check(&domain.Release{Indexer: "mock", TorrentName: "Old.Release-GRP"}, time.Hour, now)
+	d.check(&domain.Release{Indexer: "mock", TorrentName: "New.Release-GRP"}, time.Hour, now.Add(50*time.Minute))
+
+	d.check(&domain.Release{Indexer: "mock", TorrentName: "Newer.Release-GRP"}, time.Hour, now.Add(2*time.Hour))
+
+	assert.Len(t, d.seen, 1)
+}
+
+func Test_normalizeDedupTitle(t *testing.T) {
+	assert.Equal(t, "shows01e011080pwebh264grp", normalizeDedupTitle("Show.S01E01.1080p.WEB.h264-GRP"))
+	assert.Equal(t, "shows01e011080pwebh264grp", normalizeDedupTitle("Show S01E01 1080p WEB_h264 - GRP"))
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const holdJobIdentifier = "release-season-pack-hold"
+
+func (s *service) Start() error {
+	if s.config != nil && !domain.ValidFilterMatchPolicy(s.config.FilterMatchPolicy) {
+		s.log.Warn().Msgf("unknown filterMatchPolicy %q, using first-match", s.config.FilterMatchPolicy)
+	}
+
+	job := &HoldJob{
+		log: s.log.With().Str("job", holdJobIdentifier).Logger(),
+		svc: s,
+	}
+
+	if _, err := s.scheduler.ScheduleJob(job, time.Minute, holdJobIdentifier); err != nil {
+		return errors.Wrap(err, "add job %s failed", holdJobIdentifier)
+	}
+
+	// delayed actions are stored, start their timers again after a restart
+	holds, err := s.holdRepo.FindByType(context.Background(), domain.ReleaseHoldTypeDelay)
+	if err != nil {
+		return errors.Wrap(err, "could not find delayed releases")
+	}
+
+	for _, hold := range holds {
+		s.startHoldTimer(hold)
+	}
+
+	if len(holds) > 0 {
+		s.log.Debug().Msgf("resumed %d delayed releases", len(holds))
+	}
+
+	return nil
+}
+
+// HoldJob runs the actions of held releases whose hold expired.
+// Delayed releases run from a timer, the job picks them up if the timer did not run.
+type HoldJob struct {
+	log zerolog.Logger
+	svc *service
+}
+
+func (j *HoldJob) Run() {
+	if err := j.svc.runDueHolds(context.Background()); err != nil {
+		j.log.Error().Err(err).Msg("error running held releases")
+	}
+}
+
+// holdForSeasonPack stores the release so its actions run when the hold of the filter expires
+func (s *service) holdForSeasonPack(ctx context.Context, f *domain.Filter, release *domain.Release) error {
+	hold := &domain.ReleaseHold{
+		Type:      domain.ReleaseHoldTypeSeasonPack,
+		ReleaseID: release.ID,
+		FilterID:  f.ID,
+		Title:     release.Title,
+		Season:    release.Season,
+		Episode:   release.Episode,
+		HoldUntil: time.Now().Add(time.Duration(f.SeasonPackHold) * time.Hour),
+	}
+
+	if err := s.holdRepo.Store(ctx, hold); err != nil {
+		return errors.Wrap(err, "could not store hold for release: %s", release.TorrentName)
+	}
+
+	s.log.Info().Msgf("Holding '%s' (%s) for a season pack until %s", release.TorrentName, f.Name, hold.HoldUntil.Format(time.RFC3339))
+
+	return nil
+}
+
+// actionDelay returns the fixed delay of the filter plus a random jitter of up to DelayJitter seconds
+func actionDelay(f *domain.Filter, int63n func(n int64) int64) time.Duration {
+	delay := time.Duration(f.Delay) * time.Second
+
+	if f.DelayJitter > 0 {
+		delay += time.Duration(int63n(int64(f.DelayJitter)+1)) * time.Second
+	}
+
+	return delay
+}
+
+// delayActions stores the release so its actions run after the delay, also when autobrr is restarted in between
+func (s *service) delayActions(ctx context.Context, f *domain.Filter, release *domain.Release, delay time.Duration) error {
+	hold := domain.ReleaseHold{
+		Type:      domain.ReleaseHoldTypeDelay,
+		ReleaseID: release.ID,
+		FilterID:  f.ID,
+		Title:     release.Title,
+		Season:    release.Season,
+		Episode:   release.Episode,
+		// the delay starts when the release was announced, not when it was processed
+		HoldUntil: release.Timestamp.Add(delay),
+	}
+
+	if err := s.holdRepo.Store(ctx, &hold); err != nil {
+		return errors.Wrap(err, "could not store delay for release: %s", release.TorrentName)
+	}
+
+	s.startHoldTimer(hold)
+
+	return nil
+}
+
+// startHoldTimer runs the hold when it expires
+func (s *service) startHoldTimer(hold domain.ReleaseHold) {
+	time.AfterFunc(time.Until(hold.HoldUntil), func() {
+		if err := s.runHold(context.Background(), hold); err != nil {
+			s.log.Error().Err(err).Msgf("could not run delayed release: %d", hold.ReleaseID)
+		}
+	})
+}
+
+// cancelHeldEpisodes drops the held episodes of the season of a grabbed season pack.
+// Each action of a held episode gets a rejected status so the release history shows why it was not grabbed.
+func (s *service) cancelHeldEpisodes(ctx context.Context, pack *domain.Release) {
+	holds, err := s.holdRepo.FindBySeason(ctx, pack.Title, pack.Season)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find held episodes for season pack: %s", pack.TorrentName)
+		return
+	}
+
+	for _, hold := range holds {
+		rejection := fmt.Sprintf("cancelled: season pack grabbed: %s", pack.TorrentName)
+
+		if err := s.rejectHold(ctx, hold, rejection); err != nil {
+			s.log.Error().Err(err).Msgf("could not cancel held release: %d", hold.ReleaseID)
+			continue
+		}
+
+		s.log.Info().Msgf("Cancelled held episode (%s) season: %d ep: %d, season pack grabbed: %s", hold.Title, hold.Season, hold.Episode, pack.TorrentName)
+	}
+}
+
+func (s *service) rejectHold(ctx context.Context, hold domain.ReleaseHold, rejection string) error {
+	f, err := s.filterSvc.FindByID(ctx, hold.FilterID)
+	if err != nil && !errors.Is(err, domain.ErrRecordNotFound) {
+		return err
+	}
+
+	if f != nil {
+		actions, err := s.findActions(ctx, f)
+		if err != nil {
+			return err
+		}
+
+		release := &domain.Release{ID: hold.ReleaseID, FilterName: f.Name, FilterID: f.ID}
+
+		for _, act := range actions {
+			if !act.Enabled {
+				continue
+			}
+
+			status := domain.NewReleaseActionStatus(act, release)
+			status.Status = domain.ReleasePushStatusRejected
+			status.Rejections = []string{rejection}
+
+			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.holdRepo.Delete(ctx, hold.ID)
+}
+
+// runDueHolds runs the actions of the held releases whose hold expired
+func (s *service) runDueHolds(ctx context.Context) error {
+	holds, err := s.holdRepo.FindDue(ctx, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "could not find held releases")
+	}
+
+	for _, hold := range holds {
+		if err := s.runHold(ctx, hold); err != nil {
+			s.log.Error().Err(err).Msgf("could not run held release: %d", hold.ReleaseID)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) runHold(ctx context.Context, hold domain.ReleaseHold) error {
+	// the hold is removed first so a failing action is not retried every minute,
+	// and only one of the timer and the job runs it
+	claimed, err := s.holdRepo.Claim(ctx, hold.ID)
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, hold.FilterID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			s.log.Debug().Msgf("dropping held release %d, filter %d no longer exists", hold.ReleaseID, hold.FilterID)
+			return nil
+		}
+		return err
+	}
+
+	release, err := s.repo.Get(ctx, &domain.GetReleaseRequest{Id: int(hold.ReleaseID)})
+	if err != nil {
+		return err
+	}
+
+	if release == nil {
+		s.log.Debug().Msgf("dropping held release %d, release no longer exists", hold.ReleaseID)
+		return nil
+	}
+
+	// parse the name again to get the fields that are not stored but can be used by actions
+	release.ParseString(release.TorrentName)
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	defer release.CleanupTemporaryFiles()
+
+	actions, err := s.findActions(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	if hold.Type == domain.ReleaseHoldTypeDelay {
+		l.Info().Msgf("Running delayed actions for '%s' (%s)", release.TorrentName, f.Name)
+	} else {
+		l.Info().Msgf("No season pack grabbed, running held actions for '%s' (%s)", release.TorrentName, f.Name)
+	}
+
+	s.runActions(ctx, l, actions, release, map[actionClientTypeKey]bool{})
+
+	return nil
+}
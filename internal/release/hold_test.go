@@ -0,0 +1,36 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_actionDelay(t *testing.T) {
+	// returns the highest possible value to check the jitter bound
+	maxInt63n := func(n int64) int64 { return n - 1 }
+
+	tests := []struct {
+		name   string
+		filter domain.Filter
+		want   time.Duration
+	}{
+		{name: "instant", filter: domain.Filter{}, want: 0},
+		{name: "fixed", filter: domain.Filter{Delay: 30}, want: 30 * time.Second},
+		{name: "jitter only", filter: domain.Filter{DelayJitter: 10}, want: 10 * time.Second},
+		{name: "fixed and jitter", filter: domain.Filter{Delay: 30, DelayJitter: 10}, want: 40 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, actionDelay(&tt.filter, maxInt63n))
+		})
+	}
+
+	assert.Equal(t, 30*time.Second, actionDelay(&domain.Filter{Delay: 30, DelayJitter: 10}, func(n int64) int64 { return 0 }))
+}
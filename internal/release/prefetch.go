@@ -0,0 +1,226 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// prefetchTimeout bounds the download of a torrent file with its retries
+const prefetchTimeout = 3 * time.Minute
+
+// torrentCache keeps the prefetched torrent files of feed releases on disk, keyed by download url. Releases get a
+// copy of the cached file, so the cleanup of a release does not remove it. The least recently used files are
+// removed over the max size.
+type torrentCache struct {
+	m       sync.Mutex
+	dir     string
+	size    int64
+	entries map[string]*torrentCacheEntry
+}
+
+type torrentCacheEntry struct {
+	path  string
+	bytes int64
+	used  time.Time
+
+	hash  string
+	size  uint64
+	files []domain.ReleaseTorrentFile
+}
+
+func newTorrentCache() *torrentCache {
+	return &torrentCache{
+		entries: map[string]*torrentCacheEntry{},
+	}
+}
+
+// get copies the cached torrent file of the release to a temporary file of the release, false when it is not cached
+func (c *torrentCache) get(release *domain.Release, now time.Time) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e, ok := c.entries[release.DownloadURL]
+	if !ok {
+		return false
+	}
+
+	tmpFile, err := copyToTemp(e.path)
+	if err != nil {
+		c.remove(release.DownloadURL)
+		return false
+	}
+
+	e.used = now
+
+	release.TorrentTmpFile = tmpFile
+	release.TorrentHash = e.hash
+	release.Size = e.size
+	release.TorrentFiles = e.files
+
+	return true
+}
+
+// put caches the downloaded torrent file of the release and removes the least recently used files over maxBytes
+func (c *torrentCache) put(release *domain.Release, maxBytes int64, now time.Time) error {
+	if release.TorrentTmpFile == "" || maxBytes <= 0 {
+		return nil
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if _, ok := c.entries[release.DownloadURL]; ok {
+		return nil
+	}
+
+	if c.dir == "" {
+		dir, err := os.MkdirTemp("", "autobrr-torrents-")
+		if err != nil {
+			return errors.Wrap(err, "could not create torrent cache dir")
+		}
+		c.dir = dir
+	}
+
+	src, err := os.Open(release.TorrentTmpFile)
+	if err != nil {
+		return errors.Wrap(err, "could not open torrent file: %s", release.TorrentTmpFile)
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(c.dir, "torrent-")
+	if err != nil {
+		return errors.Wrap(err, "could not create cached torrent file")
+	}
+	defer dst.Close()
+
+	n, err := io.Copy(dst, src)
+	if err != nil || n > maxBytes {
+		os.Remove(dst.Name())
+		if err != nil {
+			return errors.Wrap(err, "could not write cached torrent file: %s", dst.Name())
+		}
+		return nil
+	}
+
+	c.entries[release.DownloadURL] = &torrentCacheEntry{
+		path:  dst.Name(),
+		bytes: n,
+		used:  now,
+		hash:  release.TorrentHash,
+		size:  release.Size,
+		files: release.TorrentFiles,
+	}
+	c.size += n
+
+	c.evict(maxBytes)
+
+	return nil
+}
+
+// evict removes the least recently used files until the cache is within maxBytes
+func (c *torrentCache) evict(maxBytes int64) {
+	for c.size > maxBytes && len(c.entries) > 0 {
+		oldest := ""
+		var oldestUsed time.Time
+		for key, e := range c.entries {
+			if oldest == "" || e.used.Before(oldestUsed) {
+				oldest, oldestUsed = key, e.used
+			}
+		}
+
+		c.remove(oldest)
+	}
+}
+
+func (c *torrentCache) remove(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	os.Remove(e.path)
+	c.size -= e.bytes
+	delete(c.entries, key)
+}
+
+// copyToTemp copies the file to a new temporary file like the ones of downloaded torrents and returns its name
+func copyToTemp(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "autobrr-")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+
+	return dst.Name(), nil
+}
+
+// needsTorrentFile reports whether an enabled action can use the torrent file of a release
+func needsTorrentFile(actions []*domain.Action) bool {
+	for _, a := range actions {
+		if !a.Enabled {
+			continue
+		}
+
+		switch a.Type {
+		case domain.ActionTypeQbittorrent, domain.ActionTypeDelugeV1, domain.ActionTypeDelugeV2, domain.ActionTypeRTorrent,
+			domain.ActionTypeTransmission, domain.ActionTypePorla, domain.ActionTypeWatchFolder, domain.ActionTypeExec,
+			domain.ActionTypeWebhook, domain.ActionTypeAria2:
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefetchTorrent downloads the torrent file of a feed release before its actions run, or copies it from the cache,
+// so slow download endpoints do not time out the actions and the actions share one download. Failures are logged
+// and left to the actions, which download the file themselves.
+func (s *service) prefetchTorrent(ctx context.Context, l zerolog.Logger, actions []*domain.Action, release *domain.Release) {
+	if s.config == nil || !s.config.FeedTorrentPrefetch || s.torrentCache == nil {
+		return
+	}
+
+	if release.Implementation == domain.ReleaseImplementationIRC || release.Protocol != domain.ReleaseProtocolTorrent ||
+		release.HasMagnetUri() || release.DownloadURL == "" || release.TorrentTmpFile != "" || !needsTorrentFile(actions) {
+		return
+	}
+
+	if s.torrentCache.get(release, time.Now()) {
+		l.Debug().Msgf("release.Process: using cached torrent file of %s", release.TorrentName)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prefetchTimeout)
+	defer cancel()
+
+	if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		l.Warn().Err(err).Msgf("release.Process: could not prefetch torrent file of %s", release.TorrentName)
+		return
+	}
+
+	if err := s.torrentCache.put(release, int64(s.config.FeedTorrentCacheMB)*1024*1024, time.Now()); err != nil {
+		l.Warn().Err(err).Msgf("release.Process: could not cache torrent file of %s", release.TorrentName)
+	}
+}
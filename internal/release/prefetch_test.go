@@ -0,0 +1,109 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTorrentRelease(t *testing.T, url string, data string) *domain.Release {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "autobrr-")
+	assert.NoError(t, err)
+	_, err = f.WriteString(data)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	return &domain.Release{
+		DownloadURL:    url,
+		TorrentTmpFile: f.Name(),
+		TorrentHash:    "hash-" + url,
+		Size:           uint64(len(data)),
+		TorrentFiles:   []domain.ReleaseTorrentFile{{Path: "file.mkv", Size: 1000}},
+	}
+}
+
+func Test_torrentCache_getPut(t *testing.T) {
+	c := newTorrentCache()
+	defer os.RemoveAll(c.dir)
+
+	now := time.Now()
+
+	miss := &domain.Release{DownloadURL: "https://example.com/1"}
+	assert.False(t, c.get(miss, now))
+
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/1", "0123456789"), 100, now))
+
+	rls := &domain.Release{DownloadURL: "https://example.com/1"}
+	assert.True(t, c.get(rls, now))
+	defer rls.CleanupTemporaryFiles()
+
+	data, err := os.ReadFile(rls.TorrentTmpFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+	assert.Equal(t, "hash-https://example.com/1", rls.TorrentHash)
+	assert.Equal(t, uint64(10), rls.Size)
+	assert.Len(t, rls.TorrentFiles, 1)
+
+	// the release gets a copy, its cleanup keeps the cached file
+	rls.CleanupTemporaryFiles()
+	assert.True(t, c.get(&domain.Release{DownloadURL: "https://example.com/1"}, now))
+}
+
+func Test_torrentCache_evict(t *testing.T) {
+	c := newTorrentCache()
+	defer os.RemoveAll(c.dir)
+
+	now := time.Now()
+
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/1", "0123456789"), 25, now))
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/2", "0123456789"), 25, now.Add(time.Second)))
+
+	// using the first file makes the second the least recently used
+	rls := &domain.Release{DownloadURL: "https://example.com/1"}
+	assert.True(t, c.get(rls, now.Add(2*time.Second)))
+	rls.CleanupTemporaryFiles()
+
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/3", "0123456789"), 25, now.Add(3*time.Second)))
+
+	assert.Len(t, c.entries, 2)
+	assert.Equal(t, int64(20), c.size)
+	assert.Contains(t, c.entries, "https://example.com/1")
+	assert.NotContains(t, c.entries, "https://example.com/2")
+	assert.Contains(t, c.entries, "https://example.com/3")
+
+	// a file larger than the cache is not kept
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/4", "0123456789012345678901234567890"), 25, now))
+	assert.NotContains(t, c.entries, "https://example.com/4")
+
+	// no size does not cache
+	assert.NoError(t, c.put(testTorrentRelease(t, "https://example.com/5", "0"), 0, now))
+	assert.NotContains(t, c.entries, "https://example.com/5")
+}
+
+func Test_needsTorrentFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []*domain.Action
+		want    bool
+	}{
+		{name: "no_actions", actions: nil, want: false},
+		{name: "arr", actions: []*domain.Action{{Type: domain.ActionTypeSonarr, Enabled: true}}, want: false},
+		{name: "disabled_client", actions: []*domain.Action{{Type: domain.ActionTypeQbittorrent, Enabled: false}}, want: false},
+		{name: "client", actions: []*domain.Action{{Type: domain.ActionTypeSonarr, Enabled: true}, {Type: domain.ActionTypeQbittorrent, Enabled: true}}, want: true},
+		{name: "watch_folder", actions: []*domain.Action{{Type: domain.ActionTypeWatchFolder, Enabled: true}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, needsTorrentFile(tt.actions))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package release
 
 import (
 	"context"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/scheduler"
 
 	"github.com/rs/zerolog"
 )
@@ -23,33 +25,64 @@ type Service interface {
 	GetActionStatus(ctx context.Context, req *domain.GetReleaseActionStatusRequest) (*domain.ReleaseActionStatus, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
+	StatsBreakdown(ctx context.Context) (*domain.ReleaseStatsBreakdown, error)
 	Store(ctx context.Context, release *domain.Release) error
 	StoreReleaseActionStatus(ctx context.Context, actionStatus *domain.ReleaseActionStatus) error
-	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error
+	Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error)
 	Process(release *domain.Release)
 	ProcessMultiple(releases []*domain.Release)
 	Retry(ctx context.Context, req *domain.ReleaseActionRetryReq) error
+	Start() error
 }
 
 type actionClientTypeKey struct {
 	Type     domain.ActionType
 	ClientID int32
+	Target   string
+}
+
+// newActionClientTypeKey returns the key of the target of an action, the download client or arr, or for actions
+// without client the exec command, webhook or watch folder
+func newActionClientTypeKey(action *domain.Action) actionClientTypeKey {
+	key := actionClientTypeKey{Type: action.Type, ClientID: action.ClientID}
+
+	switch action.Type {
+	case domain.ActionTypeExec:
+		key.Target = action.ExecCmd + " " + action.ExecArgs
+	case domain.ActionTypeWebhook:
+		key.Target = action.WebhookHost
+	case domain.ActionTypeWatchFolder:
+		key.Target = action.WatchFolder
+	}
+
+	return key
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.ReleaseRepo
+	log      zerolog.Logger
+	config   *domain.Config
+	repo     domain.ReleaseRepo
+	holdRepo domain.ReleaseHoldRepo
 
 	actionSvc action.Service
 	filterSvc filter.Service
+	scheduler scheduler.Service
+
+	dedup        *releaseDedup
+	torrentCache *torrentCache
 }
 
-func NewService(log logger.Logger, repo domain.ReleaseRepo, actionSvc action.Service, filterSvc filter.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.ReleaseRepo, holdRepo domain.ReleaseHoldRepo, actionSvc action.Service, filterSvc filter.Service, scheduler scheduler.Service) Service {
 	return &service{
-		log:       log.With().Str("module", "release").Logger(),
-		repo:      repo,
-		actionSvc: actionSvc,
-		filterSvc: filterSvc,
+		log:          log.With().Str("module", "release").Logger(),
+		config:       config,
+		repo:         repo,
+		holdRepo:     holdRepo,
+		actionSvc:    actionSvc,
+		filterSvc:    filterSvc,
+		scheduler:    scheduler,
+		dedup:        newReleaseDedup(),
+		torrentCache: newTorrentCache(),
 	}
 }
 
@@ -77,6 +110,10 @@ func (s *service) Stats(ctx context.Context) (*domain.ReleaseStats, error) {
 	return s.repo.Stats(ctx)
 }
 
+func (s *service) StatsBreakdown(ctx context.Context) (*domain.ReleaseStatsBreakdown, error) {
+	return s.repo.StatsBreakdown(ctx)
+}
+
 func (s *service) Store(ctx context.Context, release *domain.Release) error {
 	return s.repo.Store(ctx, release)
 }
@@ -85,7 +122,7 @@ func (s *service) StoreReleaseActionStatus(ctx context.Context, status *domain.R
 	return s.repo.StoreReleaseActionStatus(ctx, status)
 }
 
-func (s *service) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) error {
+func (s *service) Delete(ctx context.Context, req *domain.DeleteReleaseRequest) (int64, error) {
 	return s.repo.Delete(ctx, req)
 }
 
@@ -108,7 +145,11 @@ func (s *service) Process(release *domain.Release) {
 
 	// TODO check in config for "Save all releases"
 	// TODO cross-seed check
-	// TODO dupe checks
+
+	if source, dupe := s.isDuplicate(release); dupe {
+		s.log.Info().Msgf("skipping release %s from %s, already processed from %s of the same indexer", release.TorrentName, release.Implementation, source)
+		return
+	}
 
 	// get filters by priority
 	filters, err := s.filterSvc.FindByIndexerIdentifier(ctx, release.Indexer)
@@ -130,13 +171,32 @@ func (s *service) Process(release *domain.Release) {
 	return
 }
 
+// isDuplicate reports whether the release was processed from the other source of its indexer, irc or feed,
+// within the dedup window of the config
+func (s *service) isDuplicate(release *domain.Release) (string, bool) {
+	if s.dedup == nil || s.config == nil || s.config.ReleaseDedupMinutes <= 0 {
+		return "", false
+	}
+
+	return s.dedup.check(release, time.Duration(s.config.ReleaseDedupMinutes)*time.Minute, time.Now())
+}
+
 func (s *service) processFilters(ctx context.Context, filters []domain.Filter, release *domain.Release) error {
 	// keep track of action clients to avoid sending the same thing all over again
 	// save both client type and client id to potentially try another client of same type
-	triedActionClients := map[actionClientTypeKey]struct{}{}
+	// the value is true when the client failed, a failed client is not tried again by lower priority filters
+	triedActionClients := map[actionClientTypeKey]bool{}
+
+	policy := s.matchPolicy()
+
+	// filters already checked, a filter is checked at most once even when chained to
+	checked := map[int]struct{}{}
+
+	// loop over and check filters by priority, or in the order set by their on reject and on action failure settings
+	for i := 0; i >= 0 && i < len(filters); {
+		f := filters[i]
+		checked[f.ID] = struct{}{}
 
-	// loop over and check filters
-	for _, f := range filters {
 		l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
 
 		// save filter on release
@@ -155,6 +215,8 @@ func (s *service) processFilters(ctx context.Context, filters []domain.Filter, r
 			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s, no match. rejections: %s", release.Indexer, release.FilterName, release.TorrentName, release.RejectionsString(false))
 
 			l.Debug().Msgf("release rejected: %s", release.RejectionsString(true))
+
+			i = s.nextFilter(l, filters, i, f.OnReject, f.OnRejectFilterID, checked)
 			continue
 		}
 
@@ -171,9 +233,8 @@ func (s *service) processFilters(ctx context.Context, filters []domain.Filter, r
 		}
 
 		// found matching filter, lets find the filter actions and attach
-		actions, err := s.actionSvc.FindByFilterID(ctx, f.ID)
+		actions, err := s.findActions(ctx, &f)
 		if err != nil {
-			s.log.Error().Err(err).Msgf("release.Process: error finding actions for filter: %s", f.Name)
 			return err
 		}
 
@@ -183,69 +244,229 @@ func (s *service) processFilters(ctx context.Context, filters []domain.Filter, r
 			return nil
 		}
 
-		// sleep for the delay period specified in the filter before running actions
-		delay := release.Filter.Delay
-		if delay > 0 {
-			l.Debug().Msgf("release.Process: delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.FilterName, release.Indexer, delay)
-			time.Sleep(time.Duration(delay) * time.Second)
+		// hold the actions of single episodes when the filter prefers season packs
+		if f.PreferSeasonPacks && f.SeasonPackHold > 0 && release.IsSingleEpisode() {
+			if err := s.holdForSeasonPack(ctx, &f, release); err != nil {
+				l.Error().Err(err).Msg("release.Process: error holding release for season pack")
+				return err
+			}
+
+			if !policy.ContinueAfterMatch() {
+				break
+			}
+
+			i = s.nextFilter(l, filters, i, domain.FilterChainNext, 0, checked)
+			continue
 		}
 
-		var rejections []string
+		// queue the actions for the delay period specified in the filter
+		if delay := actionDelay(&f, rand.Int63n); delay > 0 {
+			l.Debug().Msgf("release.Process: delaying processing of '%s' (%s) for %s by %s as specified in the filter", release.TorrentName, release.FilterName, release.Indexer, delay)
 
-		// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
-		for _, a := range actions {
-			act := a
+			if err := s.delayActions(ctx, &f, release, delay); err != nil {
+				l.Error().Err(err).Msg("release.Process: error delaying release actions")
+				return err
+			}
 
-			// only run enabled actions
-			if !act.Enabled {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.FilterName, release.TorrentName, act.Name)
-				continue
+			if !policy.ContinueAfterMatch() {
+				break
 			}
 
-			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.FilterName, release.TorrentName, act.Name)
+			i = s.nextFilter(l, filters, i, domain.FilterChainNext, 0, checked)
+			continue
+		}
 
-			// keep track of action clients to avoid sending the same thing all over again
-			_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
-			if tried {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer, release.FilterName, release.TorrentName)
-				continue
-			}
+		result := s.runActions(ctx, l, actions, release, triedActionClients)
 
-			// run action
-			status, err := s.runAction(ctx, act, release)
-			if err != nil {
-				l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
-				//continue
-			}
+		// the filter has no actions for the protocol, eg. only torrent clients for a usenet release, so it is
+		// left to the next filter like the filter did not match
+		if result.allUnsupported() {
+			l.Debug().Msgf("release.Process: filter: %s has no actions for %s releases", f.Name, release.Protocol)
 
-			rejections = status.Rejections
+			i = s.nextFilter(l, filters, i, domain.FilterChainNext, 0, checked)
+			continue
+		}
 
-			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
-				s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
-			}
+		// no action got the release, eg. the client is down or arr rejected it. Fall through to the filter
+		// set by on action failure instead of dropping the release
+		if result.allFailed() {
+			l.Debug().Msgf("release.Process: all actions failed for filter: %s rejections: %s", f.Name, strings.Join(result.rejections, ", "))
+
+			i = s.nextFilter(l, filters, i, f.OnActionFailure, f.OnActionFailureFilterID, checked)
+			continue
+		}
+
+		if result.failed > 0 {
+			l.Warn().Msgf("release.Process: %d of %d actions failed for filter: %s, not falling through as the release was grabbed", result.failed, result.failed+result.approved, f.Name)
+		}
+
+		// a grabbed season pack replaces the held episodes of the season
+		if release.IsSeasonPack() {
+			s.cancelHeldEpisodes(ctx, release)
+		}
+
+		// all actions run, decide to stop or continue here
+		if !policy.ContinueAfterMatch() {
+			break
+		}
+
+		l.Debug().Msgf("release.Process: filter match policy %s, continue with next filter", policy)
+
+		i = s.nextFilter(l, filters, i, domain.FilterChainNext, 0, checked)
+	}
 
-			if len(rejections) > 0 {
-				// if we get action rejection, remember which action client it was from
-				triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
+	return nil
+}
+
+// matchPolicy returns how releases matched by more than one filter are handled
+func (s *service) matchPolicy() domain.FilterMatchPolicy {
+	if s.config == nil {
+		return domain.FilterMatchFirst
+	}
+
+	return domain.ParseFilterMatchPolicy(s.config.FilterMatchPolicy)
+}
 
-				// log something and fire events
-				l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
+// nextFilter returns the index of the filter to check after the filter at current, or -1 to stop processing the release
+func (s *service) nextFilter(l zerolog.Logger, filters []domain.Filter, current int, mode domain.FilterChainMode, targetID int, checked map[int]struct{}) int {
+	next, found := domain.NextFilterIndex(filters, current, mode, targetID, checked)
+	if !found {
+		l.Warn().Msgf("release.Process: filter to continue with %d not found or not enabled for indexer, trying next one..", targetID)
+	}
+
+	switch {
+	case next < 0 && mode == domain.FilterChainStop:
+		l.Debug().Msg("release.Process: stop processing release as set by filter")
+	case next < 0 && mode == domain.FilterChainFilter && found:
+		l.Debug().Msgf("release.Process: filter to continue with %d already checked, stop processing release", targetID)
+	case next >= 0 && mode == domain.FilterChainFilter && filters[next].ID == targetID:
+		l.Debug().Msgf("release.Process: continue with filter: %s", filters[next].Name)
+	}
+
+	return next
+}
+
+// findActions returns the actions of the filter, or the actions of its group when the filter has none
+func (s *service) findActions(ctx context.Context, f *domain.Filter) ([]*domain.Action, error) {
+	actions, err := s.actionSvc.FindByFilterID(ctx, f.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.Process: error finding actions for filter: %s", f.Name)
+		return nil, err
+	}
+
+	// filters without actions of their own run the actions of their group
+	if len(actions) == 0 && f.GroupID > 0 {
+		actions, err = s.actionSvc.FindByFilterGroupID(ctx, f.GroupID)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("release.Process: error finding actions for filter group: %d", f.GroupID)
+			return nil, err
+		}
+	}
+
+	return actions, nil
+}
+
+// actionsResult is the outcome of running the actions of a filter
+type actionsResult struct {
+	rejections []string
+
+	// approved is the number of actions that got the release
+	approved int
+
+	// failed is the number of actions that errored or were rejected, including actions skipped because their client failed before
+	failed int
+
+	// unsupported is the number of actions skipped because they can't handle the protocol of the release
+	unsupported int
+}
+
+// allFailed reports if actions were run and none of them got the release
+func (r actionsResult) allFailed() bool {
+	return r.approved == 0 && r.failed > 0
+}
+
+// allUnsupported reports if none of the actions could handle the protocol of the release
+func (r actionsResult) allUnsupported() bool {
+	return r.approved == 0 && r.failed == 0 && r.unsupported > 0
+}
+
+// runActions runs the enabled actions for the release and returns an actionsResult with the rejections of the last action run and the count of approved, failed and unsupported actions
+func (s *service) runActions(ctx context.Context, l zerolog.Logger, actions []*domain.Action, release *domain.Release, triedActionClients map[actionClientTypeKey]bool) actionsResult {
+	var result actionsResult
+
+	s.prefetchTorrent(ctx, l, actions, release)
+
+	// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
+	for _, a := range actions {
+		act := a
+
+		// only run enabled actions
+		if !act.Enabled {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.FilterName, release.TorrentName, act.Name)
+			continue
+		}
+
+		// route usenet releases to usenet clients and torrents to torrent clients
+		if !act.Type.SupportsProtocol(release.Protocol) {
+			result.unsupported++
+
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' does not support protocol %s, skip", release.Indexer, release.FilterName, release.TorrentName, act.Name, release.Protocol)
+			continue
+		}
+
+		l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.FilterName, release.TorrentName, act.Name)
+
+		// keep track of action clients to avoid sending the same thing all over again
+		key := newActionClientTypeKey(act)
+
+		failed, tried := triedActionClients[key]
+		if tried {
+			if failed {
+				result.failed++
 			}
 
-			// if no rejections consider action approved, run next
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried or grabbed release, skip", release.Indexer, release.FilterName, release.TorrentName)
 			continue
 		}
 
-		// if we have rejections from arr, continue to next filter
+		// run action
+		status, err := s.runAction(ctx, act, release)
+		if err != nil {
+			l.Error().Err(err).Msgf("release.Process: error running actions for filter: %s", release.FilterName)
+			//continue
+		}
+
+		rejections := status.Rejections
+
+		if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+			s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.FilterName)
+		}
+
+		if err == nil && len(rejections) == 0 {
+			result.approved++
+
+			if s.matchPolicy().Dedupe() {
+				// remember action targets that got the release so other matching filters don't send it again
+				triedActionClients[key] = false
+			}
+		}
+
 		if len(rejections) > 0 {
-			continue
+			result.failed++
+			result.rejections = append(result.rejections, rejections...)
+
+			// if we get action rejection, remember which action client it was from
+			triedActionClients[key] = true
+
+			// log something and fire events
+			l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
 		}
 
-		// all actions run, decide to stop or continue here
-		break
+		// if no rejections consider action approved, run next
+		continue
 	}
 
-	return nil
+	return result
 }
 
 func (s *service) ProcessMultiple(releases []*domain.Release) {
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_actionsResult_allFailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		result actionsResult
+		want   bool
+	}{
+		{name: "no_actions_run", result: actionsResult{}, want: false},
+		{name: "all_approved", result: actionsResult{approved: 2}, want: false},
+		{name: "some_failed", result: actionsResult{approved: 1, failed: 1}, want: false},
+		{name: "all_failed", result: actionsResult{failed: 2, rejections: []string{"client down"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.result.allFailed())
+		})
+	}
+}
+
+func Test_actionsResult_allUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		result actionsResult
+		want   bool
+	}{
+		{name: "no_actions_run", result: actionsResult{}, want: false},
+		{name: "all_unsupported", result: actionsResult{unsupported: 2}, want: true},
+		{name: "some_approved", result: actionsResult{approved: 1, unsupported: 1}, want: false},
+		{name: "some_failed", result: actionsResult{failed: 1, unsupported: 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.result.allUnsupported())
+		})
+	}
+}
+
+func Test_newActionClientTypeKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        domain.Action
+		b        domain.Action
+		wantSame bool
+	}{
+		{name: "same_client", a: domain.Action{Type: domain.ActionTypeQbittorrent, ClientID: 1}, b: domain.Action{Type: domain.ActionTypeQbittorrent, ClientID: 1, Category: "tv"}, wantSame: true},
+		{name: "other_client", a: domain.Action{Type: domain.ActionTypeQbittorrent, ClientID: 1}, b: domain.Action{Type: domain.ActionTypeQbittorrent, ClientID: 2}, wantSame: false},
+		{name: "same_exec", a: domain.Action{Type: domain.ActionTypeExec, ExecCmd: "/scripts/notify.sh", ExecArgs: "{{ .TorrentName }}"}, b: domain.Action{Type: domain.ActionTypeExec, ExecCmd: "/scripts/notify.sh", ExecArgs: "{{ .TorrentName }}"}, wantSame: true},
+		{name: "other_exec", a: domain.Action{Type: domain.ActionTypeExec, ExecCmd: "/scripts/notify.sh"}, b: domain.Action{Type: domain.ActionTypeExec, ExecCmd: "/scripts/sync.sh"}, wantSame: false},
+		{name: "other_webhook", a: domain.Action{Type: domain.ActionTypeWebhook, WebhookHost: "http://a.local/hook"}, b: domain.Action{Type: domain.ActionTypeWebhook, WebhookHost: "http://b.local/hook"}, wantSame: false},
+		{name: "other_watch_folder", a: domain.Action{Type: domain.ActionTypeWatchFolder, WatchFolder: "/watch/tv"}, b: domain.Action{Type: domain.ActionTypeWatchFolder, WatchFolder: "/watch/movies"}, wantSame: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantSame, newActionClientTypeKey(&tt.a) == newActionClientTypeKey(&tt.b))
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/feed"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/irc"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/update"
 
@@ -26,6 +28,8 @@ type Server struct {
 	indexerService indexer.Service
 	ircService     irc.Service
 	feedService    feed.Service
+	filterService  filter.Service
+	releaseService release.Service
 	scheduler      scheduler.Service
 	updateService  *update.Service
 
@@ -33,13 +37,15 @@ type Server struct {
 	lock   sync.Mutex
 }
 
-func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
+func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, filterSvc filter.Service, releaseSvc release.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
 	return &Server{
 		log:            log.With().Str("module", "server").Logger(),
 		config:         config,
 		indexerService: indexerSvc,
 		ircService:     ircSvc,
 		feedService:    feedSvc,
+		filterService:  filterSvc,
+		releaseService: releaseSvc,
 		scheduler:      scheduler,
 		updateService:  updateSvc,
 	}
@@ -65,6 +71,16 @@ func (s *Server) Start() error {
 		s.log.Error().Err(err).Msg("Could not start feed service")
 	}
 
+	// refresh filter list sources and write filter stats
+	if err := s.filterService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start filter service")
+	}
+
+	// run the actions of releases held for season packs
+	if err := s.releaseService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start release service")
+	}
+
 	return nil
 }
 
@@ -74,6 +90,9 @@ func (s *Server) Shutdown() {
 	// stop all irc handlers
 	s.ircService.StopHandlers()
 
+	// write pending filter stats
+	s.filterService.Stop()
+
 	// stop cron scheduler
 	s.scheduler.Stop()
 }
@@ -13,7 +13,10 @@ import (
 type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	FindAll(ctx context.Context) ([]*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	Update(ctx context.Context, user domain.User) error
+	Delete(ctx context.Context, username string) error
 }
 
 type service struct {
@@ -51,3 +54,15 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 
 	return s.repo.Store(ctx, req)
 }
+
+func (s *service) FindAll(ctx context.Context) ([]*domain.User, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *service) Update(ctx context.Context, user domain.User) error {
+	return s.repo.Update(ctx, user)
+}
+
+func (s *service) Delete(ctx context.Context, username string) error {
+	return s.repo.Delete(ctx, username)
+}
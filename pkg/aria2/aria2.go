@@ -0,0 +1,338 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/net/websocket"
+)
+
+var (
+	DefaultTimeout = 60 * time.Second
+)
+
+// Client calls the json-rpc interface of aria2 over http or a websocket, by the scheme of the address
+type Client struct {
+	addr    string
+	secret  string
+	timeout time.Duration
+
+	tlsSkipVerify bool
+
+	log *log.Logger
+
+	Http *http.Client
+}
+
+type Options struct {
+	// Addr is the rpc url like http://localhost:6800/jsonrpc or ws://localhost:6800/jsonrpc
+	Addr string
+
+	// Secret is the --rpc-secret of aria2
+	Secret string
+
+	TLSSkipVerify bool
+
+	Log *log.Logger
+}
+
+func New(opts Options) *Client {
+	c := &Client{
+		addr:          opts.Addr,
+		secret:        opts.Secret,
+		timeout:       DefaultTimeout,
+		tlsSkipVerify: opts.TLSSkipVerify,
+		log:           log.New(io.Discard, "", log.LstdFlags),
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	c.Http = &http.Client{
+		Timeout:   c.timeout,
+		Transport: transport,
+	}
+
+	if opts.Log != nil {
+		c.log = opts.Log
+	}
+
+	return c
+}
+
+type rpcRequest struct {
+	JsonRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+	ID      string        `json:"id"`
+}
+
+type rpcResponse struct {
+	ID     string          `json:"id"`
+	Method string          `json:"method,omitempty"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error of a failed call, like a wrong secret
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+// params prepends the secret token to the params of a call
+func (c *Client) params(params ...interface{}) []interface{} {
+	if c.secret == "" {
+		return params
+	}
+
+	return append([]interface{}{"token:" + c.secret}, params...)
+}
+
+// call sends the method and decodes its result into result
+func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return errors.Wrap(err, "invalid url: %s", c.addr)
+	}
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	req := rpcRequest{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      "autobrr",
+	}
+
+	var res *rpcResponse
+	switch u.Scheme {
+	case "http", "https":
+		res, err = c.callHTTP(ctx, req)
+
+	case "ws", "wss":
+		res, err = c.callWebSocket(ctx, u, req)
+
+	default:
+		return errors.New("unsupported scheme %s, use http(s) or ws(s): %s", u.Scheme, c.addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if err := json.Unmarshal(res.Result, result); err != nil {
+		return errors.Wrap(err, "could not decode result of %s", method)
+	}
+
+	return nil
+}
+
+func (c *Client) callHTTP(ctx context.Context, rpcReq rpcRequest) (*rpcResponse, error) {
+	body, err := json.Marshal(rpcReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.Http.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling %s", rpcReq.Method)
+	}
+
+	defer res.Body.Close()
+
+	// aria2 answers errors like a wrong secret with 400 and an rpc error
+	var data rpcResponse
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		if res.StatusCode != http.StatusOK {
+			return nil, errors.New("unexpected status calling %s: %d", rpcReq.Method, res.StatusCode)
+		}
+
+		return nil, errors.Wrap(err, "could not decode response of %s", rpcReq.Method)
+	}
+
+	return &data, nil
+}
+
+// callWebSocket sends the request over a new websocket and reads until its response, skipping the notifications
+// aria2 sends on websockets
+func (c *Client) callWebSocket(ctx context.Context, u *url.URL, rpcReq rpcRequest) (*rpcResponse, error) {
+	origin := "http://" + u.Host
+	if u.Scheme == "wss" {
+		origin = "https://" + u.Host
+	}
+
+	config, err := websocket.NewConfig(c.addr, origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid websocket url: %s", c.addr)
+	}
+
+	config.Dialer = &net.Dialer{Timeout: c.timeout}
+	if c.tlsSkipVerify {
+		config.TlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error connecting to %s", c.addr)
+	}
+	defer conn.Close()
+
+	// the websocket can not be read with a context, closing it stops the read
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, errors.Wrap(err, "could not set deadline")
+	}
+
+	if err := websocket.JSON.Send(conn, rpcReq); err != nil {
+		return nil, errors.Wrap(err, "error calling %s", rpcReq.Method)
+	}
+
+	for {
+		var data rpcResponse
+		if err := websocket.JSON.Receive(conn, &data); err != nil {
+			return nil, errors.Wrap(err, "error reading response of %s", rpcReq.Method)
+		}
+
+		if data.Method != "" || data.ID != rpcReq.ID {
+			continue
+		}
+
+		return &data, nil
+	}
+}
+
+type VersionResponse struct {
+	Version         string   `json:"version"`
+	EnabledFeatures []string `json:"enabledFeatures"`
+}
+
+// GetVersion returns the version of aria2
+func (c *Client) GetVersion(ctx context.Context) (*VersionResponse, error) {
+	var version VersionResponse
+	if err := c.call(ctx, "aria2.getVersion", c.params(), &version); err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// AddUri adds the download of the uri, like a magnet link, and returns its gid
+func (c *Client) AddUri(ctx context.Context, uri string, opts AddOptions) (string, error) {
+	var gid string
+	if err := c.call(ctx, "aria2.addUri", c.params([]string{uri}, opts.Map()), &gid); err != nil {
+		return "", err
+	}
+
+	c.log.Printf("aria2 addUri: added %s with gid %s", uri, gid)
+
+	return gid, nil
+}
+
+// AddTorrent adds the download of the torrent file and returns its gid
+func (c *Client) AddTorrent(ctx context.Context, torrent []byte, opts AddOptions) (string, error) {
+	var gid string
+	if err := c.call(ctx, "aria2.addTorrent", c.params(base64.StdEncoding.EncodeToString(torrent), []string{}, opts.Map()), &gid); err != nil {
+		return "", err
+	}
+
+	c.log.Printf("aria2 addTorrent: added torrent with gid %s", gid)
+
+	return gid, nil
+}
+
+// AddOptions are the options of a new download, zero values are left to the defaults of aria2
+type AddOptions struct {
+	Dir string
+
+	// MaxDownloadLimit and MaxUploadLimit are in KiB/s
+	MaxDownloadLimit int64
+	MaxUploadLimit   int64
+
+	Pause bool
+
+	SeedRatio float64
+
+	// SeedTime is in minutes
+	SeedTime int64
+}
+
+// Map returns the options in the form of the aria2 api, all values are strings
+func (o AddOptions) Map() map[string]string {
+	m := map[string]string{}
+
+	if o.Dir != "" {
+		m["dir"] = o.Dir
+	}
+
+	if o.MaxDownloadLimit > 0 {
+		m["max-download-limit"] = strconv.FormatInt(o.MaxDownloadLimit, 10) + "K"
+	}
+
+	if o.MaxUploadLimit > 0 {
+		m["max-upload-limit"] = strconv.FormatInt(o.MaxUploadLimit, 10) + "K"
+	}
+
+	if o.Pause {
+		m["pause"] = "true"
+	}
+
+	if o.SeedRatio > 0 {
+		m["seed-ratio"] = strconv.FormatFloat(o.SeedRatio, 'f', -1, 64)
+	}
+
+	if o.SeedTime > 0 {
+		m["seed-time"] = strconv.FormatInt(o.SeedTime, 10)
+	}
+
+	return m
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package aria2
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+const testSecret = "s3cret"
+
+// handle answers a call like aria2, a wrong secret fails with an rpc error
+func handle(t *testing.T, req rpcRequest) string {
+	t.Helper()
+
+	if len(req.Params) == 0 || req.Params[0] != "token:"+testSecret {
+		return `{"id":"autobrr","jsonrpc":"2.0","error":{"code":1,"message":"Unauthorized"}}`
+	}
+
+	switch req.Method {
+	case "aria2.getVersion":
+		return `{"id":"autobrr","jsonrpc":"2.0","result":{"enabledFeatures":["BitTorrent","Metalink"],"version":"1.36.0"}}`
+
+	case "aria2.addUri":
+		assert.Equal(t, []interface{}{"magnet:?xt=urn:btih:abc"}, req.Params[1])
+		assert.Equal(t, map[string]interface{}{"dir": "/downloads", "max-download-limit": "1024K", "pause": "true"}, req.Params[2])
+		return `{"id":"autobrr","jsonrpc":"2.0","result":"2089b05ecca3d829"}`
+
+	case "aria2.addTorrent":
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("torrent")), req.Params[1])
+		assert.Equal(t, []interface{}{}, req.Params[2])
+		assert.Equal(t, map[string]interface{}{"seed-ratio": "1.5", "seed-time": "60"}, req.Params[3])
+		return `{"id":"autobrr","jsonrpc":"2.0","result":"d2703803b52216d1"}`
+	}
+
+	return `{"id":"autobrr","jsonrpc":"2.0","error":{"code":1,"message":"No such method"}}`
+}
+
+func TestClient_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		res := handle(t, req)
+		if strings.Contains(res, `"error"`) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+		w.Write([]byte(res))
+	}))
+	defer srv.Close()
+
+	c := New(Options{Addr: srv.URL + "/jsonrpc", Secret: testSecret})
+
+	version, err := c.GetVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.36.0", version.Version)
+
+	gid, err := c.AddUri(context.Background(), "magnet:?xt=urn:btih:abc", AddOptions{Dir: "/downloads", MaxDownloadLimit: 1024, Pause: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "2089b05ecca3d829", gid)
+
+	gid, err = c.AddTorrent(context.Background(), []byte("torrent"), AddOptions{SeedRatio: 1.5, SeedTime: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, "d2703803b52216d1", gid)
+
+	_, err = New(Options{Addr: srv.URL + "/jsonrpc", Secret: "wrong"}).GetVersion(context.Background())
+	assert.EqualError(t, err, "1: Unauthorized")
+}
+
+func TestClient_WebSocket(t *testing.T) {
+	srv := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		var req rpcRequest
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			return
+		}
+
+		// notifications of other downloads come before the response
+		websocket.Message.Send(conn, `{"jsonrpc":"2.0","method":"aria2.onDownloadStart","params":[{"gid":"2089b05ecca3d829"}]}`)
+		websocket.Message.Send(conn, handle(t, req))
+	}))
+	defer srv.Close()
+
+	c := New(Options{Addr: "ws" + strings.TrimPrefix(srv.URL, "http") + "/jsonrpc", Secret: testSecret})
+
+	version, err := c.GetVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.36.0", version.Version)
+	assert.Equal(t, []string{"BitTorrent", "Metalink"}, version.EnabledFeatures)
+}
+
+func TestClient_UnsupportedScheme(t *testing.T) {
+	_, err := New(Options{Addr: "ftp://localhost:6800/jsonrpc"}).GetVersion(context.Background())
+	assert.EqualError(t, err, "unsupported scheme ftp, use http(s) or ws(s): ftp://localhost:6800/jsonrpc")
+}
+
+func TestAddOptions_Map(t *testing.T) {
+	assert.Empty(t, AddOptions{}.Map())
+	assert.Equal(t, map[string]string{
+		"dir":                "/downloads",
+		"max-download-limit": "500K",
+		"max-upload-limit":   "100K",
+		"pause":              "true",
+		"seed-ratio":         "2",
+		"seed-time":          "1440",
+	}, AddOptions{
+		Dir:              "/downloads",
+		MaxDownloadLimit: 500,
+		MaxUploadLimit:   100,
+		Pause:            true,
+		SeedRatio:        2,
+		SeedTime:         1440,
+	}.Map())
+}
@@ -150,6 +150,8 @@ func TestClient_GetTorrentByID(t *testing.T) {
 				TorrentId: "1555073",
 				InfoHash:  "56CD94119F6BF7FC294A92D7A4099C3D1815C907",
 				Size:      "3288852849",
+				Seeders:   "5",
+				Leechers:  "41",
 			},
 			wantErr: false,
 		},
@@ -223,6 +223,8 @@ func (c *Client) GetTorrentByID(ctx context.Context, torrentID string) (*domain.
 		Id:       strconv.Itoa(r.Response.Torrent.Id),
 		InfoHash: r.Response.Torrent.InfoHash,
 		Size:     strconv.FormatUint(r.Response.Torrent.Size, 10),
+		Seeders:  strconv.Itoa(r.Response.Torrent.Seeders),
+		Leechers: strconv.Itoa(r.Response.Torrent.Leechers),
 	}
 
 	return t, nil
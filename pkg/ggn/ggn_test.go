@@ -73,6 +73,8 @@ func Test_client_GetTorrentByID(t *testing.T) {
 				Id:       "422368",
 				InfoHash: "78DA2811E6732012B8224198D4DC2FD49A5E950F",
 				Size:     "134800",
+				Seeders:  "10",
+				Leechers: "0",
 			},
 			wantErr: false,
 		},
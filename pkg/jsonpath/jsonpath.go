@@ -0,0 +1,183 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package jsonpath selects values of decoded json with the subset of JSONPath that json apis need:
+// the root $, child keys .key and ['key'], indexes [0] and [-1] and the wildcards .* and [*].
+package jsonpath
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+// Path is a parsed JSONPath
+type Path struct {
+	raw   string
+	steps []step
+}
+
+// Parse parses a path like $.data.releases[*] or $['name']. The root $ is optional, so name selects the same as $.name.
+func Parse(path string) (Path, error) {
+	p := Path{raw: path}
+
+	s := strings.TrimSpace(path)
+	if s == "" {
+		return p, errors.New("empty json path")
+	}
+
+	s = strings.TrimPrefix(s, "$")
+	if s != "" && s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if strings.HasPrefix(s, ".") {
+				return p, errors.New("invalid json path %s: recursive descent is not supported", path)
+			}
+
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+
+			name := s[:end]
+			if name == "" {
+				return p, errors.New("invalid json path %s: empty key", path)
+			}
+
+			if name == "*" {
+				p.steps = append(p.steps, step{kind: stepWildcard})
+			} else {
+				p.steps = append(p.steps, step{kind: stepKey, key: name})
+			}
+
+			s = s[end:]
+
+		case '[':
+			end := closingBracket(s)
+			if end < 0 {
+				return p, errors.New("invalid json path %s: missing ]", path)
+			}
+
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, step{kind: stepWildcard})
+
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, step{kind: stepKey, key: inner[1 : len(inner)-1]})
+
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return p, errors.New("invalid json path %s: unsupported selector [%s]", path, inner)
+				}
+
+				p.steps = append(p.steps, step{kind: stepIndex, index: index})
+			}
+
+		default:
+			return p, errors.New("invalid json path %s: unexpected %q", path, s[0])
+		}
+	}
+
+	return p, nil
+}
+
+// closingBracket returns the index of the ] closing the selector at the start of s, skipping quoted keys
+func closingBracket(s string) int {
+	var quote byte
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '\'' || s[i] == '"':
+			quote = s[i]
+		case s[i] == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (p Path) String() string {
+	return p.raw
+}
+
+// Get returns the values the path selects in data, which is json decoded into any.
+// Wildcards select the elements of arrays and the values of objects ordered by key.
+func (p Path) Get(data any) []any {
+	current := []any{data}
+
+	for _, st := range p.steps {
+		next := make([]any, 0, len(current))
+
+		for _, v := range current {
+			switch st.kind {
+			case stepKey:
+				if obj, ok := v.(map[string]any); ok {
+					if child, ok := obj[st.key]; ok {
+						next = append(next, child)
+					}
+				}
+
+			case stepIndex:
+				if arr, ok := v.([]any); ok {
+					i := st.index
+					if i < 0 {
+						i += len(arr)
+					}
+					if i >= 0 && i < len(arr) {
+						next = append(next, arr[i])
+					}
+				}
+
+			case stepWildcard:
+				switch t := v.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					keys := make([]string, 0, len(t))
+					for k := range t {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+
+					for _, k := range keys {
+						next = append(next, t[k])
+					}
+				}
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "root", path: "$"},
+		{name: "child", path: "$.data.releases"},
+		{name: "relative", path: "name"},
+		{name: "wildcard", path: "$.data[*].name"},
+		{name: "quoted", path: "$['release name']"},
+		{name: "index", path: "$.files[-1]"},
+		{name: "empty", path: "", wantErr: true},
+		{name: "recursive", path: "$..name", wantErr: true},
+		{name: "unclosed", path: "$.data[0", wantErr: true},
+		{name: "filter", path: "$.data[?(@.size > 1)]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPath_Get(t *testing.T) {
+	var data any
+	err := json.Unmarshal([]byte(`{
+		"data": [
+			{"name": "Show.S01E01", "tags": ["hd", "web"], "release name": "a"},
+			{"name": "Show.S01E02", "tags": ["sd"]}
+		],
+		"meta": {"b": 2, "a": 1}
+	}`), &data)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		want []any
+	}{
+		{name: "wildcard_key", path: "$.data[*].name", want: []any{"Show.S01E01", "Show.S01E02"}},
+		{name: "relative", path: "data[0].name", want: []any{"Show.S01E01"}},
+		{name: "negative_index", path: "$.data[-1].tags[0]", want: []any{"sd"}},
+		{name: "quoted_key", path: "$.data[0]['release name']", want: []any{"a"}},
+		{name: "object_wildcard", path: "$.meta.*", want: []any{float64(1), float64(2)}},
+		{name: "missing", path: "$.data[5].name", want: []any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(tt.path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p.Get(data))
+		})
+	}
+}
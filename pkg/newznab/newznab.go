@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,8 +21,13 @@ import (
 
 const DefaultTimeout = 60
 
+// ErrNotModified is returned by GetFeedIfModified when the feed did not change
+var ErrNotModified = errors.Sentinel("newznab: feed not modified")
+
 type Client interface {
 	GetFeed(ctx context.Context) (*Feed, error)
+	GetFeedIfModified(ctx context.Context, etag string, lastModified string) (*Feed, error)
+	GetFeedPage(ctx context.Context, offset int, limit int) (*Feed, error)
 	GetCaps(ctx context.Context) (*Caps, error)
 	Caps() *Caps
 }
@@ -53,6 +59,9 @@ type Config struct {
 	UseBasicAuth bool
 	BasicAuth    BasicAuth
 
+	// Transport replaces the default transport, eg. to request through a proxy
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
@@ -70,6 +79,10 @@ func NewClient(config Config) Client {
 		httpClient.Timeout = time.Second * config.Timeout
 	}
 
+	if config.Transport != nil {
+		httpClient.Transport = config.Transport
+	}
+
 	c := &client{
 		http:   httpClient,
 		Host:   config.Host,
@@ -139,7 +152,7 @@ func (c *client) get(ctx context.Context, endpoint string, queryParams map[strin
 	return resp.StatusCode, &response, nil
 }
 
-func (c *client) getData(ctx context.Context, endpoint string, queryParams map[string]string) (*http.Response, error) {
+func (c *client) getData(ctx context.Context, endpoint string, queryParams map[string]string, header http.Header) (*http.Response, error) {
 	u, err := url.Parse(c.Host)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not build request")
@@ -156,10 +169,10 @@ func (c *client) getData(ctx context.Context, endpoint string, queryParams map[s
 	}
 
 	for k, v := range queryParams {
-		if qp.Has("t") {
+		if k == "t" && qp.Has("t") {
 			continue
 		}
-		qp.Add(k, v)
+		qp.Set(k, v)
 	}
 
 	u.RawQuery = qp.Encode()
@@ -170,6 +183,12 @@ func (c *client) getData(ctx context.Context, endpoint string, queryParams map[s
 		return nil, errors.Wrap(err, "could not build request")
 	}
 
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
 	if c.UseBasicAuth {
 		req.SetBasicAuth(c.BasicAuth.Username, c.BasicAuth.Password)
 	}
@@ -183,16 +202,49 @@ func (c *client) getData(ctx context.Context, endpoint string, queryParams map[s
 }
 
 func (c *client) GetFeed(ctx context.Context) (*Feed, error) {
+	return c.GetFeedIfModified(ctx, "", "")
+}
 
-	p := map[string]string{"t": "search"}
+// GetFeedIfModified gets the feed with a conditional request when the validators of the last response are set.
+// It returns ErrNotModified when the indexer answers the feed did not change.
+func (c *client) GetFeedIfModified(ctx context.Context, etag string, lastModified string) (*Feed, error) {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
 
-	resp, err := c.getData(ctx, "", p)
+	return c.getFeed(ctx, map[string]string{"t": "search"}, header)
+}
+
+// GetFeedPage gets the older items of the feed, skipping the newest offset items.
+func (c *client) GetFeedPage(ctx context.Context, offset int, limit int) (*Feed, error) {
+	p := map[string]string{
+		"t":      "search",
+		"offset": strconv.Itoa(offset),
+	}
+
+	if limit > 0 {
+		p["limit"] = strconv.Itoa(limit)
+	}
+
+	return c.getFeed(ctx, p, nil)
+}
+
+func (c *client) getFeed(ctx context.Context, p map[string]string, header http.Header) (*Feed, error) {
+	resp, err := c.getData(ctx, "", p, header)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get feed")
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
 	dump, err := httputil.DumpResponse(resp, true)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not dump response")
@@ -215,6 +267,8 @@ func (c *client) GetFeed(ctx context.Context) (*Feed, error) {
 	}
 
 	response.Raw = buf.String()
+	response.ETag = resp.Header.Get("ETag")
+	response.LastModified = resp.Header.Get("Last-Modified")
 
 	if c.Capabilities != nil {
 		for _, item := range response.Channel.Items {
@@ -0,0 +1,207 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// Priorities of the NZBGet queue
+const (
+	PriorityVeryLow  = -100
+	PriorityLow      = -50
+	PriorityNormal   = 0
+	PriorityHigh     = 50
+	PriorityVeryHigh = 100
+	PriorityForce    = 900
+)
+
+// DupeModeScore is the default duplicate mode of NZBGet, of the duplicates the one with the highest score is kept
+const DupeModeScore = "SCORE"
+
+type Client struct {
+	addr     string
+	username string
+	password string
+
+	log *log.Logger
+
+	Http *http.Client
+}
+
+type Options struct {
+	// Addr is the url of the web interface like http://localhost:6789
+	Addr string
+
+	// Username and Password are the control username and password of NZBGet
+	Username string
+	Password string
+
+	Log *log.Logger
+}
+
+func New(opts Options) *Client {
+	c := &Client{
+		addr:     opts.Addr,
+		username: opts.Username,
+		password: opts.Password,
+		log:      log.New(io.Discard, "", log.LstdFlags),
+		Http: &http.Client{
+			Timeout: time.Second * 60,
+		},
+	}
+
+	if opts.Log != nil {
+		c.log = opts.Log
+	}
+
+	return c
+}
+
+type rpcRequest struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	ID     int           `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error of a failed call
+type RPCError struct {
+	Name    string `json:"name"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return e.Name + ": " + e.Message
+}
+
+// call sends the method with its params to the json-rpc api and decodes the result into result
+func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	addr, err := url.JoinPath(c.addr, "/jsonrpc")
+	if err != nil {
+		return errors.Wrap(err, "invalid url: %s", c.addr)
+	}
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	body, err := json.Marshal(rpcRequest{Method: method, Params: params, ID: 1})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.Http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error calling %s", method)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized: bad credentials")
+	} else if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status calling %s: %d", method, res.StatusCode)
+	}
+
+	var data rpcResponse
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return errors.Wrap(err, "could not decode response of %s", method)
+	}
+
+	if data.Error != nil {
+		return data.Error
+	}
+
+	if err := json.Unmarshal(data.Result, result); err != nil {
+		return errors.Wrap(err, "could not decode result of %s", method)
+	}
+
+	return nil
+}
+
+// Version returns the version of NZBGet
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := c.call(ctx, "version", nil, &version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// Append adds the nzb at the url to the queue and returns its id
+func (c *Client) Append(ctx context.Context, r AppendRequest) (int64, error) {
+	dupeMode := r.DupeMode
+	if dupeMode == "" {
+		dupeMode = DupeModeScore
+	}
+
+	params := []interface{}{
+		r.Name,
+		r.Url,
+		r.Category,
+		r.Priority,
+		false, // add to top
+		r.AddPaused,
+		r.DupeKey,
+		r.DupeScore,
+		dupeMode,
+		[]interface{}{}, // post-processing parameters
+	}
+
+	var id int64
+	if err := c.call(ctx, "append", params, &id); err != nil {
+		return 0, err
+	}
+
+	// nzbget answers 0 or less when the nzb could not be added
+	if id <= 0 {
+		return 0, errors.New("nzbget could not add nzb: %s", r.Url)
+	}
+
+	c.log.Printf("nzbget append: added %s with id %d", r.Url, id)
+
+	return id, nil
+}
+
+type AppendRequest struct {
+	// Name is the nzb file name, nzbget names it after the url when empty
+	Name     string
+	Url      string
+	Category string
+	Priority int
+
+	AddPaused bool
+
+	// DupeKey marks nzbs with the same key as duplicates, with DupeScore and DupeMode deciding which is kept
+	DupeKey   string
+	DupeScore int
+	DupeMode  string
+}
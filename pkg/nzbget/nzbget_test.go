@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nzbget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(t *testing.T, handler func(method string, params []interface{}) string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "nzbget" || pass != "tegbzn6789" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/jsonrpc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var req rpcRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Write([]byte(handler(req.Method, req.Params)))
+	}))
+}
+
+func TestClient_Version(t *testing.T) {
+	srv := testServer(t, func(method string, params []interface{}) string {
+		assert.Equal(t, "version", method)
+		assert.Empty(t, params)
+		return `{"version": "1.1", "result": "21.1"}`
+	})
+	defer srv.Close()
+
+	c := New(Options{Addr: srv.URL, Username: "nzbget", Password: "tegbzn6789"})
+
+	version, err := c.Version(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "21.1", version)
+
+	_, err = New(Options{Addr: srv.URL, Username: "nzbget", Password: "wrong"}).Version(context.Background())
+	assert.EqualError(t, err, "unauthorized: bad credentials")
+}
+
+func TestClient_Append(t *testing.T) {
+	srv := testServer(t, func(method string, params []interface{}) string {
+		assert.Equal(t, "append", method)
+
+		if params[1] == "https://example.com/fail.nzb" {
+			return `{"version": "1.1", "result": 0}`
+		}
+		if params[1] == "https://example.com/error.nzb" {
+			return `{"version": "1.1", "error": {"name": "JSONRPCError", "code": 1, "message": "Invalid parameter"}}`
+		}
+
+		assert.Equal(t, []interface{}{"Show.S01E01.nzb", "https://example.com/1.nzb", "tv", float64(50), false, true, "show-s01e01", float64(0), "SCORE", []interface{}{}}, params)
+		return `{"version": "1.1", "result": 12}`
+	})
+	defer srv.Close()
+
+	c := New(Options{Addr: srv.URL, Username: "nzbget", Password: "tegbzn6789"})
+
+	id, err := c.Append(context.Background(), AppendRequest{
+		Name:      "Show.S01E01.nzb",
+		Url:       "https://example.com/1.nzb",
+		Category:  "tv",
+		Priority:  PriorityHigh,
+		AddPaused: true,
+		DupeKey:   "show-s01e01",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), id)
+
+	_, err = c.Append(context.Background(), AppendRequest{Url: "https://example.com/fail.nzb"})
+	assert.EqualError(t, err, "nzbget could not add nzb: https://example.com/fail.nzb")
+
+	_, err = c.Append(context.Background(), AppendRequest{Url: "https://example.com/error.nzb"})
+	assert.EqualError(t, err, "JSONRPCError: Invalid parameter")
+}
@@ -205,6 +205,8 @@ func (c *Client) GetTorrentByID(ctx context.Context, torrentID string) (*domain.
 		InfoHash: r.Response.Torrent.InfoHash,
 		Size:     strconv.Itoa(r.Response.Torrent.Size),
 		Uploader: r.Response.Torrent.Username,
+		Seeders:  strconv.Itoa(r.Response.Torrent.Seeders),
+		Leechers: strconv.Itoa(r.Response.Torrent.Leechers),
 	}
 
 	return res, nil
@@ -70,6 +70,8 @@ func TestOrpheusClient_GetTorrentByID(t *testing.T) {
 				InfoHash: "",
 				Size:     "255299244",
 				Uploader: "uploader",
+				Seeders:  "0",
+				Leechers: "0",
 			},
 			wantErr: "",
 		},
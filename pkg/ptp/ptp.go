@@ -160,6 +160,8 @@ func (c *Client) GetTorrentByID(ctx context.Context, torrentID string) (*domain.
 				Id:       torrent.Id,
 				InfoHash: torrent.InfoHash,
 				Size:     torrent.Size,
+				Seeders:  torrent.Seeders,
+				Leechers: torrent.Leechers,
 			}, nil
 		}
 	}
@@ -74,6 +74,8 @@ func TestPTPClient_GetTorrentByID(t *testing.T) {
 				Id:       "000001",
 				InfoHash: "F57AA86DFB03F87FCC7636E310D35918442EAE5C",
 				Size:     "1344512700",
+				Seeders:  "19",
+				Leechers: "0",
 			},
 			wantErr: false,
 		},
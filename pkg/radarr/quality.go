@@ -0,0 +1,175 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package radarr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type QualityDefinition struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Revision struct {
+	Version int `json:"version"`
+	Real    int `json:"real"`
+}
+
+type Quality struct {
+	Quality  QualityDefinition `json:"quality"`
+	Revision Revision          `json:"revision"`
+}
+
+type MovieFile struct {
+	ID                  int     `json:"id"`
+	Quality             Quality `json:"quality"`
+	QualityCutoffNotMet bool    `json:"qualityCutoffNotMet"`
+}
+
+type Movie struct {
+	ID               int        `json:"id"`
+	Title            string     `json:"title"`
+	Year             int        `json:"year"`
+	QualityProfileID int        `json:"qualityProfileId"`
+	HasFile          bool       `json:"hasFile"`
+	MovieFile        *MovieFile `json:"movieFile,omitempty"`
+}
+
+type ParsedMovieInfo struct {
+	Quality Quality `json:"quality"`
+}
+
+type ParseResponse struct {
+	Title           string          `json:"title"`
+	ParsedMovieInfo ParsedMovieInfo `json:"parsedMovieInfo"`
+	Movie           *Movie          `json:"movie,omitempty"`
+}
+
+// QualityProfileItem is either a single quality or a group of qualities that rank the same
+type QualityProfileItem struct {
+	ID      int                  `json:"id"`
+	Name    string               `json:"name"`
+	Quality *QualityDefinition   `json:"quality,omitempty"`
+	Items   []QualityProfileItem `json:"items"`
+	Allowed bool                 `json:"allowed"`
+}
+
+// QualityProfile holds the qualities of a profile ordered from lowest to highest
+type QualityProfile struct {
+	ID             int                  `json:"id"`
+	Name           string               `json:"name"`
+	UpgradeAllowed bool                 `json:"upgradeAllowed"`
+	Cutoff         int                  `json:"cutoff"`
+	Items          []QualityProfileItem `json:"items"`
+}
+
+// rank returns the position of the quality in the profile and if it is allowed
+func (p *QualityProfile) rank(qualityID int) (int, bool) {
+	for i, item := range p.Items {
+		if item.Quality != nil && item.Quality.ID == qualityID {
+			return i, item.Allowed
+		}
+
+		for _, groupItem := range item.Items {
+			if groupItem.Quality != nil && groupItem.Quality.ID == qualityID {
+				return i, item.Allowed
+			}
+		}
+	}
+
+	return -1, false
+}
+
+// cutoffRank returns the position of the cutoff which is either a quality or a group id
+func (p *QualityProfile) cutoffRank() int {
+	for i, item := range p.Items {
+		if (item.Quality != nil && item.Quality.ID == p.Cutoff) || (item.Quality == nil && item.ID == p.Cutoff) {
+			return i
+		}
+	}
+
+	return len(p.Items)
+}
+
+// IsUpgrade reports whether a release of quality is wanted over the existing file per the profile.
+// A nil current means there is no file yet. The reason is set when it is not an upgrade.
+// Custom formats are not taken into account.
+func (p *QualityProfile) IsUpgrade(current *Quality, release Quality) (bool, string) {
+	releaseRank, allowed := p.rank(release.Quality.ID)
+	if !allowed {
+		return false, fmt.Sprintf("quality %s is not wanted in profile %s", release.Quality.Name, p.Name)
+	}
+
+	if current == nil {
+		return true, ""
+	}
+
+	currentRank, _ := p.rank(current.Quality.ID)
+
+	switch {
+	case releaseRank > currentRank:
+		if !p.UpgradeAllowed {
+			return false, fmt.Sprintf("upgrades are not allowed in profile %s", p.Name)
+		}
+
+		if currentRank >= p.cutoffRank() {
+			return false, fmt.Sprintf("existing file %s already meets the cutoff of profile %s", current.Quality.Name, p.Name)
+		}
+
+		return true, ""
+
+	case releaseRank == currentRank && release.Revision.Version > current.Revision.Version:
+		// propers and repacks of the same quality
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("existing file %s is of equal or higher preference than %s", current.Quality.Name, release.Quality.Name)
+}
+
+func (c *client) Parse(ctx context.Context, title string) (*ParseResponse, error) {
+	params := url.Values{}
+	params.Set("title", title)
+
+	status, res, err := c.get(ctx, "parse", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse title: %s", title)
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr parse unexpected status: %d", status)
+	}
+
+	response := ParseResponse{}
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
+
+func (c *client) GetQualityProfile(ctx context.Context, id int) (*QualityProfile, error) {
+	status, res, err := c.get(ctx, "qualityprofile/"+strconv.Itoa(id), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get quality profile: %d", id)
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("radarr qualityprofile unexpected status: %d", status)
+	}
+
+	response := QualityProfile{}
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal data")
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package radarr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_client_Parse(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v3/parse", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "That.Movie.2023.1080p.BluRay.x264-GROUP", r.URL.Query().Get("title"))
+
+		jsonPayload, _ := os.ReadFile("testdata/parse_response.json")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(jsonPayload)
+	})
+
+	mux.HandleFunc("/api/v3/qualityprofile/4", func(w http.ResponseWriter, r *http.Request) {
+		jsonPayload, _ := os.ReadFile("testdata/quality_profile_response.json")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(jsonPayload)
+	})
+
+	c := New(Config{Hostname: ts.URL, APIKey: "mock-key"})
+
+	parsed, err := c.Parse(context.Background(), "That.Movie.2023.1080p.BluRay.x264-GROUP")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bluray-1080p", parsed.ParsedMovieInfo.Quality.Quality.Name)
+	assert.Equal(t, 4, parsed.Movie.QualityProfileID)
+	assert.Equal(t, "WEBDL-1080p", parsed.Movie.MovieFile.Quality.Quality.Name)
+
+	profile, err := c.GetQualityProfile(context.Background(), parsed.Movie.QualityProfileID)
+	assert.NoError(t, err)
+	assert.Equal(t, "HD-1080p", profile.Name)
+
+	upgrade, _ := profile.IsUpgrade(&parsed.Movie.MovieFile.Quality, parsed.ParsedMovieInfo.Quality)
+	assert.True(t, upgrade)
+}
+
+func TestQualityProfile_IsUpgrade(t *testing.T) {
+	data, err := os.ReadFile("testdata/quality_profile_response.json")
+	assert.NoError(t, err)
+
+	var profile QualityProfile
+	assert.NoError(t, json.Unmarshal(data, &profile))
+
+	quality := func(id int, name string, version int) Quality {
+		return Quality{Quality: QualityDefinition{ID: id, Name: name}, Revision: Revision{Version: version}}
+	}
+
+	tests := []struct {
+		name    string
+		current *Quality
+		release Quality
+		want    bool
+	}{
+		{name: "no_file", current: nil, release: quality(3, "WEBDL-1080p", 1), want: true},
+		{name: "not_allowed", current: nil, release: quality(1, "SDTV", 1), want: false},
+		{name: "higher", current: &Quality{Quality: QualityDefinition{ID: 3, Name: "WEBDL-1080p"}, Revision: Revision{Version: 1}}, release: quality(7, "Bluray-1080p", 1), want: true},
+		{name: "same_group", current: &Quality{Quality: QualityDefinition{ID: 3, Name: "WEBDL-1080p"}, Revision: Revision{Version: 1}}, release: quality(15, "WEBRip-1080p", 1), want: false},
+		{name: "lower", current: &Quality{Quality: QualityDefinition{ID: 7, Name: "Bluray-1080p"}, Revision: Revision{Version: 1}}, release: quality(3, "WEBDL-1080p", 1), want: false},
+		{name: "cutoff_met", current: &Quality{Quality: QualityDefinition{ID: 7, Name: "Bluray-1080p"}, Revision: Revision{Version: 1}}, release: quality(30, "Remux-1080p", 1), want: false},
+		{name: "proper", current: &Quality{Quality: QualityDefinition{ID: 7, Name: "Bluray-1080p"}, Revision: Revision{Version: 1}}, release: quality(7, "Bluray-1080p", 2), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := profile.IsUpgrade(tt.current, tt.release)
+			assert.Equal(t, tt.want, got, reason)
+			assert.Equal(t, tt.want, reason == "")
+		})
+	}
+}
@@ -31,6 +31,8 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	Parse(ctx context.Context, title string) (*ParseResponse, error)
+	GetQualityProfile(ctx context.Context, id int) (*QualityProfile, error)
 }
 
 type client struct {
@@ -96,7 +98,7 @@ func (r *BadRequestResponse) String() string {
 }
 
 func (c *client) Test(ctx context.Context) (*SystemStatusResponse, error) {
-	status, res, err := c.get(ctx, "system/status")
+	status, res, err := c.get(ctx, "system/status", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "radarr error running test")
 	}
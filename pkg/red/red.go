@@ -209,6 +209,8 @@ func (c *Client) GetTorrentByID(ctx context.Context, torrentID string) (*domain.
 		Id:       strconv.Itoa(r.Response.Torrent.Id),
 		InfoHash: r.Response.Torrent.InfoHash,
 		Size:     strconv.Itoa(r.Response.Torrent.Size),
+		Seeders:  strconv.Itoa(r.Response.Torrent.Seeders),
+		Leechers: strconv.Itoa(r.Response.Torrent.Leechers),
 	}, nil
 
 }
@@ -15,9 +15,10 @@ import (
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
-func (c *client) get(ctx context.Context, endpoint string) (int, []byte, error) {
+func (c *client) get(ctx context.Context, endpoint string, params url.Values) (int, []byte, error) {
 	u, err := url.Parse(c.config.Hostname)
 	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
+	u.RawQuery = params.Encode()
 	reqUrl := u.String()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, http.NoBody)
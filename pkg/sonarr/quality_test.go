@@ -0,0 +1,57 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package sonarr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualityProfile_IsUpgrade(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v3/qualityprofile/4", func(w http.ResponseWriter, r *http.Request) {
+		jsonPayload, _ := os.ReadFile("testdata/quality_profile_response.json")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(jsonPayload)
+	})
+
+	profile, err := New(Config{Hostname: ts.URL, APIKey: "mock-key"}).GetQualityProfile(context.Background(), 4)
+	assert.NoError(t, err)
+
+	quality := func(id int, name string, version int) Quality {
+		return Quality{Quality: QualityDefinition{ID: id, Name: name}, Revision: Revision{Version: version}}
+	}
+	webdl := quality(3, "WEBDL-1080p", 1)
+	bluray := quality(7, "Bluray-1080p", 1)
+
+	tests := []struct {
+		name    string
+		current *Quality
+		release Quality
+		want    bool
+	}{
+		{name: "no_file", current: nil, release: webdl, want: true},
+		{name: "not_allowed", current: nil, release: quality(1, "SDTV", 1), want: false},
+		{name: "higher", current: &webdl, release: bluray, want: true},
+		{name: "same_group", current: &webdl, release: quality(15, "WEBRip-1080p", 1), want: false},
+		{name: "lower", current: &bluray, release: webdl, want: false},
+		{name: "cutoff_met", current: &bluray, release: quality(30, "Remux-1080p", 1), want: false},
+		{name: "repack", current: &bluray, release: quality(7, "Bluray-1080p", 2), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := profile.IsUpgrade(tt.current, tt.release)
+			assert.Equal(t, tt.want, got, reason)
+		})
+	}
+}
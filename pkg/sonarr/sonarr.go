@@ -32,6 +32,9 @@ type Config struct {
 type Client interface {
 	Test(ctx context.Context) (*SystemStatusResponse, error)
 	Push(ctx context.Context, release Release) ([]string, error)
+	Parse(ctx context.Context, title string) (*ParseResponse, error)
+	GetQualityProfile(ctx context.Context, id int) (*QualityProfile, error)
+	GetEpisodeFile(ctx context.Context, id int) (*EpisodeFile, error)
 }
 
 type client struct {
@@ -99,7 +102,7 @@ type SystemStatusResponse struct {
 }
 
 func (c *client) Test(ctx context.Context) (*SystemStatusResponse, error) {
-	status, res, err := c.get(ctx, "system/status")
+	status, res, err := c.get(ctx, "system/status", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not make Test")
 	}
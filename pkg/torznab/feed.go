@@ -13,6 +13,10 @@ import (
 type Feed struct {
 	Channel Channel `xml:"channel"`
 	Raw     string
+
+	// ETag and LastModified are the validators of the response, for conditional requests of the feed
+	ETag         string
+	LastModified string
 }
 
 func (f Feed) Len() int {
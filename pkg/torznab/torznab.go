@@ -12,16 +12,23 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
+// ErrNotModified is returned by FetchFeedIfModified when the feed did not change
+var ErrNotModified = errors.Sentinel("torznab: feed not modified")
+
 type Client interface {
 	FetchFeed(ctx context.Context) (*Feed, error)
+	FetchFeedIfModified(ctx context.Context, etag string, lastModified string) (*Feed, error)
+	FetchFeedPage(ctx context.Context, offset int, limit int) (*Feed, error)
 	FetchCaps(ctx context.Context) (*Caps, error)
 	GetCaps() *Caps
+	SetCaps(caps *Caps)
 }
 
 type client struct {
@@ -51,6 +58,9 @@ type Config struct {
 	UseBasicAuth bool
 	BasicAuth    BasicAuth
 
+	// Transport replaces the default transport, eg. to request through a proxy
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
@@ -64,6 +74,10 @@ func NewClient(config Config) Client {
 		Timeout: config.Timeout,
 	}
 
+	if config.Transport != nil {
+		httpClient.Transport = config.Transport
+	}
+
 	c := &client{
 		http:   httpClient,
 		Host:   config.Host,
@@ -78,7 +92,7 @@ func NewClient(config Config) Client {
 	return c
 }
 
-func (c *client) get(ctx context.Context, endpoint string, opts map[string]string) (int, *Feed, error) {
+func (c *client) get(ctx context.Context, endpoint string, opts map[string]string, header http.Header) (int, *Feed, error) {
 	params := url.Values{
 		"t": {"search"},
 	}
@@ -87,6 +101,10 @@ func (c *client) get(ctx context.Context, endpoint string, opts map[string]strin
 		params.Add("apikey", c.ApiKey)
 	}
 
+	for k, v := range opts {
+		params.Set(k, v)
+	}
+
 	u, err := url.Parse(c.Host)
 	u.Path = strings.TrimSuffix(u.Path, "/")
 	u.RawQuery = params.Encode()
@@ -97,6 +115,12 @@ func (c *client) get(ctx context.Context, endpoint string, opts map[string]strin
 		return 0, nil, errors.Wrap(err, "could not build request")
 	}
 
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
 	if c.UseBasicAuth {
 		req.SetBasicAuth(c.BasicAuth.Username, c.BasicAuth.Password)
 	}
@@ -113,6 +137,10 @@ func (c *client) get(ctx context.Context, endpoint string, opts map[string]strin
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return resp.StatusCode, nil, nil
+	}
+
 	dump, err := httputil.DumpResponse(resp, true)
 	if err != nil {
 		return 0, nil, errors.Wrap(err, "could not dump response")
@@ -131,11 +159,44 @@ func (c *client) get(ctx context.Context, endpoint string, opts map[string]strin
 	}
 
 	response.Raw = buf.String()
+	response.ETag = resp.Header.Get("ETag")
+	response.LastModified = resp.Header.Get("Last-Modified")
 
 	return resp.StatusCode, &response, nil
 }
 
 func (c *client) FetchFeed(ctx context.Context) (*Feed, error) {
+	return c.FetchFeedIfModified(ctx, "", "")
+}
+
+// FetchFeedIfModified fetches the feed with a conditional request when the validators of the last response are set.
+// It returns ErrNotModified when the indexer answers the feed did not change.
+func (c *client) FetchFeedIfModified(ctx context.Context, etag string, lastModified string) (*Feed, error) {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+
+	return c.fetchFeed(ctx, nil, header)
+}
+
+// FetchFeedPage fetches the older items of the feed, skipping the newest offset items.
+func (c *client) FetchFeedPage(ctx context.Context, offset int, limit int) (*Feed, error) {
+	opts := map[string]string{
+		"offset": strconv.Itoa(offset),
+	}
+
+	if limit > 0 {
+		opts["limit"] = strconv.Itoa(limit)
+	}
+
+	return c.fetchFeed(ctx, opts, nil)
+}
+
+func (c *client) fetchFeed(ctx context.Context, opts map[string]string, header http.Header) (*Feed, error) {
 	if c.Capabilities == nil {
 		status, caps, err := c.getCaps(ctx, "?t=caps", nil)
 		if err != nil {
@@ -149,11 +210,15 @@ func (c *client) FetchFeed(ctx context.Context) (*Feed, error) {
 		c.Capabilities = caps
 	}
 
-	status, res, err := c.get(ctx, "", nil)
+	status, res, err := c.get(ctx, "", opts, header)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get feed")
 	}
 
+	if status == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
 	if status != http.StatusOK {
 		return nil, errors.New("could not get feed")
 	}
@@ -244,12 +309,17 @@ func (c *client) GetCaps() *Caps {
 	return c.Capabilities
 }
 
+// SetCaps sets the caps the categories of the feed items are mapped with, eg. stored caps of the feed
+func (c *client) SetCaps(caps *Caps) {
+	c.Capabilities = caps
+}
+
 func (c *client) Search(ctx context.Context, query string) ([]*FeedItem, error) {
 	v := url.Values{}
 	v.Add("q", query)
 	params := v.Encode()
 
-	status, res, err := c.get(ctx, "&t=search&"+params, nil)
+	status, res, err := c.get(ctx, "&t=search&"+params, nil, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not search feed")
 	}
@@ -211,11 +211,23 @@ export const APIClient = {
     toggleEnable: (id: number, enabled: boolean) => appClient.Put(`api/filters/${id}/enabled`, {
       body: { enabled }
     }),
-    delete: (id: number) => appClient.Delete(`api/filters/${id}`)
+    delete: (id: number) => appClient.Delete(`api/filters/${id}`),
+    importPreset: (req: FilterPresetImportRequest) => appClient.Post<FilterPresetImportResult>("api/filters/presets/import", {
+      body: req
+    }),
+    syncPresets: () => appClient.Post<FilterPresetImportResult[]>("api/filters/presets/sync"),
+    getTags: () => appClient.Get<string[]>("api/filters/tags"),
+    toggleEnableByTag: (tag: string, enabled: boolean) => appClient.Put<FilterBulkResult>(`api/filters/tags/${encodeURIComponent(tag)}/enabled`, {
+      body: { enabled }
+    }),
+    deleteByTag: (tag: string) => appClient.Delete(`api/filters/tags/${encodeURIComponent(tag)}`),
+    exportByTag: (tag: string) => appClient.Get<FilterExport[]>(`api/filters/tags/${encodeURIComponent(tag)}/export`),
+    getFlags: () => appClient.Get<FilterFlag[]>("api/filters/flags"),
+    clearFlag: (flag: string) => appClient.Delete(`api/filters/flags/${encodeURIComponent(flag)}`)
   },
   feeds: {
     find: () => appClient.Get<Feed[]>("api/feeds"),
-    create: (feed: FeedCreate) => appClient.Post("api/feeds", {
+    create: (feed: FeedCreate) => appClient.Post<Feed>("api/feeds", {
       body: feed
     }),
     toggleEnable: (id: number, enabled: boolean) => appClient.Patch(`api/feeds/${id}/enabled`, {
@@ -225,9 +237,20 @@ export const APIClient = {
       body: feed
     }),
     delete: (id: number) => appClient.Delete(`api/feeds/${id}`),
+    getCache: (id: number, limit?: number) => appClient.Get<FeedCache>(`api/feeds/${id}/cache`, {
+      queryString: limit ? { limit } : undefined
+    }),
     deleteCache: (id: number) => appClient.Delete(`api/feeds/${id}/cache`),
+    backfill: (id: number, settings?: FeedBackfillSettings) => appClient.Post(`api/feeds/${id}/backfill`, {
+      body: settings
+    }),
+    refreshCaps: (id: number) => appClient.Post<FeedCaps>(`api/feeds/${id}/caps`),
+    getRuns: (id: number) => appClient.Get<FeedRun[]>(`api/feeds/${id}/runs`),
     test: (feed: Feed) => appClient.Post("api/feeds/test", {
       body: feed
+    }),
+    preview: (feed: Feed) => appClient.Post<FeedPreview>("api/feeds/preview", {
+      body: feed
     })
   },
   indexers: {
@@ -237,6 +260,8 @@ export const APIClient = {
     getAll: () => appClient.Get<IndexerDefinition[]>("api/indexer"),
     // returns all possible indexer definitions
     getSchema: () => appClient.Get<IndexerDefinition[]>("api/indexer/schema"),
+    // reloads the custom definitions and rebinds the announce parsers of running irc networks
+    reloadDefinitions: () => appClient.Post<{ reloaded: string[] }>("api/indexer/definitions/reload"),
     create: (indexer: Indexer) => appClient.Post<Indexer>("api/indexer", {
       body: indexer
     }),
@@ -264,6 +289,29 @@ export const APIClient = {
     sendCmd: (cmd: SendIrcCmdRequest) => appClient.Post(`api/irc/network/${cmd.network_id}/cmd`, {
       body: cmd
     }),
+    sendRawCmd: (cmd: SendIrcRawCmdRequest) => appClient.Post(`api/irc/network/${cmd.network_id}/raw`, {
+      body: cmd
+    }),
+    findLogs: (networkId: number, params: IrcLogQueryParams) => appClient.Get<IrcLogResponse>(`api/irc/network/${networkId}/logs`, {
+      queryString: { ...params }
+    }),
+    replay: (req: IrcReplayRequest) => appClient.Post<IrcReplayResult>(`api/irc/network/${req.network_id}/replay`, {
+      body: req
+    }),
+    inject: (req: IrcInjectRequest) => appClient.Post<IrcInjectResult>(`api/irc/network/${req.network_id}/inject`, {
+      body: req
+    }),
+    getTriggers: (networkId: number) => appClient.Get<IrcTrigger[]>(`api/irc/network/${networkId}/triggers`),
+    createTrigger: (trigger: IrcTrigger) => appClient.Post<IrcTrigger>(`api/irc/network/${trigger.network_id}/triggers`, {
+      body: trigger
+    }),
+    updateTrigger: (trigger: IrcTrigger) => appClient.Put<IrcTrigger>(`api/irc/trigger/${trigger.id}`, {
+      body: trigger
+    }),
+    deleteTrigger: (id: number) => appClient.Delete(`api/irc/trigger/${id}`),
+    getQuarantine: (networkId: number) => appClient.Get<IrcQuarantinedAnnounce[]>(`api/irc/network/${networkId}/quarantine`),
+    releaseQuarantine: (networkId: number) => appClient.Post<{ released: number }>(`api/irc/network/${networkId}/quarantine/release`),
+    dropQuarantine: (networkId: number) => appClient.Delete(`api/irc/network/${networkId}/quarantine`),
     events: (network: string) => new EventSource(
       `${sseBaseUrl()}api/irc/events?stream=${encodeRFC3986URIComponent(network)}`,
       { withCredentials: true }
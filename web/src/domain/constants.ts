@@ -81,6 +81,19 @@ export const hdr = [
 
 export const HDR_OPTIONS: MultiSelectOption[] = hdr.map(v => ({ value: v, label: v, key: v }));
 
+// combined HDR formats used by the HDR preference, DV without HDR fallback layer is "DV"
+export const hdr_formats = [
+  "DV HDR10+",
+  "DV HDR10",
+  "DV HDR",
+  "DV",
+  "HDR10+",
+  "HDR10",
+  "HDR",
+  "HLG",
+  "SDR"
+];
+
 export const quality_other = [
   "REMUX",
   "HYBRID",
@@ -130,6 +143,33 @@ export const qualityMusic = [
 
 export const QUALITY_MUSIC_OPTIONS: MultiSelectOption[] = qualityMusic.map(v => ({ value: v, label: v, key: v }));
 
+export const bitDepthMusic = [
+  "16",
+  "24"
+];
+
+export const BIT_DEPTH_MUSIC_OPTIONS: MultiSelectOption[] = bitDepthMusic.map(v => ({ value: v, label: `${v}bit`, key: v }));
+
+export const sampleRateMusic = [
+  "44.1",
+  "48",
+  "88.2",
+  "96",
+  "176.4",
+  "192"
+];
+
+export const SAMPLE_RATE_MUSIC_OPTIONS: MultiSelectOption[] = sampleRateMusic.map(v => ({ value: v, label: `${v} kHz`, key: v }));
+
+export const audioChannels = [
+  "1.0",
+  "2.0",
+  "5.1",
+  "7.1"
+];
+
+export const AUDIO_CHANNELS_OPTIONS: MultiSelectOption[] = audioChannels.map(v => ({ value: v, label: v, key: v }));
+
 export const releaseTypeMusic = [
   "Album",
   "Anthology",
@@ -219,6 +259,23 @@ export const languageOptions = [
 
 export const LANGUAGE_OPTIONS = languageOptions.map(v => ({ value: v, label: v, key: v }));
 
+// subtitle tags are ranked by the subtitle preference, all other language tags by the language preference
+export const subtitle_preference_options = [
+  "NONE",
+  "DKSUBS",
+  "HARDSUB",
+  "Hardcoded",
+  "HebSub",
+  "MULTiSUB",
+  "MULTiSUBS",
+  "SUBBED",
+  "SUBFORCED",
+  "SUBPACK",
+  "UNSUBBED"
+];
+
+export const language_preference_options = languageOptions.filter(v => !subtitle_preference_options.includes(v));
+
 export interface RadioFieldsetOption {
   label: string;
   description: string;
@@ -257,6 +314,11 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
     description: "Add torrents directly to Porla",
     value: "PORLA"
   },
+  {
+    label: "aria2",
+    description: "Add torrents directly to aria2",
+    value: "ARIA2"
+  },
   {
     label: "Radarr",
     description: "Send to Radarr and let it decide",
@@ -287,6 +349,12 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
     description: "Add nzbs directly to SABnzbd",
     value: "SABNZBD",
     type: "nzb"
+  },
+  {
+    label: "NZBGet",
+    description: "Add nzbs directly to NZBGet",
+    value: "NZBGET",
+    type: "nzb"
   }
 ];
 
@@ -297,12 +365,14 @@ export const DownloadClientTypeNameMap: Record<DownloadClientType | string, stri
   "RTORRENT": "rTorrent",
   "TRANSMISSION": "Transmission",
   "PORLA": "Porla",
+  "ARIA2": "aria2",
   "RADARR": "Radarr",
   "SONARR": "Sonarr",
   "LIDARR": "Lidarr",
   "WHISPARR": "Whisparr",
   "READARR": "Readarr",
-  "SABNZBD": "SABnzbd"
+  "SABNZBD": "SABnzbd",
+  "NZBGET": "NZBGet"
 };
 
 export const ActionTypeOptions: RadioFieldsetOption[] = [
@@ -316,12 +386,14 @@ export const ActionTypeOptions: RadioFieldsetOption[] = [
   { label: "rTorrent", description: "Add torrents directly to rTorrent", value: "RTORRENT" },
   { label: "Transmission", description: "Add torrents directly to Transmission", value: "TRANSMISSION" },
   { label: "Porla", description: "Add torrents directly to Porla", value: "PORLA" },
+  { label: "aria2", description: "Add torrents directly to aria2", value: "ARIA2" },
   { label: "Radarr", description: "Send to Radarr and let it decide", value: "RADARR" },
   { label: "Sonarr", description: "Send to Sonarr and let it decide", value: "SONARR" },
   { label: "Lidarr", description: "Send to Lidarr and let it decide", value: "LIDARR" },
   { label: "Whisparr", description: "Send to Whisparr and let it decide", value: "WHISPARR" },
   { label: "Readarr", description: "Send to Readarr and let it decide", value: "READARR" },
-  { label: "SABnzbd", description: "Add to SABnzbd", value: "SABNZBD" }
+  { label: "SABnzbd", description: "Add to SABnzbd", value: "SABNZBD" },
+  { label: "NZBGet", description: "Add to NZBGet", value: "NZBGET" }
 ];
 
 export const ActionTypeNameMap = {
@@ -335,12 +407,14 @@ export const ActionTypeNameMap = {
   "RTORRENT": "rTorrent",
   "TRANSMISSION": "Transmission",
   "PORLA": "Porla",
+  "ARIA2": "aria2",
   "RADARR": "Radarr",
   "SONARR": "Sonarr",
   "LIDARR": "Lidarr",
   "WHISPARR": "Whisparr",
   "READARR": "Readarr",
-  "SABNZBD": "SABnzbd"
+  "SABNZBD": "SABnzbd",
+  "NZBGET": "NZBGet"
 };
 
 export const ActionContentLayoutOptions: SelectGenericOption<ActionContentLayout>[] = [
@@ -399,9 +473,35 @@ export const NotificationTypeOptions: OptionBasicTyped<NotificationType>[] = [
   {
     label: "Gotify",
     value: "GOTIFY"
+  },
+  {
+    label: "Webhook",
+    value: "WEBHOOK"
   }
 ];
 
+export const IrcSplitActionOptions: OptionBasicTyped<string>[] = [
+  {
+    label: "Delay, process once the quarantine ends",
+    value: "DELAY"
+  },
+  {
+    label: "Hold, until released or dropped",
+    value: "HOLD"
+  }
+];
+
+export const IrcEncodingOptions: OptionBasicTyped<string>[] = [
+  { label: "UTF-8 only", value: "" },
+  { label: "ISO-8859-1 (Latin-1)", value: "iso-8859-1" },
+  { label: "ISO-8859-2 (Latin-2)", value: "iso-8859-2" },
+  { label: "ISO-8859-15 (Latin-9)", value: "iso-8859-15" },
+  { label: "Windows-1250", value: "windows-1250" },
+  { label: "Windows-1251", value: "windows-1251" },
+  { label: "Windows-1252", value: "windows-1252" },
+  { label: "KOI8-R", value: "koi8-r" }
+];
+
 export const IrcAuthMechanismTypeOptions: OptionBasicTyped<IrcAuthMechanism>[] = [
   {
     label: "None",
@@ -411,6 +511,10 @@ export const IrcAuthMechanismTypeOptions: OptionBasicTyped<IrcAuthMechanism>[] =
     label: "SASL (plain)",
     value: "SASL_PLAIN"
   },
+  {
+    label: "SASL (external, client certificate)",
+    value: "SASL_EXTERNAL"
+  },
   {
     label: "NickServ",
     value: "NICKSERV"
@@ -487,6 +591,11 @@ export const EventOptions: SelectOption[] = [
     value: "PUSH_ERROR",
     description: "On push error for the arrs or download client"
   },
+  {
+    label: "IRC Connected",
+    value: "IRC_CONNECTED",
+    description: "Connected to irc network"
+  },
   {
     label: "IRC Disconnected",
     value: "IRC_DISCONNECTED",
@@ -497,6 +606,41 @@ export const EventOptions: SelectOption[] = [
     value: "IRC_RECONNECTED",
     description: "Reconnected to irc network after error"
   },
+  {
+    label: "IRC Authentication Failed",
+    value: "IRC_AUTH_FAILED",
+    description: "SASL or NickServ authentication failed"
+  },
+  {
+    label: "IRC Kicked",
+    value: "IRC_KICKED",
+    description: "Kicked from an irc channel"
+  },
+  {
+    label: "IRC Banned",
+    value: "IRC_BANNED",
+    description: "Banned from an irc channel or server"
+  },
+  {
+    label: "IRC Trigger",
+    value: "IRC_TRIGGER",
+    description: "A keyword trigger matched a line in an irc channel"
+  },
+  {
+    label: "Feed Failing",
+    value: "FEED_FAILING",
+    description: "A feed kept failing to refresh"
+  },
+  {
+    label: "Feed Recovered",
+    value: "FEED_RECOVERED",
+    description: "A failing feed refreshed again"
+  },
+  {
+    label: "Feed Auth Failed",
+    value: "FEED_AUTH_FAILED",
+    description: "A feed was refused even after logging in again, or its login failed"
+  },
   {
     label: "New update",
     value: "APP_UPDATE_AVAILABLE",
@@ -515,6 +659,17 @@ export const FeedDownloadTypeOptions: OptionBasicTyped<FeedDownloadType>[] = [
   }
 ];
 
+export const FeedPushProtocolOptions: OptionBasicTyped<FeedPushProtocol>[] = [
+  {
+    label: "Server-sent events",
+    value: "SSE"
+  },
+  {
+    label: "WebSocket",
+    value: "WEBSOCKET"
+  }
+];
+
 export const tagsMatchLogicOptions: OptionBasic[] = [
   {
     label: "any",
@@ -526,6 +681,22 @@ export const tagsMatchLogicOptions: OptionBasic[] = [
   }
 ];
 
+export const FilterAnimeBatchOptions: OptionBasicTyped<FilterAnimeBatch>[] = [
+  { label: "Batches only", value: "BATCH" },
+  { label: "Single episodes only", value: "SINGLE" },
+];
+
+export const AnimeSubTypeOptions: OptionBasicTyped<AnimeSubType>[] = [
+  { label: "Fansub", value: "FANSUB" },
+  { label: "Official subs", value: "OFFICIAL" },
+];
+
+export const FilterChainModeOptions: OptionBasicTyped<FilterChainMode>[] = [
+  { label: "Next filter by priority", value: "NEXT" },
+  { label: "Stop processing", value: "STOP" },
+  { label: "Continue with filter", value: "FILTER" },
+];
+
 export const ExternalFilterTypeOptions: RadioFieldsetOption[] = [
   { label: "Exec", description: "Run a custom command", value: "EXEC" },
   { label: "Webhook", description: "Run webhook", value: "WEBHOOK" },
@@ -536,6 +707,11 @@ export const ExternalFilterTypeNameMap = {
   "WEBHOOK": "Webhook",
 };
 
+export const ExternalFilterExecModeOptions: OptionBasicTyped<ExternalExecMode>[] = [
+  { label: "Exit code", value: "EXIT_CODE" },
+  { label: "JSON", value: "JSON" },
+];
+
 export const ExternalFilterWebhookMethodOptions: OptionBasicTyped<WebhookMethod>[] = [
   { label: "GET", value: "GET" },
   { label: "POST", value: "POST" },
@@ -376,6 +376,42 @@ function FormFieldsSabnzbd() {
   );
 }
 
+function FormFieldsNzbget() {
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="Host"
+        help="Eg. http://ip:6789 or https://url.com/nzbget"
+      />
+
+      <TextFieldWide name="username" label="Username" help="ControlUsername of NZBGet" />
+      <PasswordFieldWide name="password" label="Password" help="ControlPassword of NZBGet" />
+    </div>
+  );
+}
+
+function FormFieldsAria2() {
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        required
+        name="host"
+        label="RPC url"
+        help="Eg. http://ip:6800/jsonrpc or ws://ip:6800/jsonrpc, https and wss for TLS"
+      />
+
+      <PasswordFieldWide name="settings.apikey" label="RPC secret" help="The --rpc-secret of aria2, if set" />
+
+      <SwitchGroupWide
+        name="tls_skip_verify"
+        label="Skip TLS verification (insecure)"
+      />
+    </div>
+  );
+}
+
 export interface componentMapType {
   [key: string]: ReactElement;
 }
@@ -387,12 +423,14 @@ export const componentMap: componentMapType = {
   RTORRENT: <FormFieldsRTorrent />,
   TRANSMISSION: <FormFieldsTransmission />,
   PORLA: <FormFieldsPorla />,
+  ARIA2: <FormFieldsAria2 />,
   RADARR: <FormFieldsArr />,
   SONARR: <FormFieldsArr />,
   LIDARR: <FormFieldsArr />,
   WHISPARR: <FormFieldsArr />,
   READARR: <FormFieldsArr />,
-  SABNZBD: <FormFieldsSabnzbd />
+  SABNZBD: <FormFieldsSabnzbd />,
+  NZBGET: <FormFieldsNzbget />
 };
 
 function FormFieldsRulesBasic() {
@@ -19,7 +19,7 @@ import { PasswordFieldWide, SwitchGroupWide, TextFieldWide } from "@components/i
 import { SlideOver } from "@components/panels";
 import Toast from "@components/notifications/Toast";
 import { SelectFieldBasic, SelectFieldCreatable } from "@components/inputs/select_wide";
-import { FeedDownloadTypeOptions } from "@domain/constants";
+import { FeedDownloadTypeOptions, FeedPushProtocolOptions } from "@domain/constants";
 import { feedKeys } from "@screens/settings/Feed";
 import { indexerKeys } from "@screens/settings/Indexer";
 import { DocsLink } from "@components/ExternalLink";
@@ -229,6 +229,79 @@ const RSSFeedSettingFields = (ind: IndexerDefinition, indexer: string) => {
   }
 };
 
+const JSONFeedSettingFields = (ind: IndexerDefinition, indexer: string) => {
+  if (indexer !== "") {
+    return (
+      <Fragment>
+        {ind && ind.json && ind.json.settings && (
+          <div className="">
+            <div className="px-4 space-y-1">
+              <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">JSON</Dialog.Title>
+              <p className="text-sm text-gray-500 dark:text-gray-200">
+                JSON API feed. Set the JSONPath of the releases in the response, eg. $.data, and of their fields, eg. $.name. More fields and pagination can be set in the feed settings.
+              </p>
+            </div>
+
+            <TextFieldWide name="name" label="Name" defaultValue="" />
+
+            {ind.json.settings.map((f: IndexerSetting, idx: number) => {
+              switch (f.type) {
+                case "text":
+                  return <TextFieldWide name={`feed.${f.name}`} label={f.label} required={f.required} key={idx} help={f.help} autoComplete="off" validate={validateField(f)} />;
+                case "secret":
+                  return <PasswordFieldWide name={`feed.${f.name}`} label={f.label} required={f.required} key={idx} help={f.help} defaultValue={f.default} validate={validateField(f)} />;
+              }
+              return null;
+            })}
+
+            <PasswordFieldWide name="feed.settings.json.headers.Authorization" label="Authorization" help="Authorization header, eg. Bearer token. Optional" />
+            <TextFieldWide name="feed.settings.json.items_path" label="Items" required={true} />
+            <TextFieldWide name="feed.settings.json.fields.title" label="Title" required={true} />
+            <TextFieldWide name="feed.settings.json.fields.download_url" label="Download URL" required={true} />
+          </div>
+        )}
+      </Fragment>
+    );
+  }
+};
+
+const PushFeedSettingFields = (ind: IndexerDefinition, indexer: string) => {
+  if (indexer !== "") {
+    return (
+      <Fragment>
+        {ind && ind.push && ind.push.settings && (
+          <div className="">
+            <div className="px-4 space-y-1">
+              <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Push</Dialog.Title>
+              <p className="text-sm text-gray-500 dark:text-gray-200">
+                Push feed streaming new uploads over server-sent events or a websocket. Each message is json, set the JSONPath of the releases in a message, eg. $, and of their fields, eg. $.name. More fields can be set in the feed settings.
+              </p>
+            </div>
+
+            <TextFieldWide name="name" label="Name" defaultValue="" />
+
+            {ind.push.settings.map((f: IndexerSetting, idx: number) => {
+              switch (f.type) {
+                case "text":
+                  return <TextFieldWide name={`feed.${f.name}`} label={f.label} required={f.required} key={idx} help={f.help} autoComplete="off" validate={validateField(f)} />;
+                case "secret":
+                  return <PasswordFieldWide name={`feed.${f.name}`} label={f.label} required={f.required} key={idx} help={f.help} defaultValue={f.default} validate={validateField(f)} />;
+              }
+              return null;
+            })}
+
+            <SelectFieldBasic name="feed.settings.push.protocol" label="Protocol" options={FeedPushProtocolOptions} />
+            <PasswordFieldWide name="feed.settings.json.headers.Authorization" label="Authorization" help="Authorization header, eg. Bearer token. Optional" />
+            <TextFieldWide name="feed.settings.json.items_path" label="Items" required={true} />
+            <TextFieldWide name="feed.settings.json.fields.title" label="Title" required={true} />
+            <TextFieldWide name="feed.settings.json.fields.download_url" label="Download URL" required={true} />
+          </div>
+        )}
+      </Fragment>
+    );
+  }
+};
+
 const SettingFields = (ind: IndexerDefinition, indexer: string) => {
   if (indexer !== "") {
     return (
@@ -389,6 +462,53 @@ export function IndexerAddForm({ isOpen, toggle }: AddProps) {
       });
       return;
 
+    } else if (formData.implementation === "json") {
+      const createFeed: FeedCreate = {
+        name: formData.name,
+        enabled: false,
+        type: "JSON",
+        url: formData.feed.url,
+        interval: 30,
+        timeout: 60,
+        indexer_id: 0,
+        settings: formData.feed.settings
+      };
+
+      mutation.mutate(formData as Indexer, {
+        onSuccess: (indexer) => {
+          // @eslint-ignore
+          createFeed.indexer_id = indexer.id;
+
+          feedMutation.mutate(createFeed);
+        }
+      });
+      return;
+
+    } else if (formData.implementation === "push") {
+      const createFeed: FeedCreate = {
+        name: formData.name,
+        enabled: false,
+        type: "PUSH",
+        url: formData.feed.url,
+        interval: 0,
+        timeout: 60,
+        indexer_id: 0,
+        settings: {
+          ...formData.feed.settings,
+          push: { protocol: "SSE", ...formData.feed.settings?.push }
+        }
+      };
+
+      mutation.mutate(formData as Indexer, {
+        onSuccess: (indexer) => {
+          // @eslint-ignore
+          createFeed.indexer_id = indexer.id;
+
+          feedMutation.mutate(createFeed);
+        }
+      });
+      return;
+
     } else if (formData.implementation === "irc") {
       const channels: IrcChannel[] = [];
       if (ind.irc?.channels.length) {
@@ -412,6 +532,7 @@ export function IndexerAddForm({ isOpen, toggle }: AddProps) {
         server: ind.irc.server,
         port: ind.irc.port,
         tls: ind.irc.tls,
+        tls_skip_verify: true,
         nick: formData.irc.nick,
         auth: {
           mechanism: "NONE"
@@ -578,6 +699,8 @@ export function IndexerAddForm({ isOpen, toggle }: AddProps) {
                         {TorznabFeedSettingFields(indexer, values.identifier)}
                         {NewznabFeedSettingFields(indexer, values.identifier)}
                         {RSSFeedSettingFields(indexer, values.identifier)}
+                        {JSONFeedSettingFields(indexer, values.identifier)}
+                        {PushFeedSettingFields(indexer, values.identifier)}
                       </div>
 
                       <div
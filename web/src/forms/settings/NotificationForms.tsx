@@ -206,12 +206,39 @@ function FormFieldsGotify() {
   );
 }
 
+function FormFieldsWebhook() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          The event is posted as JSON with the subject, message and details like the network, channel and reason of irc events.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="URL the events are posted to"
+        placeholder="https://example.com/hooks/autobrr"
+        required={true}
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Bearer token"
+        help="Optional, sent in the Authorization header"
+      />
+    </div>
+  );
+}
+
 const componentMap: componentMapType = {
   DISCORD: <FormFieldsDiscord />,
   NOTIFIARR: <FormFieldsNotifiarr />,
   TELEGRAM: <FormFieldsTelegram />,
   PUSHOVER: <FormFieldsPushover />,
-  GOTIFY: <FormFieldsGotify />
+  GOTIFY: <FormFieldsGotify />,
+  WEBHOOK: <FormFieldsWebhook />
 };
 
 interface NotificationAddFormValues {
@@ -72,6 +72,8 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
     webhook_method: "",
     webhook_data: "",
     webhook_headers: [],
+    priority: 0,
+    dupe_key: "",
     external_download_client_id: 0,
     client_id: 0
   };
@@ -135,12 +137,14 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
       action.type === "RTORRENT" ||
       action.type === "TRANSMISSION" ||
       action.type === "PORLA" ||
+      action.type === "ARIA2" ||
       action.type === "RADARR" ||
       action.type === "SONARR" ||
       action.type === "LIDARR" ||
       action.type === "WHISPARR" ||
       action.type === "READARR" ||
-      action.type === "SABNZBD"
+      action.type === "SABNZBD" ||
+      action.type === "NZBGET"
     )) {
       setFieldValue(fieldName, 0); // Reset the client_id field value
     }
@@ -560,6 +564,66 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
         </CollapsableSection>
       </div>
     );
+  case "ARIA2":
+    return (
+      <div className="w-full">
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+
+          <div className="col-span-6 sm:col-span-6">
+            <TextField
+              name={`actions.${idx}.save_path`}
+              label="Save path"
+              columns={6}
+              placeholder="eg. /full/path/to/torrent/data"
+              tooltip={<div>The download directory on the aria2 host, left empty aria2 uses its own. Supports macros.</div>} />
+          </div>
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <SwitchGroup
+              name={`actions.${idx}.paused`}
+              label="Add paused"
+            />
+          </div>
+        </div>
+
+        <CollapsableSection title="Rules" subtitle="client options">
+          <div className="col-span-12">
+            <div className="mt-6 grid grid-cols-12 gap-6">
+              <NumberField
+                name={`actions.${idx}.limit_download_speed`}
+                label="Limit download speed (KiB/s)"
+              />
+              <NumberField
+                name={`actions.${idx}.limit_upload_speed`}
+                label="Limit upload speed (KiB/s)"
+              />
+            </div>
+
+            <div className="mt-6 grid grid-cols-12 gap-6">
+              <NumberField
+                name={`actions.${idx}.limit_ratio`}
+                label="Ratio limit"
+                placeholder="Takes any number (0 is no limit)"
+                step={0.25}
+                isDecimal
+              />
+              <NumberField
+                name={`actions.${idx}.limit_seed_time`}
+                label="Seed time limit (minutes)"
+                placeholder="Takes any number (0 is no limit)"
+              />
+            </div>
+          </div>
+        </CollapsableSection>
+      </div>
+    );
   case "RADARR":
   case "SONARR":
   case "LIDARR":
@@ -598,6 +662,52 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
         </div>
       </div>
     );
+  case "NZBGET":
+    return (
+      <div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+
+          <TextField
+            name={`actions.${idx}.category`}
+            label="Category"
+            columns={6}
+            placeholder="eg. category"
+            tooltip={<p>Category must exist already. Supports macros.</p>} />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <NumberField
+            name={`actions.${idx}.priority`}
+            label="Priority"
+            placeholder="0"
+            min={-100}
+            max={900}
+            tooltip={<p>Queue priority: -100 very low, -50 low, 0 normal, 50 high, 100 very high or 900 force.</p>}
+          />
+
+          <TextField
+            name={`actions.${idx}.dupe_key`}
+            label="Dupe key"
+            columns={6}
+            placeholder="eg. {{ .Title }}-S{{ .Season }}E{{ .Episode }}"
+            tooltip={<p>NZBGet treats nzbs with the same dupe key as duplicates and keeps the one with the highest score. Supports macros.</p>} />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <SwitchGroup
+              name={`actions.${idx}.paused`}
+              label="Add paused"
+            />
+          </div>
+        </div>
+      </div>
+    );
 
   default:
     return null;
@@ -7,7 +7,7 @@ import { ReactNode, Suspense, useEffect, useRef } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { NavLink, Route, Routes, useLocation, useNavigate, useParams } from "react-router-dom";
 import { toast } from "react-hot-toast";
-import { Form, Formik, FormikValues, useFormikContext } from "formik";
+import { Field, FieldArray, FieldArrayRenderProps, FieldProps, Form, Formik, FormikValues, useFormikContext } from "formik";
 import { z } from "zod";
 import { toFormikValidationSchema } from "zod-formik-adapter";
 import { ChevronDownIcon, ChevronRightIcon } from "@heroicons/react/24/solid";
@@ -18,14 +18,23 @@ import {
   downloadsPerUnitOptions,
   FORMATS_OPTIONS,
   HDR_OPTIONS,
+  hdr_formats,
+  language_preference_options,
+  subtitle_preference_options,
   LANGUAGE_OPTIONS,
   ORIGIN_OPTIONS,
   OTHER_OPTIONS,
   QUALITY_MUSIC_OPTIONS,
+  BIT_DEPTH_MUSIC_OPTIONS,
+  SAMPLE_RATE_MUSIC_OPTIONS,
+  AUDIO_CHANNELS_OPTIONS,
   RELEASE_TYPE_MUSIC_OPTIONS,
   RESOLUTION_OPTIONS,
   SOURCES_MUSIC_OPTIONS,
   SOURCES_OPTIONS,
+  FilterChainModeOptions,
+  FilterAnimeBatchOptions,
+  AnimeSubTypeOptions,
   tagsMatchLogicOptions
 } from "@app/domain/constants";
 import { APIClient } from "@api/APIClient";
@@ -165,12 +174,12 @@ const FormErrorNotification = () => {
   return null;
 };
 
-const allowedClientType = ["QBITTORRENT", "DELUGE_V1", "DELUGE_V2", "RTORRENT", "TRANSMISSION", "PORLA", "RADARR", "SONARR", "LIDARR", "WHISPARR", "READARR", "SABNZBD"];
+const allowedClientType = ["QBITTORRENT", "DELUGE_V1", "DELUGE_V2", "RTORRENT", "TRANSMISSION", "PORLA", "ARIA2", "RADARR", "SONARR", "LIDARR", "WHISPARR", "READARR", "SABNZBD", "NZBGET"];
 
 const actionSchema = z.object({
   enabled: z.boolean(),
   name: z.string(),
-  type: z.enum(["QBITTORRENT", "DELUGE_V1", "DELUGE_V2", "RTORRENT", "TRANSMISSION", "PORLA", "RADARR", "SONARR", "LIDARR", "WHISPARR", "READARR", "SABNZBD", "TEST", "EXEC", "WATCH_FOLDER", "WEBHOOK"]),
+  type: z.enum(["QBITTORRENT", "DELUGE_V1", "DELUGE_V2", "RTORRENT", "TRANSMISSION", "PORLA", "ARIA2", "RADARR", "SONARR", "LIDARR", "WHISPARR", "READARR", "SABNZBD", "NZBGET", "TEST", "EXEC", "WATCH_FOLDER", "WEBHOOK"]),
   client_id: z.number().optional(),
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
@@ -192,7 +201,9 @@ const actionSchema = z.object({
   webhook_host: z.string().optional(),
   webhook_type: z.string().optional(),
   webhook_method: z.string().optional(),
-  webhook_data: z.string().optional()
+  webhook_data: z.string().optional(),
+  priority: z.number().optional(),
+  dupe_key: z.string().optional()
 }).superRefine((value, ctx) => {
   if (allowedClientType.includes(value.type)) {
     if (value.client_id === 0) {
@@ -213,11 +224,19 @@ const externalFilterSchema = z.object({
   exec_cmd: z.string().optional(),
   exec_args: z.string().optional(),
   exec_expect_status: z.number().optional(),
+  exec_mode: z.enum(["EXIT_CODE", "JSON"]).optional(),
+  exec_timeout: z.number().min(0).max(3600).optional(),
+  exec_env: z.string().optional(),
   webhook_host: z.string().optional(),
   webhook_type: z.string().optional(),
   webhook_method: z.string().optional(),
   webhook_data: z.string().optional(),
   webhook_expect_status: z.number().optional(),
+  webhook_retry_status: z.string().optional(),
+  webhook_retry_attempts: z.number().min(0).max(10).optional(),
+  webhook_retry_delay_seconds: z.number().min(0).max(60).optional(),
+  webhook_expect_json_path: z.string().optional(),
+  webhook_expect_json_value: z.string().optional(),
 });
 
 const indexerSchema = z.object({
@@ -340,10 +359,19 @@ export function FilterDetails() {
                 enabled: filter.enabled,
                 min_size: filter.min_size,
                 max_size: filter.max_size,
+                verify_torrent: filter.verify_torrent,
+                min_file_count: filter.min_file_count,
+                max_file_count: filter.max_file_count,
+                match_file_extensions: filter.match_file_extensions,
+                except_file_extensions: filter.except_file_extensions,
                 delay: filter.delay,
+                delay_jitter: filter.delay_jitter,
                 priority: filter.priority,
                 max_downloads: filter.max_downloads,
                 max_downloads_unit: filter.max_downloads_unit,
+                max_downloads_interval: filter.max_downloads_interval,
+                max_downloads_per_indexer: filter.max_downloads_per_indexer,
+                schedule: filter.schedule,
                 use_regex: filter.use_regex || false,
                 shows: filter.shows,
                 years: filter.years,
@@ -353,11 +381,25 @@ export function FilterDetails() {
                 containers: filter.containers || [],
                 match_hdr: filter.match_hdr || [],
                 except_hdr: filter.except_hdr || [],
+                hdr_preference: filter.hdr_preference || [],
+                reject_dv_without_fallback: filter.reject_dv_without_fallback,
                 match_other: filter.match_other || [],
                 except_other: filter.except_other || [],
                 seasons: filter.seasons,
                 episodes: filter.episodes,
+                absolute_episodes: filter.absolute_episodes,
+                air_dates: filter.air_dates,
+                max_air_date_age: filter.max_air_date_age,
+                anime_batch: filter.anime_batch,
+                anime_sub_type: filter.anime_sub_type,
+                anime_group_tiers: filter.anime_group_tiers,
+                anime_max_group_tier: filter.anime_max_group_tier,
                 smart_episode: filter.smart_episode,
+                prefer_season_packs: filter.prefer_season_packs,
+                season_pack_hold: filter.season_pack_hold,
+                proper_upgrades: filter.proper_upgrades,
+                proper_upgrades_replace: filter.proper_upgrades_replace,
+                arr_upgrade_only: filter.arr_upgrade_only,
                 match_releases: filter.match_releases,
                 except_releases: filter.except_releases,
                 match_release_groups: filter.match_release_groups,
@@ -368,6 +410,16 @@ export function FilterDetails() {
                 match_description: filter.match_description,
                 except_description: filter.except_description,
                 use_regex_description: filter.use_regex_description,
+                use_regex_shows: filter.use_regex_shows,
+                use_regex_release_groups: filter.use_regex_release_groups,
+                use_regex_tags: filter.use_regex_tags,
+                use_regex_uploaders: filter.use_regex_uploaders,
+                expression: filter.expression,
+                preset_id: filter.preset_id,
+                preset_source: filter.preset_source,
+                preset_managed: filter.preset_managed,
+                preset_hash: filter.preset_hash,
+                filter_tags: filter.filter_tags || [],
                 match_categories: filter.match_categories,
                 except_categories: filter.except_categories,
                 tags: filter.tags,
@@ -376,13 +428,28 @@ export function FilterDetails() {
                 except_tags_match_logic: filter.except_tags_match_logic,
                 match_uploaders: filter.match_uploaders,
                 except_uploaders: filter.except_uploaders,
+                flags: filter.flags,
+                except_flags: filter.except_flags,
                 match_language: filter.match_language || [],
                 except_language: filter.except_language || [],
+                language_preference: filter.language_preference || [],
+                subtitle_preference: filter.subtitle_preference || [],
+                except_language_only: filter.except_language_only || [],
                 freeleech: filter.freeleech,
                 freeleech_percent: filter.freeleech_percent,
+                freeleech_tokens: filter.freeleech_tokens,
+                min_seeders: filter.min_seeders,
+                min_leechers: filter.min_leechers,
+                on_reject: filter.on_reject,
+                on_reject_filter_id: filter.on_reject_filter_id,
+                on_action_failure: filter.on_action_failure,
+                on_action_failure_filter_id: filter.on_action_failure_filter_id,
                 formats: filter.formats || [],
                 quality: filter.quality || [],
                 media: filter.media || [],
+                bit_depths: filter.bit_depths || [],
+                sample_rates: filter.sample_rates || [],
+                audio_channels: filter.audio_channels || [],
                 match_release_types: filter.match_release_types || [],
                 log_score: filter.log_score,
                 log: filter.log,
@@ -395,6 +462,8 @@ export function FilterDetails() {
                 indexers: filter.indexers || [],
                 actions: filter.actions || [],
                 external: filter.external || [],
+                list_sources: filter.list_sources || [],
+                rewrite_rules: filter.rewrite_rules || [],
               } as Filter}
               onSubmit={handleSubmit}
               enableReinitialize={true}
@@ -432,17 +501,30 @@ export function FilterDetails() {
 }
 
 export function General() {
+  const { values } = useFormikContext<FormikValues>();
+
   const { isLoading, data: indexers } = useQuery({
     queryKey: ["filters", "indexer_list"],
     queryFn: APIClient.indexers.getOptions,
     refetchOnWindowFocus: false
   });
 
+  const { data: filterTags } = useQuery({
+    queryKey: ["filters", "tags"],
+    queryFn: APIClient.filters.getTags,
+    refetchOnWindowFocus: false
+  });
+
   const opts = indexers && indexers.length > 0 ? indexers.map(v => ({
     label: v.name,
     value: v.id
   })) : [];
 
+  const tagOpts = filterTags ? filterTags.map(tag => ({
+    label: tag,
+    value: tag
+  })) : [];
+
   return (
     <div>
       <div className="mt-6 lg:pb-8">
@@ -452,6 +534,19 @@ export function General() {
           <div className="col-span-6">
             {!isLoading && <IndexerMultiSelect name="indexers" options={opts} label="Indexers" columns={6} />}
           </div>
+
+          <MultiSelect
+            name="filter_tags"
+            label="Tags"
+            options={tagOpts}
+            columns={6}
+            creatable={true}
+            tooltip={
+              <div>
+                <p>Free-form tags to group filters, eg. sports. Filters with a tag can be enabled, disabled, deleted and exported together.</p>
+              </div>
+            }
+          />
         </div>
       </div>
 
@@ -489,7 +584,19 @@ export function General() {
             placeholder="Number of seconds to delay actions"
             tooltip={
               <div>
-                <p>Number of seconds to wait before running actions.</p>
+                <p>Number of seconds to wait before running actions. Delayed actions are queued and still run after a restart.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <NumberField
+            name="delay_jitter"
+            label="Delay jitter"
+            placeholder="Max random seconds added to the delay"
+            min={0}
+            tooltip={
+              <div>
+                <p>Adds a random delay of up to this number of seconds to stagger grabs. Leave empty for racing filters to run actions instantly.</p>
                 <DocsLink href="https://autobrr.com/filters#rules" />
               </div>
             }
@@ -523,7 +630,31 @@ export function General() {
             optionDefaultText="Select unit"
             tooltip={
               <div>
-                <p>The unit of time for counting the maximum downloads per filter.</p>
+                <p>The unit of time for counting the maximum downloads per filter. Downloads are counted in a sliding window ending now, eg. the last 24 hours for DAY.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <NumberField
+            name="max_downloads_interval"
+            label="Max downloads window"
+            placeholder="Number of units in the window (default 1)"
+            min={1}
+            tooltip={
+              <div>
+                <p>Length of the window in units, eg. 6 with HOUR allows max downloads per 6 hours.</p>
+                <DocsLink href="https://autobrr.com/filters#rules" />
+              </div>
+            }
+          />
+          <TextField
+            name="schedule"
+            label="Schedule"
+            columns={12}
+            placeholder="eg. mon-fri 22:00-06:00; sat,sun"
+            tooltip={
+              <div>
+                <p>Only check releases during these windows, in server local time. Windows are separated by <code>;</code> and take days and/or a time range. Leave empty to always be active.</p>
                 <DocsLink href="https://autobrr.com/filters#rules" />
               </div>
             }
@@ -531,6 +662,61 @@ export function General() {
         </div>
       </div>
 
+      <div className="border-t dark:border-gray-700">
+        <SwitchGroup
+          name="max_downloads_per_indexer"
+          label="Max downloads per indexer"
+          description="Count max downloads separately for each indexer instead of for the whole filter."
+        />
+      </div>
+
+      <div className="border-t dark:border-gray-700">
+        <SwitchGroup
+          name="verify_torrent"
+          label="Verify torrent"
+          description="Download the torrent file after a match and check its real size, file count and file extensions before running actions. Announced sizes are often missing or wrong."
+        />
+      </div>
+
+      {values.verify_torrent && (
+        <div className="mt-2 mb-6 grid grid-cols-12 gap-6">
+          <NumberField
+            name="min_file_count"
+            label="Min file count"
+            placeholder="Takes any number (0 is no limit)"
+            min={0}
+          />
+          <NumberField
+            name="max_file_count"
+            label="Max file count"
+            placeholder="Takes any number (0 is no limit)"
+            min={0}
+          />
+          <TextField
+            name="match_file_extensions"
+            label="Match file extensions"
+            columns={6}
+            placeholder="eg. mkv,mp4"
+            tooltip={
+              <div>
+                <p>Comma separated list of extensions. At least one file in the torrent must have one of them.</p>
+              </div>
+            }
+          />
+          <TextField
+            name="except_file_extensions"
+            label="Except file extensions"
+            columns={6}
+            placeholder="eg. exe,lnk,zipx"
+            tooltip={
+              <div>
+                <p>Comma separated list of extensions. The torrent is rejected if any file has one of them.</p>
+              </div>
+            }
+          />
+        </div>
+      )}
+
       <div className="border-t dark:border-gray-700">
         <SwitchGroup name="enabled" label="Enabled" description="Enable or disable this filter." />
       </div>
@@ -539,17 +725,21 @@ export function General() {
 }
 
 export function MoviesTv() {
+  const { values } = useFormikContext<FormikValues>();
+
   return (
     <div>
       <div className="mt-6 grid grid-cols-12 gap-6">
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="shows"
           label="Movies / Shows"
+          useRegex={values.use_regex_shows}
           columns={8}
           placeholder="eg. Movie,Show 1,Show?2"
           tooltip={
             <div>
               <p>You can use basic filtering like wildcards <code>*</code> or replace single characters with <code>?</code></p>
+              <p>Tick <b>Use Regex</b> to match the parsed title with regex instead.</p>
               <DocsLink href="https://autobrr.com/filters#tvmovies" />
             </div>
           }
@@ -558,14 +748,17 @@ export function MoviesTv() {
           name="years"
           label="Years"
           columns={4}
-          placeholder="eg. 2018,2019-2021"
+          placeholder="eg. 2018,2019-2021,>=2023,1990s"
           tooltip={
             <div>
-              <p>This field takes a range of years and/or comma separated single years.</p>
+              <p>This field takes comma separated single years, ranges like 1990-1999, comparisons like &gt;=2020 or &lt;1980 and decades like 1990s.</p>
               <DocsLink href="https://autobrr.com/filters#tvmovies" />
             </div>
           }
         />
+        <div className="col-span-6">
+          <SwitchGroup name="use_regex_shows" label="Use Regex" />
+        </div>
       </div>
       <div className="mt-6 lg:pb-8">
         <TitleSubtitle
@@ -600,6 +793,32 @@ export function MoviesTv() {
           />
         </div>
 
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name="air_dates"
+            label="Air dates"
+            columns={8}
+            placeholder="eg. 2023-10-12,2023-11,2023-10-01/2023-10-15,>=2023-10-01"
+            tooltip={
+              <div>
+                <p>Match daily shows like Show.2023.10.12 by air date. Takes dates, months, ranges and comparisons.</p>
+                <p>Releases without an air date are rejected.</p>
+              </div>
+            }
+          />
+          <NumberField
+            name="max_air_date_age"
+            label="Max air date age"
+            placeholder="Days (0 is no limit)"
+            min={0}
+            tooltip={
+              <div>
+                <p>Only match daily shows that aired within this many days of the announce. Releases without an air date are rejected.</p>
+              </div>
+            }
+          />
+        </div>
+
         <div className="mt-6">
           <CheckboxField
             name="smart_episode"
@@ -608,6 +827,127 @@ export function MoviesTv() {
           />{" "}
           {/*Do not match older or already existing episodes.*/}
         </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-12 sm:col-span-6">
+            <CheckboxField
+              name="prefer_season_packs"
+              label="Prefer Season Packs"
+              sublabel="Do not match episodes of a season when its season pack was already grabbed."
+            />
+          </div>
+          {values.prefer_season_packs && (
+            <NumberField
+              name="season_pack_hold"
+              label="Season pack hold (hours)"
+              placeholder="eg. 12"
+              min={0}
+              max={168}
+              tooltip={
+                <div>
+                  <p>Hold the actions of matched episodes for this many hours. If a season pack of the same season is grabbed in the meantime, the held episodes are cancelled. 0 disables the hold.</p>
+                </div>
+              }
+            />
+          )}
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-12 sm:col-span-6">
+            <CheckboxField
+              name="proper_upgrades"
+              label="PROPER/REPACK Upgrades"
+              sublabel="Grab a PROPER or REPACK of a release this filter grabbed before, even when max downloads or smart episode would reject it."
+            />
+          </div>
+          {values.proper_upgrades && (
+            <div className="col-span-12 sm:col-span-6">
+              <CheckboxField
+                name="proper_upgrades_replace"
+                label="Replace in client"
+                sublabel="Remove the replaced torrent from qBittorrent. The downloaded data is kept."
+              />
+            </div>
+          )}
+        </div>
+
+        <div className="mt-6">
+          <CheckboxField
+            name="arr_upgrade_only"
+            label="Sonarr/Radarr upgrade mode"
+            sublabel="Only push to Sonarr and Radarr actions when the release is wanted by the quality profile of the series or movie: missing, or an upgrade of the existing file below the cutoff."
+          />
+        </div>
+      </div>
+
+      <div className="mt-6 lg:pb-8">
+        <TitleSubtitle
+          title="Anime"
+          subtitle="Only set for releases from anime trackers, which are parsed for absolute episodes, batches, groups and subs."
+        />
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name="absolute_episodes"
+            label="Absolute episodes"
+            columns={4}
+            placeholder="eg. 1-24,1000-1100"
+            tooltip={
+              <div>
+                <p>Match absolute episode numbers, eg. <code>One Piece - 1085</code>. Season numbered releases have no absolute episode.</p>
+              </div>
+            }
+          />
+          <Select
+            name="anime_batch"
+            label="Batches"
+            columns={4}
+            options={FilterAnimeBatchOptions}
+            optionDefaultText="Batches and single episodes"
+            tooltip={
+              <div>
+                <p>Batches are releases with a range of episodes, a whole season or tagged as batch or complete.</p>
+              </div>
+            }
+          />
+          <Select
+            name="anime_sub_type"
+            label="Subs"
+            columns={4}
+            options={AnimeSubTypeOptions}
+            optionDefaultText="Any subs"
+            tooltip={
+              <div>
+                <p>Official subs are rips from streaming services like Crunchyroll. Releases of other groups in brackets are fansubs.</p>
+              </div>
+            }
+          />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextAreaAutoResize
+            name="anime_group_tiers"
+            label="Group tiers"
+            columns={8}
+            placeholder={"eg. Group1, Group2\nGroup3"}
+            tooltip={
+              <div>
+                <p>One tier per line with the best groups first, groups separated by comma. Releases of groups not in any tier don't match.</p>
+              </div>
+            }
+          />
+          <NumberField
+            name="anime_max_group_tier"
+            label="Lowest tier"
+            placeholder="0 matches all tiers"
+            min={0}
+            tooltip={
+              <div>
+                <p>Only match groups in this tier or better, eg. 2 matches the first two lines.</p>
+              </div>
+            }
+          />
+        </div>
       </div>
 
       <div className="mt-6 lg:pb-8">
@@ -703,6 +1043,22 @@ export function MoviesTv() {
           />
         </div>
 
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <PreferenceList
+            name="hdr_preference"
+            label="HDR preference"
+            description="Only HDR formats in the list match, ordered from most to least preferred. Smart episode upgrades use the same order."
+            options={hdr_formats}
+          />
+          <div className="col-span-12 sm:col-span-6">
+            <SwitchGroup
+              name="reject_dv_without_fallback"
+              label="Reject DV without fallback"
+              description="Reject Dolby Vision releases without an HDR fallback layer, for displays without Dolby Vision support."
+            />
+          </div>
+        </div>
+
         <div className="mt-6 grid grid-cols-12 gap-6">
           <MultiSelect
             name="match_other"
@@ -768,10 +1124,10 @@ export function Music({ values }: AdvancedProps) {
           name="years"
           label="Years"
           columns={4}
-          placeholder="eg. 2018,2019-2021"
+          placeholder="eg. 2018,2019-2021,>=2023,1990s"
           tooltip={
             <div>
-              <p>This field takes a range of years and/or comma separated single years.</p>
+              <p>This field takes comma separated single years, ranges like 1990-1999, comparisons like &gt;=2020 or &lt;1980 and decades like 1990s.</p>
               <DocsLink href="https://autobrr.com/filters#music" />
             </div>
           }
@@ -838,6 +1194,49 @@ export function Music({ values }: AdvancedProps) {
           />
         </div>
 
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <MultiSelect
+            name="bit_depths"
+            options={BIT_DEPTH_MUSIC_OPTIONS}
+            label="Bit depth"
+            columns={4}
+            disabled={values.perfect_flac}
+            tooltip={
+              <div>
+                <p>Will only match releases with any of the selected bit depths, parsed from the release name or announce tags.</p>
+                <DocsLink href="https://autobrr.com/filters#quality-1" />
+              </div>
+            }
+          />
+          <MultiSelect
+            name="sample_rates"
+            options={SAMPLE_RATE_MUSIC_OPTIONS}
+            label="Sample rate"
+            columns={4}
+            creatable={true}
+            disabled={values.perfect_flac}
+            tooltip={
+              <div>
+                <p>Will only match releases with any of the selected sample rates in kHz. Releases without a sample rate in the announce don't match.</p>
+                <DocsLink href="https://autobrr.com/filters#quality-1" />
+              </div>
+            }
+          />
+          <MultiSelect
+            name="audio_channels"
+            options={AUDIO_CHANNELS_OPTIONS}
+            label="Channels"
+            columns={4}
+            creatable={true}
+            tooltip={
+              <div>
+                <p>Will only match releases with any of the selected channel layouts. Mono and Stereo are matched as 1.0 and 2.0.</p>
+                <DocsLink href="https://autobrr.com/filters#quality-1" />
+              </div>
+            }
+          />
+        </div>
+
         <div className="mt-6 grid grid-cols-12 gap-6">
           <NumberField
             name="log_score"
@@ -964,14 +1363,23 @@ export function Advanced({ values }: AdvancedProps) {
         </div>
       </CollapsableSection>
 
+      <CollapsableSection
+        defaultOpen={true}
+        title="Rewrite release name"
+        subtitle="Regex rules applied in order to the release name passed to actions, eg. to strip tracker tags. The release history keeps the original name."
+      >
+        <RewriteRules name="rewrite_rules" />
+      </CollapsableSection>
+
       <CollapsableSection
         defaultOpen={true}
         title="Groups"
         subtitle="Match only certain groups and/or ignore other groups."
       >
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="match_release_groups"
           label="Match release groups"
+          useRegex={values.use_regex_release_groups}
           columns={6}
           placeholder="eg. group1,group2"
           tooltip={
@@ -981,15 +1389,20 @@ export function Advanced({ values }: AdvancedProps) {
             </div>
           }
         />
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="except_release_groups"
           label="Except release groups"
+          useRegex={values.use_regex_release_groups}
           columns={6}
           placeholder="eg. badgroup1,badgroup2"
           tooltip={
             <div>
               <p>Comma separated list of release groups to ignore (takes priority over Match releases).</p>
               <DocsLink href="https://autobrr.com/filters#advanced" />
+
+        <div className="col-span-6">
+          <SwitchGroup name="use_regex_release_groups" label="Use Regex" />
+        </div>
             </div>
           }
         />
@@ -1025,9 +1438,10 @@ export function Advanced({ values }: AdvancedProps) {
           }
         />
 
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="tags"
           label="Match tags"
+          useRegex={values.use_regex_tags}
           columns={4}
           placeholder="eg. tag1,tag2"
           tooltip={
@@ -1050,9 +1464,10 @@ export function Advanced({ values }: AdvancedProps) {
             </div>
           }
         />
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="except_tags"
           label="Except tags"
+          useRegex={values.use_regex_tags}
           columns={4}
           placeholder="eg. tag1,tag2"
           tooltip={
@@ -1072,6 +1487,10 @@ export function Advanced({ values }: AdvancedProps) {
             <div>
               <p>Logic used to match except tags.</p>
               <DocsLink href="https://autobrr.com/filters#advanced" />
+
+        <div className="col-span-6">
+          <SwitchGroup name="use_regex_tags" label="Use Regex" />
+        </div>
             </div>
           }
         />
@@ -1082,9 +1501,10 @@ export function Advanced({ values }: AdvancedProps) {
         title="Uploaders"
         subtitle="Match or ignore uploaders."
       >
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="match_uploaders"
           label="Match uploaders"
+          useRegex={values.use_regex_uploaders}
           columns={6}
           placeholder="eg. uploader1,uploader2"
           tooltip={
@@ -1094,9 +1514,10 @@ export function Advanced({ values }: AdvancedProps) {
             </div>
           }
         />
-        <TextAreaAutoResize
+        <RegexTextAreaField
           name="except_uploaders"
           label="Except uploaders"
+          useRegex={values.use_regex_uploaders}
           columns={6}
           placeholder="eg. anonymous1,anonymous2"
           tooltip={
@@ -1104,6 +1525,39 @@ export function Advanced({ values }: AdvancedProps) {
               <p>Comma separated list of uploaders to ignore (takes priority over Match releases).
               </p>
               <DocsLink href="https://autobrr.com/filters#advanced" />
+
+        <div className="col-span-6">
+          <SwitchGroup name="use_regex_uploaders" label="Use Regex" />
+        </div>
+            </div>
+          }
+        />
+      </CollapsableSection>
+
+      <CollapsableSection
+        defaultOpen={true}
+        title="Flags"
+        subtitle="Require or reject flags set by irc keyword triggers."
+      >
+        <TextField
+          name="flags"
+          label="Require flags"
+          columns={6}
+          placeholder="eg. freeleech-weekend"
+          tooltip={
+            <div>
+              <p>Comma separated list of flags. The filter only matches while all of them are set.</p>
+            </div>
+          }
+        />
+        <TextField
+          name="except_flags"
+          label="Except flags"
+          columns={6}
+          placeholder="eg. site-maintenance"
+          tooltip={
+            <div>
+              <p>Comma separated list of flags. The filter does not match while any of them is set.</p>
             </div>
           }
         />
@@ -1128,6 +1582,33 @@ export function Advanced({ values }: AdvancedProps) {
           columns={6}
           creatable={true}
         />
+
+        <div className="col-span-12 mt-6 grid grid-cols-12 gap-6">
+          <PreferenceList
+            name="language_preference"
+            label="Language preference"
+            description="Only audio languages in the list match, ordered from most to least preferred. Releases without a language tag are ENGLiSH. Smart episode upgrades use the same order."
+            options={language_preference_options}
+          />
+          <PreferenceList
+            name="subtitle_preference"
+            label="Subtitle preference"
+            description="Only subtitles in the list match, ordered from most to least preferred. Use NONE for releases without a subtitle tag."
+            options={subtitle_preference_options}
+          />
+          <MultiSelect
+            name="except_language_only"
+            options={LANGUAGE_OPTIONS}
+            label="Reject if only"
+            columns={6}
+            creatable={true}
+            tooltip={
+              <div>
+                <p>Rejects releases whose audio languages are all in this list, eg. FRENCH rejects French-only dubs but keeps MULTi FRENCH releases.</p>
+              </div>
+            }
+          />
+        </div>
       </CollapsableSection>
 
       <CollapsableSection
@@ -1263,6 +1744,10 @@ export function Advanced({ values }: AdvancedProps) {
                 depending on the indexers you use.
               </p>
               <br />
+              <p>
+                Use exact values, ranges like <code>75-100</code> or comparisons like <code>&gt;=50</code>.
+              </p>
+              <br />
               <p>
                 See who uses what in the documentation:{" "}
                 <DocsLink href="https://autobrr.com/filters/freeleech" />
@@ -1270,13 +1755,266 @@ export function Advanced({ values }: AdvancedProps) {
             </div>
           }
           columns={6}
-          placeholder="eg. 50,75-100"
+          placeholder="eg. 50,75-100,>=50"
         />
+
+        <div className="col-span-6">
+          <SwitchGroup
+            name="freeleech_tokens"
+            label="Freeleech tokens available"
+            description="Only match when you have freeleech tokens left on the indexer. Fully freeleech releases are always matched."
+            tooltip={
+              <div>
+                <p>
+                  The tokens are checked via the indexer API. Releases from indexers
+                  without API support for tokens are rejected.
+                </p>
+              </div>
+            }
+          />
+        </div>
+
+        <div className="col-span-12 grid grid-cols-12 gap-6">
+          <NumberField
+            name="min_seeders"
+            label="Min seeders"
+            placeholder="Takes any number (0 is no limit)"
+            min={0}
+            tooltip={
+              <div>
+                <p>
+                  Seeders and leechers come from torznab feeds or are looked up via the
+                  indexer API. Releases from indexers without API support are rejected.
+                </p>
+              </div>
+            }
+          />
+          <NumberField
+            name="min_leechers"
+            label="Min leechers"
+            placeholder="Takes any number (0 is no limit)"
+            min={0}
+          />
+        </div>
+      </CollapsableSection>
+
+      <CollapsableSection
+        defaultOpen={false}
+        title="Expression"
+        subtitle="Match releases with a single expression, checked in addition to the fields above."
+      >
+        <TextAreaAutoResize
+          name="expression"
+          label="Expression"
+          columns={12}
+          placeholder={'eg. Resolution == "2160p" && Size < 40GB && (Group in ["A", "B"] || Source == "WEB-DL")'}
+          tooltip={
+            <div>
+              <p>Combine release fields like Resolution, Source, Codec, HDR, Group, Size, Season, Year, Tags and Uploader with <code>&&</code>, <code>||</code> and <code>!</code>.</p>
+              <br />
+              <p>Operators: <code>== != &lt; &lt;= &gt; &gt;= in contains startsWith endsWith matches</code>. Sizes like <code>40GB</code> are converted to bytes. Text is compared case-sensitive, use <code>lower(Group)</code> or <code>matches "(?i)..."</code> to ignore case.</p>
+              <br />
+              <p>Fields with several values like Codec, HDR, Audio and Tags are lists, check them with <code>"HEVC" in Codec</code>.</p>
+              <DocsLink href="https://autobrr.com/filters#advanced" />
+            </div>
+          }
+        />
+        {values.preset_id ? (
+          <div className="col-span-12">
+            <SwitchGroup
+              name="preset_managed"
+              label="Managed preset"
+              description={`Imported from preset ${values.preset_id}. Managed presets are synced from ${values.preset_source || "their source"} when it changes, which overwrites the preset fields.`}
+            />
+          </div>
+        ) : null}
+      </CollapsableSection>
+
+      <CollapsableSection
+        defaultOpen={false}
+        title="Filter chaining"
+        subtitle="Choose what happens when this filter rejects a release or its actions fail."
+      >
+        <Select
+          name="on_reject"
+          label="On reject"
+          columns={6}
+          options={FilterChainModeOptions}
+          optionDefaultText="Next filter by priority"
+          tooltip={
+            <div>
+              <p>Filter checked next when this filter does not match the release. Continue with filter skips the filters in between.</p>
+            </div>
+          }
+        />
+        {values.on_reject === "FILTER" ? (
+          <ChainFilterSelect name="on_reject_filter_id" label="Continue with" filterId={values.id} />
+        ) : null}
+        <Select
+          name="on_action_failure"
+          label="On action failure"
+          columns={6}
+          options={FilterChainModeOptions}
+          optionDefaultText="Next filter by priority"
+          tooltip={
+            <div>
+              <p>Filter checked next when all actions of this filter are rejected or fail, eg. try a racing client first and else send to Sonarr. When any action gets the release it does not fall through.</p>
+            </div>
+          }
+        />
+        {values.on_action_failure === "FILTER" ? (
+          <ChainFilterSelect name="on_action_failure_filter_id" label="Continue with" filterId={values.id} />
+        ) : null}
+        <div className="col-span-12">
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            A filter is checked at most once per release. The filter to continue with must be enabled for the indexer of the release, else the next filter by priority is checked.
+          </p>
+        </div>
       </CollapsableSection>
     </div>
   );
 }
 
+interface PreferenceListProps {
+  name: string;
+  label: string;
+  description: string;
+  options: string[];
+}
+
+function PreferenceList({ name, label, description, options }: PreferenceListProps) {
+  return (
+    <div className="col-span-12 sm:col-span-6">
+      <label htmlFor={name} className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">
+        {label}
+      </label>
+      <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+        {description}
+      </p>
+      <Field name={name}>
+        {({ field, form: { setFieldValue } }: FieldProps) => {
+          const formats: string[] = field.value ?? [];
+
+          const move = (from: number, to: number) => {
+            const next = [...formats];
+            next.splice(to, 0, next.splice(from, 1)[0]);
+            setFieldValue(field.name, next);
+          };
+
+          return (
+            <div className="mt-2">
+              <ol className="divide-y divide-gray-200 dark:divide-gray-700">
+                {formats.map((format, idx) => (
+                  <li key={format} className="flex items-center justify-between py-1 text-sm text-gray-900 dark:text-gray-100">
+                    <span>{idx + 1}. {format}</span>
+                    <span className="space-x-2">
+                      <button type="button" disabled={idx === 0} onClick={() => move(idx, idx - 1)} className="text-blue-500 disabled:text-gray-400">Up</button>
+                      <button type="button" disabled={idx === formats.length - 1} onClick={() => move(idx, idx + 1)} className="text-blue-500 disabled:text-gray-400">Down</button>
+                      <button type="button" onClick={() => setFieldValue(field.name, formats.filter((f) => f !== format))} className="text-red-500">Remove</button>
+                    </span>
+                  </li>
+                ))}
+              </ol>
+              <select
+                id={name}
+                className="mt-2 block w-full focus:outline-none focus:ring-1 focus:ring-offset-0 focus:ring-blue-500 dark:focus:ring-blue-500 rounded-md sm:text-sm border-gray-300 dark:border-gray-700 dark:bg-gray-800 dark:text-gray-100"
+                value=""
+                onChange={(e) => e.target.value && setFieldValue(field.name, [...formats, e.target.value])}
+              >
+                <option value="">Add</option>
+                {options.filter((f) => !formats.includes(f)).map((f) => (
+                  <option key={f} value={f}>{f}</option>
+                ))}
+              </select>
+            </div>
+          );
+        }}
+      </Field>
+    </div>
+  );
+}
+
+interface RewriteRulesProps {
+  name: string;
+}
+
+function RewriteRules({ name }: RewriteRulesProps) {
+  const { values } = useFormikContext<Filter>();
+  const rules = values.rewrite_rules ?? [];
+
+  return (
+    <FieldArray name={name}>
+      {({ remove, push }: FieldArrayRenderProps) => (
+        <div className="col-span-12">
+          {rules.map((_rule, idx) => (
+            <div key={idx} className="mb-2 grid grid-cols-12 gap-6">
+              <TextField
+                name={`${name}.${idx}.pattern`}
+                label="Pattern"
+                columns={5}
+                placeholder="eg. \s*\[[^\]]+\]$"
+              />
+              <TextField
+                name={`${name}.${idx}.replacement`}
+                label="Replacement"
+                columns={5}
+                placeholder="eg. $1 or leave empty to remove"
+              />
+              <div className="col-span-2 flex items-end">
+                <button type="button" onClick={() => remove(idx)} className="text-sm text-red-500">Remove</button>
+              </div>
+            </div>
+          ))}
+          <button
+            type="button"
+            onClick={() => push({ pattern: "", replacement: "" })}
+            className="mt-2 text-sm text-blue-500"
+          >
+            Add rule
+          </button>
+        </div>
+      )}
+    </FieldArray>
+  );
+}
+
+interface ChainFilterSelectProps {
+  name: string;
+  label: string;
+  filterId: number;
+}
+
+function ChainFilterSelect({ name, label, filterId }: ChainFilterSelectProps) {
+  const { data: filters } = useQuery({
+    queryKey: filterKeys.lists(),
+    queryFn: APIClient.filters.getAll,
+    refetchOnWindowFocus: false
+  });
+
+  return (
+    <div className="col-span-6">
+      <label htmlFor={name} className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">
+        {label}
+      </label>
+      <Field name={name}>
+        {({ field, form: { setFieldValue } }: FieldProps) => (
+          <select
+            id={name}
+            className="mt-2 block w-full focus:outline-none focus:ring-1 focus:ring-offset-0 focus:ring-blue-500 dark:focus:ring-blue-500 rounded-md sm:text-sm border-gray-300 dark:border-gray-700 dark:bg-gray-800 dark:text-gray-100"
+            value={field.value ?? 0}
+            onChange={(e) => setFieldValue(field.name, parseInt(e.target.value, 10))}
+          >
+            <option value={0}>Select filter</option>
+            {filters?.filter((f) => f.id !== filterId).map((f) => (
+              <option key={f.id} value={f.id}>{f.name}</option>
+            ))}
+          </select>
+        )}
+      </Field>
+    </div>
+  );
+}
+
 interface WarningAlertProps {
   text: string | JSX.Element;
   alert?: string;
@@ -12,6 +12,7 @@ import { useToggle } from "@hooks/hooks";
 import { classNames } from "@utils";
 import { Switch as SwitchBasic } from "@headlessui/react";
 import {
+  ExternalFilterExecModeOptions,
   ExternalFilterTypeNameMap,
   ExternalFilterTypeOptions,
   ExternalFilterWebhookMethodOptions
@@ -274,10 +275,48 @@ const TypeForm = ({ external, idx }: TypeFormProps) => {
           />
         </div>
         <div className="mt-6 grid grid-cols-12 gap-6">
+          <Select
+            name={`external.${idx}.exec_mode`}
+            label="Mode"
+            optionDefaultText="Exit code"
+            options={ExternalFilterExecModeOptions}
+            tooltip={
+              <div>
+                <p>
+                  Exit code: the release matches when the command exits with the expected status.
+                </p>
+                <br />
+                <p>
+                  JSON: the release and filter are written as json to stdin and the command must print
+                  {" "}<code>{"{ \"accept\": true, \"reason\": \"\", \"overrides\": { \"category\": \"tv\" } }"}</code>{" "}
+                  to stdout. Overrides are optional and change the release when accepted.
+                </p>
+              </div>
+            }
+          />
+          {external.exec_mode !== "JSON" ? (
+            <NumberField
+              name={`external.${idx}.exec_expect_status`}
+              label="Expected exit status"
+              placeholder="0"
+            />
+          ) : null}
           <NumberField
-            name={`external.${idx}.exec_expect_status`}
-            label="Expected exit status"
-            placeholder="0"
+            name={`external.${idx}.exec_timeout`}
+            label="Timeout (seconds)"
+            placeholder="60"
+            min={0}
+            max={3600}
+            tooltip={<p>The command is killed and the release rejected when it runs longer. Defaults to 60 seconds.</p>}
+          />
+        </div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextArea
+            name={`external.${idx}.exec_env`}
+            label="Environment variables"
+            columns={6}
+            rows={4}
+            placeholder={"One per line eg. API_KEY=secret\nRELEASE={{ .TorrentName }}"}
           />
         </div>
       </div>
@@ -318,6 +357,51 @@ const TypeForm = ({ external, idx }: TypeFormProps) => {
           label="Expected http status"
           placeholder="200"
         />
+
+        <TextField
+          name={`external.${idx}.webhook_expect_json_path`}
+          label="Expected json path"
+          columns={6}
+          placeholder="eg. result.allowed"
+          tooltip={
+            <div>
+              <p>
+                Dot separated path to a value in the json response, use numbers for array items eg. results.0.status.
+                The release is rejected when the value does not match.
+              </p>
+            </div>
+          }
+        />
+        <TextField
+          name={`external.${idx}.webhook_expect_json_value`}
+          label="Expected json value"
+          columns={6}
+          placeholder="eg. true"
+          tooltip={<p>Leave empty to match any value except null, false, 0 and an empty string.</p>}
+        />
+
+        <TextField
+          name={`external.${idx}.webhook_retry_status`}
+          label="Retry on http status"
+          columns={6}
+          placeholder="eg. 500,502,503,504"
+          tooltip={<p>Comma separated http status codes to retry the request on. Requests that fail to connect are retried as well.</p>}
+        />
+        <NumberField
+          name={`external.${idx}.webhook_retry_attempts`}
+          label="Retry attempts"
+          placeholder="0"
+          min={0}
+          max={10}
+        />
+        <NumberField
+          name={`external.${idx}.webhook_retry_delay_seconds`}
+          label="Retry delay (seconds)"
+          placeholder="1"
+          min={0}
+          max={60}
+          tooltip={<p>Delay before the first retry, doubled for every following retry.</p>}
+        />
       </div>
     );
 
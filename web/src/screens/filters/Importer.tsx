@@ -23,7 +23,7 @@ const ModalUpper = ({ children }: { children: React.ReactNode; }) => (
   <div className="bg-white dark:bg-gray-800 px-4 pt-5 pb-4 sm:py-6 sm:px-4 sm:pb-4">
     <div className="mt-3 text-left sm:mt-0 sm:ml-4 sm:pr-8 max-w-full">
       <Dialog.Title as="h3" className="mb-3 text-lg leading-6 font-medium text-gray-900 dark:text-white break-words">
-        Import filter (in JSON or autodl-irssi format)
+        Import filter (in JSON, preset, TRaSH custom format or autodl-irssi format)
       </Dialog.Title>
       {children}
     </div>
@@ -61,51 +61,94 @@ const ModalLower = ({ isOpen, setIsOpen, onImportClick }: ModalLowerProps) => (
 const ImportJSON = async (inputFilterText: string) => {
   let newFilter = {} as Filter;
   try {
-    const importedData = JSON.parse(inputFilterText);
+    // a tag export holds a list of filters
+    const parsed = JSON.parse(inputFilterText);
+    const importedFilters: FilterExport[] = Array.isArray(parsed) ? parsed : [parsed];
 
     // Fetch existing filters from the API
     const existingFilters = await APIClient.filters.getAll();
 
-    // Create a unique filter title by appending an incremental number if title is taken by another filter
-    let nameCounter = 0;
-    let uniqueFilterName = importedData.name;
-    while (existingFilters.some((filter) => filter.name === uniqueFilterName)) {
-      nameCounter++;
-      uniqueFilterName = `${importedData.name}-${nameCounter}`;
+    for (const importedData of importedFilters) {
+      // Create a unique filter title by appending an incremental number if title is taken by another filter
+      let nameCounter = 0;
+      let uniqueFilterName = importedData.name;
+      while (existingFilters.some((filter) => filter.name === uniqueFilterName)) {
+        nameCounter++;
+        uniqueFilterName = `${importedData.name}-${nameCounter}`;
+      }
+
+      // Create a new filter using the API
+      newFilter = {
+        resolutions: [],
+        sources: [],
+        codecs: [],
+        containers: [],
+        ...importedData.data,
+        name: uniqueFilterName
+      } as Filter;
+
+      const created = await APIClient.filters.create(newFilter);
+      existingFilters.push(created);
+
+      toast.custom((t) =>
+        <Toast
+          type="success"
+          body={`Filter '${uniqueFilterName}' imported successfully!`}
+          t={t}
+        />
+      );
     }
+  } catch (e) {
+    console.error("Failure while importing JSON filter: ", e);
+    console.error("  --> Filter: ", newFilter);
 
-    // Create a new filter using the API
-    newFilter = {
-      resolutions: [],
-      sources: [],
-      codecs: [],
-      containers: [],
-      ...importedData.data,
-      name: uniqueFilterName
-    } as Filter;
+    toast.custom((t) =>
+      <Toast
+        type="error"
+        body="Failed to import JSON data. Information logged to console."
+        t={t}
+      />
+    );
+  }
+}
 
-    await APIClient.filters.create(newFilter);
+// Presets and TRaSH-Guides custom formats are imported by the backend, which updates the filter
+// of a preset that was imported before
+const isPreset = (inputText: string) => {
+  try {
+    const data = JSON.parse(inputText);
+    return data.preset_id !== undefined || data.specifications !== undefined;
+  } catch (e) {
+    return false;
+  }
+};
+
+const ImportPreset = async (inputText: string) => {
+  try {
+    const result = await APIClient.filters.importPreset({ preset: inputText });
 
     toast.custom((t) =>
       <Toast
-        type="success"
-        body={`Filter '${uniqueFilterName}' imported successfully!`}
+        type={result.warnings.length ? "warning" : "success"}
+        body={[
+          `Filter '${result.name}' ${result.created ? "created" : "updated"} from preset ${result.preset_id}.`,
+          ...result.warnings
+        ].join(" ")}
         t={t}
       />
     );
   } catch (e) {
-    console.error("Failure while importing JSON filter: ", e);
-    console.error("  --> Filter: ", newFilter);
+    console.error("Failure while importing preset: ", e);
 
     toast.custom((t) =>
       <Toast
         type="error"
-        body="Failed to import JSON data. Information logged to console."
+        body="Failed to import preset. Information logged to console."
         t={t}
       />
     );
   }
-}
+};
 
 const ImportAutodlIrssi = async (inputText: string) => {
   const parser = new AutodlIrssiConfigParser();
@@ -176,7 +219,8 @@ export const Importer = ({
   const queryClient = useQueryClient();
 
   const isJSON = (inputText: string) => (
-    inputText.indexOf("{") <= 3 && inputText.lastIndexOf("}") >= (inputText.length - 3 - 1)
+    (inputText.indexOf("{") <= 3 && inputText.lastIndexOf("}") >= (inputText.length - 3 - 1)) ||
+    (inputText.indexOf("[") <= 3 && inputText.lastIndexOf("]") >= (inputText.length - 3 - 1))
   );
 
   const showAutodlImportWarnings = (inputText: string) => {
@@ -198,7 +242,10 @@ export const Importer = ({
     try {
       const inputText = inputFilterText.trim();
 
-      if (isJSON(inputText)) {
+      if (isPreset(inputText)) {
+        console.log("Importing filter preset");
+        await ImportPreset(inputText);
+      } else if (isJSON(inputText)) {
         console.log("Parsing import filter as JSON");
         await ImportJSON(inputText);
       } else {
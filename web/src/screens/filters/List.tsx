@@ -629,6 +629,14 @@ function FilterListItem({ filter, values, idx }: FilterListItemProps) {
           >
             {filter.name}
           </Link>
+          {filter.filter_tags?.map((tag) => (
+            <span
+              key={tag}
+              className="ml-2 inline-flex items-center rounded-md px-1.5 py-0.5 text-xs font-medium bg-gray-100 dark:bg-gray-700 text-gray-700 dark:text-gray-300"
+            >
+              {tag}
+            </span>
+          ))}
         </span>
         <div className="flex items-center">
           <span className="mr-2 break-words whitespace-nowrap text-xs font-medium text-gray-600 dark:text-gray-400">
@@ -5,6 +5,8 @@ export const FILTER_FIELDS: Record<string, string> = {
   "priority": "number",
   "log_score": "number",
   "max_downloads": "number",
+  "max_downloads_interval": "number",
+  "max_downloads_per_indexer": "boolean",
   "use_regex": "boolean",
   "scene": "boolean",
   "smart_episode": "boolean",
@@ -20,6 +22,11 @@ export const FILTER_FIELDS: Record<string, string> = {
   "shows": "string",
   "seasons": "string",
   "episodes": "string",
+  "absolute_episodes": "string",
+  "anime_batch": "string",
+  "anime_sub_type": "string",
+  "anime_group_tiers": "string",
+  "anime_max_group_tier": "number",
   "years": "string",
   "artists": "string",
   "albums": "string",
@@ -29,6 +36,8 @@ export const FILTER_FIELDS: Record<string, string> = {
   "match_uploaders": "string",
   "except_uploaders": "string",
   "tags": "string",
+  "flags": "string",
+  "except_flags": "string",
   "except_tags": "string",
   "match_sites": "string",
   "except_sites": "string",
@@ -41,6 +50,11 @@ export const FILTER_FIELDS: Record<string, string> = {
   "containers": "[]string",
   "match_hdr": "[]string",
   "except_hdr": "[]string",
+  "hdr_preference": "[]string",
+  "language_preference": "[]string",
+  "subtitle_preference": "[]string",
+  "except_language_only": "[]string",
+  "reject_dv_without_fallback": "boolean",
   "match_other": "[]string",
   "except_other": "[]string",
   "match_release_types": "[]string",
@@ -48,7 +62,10 @@ export const FILTER_FIELDS: Record<string, string> = {
   "except_tags_any": "boolean",
   "formats": "[]string",
   "quality": "[]string",
-  "media": "[]string"
+  "media": "[]string",
+  "bit_depths": "[]string",
+  "sample_rates": "[]string",
+  "audio_channels": "[]string"
 } as const;
 
 export const IRC_FIELDS: Record<string, string> = {
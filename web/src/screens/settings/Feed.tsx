@@ -9,6 +9,8 @@ import { Menu, Switch, Transition } from "@headlessui/react";
 import { toast } from "react-hot-toast";
 import {
   ArrowsRightLeftIcon,
+  ArrowUturnLeftIcon,
+  DocumentDuplicateIcon,
   DocumentTextIcon,
   EllipsisHorizontalIcon,
   PencilSquareIcon,
@@ -105,7 +107,7 @@ function FeedSettings() {
           <div className="ml-4 mt-4">
             <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Feeds</h3>
             <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
-              Manage RSS, Newznab, and Torznab feeds.
+              Manage RSS, JSON, push, Newznab, and Torznab feeds.
             </p>
           </div>
         </div>
@@ -201,9 +203,30 @@ function ListItem({ feed }: ListItemProps) {
           </Switch>
         </div>
         <div className="col-span-8 sm:col-span-5 pl-12 py-3 flex flex-col text-sm font-medium text-gray-900 dark:text-white">
-          <span>{feed.name}</span>
+          <span>
+            {feed.name}
+            {feed.health && feed.health.status !== "OK" && (
+              <span
+                title={feed.health.last_error}
+                className={classNames(
+                  feed.health.status === "FAILING" ? "bg-red-100 text-red-800 dark:bg-red-800 dark:text-red-100" : "bg-yellow-100 text-yellow-800 dark:bg-yellow-800 dark:text-yellow-100",
+                  "ml-2 inline-flex items-center px-2 py-0.5 rounded text-xs font-medium"
+                )}
+              >
+                {feed.health.status === "FAILING" ? "Failing" : "Degraded"}
+              </span>
+            )}
+          </span>
           <span className="text-gray-900 dark:text-gray-500 text-xs">
             {feed.indexer}
+            {feed.runs?.last_run && (
+              <span
+                title={`HTTP ${feed.runs.last_run.http_status || "-"} in ${feed.runs.last_run.duration_ms} ms, ${feed.runs.matched} matched in ${feed.runs.runs} runs since start`}
+                className="ml-2"
+              >
+                {feed.runs.last_run.items} items, {feed.runs.last_run.new_items} new, {feed.runs.last_run.matched} matched
+              </span>
+            )}
           </span>
         </div>
         <div className="hidden md:flex col-span-1 py-3 items-center">
@@ -267,6 +290,56 @@ const FeedItemDropdown = ({
     }
   });
 
+  const backfillMutation = useMutation({
+    mutationFn: (id: number) => APIClient.feeds.backfill(id),
+    onSuccess: () => {
+      toast.custom((t) => <Toast type="success" body={`Feed ${feed?.name} backfill started`} t={t} />);
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body={`Feed ${feed?.name} backfill could not be started`} t={t} />);
+    }
+  });
+
+  const addFeedMutation = useMutation({
+    mutationFn: async (feed: Feed) => {
+      const created = await APIClient.feeds.create({
+        name: `${feed.name} (copy)`,
+        type: feed.type,
+        enabled: false,
+        url: feed.url,
+        interval: feed.interval,
+        timeout: feed.timeout,
+        api_key: feed.api_key,
+        indexer_id: feed.indexer_id,
+        priority: feed.priority,
+        settings: feed.settings
+      });
+
+      // the create request leaves the max age at its default
+      return APIClient.feeds.update({ ...feed, id: created.id, name: created.name, enabled: false });
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: feedKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`Feed added to ${feed?.indexer}, edit it to set its url and priority`} t={t} />);
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body={`Feed could not be added to ${feed?.indexer}`} t={t} />);
+    }
+  });
+
+  const refreshCapsMutation = useMutation({
+    mutationFn: (id: number) => APIClient.feeds.refreshCaps(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: feedKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`Feed ${feed?.name} caps refreshed`} t={t} />);
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body={`Feed ${feed?.name} caps could not be refreshed`} t={t} />);
+    }
+  });
+
   return (
     <Menu as="div">
       <DeleteModal
@@ -351,6 +424,26 @@ const FeedItemDropdown = ({
                 </button>
               )}
             </Menu.Item>
+            <Menu.Item>
+              {({ active }) => (
+                <button
+                  className={classNames(
+                    active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                    "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                  )}
+                  onClick={() => addFeedMutation.mutate(feed)}
+                >
+                  <DocumentDuplicateIcon
+                    className={classNames(
+                      active ? "text-white" : "text-blue-500",
+                      "w-5 h-5 mr-2"
+                    )}
+                    aria-hidden="true"
+                  />
+                  Add feed to indexer
+                </button>
+              )}
+            </Menu.Item>
           </div>
           <div>
             <Menu.Item>
@@ -373,6 +466,52 @@ const FeedItemDropdown = ({
                 </ExternalLink>
               )}
             </Menu.Item>
+            {feed.type !== "RSS" && (
+              <Menu.Item>
+                {({ active }) => (
+                  <button
+                    className={classNames(
+                      active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                      "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                    )}
+                    onClick={() => backfillMutation.mutate(feed.id)}
+                    title="Process older items of the feed"
+                  >
+                    <ArrowUturnLeftIcon
+                      className={classNames(
+                        active ? "text-white" : "text-blue-500",
+                        "w-5 h-5 mr-2"
+                      )}
+                      aria-hidden="true"
+                    />
+                    Backfill
+                  </button>
+                )}
+              </Menu.Item>
+            )}
+            {feed.type === "TORZNAB" && (
+              <Menu.Item>
+                {({ active }) => (
+                  <button
+                    className={classNames(
+                      active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                      "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                    )}
+                    onClick={() => refreshCapsMutation.mutate(feed.id)}
+                    title="Fetch the categories of the indexer now"
+                  >
+                    <ArrowPathIcon
+                      className={classNames(
+                        active ? "text-white" : "text-blue-500",
+                        "w-5 h-5 mr-2"
+                      )}
+                      aria-hidden="true"
+                    />
+                    Refresh caps
+                  </button>
+                )}
+              </Menu.Item>
+            )}
             <Menu.Item>
               {({ active }) => (
                 <button
@@ -102,11 +102,25 @@ const ImplementationBadgeRSS = () => (
   </span>
 );
 
+const ImplementationBadgeJSON = () => (
+  <span className="inline-flex items-center px-2.5 py-0.5 rounded-md text-sm font-medium bg-teal-200 dark:bg-teal-400 text-teal-800 dark:text-teal-800">
+    JSON
+  </span>
+);
+
+const ImplementationBadgePush = () => (
+  <span className="inline-flex items-center px-2.5 py-0.5 rounded-md text-sm font-medium bg-indigo-200 dark:bg-indigo-400 text-indigo-800 dark:text-indigo-800">
+    Push
+  </span>
+);
+
 export const ImplementationBadges: componentMapType = {
   irc: <ImplementationBadgeIRC />,
   torznab: <ImplementationBadgeTorznab />,
   newznab: <ImplementationBadgeNewznab />,
-  rss: <ImplementationBadgeRSS />
+  rss: <ImplementationBadgeRSS />,
+  json: <ImplementationBadgeJSON />,
+  push: <ImplementationBadgePush />
 };
 
 interface ListItemProps {
@@ -189,6 +203,17 @@ function IndexerSettings() {
 
   const sortedIndexers = useSort(data || []);
 
+  const queryClient = useQueryClient();
+
+  const reloadMutation = useMutation({
+    mutationFn: APIClient.indexers.reloadDefinitions,
+    onSuccess: (res) => {
+      queryClient.invalidateQueries({ queryKey: indexerKeys.lists() });
+
+      toast.custom((t) => <Toast type="success" body={`Reloaded ${res.reloaded?.length ?? 0} custom definitions`} t={t} />);
+    }
+  });
+
   if (error) {
     return (<p>An error has occurred</p>);
   }
@@ -209,6 +234,14 @@ function IndexerSettings() {
             </p>
           </div>
           <div className="ml-4 mt-4 flex-shrink-0">
+            <button
+              type="button"
+              onClick={() => reloadMutation.mutate()}
+              title="Reload custom definitions without reconnecting irc networks"
+              className="relative inline-flex items-center mr-2 px-4 py-2 border border-gray-300 dark:border-gray-700 shadow-sm text-sm font-medium rounded-md text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+            >
+              Reload definitions
+            </button>
             <button
               type="button"
               onClick={toggleAddIndexer}
@@ -3,7 +3,7 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-import { Fragment, useRef, useState, useMemo, useEffect, MouseEvent } from "react";
+import { Fragment, useRef, useState, useMemo, useEffect, MouseEvent, FormEvent } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { LockClosedIcon, LockOpenIcon } from "@heroicons/react/24/solid";
 import { Menu, Switch, Transition } from "@headlessui/react";
@@ -284,11 +284,16 @@ const ListItem = ({ network, expanded }: ListItemProps) => {
         <div className="col-span-8 xs:col-span-3 md:col-span-3 items-center pl-8 font-medium text-gray-900 dark:text-white cursor-pointer">
           <div className="flex">
             <span className="relative inline-flex items-center ml-1">
-              {network.enabled ? (
+              {network.enabled && network.scheduled_offline ? (
+                <span
+                  className="mr-3 flex h-3 w-3 rounded-full opacity-75 bg-blue-300"
+                  title={`Offline by schedule: ${network.offline_schedule}`}
+                />
+              ) : network.enabled ? (
                 network.healthy ? (
                   <span
                     className="mr-3 flex h-3 w-3 relative"
-                    title={`Connected since: ${simplifyDate(network.connected_since)}`}
+                    title={`Connected since: ${simplifyDate(network.connected_since)}, lag: ${network.lag_ms}ms`}
                   >
                     <span className="animate-ping inline-flex h-full w-full rounded-full bg-green-400 opacity-75" />
                     <span className="inline-flex absolute rounded-full h-3 w-3 bg-green-500" />
@@ -369,6 +374,7 @@ const ListItem = ({ network, expanded }: ListItemProps) => {
                 <p>No channels!</p>
               </div>
             )}
+            <ConsoleItem network={network} />
           </div>
         </div>
       )}
@@ -421,7 +427,7 @@ const ChannelItem = ({ network, channel }: ChannelItemProps) => {
           </span>
         </div>
         <div className="col-span-3 flex items-center md:px-6">
-          <span title={simplifyDate(channel.last_announce)}>
+          <span title={`${simplifyDate(channel.last_announce)}, ${channel.announces_last_hour} announces in the last hour`}>
             {IsEmptyDate(channel.last_announce)}
           </span>
         </div>
@@ -438,6 +444,80 @@ const ChannelItem = ({ network, channel }: ChannelItemProps) => {
   );
 };
 
+interface ConsoleItemProps {
+  network: IrcNetwork;
+}
+
+// console stream of raw commands sent to the network and the replies to them
+const consoleChannel = "*console";
+
+const ConsoleItem = ({ network }: ConsoleItemProps) => {
+  const [viewConsole, toggleView] = useToggle(false);
+  const [command, setCommand] = useState("");
+
+  const sendMutation = useMutation({
+    mutationFn: (cmd: string) => APIClient.irc.sendRawCmd({ network_id: network.id, command: cmd }),
+    onSuccess: () => setCommand(""),
+    onError: (error: Error) => {
+      toast.custom((t) => <Toast type="error" body={`${network.name}: ${error.message}`} t={t} />);
+    }
+  });
+
+  const onSubmit = (e: FormEvent) => {
+    e.preventDefault();
+
+    if (command.trim() === "") {
+      return;
+    }
+
+    sendMutation.mutate(command);
+  };
+
+  return (
+    <div
+      className={classNames(
+        "mt-2 text-gray-500 dark:text-gray-400",
+        viewConsole ? "bg-gray-200 dark:bg-gray-800 rounded-md" : ""
+      )}
+    >
+      <div
+        className="grid grid-cols-12 gap-4 items-center py-4 hover:bg-gray-300 dark:hover:bg-gray-800 hover:cursor-pointer rounded-md"
+        onClick={toggleView}
+      >
+        <div className="col-span-11 flex items-center md:px-6">
+          Console
+        </div>
+        <div className="col-span-1 flex items-center justify-end">
+          <button className="hover:text-gray-500 px-2 mx-2 py-1 dark:bg-gray-800 rounded dark:border-gray-900">
+            {viewConsole ? "Hide" : "View"}
+          </button>
+        </div>
+      </div>
+      {viewConsole && (
+        <>
+          <Events network={network} channel={consoleChannel}/>
+          <form className="flex p-2" onSubmit={onSubmit}>
+            <input
+              type="text"
+              value={command}
+              onChange={(e) => setCommand(e.target.value)}
+              placeholder="PRIVMSG NickServ :HELP"
+              className="flex-1 font-mono focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 border-gray-300 dark:border-gray-700 block w-full shadow-sm sm:text-sm rounded-md dark:bg-gray-900 dark:text-gray-100"
+            />
+            <button
+              type="submit"
+              disabled={sendMutation.isLoading}
+              className="ml-2 px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700"
+            >
+              Send
+            </button>
+          </form>
+        </>
+      )}
+    </div>
+  );
+};
+
 interface ListItemDropdownProps {
   network: IrcNetwork;
   toggleUpdate: () => void;
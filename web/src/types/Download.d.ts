@@ -10,12 +10,14 @@ type DownloadClientType =
   "RTORRENT" |
   "TRANSMISSION" |
   "PORLA" |
+  "ARIA2" |
   "RADARR" |
   "SONARR" |
   "LIDARR" |
   "WHISPARR" |
   "READARR" |
-  "SABNZBD";
+  "SABNZBD" |
+  "NZBGET";
 
 // export enum DownloadClientTypeEnum {
 //     QBITTORRENT = "QBITTORRENT",
@@ -6,6 +6,7 @@
 interface Feed {
   id: number;
   indexer: string;
+  indexer_id: number;
   name: string;
   type: FeedType;
   enabled: boolean;
@@ -18,19 +19,178 @@ interface Feed {
   last_run: string;
   last_run_data: string;
   next_run: string;
+  health?: FeedHealth;
+  caps?: FeedCaps;
+  runs?: FeedRunStats;
+  // feeds of an indexer share their cache, the higher priority refreshes first
+  priority: number;
   settings: FeedSettings;
   created_at: Date;
   updated_at: Date;
 }
 
+// torznab capabilities of the feed, refreshed daily
+interface FeedCaps {
+  categories: FeedCategory[];
+  updated_at: string;
+}
+
+interface FeedCategory {
+  id: number;
+  name: string;
+  subcategories?: FeedCategory[];
+}
+
+interface FeedCategoryMapping {
+  id: number;
+  category: string;
+}
+
+interface FeedCacheItem {
+  feed_id: string;
+  key: string;
+  value: string;
+  ttl: string;
+  created: string;
+}
+
+interface FeedCache {
+  feed_id: number;
+  count: number;
+  oldest: string;
+  newest: string;
+  items: FeedCacheItem[];
+}
+
+interface FeedPreview {
+  items: FeedPreviewItem[];
+}
+
+interface FeedPreviewItem {
+  key: string;
+  title: string;
+  published: string;
+  release: Release | null;
+  warnings: string[];
+}
+
+// diagnostics of a refresh, matched is counted once the filters are done
+interface FeedRun {
+  feed_id: number;
+  started_at: string;
+  duration_ms: number;
+  items: number;
+  new_items: number;
+  matched: number;
+  http_status: number;
+  error?: string;
+}
+
+// last run and totals since start
+interface FeedRunStats {
+  last_run?: FeedRun;
+  runs: number;
+  errors: number;
+  new_items: number;
+  matched: number;
+}
+
+type FeedHealthStatus = "OK" | "DEGRADED" | "FAILING";
+
+interface FeedHealth {
+  status: FeedHealthStatus;
+  errors: number;
+  last_error?: string;
+  last_error_at: string;
+  failing_since: string;
+  backoff_until: string;
+}
+
 interface FeedSettings {
   download_type: FeedDownloadType;
   // download_type: string;
+  field_mapping?: FeedFieldMapping;
+  json?: FeedJSONSettings;
+  cron?: string;
+  not_between?: string;
+  catch_up?: boolean;
+  title_include?: string;
+  title_exclude?: string;
+  backfill?: FeedBackfillSettings;
+  failing_alert_after?: number;
+  proxy?: string;
+  // one "Name: value" per line
+  headers?: string;
+  login?: FeedLoginSettings;
+  category_mapping?: FeedCategoryMapping[];
+  push?: FeedPushSettings;
+}
+
+type FeedPushProtocol = "SSE" | "WEBSOCKET";
+
+// streamed feed, its messages are mapped with the json settings
+interface FeedPushSettings {
+  protocol: FeedPushProtocol;
+  event?: string;
+  subscribe?: string;
+}
+
+interface FeedLoginSettings {
+  url: string;
+  method?: "GET" | "POST";
+  body?: string;
+  content_type?: string;
+  api_key_path?: string;
+}
+
+interface FeedBackfillSettings {
+  on_first_run: boolean;
+  count?: number;
+  max_age?: number;
+  delay?: number;
+}
+
+// element paths like title, enclosure@length or torznab:attr[name=size]@value
+interface FeedFieldMapping {
+  title?: string;
+  download_url?: string;
+  magnet_uri?: string;
+  size?: string;
+  category?: string;
+  info_hash?: string;
+  uploader?: string;
+  freeleech?: string;
+}
+
+// JSONPaths like $.data[*] for the items, and paths relative to the item like $.name for the fields
+interface FeedJSONSettings {
+  items_path: string;
+  fields: FeedJSONFieldMapping;
+  headers?: Record<string, string>;
+  page_param?: string;
+  page_start?: number;
+  max_pages?: number;
+  page_size_param?: string;
+  page_size?: number;
+}
+
+interface FeedJSONFieldMapping {
+  guid?: string;
+  title: string;
+  download_url?: string;
+  magnet_uri?: string;
+  info_url?: string;
+  size?: string;
+  category?: string;
+  info_hash?: string;
+  uploader?: string;
+  freeleech?: string;
+  published?: string;
 }
 
 type FeedDownloadType = "MAGNET" | "TORRENT";
 
-type FeedType = "TORZNAB" | "NEWZNAB" | "RSS";
+type FeedType = "TORZNAB" | "NEWZNAB" | "RSS" | "JSON" | "PUSH";
 
 interface FeedCreate {
   name: string;
@@ -41,5 +201,6 @@ interface FeedCreate {
   timeout: number;
   api_key?: string;
   indexer_id: number;
+  priority?: number;
   settings: FeedSettings;
 }
@@ -11,10 +11,18 @@ interface Filter {
   updated_at: Date;
   min_size: string;
   max_size: string;
+  verify_torrent?: boolean;
+  min_file_count?: number;
+  max_file_count?: number;
+  match_file_extensions?: string;
+  except_file_extensions?: string;
   delay: number;
+  delay_jitter?: number;
   priority: number;
   max_downloads: number;
   max_downloads_unit: string;
+  max_downloads_interval?: number;
+  max_downloads_per_indexer?: boolean;
   match_releases: string;
   except_releases: string;
   use_regex: boolean;
@@ -26,21 +34,56 @@ interface Filter {
   match_description: string;
   except_description: string;
   use_regex_description: boolean;
+  use_regex_shows: boolean;
+  use_regex_release_groups: boolean;
+  use_regex_tags: boolean;
+  use_regex_uploaders: boolean;
+  expression: string;
+  schedule: string;
+  preset_id?: string;
+  preset_source?: string;
+  preset_managed?: boolean;
+  preset_hash?: string;
+  filter_tags?: string[];
+  flags?: string;
+  except_flags?: string;
+  group_id?: number;
   scene: boolean;
   origins: string[];
   except_origins: string[];
   freeleech: boolean;
   freeleech_percent: string;
+  freeleech_tokens: boolean;
+  min_seeders?: number;
+  min_leechers?: number;
+  on_reject?: FilterChainMode;
+  on_reject_filter_id?: number;
+  on_action_failure?: FilterChainMode;
+  on_action_failure_filter_id?: number;
   shows: string;
   seasons: string;
   episodes: string;
+  absolute_episodes?: string;
+  air_dates?: string;
+  max_air_date_age?: number;
+  anime_batch?: FilterAnimeBatch;
+  anime_sub_type?: AnimeSubType;
+  anime_group_tiers?: string;
+  anime_max_group_tier?: number;
   smart_episode: boolean;
+  prefer_season_packs: boolean;
+  season_pack_hold: number;
+  proper_upgrades: boolean;
+  proper_upgrades_replace: boolean;
+  arr_upgrade_only?: boolean;
   resolutions: string[];
   codecs: string[];
   sources: string[];
   containers: string[];
   match_hdr: string[];
   except_hdr: string[];
+  hdr_preference?: string[];
+  reject_dv_without_fallback?: boolean;
   match_other: string[];
   except_other: string[];
   years: string;
@@ -51,6 +94,9 @@ interface Filter {
   formats: string[];
   quality: string[];
   media: string[];
+  bit_depths?: string[];
+  sample_rates?: string[];
+  audio_channels?: string[];
   perfect_flac: boolean;
   cue: boolean;
   log: boolean;
@@ -61,6 +107,9 @@ interface Filter {
   except_uploaders: string;
   match_language: string[];
   except_language: string[];
+  language_preference?: string[];
+  subtitle_preference?: string[];
+  except_language_only?: string[];
   tags: string;
   except_tags: string;
   tags_any: string;
@@ -71,6 +120,13 @@ interface Filter {
   actions: Action[];
   indexers: Indexer[];
   external: ExternalFilter[];
+  list_sources?: FilterListSource[];
+  rewrite_rules?: FilterRewriteRule[];
+}
+
+interface FilterRewriteRule {
+  pattern: string;
+  replacement: string;
 }
 
 interface Action {
@@ -102,6 +158,8 @@ interface Action {
   webhook_method: string;
   webhook_data: string,
   webhook_headers: string[];
+  priority?: number;
+  dupe_key?: string;
   external_download_client_id?: number;
   client_id?: number;
   filter_id?: number;
@@ -113,8 +171,30 @@ type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | DownloadClientT
 
 type ExternalType = "EXEC" |  "WEBHOOK";
 
+type ExternalExecMode = "EXIT_CODE" | "JSON";
+
+type FilterChainMode = "NEXT" | "STOP" | "FILTER";
+
+type FilterAnimeBatch = "BATCH" | "SINGLE";
+
+type AnimeSubType = "FANSUB" | "OFFICIAL";
+
 type WebhookMethod = "GET" | "POST" | "PUT" | "PATCH" | "DELETE";
 
+type FilterListFormat = "TEXT" | "JSON";
+
+interface FilterListSource {
+  id: number;
+  filter_id: number;
+  field: string;
+  url: string;
+  format: FilterListFormat;
+  json_key?: string;
+  refresh_interval: number;
+  last_refreshed_at?: string;
+  last_error?: string;
+}
+
 interface ExternalFilter {
   id: number;
   index: number;
@@ -124,11 +204,60 @@ interface ExternalFilter {
   exec_cmd?: string;
   exec_args?: string;
   exec_expect_status?: number;
+  exec_mode?: ExternalExecMode;
+  exec_timeout?: number;
+  exec_env?: string;
   webhook_host?: string,
   webhook_type?: string;
   webhook_method?: WebhookMethod;
   webhook_data?: string,
   webhook_headers?: string;
   webhook_expect_status?: number;
+  webhook_retry_status?: string;
+  webhook_retry_attempts?: number;
+  webhook_retry_delay_seconds?: number;
+  webhook_expect_json_path?: string;
+  webhook_expect_json_value?: string;
   filter_id?: number;
 }
+
+interface FilterFlag {
+  name: string;
+  until?: string;
+}
+
+interface FilterBulkResult {
+  tag: string;
+  filters: number[];
+}
+
+interface FilterExport {
+  name: string;
+  version: string;
+  data: Partial<Filter>;
+}
+
+interface FilterPresetImportRequest {
+  preset?: string;
+  source?: string;
+  managed?: boolean;
+  app?: "radarr" | "sonarr";
+}
+
+interface FilterPresetImportResult {
+  filter_id: number;
+  name: string;
+  preset_id: string;
+  created: boolean;
+  changed: boolean;
+  warnings: string[];
+}
+
+interface FilterDryRunResult {
+  filter_id: number;
+  name: string;
+  priority: number;
+  match: boolean;
+  rejections: string[];
+  notes: string[];
+}
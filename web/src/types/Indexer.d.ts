@@ -31,6 +31,8 @@ interface IndexerDefinition {
   torznab: IndexerTorznab;
   newznab?: IndexerTorznab;
   rss: IndexerFeed;
+  json?: IndexerFeed;
+  push?: IndexerFeed;
   parse: IndexerParse;
 }
 
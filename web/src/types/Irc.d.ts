@@ -16,6 +16,28 @@ interface IrcNetwork {
   invite_command: string;
   use_bouncer: boolean;
   bouncer_addr: string;
+  tls_cert?: string;
+  tls_key?: string;
+  tls_key_set?: boolean;
+  proxy?: string;
+  reconnect_delay?: number;
+  reconnect_max_delay?: number;
+  reconnect_jitter?: number;
+  reconnect_alert_attempts?: number;
+  alt_servers?: string;
+  send_burst?: number;
+  send_delay?: number;
+  tls_min_version?: string;
+  tls_ca?: string;
+  tls_skip_verify?: boolean;
+  share_connection?: boolean;
+  offline_schedule?: string;
+  ident?: string;
+  realname?: string;
+  perform_commands?: string;
+  split_quarantine?: number;
+  split_action?: string;
+  encoding?: string;
   channels: IrcChannel[];
   connected: boolean;
   connected_since: string;
@@ -33,6 +55,7 @@ interface IrcNetworkCreate {
   invite_command: string;
   use_bouncer?: boolean;
   bouncer_addr?: string;
+  tls_skip_verify?: boolean;
   channels: IrcChannel[];
   connected: boolean;
 }
@@ -44,11 +67,14 @@ interface IrcChannel {
   password: string;
   detached: boolean;
   monitoring: boolean;
+  announcers?: string;
 }
 
 interface IrcChannelWithHealth extends IrcChannel {
   monitoring_since: string;
   last_announce: string;
+  announces_last_hour: number;
+  announces_total: number;
 }
 
 interface IrcNetworkWithHealth {
@@ -64,14 +90,38 @@ interface IrcNetworkWithHealth {
   invite_command: string;
   use_bouncer: boolean;
   bouncer_addr: string;
+  tls_cert?: string;
+  tls_key_set?: boolean;
+  proxy?: string;
+  reconnect_delay?: number;
+  reconnect_max_delay?: number;
+  reconnect_jitter?: number;
+  reconnect_alert_attempts?: number;
+  alt_servers?: string;
+  send_burst?: number;
+  send_delay?: number;
+  tls_min_version?: string;
+  tls_ca?: string;
+  tls_skip_verify?: boolean;
+  share_connection?: boolean;
+  offline_schedule?: string;
+  ident?: string;
+  realname?: string;
+  perform_commands?: string;
+  split_quarantine?: number;
+  split_action?: string;
+  encoding?: string;
   channels: IrcChannelWithHealth[];
   connected: boolean;
   connected_since: string;
   connection_errors: string[];
   healthy: boolean;
+  lag_ms: number;
+  scheduled_offline: boolean;
+  quarantined_announces: number;
 }
 
-type IrcAuthMechanism = "NONE" | "SASL_PLAIN" | "NICKSERV";
+type IrcAuthMechanism = "NONE" | "SASL_PLAIN" | "SASL_EXTERNAL" | "NICKSERV";
 
 interface IrcAuth {
   mechanism: IrcAuthMechanism; // optional
@@ -86,3 +136,95 @@ interface SendIrcCmdRequest {
   nick: string;
   msg: string;
 }
+
+interface IrcLogLine {
+  id: number;
+  network_id: number;
+  channel: string;
+  nick: string;
+  msg: string;
+  time: string;
+}
+
+interface IrcLogQueryParams {
+  channel?: string;
+  nick?: string;
+  q?: string;
+  before?: string;
+  after?: string;
+  limit?: number;
+  offset?: number;
+}
+
+interface IrcLogResponse {
+  data: IrcLogLine[];
+  count: number;
+}
+
+interface IrcReplayRequest {
+  network_id: number;
+  channel: string;
+  lines: number;
+  dry_run: boolean;
+}
+
+interface IrcReplayAnnounce {
+  time: string;
+  lines: string[];
+  release: Release;
+  results?: FilterDryRunResult[];
+  processed: boolean;
+}
+
+interface IrcReplayResult {
+  channel: string;
+  indexer: string;
+  dry_run: boolean;
+  lines: number;
+  announces: IrcReplayAnnounce[];
+}
+
+interface IrcInjectRequest {
+  network_id: number;
+  channel: string;
+  nick?: string;
+  lines: string[];
+  dry_run: boolean;
+}
+
+interface IrcInjectResult {
+  channel: string;
+  indexer: string;
+  nick: string;
+  dry_run: boolean;
+  queued: number;
+  announces: IrcReplayAnnounce[];
+}
+
+type IrcTriggerAction = "NOTIFY" | "SET_FLAG" | "CLEAR_FLAG";
+
+interface IrcTrigger {
+  id: number;
+  network_id: number;
+  name: string;
+  enabled: boolean;
+  channel: string;
+  pattern: string;
+  action: IrcTriggerAction;
+  flag: string;
+  flag_duration: number;
+  created_at?: string;
+  updated_at?: string;
+}
+
+interface IrcQuarantinedAnnounce {
+  channel: string;
+  source: string;
+  msg: string;
+  time: string;
+}
+
+interface SendIrcRawCmdRequest {
+  network_id: number;
+  command: string;
+}
@@ -3,13 +3,21 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY";
+type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "GOTIFY" | "WEBHOOK";
 type NotificationEvent =
   "PUSH_APPROVED"
   | "PUSH_REJECTED"
   | "PUSH_ERROR"
+  | "IRC_CONNECTED"
   | "IRC_DISCONNECTED"
   | "IRC_RECONNECTED"
+  | "IRC_AUTH_FAILED"
+  | "IRC_KICKED"
+  | "IRC_BANNED"
+  | "IRC_TRIGGER"
+  | "FEED_FAILING"
+  | "FEED_RECOVERED"
+  | "FEED_AUTH_FAILED"
   | "APP_UPDATE_AVAILABLE";
 
 interface ServiceNotification {